@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/assistant"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/nerzhul/nrz-ai/pkg/vad"
+	"github.com/spf13/cobra"
+)
+
+// benchStage collects the latencies of one pipeline stage
+type benchStage struct {
+	name      string
+	latencies []time.Duration
+}
+
+// add records one run of the stage
+func (s *benchStage) add(d time.Duration) {
+	s.latencies = append(s.latencies, d)
+}
+
+// total returns the summed latency of all runs
+func (s *benchStage) total() time.Duration {
+	var total time.Duration
+	for _, d := range s.latencies {
+		total += d
+	}
+	return total
+}
+
+// percentile returns the p-th percentile (0-100) latency
+func (s *benchStage) percentile(p int) time.Duration {
+	if len(s.latencies) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), s.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[(len(sorted)-1)*p/100]
+}
+
+func createBenchCmd(cfg *config.Config) *cobra.Command {
+	var file string
+	var runs int
+
+	cmd := &cobra.Command{
+		Use:   "bench --file sample.wav",
+		Short: "Benchmark Whisper and the pipeline stages on an audio file",
+		Long: `Run the file through the live pipeline stages (decoding, VAD segmentation, Whisper
+and, with --ai, the AI backend) and report per-stage latencies, the real-time factor
+and memory usage, to pick the right model for your hardware.
+
+The real-time factor is the Whisper time divided by the audio duration: below 1 the
+model keeps up with live speech.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if runs < 1 {
+				runs = 1
+			}
+
+			var aiService ai.AIService
+			if cfg.AIEnabled {
+				if aiService, _ = newAIComponents(cfg); aiService == nil {
+					logger.Warn("⚠️  No AI backend available, skipping the AI stage")
+				}
+			}
+
+			if err := runBench(*cfg, file, runs, aiService); err != nil {
+				logger.WithError(err).Fatal("❌ Benchmark failed")
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&file, "file", "", "Audio file to benchmark with")
+	cmd.Flags().IntVar(&runs, "runs", 1, "Number of times the file is processed")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// runBench measures every stage and prints the report
+func runBench(cfg config.Config, file string, runs int, aiService ai.AIService) error {
+	fmt.Printf("⏱️  Benchmarking %s with %s (%d run(s))\n", file, cfg.WhisperModel, runs)
+
+	start := time.Now()
+	samples, err := audio.NewFFmpegDecoder().DecodeFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", file, err)
+	}
+	decode := time.Since(start)
+	audioDuration := time.Duration(len(samples)) * time.Second / sampleRate
+
+	whisperService := newWhisperService(cfg)
+	start = time.Now()
+	if err := whisperService.LoadModel(cfg.WhisperModel); err != nil {
+		return fmt.Errorf("failed to load Whisper model: %w", err)
+	}
+	load := time.Since(start)
+	defer whisperService.Close()
+
+	vadStage := &benchStage{name: "VAD"}
+	whisperStage := &benchStage{name: "Whisper (per utterance)"}
+	aiStage := &benchStage{name: "AI (first token)"}
+	aiTotalStage := &benchStage{name: "AI (full answer)"}
+	utterances := 0
+
+	for run := 0; run < runs; run++ {
+		start = time.Now()
+		segments, err := benchSegments(samples)
+		if err != nil {
+			return err
+		}
+		vadStage.add(time.Since(start))
+		utterances = len(segments)
+
+		for _, segment := range segments {
+			start = time.Now()
+			result, err := whisperService.Transcribe(context.Background(), segment, cfg.Language)
+			if err != nil {
+				return fmt.Errorf("failed to transcribe: %w", err)
+			}
+			whisperStage.add(time.Since(start))
+
+			if aiService != nil && strings.TrimSpace(result.Text) != "" {
+				firstToken, answer, err := benchAI(aiService, cfg.SystemPrompt, result.Text)
+				if err != nil {
+					logger.WithError(err).Warn("⚠️  AI request failed")
+					continue
+				}
+				aiStage.add(firstToken)
+				aiTotalStage.add(answer)
+			}
+		}
+	}
+	whisperTotal := whisperStage.total() / time.Duration(runs)
+
+	fmt.Println("─────────────────────────────────────────────")
+	fmt.Printf("🎧 Audio: %s, %d utterance(s)\n", audioDuration.Round(time.Millisecond), utterances)
+	fmt.Printf("📂 Decoding: %s\n", decode.Round(time.Millisecond))
+	fmt.Printf("📦 Model load: %s\n", load.Round(time.Millisecond))
+	for _, stage := range []*benchStage{vadStage, whisperStage, aiStage, aiTotalStage} {
+		if len(stage.latencies) == 0 {
+			continue
+		}
+		fmt.Printf("⏱️  %-24s p50 %-10s p95 %-10s max %s\n", stage.name,
+			stage.percentile(50).Round(time.Millisecond),
+			stage.percentile(95).Round(time.Millisecond),
+			stage.percentile(100).Round(time.Millisecond))
+	}
+
+	fmt.Printf("🎙️  Transcription time: %s per run\n", whisperTotal.Round(time.Millisecond))
+	if audioDuration > 0 {
+		rtf := whisperTotal.Seconds() / audioDuration.Seconds()
+		verdict := "✅ fast enough for live use"
+		if rtf >= 1 {
+			verdict = "⚠️  slower than real time, try a smaller model"
+		}
+		fmt.Printf("🚀 Real-time factor: %.2f (%s)\n", rtf, verdict)
+	}
+
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	if peak, err := peakRSS(); err == nil {
+		fmt.Printf("🧠 Memory: peak RSS %d MiB, Go heap %d MiB\n", peak>>20, memStats.HeapSys>>20)
+	} else {
+		fmt.Printf("🧠 Memory: Go heap %d MiB\n", memStats.HeapSys>>20)
+	}
+	return nil
+}
+
+// benchSegments cuts samples into utterances the way the live pipeline
+// does: on silence after speech, or when the buffer is full
+func benchSegments(samples []float32) ([][]float32, error) {
+	vadConfig := assistant.DefaultVADConfig()
+	detector := vad.NewRMSDetector()
+	if err := detector.Initialize(vadConfig); err != nil {
+		return nil, err
+	}
+
+	silenceThresholdSamples := (vadConfig.SilenceDurationMs * sampleRate) / 1000
+	minSpeechSamples := (vadConfig.MinSpeechDurationMs * sampleRate) / 1000
+	maxBufferSize := sampleRate * assistant.MaxBufferDurationS
+
+	var segments [][]float32
+	begin := 0
+	for i, sample := range samples {
+		detector.ProcessSample(sample)
+		length := i + 1 - begin
+
+		if detector.IsSpeaking() && detector.GetSilenceDuration() >= silenceThresholdSamples {
+			if length >= minSpeechSamples {
+				segments = append(segments, samples[begin:i+1])
+			}
+			begin = i + 1
+			detector.Reset()
+		} else if length >= maxBufferSize {
+			segments = append(segments, samples[begin:i+1])
+			begin = i + 1
+			detector.Reset()
+		}
+	}
+
+	// Trailing speech, as flushed on shutdown
+	if detector.IsSpeaking() && len(samples)-begin >= minSpeechSamples {
+		segments = append(segments, samples[begin:])
+	}
+	return segments, nil
+}
+
+// benchAI sends a transcript to the AI and returns the time to the first
+// token and to the full answer
+func benchAI(service ai.AIService, systemPrompt, text string) (time.Duration, time.Duration, error) {
+	start := time.Now()
+	stream, err := service.ChatStream(ai.ChatRequest{
+		Messages: []ai.Message{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: text},
+		},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var firstToken time.Duration
+	_, err = ai.AggregateStream(stream, func(string) {
+		if firstToken == 0 {
+			firstToken = time.Since(start)
+		}
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return firstToken, time.Since(start), nil
+}
+
+// peakRSS returns the peak resident memory of the process in bytes. It
+// includes the Whisper model allocated outside of the Go heap. Linux only.
+func peakRSS() (int64, error) {
+	file, err := os.Open("/proc/self/status")
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "VmHWM:" {
+			kb, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			return kb << 10, nil
+		}
+	}
+	return 0, fmt.Errorf("VmHWM not found in /proc/self/status")
+}
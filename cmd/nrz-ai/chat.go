@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/spf13/cobra"
+)
+
+func createChatCmd(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "chat",
+		Short: "Chat with the AI in the terminal, without audio",
+		Long: `Run an interactive text chat against the configured AI backend (Ollama and/or
+Home Assistant), with the same system prompt and conversation history as voice mode.
+Commands:
+  /system <prompt>   replace the system prompt (persona)
+  /undo              forget the last exchange
+  /clear             forget the conversation
+  /history           print the conversation
+  /export <file>     save the conversation as JSON (see nrz-ai conversation)
+  /import <file>     replace the conversation with a JSON file
+  /exit              quit (or Ctrl-D)`,
+		Run: func(cmd *cobra.Command, args []string) {
+			// No point in a chat without AI, whatever the config says
+			cfg.AIEnabled = true
+			aiService, conversation := newAIComponents(cfg)
+			if aiService == nil {
+				logger.WithField("ollama_url", cfg.OllamaURL).Fatal("❌ No AI backend available")
+			}
+			defer aiService.Close()
+
+			fmt.Println("💬 Type a message, /exit to quit")
+			runChat(os.Stdin, os.Stdout, aiService, conversation)
+		},
+	}
+}
+
+// runChat reads messages line by line from in until EOF or /exit and
+// streams the AI answers to out
+func runChat(in io.Reader, out io.Writer, service ai.AIService, conversation ai.ConversationManager) {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "🧑 > ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "/") {
+			if !chatCommand(out, line, conversation) {
+				return
+			}
+			continue
+		}
+
+		if err := chatTurn(out, line, service, conversation); err != nil {
+			logger.WithError(err).Error("❌ AI Error")
+		}
+	}
+}
+
+// chatCommand runs a slash command, returning false to quit
+func chatCommand(out io.Writer, line string, conversation ai.ConversationManager) bool {
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch name {
+	case "/exit", "/quit":
+		return false
+	case "/clear":
+		conversation.ClearHistory()
+		fmt.Fprintln(out, "🧹 AI conversation history cleared")
+	case "/undo":
+		if removed := conversation.RemoveLast(ai.LastExchange(conversation.GetMessages())); len(removed) == 0 {
+			fmt.Fprintln(out, "⚠️  Nothing to undo")
+			break
+		}
+		fmt.Fprintln(out, "↩️  Last exchange forgotten")
+	case "/system":
+		if arg == "" {
+			fmt.Fprintln(out, "⚠️  Usage: /system <prompt>")
+			break
+		}
+		conversation.SetSystemPrompt(arg)
+		fmt.Fprintln(out, "🎭 AI persona updated")
+	case "/history":
+		for _, message := range conversation.GetMessages() {
+			if message.Meta != nil {
+				fmt.Fprintf(out, "[%s %s] %s\n", message.Meta.Time.Format("15:04:05"), message.Role, message.Content)
+				continue
+			}
+			fmt.Fprintf(out, "[%s] %s\n", message.Role, message.Content)
+		}
+	case "/export":
+		if arg == "" {
+			fmt.Fprintln(out, "⚠️  Usage: /export <file>")
+			break
+		}
+		var exported bytes.Buffer
+		if err := conversation.ExportJSON(&exported); err != nil {
+			logger.WithError(err).Error("❌ Failed to export the conversation")
+			break
+		}
+		if err := writeConversation(arg, exported.Bytes()); err != nil {
+			logger.WithError(err).Error("❌ Failed to write the conversation")
+			break
+		}
+		fmt.Fprintf(out, "💾 AI conversation saved to %s\n", arg)
+	case "/import":
+		if arg == "" {
+			fmt.Fprintln(out, "⚠️  Usage: /import <file>")
+			break
+		}
+		data, err := os.ReadFile(arg)
+		if err == nil {
+			err = conversation.ImportJSON(bytes.NewReader(data))
+		}
+		if err != nil {
+			logger.WithError(err).Error("❌ Failed to import the conversation")
+			break
+		}
+		fmt.Fprintf(out, "📥 AI conversation imported from %s\n", arg)
+	default:
+		fmt.Fprintf(out, "⚠️  Unknown command %s (/system, /undo, /clear, /history, /export, /import, /exit)\n", name)
+	}
+	return true
+}
+
+// chatTurn sends a user message and prints the answer as it streams in.
+// The message is only kept in the history once answered.
+func chatTurn(out io.Writer, text string, service ai.AIService, conversation ai.ConversationManager) error {
+	userMsg := ai.Message{Role: "user", Content: text, Meta: ai.NewMetadata(time.Now(), ai.SourceText)}
+	request := ai.ChatRequest{
+		Messages: append(conversation.GetMessages(), userMsg),
+	}
+
+	stream, err := service.ChatStream(request)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(out, "🤖 ")
+	response, err := ai.AggregateStream(stream, func(token string) {
+		fmt.Fprint(out, token)
+	})
+	fmt.Fprintln(out)
+	if err != nil {
+		return err
+	}
+
+	answer := strings.TrimSpace(response.Message.Content)
+	if answer == "" {
+		logger.Warn("⚠️  Warning: AI returned empty response")
+		return nil
+	}
+
+	conversation.AddMessage(userMsg)
+	conversation.AddMessage(ai.Message{
+		Role:    "assistant",
+		Content: answer,
+		Meta:    &ai.Metadata{Time: time.Now(), Model: response.Model},
+	})
+	return nil
+}
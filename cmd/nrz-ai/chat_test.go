@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+)
+
+func TestRunChat(t *testing.T) {
+	conversation := ai.NewMockConversationManager()
+	var out strings.Builder
+	in := strings.NewReader("Bonjour\n\n/system Tu es Jack\n/history\n/undo\n/undo\n/nope\n/exit\nJamais lu\n")
+
+	runChat(in, &out, ai.NewMockAIService(), conversation)
+
+	for _, expected := range []string{
+		"🤖 Mock streaming response\n",
+		"🎭 AI persona updated",
+		" user] Bonjour\n",
+		"↩️  Last exchange forgotten",
+		"⚠️  Nothing to undo",
+		"⚠️  Unknown command /nope",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %q in the output, got %q", expected, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "Jamais lu") || strings.Count(out.String(), "🤖") != 1 {
+		t.Errorf("Expected the chat to stop on /exit, got %q", out.String())
+	}
+	if messages := conversation.GetMessages(); len(messages) != 0 {
+		t.Errorf("Expected the exchange to be undone, got %+v", messages)
+	}
+	if prompt := conversation.ReplaceSystemPrompt(""); prompt != "Tu es Jack" {
+		t.Errorf("Expected the new system prompt, got %q", prompt)
+	}
+}
+
+func TestRunChat_KeepsAnsweredMessages(t *testing.T) {
+	conversation := ai.NewMockConversationManager()
+	service := ai.NewMockAIService()
+	var out strings.Builder
+
+	runChat(strings.NewReader("Bonjour\n"), &out, service, conversation)
+	service.SetResponses([]ai.ChatResponse{{Message: ai.Message{Role: "assistant", Content: "  "}, Done: true}})
+	runChat(strings.NewReader("Tu es là ?\n"), &out, service, conversation)
+
+	messages := conversation.GetMessages()
+	if len(messages) != 2 || messages[0].Content != "Bonjour" || messages[1].Content != "Mock streaming response" {
+		t.Errorf("Expected only the answered exchange in the history, got %+v", messages)
+	}
+	if messages[0].Meta == nil || messages[0].Meta.Source != ai.SourceText {
+		t.Errorf("Expected the message to be kept with its source, got %+v", messages[0].Meta)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/nerzhul/nrz-ai/internal/i18n"
+	"github.com/nerzhul/nrz-ai/internal/intents"
+	"github.com/nerzhul/nrz-ai/pkg/assistant"
+)
+
+// languageNames maps spoken language names to Whisper codes
+var languageNames = map[string]string{
+	"français": "fr", "francais": "fr", "french": "fr",
+	"anglais": "en", "english": "en",
+	"espagnol": "es", "spanish": "es",
+	"allemand": "de", "german": "de",
+	"italien": "it", "italian": "it",
+	"portugais": "pt", "portuguese": "pt",
+	"néerlandais": "nl", "dutch": "nl",
+}
+
+// voiceCommands are the built-in commands controlling the assistant. They
+// must be the whole utterance so that sentences merely containing "répète"
+// still go to the AI.
+func voiceCommands() []intents.Intent {
+	return []intents.Intent{
+		{
+			Name:     "clear_history",
+			Patterns: []string{"^(?:efface|oublie) (?:l'historique|la conversation|tout)$", "^(?:clear|forget) (?:the )?(?:history|conversation)$"},
+			Handler:  "clear_history",
+		},
+		{
+			Name:     "forget_last",
+			Patterns: []string{"^(?:oublie ça|oublie ce que je viens de dire|laisse tomber)$", "^(?:forget that|scratch that|never mind)$"},
+			Handler:  "forget_last",
+		},
+		{
+			Name:     "set_language",
+			Patterns: []string{"^(?:change de langue|passe|parle) en {language}$", "^(?:switch to|speak) {language}$"},
+			Handler:  "set_language",
+		},
+		{
+			Name:     "stop",
+			Patterns: []string{"^(?:arrête-toi|arrête d'écouter|stop|tais-toi)$", "^stop listening$"},
+			Handler:  "stop",
+		},
+		{
+			Name:     "repeat",
+			Patterns: []string{"^(?:tu peux )?(?:répète|répéter)(?: s'il te plaît| stp)?$", "^(?:can you )?repeat(?: that| please)*$"},
+			Handler:  "repeat",
+		},
+		{
+			Name:     "shorter",
+			Patterns: []string{"^(?:plus court|en plus court|sois plus bref|plus bref)(?: s'il te plaît| stp)?$", "^(?:shorter|be more concise)(?: please)?$"},
+			Handler:  "shorter",
+		},
+		{
+			Name:     "summarize",
+			Patterns: []string{"^(?:résume|fais-moi un résumé de) (?:la|notre) conversation(?: s'il te plaît| stp)?$", "^(?:summarize|sum up) (?:the|our) conversation(?: please)?$"},
+			Handler:  "summarize",
+		},
+	}
+}
+
+// newCommandRouter routes the built-in voice commands to the processor
+func newCommandRouter(sp *assistant.Assistant, language string) (*intents.Router, error) {
+	router, err := intents.NewRouter(voiceCommands(), language)
+	if err != nil {
+		return nil, err
+	}
+
+	router.Register("clear_history", intents.HandlerFunc(func(match intents.Match) (string, error) {
+		sp.ClearHistory()
+		return i18n.T(match.Language, "command.history_cleared"), nil
+	}))
+
+	router.Register("forget_last", intents.HandlerFunc(func(match intents.Match) (string, error) {
+		if !sp.ForgetLast() {
+			return i18n.T(match.Language, "command.nothing_to_forget"), nil
+		}
+		return i18n.T(match.Language, "command.forgot_last"), nil
+	}))
+
+	router.Register("set_language", intents.HandlerFunc(func(match intents.Match) (string, error) {
+		name := strings.ToLower(match.Slots["language"])
+		code, ok := languageNames[name]
+		if !ok {
+			return i18n.T(match.Language, "command.unknown_language", name), nil
+		}
+		if err := sp.SetLanguage(code); err != nil {
+			return "", err
+		}
+		// Answer in the new language
+		return i18n.T(code, "command.language_set", i18n.T(code, "language."+code)), nil
+	}))
+
+	router.Register("stop", intents.HandlerFunc(func(match intents.Match) (string, error) {
+		if wakeWord := sp.WakeWord(); wakeWord != "" {
+			sp.Deactivate()
+			return i18n.T(match.Language, "command.stop_wake_word", wakeWord), nil
+		}
+		sp.Pause()
+		return i18n.T(match.Language, "command.paused"), nil
+	}))
+
+	router.Register("repeat", intents.HandlerFunc(func(match intents.Match) (string, error) {
+		if last := sp.LastReply(); last != "" {
+			return last, nil
+		}
+		return i18n.T(match.Language, "command.nothing_said"), nil
+	}))
+
+	router.Register("shorter", intents.HandlerFunc(func(match intents.Match) (string, error) {
+		if !sp.AIEnabled() || sp.LastReply() == "" {
+			return i18n.T(match.Language, "command.nothing_to_shorten"), nil
+		}
+		return sp.Ask(i18n.T(match.Language, "command.shorten_prompt"))
+	}))
+
+	router.Register("summarize", intents.HandlerFunc(func(match intents.Match) (string, error) {
+		if !sp.AIEnabled() || sp.LastReply() == "" {
+			return i18n.T(match.Language, "command.nothing_to_summarize"), nil
+		}
+		// The summary is the next AI answer, archived with the session
+		return sp.Ask(i18n.T(match.Language, "command.summarize_prompt"))
+	}))
+
+	if err := router.Validate(); err != nil {
+		return nil, err
+	}
+	return router, nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/control"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/internal/privacy"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/spf13/cobra"
+)
+
+func createConversationCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "conversation",
+		Short: "Export or import the AI conversation of a running nrz-ai",
+		Long: `Move the AI conversation of an nrz-ai started with --control to another machine,
+seed it with a scripted context, or inspect and edit it by hand. Conversations are
+JSON documents:
+  {"version": 1, "system_prompt": "...", "messages": [{"role": "user", "content": "..."}]}`,
+	}
+	cmd.AddCommand(createConversationExportCmd(cfg))
+	cmd.AddCommand(createConversationImportCmd(cfg))
+	return cmd
+}
+
+func createConversationExportCmd(cfg *config.Config) *cobra.Command {
+	var out string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write the AI conversation as JSON to stdout or --out",
+		Run: func(cmd *cobra.Command, args []string) {
+			reply, err := control.Send(controlSocketPath(*cfg), "export-conversation")
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Failed to export the conversation")
+			}
+			var indented bytes.Buffer
+			if err := json.Indent(&indented, []byte(reply), "", "  "); err != nil {
+				logger.WithError(err).Fatal("❌ Invalid conversation received")
+			}
+			indented.WriteByte('\n')
+
+			if out == "" {
+				os.Stdout.Write(indented.Bytes())
+				return
+			}
+			if err := writeConversation(out, indented.Bytes()); err != nil {
+				logger.WithError(err).Fatal("❌ Failed to write the conversation")
+			}
+			fmt.Printf("✅ AI conversation → %s\n", out)
+		},
+	}
+
+	cmd.Flags().StringVar(&out, "out", "", "File to write instead of stdout")
+
+	return cmd
+}
+
+func createConversationImportCmd(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "import <file|->",
+		Short: "Replace the AI conversation with a JSON file, - for stdin",
+		Long: `Replace the history of the AI conversation. The system prompt of the file
+replaces the current one, which is kept when the file has none.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			data, err := readConversation(args[0])
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Failed to read the conversation")
+			}
+			// Check it here for errors pointing at the file, then send it on one line
+			conversation, err := ai.DecodeConversation(bytes.NewReader(data))
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Invalid conversation")
+			}
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, data); err != nil {
+				logger.WithError(err).Fatal("❌ Invalid conversation")
+			}
+			if _, err := control.Send(controlSocketPath(*cfg), "import-conversation "+compact.String()); err != nil {
+				logger.WithError(err).Fatal("❌ Failed to import the conversation")
+			}
+			fmt.Printf("✅ AI conversation imported (%d messages)\n", len(conversation.Messages))
+		},
+	}
+}
+
+// readConversation reads a conversation file, or stdin for "-"
+func readConversation(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// writeConversation writes a conversation file only its owner may read
+func writeConversation(path string, data []byte) error {
+	if err := privacy.Check("conversation export"); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/control"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+func createCtlCmd(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "ctl <command> [args]",
+		Short: "Send a command to a running nrz-ai",
+		Long: `Drive an nrz-ai started with --control through its Unix socket:
+  pause                 stop processing audio
+  resume                restart audio processing
+  clear-history         forget the AI conversation
+  forget-last           forget the last AI exchange, e.g. a misheard request
+  set-language <lang>   change the transcription language
+  lock-language         stop switching to the spoken language automatically
+  unlock-language       switch to the spoken language again
+  say <text>            handle text as if it had been spoken
+  status                print the assistant state as JSON
+  export-conversation   print the AI conversation as JSON (see nrz-ai conversation)
+  import-conversation <json>
+                        replace the AI conversation`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// The server takes everything after the command word as its argument
+			reply, err := control.Send(controlSocketPath(*cfg), strings.Join(args, " "))
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Control command failed")
+			}
+			if reply != "" {
+				fmt.Println(reply)
+			}
+		},
+	}
+}
+
+// controlSocketPath returns the configured control socket or the default one
+func controlSocketPath(cfg config.Config) string {
+	if cfg.ControlSocket != "" {
+		return cfg.ControlSocket
+	}
+	return control.DefaultSocketPath()
+}
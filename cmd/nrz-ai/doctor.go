@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/hardware"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/spf13/cobra"
+)
+
+// ggmlMagic starts every whisper.cpp model file ("ggml" little-endian)
+const ggmlMagic = 0x67676d6c
+
+// checkStatus is the outcome of a doctor check
+type checkStatus int
+
+const (
+	checkOK checkStatus = iota
+	checkWarn
+	checkFail
+	checkSkip
+)
+
+// checkResult describes the outcome of a check and how to fix it
+type checkResult struct {
+	status checkStatus
+	detail string
+	fix    string
+}
+
+// doctorCheck is a single environment check
+type doctorCheck struct {
+	name string
+	run  func(cfg config.Config) checkResult
+}
+
+// doctorChecks lists the checks in the order they are run
+var doctorChecks = []doctorCheck{
+	{"ffmpeg", checkFFmpeg},
+	{"ffplay", checkFFplay},
+	{"Audio server", checkAudioServer},
+	{"Audio source", checkAudioSource},
+	{"Whisper model", checkWhisperModel},
+	{"GPU", checkGPU},
+	{"Ollama", checkOllama},
+	{"Home Assistant", checkHomeAssistant},
+}
+
+func createDoctorCmd(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the environment and suggest fixes",
+		Long: `Check everything nrz-ai depends on: ffmpeg/ffplay, the PulseAudio or PipeWire
+server and the configured audio source, the Whisper model file, GPU acceleration,
+and the Ollama and Home Assistant backends when enabled.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Println("🩺 NRZ-AI doctor")
+
+			failed := 0
+			for _, check := range doctorChecks {
+				result := check.run(*cfg)
+				fmt.Printf("%s %s: %s\n", statusIcon(result.status), check.name, result.detail)
+				if result.fix != "" && (result.status == checkWarn || result.status == checkFail) {
+					fmt.Printf("   → %s\n", result.fix)
+				}
+				if result.status == checkFail {
+					failed++
+				}
+			}
+
+			if failed > 0 {
+				logger.WithField("failed", failed).Fatalf("❌ %d check(s) failed", failed)
+			}
+			fmt.Println("✅ Everything looks good")
+		},
+	}
+}
+
+// statusIcon returns the emoji printed in front of a check
+func statusIcon(status checkStatus) string {
+	switch status {
+	case checkOK:
+		return "✅"
+	case checkWarn:
+		return "⚠️ "
+	case checkFail:
+		return "❌"
+	default:
+		return "⏭️ "
+	}
+}
+
+// runTool runs a diagnostic command with a short timeout and returns its output
+func runTool(name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, name, args...).Output()
+	return string(output), err
+}
+
+func checkFFmpeg(cfg config.Config) checkResult {
+	path, err := exec.LookPath(audio.FFmpegPath())
+	if err != nil {
+		return checkResult{checkFail, fmt.Sprintf("%s not found", audio.FFmpegPath()),
+			"Install ffmpeg (sudo apt install ffmpeg / sudo dnf install ffmpeg), it captures and decodes all audio, or fix ffmpeg_path"}
+	}
+	format := audio.InputFormat()
+	if cfg.FFmpegInputFormat != "" {
+		format = cfg.FFmpegInputFormat
+	}
+	if format == audio.FormatProbe {
+		return checkResult{status: checkOK, detail: path}
+	}
+	demuxers, err := runTool(path, "-hide_banner", "-demuxers")
+	if err == nil && !strings.Contains(demuxers, " "+format+" ") {
+		return checkResult{checkWarn, fmt.Sprintf("%s (no %s input support)", path, format),
+			"Install an ffmpeg build with microphone input support (libpulse on Linux)"}
+	}
+	return checkResult{status: checkOK, detail: path}
+}
+
+func checkFFplay(cfg config.Config) checkResult {
+	path, err := exec.LookPath("ffplay")
+	if err != nil {
+		status := checkWarn
+		if cfg.SoundTheme == "off" && cfg.WakeWordSound == "" && cfg.TimerSound == "" {
+			status = checkSkip
+		}
+		return checkResult{status, "not found in PATH, audio feedback is disabled",
+			"Install ffplay, usually shipped with the ffmpeg package"}
+	}
+	return checkResult{status: checkOK, detail: path}
+}
+
+func checkAudioServer(cfg config.Config) checkResult {
+	if audio.IsPipeSource(cfg.AudioSource) {
+		return checkResult{status: checkSkip, detail: "audio read from " + cfg.AudioSource}
+	}
+	if audio.InputFormat() != "pulse" {
+		return checkResult{status: checkSkip, detail: "no audio server with " + audio.InputFormat()}
+	}
+	if _, err := exec.LookPath("pactl"); err != nil {
+		return checkResult{checkWarn, "pactl not found, cannot query the audio server",
+			"Install pulseaudio-utils (also works with PipeWire through pipewire-pulse)"}
+	}
+
+	info, err := runTool("pactl", "info")
+	if err != nil {
+		return checkResult{checkFail, "PulseAudio/PipeWire server not reachable",
+			"Start it with: systemctl --user start pipewire-pulse (or pulseaudio --start)"}
+	}
+	return checkResult{status: checkOK, detail: pactlField(info, "Server Name")}
+}
+
+func checkAudioSource(cfg config.Config) checkResult {
+	if audio.IsPipeSource(cfg.AudioSource) {
+		return checkPipe(cfg)
+	}
+	if audio.InputFormat() != "pulse" {
+		return checkDevice(cfg.AudioSource)
+	}
+	if _, err := exec.LookPath("pactl"); err != nil {
+		return checkResult{status: checkSkip, detail: "pactl not available"}
+	}
+
+	if cfg.AudioSource == "" || cfg.AudioSource == "default" {
+		info, err := runTool("pactl", "info")
+		if err != nil {
+			return checkResult{status: checkSkip, detail: "audio server not reachable"}
+		}
+		source := pactlField(info, "Default Source")
+		if source == "" {
+			return checkResult{checkFail, "no default source",
+				"Plug a microphone or pick one with --audio-source (see pactl list short sources)"}
+		}
+		if strings.HasSuffix(source, ".monitor") {
+			return checkResult{checkWarn, "default source " + source + " is a speaker monitor",
+				"Select a microphone as default source or pass --audio-source"}
+		}
+		return checkResult{status: checkOK, detail: "default (" + source + ")"}
+	}
+
+	sources, err := runTool("pactl", "list", "short", "sources")
+	if err != nil {
+		return checkResult{status: checkSkip, detail: "audio server not reachable"}
+	}
+	var names []string
+	for _, line := range strings.Split(sources, "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 {
+			if fields[1] == cfg.AudioSource {
+				return checkResult{status: checkOK, detail: cfg.AudioSource}
+			}
+			names = append(names, fields[1])
+		}
+	}
+	return checkResult{checkFail, fmt.Sprintf("source '%s' not found", cfg.AudioSource),
+		"Use one of: " + strings.Join(names, ", ")}
+}
+
+// checkDevice looks the audio source up in the input devices
+func checkDevice(source string) checkResult {
+	devices, err := audio.ListDevices()
+	if err != nil {
+		return checkResult{checkFail, "cannot list the audio devices", "Check ffmpeg is installed"}
+	}
+	var names []string
+	for _, device := range devices {
+		if device.Name == source {
+			return checkResult{status: checkOK, detail: source}
+		}
+		names = append(names, device.Name)
+	}
+	if len(devices) == 0 {
+		return checkResult{checkFail, "no audio input device", "Plug a microphone"}
+	}
+	if source == "" || source == audio.DefaultSource {
+		return checkResult{status: checkOK, detail: "default (" + devices[0].Name + ")"}
+	}
+	return checkResult{checkFail, fmt.Sprintf("source '%s' not found", source),
+		"Use one of: " + strings.Join(names, ", ")}
+}
+
+// checkPipe checks the pipe or socket of the audio source exists and its
+// format can be read
+func checkPipe(cfg config.Config) checkResult {
+	format := audio.PipeFormat{Encoding: cfg.PipeFormat, SampleRate: cfg.PipeSampleRate, Channels: cfg.PipeChannels}
+	if err := format.Validate(); err != nil {
+		return checkResult{checkFail, err.Error(), "Set pipe_format, pipe_sample_rate and pipe_channels to the PCM written"}
+	}
+
+	socket := strings.HasPrefix(cfg.AudioSource, audio.SocketPrefix)
+	path := strings.TrimPrefix(strings.TrimPrefix(cfg.AudioSource, audio.PipePrefix), audio.SocketPrefix)
+	info, err := os.Stat(path)
+	switch {
+	case err != nil && socket:
+		return checkResult{checkFail, path + " not found", "Start the host capture listening on the socket first"}
+	case err != nil:
+		return checkResult{checkFail, path + " not found", "Create the pipe with: mkfifo " + path}
+	case socket && info.Mode()&os.ModeSocket == 0:
+		return checkResult{checkFail, path + " is not a socket", "Use pipe:" + path + " for a named pipe or a file"}
+	}
+	return checkResult{status: checkOK, detail: fmt.Sprintf("%s (%s, %d Hz, %d channel(s))",
+		path, cfg.PipeFormat, cfg.PipeSampleRate, cfg.PipeChannels)}
+}
+
+// pactlField returns the value of a "Key: value" line of pactl info
+func pactlField(info, key string) string {
+	for _, line := range strings.Split(info, "\n") {
+		if value, found := strings.CutPrefix(strings.TrimSpace(line), key+":"); found {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+func checkWhisperModel(cfg config.Config) checkResult {
+	const fix = "Download a model with: make model (or from https://huggingface.co/ggerganov/whisper.cpp)"
+
+	stat, err := os.Stat(cfg.WhisperModel)
+	if err != nil {
+		return checkResult{checkFail, fmt.Sprintf("%s not found", cfg.WhisperModel), fix}
+	}
+
+	valid, err := isGGMLFile(cfg.WhisperModel)
+	if err != nil {
+		return checkResult{checkFail, fmt.Sprintf("%s not readable: %v", cfg.WhisperModel, err),
+			"Check the file permissions"}
+	}
+	if !valid {
+		return checkResult{checkFail, fmt.Sprintf("%s is not a ggml model (truncated download?)", cfg.WhisperModel),
+			"Delete it and download it again. " + fix}
+	}
+
+	return checkResult{status: checkOK,
+		detail: fmt.Sprintf("%s (%d MiB)", filepath.Base(cfg.WhisperModel), stat.Size()>>20)}
+}
+
+// isGGMLFile reports whether path starts with the ggml magic number
+func isGGMLFile(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return false, nil
+	}
+	return binary.LittleEndian.Uint32(header) == ggmlMagic, nil
+}
+
+func checkGPU(cfg config.Config) checkResult {
+	caps := hardware.Detect()
+	if caps.GPU() {
+		return checkResult{status: checkOK, detail: fmt.Sprintf("%s, real time up to ggml-%s", caps.Device, caps.Recommend())}
+	}
+	if caps.RenderNode != "" {
+		return checkResult{checkWarn, "render node " + caps.RenderNode + " but no ROCm/CUDA runtime",
+			"Install ROCm or CUDA and rebuild whisper.cpp with GPU support for faster transcription"}
+	}
+	return checkResult{checkWarn, fmt.Sprintf("none, Whisper runs on %d CPU cores", caps.Cores),
+		fmt.Sprintf("Prefer ggml-%s or smaller, use --auto-model, or check the real-time factor with nrz-ai bench", caps.Recommend())}
+}
+
+func checkOllama(cfg config.Config) checkResult {
+	if !cfg.AIEnabled || cfg.HomeAssistantMode == "only" {
+		return checkResult{status: checkSkip, detail: "AI disabled"}
+	}
+
+	service := ai.NewOllamaService(cfg.OllamaURL, cfg.OllamaModel)
+	if result, ok := applyAITransport(cfg, service.SetTransport); !ok {
+		return result
+	}
+	models, err := service.ListModels()
+	if err != nil {
+		return checkResult{checkFail, fmt.Sprintf("not reachable at %s", cfg.OllamaURL),
+			"Start it with: ollama serve (or check --ollama-url)"}
+	}
+
+	for _, model := range models {
+		if model == cfg.OllamaModel || model == cfg.OllamaModel+":latest" {
+			return checkResult{status: checkOK, detail: fmt.Sprintf("%s at %s", cfg.OllamaModel, cfg.OllamaURL)}
+		}
+	}
+	return checkResult{checkFail, fmt.Sprintf("model %s not installed", cfg.OllamaModel),
+		"Pull it with: ollama pull " + cfg.OllamaModel}
+}
+
+// applyAITransport passes the TLS and proxy settings to setTransport, ok is
+// false with the failed result when they are invalid
+func applyAITransport(cfg config.Config, setTransport func(http.RoundTripper)) (checkResult, bool) {
+	opts, set := aiTransportOptions(&cfg)
+	if !set {
+		return checkResult{}, true
+	}
+	transport, err := ai.NewTransport(opts)
+	if err != nil {
+		return checkResult{checkFail, err.Error(),
+			"Check ai_ca_file, ai_cert_file, ai_key_file and ai_proxy"}, false
+	}
+	setTransport(transport)
+	return checkResult{}, true
+}
+
+func checkHomeAssistant(cfg config.Config) checkResult {
+	if cfg.HomeAssistantMode == "" || cfg.HomeAssistantMode == "off" {
+		return checkResult{status: checkSkip, detail: "disabled"}
+	}
+	if cfg.HomeAssistantToken == "" {
+		return checkResult{checkFail, "no access token configured",
+			"Create a long-lived token in your Home Assistant profile and set homeassistant_token"}
+	}
+
+	service := ai.NewHomeAssistantService(cfg.HomeAssistantURL, cfg.HomeAssistantToken,
+		cfg.HomeAssistantAgentID, cfg.Language)
+	if result, ok := applyAITransport(cfg, service.SetTransport); !ok {
+		return result
+	}
+	if !service.IsAvailable() {
+		return checkResult{checkFail, fmt.Sprintf("not reachable at %s", cfg.HomeAssistantURL),
+			"Check homeassistant_url and that the token is still valid"}
+	}
+	return checkResult{status: checkOK, detail: cfg.HomeAssistantURL}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/archive"
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/history"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// historyIndexFile is the full-text index of the session archive
+func historyIndexFile() string {
+	return filepath.Join(config.StateDir(), "history.db")
+}
+
+func createHistoryCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Search what was said in the archived sessions",
+		Long: `Search the transcripts and AI answers of the session archive (see nrz-ai
+sessions). The archive is indexed in a SQLite full-text index, updated with the
+new and changed sessions before each search.`,
+	}
+	cmd.AddCommand(createHistorySearchCmd(cfg))
+	return cmd
+}
+
+func createHistorySearchCmd(cfg *config.Config) *cobra.Command {
+	var since, until, on, session string
+	var context, limit int
+
+	cmd := &cobra.Command{
+		Use:   "search <words...>",
+		Short: "Find the utterances containing all the words",
+		Long: `Print the utterances containing all the words, accents and case ignored, most
+recent first, with --context utterances around each one. Days are YYYY-MM-DD,
+today, yesterday or a day name for the last such day, e.g. --on tuesday.`,
+		Example: `  nrz-ai history search chauffage --on tuesday
+  nrz-ai history search "rendez-vous dentiste" --since 2024-06-01 --context 2
+  nrz-ai history search courses --session last`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			query := history.Query{
+				Text:    strings.Join(args, " "),
+				Session: session,
+				Context: context,
+				Limit:   limit,
+			}
+			now := time.Now()
+			if on != "" {
+				since, until = on, on
+			}
+			if since != "" {
+				day, err := history.ParseDay(since, now)
+				if err != nil {
+					logger.WithError(err).Fatal("❌ Invalid --since")
+				}
+				query.Since = day
+			}
+			if until != "" {
+				day, err := history.ParseDay(until, now)
+				if err != nil {
+					logger.WithError(err).Fatal("❌ Invalid --until")
+				}
+				// The whole day is included
+				query.Until = day.AddDate(0, 0, 1)
+			}
+
+			index, err := history.Open(historyIndexFile())
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Failed to open the history index")
+			}
+			defer index.Close()
+			if indexed, err := index.Sync(cfg.ArchiveDir); err != nil {
+				logger.WithError(err).Fatal("❌ Failed to index the session archive")
+			} else if indexed > 0 {
+				logger.Debugf("Indexed %d session(s) from %s", indexed, cfg.ArchiveDir)
+			}
+
+			matches, err := index.Search(query)
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Search failed")
+			}
+			if len(matches) == 0 {
+				fmt.Printf("No utterance matches \"%s\"\n", query.Text)
+				return
+			}
+
+			fmt.Printf("🔎 %d match(es) for \"%s\"\n", len(matches), query.Text)
+			for _, m := range matches {
+				fmt.Printf("\n── %s, %s\n", m.Session, m.Event.Time.Format("Mon 2 Jan 2006"))
+				for _, event := range m.Before {
+					printHistoryLine("  ", event)
+				}
+				printHistoryLine("› ", m.Event)
+				for _, event := range m.After {
+					printHistoryLine("  ", event)
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "Only utterances from this day on")
+	cmd.Flags().StringVar(&until, "until", "", "Only utterances until this day, included")
+	cmd.Flags().StringVar(&on, "on", "", "Only utterances of this day (same as --since and --until)")
+	cmd.Flags().StringVar(&session, "session", "", "Only search this session ID, or last")
+	cmd.Flags().IntVar(&context, "context", 1, "Utterances shown before and after each match")
+	cmd.Flags().IntVar(&limit, "limit", history.DefaultLimit, "Maximum number of matches")
+
+	return cmd
+}
+
+// printHistoryLine prints an archived utterance like the text export
+func printHistoryLine(prefix string, event output.Event) {
+	fmt.Printf("%s[%s] %s: %s\n", prefix, event.Time.Format("15:04:05"), archive.Author(event), strings.TrimSpace(event.Text))
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/assistant"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/spf13/cobra"
+)
+
+// modelBaseURL hosts the ggml Whisper models
+const modelBaseURL = "https://huggingface.co/ggerganov/whisper.cpp/resolve/main/"
+
+// whisperModel is a downloadable Whisper model
+type whisperModel struct {
+	name string
+	size string
+	note string
+}
+
+// whisperModels lists the models offered by the setup wizard, smallest first
+var whisperModels = []whisperModel{
+	{"tiny", "75 MiB", "fastest, rough accuracy"},
+	{"base", "142 MiB", "fast, fine for commands"},
+	{"small", "466 MiB", "good balance on CPU"},
+	{"medium", "1.5 GiB", "accurate, needs a fast CPU or a GPU"},
+	{"large-v3-turbo", "1.6 GiB", "near large-v3 accuracy, much faster"},
+	{"large-v3", "3.1 GiB", "best accuracy, GPU recommended"},
+}
+
+// wizard asks questions on a terminal
+type wizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask prints a question and returns the answer, or def when empty
+func (w *wizard) ask(question, def string) string {
+	if def != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+
+	line, _ := w.in.ReadString('\n')
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer
+	}
+	return def
+}
+
+// confirm asks a yes/no question
+func (w *wizard) confirm(question string, def bool) bool {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	answer := strings.ToLower(w.ask(fmt.Sprintf("%s (%s)", question, hint), ""))
+	switch answer {
+	case "y", "yes", "o", "oui":
+		return true
+	case "n", "no", "non":
+		return false
+	default:
+		return def
+	}
+}
+
+// choose prints numbered options and returns the chosen index
+func (w *wizard) choose(question string, options []string, def int) int {
+	for i, option := range options {
+		fmt.Fprintf(w.out, "  %d) %s\n", i+1, option)
+	}
+	for {
+		answer := w.ask(question, strconv.Itoa(def+1))
+		if index, err := strconv.Atoi(answer); err == nil && index >= 1 && index <= len(options) {
+			return index - 1
+		}
+		fmt.Fprintf(w.out, "⚠️  Pick a number between 1 and %d\n", len(options))
+	}
+}
+
+func createInitCmd(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "init",
+		Short: "Interactive first-run setup",
+		Long: `Walk through picking the microphone (with a level test), downloading a Whisper
+model, the language, the wake word and the AI, then write the config file
+(~/.config/nrz-ai/config.yaml).`,
+		Run: func(cmd *cobra.Command, args []string) {
+			w := &wizard{in: bufio.NewReader(os.Stdin), out: os.Stdout}
+			fmt.Println("👋 Welcome to NRZ-AI, let's set things up")
+
+			runSetup(w, cfg)
+
+			if err := cfg.SaveConfig(); err != nil {
+				logger.WithError(err).Fatal("❌ Failed to write the config file")
+			}
+			fmt.Printf("✅ Configuration saved to %s\n", filepath.Join(config.Dir(), "config.yaml"))
+			fmt.Println("🚀 Run nrz-ai to start, or nrz-ai doctor to check your setup")
+		},
+	}
+}
+
+// runSetup asks every setup question and updates cfg
+func runSetup(w *wizard, cfg *config.Config) {
+	fmt.Println("\n🎤 Microphone")
+	cfg.AudioSource = chooseAudioSource(w, cfg.AudioSource)
+	if w.confirm("Test the input level now?", true) {
+		testLevel(cfg.AudioSource, newFFmpegOptions(*cfg))
+	}
+
+	fmt.Println("\n📦 Whisper model")
+	cfg.WhisperModel = chooseModel(w, cfg.WhisperModel)
+
+	fmt.Println("\n🗣️  Language")
+	cfg.Language = w.ask("Language code (fr, en, es, de, ...)", cfg.Language)
+
+	fmt.Println("\n🎯 Wake word")
+	cfg.WakeWordEnabled = w.confirm("Only listen after a wake word?", cfg.WakeWordEnabled)
+	if cfg.WakeWordEnabled {
+		cfg.WakeWord = w.ask("Wake word", cfg.WakeWord)
+	}
+
+	fmt.Println("\n🤖 AI conversation")
+	cfg.AIEnabled = w.confirm("Answer with an Ollama model?", cfg.AIEnabled)
+	if cfg.AIEnabled {
+		cfg.OllamaURL = w.ask("Ollama URL", cfg.OllamaURL)
+		cfg.OllamaModel = chooseOllamaModel(w, cfg.OllamaURL, cfg.OllamaModel)
+	}
+}
+
+// chooseAudioSource lists the audio input devices, monitors last
+func chooseAudioSource(w *wizard, current string) string {
+	devices, err := audio.ListDevices()
+	if err != nil || len(devices) == 0 {
+		fmt.Println("⚠️  Could not list audio sources (see nrz-ai audio devices)")
+		return w.ask("Audio source", current)
+	}
+
+	options := make([]string, 0, len(devices))
+	def := 0
+	for i, device := range devices {
+		options = append(options, device.Name)
+		if device.Name == current {
+			def = i
+		}
+	}
+	return options[w.choose("Audio source", options, def)]
+}
+
+// testLevel captures two seconds of audio and shows the input level
+func testLevel(source string, options audio.FFmpegOptions) {
+	fmt.Println("🔴 Speak for 2 seconds...")
+
+	stream, err := audio.NewFFmpegCapture(options).StartCapture(source)
+	if err != nil {
+		logger.WithError(err).Error("❌ Failed to start audio capture")
+		return
+	}
+	defer stream.Close()
+
+	processor := audio.NewProcessor()
+	decoder := audio.NewFrameDecoder(processor)
+	var samples []float32
+	buffer := make([]byte, readChunkSize)
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		n, err := stream.Read(buffer)
+		if err != nil {
+			break
+		}
+		samples = append(samples, decoder.Decode(buffer[:n])...)
+	}
+	if len(samples) == 0 {
+		fmt.Println("❌ No audio received, check the source and its mute state")
+		return
+	}
+
+	// Loudest 100ms window, so pauses between words do not hide the voice
+	var peak float32
+	window := sampleRate / 10
+	for end := window; end <= len(samples); end += window {
+		if rms := processor.CalculateRMS(samples[:end], window); rms > peak {
+			peak = rms
+		}
+	}
+
+	bars := int(peak * 100)
+	if bars > 20 {
+		bars = 20
+	}
+	fmt.Printf("🎚️  Level: %s%s (%.3f)\n", strings.Repeat("█", bars), strings.Repeat("░", 20-bars), peak)
+	if peak < assistant.DefaultVADConfig().SilenceThreshold*2 {
+		fmt.Println("⚠️  Very low level, raise the microphone gain or pick another source")
+	} else {
+		fmt.Println("✅ Microphone works")
+	}
+}
+
+// chooseModel keeps an existing model or downloads one to the data directory
+func chooseModel(w *wizard, current string) string {
+	if _, err := os.Stat(current); err == nil && w.confirm(fmt.Sprintf("Keep the installed model %s?", current), true) {
+		return current
+	}
+
+	options := make([]string, 0, len(whisperModels))
+	for _, model := range whisperModels {
+		options = append(options, fmt.Sprintf("%-15s %-8s %s", model.name, model.size, model.note))
+	}
+	model := whisperModels[w.choose("Model", options, 2)]
+
+	path := filepath.Join(config.DataDir(), "models", "ggml-"+model.name+".bin")
+	if _, err := os.Stat(path); err == nil {
+		fmt.Printf("✅ Already downloaded: %s\n", path)
+		return path
+	}
+
+	fmt.Printf("📥 Downloading ggml-%s.bin (%s)...\n", model.name, model.size)
+	if err := downloadModel(modelBaseURL+"ggml-"+model.name+".bin", path); err != nil {
+		logger.WithError(err).Error("❌ Failed to download the model")
+		return w.ask("Path to a Whisper model file", current)
+	}
+	fmt.Printf("✅ Model saved to %s\n", path)
+	return path
+}
+
+// downloadModel fetches a model to path through a temporary file and checks
+// it is a ggml file
+func downloadModel(url, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	tmp := path + ".part"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	progress := &downloadProgress{total: resp.ContentLength}
+	_, err = io.Copy(io.MultiWriter(file, progress), resp.Body)
+	fmt.Println()
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if valid, err := isGGMLFile(tmp); err != nil || !valid {
+		return fmt.Errorf("downloaded file is not a ggml model")
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// downloadProgress prints the download percentage
+type downloadProgress struct {
+	total   int64
+	done    int64
+	percent int64
+}
+
+// Write counts downloaded bytes
+func (p *downloadProgress) Write(data []byte) (int, error) {
+	p.done += int64(len(data))
+	if p.total > 0 {
+		if percent := p.done * 100 / p.total; percent != p.percent {
+			p.percent = percent
+			fmt.Printf("\r   %3d%% (%d / %d MiB)", percent, p.done>>20, p.total>>20)
+		}
+	}
+	return len(data), nil
+}
+
+// chooseOllamaModel offers the installed Ollama models
+func chooseOllamaModel(w *wizard, url, current string) string {
+	models, err := ai.NewOllamaService(url, "").ListModels()
+	if err != nil || len(models) == 0 {
+		fmt.Printf("⚠️  No models found at %s, install one with: ollama pull %s\n", url, current)
+		return w.ask("Ollama model", current)
+	}
+
+	def := 0
+	for i, model := range models {
+		if model == current || model == current+":latest" {
+			def = i
+		}
+	}
+	return models[w.choose("Ollama model", models, def)]
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/intents"
+	"github.com/nerzhul/nrz-ai/internal/timers"
+)
+
+// newIntentRouter builds the intent router: intents of the intents file
+// first, then the built-in ones. A missing default intents file is fine.
+func newIntentRouter(cfg config.Config, timerManager *timers.Manager) (*intents.Router, string, error) {
+	path := cfg.IntentsFile
+	if path == "" {
+		path = filepath.Join(config.Dir(), "intents.yaml")
+	}
+
+	definitions, err := intents.LoadFile(path)
+	if errors.Is(err, os.ErrNotExist) && cfg.IntentsFile == "" {
+		path = "built-in"
+	} else if err != nil {
+		return nil, "", fmt.Errorf("failed to load intents: %w", err)
+	}
+
+	definitions = append(definitions, intents.DefaultIntents()...)
+	definitions = append(definitions, timers.Intents()...)
+
+	router, err := intents.NewRouter(definitions, cfg.Language)
+	if err != nil {
+		return nil, "", err
+	}
+	timers.RegisterHandlers(router, timerManager)
+	router.SetShellAllowlist(cfg.ShellAllowlist)
+
+	if err := router.Validate(); err != nil {
+		return nil, "", err
+	}
+	return router, path, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/logfile"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+)
+
+// logFiles holds the rotating files opened for a daemon session
+type logFiles struct {
+	files       []*logfile.RotatingFile
+	transcripts *logfile.TranscriptLog
+}
+
+// openLogFiles opens the log and transcript files enabled in cfg. A file
+// that cannot be opened is reported and skipped.
+func openLogFiles(cfg config.Config) *logFiles {
+	l := &logFiles{}
+	if !cfg.LogFile && !cfg.TranscriptLog {
+		return l
+	}
+
+	dir := cfg.LogDir
+	if dir == "" {
+		dir = config.StateDir()
+	}
+	rotation := logfile.RotateConfig{
+		MaxSize:    int64(cfg.LogMaxSizeMB) * 1024 * 1024,
+		MaxAge:     cfg.LogRotateInterval,
+		MaxBackups: cfg.LogMaxBackups,
+	}
+
+	if cfg.LogFile {
+		if f := l.open(filepath.Join(dir, "nrz-ai.log"), rotation); f != nil {
+			logger.AddFileOutput(f)
+			fmt.Printf("🗒️  Log file: %s\n", f.Path())
+		}
+	}
+
+	if cfg.TranscriptLog {
+		if f := l.open(filepath.Join(dir, "transcripts.log"), rotation); f != nil {
+			l.transcripts = logfile.NewTranscriptLog(f)
+			fmt.Printf("🗒️  Transcript log: %s\n", f.Path())
+		}
+	}
+
+	return l
+}
+
+// open opens a rotating file, returning nil on failure
+func (l *logFiles) open(path string, rotation logfile.RotateConfig) *logfile.RotatingFile {
+	f, err := logfile.NewRotatingFile(path, rotation)
+	if err != nil {
+		logger.WithError(err).Warnf("⚠️  Failed to open log file %s", path)
+		return nil
+	}
+	l.files = append(l.files, f)
+	return f
+}
+
+// Close closes every opened file
+func (l *logFiles) Close() {
+	for _, f := range l.files {
+		f.Close()
+	}
+}
@@ -1,326 +1,69 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
-	"os/exec"
 	"os/signal"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
-	"github.com/nerzhul/nrz-ai/internal/ai"
-	"github.com/nerzhul/nrz-ai/internal/audio"
+	"github.com/nerzhul/nrz-ai/internal/archive"
+	"github.com/nerzhul/nrz-ai/internal/blocklist"
+	"github.com/nerzhul/nrz-ai/internal/clipboard"
 	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/control"
+	"github.com/nerzhul/nrz-ai/internal/dbus"
+	"github.com/nerzhul/nrz-ai/internal/diarization"
+	"github.com/nerzhul/nrz-ai/internal/dictation"
+	"github.com/nerzhul/nrz-ai/internal/gpio"
+	"github.com/nerzhul/nrz-ai/internal/hardware"
+	"github.com/nerzhul/nrz-ai/internal/hooks"
+	"github.com/nerzhul/nrz-ai/internal/i18n"
+	"github.com/nerzhul/nrz-ai/internal/instance"
 	"github.com/nerzhul/nrz-ai/internal/logger"
-	"github.com/nerzhul/nrz-ai/internal/vad"
-	"github.com/nerzhul/nrz-ai/internal/whisper"
+	"github.com/nerzhul/nrz-ai/internal/matrix"
+	"github.com/nerzhul/nrz-ai/internal/mqtt"
+	"github.com/nerzhul/nrz-ai/internal/notes"
+	"github.com/nerzhul/nrz-ai/internal/notify"
+	"github.com/nerzhul/nrz-ai/internal/redact"
+	"github.com/nerzhul/nrz-ai/internal/server"
+	"github.com/nerzhul/nrz-ai/internal/sounds"
+	"github.com/nerzhul/nrz-ai/internal/systemd"
+	"github.com/nerzhul/nrz-ai/internal/telegram"
+	"github.com/nerzhul/nrz-ai/internal/timers"
+	"github.com/nerzhul/nrz-ai/internal/transcript"
+	"github.com/nerzhul/nrz-ai/internal/tui"
+	"github.com/nerzhul/nrz-ai/internal/webhook"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/assistant"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+	"github.com/nerzhul/nrz-ai/pkg/vad"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
 	"github.com/spf13/cobra"
 )
 
 const (
-	sampleRate          = 16000
-	readChunkSize       = 4096
-	silenceThreshold    = 0.01
-	silenceDurationMs   = 800
-	minSpeechDurationMs = 500
-	maxBufferDurationS  = 30
-	rmsWindowSize       = 160
-	noiseFloorSamples   = 32000
-)
-
-
-
-// SpeechProcessor handles the main speech-to-text processing
-type SpeechProcessor struct {
-	audioCapture   audio.AudioCapture
-	audioProcessor audio.AudioProcessor
-	vadDetector    vad.VoiceActivityDetector
-	whisperService whisper.WhisperService
-	aiService      ai.AIService
-	conversation   ai.ConversationManager
-
-	audioBuffer   []float32
-	language      string
-	maxBufferSize int
-	aiEnabled     bool
-
-	// Wake word detection
-	wakeWordEnabled bool
-	wakeWord        string
-	wakeWordSound   string
-	wakeWordBuffer  []float32
-	listeningActive bool
-} // NewSpeechProcessor creates a new speech processor
-func NewSpeechProcessor(
-	capture audio.AudioCapture,
-	processor audio.AudioProcessor,
-	detector vad.VoiceActivityDetector,
-	service whisper.WhisperService,
-	aiSvc ai.AIService,
-	conv ai.ConversationManager,
-	wakeWordEnabled bool,
-	wakeWord string,
-	wakeWordSound string,
-) *SpeechProcessor {
-	return &SpeechProcessor{
-		audioCapture:    capture,
-		audioProcessor:  processor,
-		vadDetector:     detector,
-		whisperService:  service,
-		aiService:       aiSvc,
-		conversation:    conv,
-		audioBuffer:     make([]float32, 0, sampleRate*maxBufferDurationS),
-		language:        "fr",
-		maxBufferSize:   sampleRate * maxBufferDurationS,
-		aiEnabled:       aiSvc != nil,
-		wakeWordEnabled: wakeWordEnabled,
-		wakeWord:        wakeWord,
-		wakeWordSound:   wakeWordSound,
-		wakeWordBuffer:  make([]float32, 0, sampleRate*2), // 2 seconds for wake word detection
-		listeningActive: !wakeWordEnabled,                 // If wake word disabled, always listen
-	}
-}
-
-// Initialize initializes all components
-func (sp *SpeechProcessor) Initialize(modelPath, audioSource, language string) error {
-	// Load Whisper model
-	if err := sp.whisperService.LoadModel(modelPath); err != nil {
-		return fmt.Errorf("failed to load Whisper model: %w", err)
-	}
-
-	sp.whisperService.SetLanguage(language)
-	sp.language = language
-
-	// Initialize VAD
-	vadConfig := vad.VADConfig{
-		SampleRate:          sampleRate,
-		SilenceThreshold:    silenceThreshold,
-		SilenceDurationMs:   silenceDurationMs,
-		MinSpeechDurationMs: minSpeechDurationMs,
-		RMSWindowSize:       rmsWindowSize,
-		NoiseFloorSamples:   noiseFloorSamples,
-	}
-
-	return sp.vadDetector.Initialize(vadConfig)
-}
-
-// detectWakeWord checks if the wake word is present in the audio buffer
-func (sp *SpeechProcessor) detectWakeWord() bool {
-	if !sp.wakeWordEnabled || len(sp.wakeWordBuffer) < sampleRate/2 {
-		return false
-	}
-
-	// Use Whisper to transcribe the wake word buffer
-	result, err := sp.whisperService.Transcribe(sp.wakeWordBuffer, sp.language)
-	if err != nil {
-		return false
-	}
-
-	// Check if wake word is present (case-insensitive)
-	text := strings.ToLower(strings.TrimSpace(result.Text))
-	wakeWord := strings.ToLower(sp.wakeWord)
-
-	return strings.Contains(text, wakeWord)
-}
-
-// resetWakeWordBuffer clears the wake word buffer
-func (sp *SpeechProcessor) resetWakeWordBuffer() {
-	sp.wakeWordBuffer = sp.wakeWordBuffer[:0]
-}
-
-// startListeningTimeout deactivates listening after 30 seconds of inactivity
-func (sp *SpeechProcessor) startListeningTimeout() {
-	time.Sleep(30 * time.Second)
-	if sp.wakeWordEnabled {
-		sp.listeningActive = false
-		fmt.Printf("🔍 Listening timeout. Waiting for wake word '%s' again...\n", sp.wakeWord)
-	}
-}
-
-// playWakeWordSound plays the wake word detection sound asynchronously
-func (sp *SpeechProcessor) playWakeWordSound() {
-	if sp.wakeWordSound == "" {
-		return
-	}
-
-	// Play sound using ffplay in background (suppress output)
-	go func() {
-		cmd := exec.Command("ffplay", "-nodisp", "-autoexit", "-v", "quiet", sp.wakeWordSound)
-		err := cmd.Run()
-		if err != nil {
-			logger.WithError(err).Error("🔊 Failed to play wake word sound")
-		}
-	}()
-}
-
-// ProcessStream processes the audio stream
-func (sp *SpeechProcessor) ProcessStream(audioSource string) error {
-	stream, err := sp.audioCapture.StartCapture(audioSource)
-	if err != nil {
-		return fmt.Errorf("failed to start audio capture: %w", err)
-	}
-	defer stream.Close()
-
-	chunk := make([]byte, readChunkSize)
-	silenceThresholdSamples := (silenceDurationMs * sampleRate) / 1000
-	minSpeechSamples := (minSpeechDurationMs * sampleRate) / 1000
-
-	if sp.wakeWordEnabled {
-		fmt.Printf("🔍 Listening for wake word '%s'...\n", sp.wakeWord)
-	} else {
-		fmt.Println("🔴 Processing audio stream...")
-	}
-
-	for {
-		n, err := stream.Read(chunk)
-		if err != nil {
-			logger.WithError(err).Error("Error reading audio stream")
-			break
-		}
-
-		// Convert bytes to float32 samples
-		samples := sp.audioProcessor.ProcessBytes(chunk[:n])
-
-		for _, sample := range samples {
-			// Handle wake word detection
-			if sp.wakeWordEnabled {
-				sp.wakeWordBuffer = append(sp.wakeWordBuffer, sample)
-
-				// Keep wake word buffer to reasonable size (2 seconds)
-				if len(sp.wakeWordBuffer) > sampleRate*2 {
-					// Remove oldest samples
-					copy(sp.wakeWordBuffer, sp.wakeWordBuffer[sampleRate/4:])
-					sp.wakeWordBuffer = sp.wakeWordBuffer[:len(sp.wakeWordBuffer)-sampleRate/4]
-				}
-
-				// Check for wake word every 500ms
-				if len(sp.wakeWordBuffer)%(sampleRate/2) == 0 {
-					if sp.detectWakeWord() {
-						fmt.Printf("🎯 Wake word '%s' detected! Activating listening...\n", sp.wakeWord)
-						// Play wake word sound
-						sp.playWakeWordSound()
-						sp.listeningActive = true
-						sp.resetWakeWordBuffer()
-						// Start a timer to deactivate listening after 30 seconds of inactivity
-						go sp.startListeningTimeout()
-					}
-				}
-
-				// If not actively listening, skip regular processing
-				if !sp.listeningActive {
-					continue
-				}
-			}
-
-			sp.audioBuffer = append(sp.audioBuffer, sample)
-
-			// Process sample with VAD
-			sp.vadDetector.ProcessSample(sample)
-
-			// Check if we should transcribe (silence detected after speech)
-			if sp.vadDetector.IsSpeaking() &&
-				sp.vadDetector.GetSilenceDuration() >= silenceThresholdSamples {
-
-				if len(sp.audioBuffer) >= minSpeechSamples {
-					sp.transcribeAndOutput()
-				}
+	sampleRate    = assistant.SampleRate
+	readChunkSize = assistant.ReadChunkSize
 
-				sp.resetForNextPhrase()
-			}
-		}
-
-		// Prevent buffer overflow
-		if len(sp.audioBuffer) >= sp.maxBufferSize {
-			logger.Warn("⚠️  Max buffer reached, processing...")
-			sp.transcribeAndOutput()
-			sp.resetForNextPhrase()
-		}
-	}
-
-	return nil
-}
-
-// transcribeAndOutput transcribes current buffer and outputs result
-func (sp *SpeechProcessor) transcribeAndOutput() {
-	logger.Debugf("📈 Processing %d samples (%.2f seconds)",
-		len(sp.audioBuffer), float64(len(sp.audioBuffer))/float64(sampleRate))
-
-	result, err := sp.whisperService.Transcribe(sp.audioBuffer, sp.language)
-	if err != nil {
-		logger.WithError(err).Error("Failed to transcribe")
-		return
-	}
-
-	if result.Text != "" {
-		timestamp := time.Now().Format("15:04:05")
-
-		// Clean up the text
-		cleanText := strings.TrimSpace(result.Text)
-
-		fmt.Printf("[%s] 🎤 %s\n", timestamp, cleanText)
-
-		// Send to AI if enabled and text is meaningful
-		if sp.aiEnabled && len(cleanText) > 3 {
-			sp.processWithAI(cleanText)
-		}
-	}
-}
-
-// processWithAI sends the transcribed text to the AI service
-func (sp *SpeechProcessor) processWithAI(text string) {
-	// Add user message to conversation
-	userMsg := ai.Message{
-		Role:    "user",
-		Content: text,
-	}
-	sp.conversation.AddMessage(userMsg)
-
-	// Prepare chat request
-	request := ai.ChatRequest{
-		Messages: sp.conversation.GetMessages(),
-		Model:    "", // Will be set by the service
-	}
-
-	// Send to AI
-	response, err := sp.aiService.Chat(request)
-	if err != nil {
-		logger.WithError(err).Error("❌ AI Error")
-		return
-	}
-
-	if response.Error != "" {
-		logger.WithField("error", response.Error).Error("❌ AI Response Error")
-		return
-	}
-
-	// Validate response content
-	if response.Message.Content == "" {
-		logger.Warn("⚠️  Warning: AI returned empty response")
-		return
-	}
-
-	// Add AI response to conversation
-	sp.conversation.AddMessage(response.Message)
+	// Time left to pending transcriptions on shutdown before aborting them
+	shutdownTimeout = 30 * time.Second
+	// Time left to an aborted pipeline to return before exiting anyway
+	abortTimeout = 5 * time.Second
+)
 
-	// Display AI response
-	timestamp := time.Now().Format("15:04:05")
-	cleanContent := strings.TrimSpace(response.Message.Content)
+// takeover stops the running instance instead of refusing to start
+var takeover bool
 
-	fmt.Printf("[%s] 🤖 %s\n", timestamp, cleanContent)
-} // resetForNextPhrase resets state for next phrase
-func (sp *SpeechProcessor) resetForNextPhrase() {
-	sp.audioBuffer = sp.audioBuffer[:0]
-	sp.vadDetector.Reset()
-}
-
-// Close closes all resources
-func (sp *SpeechProcessor) Close() error {
-	if err := sp.audioCapture.Stop(); err != nil {
-		logger.WithError(err).Error("Error stopping audio capture")
-	}
-	return sp.whisperService.Close()
-}
+// modelFlag is set when --model was given, which auto_model never overrides
+var modelFlag bool
 
 func main() {
 	// Load configuration
@@ -330,9 +73,11 @@ func main() {
 		logger.WithError(err).Fatal("Failed to load configuration")
 	}
 
-	// Initialize logger
+	// Initialize logger, again once flags are parsed
 	logger.InitLogger(cfg.LogLevel)
 
+	var quiet bool
+
 	var rootCmd = &cobra.Command{
 		Use:   "nrz-ai",
 		Short: "Real-time Speech-to-Text with AI conversation",
@@ -345,26 +90,94 @@ Features:
   • Real-time French/multilingual speech transcription  
   • Optional AI conversation with Ollama integration
   • Configurable models and audio sources`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Resolve flags > NRZ_AI_* environment > config file > defaults,
+			// also for config reloads
+			if err := config.BindFlags(cmd.Flags(), config.FlagKeys); err != nil {
+				return err
+			}
+			if err := config.Reload(cfg); err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			logger.InitLogger(cfg.LogLevel)
+			audio.SetFFmpegPath(cfg.FFmpegPath)
+			modelFlag = cmd.Flags().Changed("model")
+			applyAutoModel(cfg)
+			if quiet {
+				cfg.OutputFormat = string(output.FormatPlain)
+				cfg.Outputs = quietOutputs(cfg.Outputs)
+			}
+			if cfg.Privacy {
+				printPrivacyBanner(applyPrivacy(cfg))
+			}
+			return nil
+		},
 		Run: func(cmd *cobra.Command, args []string) {
-			runApp(*cfg)
+			if status := runApp(*cfg, ""); status != 0 {
+				os.Exit(status)
+			}
 		},
 	}
 
 	// Audio & Speech flags
 	rootCmd.PersistentFlags().StringVarP(&cfg.WhisperModel, "model", "m",
 		cfg.WhisperModel, "Path to Whisper model file")
+	rootCmd.PersistentFlags().BoolVar(&cfg.AutoModel, "auto-model",
+		cfg.AutoModel, "Use the largest installed Whisper model running in real time on this hardware")
 	rootCmd.PersistentFlags().StringVarP(&cfg.Language, "language", "l",
 		cfg.Language, "Language code (fr, en, es, etc.)")
+	rootCmd.PersistentFlags().IntVar(&cfg.LanguageSwitch, "language-switch",
+		cfg.LanguageSwitch, "With language auto, utterances in a row switching the reply language (0 = never)")
 	rootCmd.PersistentFlags().StringVarP(&cfg.AudioSource, "audio-source", "a",
-		cfg.AudioSource, "Audio source (PulseAudio device name)")
+		cfg.AudioSource, "Audio source (see nrz-ai audio devices, or pipe:<path> / unix:<path> for raw PCM)")
+
+	// Whisper decoding flags
+	rootCmd.PersistentFlags().IntVar(&cfg.BeamSize, "beam-size",
+		cfg.BeamSize, "Whisper beam size (0 = whisper default)")
+	rootCmd.PersistentFlags().Float32Var(&cfg.Temperature, "temperature",
+		cfg.Temperature, "Whisper sampling temperature")
+	rootCmd.PersistentFlags().Float32Var(&cfg.EntropyThreshold, "entropy-threshold",
+		cfg.EntropyThreshold, "Whisper entropy threshold for decoder fallback")
+	rootCmd.PersistentFlags().UintVar(&cfg.MaxSegmentLength, "max-segment-length",
+		cfg.MaxSegmentLength, "Maximum whisper segment length in characters (0 = no limit)")
+
+	// Voice activity detection flags
+	rootCmd.PersistentFlags().Float32Var(&cfg.VADThreshold, "vad-threshold",
+		cfg.VADThreshold, "Minimum speech RMS level (the calibrated threshold is 3x the noise floor)")
+	rootCmd.PersistentFlags().IntVar(&cfg.VADSilenceMs, "vad-silence-ms",
+		cfg.VADSilenceMs, "Silence ending an utterance, in milliseconds")
+	rootCmd.PersistentFlags().IntVar(&cfg.VADMinSpeechMs, "vad-min-speech-ms",
+		cfg.VADMinSpeechMs, "Speech needed to start an utterance, in milliseconds")
+	rootCmd.PersistentFlags().IntVar(&cfg.VADMaxSpeechS, "vad-max-speech-s",
+		cfg.VADMaxSpeechS, "Longest utterance, in seconds, split at a pause beyond")
+
+	// Speaker diarization flags
+	rootCmd.PersistentFlags().BoolVar(&cfg.DiarizationEnabled, "diarize",
+		cfg.DiarizationEnabled, "Label transcript segments with speakers (Speaker 1, Speaker 2, ...)")
+	rootCmd.PersistentFlags().Float32Var(&cfg.DiarizationThreshold, "diarization-threshold",
+		cfg.DiarizationThreshold, "Voice similarity needed to match a known speaker (0-1)")
+	rootCmd.PersistentFlags().IntVar(&cfg.DiarizationMaxSpeakers, "max-speakers",
+		cfg.DiarizationMaxSpeakers, "Maximum number of distinct speakers")
+	rootCmd.PersistentFlags().BoolVar(&cfg.VoicesEnabled, "voices",
+		cfg.VoicesEnabled, "Identify enrolled speakers and keep a conversation per speaker")
+
+	// Redaction flags
+	rootCmd.PersistentFlags().BoolVar(&cfg.RedactEnabled, "redact",
+		cfg.RedactEnabled, "Mask emails, phone and card numbers in transcripts (see redact_rules)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.BlocklistEnabled, "blocklist",
+		cfg.BlocklistEnabled, "Drop the phrases Whisper makes up from silence (see blocklist_phrases)")
 
 	// Wake Word flags
-	rootCmd.PersistentFlags().BoolVarP(&cfg.WakeWordEnabled, "wake-word", "w", 
+	rootCmd.PersistentFlags().BoolVarP(&cfg.WakeWordEnabled, "wake-word", "w",
 		cfg.WakeWordEnabled, "Enable wake word detection (requires saying wake word before listening)")
 	rootCmd.PersistentFlags().StringVar(&cfg.WakeWord, "wake-word-text",
 		cfg.WakeWord, "Wake word to activate listening")
 	rootCmd.PersistentFlags().StringVar(&cfg.WakeWordSound, "wake-word-sound",
-		cfg.WakeWordSound, "Sound file to play when wake word is detected")
+		cfg.WakeWordSound, "Sound file played when the wake word is detected instead of the theme's")
+	rootCmd.PersistentFlags().StringVar(&cfg.SoundTheme, "sound-theme",
+		cfg.SoundTheme, "Audio feedback: default (generated tones), off, or a directory of sounds")
+	rootCmd.PersistentFlags().IntVar(&cfg.GPIOPin, "gpio-pin",
+		cfg.GPIOPin, "GPIO pin of a push-button activating listening like the wake word (-1 = disabled)")
 
 	// AI flags
 	rootCmd.PersistentFlags().BoolVar(&cfg.AIEnabled, "ai",
@@ -375,27 +188,250 @@ Features:
 		cfg.OllamaModel, "Ollama model to use")
 	rootCmd.PersistentFlags().StringVar(&cfg.SystemPrompt, "system-prompt",
 		cfg.SystemPrompt, "AI system prompt")
-	rootCmd.PersistentFlags().IntVar(&cfg.MaxHistory, "max-history", 
+	rootCmd.PersistentFlags().DurationVar(&cfg.AIWatchInterval, "ai-watch-interval",
+		cfg.AIWatchInterval, "How often an unreachable AI backend is retried (0 = give up at startup)")
+	rootCmd.PersistentFlags().Float32Var(&cfg.AIMinConfidence, "min-confidence",
+		cfg.AIMinConfidence, "Mean Whisper confidence needed to answer a transcript (0-1, 0 = always answer)")
+	rootCmd.PersistentFlags().IntVar(&cfg.AIMinWords, "min-words",
+		cfg.AIMinWords, "Words needed to answer a transcript")
+	rootCmd.PersistentFlags().BoolVar(&cfg.AIQuestionsOnly, "questions-only",
+		cfg.AIQuestionsOnly, "Only send the AI transcripts ending with a question mark")
+	rootCmd.PersistentFlags().StringSliceVar(&cfg.AIPrefixes, "ai-prefix",
+		cfg.AIPrefixes, "Only send the AI transcripts starting with one of these words (e.g. Jack)")
+	rootCmd.PersistentFlags().StringVar(&cfg.AIName, "ai-name",
+		cfg.AIName, "Only send the AI transcripts starting with this name, without it (e.g. Jack)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.AIContext, "ai-context",
+		cfg.AIContext, "Send the current date, time and language with every AI request")
+	rootCmd.PersistentFlags().StringVar(&cfg.AILocation, "location",
+		cfg.AILocation, "Location told to the AI, e.g. \"Lyon, France\"")
+	rootCmd.PersistentFlags().IntVar(&cfg.MaxHistory, "max-history",
 		cfg.MaxHistory, "Maximum conversation history to keep")
 
+	// Intent flags
+	rootCmd.PersistentFlags().BoolVar(&cfg.IntentsEnabled, "intents",
+		cfg.IntentsEnabled, "Answer matching phrases locally before the AI (works without Ollama)")
+	rootCmd.PersistentFlags().StringVar(&cfg.IntentsFile, "intents-file",
+		cfg.IntentsFile, "Intents YAML file (default ~/.config/nrz-ai/intents.yaml)")
+
+	// Mode flags
+	rootCmd.PersistentFlags().StringVar(&cfg.Mode, "mode",
+		cfg.Mode, "Operating mode (assistant, meeting, dictation, translate)")
+	rootCmd.PersistentFlags().StringVar(&cfg.MeetingDir, "meeting-dir",
+		cfg.MeetingDir, "Directory for meeting minutes (default $XDG_DATA_HOME/nrz-ai/meetings)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.MeetingSummary, "meeting-summary",
+		cfg.MeetingSummary, "Generate an AI summary at the end of the meeting")
+	rootCmd.PersistentFlags().StringVar(&cfg.DictationBackend, "dictation-backend",
+		cfg.DictationBackend, "Keystroke tool for dictation mode (auto, xdotool, wtype, ydotool)")
+	rootCmd.PersistentFlags().StringVar(&cfg.TranslateTarget, "target",
+		cfg.TranslateTarget, "Language the translate mode translates into")
+	rootCmd.PersistentFlags().StringVar(&cfg.TranslateBackend, "translate-backend",
+		cfg.TranslateBackend, "Translator of the translate mode (auto, ai, whisper)")
+
+	// Home Assistant flags
+	rootCmd.PersistentFlags().StringVar(&cfg.HomeAssistantMode, "homeassistant",
+		cfg.HomeAssistantMode, "Home Assistant conversation agent: off, only or fallback (then Ollama)")
+	rootCmd.PersistentFlags().StringVar(&cfg.HomeAssistantURL, "homeassistant-url",
+		cfg.HomeAssistantURL, "Home Assistant base URL")
+
+	// MQTT flags
+	rootCmd.PersistentFlags().BoolVar(&cfg.MQTTEnabled, "mqtt",
+		cfg.MQTTEnabled, "Publish events to MQTT and listen to command topics")
+	rootCmd.PersistentFlags().StringVar(&cfg.MQTTBroker, "mqtt-broker",
+		cfg.MQTTBroker, "MQTT broker URL (tcp://, ssl://, ws://)")
+
+	// Matrix flags
+	rootCmd.PersistentFlags().BoolVar(&cfg.MatrixEnabled, "matrix",
+		cfg.MatrixEnabled, "Post transcripts and AI answers to a Matrix room and answer its messages")
+	rootCmd.PersistentFlags().StringVar(&cfg.MatrixRoom, "matrix-room",
+		cfg.MatrixRoom, "Matrix room ID or alias joined by the bot")
+
+	// Telegram flags
+	rootCmd.PersistentFlags().BoolVar(&cfg.TelegramEnabled, "telegram",
+		cfg.TelegramEnabled, "Answer the text and voice messages sent to the Telegram bot")
+
+	// D-Bus flags
+	rootCmd.PersistentFlags().BoolVar(&cfg.DBusEnabled, "dbus",
+		cfg.DBusEnabled, "Expose the org.nrz.AI service on the D-Bus session bus")
+
+	// Notification flags
+	rootCmd.PersistentFlags().BoolVar(&cfg.NotifyEnabled, "notify",
+		cfg.NotifyEnabled, "Show desktop notifications for wake words, transcripts and AI responses")
+
+	// Control socket flags
+	rootCmd.PersistentFlags().BoolVar(&cfg.ControlEnabled, "control",
+		cfg.ControlEnabled, "Accept nrz-ai ctl commands on a Unix socket")
+	rootCmd.PersistentFlags().StringVar(&cfg.ControlSocket, "control-socket",
+		cfg.ControlSocket, "Control socket path (default $XDG_RUNTIME_DIR/nrz-ai.sock)")
+
+	// Webhook flags
+	rootCmd.PersistentFlags().StringSliceVar(&cfg.WebhookURLs, "webhook-url",
+		cfg.WebhookURLs, "URL receiving JSON POSTs for transcripts, wake words and AI responses (repeatable)")
+
+	// Hook flags
+	rootCmd.PersistentFlags().StringVar(&cfg.HookOnWake, "on-wake",
+		cfg.HookOnWake, "Shell command run on wake word activations, with the event as JSON on stdin")
+	rootCmd.PersistentFlags().StringVar(&cfg.HookOnTranscript, "on-transcript",
+		cfg.HookOnTranscript, "Shell command run on every transcript, with the event as JSON on stdin")
+	rootCmd.PersistentFlags().StringVar(&cfg.HookOnAIResponse, "on-ai-response",
+		cfg.HookOnAIResponse, "Shell command run on every AI answer, with the event as JSON on stdin")
+	rootCmd.PersistentFlags().StringVar(&cfg.HookOnError, "on-error",
+		cfg.HookOnError, "Shell command run on pipeline errors, with the event as JSON on stdin")
+
+	// Output flags
+	rootCmd.PersistentFlags().StringVar(&cfg.OutputFormat, "output",
+		cfg.OutputFormat, "Live output format: text, json (JSON Lines events on stdout) or plain (transcripts only)")
+	rootCmd.PersistentFlags().StringSliceVar(&cfg.Outputs, "outputs",
+		cfg.Outputs, "Live outputs receiving the events, replacing --output: console, json, plain, jsonl_file[:path], text_file[:path]")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q",
+		false, "Print only transcripts and AI answers, one per line (same as --output plain)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.TUI, "tui",
+		cfg.TUI, "Full-screen dashboard with level meter, state and transcript")
+	rootCmd.PersistentFlags().StringVar(&cfg.TimestampFormat, "timestamp-format",
+		cfg.TimestampFormat, "Timestamp layout of the text and JSON outputs: a Go layout, iso8601 or elapsed")
+	rootCmd.PersistentFlags().StringVar(&cfg.TimestampTimezone, "timezone",
+		cfg.TimestampTimezone, "Time zone of the timestamps, e.g. UTC or Europe/Paris (default local)")
+
+	// Captions flags
+	rootCmd.PersistentFlags().StringVar(&cfg.CaptionsFile, "captions",
+		cfg.CaptionsFile, "Write live captions to a .srt or .vtt file")
+
+	// Clipboard flags
+	rootCmd.PersistentFlags().StringVar(&cfg.ClipboardTarget, "clipboard",
+		cfg.ClipboardTarget, "Copy to clipboard: off, transcript or ai")
+	rootCmd.PersistentFlags().StringVar(&cfg.ClipboardBackend, "clipboard-backend",
+		cfg.ClipboardBackend, "Clipboard tool (auto, wl-copy, xclip, xsel)")
+
+	// Profile flags
+	rootCmd.PersistentFlags().StringVarP(&cfg.Profile, "profile", "p",
+		cfg.Profile, "Config file profile overriding the base settings (e.g. meeting, assistant, dictation)")
+
 	// Advanced flags
 	rootCmd.PersistentFlags().StringVar(&cfg.LogLevel, "log-level",
 		cfg.LogLevel, "Log level (debug, info, warn, error)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.Daemon, "daemon",
+		cfg.Daemon, "Run as a systemd service: plain journal logs, readiness and watchdog notifications")
+	rootCmd.PersistentFlags().BoolVar(&cfg.Privacy, "privacy",
+		cfg.Privacy, "Never write audio, transcripts or conversations to disk")
+	rootCmd.PersistentFlags().BoolVar(&cfg.LogFile, "log-file",
+		cfg.LogFile, "Also write logs to a rotated file in the log directory")
+	rootCmd.PersistentFlags().BoolVar(&cfg.TranscriptLog, "transcript-log",
+		cfg.TranscriptLog, "Append transcripts and AI answers to a rotated file in the log directory")
+	rootCmd.PersistentFlags().StringVar(&cfg.LogDir, "log-dir",
+		cfg.LogDir, "Log directory (default $XDG_STATE_HOME/nrz-ai)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.ArchiveEnabled, "archive",
+		cfg.ArchiveEnabled, "Archive the transcripts and AI answers of the session (see nrz-ai sessions)")
+	rootCmd.PersistentFlags().StringVar(&cfg.DailyNotesPath, "daily-notes",
+		cfg.DailyNotesPath, "Append transcripts and AI answers to daily Markdown notes (e.g. ~/notes/{date}.md)")
+	rootCmd.PersistentFlags().IntVar(&cfg.TranscriptionQueueSize, "queue-size",
+		cfg.TranscriptionQueueSize, "Maximum number of speech segments waiting for transcription")
+	rootCmd.PersistentFlags().StringVar(&cfg.TranscriptionQueuePolicy, "queue-policy",
+		cfg.TranscriptionQueuePolicy, "Policy when the transcription queue is full (block, drop-oldest, merge, fallback-model)")
+	rootCmd.PersistentFlags().StringVar(&cfg.TranscriptionFallback, "fallback-model",
+		cfg.TranscriptionFallback, "Smaller Whisper model used by the fallback-model queue policy")
+	rootCmd.PersistentFlags().DurationVar(&cfg.TranscriptionTimeout, "transcription-timeout",
+		cfg.TranscriptionTimeout, "Maximum time to transcribe a single utterance (0 = no limit)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.TranscriptionPartials, "partials",
+		cfg.TranscriptionPartials, "Show the segments of an utterance as Whisper decodes them")
+	rootCmd.PersistentFlags().DurationVar(&cfg.AudioStatsInterval, "stats-interval",
+		cfg.AudioStatsInterval, "Interval of the audio counters logged at debug level (0 = never)")
+	rootCmd.PersistentFlags().DurationVar(&cfg.CaptureStallTimeout, "stall-timeout",
+		cfg.CaptureStallTimeout, "Restart the audio capture after this long without audio (0 = never)")
+	rootCmd.PersistentFlags().BoolVar(&takeover, "takeover",
+		false, "Stop the running nrz-ai instance and take its place")
 
 	// Add subcommands
-	rootCmd.AddCommand(createListModelsCmd())
-	rootCmd.AddCommand(createTestAudioCmd())
+	rootCmd.AddCommand(createListModelsCmd(cfg))
+	rootCmd.AddCommand(createTestAudioCmd(cfg))
+	rootCmd.AddCommand(createAudioCmd(cfg))
+	rootCmd.AddCommand(createVoicesCmd(cfg))
+	rootCmd.AddCommand(createSessionsCmd(cfg))
+	rootCmd.AddCommand(createHistoryCmd(cfg))
+	rootCmd.AddCommand(createTranscribeCmd(cfg))
+	rootCmd.AddCommand(createServeCmd(cfg))
+	rootCmd.AddCommand(createCtlCmd(cfg))
+	rootCmd.AddCommand(createChatCmd(cfg))
+	rootCmd.AddCommand(createConversationCmd(cfg))
+	rootCmd.AddCommand(createBenchCmd(cfg))
+	rootCmd.AddCommand(createDoctorCmd(cfg))
+	rootCmd.AddCommand(createInitCmd(cfg))
+	rootCmd.AddCommand(createRecordCmd(cfg))
+	rootCmd.AddCommand(createReplayCmd(cfg))
+	rootCmd.AddCommand(createInstallServiceCmd())
+	rootCmd.AddCommand(createPathsCmd(cfg))
 
 	if err := rootCmd.Execute(); err != nil {
 		logger.WithError(err).Fatal("Failed to execute command")
 	}
 }
 
-func runApp(cfg config.Config) {
+// runApp runs the live microphone pipeline. A non-empty serveAddr also
+// serves the HTTP API, streaming live events. It returns the process exit
+// status once everything is flushed and closed.
+func runApp(cfg config.Config, serveAddr string) int {
+	// Only one instance owns the microphone, released by the kernel on exit
+	lock := lockInstance()
+	defer lock.Release()
+
+	// Config reloads are compared to the settings as loaded, before the
+	// mode and AI availability adjust them
+	loadedCfg := cfg
+
+	mode, err := ParseMode(cfg.Mode)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid mode")
+	}
+
+	if mode == ModeMeeting || mode == ModeDictation || mode == ModeTranslate {
+		// Meeting, dictation and translate modes transcribe everything and never chat
+		cfg.WakeWordEnabled = false
+		cfg.AIEnabled = false
+		cfg.HomeAssistantMode = "off"
+	}
+
+	timestamps := newTimestamps(cfg)
+	outputs := openOutputs(cfg, output.NewSessionID(), timestamps)
+	defer outputs.Close()
+
+	if cfg.Daemon {
+		// journald timestamps every line and does not render colors
+		logger.SetJournalFormat()
+	}
+
+	// The dashboard owns the terminal, console lines are dropped and logs
+	// go to its log pane
+	var terminal *os.File
+	if cfg.TUI {
+		if outputs.stdout != "" {
+			logger.WithField("output", outputs.stdout).Fatal("--tui cannot be combined with the json or plain output")
+		}
+		devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to open the null device")
+		}
+		terminal = os.Stdout
+		os.Stdout = devNull
+		logger.SetJournalFormat()
+	}
+
 	fmt.Printf("🎙️  NRZ-AI - Real-time Speech-to-Text\n")
+
+	// Extra locales and overridden replies, e.g. ~/.config/nrz-ai/locales/es.yaml
+	if err := i18n.LoadDir(filepath.Join(config.Dir(), "locales")); err != nil {
+		logger.WithError(err).Warn("⚠️  Failed to load the message catalogs")
+	}
+	if !i18n.Supported(cfg.Language) {
+		logger.WithField("language", cfg.Language).Info("No message catalog for the language, replying in English")
+	}
+
+	logs := openLogFiles(cfg)
+	defer logs.Close()
 	fmt.Printf("📦 Whisper model: %s\n", cfg.WhisperModel)
 	fmt.Printf("🎤 Audio source: %s\n", cfg.AudioSource)
 	fmt.Printf("🗣️  Language: %s\n", cfg.Language)
+	fmt.Printf("🧭 Mode: %s\n", mode)
+	if cfg.Profile != "" {
+		fmt.Printf("🧩 Profile: %s\n", cfg.Profile)
+	}
 
 	if cfg.WakeWordEnabled {
 		fmt.Printf("🔍 Wake word: %s (listening mode)\n", cfg.WakeWord)
@@ -404,38 +440,203 @@ func runApp(cfg config.Config) {
 	if cfg.AIEnabled {
 		fmt.Printf("🤖 AI Service: Ollama (%s)\n", cfg.OllamaURL)
 		fmt.Printf("🧠 Model: %s\n", cfg.OllamaModel)
+		if cfg.AIName != "" {
+			fmt.Printf("📛 Answering when addressed as %s\n", cfg.AIName)
+		}
 	}
 
 	// Create components using our architecture
-	audioCapture := audio.NewFFmpegCapture()
-	audioProcessor := audio.NewProcessor()
-	vadDetector := vad.NewRMSDetector()
-	whisperService := whisper.NewService()
+	whisperService := newWhisperService(cfg)
+
+	// Create AI components if enabled, an unreachable backend is retried
+	// in the background when watched
+	watchAI := cfg.AIWatchInterval > 0
+	aiService, conversation, aiAvailable := connectAI(&cfg, watchAI)
+	if aiService != nil {
+		defer aiService.Close()
+	}
 
-	// Create AI components if enabled
-	var aiService ai.AIService
-	var conversation ai.ConversationManager
+	// Create the assistant
+	processor, err := assistant.New(assistant.Options{
+		Whisper:      whisperService,
+		Capture:      newCapture(cfg),
+		AI:           aiService,
+		Conversation: conversation,
+		WakeWord:     enabledWakeWord(cfg),
+	})
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to create the assistant")
+	}
 
-	if cfg.AIEnabled {
-		aiService = ai.NewOllamaService(cfg.OllamaURL, cfg.OllamaModel)
-		conversation = ai.NewConversation(cfg.MaxHistory)
+	var dashboard *tui.Dashboard
+	if terminal != nil {
+		info := tui.Info{
+			Model:       filepath.Base(cfg.WhisperModel),
+			Language:    cfg.Language,
+			AudioSource: cfg.AudioSource,
+			Timestamps:  timestamps,
+		}
+		if cfg.WakeWordEnabled {
+			info.WakeWord = cfg.WakeWord
+		}
+		dashboard = tui.NewDashboard(info, processor, terminal)
+		processor.AddEmitter(dashboard)
+		processor.SetLevelMeter(dashboard.Level)
+		logger.SetOutput(dashboard.LogWriter())
+		go func() {
+			if err := dashboard.Run(); err != nil {
+				logger.SetOutput(os.Stderr)
+				logger.WithError(err).Error("❌ Dashboard failed")
+			}
+		}()
+	}
 
-		// Check if Ollama is available
-		if !aiService.IsAvailable() {
-			logger.Warnf("⚠️  Warning: Ollama service not available at %s", cfg.OllamaURL)
-			logger.Warn("   Make sure Ollama is running: ollama serve")
-			logger.Warnf("   And the model is available: ollama pull %s", cfg.OllamaModel)
-			cfg.AIEnabled = false
-			aiService = nil
-			conversation = nil
+	queuePolicy, err := assistant.ParseQueuePolicy(cfg.TranscriptionQueuePolicy)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid transcription queue policy")
+	}
+	if queuePolicy == assistant.QueuePolicyFallbackModel && cfg.TranscriptionFallback == "" {
+		logger.WithField("policy", queuePolicy).Fatal("The queue policy requires --fallback-model")
+	}
+	processor.SetTranscriptionQueue(cfg.TranscriptionQueueSize, queuePolicy)
+	processor.SetFallbackModel(cfg.TranscriptionFallback)
+	processor.SetTranscriptionTimeout(cfg.TranscriptionTimeout)
+	processor.SetPartialTranscripts(cfg.TranscriptionPartials)
+	processor.SetStatsInterval(cfg.AudioStatsInterval)
+	if !audio.IsPipeSource(cfg.AudioSource) {
+		// A pipe writer may legitimately pause, only ffmpeg is watched
+		processor.SetCaptureWatchdog(cfg.CaptureStallTimeout)
+	}
+	processor.SetTimestamps(timestamps)
+	if logs.transcripts != nil {
+		processor.AddEmitter(logs.transcripts)
+	}
+	if cfg.ArchiveEnabled {
+		start := time.Now()
+		sessionArchive, err := archive.Create(cfg.ArchiveDir, archive.Session{
+			ID:       archive.NewID(start),
+			Start:    start,
+			Mode:     string(mode),
+			Language: cfg.Language,
+		})
+		if err != nil {
+			logger.WithError(err).Warn("⚠️  Session archive disabled")
 		} else {
-			conversation.SetSystemPrompt(cfg.SystemPrompt)
-			fmt.Printf("✅ AI service connected successfully\n")
+			defer sessionArchive.Close()
+			processor.AddEmitter(sessionArchive)
+			fmt.Printf("🗄️  Session archive: %s\n", sessionArchive.Path())
+		}
+	}
+	if cfg.DailyNotesPath != "" {
+		dailyNotes, err := notes.NewDailyNotes(cfg.DailyNotesPath)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to set up daily notes")
+		}
+		processor.AddEmitter(dailyNotes)
+		fmt.Printf("📓 Daily notes: %s\n", dailyNotes.Path(time.Now()))
+	}
+	outputs.attach(processor)
+
+	var session *meetingSession
+	if mode == ModeMeeting {
+		session, err = startMeetingSession(cfg)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to start meeting mode")
+		}
+		processor.SetMeetingRecorder(session.recorder)
+		fmt.Printf("📝 Meeting minutes: %s\n", session.recorder.Path())
+	}
+	if mode == ModeDictation {
+		injector, err := dictation.NewInjector(cfg.DictationBackend)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to start dictation mode")
+		}
+		processor.SetDictation(dictation.NewDictation(injector, cfg.Language))
+		fmt.Printf("⌨️  Dictation backend: %s\n", injector.Name())
+	}
+	if mode == ModeTranslate {
+		translator, backend, err := newTranslator(cfg, whisperService)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to start translate mode")
+		}
+		processor.SetTranslator(translator)
+		fmt.Printf("🌍 Translating into %s with %s\n", translator.Target(), backend)
+	}
+	if mode == ModeAssistant && (cfg.AIEnabled || cfg.IntentsEnabled) {
+		commands, err := newCommandRouter(processor, cfg.Language)
+		if err != nil {
+			logger.WithError(err).Fatal("Invalid voice commands")
+		}
+		processor.SetVoiceCommands(commands)
+	}
+	if mode == ModeAssistant && cfg.IntentsEnabled {
+		timerManager, err := timers.NewManager(filepath.Join(config.StateDir(), "timers.json"))
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to load pending timers")
+		}
+		router, source, err := newIntentRouter(cfg, timerManager)
+		if err != nil {
+			logger.WithError(err).Fatal("Invalid intents")
+		}
+		processor.SetIntents(router)
+		fmt.Printf("🧩 Intents: %d (%s)\n", router.Intents(), source)
+
+		timerManager.Start(func(timer timers.Timer) {
+			processor.Announce(timer.Message(processor.Language()))
+		})
+		defer timerManager.Close()
+		if pending := len(timerManager.Pending()); pending > 0 {
+			fmt.Printf("⏰ Pending timers: %d\n", pending)
+		}
+	}
+	if cfg.CaptionsFile != "" {
+		captions, err := transcript.NewCaptionWriter(cfg.CaptionsFile)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to create captions file")
 		}
+		defer captions.Close()
+		// Cue times are offsets, tell the wall clock time they count from
+		captions.Note("Started " + timestamps.In(time.Now()).Format(time.RFC3339))
+		processor.SetCaptionWriter(captions)
+		fmt.Printf("💬 Live captions: %s\n", captions.Path())
 	}
 
-	// Create speech processor
-	processor := NewSpeechProcessor(audioCapture, audioProcessor, vadDetector, whisperService, aiService, conversation, cfg.WakeWordEnabled, cfg.WakeWord, cfg.WakeWordSound)
+	clipboardTarget, err := clipboard.ParseTarget(cfg.ClipboardTarget)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid clipboard target")
+	}
+	if clipboardTarget == clipboard.TargetAI && !cfg.AIEnabled {
+		logger.Warn("⚠️  Clipboard target 'ai' requires AI, nothing will be copied")
+	}
+	if clipboardTarget != clipboard.TargetOff {
+		cb, err := clipboard.NewClipboard(cfg.ClipboardBackend)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to set up clipboard output")
+		}
+		processor.SetClipboard(cb, clipboardTarget)
+		fmt.Printf("📋 Clipboard: %s (%s)\n", clipboardTarget, cb.Name())
+	}
+	if cfg.DiarizationEnabled {
+		processor.SetDiarizer(diarization.NewClusterDiarizer(newDiarizationConfig(cfg)))
+	}
+	if cfg.VoicesEnabled {
+		store := openVoices(cfg)
+		processor.SetSpeakers(store, cfg.SystemPrompt, cfg.MaxHistory)
+		fmt.Printf("🗣️  Speaker identification: %d enrolled voice(s), others are guests\n", len(store.Voices()))
+	}
+	redactor := newRedactor(cfg)
+	processor.SetRedactor(redactor)
+	processor.SetBlocklist(newBlocklist(cfg))
+	processor.SetAnswerGate(float64(cfg.AIMinConfidence), cfg.AIMinWords)
+	processor.SetAITrigger(newAITrigger(cfg))
+	processor.SetLanguageSwitch(cfg.LanguageSwitch, cfg.SystemPrompts)
+	if cfg.Language == "auto" && cfg.LanguageSwitch > 0 {
+		fmt.Printf("🌐 Language switching: after %d utterance(s) in another language\n", cfg.LanguageSwitch)
+	}
+	processor.SetVADConfig(newVADConfig(cfg))
+	if cfg.VADMaxSpeechS > 0 {
+		processor.SetMaxUtterance(time.Duration(cfg.VADMaxSpeechS) * time.Second)
+	}
 
 	// Initialize
 	if err := processor.Initialize(cfg.WhisperModel, cfg.AudioSource, cfg.Language); err != nil {
@@ -443,34 +644,706 @@ func runApp(cfg config.Config) {
 	}
 	defer processor.Close()
 
-	// Handle shutdown signal
-	sigChan := make(chan os.Signal, 1)
+	if aiService != nil && watchAI {
+		// Answer again once the backend comes back, pause while it is away
+		processor.SetAIAvailable(aiAvailable)
+		aiWatcher := ai.NewWatcher(aiService, cfg.AIWatchInterval, processor.SetAIAvailable)
+		aiWatcher.Start(aiAvailable)
+		defer aiWatcher.Close()
+	}
+
+	if serveAddr != "" {
+		srv := server.NewServer(whisperService, audio.NewFFmpegDecoder(), cfg.Language, cfg.WhisperModel)
+		if aiService != nil {
+			srv.SetAI(aiService, conversation)
+			srv.SetAnswerer(processor)
+		}
+		srv.SetLive(true)
+		srv.SetRedactor(redactor)
+		srv.SetBlocklist(newBlocklist(cfg))
+		srv.SetCapturing(processor.Capturing)
+		secureServer(srv, cfg)
+		if cfg.WakeWordEnabled {
+			srv.SetActivation(func() { processor.ActivateBy(assistant.ActivatedByHTTP) })
+		}
+		processor.AddEmitter(srv)
+		go startServer(context.Background(), srv, serveAddr, nil)
+	}
+
+	if cfg.GPIOPin >= 0 {
+		if !cfg.WakeWordEnabled {
+			logger.Warn("⚠️  The GPIO button activates listening, it requires the wake word")
+		} else {
+			button := gpio.NewButton(cfg.GPIOPin, cfg.GPIOActiveLow, func() {
+				processor.ActivateBy(assistant.ActivatedByGPIO)
+			})
+			if err := button.Start(); err != nil {
+				logger.WithError(err).Fatal("Failed to set up the GPIO button")
+			}
+			defer button.Close()
+			fmt.Printf("🔘 GPIO button: pin %d\n", cfg.GPIOPin)
+		}
+	}
+
+	if cfg.MQTTEnabled {
+		mqttClient, err := mqtt.NewClient(newMQTTConfig(cfg))
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to set up MQTT")
+		}
+		mqttClient.SetCommandHandler(processor)
+		// The client keeps retrying in the background if the broker is down
+		if err := mqttClient.Connect(); err != nil {
+			logger.WithError(err).Warnf("⚠️  MQTT broker %s not reachable yet", cfg.MQTTBroker)
+		} else {
+			fmt.Printf("📡 MQTT connected to %s (topics %s/...)\n", cfg.MQTTBroker, cfg.MQTTTopicPrefix)
+		}
+		mqttClient.Emit(output.Event{Type: output.EventState, Time: time.Now(), State: string(processor.State())})
+		processor.AddEmitter(mqttClient)
+		defer mqttClient.Close()
+	}
+
+	if cfg.MatrixEnabled {
+		bot := matrix.NewBot(newMatrixConfig(cfg))
+		bot.SetCommandHandler(processor)
+		if err := bot.Start(); err != nil {
+			logger.WithError(err).Warn("⚠️  Matrix bridge disabled")
+		} else {
+			fmt.Printf("💠 Matrix: %s in %s handling %d user(s)\n", bot.UserID(), cfg.MatrixRoom, len(cfg.MatrixUsers))
+			processor.AddEmitter(bot)
+			defer bot.Close()
+		}
+	}
+
+	if cfg.TelegramEnabled {
+		bot := telegram.NewBot(newTelegramConfig(cfg), whisperService, audio.NewFFmpegDecoder(), cfg.Language)
+		if aiService != nil {
+			bot.SetAnswerer(processor)
+		}
+		bot.SetRedactor(redactor)
+		bot.SetBlocklist(newBlocklist(cfg))
+		if err := bot.Start(); err != nil {
+			logger.WithError(err).Warn("⚠️  Telegram bot disabled")
+		} else {
+			fmt.Printf("✈️  Telegram: @%s answering %d chat(s)\n", bot.Username(), len(cfg.TelegramChatIDs))
+			processor.AddEmitter(bot)
+			defer bot.Close()
+		}
+	}
+
+	if cfg.DBusEnabled {
+		dbusService := dbus.NewService(processor)
+		if err := dbusService.Start(); err != nil {
+			logger.WithError(err).Warn("⚠️  D-Bus service disabled")
+		} else {
+			fmt.Printf("🖥️  D-Bus service: %s on the session bus\n", dbus.BusName)
+			processor.AddEmitter(dbusService)
+			defer dbusService.Close()
+		}
+	}
+
+	if feedback := newSoundEmitter(cfg); feedback != nil {
+		processor.AddEmitter(feedback)
+	}
+
+	if cfg.NotifyEnabled {
+		notifyEvents, err := notify.ParseEvents(cfg.NotifyEvents)
+		if err != nil {
+			logger.WithError(err).Fatal("Invalid notification events")
+		}
+		notifier, err := notify.NewNotifier()
+		if err != nil {
+			logger.WithError(err).Warn("⚠️  Desktop notifications disabled")
+		} else {
+			processor.AddEmitter(notify.NewEmitter(notifier, notifyEvents))
+			fmt.Printf("🔔 Desktop notifications: %s\n", notifier.Name())
+		}
+	}
+
+	if cfg.ControlEnabled {
+		controlServer := control.NewServer(controlSocketPath(cfg), processor)
+		if err := controlServer.Start(); err != nil {
+			logger.WithError(err).Warn("⚠️  Control socket disabled")
+		} else {
+			fmt.Printf("🎛️  Control socket: %s\n", controlServer.Path())
+			defer controlServer.Close()
+		}
+	}
+
+	if len(cfg.WebhookURLs) > 0 {
+		webhooks := webhook.NewDispatcher(webhook.Config{
+			URLs:       cfg.WebhookURLs,
+			Secret:     cfg.WebhookSecret,
+			MaxRetries: cfg.WebhookRetries,
+		})
+		processor.AddEmitter(webhooks)
+		defer webhooks.Close()
+		fmt.Printf("🪝 Webhooks: %d URL(s)\n", len(cfg.WebhookURLs))
+	}
+
+	if hookConfig := newHookConfig(cfg); len(hookConfig.Commands()) > 0 {
+		hookRunner := hooks.NewRunner(hookConfig)
+		processor.AddEmitter(hookRunner)
+		defer hookRunner.Close()
+		fmt.Printf("🪝 Hooks: %d event(s)\n", len(hookConfig.Commands()))
+	}
+
+	// Apply live-changeable settings when the config file changes or on SIGHUP
+	reloader := newConfigReloader(loadedCfg, processor, aiService)
+	config.WatchConfig(reloader.Apply)
+	stopReload := reloader.HandleSIGHUP()
+	defer stopReload()
+	stopPauseSignals := handlePauseSignals(processor)
+	defer stopPauseSignals()
+
+	// Handle shutdown signal: capture stops and pending transcriptions are
+	// finished first, a second signal or the shutdown timeout aborts them
+	sigChan := make(chan os.Signal, 3)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
 
+	var aborted atomic.Bool
+	stopped := make(chan struct{})
 	go func() {
-		<-sigChan
+		select {
+		case <-sigChan:
+		case <-stopped:
+			return
+		}
 		fmt.Println("\n\n✅ Stopping recording")
-		processor.Close()
-		os.Exit(0)
+		systemd.Notify(systemd.Stopping)
+		processor.Stop()
+
+		select {
+		case <-sigChan:
+			logger.Warn("⚠️  Aborting pending transcriptions")
+		case <-time.After(shutdownTimeout):
+			logger.Warnf("⚠️  Pending transcriptions still running after %s, aborting", shutdownTimeout)
+		case <-stopped:
+			return
+		}
+		aborted.Store(true)
+		processor.Abort()
+
+		// Last resort when a stage ignores the cancellation
+		select {
+		case <-sigChan:
+		case <-time.After(abortTimeout):
+		case <-stopped:
+			return
+		}
+		logger.Error("❌ Pipeline did not stop, exiting")
+		os.Exit(1)
 	}()
 
 	if cfg.AIEnabled {
-		fmt.Println("💡 Tip: Speak naturally, AI will respond to your voice!")
+		fmt.Println(i18n.T(cfg.Language, "session.tip_ai"))
 	}
 
 	if cfg.WakeWordEnabled {
-		fmt.Printf("🎯 Say '%s' to activate listening, then speak normally\n", cfg.WakeWord)
+		fmt.Println(i18n.T(cfg.Language, "session.tip_wake_word", cfg.WakeWord))
 	}
 
 	fmt.Println("─────────────────────────────────────────────")
 
+	// The watchdog only pings while audio keeps flowing, so systemd restarts
+	// a process whose capture is stuck
+	notifyReady(fmt.Sprintf("Listening on %s", cfg.AudioSource))
+	watchdogDone := make(chan struct{})
+	systemd.StartWatchdog(processor.Capturing, watchdogDone)
+	defer close(watchdogDone)
+
 	// Start processing
-	if err := processor.ProcessStream(cfg.AudioSource); err != nil {
-		logger.WithError(err).Fatal("Failed to process stream")
+	err = processor.ProcessStream(cfg.AudioSource)
+	close(stopped)
+	if dashboard != nil {
+		// Give the terminal back before the final messages
+		dashboard.Quit()
+		os.Stdout = terminal
+		logger.SetOutput(terminal)
+	}
+
+	// The minutes, captions and logs are flushed even when the stream failed
+	status := 0
+	if err != nil {
+		logger.WithError(err).Error("❌ Failed to process stream")
+		status = 1
+	} else if aborted.Load() {
+		logger.Warn("⚠️  Pending transcriptions were aborted")
+		status = 1
+	}
+
+	if session != nil {
+		session.Finish()
+	}
+	return status
+}
+
+// lockInstance takes the single-instance lock, stopping the running instance
+// first with --takeover
+func lockInstance() *instance.Lock {
+	path := instance.DefaultLockPath()
+	if !takeover {
+		lock, err := instance.Acquire(path)
+		if errors.Is(err, instance.ErrRunning) {
+			logger.WithError(err).Fatal("Refusing to start, use --takeover to replace it")
+		}
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to take the instance lock")
+		}
+		return lock
+	}
+
+	logger.Info("🔁 Taking over the running instance...")
+	// The running instance exits by abortTimeout after its shutdown timeout
+	lock, err := instance.Takeover(path, shutdownTimeout+abortTimeout)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to take over the running instance")
+	}
+	return lock
+}
+
+// newVADConfig returns the VAD settings of cfg, defaults for unset values
+func newVADConfig(cfg config.Config) vad.VADConfig {
+	vadConfig := assistant.DefaultVADConfig()
+	if cfg.VADThreshold > 0 {
+		vadConfig.SilenceThreshold = cfg.VADThreshold
+	}
+	if cfg.VADSilenceMs > 0 {
+		vadConfig.SilenceDurationMs = cfg.VADSilenceMs
+	}
+	if cfg.VADMinSpeechMs > 0 {
+		vadConfig.MinSpeechDurationMs = cfg.VADMinSpeechMs
+	}
+	return vadConfig
+}
+
+// newAITrigger returns the rules of cfg a transcript follows to reach the AI
+func newAITrigger(cfg config.Config) assistant.AITrigger {
+	return assistant.AITrigger{Questions: cfg.AIQuestionsOnly, Prefixes: cfg.AIPrefixes, Name: cfg.AIName}
+}
+
+// enabledWakeWord returns the wake word of cfg, empty when disabled
+func enabledWakeWord(cfg config.Config) string {
+	if !cfg.WakeWordEnabled {
+		return ""
+	}
+	return cfg.WakeWord
+}
+
+// newAIComponents creates the AI service and conversation: Ollama, Home
+// Assistant, or Home Assistant falling back to Ollama. AI is disabled in cfg
+// when no backend is reachable.
+func newAIComponents(cfg *config.Config) (ai.AIService, ai.ConversationManager) {
+	aiService, conversation, _ := connectAI(cfg, false)
+	return aiService, conversation
+}
+
+// aiTransportOptions returns the TLS and proxy settings of the AI
+// backends, ok is false when none is set
+func aiTransportOptions(cfg *config.Config) (opts ai.TransportOptions, ok bool) {
+	opts = ai.TransportOptions{
+		CAFile:             cfg.AICAFile,
+		CertFile:           cfg.AICertFile,
+		KeyFile:            cfg.AIKeyFile,
+		InsecureSkipVerify: cfg.AIInsecureSkipVerify,
+		Proxy:              cfg.AIProxy,
+	}
+	return opts, opts != ai.TransportOptions{}
+}
+
+// newAITransport creates the transport of the AI backends from the TLS and
+// proxy settings, nil keeps the default transport
+func newAITransport(cfg *config.Config) *http.Transport {
+	opts, ok := aiTransportOptions(cfg)
+	if !ok {
+		return nil
+	}
+
+	transport, err := ai.NewTransport(opts)
+	if err != nil {
+		logger.WithError(err).Fatal("❌ Invalid AI backend TLS or proxy settings")
+	}
+	if cfg.AIInsecureSkipVerify {
+		logger.Warn("⚠️  ai_insecure_skip_verify is set: the certificates of Ollama and Home Assistant are NOT verified")
+		logger.Warn("   Anyone on the network path can read and alter the conversations, use ai_ca_file instead")
+	}
+	return transport
+}
+
+// connectAI creates the AI components like newAIComponents. With
+// keepUnreachable, backends that did not answer are kept for an ai.Watcher
+// to pick up once they are up, and available reports whether any answered.
+func connectAI(cfg *config.Config, keepUnreachable bool) (aiService ai.AIService, conversation ai.ConversationManager, available bool) {
+	haMode := cfg.HomeAssistantMode
+	switch haMode {
+	case "off", "only", "fallback":
+	default:
+		logger.WithField("homeassistant_mode", haMode).Fatal("Invalid Home Assistant mode (expected off, only or fallback)")
+	}
+
+	transport := newAITransport(cfg)
+
+	var ollama ai.AIService
+	if cfg.AIEnabled && haMode != "only" {
+		service := ai.NewOllamaService(cfg.OllamaURL, cfg.OllamaModel)
+		if transport != nil {
+			service.SetTransport(transport)
+		}
+		ollama = service
+
+		// Check if Ollama is available
+		if ollama.IsAvailable() {
+			available = true
+		} else {
+			logger.Warnf("⚠️  Warning: Ollama service not available at %s", cfg.OllamaURL)
+			logger.Warn("   Make sure Ollama is running: ollama serve")
+			logger.Warnf("   And the model is available: ollama pull %s", cfg.OllamaModel)
+			if !keepUnreachable {
+				ollama = nil
+			}
+		}
+	}
+
+	var homeAssistant ai.AIService
+	if haMode != "off" {
+		service := ai.NewHomeAssistantService(cfg.HomeAssistantURL, cfg.HomeAssistantToken,
+			cfg.HomeAssistantAgentID, cfg.Language)
+		if transport != nil {
+			service.SetTransport(transport)
+		}
+		homeAssistant = service
+		if homeAssistant.IsAvailable() {
+			available = true
+			fmt.Printf("🏠 Home Assistant connected (%s)\n", cfg.HomeAssistantURL)
+		} else {
+			logger.Warnf("⚠️  Warning: Home Assistant not available at %s (check the URL and token)", cfg.HomeAssistantURL)
+			if !keepUnreachable {
+				homeAssistant = nil
+			}
+		}
+	}
+
+	switch {
+	case homeAssistant != nil && ollama != nil:
+		aiService = ai.NewFallbackService(homeAssistant, ollama)
+	case homeAssistant != nil:
+		aiService = homeAssistant
+	case ollama != nil:
+		aiService = ollama
+	default:
+		cfg.AIEnabled = false
+		return nil, nil, false
+	}
+	cfg.AIEnabled = true
+	if cfg.AIContext {
+		aiService = newContextService(cfg, aiService)
+	}
+
+	conversation = ai.NewConversation(cfg.MaxHistory)
+	conversation.SetSystemPrompt(cfg.SystemPrompt)
+	if available {
+		fmt.Printf("✅ AI service connected successfully\n")
+	} else {
+		fmt.Printf("⏳ AI service unreachable, retrying every %s\n", cfg.AIWatchInterval)
 	}
+	return aiService, conversation, available
 }
 
-func createListModelsCmd() *cobra.Command {
+// newContextService wraps service to send it the date, time, language and
+// the configured context with every request
+func newContextService(cfg *config.Config, service ai.AIService) *ai.ContextService {
+	var sources []ai.ContextSource
+	if cfg.AILocation != "" {
+		sources = append(sources, ai.StaticContext("Location: "+cfg.AILocation))
+	}
+	for _, command := range cfg.AIContextCommands {
+		source, err := ai.NewCommandContext(command, cfg.AIContextRefresh)
+		if err != nil {
+			logger.WithError(err).Fatal("❌ Invalid ai_context_commands")
+		}
+		sources = append(sources, source)
+	}
+
+	contextService := ai.NewContextService(service, sources...)
+	contextService.SetLanguage(cfg.Language)
+	contextService.SetErrorHandler(func(err error) {
+		logger.WithError(err).Warn("⚠️  AI context unavailable")
+	})
+	return contextService
+}
+
+// liveOutputs are the sinks of a live session
+type liveOutputs struct {
+	events  *output.Bus // Every sink but the console
+	console bool
+	stdout  output.SinkType // The json or plain sink owning stdout, if any
+	files   []*output.FileSink
+}
+
+// openOutputs opens the sinks of outputs, or else of output_format. stdout
+// then only carries the events of a json or plain sink: next to json the
+// console lines go to stderr, next to plain only warnings and errors are
+// logged, to stderr.
+func openOutputs(cfg config.Config, sessionID string, timestamps *output.Timestamps) *liveOutputs {
+	sinks, err := liveSinks(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid outputs")
+	}
+
+	outputs := &liveOutputs{events: output.NewBus(), console: output.HasSink(sinks, output.SinkConsole)}
+	for _, sink := range sinks {
+		switch sink.Type {
+		case output.SinkJSON:
+			events := output.NewJSONWriter(os.Stdout, sessionID)
+			events.SetTimestamps(timestamps)
+			outputs.events.Subscribe(events)
+			outputs.stdout = sink.Type
+			os.Stdout = os.Stderr
+			logger.SetOutput(os.Stderr)
+		case output.SinkPlain:
+			devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+			if err != nil {
+				logger.WithError(err).Fatal("Failed to open the null device")
+			}
+			outputs.events.Subscribe(output.NewPlainWriter(os.Stdout))
+			outputs.stdout = sink.Type
+			os.Stdout = devNull
+			logger.SetOutput(os.Stderr)
+			if cfg.LogLevel == "info" {
+				logger.InitLogger("warn")
+			}
+		case output.SinkJSONLFile, output.SinkTextFile:
+			file, err := output.OpenFileSink(sink, sessionID, timestamps)
+			if err != nil {
+				logger.WithError(err).Fatal("Failed to open the output file")
+			}
+			outputs.events.Subscribe(file)
+			outputs.files = append(outputs.files, file)
+		}
+	}
+	return outputs
+}
+
+// liveSinks returns the sinks of outputs, or the one of output_format
+func liveSinks(cfg config.Config) ([]output.Sink, error) {
+	if len(cfg.Outputs) > 0 {
+		sinks, err := output.ParseSinks(cfg.Outputs, config.StateDir())
+		for i := range sinks {
+			sinks[i].Path = config.ExpandHome(sinks[i].Path)
+		}
+		return sinks, err
+	}
+	format, err := output.ParseFormat(cfg.OutputFormat)
+	if err != nil {
+		return nil, err
+	}
+	return output.FormatSinks(format), nil
+}
+
+// quietOutputs replaces the console and json outputs by plain, keeping the
+// files
+func quietOutputs(outputs []string) []string {
+	if len(outputs) == 0 {
+		return nil
+	}
+	quiet := []string{string(output.SinkPlain)}
+	for _, spec := range outputs {
+		switch name, _, _ := strings.Cut(strings.TrimSpace(spec), ":"); output.SinkType(strings.ToLower(name)) {
+		case output.SinkConsole, output.SinkJSON, output.SinkPlain:
+		default:
+			quiet = append(quiet, spec)
+		}
+	}
+	return quiet
+}
+
+// attach subscribes the sinks to the events of processor, dropping the
+// console lines unless the console is one of them
+func (o *liveOutputs) attach(processor *assistant.Assistant) {
+	if o.console {
+		processor.AddEmitter(o.events)
+	} else {
+		processor.SetEventWriter(o.events)
+	}
+	for _, file := range o.files {
+		fmt.Printf("📄 Events file: %s\n", file.Path())
+	}
+}
+
+// Close closes the output files
+func (o *liveOutputs) Close() {
+	for _, file := range o.files {
+		if err := file.Close(); err != nil {
+			logger.WithError(err).Warn("⚠️  Failed to close the output file")
+		}
+	}
+}
+
+// newTimestamps parses the timestamp format and time zone of cfg
+func newTimestamps(cfg config.Config) *output.Timestamps {
+	timestamps, err := output.NewTimestamps(cfg.TimestampFormat, cfg.TimestampTimezone)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid timestamp settings")
+	}
+	return timestamps
+}
+
+// newMQTTConfig builds the MQTT client configuration
+func newMQTTConfig(cfg config.Config) mqtt.Config {
+	return mqtt.Config{
+		Broker:             cfg.MQTTBroker,
+		ClientID:           cfg.MQTTClientID,
+		Username:           cfg.MQTTUsername,
+		Password:           cfg.MQTTPassword,
+		TopicPrefix:        cfg.MQTTTopicPrefix,
+		CAFile:             cfg.MQTTCAFile,
+		CertFile:           cfg.MQTTCertFile,
+		KeyFile:            cfg.MQTTKeyFile,
+		InsecureSkipVerify: cfg.MQTTInsecureSkipVerify,
+	}
+}
+
+// newMatrixConfig builds the Matrix bot configuration
+func newMatrixConfig(cfg config.Config) matrix.Config {
+	return matrix.Config{
+		Homeserver:  cfg.MatrixHomeserver,
+		AccessToken: cfg.MatrixAccessToken,
+		Room:        cfg.MatrixRoom,
+		Users:       cfg.MatrixUsers,
+		Transcripts: cfg.MatrixTranscripts,
+	}
+}
+
+// newTelegramConfig builds the Telegram bot configuration
+func newTelegramConfig(cfg config.Config) telegram.Config {
+	return telegram.Config{
+		Token:         cfg.TelegramToken,
+		ChatIDs:       cfg.TelegramChatIDs,
+		ForwardChatID: cfg.TelegramForwardChatID,
+	}
+}
+
+// newWhisperService creates a Whisper service using the configured decoding parameters
+func newWhisperService(cfg config.Config) *whisper.Service {
+	return whisper.NewServiceWithConfig(whisper.ModelConfig{
+		BeamSize:         cfg.BeamSize,
+		Temperature:      cfg.Temperature,
+		EntropyThreshold: cfg.EntropyThreshold,
+		MaxSegmentLength: cfg.MaxSegmentLength,
+	})
+}
+
+// applyAutoModel replaces the model of cfg by the largest installed one the
+// hardware runs in real time, when auto_model is set and --model is not
+func applyAutoModel(cfg *config.Config) {
+	if !cfg.AutoModel || modelFlag {
+		return
+	}
+
+	caps := hardware.Detect()
+	path, name := hardware.SelectModel(caps, hardware.ModelDirs(cfg.WhisperModel, config.DataDirs()))
+	recommended := caps.Recommend()
+	if path == "" {
+		logger.Warnf("⚠️  No installed Whisper model runs in real time on this hardware (%s), keeping %s; download ggml-%s.bin with nrz-ai init",
+			caps, cfg.WhisperModel, recommended)
+		return
+	}
+
+	logger.Infof("🧠 Whisper model %s picked for %s (override with --model)", name, caps)
+	if name != recommended {
+		logger.Infof("💡 ggml-%s.bin would run in real time too, for better accuracy", recommended)
+	}
+	cfg.WhisperModel = path
+}
+
+// newCapture captures the audio source with ffmpeg, or reads a pipe: or
+// unix: source in the configured PCM format
+func newCapture(cfg config.Config) audio.AudioCapture {
+	if audio.IsPipeSource(cfg.AudioSource) {
+		return audio.NewPipeCapture(audio.PipeFormat{
+			Encoding:   cfg.PipeFormat,
+			SampleRate: cfg.PipeSampleRate,
+			Channels:   cfg.PipeChannels,
+		})
+	}
+	return audio.NewFFmpegCapture(newFFmpegOptions(cfg))
+}
+
+// newFFmpegOptions builds the ffmpeg capture options
+func newFFmpegOptions(cfg config.Config) audio.FFmpegOptions {
+	return audio.FFmpegOptions{
+		InputFormat: cfg.FFmpegInputFormat,
+		InputArgs:   cfg.FFmpegExtraArgs,
+		QueueSize:   cfg.FFmpegQueueSize,
+	}
+}
+
+// newRedactor creates the transcript redactor, nil when redaction is off
+func newRedactor(cfg config.Config) *redact.Redactor {
+	if !cfg.RedactEnabled {
+		return nil
+	}
+	redactor, err := redact.New(cfg.RedactRules, cfg.RedactPatterns)
+	if err != nil {
+		logger.WithError(err).Fatal("❌ Invalid redaction settings")
+	}
+	return redactor
+}
+
+// newHookConfig returns the hook commands of cfg
+func newHookConfig(cfg config.Config) hooks.Config {
+	return hooks.Config{
+		OnWake:       cfg.HookOnWake,
+		OnTranscript: cfg.HookOnTranscript,
+		OnAIResponse: cfg.HookOnAIResponse,
+		OnError:      cfg.HookOnError,
+		Timeout:      cfg.HookTimeout,
+		Concurrency:  cfg.HookConcurrency,
+	}
+}
+
+// newBlocklist creates the transcript blocklist, nil when it is off
+func newBlocklist(cfg config.Config) *blocklist.Blocklist {
+	if !cfg.BlocklistEnabled {
+		return nil
+	}
+	list, err := blocklist.New(true, cfg.BlocklistPhrases, cfg.BlocklistPatterns)
+	if err != nil {
+		logger.WithError(err).Fatal("❌ Invalid blocklist settings")
+	}
+	return list
+}
+
+// newSoundEmitter creates the audio feedback of the sound theme, nil when
+// the theme is off
+func newSoundEmitter(cfg config.Config) *sounds.Emitter {
+	names, err := sounds.ParseNames(cfg.SoundEvents)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid sound events")
+	}
+	theme, err := sounds.LoadTheme(cfg.SoundTheme)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid sound theme")
+	}
+	theme.SetFile(sounds.SoundWake, cfg.WakeWordSound)
+	theme.SetFile(sounds.SoundTimer, cfg.TimerSound)
+	if theme.Name() == sounds.ThemeOff && cfg.WakeWordSound == "" && cfg.TimerSound == "" {
+		return nil
+	}
+	fmt.Printf("🔊 Sound theme: %s (%s)\n", theme.Name(), strings.Join(names, ", "))
+	return sounds.NewEmitter(theme, names)
+}
+
+// newDiarizationConfig builds the speaker diarization configuration
+func newDiarizationConfig(cfg config.Config) diarization.Config {
+	diarizationConfig := diarization.DefaultConfig()
+	diarizationConfig.SampleRate = sampleRate
+	diarizationConfig.SimilarityThreshold = cfg.DiarizationThreshold
+	diarizationConfig.MaxSpeakers = cfg.DiarizationMaxSpeakers
+	return diarizationConfig
+}
+
+func createListModelsCmd(cfg *config.Config) *cobra.Command {
 	return &cobra.Command{
 		Use:   "list-models",
 		Short: "List available Ollama models",
@@ -481,6 +1354,9 @@ func createListModelsCmd() *cobra.Command {
 			}
 
 			service := ai.NewOllamaService(ollamaURL, "")
+			if transport := newAITransport(cfg); transport != nil {
+				service.SetTransport(transport)
+			}
 			if !service.IsAvailable() {
 				logger.WithField("url", ollamaURL).Fatal("❌ Ollama not available")
 			}
@@ -498,10 +1374,10 @@ func createListModelsCmd() *cobra.Command {
 	}
 }
 
-func createTestAudioCmd() *cobra.Command {
+func createTestAudioCmd(cfg *config.Config) *cobra.Command {
 	return &cobra.Command{
 		Use:   "test-audio",
-		Short: "Test audio input",
+		Short: "Test audio input (see audio monitor for a live meter)",
 		Run: func(cmd *cobra.Command, args []string) {
 			audioSource, _ := cmd.Flags().GetString("audio-source")
 			if audioSource == "" {
@@ -511,7 +1387,7 @@ func createTestAudioCmd() *cobra.Command {
 			fmt.Printf("🎤 Testing audio source: %s\n", audioSource)
 			fmt.Println("This will capture 3 seconds of audio...")
 
-			capture := audio.NewFFmpegCapture()
+			capture := newCapture(*cfg)
 			stream, err := capture.StartCapture(audioSource)
 			if err != nil {
 				logger.WithError(err).Fatal("❌ Failed to start audio capture")
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/internal/meeting"
+	"github.com/nerzhul/nrz-ai/internal/translate"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+// Mode selects what nrz-ai does with transcriptions
+type Mode string
+
+const (
+	// ModeAssistant is the voice assistant: optional wake word and AI chat
+	ModeAssistant Mode = "assistant"
+	// ModeMeeting transcribes continuously into Markdown minutes
+	ModeMeeting Mode = "meeting"
+	// ModeDictation types transcripts into the focused window
+	ModeDictation Mode = "dictation"
+	// ModeTranslate prints every transcript with its translation
+	ModeTranslate Mode = "translate"
+)
+
+// ParseMode validates a mode name
+func ParseMode(name string) (Mode, error) {
+	switch Mode(name) {
+	case ModeAssistant, ModeMeeting, ModeDictation, ModeTranslate:
+		return Mode(name), nil
+	default:
+		return "", fmt.Errorf("unknown mode '%s' (expected %s, %s, %s or %s)", name, ModeAssistant, ModeMeeting, ModeDictation, ModeTranslate)
+	}
+}
+
+// meetingSession owns the minutes of a meeting-mode run
+type meetingSession struct {
+	recorder       *meeting.Recorder
+	summaryService ai.AIService
+	summaryPrompt  string
+	once           sync.Once
+}
+
+// startMeetingSession creates the minutes file and, if requested, the AI
+// service used for the end-of-meeting summary
+func startMeetingSession(cfg config.Config) (*meetingSession, error) {
+	recorder, err := meeting.NewRecorder(cfg.MeetingDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create meeting minutes: %w", err)
+	}
+
+	session := &meetingSession{
+		recorder:      recorder,
+		summaryPrompt: cfg.MeetingSummaryPrompt,
+	}
+
+	if cfg.MeetingSummary {
+		service := ai.NewOllamaService(cfg.OllamaURL, cfg.OllamaModel)
+		if transport := newAITransport(&cfg); transport != nil {
+			service.SetTransport(transport)
+		}
+		if service.IsAvailable() {
+			session.summaryService = service
+		} else {
+			logger.Warnf("⚠️  Ollama not available at %s, meeting summary disabled", cfg.OllamaURL)
+		}
+	}
+
+	return session, nil
+}
+
+// Finish summarizes the meeting if enabled and writes the final minutes.
+// Safe to call several times.
+func (m *meetingSession) Finish() {
+	m.once.Do(func() {
+		if m.summaryService != nil {
+			fmt.Println("📝 Generating meeting summary...")
+			if err := m.recorder.Summarize(m.summaryService, m.summaryPrompt); err != nil {
+				logger.WithError(err).Error("❌ Failed to summarize meeting")
+			}
+		}
+
+		if err := m.recorder.Close(); err != nil {
+			logger.WithError(err).Error("❌ Failed to write meeting minutes")
+			return
+		}
+		fmt.Printf("📝 Meeting minutes saved to %s\n", m.recorder.Path())
+	})
+}
+
+// newTranslator creates the translator of the translate mode and returns
+// its backend. Like the meeting summary, the AI one talks to Ollama
+// directly, without the conversation of the assistant mode.
+func newTranslator(cfg config.Config, whisperService whisper.WhisperService) (translate.Translator, string, error) {
+	target := strings.ToLower(strings.TrimSpace(cfg.TranslateTarget))
+	if target == "" || target == "auto" {
+		return nil, "", fmt.Errorf("translate_target must be a language code such as en or fr")
+	}
+	backend, err := translate.ParseBackend(cfg.TranslateBackend, target)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if backend == translate.BackendWhisper {
+		audioTranslator, ok := whisperService.(whisper.AudioTranslator)
+		if !ok {
+			return nil, "", fmt.Errorf("the Whisper service cannot translate")
+		}
+		return translate.NewWhisperTranslator(audioTranslator), backend, nil
+	}
+
+	service := ai.NewOllamaService(cfg.OllamaURL, cfg.OllamaModel)
+	if transport := newAITransport(&cfg); transport != nil {
+		service.SetTransport(transport)
+	}
+	if !service.IsAvailable() {
+		return nil, "", fmt.Errorf("Ollama not available at %s, needed to translate into %s", cfg.OllamaURL, target)
+	}
+	return translate.NewAITranslator(service, target), backend, nil
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/pkg/assistant"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/nerzhul/nrz-ai/pkg/vad"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// monitorRefresh is how often the meter line is redrawn
+	monitorRefresh = 100 * time.Millisecond
+	// monitorWidth is the number of cells of the meter
+	monitorWidth = 30
+	// monitorScale is the RMS level of a full meter
+	monitorScale = 0.2
+)
+
+func createAudioCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audio",
+		Short: "Audio input tools",
+	}
+	cmd.AddCommand(createAudioMonitorCmd(cfg))
+	cmd.AddCommand(createAudioDevicesCmd())
+	return cmd
+}
+
+func createAudioDevicesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "devices",
+		Short: "List the audio input devices usable as --audio-source",
+		Run: func(cmd *cobra.Command, args []string) {
+			devices, err := audio.ListDevices()
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Failed to list audio devices")
+			}
+
+			fmt.Printf("🎤 Audio input devices (%s):\n", audio.InputFormat())
+			for _, device := range devices {
+				line := "  " + device.Name
+				if device.Description != "" {
+					line += "  (" + device.Description + ")"
+				}
+				if device.Monitor {
+					line += "  [speaker monitor]"
+				}
+				fmt.Println(line)
+			}
+		},
+	}
+}
+
+func createAudioMonitorCmd(cfg *config.Config) *cobra.Command {
+	var threshold float32
+	var silenceMs, minSpeechMs int
+
+	cmd := &cobra.Command{
+		Use:   "monitor",
+		Short: "Show a live level meter and the VAD decisions of the audio source",
+		Long: `Continuously display the RMS level and peak of the audio source, the noise floor
+measured during calibration with the resulting speech threshold, and what the
+pipeline would do: when speech starts and whether each utterance would be
+transcribed or dropped as too short. Try --threshold and --silence-ms to tune
+the VAD, Ctrl-C to stop.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			vadConfig := newVADConfig(*cfg)
+			if cmd.Flags().Changed("threshold") {
+				vadConfig.SilenceThreshold = threshold
+			}
+			if cmd.Flags().Changed("silence-ms") {
+				vadConfig.SilenceDurationMs = silenceMs
+			}
+			if cmd.Flags().Changed("min-speech-ms") {
+				vadConfig.MinSpeechDurationMs = minSpeechMs
+			}
+
+			capture := newCapture(*cfg)
+			stream, err := capture.StartCapture(cfg.AudioSource)
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Failed to start audio capture")
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			go func() {
+				<-ctx.Done()
+				stream.Close()
+			}()
+
+			fmt.Printf("🎤 Monitoring %s (threshold %.3f, silence %dms), Ctrl-C to stop\n",
+				cfg.AudioSource, vadConfig.SilenceThreshold, vadConfig.SilenceDurationMs)
+			if err := monitorAudio(stream, vadConfig, os.Stdout); err != nil {
+				logger.WithError(err).Fatal("❌ Audio monitor failed")
+			}
+		},
+	}
+
+	defaults := assistant.DefaultVADConfig()
+	cmd.Flags().Float32Var(&threshold, "threshold",
+		defaults.SilenceThreshold, "Minimum speech threshold (default --vad-threshold)")
+	cmd.Flags().IntVar(&silenceMs, "silence-ms",
+		defaults.SilenceDurationMs, "Silence ending an utterance, in milliseconds (default --vad-silence-ms)")
+	cmd.Flags().IntVar(&minSpeechMs, "min-speech-ms",
+		defaults.MinSpeechDurationMs, "Shorter utterances are dropped, in milliseconds")
+
+	return cmd
+}
+
+// monitorAudio runs the VAD on stream and draws the meter to out until the
+// stream ends
+func monitorAudio(stream audio.AudioStream, vadConfig vad.VADConfig, out io.Writer) error {
+	// The detector logs each decision, the meter shows them instead
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	detector := vad.NewRMSDetector()
+	if err := detector.Initialize(vadConfig); err != nil {
+		return err
+	}
+
+	decoder := audio.NewFrameDecoder(audio.NewProcessor())
+	silenceSamples := vadConfig.SilenceDurationMs * vadConfig.SampleRate / 1000
+	minSpeechSamples := vadConfig.MinSpeechDurationMs * vadConfig.SampleRate / 1000
+
+	var peak float32
+	var utterance, calibrated int
+	speaking := false
+	lastDraw := time.Now()
+	chunk := make([]byte, readChunkSize)
+
+	for {
+		n, err := stream.Read(chunk)
+		if err != nil {
+			fmt.Fprintln(out)
+			return nil
+		}
+
+		samples := decoder.Decode(chunk[:n])
+		for _, sample := range samples {
+			if abs := float32(math.Abs(float64(sample))); abs > peak {
+				peak = abs
+			}
+
+			detector.ProcessSample(sample)
+			if !detector.IsCalibrated() {
+				calibrated++
+				continue
+			}
+			if detector.IsSpeaking() {
+				utterance++
+			}
+			if detector.IsSpeaking() && !speaking {
+				speaking = true
+				fmt.Fprintf(out, "\r\033[K[%s] 🗣️  speech started\n", time.Now().Format("15:04:05"))
+			}
+
+			if detector.IsSpeaking() && detector.GetSilenceDuration() >= silenceSamples {
+				duration := float64(utterance) / float64(vadConfig.SampleRate)
+				verdict := "would be transcribed"
+				if utterance < minSpeechSamples {
+					verdict = "too short, would be dropped"
+				}
+				fmt.Fprintf(out, "\r\033[K[%s] 🔇 utterance of %.2fs, %s\n", time.Now().Format("15:04:05"), duration, verdict)
+				detector.Reset()
+				speaking = false
+				utterance = 0
+			}
+		}
+
+		if time.Since(lastDraw) >= monitorRefresh {
+			state := detector.GetState()
+			fmt.Fprint(out, "\r\033[K"+monitorLine(state, peak, calibrated, vadConfig.NoiseFloorSamples))
+			peak = 0
+			lastDraw = time.Now()
+		}
+	}
+}
+
+// monitorLine renders the meter line for the current detector state
+func monitorLine(state vad.VADState, peak float32, calibrated, calibrationSamples int) string {
+	filled := int(state.Level / monitorScale * monitorWidth)
+	if filled > monitorWidth {
+		filled = monitorWidth
+	}
+	bar := []rune(strings.Repeat("█", filled) + strings.Repeat("░", monitorWidth-filled))
+
+	// Mark the speech threshold on the meter
+	if mark := int(state.AdaptiveThreshold / monitorScale * monitorWidth); state.IsCalibrated && mark < monitorWidth {
+		bar[mark] = '|'
+	}
+
+	line := fmt.Sprintf("🎚️  %s %.4f  peak %.3f", string(bar), state.Level, peak)
+	if !state.IsCalibrated {
+		return line + fmt.Sprintf("  calibrating noise floor %d%%", calibrated*100/calibrationSamples)
+	}
+
+	line += fmt.Sprintf("  floor %.4f → threshold %.4f", state.NoiseFloor, state.AdaptiveThreshold)
+	if state.IsSpeaking {
+		return line + "  SPEECH"
+	}
+	return line + "  silence"
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// pathEntry is a location shown by nrz-ai paths
+type pathEntry struct {
+	name string
+	path string
+	// file is set for files that must exist, directories are created on use
+	file bool
+}
+
+func createPathsCmd(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "paths",
+		Short: "Show the resolved config, data and state locations",
+		Long: `Print where nrz-ai reads its configuration, models and sounds from and where it
+writes meeting minutes, sessions and logs. Relative model and sound paths are
+looked up from the working directory, then in the data search path.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			configFile := viper.ConfigFileUsed()
+			if configFile == "" {
+				configFile = filepath.Join(config.Dir(), "config.yaml")
+			}
+			intentsFile := cfg.IntentsFile
+			if intentsFile == "" {
+				intentsFile = filepath.Join(config.Dir(), "intents.yaml")
+			}
+			logDir := cfg.LogDir
+			if logDir == "" {
+				logDir = config.StateDir()
+			}
+
+			for _, entry := range []pathEntry{
+				{name: "Config file", path: configFile, file: true},
+				{name: "Config dir", path: config.Dir()},
+				{name: "Data dir", path: config.DataDir()},
+				{name: "State dir", path: config.StateDir()},
+				{name: "Whisper model", path: cfg.WhisperModel, file: true},
+				{name: "Sound theme", path: cfg.SoundTheme},
+				{name: "Wake word sound", path: cfg.WakeWordSound, file: true},
+				{name: "Timer sound", path: cfg.TimerSound, file: true},
+				{name: "Intents file", path: intentsFile, file: true},
+				{name: "Meeting minutes", path: cfg.MeetingDir},
+				{name: "Sessions", path: config.SessionsDir()},
+				{name: "Transcripts", path: cfg.ArchiveDir},
+				{name: "History index", path: historyIndexFile(), file: true},
+				{name: "Logs", path: logDir},
+				{name: "Timers", path: filepath.Join(config.StateDir(), "timers.json")},
+				{name: "Voice prints", path: cfg.VoicesFile, file: true},
+				{name: "Control socket", path: controlSocketPath(*cfg)},
+			} {
+				fmt.Printf("%-16s %s\n", entry.name, describePath(entry))
+			}
+
+			fmt.Printf("%-16s %s\n", "Data search path", strings.Join(config.DataDirs(), ":"))
+		},
+	}
+}
+
+// describePath renders a path with a missing marker for absent files
+func describePath(entry pathEntry) string {
+	if entry.path == "" {
+		return "(none)"
+	}
+	if !entry.file {
+		return entry.path
+	}
+	if _, err := os.Stat(entry.path); err != nil {
+		return entry.path + "  ❌ missing"
+	}
+	return entry.path + "  ✅"
+}
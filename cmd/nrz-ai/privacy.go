@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/privacy"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+// applyPrivacy turns the privacy mode on and the configured features
+// writing to disk off, returning the ones turned off. The others, such as
+// meeting notes or session recordings, fail with privacy.ErrDisabled.
+func applyPrivacy(cfg *config.Config) []string {
+	privacy.Enable()
+
+	var disabled []string
+	if cfg.LogFile {
+		cfg.LogFile = false
+		disabled = append(disabled, "log file")
+	}
+	if cfg.TranscriptLog {
+		cfg.TranscriptLog = false
+		disabled = append(disabled, "transcript log")
+	}
+	if cfg.ArchiveEnabled {
+		cfg.ArchiveEnabled = false
+		disabled = append(disabled, "session archive")
+	}
+	if cfg.DailyNotesPath != "" {
+		cfg.DailyNotesPath = ""
+		disabled = append(disabled, "daily notes")
+	}
+	if cfg.CaptionsFile != "" {
+		cfg.CaptionsFile = ""
+		disabled = append(disabled, "captions file")
+	}
+	if outputs := dropFileOutputs(cfg.Outputs); len(outputs) != len(cfg.Outputs) {
+		cfg.Outputs = outputs
+		disabled = append(disabled, "output files")
+	}
+	return disabled
+}
+
+// dropFileOutputs returns the outputs but the file sinks
+func dropFileOutputs(outputs []string) []string {
+	var kept []string
+	for _, spec := range outputs {
+		name, _, _ := strings.Cut(strings.TrimSpace(spec), ":")
+		if !output.IsFileSink(output.SinkType(strings.ToLower(name))) {
+			kept = append(kept, spec)
+		}
+	}
+	return kept
+}
+
+// printPrivacyBanner tells the privacy mode is on, on stderr to keep the
+// JSON and plain outputs clean
+func printPrivacyBanner(disabled []string) {
+	fmt.Fprintln(os.Stderr, "🔒 Privacy mode: no audio, transcript or conversation is written to disk")
+	if len(disabled) > 0 {
+		fmt.Fprintf(os.Stderr, "   Turned off: %s\n", strings.Join(disabled, ", "))
+	}
+}
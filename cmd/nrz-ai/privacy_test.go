@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/privacy"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+func TestApplyPrivacy(t *testing.T) {
+	cfg := &config.Config{
+		LogFile:        true,
+		ArchiveEnabled: true,
+		CaptionsFile:   "live.srt",
+		Outputs:        []string{"console", "jsonl_file", "TEXT_FILE:/tmp/events.log"},
+	}
+
+	disabled := applyPrivacy(cfg)
+
+	if !privacy.Enabled() {
+		t.Fatal("Expected the privacy mode to be enabled")
+	}
+	if strings.Join(disabled, ", ") != "log file, session archive, captions file, output files" {
+		t.Errorf("Expected the features writing to disk to be listed, got %v", disabled)
+	}
+	if cfg.LogFile || cfg.ArchiveEnabled || cfg.CaptionsFile != "" {
+		t.Errorf("Expected the features writing to disk to be off, got %+v", cfg)
+	}
+	if len(cfg.Outputs) != 1 || cfg.Outputs[0] != "console" {
+		t.Errorf("Expected only the console output, got %v", cfg.Outputs)
+	}
+
+	sink := output.Sink{Type: output.SinkJSONLFile, Path: filepath.Join(t.TempDir(), "events.jsonl")}
+	if _, err := output.OpenFileSink(sink, "s1", nil); !errors.Is(err, privacy.ErrDisabled) {
+		t.Errorf("Expected the output files to be refused, got %v", err)
+	}
+	if disabled := applyPrivacy(cfg); len(disabled) != 0 {
+		t.Errorf("Expected nothing left to turn off, got %v", disabled)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/internal/recording"
+	"github.com/nerzhul/nrz-ai/pkg/assistant"
+	"github.com/spf13/cobra"
+)
+
+func createRecordCmd(cfg *config.Config) *cobra.Command {
+	var out string
+	var duration time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "record [--out session.nrz]",
+		Short: "Record raw microphone audio with its timing for nrz-ai replay",
+		Long: `Capture the audio source exactly as the live pipeline reads it, with the time
+each chunk arrived, until Ctrl-C or --duration. Replay the session with
+nrz-ai replay to check VAD and wake word changes against real audio.
+Sessions go to $XDG_DATA_HOME/nrz-ai/sessions unless --out is given.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if out == "" {
+				if err := os.MkdirAll(config.SessionsDir(), 0755); err != nil {
+					logger.WithError(err).Fatal("❌ Failed to create sessions directory")
+				}
+				out = filepath.Join(config.SessionsDir(),
+					fmt.Sprintf("session-%s.nrz", time.Now().Format("2006-01-02-150405")))
+			}
+
+			writer, err := recording.Create(out, recording.Meta{
+				Start:      time.Now(),
+				SampleRate: sampleRate,
+				Source:     cfg.AudioSource,
+				Language:   cfg.Language,
+			})
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Failed to create session file")
+			}
+
+			stream, err := newCapture(*cfg).StartCapture(cfg.AudioSource)
+			if err != nil {
+				writer.Close()
+				logger.WithError(err).Fatal("❌ Failed to start audio capture")
+			}
+			recorder := recording.NewRecordingStream(stream, writer)
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			if duration > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, duration)
+				defer cancel()
+			}
+			// Closing the stream unblocks the pending read
+			go func() {
+				<-ctx.Done()
+				recorder.Close()
+			}()
+
+			fmt.Printf("🔴 Recording %s to %s, Ctrl-C to stop\n", cfg.AudioSource, out)
+			var samples int64
+			buffer := make([]byte, readChunkSize)
+			for {
+				n, err := recorder.Read(buffer)
+				samples += int64(n / 4)
+				if err != nil {
+					break
+				}
+			}
+
+			if err := writer.Close(); err != nil {
+				logger.WithError(err).Fatal("❌ Failed to write session file")
+			}
+			fmt.Printf("\n✅ Recorded %s of audio to %s\n",
+				(time.Duration(samples) * time.Second / sampleRate).Round(time.Millisecond), out)
+		},
+	}
+
+	cmd.Flags().StringVar(&out, "out", "", "Session file to write (default $XDG_DATA_HOME/nrz-ai/sessions/session-<time>.nrz)")
+	cmd.Flags().DurationVar(&duration, "duration", 0, "Stop after this long (0 = until Ctrl-C)")
+
+	return cmd
+}
+
+func createReplayCmd(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "replay <session.nrz>",
+		Short: "Feed a recorded session through the pipeline",
+		Long: `Run a session recorded with nrz-ai record through VAD, wake word detection,
+Whisper and optionally the AI, as fast as possible. Timestamps come from the
+recording, so two replays with the same settings give the same output:
+combine with --output json and diff the events to validate tuning changes.
+Session names not found from the working directory are looked up in
+$XDG_DATA_HOME/nrz-ai/sessions.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			path := args[0]
+			if _, err := os.Stat(path); err != nil && !filepath.IsAbs(path) {
+				path = filepath.Join(config.SessionsDir(), path)
+			}
+
+			reader, err := recording.Open(path)
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Failed to open session")
+			}
+			meta := reader.Meta()
+			if meta.SampleRate != sampleRate {
+				logger.WithField("sample_rate", meta.SampleRate).Fatal("❌ Unsupported session sample rate")
+			}
+
+			// Transcribe in the recorded language unless overridden
+			if meta.Language != "" && !cmd.Flags().Changed("language") {
+				cfg.Language = meta.Language
+			}
+
+			// Named after the file, so event streams of two replays can be diffed
+			sessionID := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			timestamps := newTimestamps(*cfg)
+			outputs := openOutputs(*cfg, sessionID, timestamps)
+			defer outputs.Close()
+
+			fmt.Printf("⏯️  Replaying %s (recorded %s from %s)\n",
+				path, meta.Start.Format("2006-01-02 15:04:05"), meta.Source)
+
+			aiService, conversation := newAIComponents(cfg)
+			capture := recording.NewReplayCapture(reader)
+			processor, err := assistant.New(assistant.Options{
+				Whisper:      newWhisperService(*cfg),
+				Capture:      capture,
+				AI:           aiService,
+				Conversation: conversation,
+				WakeWord:     enabledWakeWord(*cfg),
+			})
+			if err != nil {
+				logger.WithError(err).Fatal("Failed to create the assistant")
+			}
+			processor.SetClock(capture.Now)
+			processor.SetTimestamps(timestamps)
+			// Never drop nor time out segments, the output must not depend on the machine speed
+			processor.SetTranscriptionQueue(cfg.TranscriptionQueueSize, assistant.QueuePolicyBlock)
+			outputs.attach(processor)
+			processor.SetVADConfig(newVADConfig(*cfg))
+			if cfg.VADMaxSpeechS > 0 {
+				processor.SetMaxUtterance(time.Duration(cfg.VADMaxSpeechS) * time.Second)
+			}
+			processor.SetAnswerGate(float64(cfg.AIMinConfidence), cfg.AIMinWords)
+			processor.SetAITrigger(newAITrigger(*cfg))
+
+			if err := processor.Initialize(cfg.WhisperModel, meta.Source, cfg.Language); err != nil {
+				logger.WithError(err).Fatal("Failed to initialize")
+			}
+			defer processor.Close()
+
+			if err := processor.ProcessStream(meta.Source); err != nil {
+				logger.WithError(err).Fatal("Failed to replay session")
+			}
+			fmt.Println("✅ Replay complete")
+		},
+	}
+}
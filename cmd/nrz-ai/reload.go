@@ -0,0 +1,122 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/assistant"
+)
+
+// configReloader applies the settings of a reloaded config that can change
+// without restarting the audio pipeline
+type configReloader struct {
+	current   config.Config
+	processor *assistant.Assistant
+	aiService ai.AIService
+	mutex     sync.Mutex
+}
+
+// newConfigReloader creates a reloader comparing reloads to cfg
+func newConfigReloader(cfg config.Config, processor *assistant.Assistant, aiService ai.AIService) *configReloader {
+	return &configReloader{
+		current:   cfg,
+		processor: processor,
+		aiService: aiService,
+	}
+}
+
+// HandleSIGHUP re-reads the config file on every SIGHUP until the returned
+// function is called
+func (r *configReloader) HandleSIGHUP() func() {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-sighup:
+				newCfg, err := config.ReadConfig()
+				if err != nil {
+					logger.WithError(err).Error("❌ Failed to reload configuration")
+					continue
+				}
+				logger.Info("🔄 Configuration reloaded on SIGHUP")
+				r.Apply(newCfg)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sighup)
+		close(done)
+	}
+}
+
+// Apply applies what changed between the current config and newCfg. Settings
+// that fail to apply keep their previous value, the others need a restart
+// and keep it too, until then, to be reported again on the next reload.
+func (r *configReloader) Apply(newCfg *config.Config) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	applyAutoModel(newCfg)
+	changed := config.Diff(&r.current, newCfg)
+	if len(changed) == 0 {
+		return
+	}
+
+	var applied, restart []string
+	for _, key := range changed {
+		switch key {
+		case "whisper_model":
+			if err := r.processor.SwitchModel(newCfg.WhisperModel); err != nil {
+				logger.WithError(err).Error("❌ Failed to switch Whisper model")
+				newCfg.WhisperModel = r.current.WhisperModel
+				continue
+			}
+		case "language":
+			if err := r.processor.SetLanguage(newCfg.Language); err != nil {
+				logger.WithError(err).Error("❌ Failed to change language")
+				newCfg.Language = r.current.Language
+				continue
+			}
+		case "system_prompt":
+			r.processor.SetPersona(newCfg.SystemPrompt)
+		case "ollama_model":
+			setter, ok := r.aiService.(ai.ModelSetter)
+			if !ok {
+				restart = append(restart, key)
+				continue
+			}
+			setter.SetModel(newCfg.OllamaModel)
+		case "vad_threshold", "vad_silence_ms", "vad_min_speech_ms":
+			r.processor.SetVADConfig(newVADConfig(*newCfg))
+		case "ai_min_confidence", "ai_min_words":
+			r.processor.SetAnswerGate(float64(newCfg.AIMinConfidence), newCfg.AIMinWords)
+		case "ai_questions_only", "ai_prefixes", "ai_name":
+			r.processor.SetAITrigger(newAITrigger(*newCfg))
+		default:
+			restart = append(restart, key)
+			continue
+		}
+		applied = append(applied, key)
+	}
+
+	if len(applied) > 0 {
+		logger.WithField("keys", strings.Join(applied, ", ")).Info("🔄 Configuration changes applied")
+	}
+	if len(restart) > 0 {
+		logger.WithField("keys", strings.Join(restart, ", ")).Warn("⚠️  Configuration changes need a restart")
+	}
+	newCfg.Restore(&r.current, restart)
+	r.current = *newCfg
+}
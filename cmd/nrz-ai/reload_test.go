@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/assistant"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+func TestConfigReloader_Apply(t *testing.T) {
+	conversation := ai.NewMockConversationManager()
+	aiService := ai.NewMockAIService()
+	service := whisper.NewMockWhisperService()
+	processor, err := assistant.New(assistant.Options{Whisper: service, AI: aiService, Conversation: conversation})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer processor.Close()
+
+	current := *config.DefaultConfig()
+	reloader := newConfigReloader(current, processor, aiService)
+
+	newCfg := current
+	newCfg.SystemPrompt = "Tu es Max"
+	newCfg.Language = "en"
+	newCfg.AudioSource = "hw:1"
+	// The mock backend cannot switch models, it needs a restart
+	newCfg.OllamaModel = "mistral"
+	edited := newCfg
+	reloader.Apply(&newCfg)
+
+	if prompt := conversation.ReplaceSystemPrompt(""); prompt != "Tu es Max" {
+		t.Errorf("Expected the new persona, got %q", prompt)
+	}
+	if processor.Language() != "en" || service.GetLanguage() != "en" {
+		t.Errorf("Expected the new language, got %s", processor.Language())
+	}
+	if reloader.current.SystemPrompt != "Tu es Max" || reloader.current.Language != "en" {
+		t.Errorf("Expected the applied settings to be current, got %+v", reloader.current)
+	}
+	if reloader.current.AudioSource != current.AudioSource || reloader.current.OllamaModel != current.OllamaModel {
+		t.Errorf("Expected the settings needing a restart to keep their value, got %s and %s",
+			reloader.current.AudioSource, reloader.current.OllamaModel)
+	}
+
+	// The next reload reports them again
+	again := edited
+	again.Language = "fr-x"
+	reloader.Apply(&again)
+	if changed := strings.Join(config.Diff(&reloader.current, &edited), ", "); changed != "audio_source, ollama_model" {
+		t.Errorf("Expected the restart settings to differ still, got %s", changed)
+	}
+	if reloader.current.Language != "en" {
+		t.Errorf("Expected an invalid language to keep the previous one, got %s", reloader.current.Language)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/internal/server"
+	"github.com/nerzhul/nrz-ai/internal/systemd"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/spf13/cobra"
+)
+
+func createServeCmd(cfg *config.Config) *cobra.Command {
+	var live bool
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the HTTP API server",
+		Long: `Expose the transcription and AI services over a local REST API:
+  GET  /            web dashboard: live captions, conversation and chat box
+  POST /transcribe  transcribe an uploaded audio file (?format=json|txt|srt|vtt&language=fr)
+  POST /chat        send {"message": "..."} to the AI
+  GET  /status      server status
+  GET  /healthz     liveness probe, answers while the process runs
+  GET  /readyz      readiness probe: model loaded, audio flowing (with --live), AI reachable
+  GET  /history     recent transcripts and AI conversation
+  GET  /events      Server-Sent Events of live pipeline events (with --live)
+  GET  /ws          WebSocket stream of live pipeline events (with --live)
+
+Set server_token, server_tls_cert/server_tls_key, server_client_ca or
+server_allowed_ips before listening beyond localhost.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if live {
+				// The microphone pipeline owns the process, the API runs alongside it
+				if status := runApp(*cfg, cfg.ServerAddr); status != 0 {
+					os.Exit(status)
+				}
+				return
+			}
+
+			whisperService := newWhisperService(*cfg)
+			if err := whisperService.LoadModel(cfg.WhisperModel); err != nil {
+				logger.WithError(err).Fatal("❌ Failed to load Whisper model")
+			}
+			defer whisperService.Close()
+
+			srv := server.NewServer(whisperService, audio.NewFFmpegDecoder(), cfg.Language, cfg.WhisperModel)
+			secureServer(srv, *cfg)
+			srv.SetRedactor(newRedactor(*cfg))
+			srv.SetBlocklist(newBlocklist(*cfg))
+			if aiService, conversation := newAIComponents(cfg); aiService != nil {
+				srv.SetAI(aiService, conversation)
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			startServer(ctx, srv, cfg.ServerAddr, func() {
+				notifyReady(fmt.Sprintf("Serving on %s", cfg.ServerAddr))
+				systemd.StartWatchdog(func() bool { return true }, ctx.Done())
+			})
+			systemd.Notify(systemd.Stopping)
+			fmt.Println("\n✅ Server stopped")
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.ServerAddr, "addr", cfg.ServerAddr, "Listen address")
+	cmd.Flags().BoolVar(&live, "live", false, "Also run the microphone pipeline and stream its events on /events and /ws")
+
+	return cmd
+}
+
+// startServer serves the API until ctx is done, exiting on listen errors.
+// onListening, if set, runs once the address is bound.
+func startServer(ctx context.Context, srv *server.Server, addr string, onListening func()) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		logger.WithError(err).Fatal("❌ API server failed")
+	}
+
+	scheme := "http"
+	if srv.TLSEnabled() {
+		scheme = "https"
+	}
+	if !srv.Authenticated() && !isLoopback(addr) {
+		logger.Warnf("⚠️  API server on %s without server_token nor client certificates, anyone on the network can use it", addr)
+	}
+	fmt.Printf("🌐 API server listening on %s://%s\n", scheme, addr)
+	fmt.Printf("📱 Dashboard: %s://%s/\n", scheme, addr)
+	if onListening != nil {
+		onListening()
+	}
+	if err := srv.Serve(ctx, listener); err != nil {
+		logger.WithError(err).Fatal("❌ API server failed")
+	}
+}
+
+// secureServer applies the access control settings of cfg to srv
+func secureServer(srv *server.Server, cfg config.Config) {
+	srv.SetAllowedOrigins(cfg.ServerAllowedOrigins)
+	srv.SetToken(cfg.ServerToken)
+	if err := srv.SetAllowedIPs(cfg.ServerAllowedIPs); err != nil {
+		logger.WithError(err).Fatal("❌ Invalid server_allowed_ips")
+	}
+	if cfg.ServerTLSCert != "" || cfg.ServerTLSKey != "" {
+		if err := srv.SetTLS(cfg.ServerTLSCert, cfg.ServerTLSKey, cfg.ServerClientCA); err != nil {
+			logger.WithError(err).Fatal("❌ Invalid server TLS settings")
+		}
+	} else if cfg.ServerClientCA != "" {
+		logger.WithField("server_client_ca", cfg.ServerClientCA).Fatal("❌ server_client_ca needs server_tls_cert and server_tls_key")
+	}
+}
+
+// isLoopback reports whether the listen address only accepts local clients
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
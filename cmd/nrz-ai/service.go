@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/internal/systemd"
+	"github.com/spf13/cobra"
+)
+
+func createInstallServiceCmd() *cobra.Command {
+	var user, force bool
+
+	cmd := &cobra.Command{
+		Use:   "install-service [-- nrz-ai flags]",
+		Short: "Install a systemd unit running nrz-ai as a daemon",
+		Long: `Write a Type=notify systemd unit starting this binary with --daemon.
+Flags after -- are added to the service command line, e.g.:
+  nrz-ai install-service --user -- --wake-word --ai --control`,
+		Run: func(cmd *cobra.Command, args []string) {
+			executable, err := os.Executable()
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Failed to locate the nrz-ai binary")
+			}
+			if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+				executable = resolved
+			}
+
+			path, err := systemd.UnitPath(user)
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Failed to locate the systemd unit directory")
+			}
+			if _, err := os.Stat(path); err == nil && !force {
+				logger.WithField("path", path).Fatal("❌ Unit already exists, use --force to overwrite it")
+			}
+
+			unit := systemd.UnitFile(systemd.UnitConfig{
+				ExecStart: append([]string{executable, "--daemon"}, args...),
+				User:      user,
+			})
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				logger.WithError(err).Fatal("❌ Failed to create the systemd unit directory")
+			}
+			if err := os.WriteFile(path, []byte(unit), 0644); err != nil {
+				logger.WithError(err).Fatal("❌ Failed to write the systemd unit")
+			}
+
+			systemctl := "systemctl"
+			if user {
+				systemctl = "systemctl --user"
+			}
+			fmt.Printf("✅ Installed %s\n", path)
+			fmt.Println("Enable and start it with:")
+			fmt.Printf("  %s daemon-reload\n", systemctl)
+			fmt.Printf("  %s enable --now %s\n", systemctl, systemd.UnitName)
+			if !user {
+				fmt.Println("⚠️  System services have no access to the user audio server, prefer --user")
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&user, "user", false, "Install a user service (~/.config/systemd/user) instead of a system one")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing unit")
+
+	return cmd
+}
+
+// notifyReady tells systemd the service is up, a no-op outside of systemd
+func notifyReady(status string) {
+	sent, err := systemd.Notify(systemd.Ready + "\n" + systemd.Status(status))
+	if err != nil {
+		logger.WithError(err).Warn("⚠️  Failed to notify systemd")
+		return
+	}
+	if sent {
+		logger.Debug("Notified systemd readiness")
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nerzhul/nrz-ai/internal/archive"
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+func createSessionsCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "List and export the archived session transcripts",
+		Long: `Every live session keeps its transcripts, speaker labels and AI answers in
+archive_dir, unless archive_enabled is false or the privacy mode is on. Sessions
+are named after their start time, "last" is the most recent one.`,
+	}
+	cmd.AddCommand(createSessionsListCmd(cfg))
+	cmd.AddCommand(createSessionsExportCmd(cfg))
+	cmd.AddCommand(createSessionsSummarizeCmd(cfg))
+	return cmd
+}
+
+func createSessionsListCmd(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the archived sessions",
+		Run: func(cmd *cobra.Command, args []string) {
+			sessions, err := archive.List(cfg.ArchiveDir)
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Failed to list archived sessions")
+			}
+			if len(sessions) == 0 {
+				fmt.Printf("No session archived in %s\n", cfg.ArchiveDir)
+				return
+			}
+
+			fmt.Printf("🗄️  Archived sessions (%s):\n", cfg.ArchiveDir)
+			for _, session := range sessions {
+				fmt.Printf("  • %s  %s, %s\n", session.ID, session.Mode, session.Language)
+			}
+		},
+	}
+}
+
+func createSessionsExportCmd(cfg *config.Config) *cobra.Command {
+	var formatName, out string
+
+	cmd := &cobra.Command{
+		Use:   "export <id|last>",
+		Short: "Export an archived session as text, JSON or Markdown",
+		Long: `Write the transcripts and AI answers of a session, with their timestamps and
+speaker labels, to stdout or --out.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			format, err := archive.ParseFormat(formatName)
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Invalid export format")
+			}
+			session, events, err := archive.Open(cfg.ArchiveDir, args[0])
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Failed to read the session")
+			}
+
+			w := os.Stdout
+			if out != "" {
+				file, err := os.Create(out)
+				if err != nil {
+					logger.WithError(err).Fatal("❌ Failed to create the export file")
+				}
+				defer file.Close()
+				w = file
+			}
+			if err := archive.Export(w, session, events, format); err != nil {
+				logger.WithError(err).Fatal("❌ Failed to export the session")
+			}
+			if out != "" {
+				fmt.Printf("✅ %s → %s\n", session.ID, out)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&formatName, "format", string(archive.FormatText), "Export format (txt, json, md)")
+	cmd.Flags().StringVar(&out, "out", "", "File to write instead of stdout")
+
+	return cmd
+}
+
+func createSessionsSummarizeCmd(cfg *config.Config) *cobra.Command {
+	var prompt string
+
+	cmd := &cobra.Command{
+		Use:   "summarize <id|last>",
+		Short: "Summarize an archived session with the AI",
+		Long: `Send the transcripts and AI answers of a session to the configured AI backend
+with session_summary_prompt, print the summary and save it next to the archive
+as <id>.summary.md.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			session, events, err := archive.Open(cfg.ArchiveDir, args[0])
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Failed to read the session")
+			}
+
+			// No point in a summary without AI, whatever the config says
+			cfg.AIEnabled = true
+			aiService, _ := newAIComponents(cfg)
+			if aiService == nil {
+				logger.WithField("ollama_url", cfg.OllamaURL).Fatal("❌ No AI backend available")
+			}
+			defer aiService.Close()
+
+			if prompt == "" {
+				prompt = cfg.SessionSummaryPrompt
+			}
+			fmt.Printf("🧠 Summarizing %s...\n", session.ID)
+			summary, err := archive.Summarize(aiService, prompt, events)
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Failed to summarize the session")
+			}
+			fmt.Printf("\n%s\n\n", summary)
+
+			path, err := archive.SaveSummary(cfg.ArchiveDir, session, summary)
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Failed to save the summary")
+			}
+			fmt.Printf("✅ Summary saved to %s\n", path)
+		},
+	}
+
+	cmd.Flags().StringVar(&prompt, "prompt", "", "Summarization prompt (default session_summary_prompt)")
+
+	return cmd
+}
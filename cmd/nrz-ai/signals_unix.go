@@ -0,0 +1,39 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/nerzhul/nrz-ai/pkg/assistant"
+)
+
+// handlePauseSignals pauses the assistant on SIGUSR1 and resumes it on
+// SIGUSR2 until the returned function is called
+func handlePauseSignals(processor *assistant.Assistant) func() {
+	sigusr := make(chan os.Signal, 1)
+	signal.Notify(sigusr, syscall.SIGUSR1, syscall.SIGUSR2)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case sig := <-sigusr:
+				if sig == syscall.SIGUSR1 {
+					processor.Pause()
+				} else {
+					processor.Resume()
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigusr)
+		close(done)
+	}
+}
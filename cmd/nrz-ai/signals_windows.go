@@ -0,0 +1,11 @@
+//go:build windows
+
+package main
+
+import "github.com/nerzhul/nrz-ai/pkg/assistant"
+
+// handlePauseSignals does nothing, Windows has no SIGUSR1 and SIGUSR2. The
+// assistant is paused with 'nrz-ai ctl pause' instead.
+func handlePauseSignals(processor *assistant.Assistant) func() {
+	return func() {}
+}
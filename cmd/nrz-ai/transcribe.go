@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/blocklist"
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/diarization"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/internal/pipeline"
+	"github.com/nerzhul/nrz-ai/internal/privacy"
+	"github.com/nerzhul/nrz-ai/internal/redact"
+	"github.com/nerzhul/nrz-ai/internal/transcript"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+	"github.com/spf13/cobra"
+)
+
+// supportedAudioExtensions lists the file extensions picked up in batch mode
+var supportedAudioExtensions = map[string]bool{
+	".wav":  true,
+	".mp3":  true,
+	".flac": true,
+	".ogg":  true,
+	".opus": true,
+	".m4a":  true,
+	".aac":  true,
+	".webm": true,
+	".mp4":  true,
+	".mkv":  true,
+}
+
+// maxAutoJobs bounds the files transcribed at once without --jobs, each
+// of them holding a copy of the model
+const maxAutoJobs = 4
+
+// batchJob is a single file to transcribe in batch mode
+type batchJob struct {
+	input  string
+	output string
+}
+
+// batchResult is the outcome of the job at index in the batch
+type batchResult struct {
+	index   int
+	err     error
+	elapsed time.Duration
+}
+
+// batchOptions are the settings shared by the files of a batch
+type batchOptions struct {
+	language string
+	format   transcript.Format
+	// Speakers are labeled when set
+	diarization *diarization.Config
+	// Personal data is masked when set
+	redactor *redact.Redactor
+	// Made up phrases are dropped when set
+	blocklist *blocklist.Blocklist
+	// Files are read and transcribed by windows of this length, starting
+	// overlap before the end of the previous one. 0 transcribes them whole.
+	window  time.Duration
+	overlap time.Duration
+}
+
+func createTranscribeCmd(cfg *config.Config) *cobra.Command {
+	var dir, outputDir, formatName string
+	var window, overlap time.Duration
+	var jobCount int
+
+	cmd := &cobra.Command{
+		Use:   "transcribe",
+		Short: "Transcribe all audio files in a directory",
+		Long: `Transcribe every supported audio file (wav, mp3, flac, ogg, opus, m4a, ...) found in a
+directory and write one transcript per file next to it, or in --output-dir.
+
+Recordings are decoded and transcribed by windows of --window, so that hour-long files
+never sit in memory as a whole. Every window starts --overlap before the end of the
+previous one and the words heard twice at the seams are merged.
+
+--jobs files are transcribed at once, each with its own copy of the model and its
+share of the CPU cores. Results are printed in file order as they complete.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := privacy.Check("transcript files"); err != nil {
+				logger.WithError(err).Fatal("❌ Cannot transcribe a directory")
+			}
+			format, err := transcript.ParseFormat(formatName)
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Invalid format")
+			}
+
+			if outputDir == "" {
+				outputDir = dir
+			}
+			if err := os.MkdirAll(outputDir, 0755); err != nil {
+				logger.WithError(err).Fatal("❌ Failed to create output directory")
+			}
+
+			jobs, err := listBatchJobs(dir, outputDir, format)
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Failed to list audio files")
+			}
+			if len(jobs) == 0 {
+				fmt.Printf("📂 No supported audio files found in %s\n", dir)
+				return
+			}
+
+			jobCount = batchJobs(jobCount, len(jobs))
+			services := make([]whisper.WhisperService, jobCount)
+			for i := range services {
+				service := newWhisperService(*cfg)
+				service.SetThreads(max(runtime.NumCPU()/jobCount, 1))
+				services[i] = service
+			}
+			whisperService := whisper.NewPool(services...)
+			if err := whisperService.LoadModel(cfg.WhisperModel); err != nil {
+				logger.WithError(err).Fatal("❌ Failed to load Whisper model")
+			}
+			defer whisperService.Close()
+
+			fmt.Printf("📂 Transcribing %d files from %s (%s, %d at once)\n", len(jobs), dir, format, jobCount)
+
+			options := batchOptions{
+				language:  cfg.Language,
+				format:    format,
+				redactor:  newRedactor(*cfg),
+				blocklist: newBlocklist(*cfg),
+				window:    window,
+				overlap:   overlap,
+			}
+			if cfg.DiarizationEnabled {
+				dc := newDiarizationConfig(*cfg)
+				options.diarization = &dc
+			}
+
+			failed := runBatch(jobs, audio.NewFFmpegDecoder(), whisperService, jobCount, options)
+			if failed > 0 {
+				logger.WithField("failed", failed).Fatalf("❌ %d of %d files failed", failed, len(jobs))
+			}
+			fmt.Printf("✅ %d files transcribed\n", len(jobs))
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", ".", "Directory containing audio files")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "Directory for transcripts (defaults to --dir)")
+	cmd.Flags().StringVar(&formatName, "format", "txt", "Transcript format (txt, json, srt, vtt)")
+	cmd.Flags().DurationVar(&window, "window", 5*time.Minute, "Length of the windows files are transcribed by (0 = whole files)")
+	cmd.Flags().DurationVar(&overlap, "overlap", 5*time.Second, "Audio shared by consecutive windows")
+	cmd.Flags().IntVar(&jobCount, "jobs", 0, "Files transcribed at once (0 = one per 4 CPU cores, up to 4)")
+
+	return cmd
+}
+
+// listBatchJobs returns the supported audio files of dir in name order
+func listBatchJobs(dir, outputDir string, format transcript.Format) ([]batchJob, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []batchJob
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || !supportedAudioExtensions[ext] {
+			continue
+		}
+
+		base := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		jobs = append(jobs, batchJob{
+			input:  filepath.Join(dir, entry.Name()),
+			output: filepath.Join(outputDir, base+"."+string(format)),
+		})
+	}
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].input < jobs[j].input })
+	return jobs, nil
+}
+
+// batchJobs returns the number of files to transcribe at once: requested,
+// or one per 4 CPU cores up to maxAutoJobs, and never more than files
+func batchJobs(requested, files int) int {
+	if requested <= 0 {
+		requested = min(max(runtime.NumCPU()/4, 1), maxAutoJobs)
+	}
+	return max(min(requested, files), 1)
+}
+
+// runBatch transcribes the files with workers in parallel, as many as the
+// Whisper service runs at once, and reports them in order. Returns the
+// number of failed files.
+func runBatch(jobs []batchJob, decoder audio.FileDecoder, service whisper.WhisperService, workers int, options batchOptions) int {
+	queue := make(chan int)
+	results := make(chan batchResult)
+	var wg sync.WaitGroup
+
+	for i := 0; i < min(workers, len(jobs)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range queue {
+				start := time.Now()
+				err := transcribeFile(jobs[index], decoder, service, options)
+				results <- batchResult{index: index, err: err, elapsed: time.Since(start)}
+			}
+		}()
+	}
+
+	go func() {
+		for index := range jobs {
+			queue <- index
+		}
+		close(queue)
+		wg.Wait()
+		close(results)
+	}()
+
+	return reportBatch(jobs, results)
+}
+
+// reportBatch prints the results in job order as they come, a result
+// waiting for an earlier file only updates the progress. Returns the number
+// of failed files.
+func reportBatch(jobs []batchJob, results <-chan batchResult) int {
+	pending := make(map[int]batchResult)
+	next, done, failed := 0, 0, 0
+
+	for result := range results {
+		done++
+		pending[result.index] = result
+		if _, ok := pending[next]; !ok {
+			fmt.Printf("⏳ [%d/%d] waiting for %s\n", done, len(jobs), jobs[next].input)
+			continue
+		}
+
+		for ; next < len(jobs); next++ {
+			result, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+
+			job := jobs[next]
+			if result.err != nil {
+				logger.WithError(result.err).WithField("file", job.input).Errorf("❌ [%d/%d] Transcription failed", done, len(jobs))
+				failed++
+				continue
+			}
+			fmt.Printf("✅ [%d/%d] %s → %s (%.1fs)\n", done, len(jobs), job.input, job.output, result.elapsed.Seconds())
+		}
+	}
+	return failed
+}
+
+// transcribeFile decodes, transcribes and writes the transcript of one file
+func transcribeFile(job batchJob, decoder audio.FileDecoder, service whisper.WhisperService, options batchOptions) error {
+	// Each file gets its own diarizer, speakers are not shared across recordings
+	var diarizer *diarization.ClusterDiarizer
+	if options.diarization != nil {
+		diarizer = diarization.NewClusterDiarizer(*options.diarization)
+	}
+
+	var result whisper.TranscriptionResult
+	streamer, streaming := decoder.(audio.StreamDecoder)
+	if streaming && options.window > 0 {
+		stream, err := streamer.DecodeStream(job.input)
+		if err != nil {
+			return err
+		}
+		defer stream.Close()
+
+		longForm := pipeline.NewLongForm(service, options.language, options.window, options.overlap, sampleRate)
+		if diarizer != nil {
+			longForm.SetLabeler(diarizer.Label)
+		}
+		if result, err = longForm.Transcribe(context.Background(), stream); err != nil {
+			return err
+		}
+	} else {
+		samples, err := decoder.DecodeFile(job.input)
+		if err != nil {
+			return err
+		}
+		if result, err = service.Transcribe(context.Background(), samples, options.language); err != nil {
+			return fmt.Errorf("failed to transcribe: %w", err)
+		}
+		if diarizer != nil {
+			result.Segments = diarizer.Label(samples, result.Segments)
+		}
+	}
+
+	if options.blocklist != nil {
+		result, _ = options.blocklist.Result(result)
+	}
+	if options.redactor != nil {
+		result = options.redactor.Result(result)
+	}
+
+	file, err := os.Create(job.output)
+	if err != nil {
+		return err
+	}
+	if err := transcript.Write(file, result, options.format); err != nil {
+		file.Close()
+		return err
+	}
+	// A full disk may only show when the file is flushed
+	return file.Close()
+}
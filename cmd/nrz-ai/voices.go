@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/config"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/internal/voices"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/spf13/cobra"
+)
+
+func createVoicesCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "voices",
+		Short: "Enroll the voices of the household for speaker identification",
+		Long: `Manage the voice prints used with --voices: each enrolled speaker is recognized
+by their voice and gets their own AI conversation, with an optional system
+prompt of their own. Speakers matching no voice print share the guest
+conversation. Voice prints are stored in voices_file.`,
+	}
+	cmd.AddCommand(createVoicesEnrollCmd(cfg))
+	cmd.AddCommand(createVoicesListCmd(cfg))
+	cmd.AddCommand(createVoicesRemoveCmd(cfg))
+	return cmd
+}
+
+// openVoices opens the voice prints file of cfg
+func openVoices(cfg config.Config) *voices.Store {
+	store, err := voices.Open(cfg.VoicesFile, sampleRate)
+	if err != nil {
+		logger.WithError(err).Fatal("❌ Failed to load voice prints")
+	}
+	store.SetThreshold(float64(cfg.VoicesThreshold))
+	return store
+}
+
+func createVoicesEnrollCmd(cfg *config.Config) *cobra.Command {
+	var duration time.Duration
+	var file, prompt string
+
+	cmd := &cobra.Command{
+		Use:   "enroll <name>",
+		Short: "Record a voice print, again to refine it",
+		Long: `Record name speaking for --duration from the audio source, or read --file, and
+add it to their voice print. Speak naturally, in a quiet room; enrolling a few
+recordings made on different days improves the recognition.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			store := openVoices(*cfg)
+
+			var samples []float32
+			var err error
+			if file != "" {
+				samples, err = audio.NewFFmpegDecoder().DecodeFile(file)
+			} else {
+				samples, err = recordVoice(*cfg, duration)
+			}
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Failed to read the voice recording")
+			}
+
+			voice, err := store.Enroll(args[0], samples, prompt)
+			if err != nil {
+				logger.WithError(err).Fatal("❌ Failed to enroll voice")
+			}
+			fmt.Printf("✅ Enrolled %s (%d recording(s)) in %s\n", voice.Name, voice.Recordings, cfg.VoicesFile)
+		},
+	}
+
+	cmd.Flags().DurationVar(&duration, "duration", 8*time.Second, "How long to record")
+	cmd.Flags().StringVar(&file, "file", "", "Audio file to enroll instead of recording")
+	cmd.Flags().StringVar(&prompt, "prompt", "", "System prompt of this speaker's conversation (empty = keep, or --system-prompt)")
+
+	return cmd
+}
+
+// recordVoice captures duration of audio from the configured source
+func recordVoice(cfg config.Config, duration time.Duration) ([]float32, error) {
+	stream, err := newCapture(cfg).StartCapture(cfg.AudioSource)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	fmt.Printf("🔴 Speak for %s...\n", duration)
+	decoder := audio.NewFrameDecoder(audio.NewProcessor())
+	want := int(duration.Seconds() * sampleRate)
+	samples := make([]float32, 0, want)
+	buffer := make([]byte, readChunkSize)
+	for len(samples) < want {
+		n, err := stream.Read(buffer)
+		samples = append(samples, decoder.Decode(buffer[:n])...)
+		if err != nil {
+			break
+		}
+	}
+	return samples, nil
+}
+
+func createVoicesListCmd(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the enrolled voices",
+		Run: func(cmd *cobra.Command, args []string) {
+			enrolled := openVoices(*cfg).Voices()
+			if len(enrolled) == 0 {
+				fmt.Println("No voice enrolled, add one with: nrz-ai voices enroll <name>")
+				return
+			}
+
+			fmt.Printf("🗣️  Enrolled voices (%s):\n", cfg.VoicesFile)
+			for _, voice := range enrolled {
+				line := fmt.Sprintf("  • %s (%d recording(s))", voice.Name, voice.Recordings)
+				if voice.Prompt != "" {
+					line += "  prompt: " + voice.Prompt
+				}
+				fmt.Println(line)
+			}
+		},
+	}
+}
+
+func createVoicesRemoveCmd(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Forget the voice print of a speaker",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := openVoices(*cfg).Remove(args[0]); err != nil {
+				logger.WithError(err).Fatal("❌ Failed to remove voice")
+			}
+			fmt.Printf("🗑️  Removed %s\n", args[0])
+		},
+	}
+}
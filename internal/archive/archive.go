@@ -0,0 +1,192 @@
+// Package archive keeps the transcripts and AI answers of every live
+// session in a JSON Lines file, so they can be listed and exported once the
+// terminal has scrolled away
+package archive
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/privacy"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+// ext is the extension of session archives
+const ext = ".jsonl"
+
+// Latest names the most recent session
+const Latest = "last"
+
+// ErrNotFound is returned for a session that is not archived
+var ErrNotFound = errors.New("session not found")
+
+// Session describes an archived session, the first line of its file
+type Session struct {
+	ID       string    `json:"id"`
+	Start    time.Time `json:"start"`
+	Mode     string    `json:"mode,omitempty"`
+	Language string    `json:"language,omitempty"`
+}
+
+// NewID returns the ID of a session started at start
+func NewID(start time.Time) string {
+	return start.Format("2006-01-02-150405")
+}
+
+// File returns the archive file of the session id in dir
+func File(dir, id string) string {
+	return filepath.Join(dir, strings.TrimSuffix(id, ext)+ext)
+}
+
+// Writer is an output.Emitter appending the transcripts and AI answers of
+// a session to its archive
+type Writer struct {
+	file    *os.File
+	encoder *json.Encoder
+	events  int
+	mutex   sync.Mutex
+}
+
+// Create starts the archive of session in dir
+func Create(dir string, session Session) (*Writer, error) {
+	if err := privacy.Check("transcript archive"); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	path := File(dir, session.ID)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Writer{file: file, encoder: json.NewEncoder(file)}
+	if err := w.encoder.Encode(session); err != nil {
+		file.Close()
+		os.Remove(path)
+		return nil, err
+	}
+	return w, nil
+}
+
+// Path returns the archive file path
+func (w *Writer) Path() string {
+	return w.file.Name()
+}
+
+// Emit archives transcript, translation and AI response events, other
+// events are ignored
+func (w *Writer) Emit(event output.Event) error {
+	if event.Type != output.EventTranscript && event.Type != output.EventTranslation && event.Type != output.EventAIResponse {
+		return nil
+	}
+	if strings.TrimSpace(event.Text) == "" {
+		return nil
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.events++
+	return w.encoder.Encode(event)
+}
+
+// Close closes the archive, removing it when nothing was said
+func (w *Writer) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	err := w.file.Close()
+	if w.events == 0 {
+		return os.Remove(w.file.Name())
+	}
+	return err
+}
+
+// List returns the sessions archived in dir, oldest first
+func List(dir string) ([]Session, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+ext))
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]Session, 0, len(paths))
+	for _, path := range paths {
+		session, err := readHeader(path)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Start.Before(sessions[j].Start) })
+	return sessions, nil
+}
+
+// Open reads the session id archived in dir, Latest for the most recent
+func Open(dir, id string) (Session, []output.Event, error) {
+	if id == Latest {
+		sessions, err := List(dir)
+		if err != nil {
+			return Session{}, nil, err
+		}
+		if len(sessions) == 0 {
+			return Session{}, nil, fmt.Errorf("%w: no session archived in %s", ErrNotFound, dir)
+		}
+		id = sessions[len(sessions)-1].ID
+	}
+
+	file, err := os.Open(File(dir, id))
+	if errors.Is(err, os.ErrNotExist) {
+		return Session{}, nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return Session{}, nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var session Session
+	var events []output.Event
+	for line := 0; scanner.Scan(); line++ {
+		if line == 0 {
+			if err := json.Unmarshal(scanner.Bytes(), &session); err != nil {
+				return Session{}, nil, fmt.Errorf("invalid archive header in %s: %w", file.Name(), err)
+			}
+			continue
+		}
+		var event output.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			// A line cut by a crash, keep what was archived before
+			break
+		}
+		events = append(events, event)
+	}
+	return session, events, scanner.Err()
+}
+
+// readHeader reads the session line of an archive
+func readHeader(path string) (Session, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Session{}, err
+	}
+	defer file.Close()
+
+	var session Session
+	if err := json.NewDecoder(file).Decode(&session); err != nil {
+		return Session{}, fmt.Errorf("invalid archive header in %s: %w", path, err)
+	}
+	return session, nil
+}
@@ -0,0 +1,167 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+// archiveSession archives the events of a session started at start
+func archiveSession(t *testing.T, dir string, start time.Time, events ...output.Event) Session {
+	t.Helper()
+
+	session := Session{ID: NewID(start), Start: start, Mode: "assistant", Language: "fr"}
+	w, err := Create(dir, session)
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	for _, event := range events {
+		if err := w.Emit(event); err != nil {
+			t.Fatalf("Failed to archive event: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close archive: %v", err)
+	}
+	return session
+}
+
+func TestWriter_ArchivesTranscriptsAndAnswers(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 6, 12, 9, 30, 0, 0, time.UTC)
+	archiveSession(t, dir, start,
+		output.Event{Type: output.EventTranscript, Time: start.Add(time.Second), Text: " Quelle heure est-il ? ", Speaker: "Alice"},
+		output.Event{Type: output.EventVAD, State: "speech"},
+		output.Event{Type: output.EventAIResponse, Time: start.Add(3 * time.Second), Text: "Il est 9 h 30."},
+	)
+
+	session, events, err := Open(dir, "2024-06-12-093000")
+	if err != nil {
+		t.Fatalf("Failed to open archive: %v", err)
+	}
+	if session.Mode != "assistant" || !session.Start.Equal(start) {
+		t.Errorf("Expected the session header, got %+v", session)
+	}
+	if len(events) != 2 || events[0].Speaker != "Alice" || events[1].Type != output.EventAIResponse {
+		t.Errorf("Expected the transcript and answer only, got %+v", events)
+	}
+}
+
+func TestWriter_RemovesEmptySession(t *testing.T) {
+	dir := t.TempDir()
+	archiveSession(t, dir, time.Now(), output.Event{Type: output.EventTranscript, Text: "  "})
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected no archive for a silent session, got %d files", len(entries))
+	}
+}
+
+func TestListAndLatest(t *testing.T) {
+	dir := t.TempDir()
+	first := time.Date(2024, 6, 12, 9, 0, 0, 0, time.UTC)
+	archiveSession(t, dir, first.Add(time.Hour), output.Event{Type: output.EventTranscript, Text: "second"})
+	archiveSession(t, dir, first, output.Event{Type: output.EventTranscript, Text: "first"})
+
+	sessions, err := List(dir)
+	if err != nil || len(sessions) != 2 || sessions[0].ID != "2024-06-12-090000" {
+		t.Fatalf("Expected two sessions oldest first, got %+v, %v", sessions, err)
+	}
+
+	_, events, err := Open(dir, Latest)
+	if err != nil || len(events) != 1 || events[0].Text != "second" {
+		t.Errorf("Expected the latest session, got %+v, %v", events, err)
+	}
+	if _, _, err := Open(dir, "2020-01-01-000000"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if _, _, err := Open(t.TempDir(), Latest); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound without sessions, got %v", err)
+	}
+}
+
+func TestExport(t *testing.T) {
+	start := time.Date(2024, 6, 12, 9, 30, 0, 0, time.UTC)
+	session := Session{ID: NewID(start), Start: start, Mode: "assistant", Language: "fr"}
+	events := []output.Event{
+		{Type: output.EventTranscript, Time: start.Add(time.Second), Text: "Quelle heure est-il ?"},
+		{Type: output.EventAIResponse, Time: start.Add(3 * time.Second), Text: "Il est 9 h 30."},
+	}
+
+	var text bytes.Buffer
+	if err := Export(&text, session, events, FormatText); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "[09:30:01] You: Quelle heure est-il ?\n[09:30:03] AI: Il est 9 h 30.\n"; text.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, text.String())
+	}
+
+	var markdown bytes.Buffer
+	if err := Export(&markdown, session, events, FormatMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	for _, part := range []string{"# Session 2024-06-12 09:30\n", "Mode: assistant · Language: fr", "**09:30:01 · You**\nQuelle heure", "**09:30:03 · AI**\n> Il est 9 h 30."} {
+		if !strings.Contains(markdown.String(), part) {
+			t.Errorf("Expected %q in the Markdown export:\n%s", part, markdown.String())
+		}
+	}
+
+	var exported bytes.Buffer
+	if err := Export(&exported, session, events, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	var decoded jsonExport
+	if err := json.Unmarshal(exported.Bytes(), &decoded); err != nil || len(decoded.Events) != 2 || decoded.Session.ID != session.ID {
+		t.Errorf("Expected the session and events in JSON, got %s (%v)", exported.String(), err)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat("Markdown"); err != nil || format != FormatMarkdown {
+		t.Errorf("Expected md, got %q, %v", format, err)
+	}
+	if _, err := ParseFormat("srt"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 6, 12, 9, 30, 0, 0, time.UTC)
+	session := archiveSession(t, dir, start,
+		output.Event{Type: output.EventTranscript, Time: start.Add(time.Second), Text: "Rappelle-moi d'appeler le plombier"},
+		output.Event{Type: output.EventAIResponse, Time: start.Add(3 * time.Second), Text: "C'est noté."})
+	_, events, err := Open(dir, session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service := ai.NewMockAIService()
+	service.SetResponses([]ai.ChatResponse{{Message: ai.Message{Role: "assistant", Content: " - Appeler le plombier \n"}, Done: true}})
+	summary, err := Summarize(service, "Résume.", events)
+	if err != nil || summary != "- Appeler le plombier" {
+		t.Fatalf("Expected the trimmed summary, got %q (%v)", summary, err)
+	}
+
+	path, err := SaveSummary(dir, session, summary)
+	if err != nil {
+		t.Fatalf("Failed to save the summary: %v", err)
+	}
+	content, _ := os.ReadFile(path)
+	if expected := "# Summary of session 2024-06-12 09:30\n\n- Appeler le plombier\n"; string(content) != expected {
+		t.Errorf("Expected %q, got %q", expected, content)
+	}
+	if sessions, err := List(dir); err != nil || len(sessions) != 1 {
+		t.Errorf("Expected the summary not to be listed as a session, got %+v (%v)", sessions, err)
+	}
+
+	if _, err := Summarize(service, "Résume.", nil); err == nil {
+		t.Error("Expected an error for an empty session")
+	}
+}
@@ -0,0 +1,111 @@
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+// Format identifies an export format
+type Format string
+
+const (
+	FormatText     Format = "txt"
+	FormatJSON     Format = "json"
+	FormatMarkdown Format = "md"
+)
+
+// ParseFormat validates an export format name
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(name)) {
+	case FormatText, FormatJSON, FormatMarkdown:
+		return Format(strings.ToLower(name)), nil
+	case "markdown":
+		return FormatMarkdown, nil
+	default:
+		return "", fmt.Errorf("unknown export format '%s' (expected txt, json or md)", name)
+	}
+}
+
+// jsonExport is the JSON representation of an archived session
+type jsonExport struct {
+	Session Session        `json:"session"`
+	Events  []output.Event `json:"events"`
+}
+
+// Export writes an archived session to w in the given format
+func Export(w io.Writer, session Session, events []output.Event, format Format) error {
+	switch format {
+	case FormatText:
+		return exportText(w, events)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(jsonExport{Session: session, Events: events})
+	case FormatMarkdown:
+		return exportMarkdown(w, session, events)
+	default:
+		return fmt.Errorf("unsupported export format '%s'", format)
+	}
+}
+
+// exportText writes one timestamped line per transcript or answer
+func exportText(w io.Writer, events []output.Event) error {
+	for _, event := range events {
+		_, err := fmt.Fprintf(w, "[%s] %s: %s\n", event.Time.Format("15:04:05"), Author(event), strings.TrimSpace(event.Text))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportMarkdown writes a titled document with one paragraph per
+// transcript or answer
+func exportMarkdown(w io.Writer, session Session, events []output.Event) error {
+	if _, err := fmt.Fprintf(w, "# Session %s\n\n", session.Start.Format("2006-01-02 15:04")); err != nil {
+		return err
+	}
+	var details []string
+	if session.Mode != "" {
+		details = append(details, "Mode: "+session.Mode)
+	}
+	if session.Language != "" {
+		details = append(details, "Language: "+session.Language)
+	}
+	if len(details) > 0 {
+		if _, err := fmt.Fprintf(w, "%s\n\n", strings.Join(details, " · ")); err != nil {
+			return err
+		}
+	}
+
+	for _, event := range events {
+		text := strings.TrimSpace(event.Text)
+		if event.Type == output.EventAIResponse {
+			// Quote the answers so they stand out from what was said
+			text = "> " + strings.ReplaceAll(text, "\n", "\n> ")
+		}
+		_, err := fmt.Fprintf(w, "**%s · %s**\n%s\n\n", event.Time.Format("15:04:05"), Author(event), text)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Author names the author of an archived event
+func Author(event output.Event) string {
+	switch {
+	case event.Type == output.EventAIResponse:
+		return "AI"
+	case event.Type == output.EventTranslation:
+		return "Translation (" + event.Language + ")"
+	case event.Speaker != "":
+		return event.Speaker
+	default:
+		return "You"
+	}
+}
@@ -0,0 +1,64 @@
+package archive
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nerzhul/nrz-ai/internal/privacy"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+// summaryExt is the extension of session summaries, next to their archive
+const summaryExt = ".summary.md"
+
+// SummaryFile returns the summary file of the session id in dir
+func SummaryFile(dir, id string) string {
+	return filepath.Join(dir, strings.TrimSuffix(id, ext)+summaryExt)
+}
+
+// Summarize asks service for a summary of the archived events, prompt
+// being the system prompt telling how to summarize
+func Summarize(service ai.AIService, prompt string, events []output.Event) (string, error) {
+	var transcript strings.Builder
+	if err := exportText(&transcript, events); err != nil {
+		return "", err
+	}
+	if transcript.Len() == 0 {
+		return "", fmt.Errorf("nothing to summarize")
+	}
+
+	response, err := service.Chat(ai.ChatRequest{
+		Messages: []ai.Message{
+			{Role: "system", Content: prompt},
+			{Role: "user", Content: transcript.String()},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize session: %w", err)
+	}
+	if response.Error != "" {
+		return "", fmt.Errorf("failed to summarize session: %s", response.Error)
+	}
+	summary := strings.TrimSpace(response.Message.Content)
+	if summary == "" {
+		return "", fmt.Errorf("failed to summarize session: empty answer")
+	}
+	return summary, nil
+}
+
+// SaveSummary writes the summary of session next to its archive in dir,
+// replacing a previous one, and returns its path
+func SaveSummary(dir string, session Session, summary string) (string, error) {
+	if err := privacy.Check("session summary"); err != nil {
+		return "", err
+	}
+	path := SummaryFile(dir, session.ID)
+	content := fmt.Sprintf("# Summary of session %s\n\n%s\n", session.Start.Format("2006-01-02 15:04"), summary)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,151 @@
+// Package blocklist drops the phrases Whisper makes up from silence or
+// noise, such as subtitle credits, before transcripts are shown or answered
+package blocklist
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+// Defaults are the phrases Whisper is known to hallucinate, by language.
+// They come from the subtitles of its training data.
+var Defaults = map[string][]string{
+	"fr": {
+		"Sous-titres réalisés par la communauté d'Amara.org",
+		"Sous-titres réalisés para la communauté d'Amara.org",
+		"Sous-titrage Société Radio-Canada",
+		"Sous-titrage ST' 501",
+		"Merci d'avoir regardé cette vidéo",
+		"Abonnez-vous",
+		"Merci.",
+	},
+	"en": {
+		"Subtitles by the Amara.org community",
+		"Thank you for watching",
+		"Thanks for watching",
+		"Please subscribe",
+	},
+	"de": {
+		"Untertitel der Amara.org-Community",
+		"Untertitel im Auftrag des ZDF für funk, 2017",
+		"Vielen Dank fürs Zuschauen",
+	},
+	"es": {
+		"Subtítulos realizados por la comunidad de Amara.org",
+		"Gracias por ver el video",
+	},
+}
+
+// Blocklist drops the transcripts and segments matching its phrases or
+// patterns
+type Blocklist struct {
+	phrases  map[string]bool            // For every language
+	defaults map[string]map[string]bool // By language
+	patterns []*regexp.Regexp
+}
+
+// New creates a blocklist of the defaults, when enabled, and of phrases,
+// matched whole ignoring case, spaces and the surrounding punctuation, and
+// patterns, regular expressions matching anywhere in the text
+func New(defaults bool, phrases []string, patterns []string) (*Blocklist, error) {
+	b := &Blocklist{
+		phrases:  normalizeAll(phrases),
+		defaults: make(map[string]map[string]bool),
+	}
+	if defaults {
+		for language, list := range Defaults {
+			b.defaults[language] = normalizeAll(list)
+		}
+	}
+	for _, pattern := range patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blocklist pattern '%s': %w", pattern, err)
+		}
+		b.patterns = append(b.patterns, compiled)
+	}
+	return b, nil
+}
+
+// Blocked reports whether text is a blocked phrase in language, the
+// defaults of every language applying when it is unknown
+func (b *Blocklist) Blocked(text, language string) bool {
+	phrase := normalize(text)
+	if phrase == "" {
+		return false
+	}
+	if b.phrases[phrase] {
+		return true
+	}
+	if defaults, ok := b.defaults[language]; ok {
+		if defaults[phrase] {
+			return true
+		}
+	} else {
+		for _, defaults := range b.defaults {
+			if defaults[phrase] {
+				return true
+			}
+		}
+	}
+	for _, pattern := range b.patterns {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Result drops the blocked segments of a transcription, and its whole text
+// when it is blocked. It returns the dropped texts.
+func (b *Blocklist) Result(result whisper.TranscriptionResult) (whisper.TranscriptionResult, []string) {
+	if b.Blocked(result.Text, result.Language) {
+		dropped := strings.TrimSpace(result.Text)
+		result.Text = ""
+		result.Segments = nil
+		return result, []string{dropped}
+	}
+
+	var dropped []string
+	var text strings.Builder
+	segments := make([]whisper.Segment, 0, len(result.Segments))
+	for _, segment := range result.Segments {
+		if b.Blocked(segment.Text, result.Language) {
+			dropped = append(dropped, strings.TrimSpace(segment.Text))
+			continue
+		}
+		text.WriteString(segment.Text)
+		segments = append(segments, segment)
+	}
+	if len(dropped) == 0 {
+		return result, nil
+	}
+	result.Text = text.String()
+	result.Segments = segments
+	return result, dropped
+}
+
+// normalizeAll normalizes phrases into a set
+func normalizeAll(phrases []string) map[string]bool {
+	set := make(map[string]bool, len(phrases))
+	for _, phrase := range phrases {
+		if normalized := normalize(phrase); normalized != "" {
+			set[normalized] = true
+		}
+	}
+	return set
+}
+
+// normalize lower cases text, collapses its spaces, unifies its apostrophes
+// and trims the punctuation around it
+func normalize(text string) string {
+	text = strings.ReplaceAll(strings.ToLower(text), "’", "'")
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.TrimFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,88 @@
+package blocklist
+
+import (
+	"testing"
+
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+func TestBlocked_Defaults(t *testing.T) {
+	blocklist, err := New(true, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create blocklist: %v", err)
+	}
+
+	tests := []struct {
+		text     string
+		language string
+		blocked  bool
+	}{
+		{" Sous-titres réalisés par la communauté d’Amara.org ", "fr", true},
+		{"Merci.", "fr", true},
+		{"MERCI !", "fr", true},
+		{"Merci beaucoup pour ton aide.", "fr", false},
+		{"Thanks for watching!", "en", true},
+		{"Thanks for watching!", "fr", false},
+		{"Thanks for watching!", "", true},
+		{"...", "fr", false},
+	}
+	for _, tt := range tests {
+		if blocked := blocklist.Blocked(tt.text, tt.language); blocked != tt.blocked {
+			t.Errorf("%q in %q: expected blocked %v, got %v", tt.text, tt.language, tt.blocked, blocked)
+		}
+	}
+}
+
+func TestBlocked_PhrasesAndPatterns(t *testing.T) {
+	blocklist, err := New(false, []string{"Bon appétit"}, []string{`(?i)www\.\w+\.com`})
+	if err != nil {
+		t.Fatalf("Failed to create blocklist: %v", err)
+	}
+
+	if !blocklist.Blocked("Bon appétit !", "en") {
+		t.Error("Expected the phrase blocked in every language")
+	}
+	if !blocklist.Blocked("Retrouvez-nous sur WWW.EXEMPLE.COM", "fr") {
+		t.Error("Expected the pattern to match anywhere")
+	}
+	if blocklist.Blocked("Merci.", "fr") {
+		t.Error("Expected no default without the defaults")
+	}
+
+	if _, err := New(false, nil, []string{"("}); err == nil {
+		t.Error("Expected an invalid pattern to fail")
+	}
+}
+
+func TestResult_DropsBlockedSegments(t *testing.T) {
+	blocklist, _ := New(true, nil, nil)
+	result := whisper.TranscriptionResult{
+		Text:     " Il fait beau. Sous-titres réalisés par la communauté d'Amara.org",
+		Language: "fr",
+		Segments: []whisper.Segment{
+			{Text: " Il fait beau.", End: 1},
+			{Text: " Sous-titres réalisés par la communauté d'Amara.org", Start: 1, End: 2},
+		},
+	}
+
+	filtered, dropped := blocklist.Result(result)
+	if filtered.Text != " Il fait beau." || len(filtered.Segments) != 1 {
+		t.Errorf("Expected only the first segment, got %+v", filtered)
+	}
+	if len(dropped) != 1 {
+		t.Errorf("Expected the credits dropped, got %v", dropped)
+	}
+
+	filtered, dropped = blocklist.Result(whisper.TranscriptionResult{
+		Text:     " Merci.",
+		Language: "fr",
+		Segments: []whisper.Segment{{Text: " Merci.", End: 1}},
+	})
+	if filtered.Text != "" || len(filtered.Segments) != 0 || len(dropped) != 1 {
+		t.Errorf("Expected the whole transcript dropped, got %+v (%v)", filtered, dropped)
+	}
+
+	if _, dropped := blocklist.Result(whisper.TranscriptionResult{Text: " Bonjour", Language: "fr"}); dropped != nil {
+		t.Errorf("Expected nothing dropped, got %v", dropped)
+	}
+}
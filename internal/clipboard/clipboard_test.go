@@ -0,0 +1,54 @@
+package clipboard
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := map[string]Target{
+		"":           TargetOff,
+		"off":        TargetOff,
+		"transcript": TargetTranscript,
+		"ai":         TargetAI,
+	}
+	for name, expected := range tests {
+		target, err := ParseTarget(name)
+		if err != nil {
+			t.Errorf("Expected no error for %q, got: %v", name, err)
+		}
+		if target != expected {
+			t.Errorf("Expected target %q for %q, got %q", expected, name, target)
+		}
+	}
+
+	if _, err := ParseTarget("printer"); err == nil {
+		t.Error("Expected error for unknown target")
+	}
+}
+
+func TestNewClipboard_UnknownBackend(t *testing.T) {
+	if _, err := NewClipboard("pbcopy"); err == nil {
+		t.Error("Expected error for unknown backend")
+	}
+}
+
+func TestMockClipboard(t *testing.T) {
+	var cb Clipboard = NewMockClipboard()
+	if err := cb.Copy("Bonjour"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	mock := cb.(*MockClipboard)
+	if mock.Content() != "Bonjour" || mock.Copies() != 1 {
+		t.Errorf("Expected one copy of 'Bonjour', got %d copies of %q", mock.Copies(), mock.Content())
+	}
+
+	mock.SetCopyError(errors.New("no display"))
+	if err := cb.Copy("Salut"); err == nil {
+		t.Error("Expected error when copy fails")
+	}
+	if mock.Content() != "Bonjour" {
+		t.Errorf("Expected content unchanged after failure, got %q", mock.Content())
+	}
+}
@@ -0,0 +1,94 @@
+package clipboard
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Target selects which text is copied to the clipboard
+type Target string
+
+const (
+	TargetOff        Target = "off"
+	TargetTranscript Target = "transcript"
+	TargetAI         Target = "ai"
+)
+
+// ParseTarget validates a clipboard target name
+func ParseTarget(name string) (Target, error) {
+	switch Target(name) {
+	case "":
+		return TargetOff, nil
+	case TargetOff, TargetTranscript, TargetAI:
+		return Target(name), nil
+	default:
+		return "", fmt.Errorf("unknown clipboard target '%s' (expected off, transcript or ai)", name)
+	}
+}
+
+// CommandClipboard implements Clipboard by piping text to a clipboard tool
+type CommandClipboard struct {
+	name string
+	args []string
+}
+
+// backends maps supported tools to their arguments
+var backends = map[string][]string{
+	"wl-copy": {},
+	"xclip":   {"-selection", "clipboard"},
+	"xsel":    {"--clipboard", "--input"},
+}
+
+// NewClipboard creates a clipboard for the given backend. "auto" picks
+// wl-copy on Wayland and xclip or xsel on X11, whichever is installed.
+func NewClipboard(backend string) (*CommandClipboard, error) {
+	if backend == "auto" {
+		return detectClipboard()
+	}
+
+	args, ok := backends[backend]
+	if !ok {
+		return nil, fmt.Errorf("unknown clipboard backend '%s' (expected auto, wl-copy, xclip or xsel)", backend)
+	}
+	if _, err := exec.LookPath(backend); err != nil {
+		return nil, fmt.Errorf("clipboard backend '%s' not found in PATH", backend)
+	}
+
+	return &CommandClipboard{name: backend, args: args}, nil
+}
+
+// detectClipboard returns the first available backend for the current session
+func detectClipboard() (*CommandClipboard, error) {
+	candidates := []string{"xclip", "xsel"}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		candidates = []string{"wl-copy"}
+	}
+
+	for _, name := range candidates {
+		if _, err := exec.LookPath(name); err == nil {
+			return &CommandClipboard{name: name, args: backends[name]}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no clipboard backend found, install one of: %s", strings.Join(candidates, ", "))
+}
+
+// Copy pipes text to the backend
+func (c *CommandClipboard) Copy(text string) error {
+	cmd := exec.Command(c.name, c.args...)
+	cmd.Stdin = strings.NewReader(text)
+
+	// No output capture: wl-copy and xclip fork to keep serving the
+	// selection and would hold a captured pipe open.
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", c.name, err)
+	}
+	return nil
+}
+
+// Name returns the backend name
+func (c *CommandClipboard) Name() string {
+	return c.name
+}
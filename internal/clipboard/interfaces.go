@@ -0,0 +1,10 @@
+package clipboard
+
+// Clipboard copies text to the system clipboard
+type Clipboard interface {
+	// Copy replaces the clipboard content with text
+	Copy(text string) error
+
+	// Name returns the backend name
+	Name() string
+}
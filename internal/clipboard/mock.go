@@ -0,0 +1,43 @@
+package clipboard
+
+// MockClipboard implements Clipboard for testing
+type MockClipboard struct {
+	content   string
+	copies    int
+	copyError error
+}
+
+// NewMockClipboard creates a new mock clipboard
+func NewMockClipboard() *MockClipboard {
+	return &MockClipboard{}
+}
+
+// SetCopyError sets an error to return for Copy calls
+func (m *MockClipboard) SetCopyError(err error) {
+	m.copyError = err
+}
+
+// Content returns the last copied text
+func (m *MockClipboard) Content() string {
+	return m.content
+}
+
+// Copies returns the number of successful Copy calls
+func (m *MockClipboard) Copies() int {
+	return m.copies
+}
+
+// Copy stores the text
+func (m *MockClipboard) Copy(text string) error {
+	if m.copyError != nil {
+		return m.copyError
+	}
+	m.content = text
+	m.copies++
+	return nil
+}
+
+// Name returns the mock name
+func (m *MockClipboard) Name() string {
+	return "mock"
+}
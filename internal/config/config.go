@@ -1,10 +1,16 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
@@ -15,20 +21,223 @@ type Config struct {
 	Language     string `mapstructure:"language" yaml:"language"`
 	AudioSource  string `mapstructure:"audio_source" yaml:"audio_source"`
 
+	// Whisper model picked for the detected hardware, unless --model is given
+	AutoModel bool `mapstructure:"auto_model" yaml:"auto_model"`
+
+	// Switching to the spoken language when transcribing with language auto
+	LanguageSwitch int               `mapstructure:"language_switch" yaml:"language_switch"`
+	SystemPrompts  map[string]string `mapstructure:"system_prompts" yaml:"system_prompts"`
+
+	// PCM format of a pipe: or unix: audio source
+	PipeFormat     string `mapstructure:"pipe_format" yaml:"pipe_format"`
+	PipeSampleRate int    `mapstructure:"pipe_sample_rate" yaml:"pipe_sample_rate"`
+	PipeChannels   int    `mapstructure:"pipe_channels" yaml:"pipe_channels"`
+
+	// ffmpeg binary, and capture of the devices the platform input misses
+	FFmpegPath        string   `mapstructure:"ffmpeg_path" yaml:"ffmpeg_path"`
+	FFmpegInputFormat string   `mapstructure:"ffmpeg_input_format" yaml:"ffmpeg_input_format"`
+	FFmpegExtraArgs   []string `mapstructure:"ffmpeg_extra_args" yaml:"ffmpeg_extra_args"`
+	FFmpegQueueSize   int      `mapstructure:"ffmpeg_queue_size" yaml:"ffmpeg_queue_size"`
+
+	// Capture restarted when the source stops sending audio
+	CaptureStallTimeout time.Duration `mapstructure:"capture_stall_timeout" yaml:"capture_stall_timeout"`
+
+	// Whisper decoding
+	BeamSize         int     `mapstructure:"whisper_beam_size" yaml:"whisper_beam_size"`
+	Temperature      float32 `mapstructure:"whisper_temperature" yaml:"whisper_temperature"`
+	EntropyThreshold float32 `mapstructure:"whisper_entropy_threshold" yaml:"whisper_entropy_threshold"`
+	MaxSegmentLength uint    `mapstructure:"whisper_max_segment_length" yaml:"whisper_max_segment_length"`
+
+	// Voice activity detection
+	VADThreshold   float32 `mapstructure:"vad_threshold" yaml:"vad_threshold"`
+	VADSilenceMs   int     `mapstructure:"vad_silence_ms" yaml:"vad_silence_ms"`
+	VADMinSpeechMs int     `mapstructure:"vad_min_speech_ms" yaml:"vad_min_speech_ms"`
+	VADMaxSpeechS  int     `mapstructure:"vad_max_speech_s" yaml:"vad_max_speech_s"`
+
+	// Speaker diarization
+	DiarizationEnabled     bool    `mapstructure:"diarization_enabled" yaml:"diarization_enabled"`
+	DiarizationThreshold   float32 `mapstructure:"diarization_threshold" yaml:"diarization_threshold"`
+	DiarizationMaxSpeakers int     `mapstructure:"diarization_max_speakers" yaml:"diarization_max_speakers"`
+
+	// Speaker identification with per-speaker conversations
+	VoicesEnabled   bool    `mapstructure:"voices_enabled" yaml:"voices_enabled"`
+	VoicesFile      string  `mapstructure:"voices_file" yaml:"voices_file"`
+	VoicesThreshold float32 `mapstructure:"voices_threshold" yaml:"voices_threshold"`
+
+	// Redaction of personal data in transcripts
+	RedactEnabled  bool     `mapstructure:"redact_enabled" yaml:"redact_enabled"`
+	RedactRules    []string `mapstructure:"redact_rules" yaml:"redact_rules"`
+	RedactPatterns []string `mapstructure:"redact_patterns" yaml:"redact_patterns"`
+
+	// Phrases dropped from the transcripts
+	BlocklistEnabled  bool     `mapstructure:"blocklist_enabled" yaml:"blocklist_enabled"`
+	BlocklistPhrases  []string `mapstructure:"blocklist_phrases" yaml:"blocklist_phrases"`
+	BlocklistPatterns []string `mapstructure:"blocklist_patterns" yaml:"blocklist_patterns"`
+
 	// Wake Word
 	WakeWordEnabled bool   `mapstructure:"wake_word_enabled" yaml:"wake_word_enabled"`
 	WakeWord        string `mapstructure:"wake_word" yaml:"wake_word"`
 	WakeWordSound   string `mapstructure:"wake_word_sound" yaml:"wake_word_sound"`
+	GPIOPin         int    `mapstructure:"gpio_pin" yaml:"gpio_pin"`
+	GPIOActiveLow   bool   `mapstructure:"gpio_active_low" yaml:"gpio_active_low"`
+
+	// Audio feedback
+	SoundTheme  string   `mapstructure:"sound_theme" yaml:"sound_theme"`
+	SoundEvents []string `mapstructure:"sound_events" yaml:"sound_events"`
+
+	// Mode
+	Mode                 string `mapstructure:"mode" yaml:"mode"`
+	MeetingDir           string `mapstructure:"meeting_dir" yaml:"meeting_dir"`
+	MeetingSummary       bool   `mapstructure:"meeting_summary" yaml:"meeting_summary"`
+	MeetingSummaryPrompt string `mapstructure:"meeting_summary_prompt" yaml:"meeting_summary_prompt"`
+	DictationBackend     string `mapstructure:"dictation_backend" yaml:"dictation_backend"`
+	TranslateTarget      string `mapstructure:"translate_target" yaml:"translate_target"`
+	TranslateBackend     string `mapstructure:"translate_backend" yaml:"translate_backend"`
+
+	// Home Assistant conversation agent
+	HomeAssistantMode    string `mapstructure:"homeassistant_mode" yaml:"homeassistant_mode"`
+	HomeAssistantURL     string `mapstructure:"homeassistant_url" yaml:"homeassistant_url"`
+	HomeAssistantToken   string `mapstructure:"homeassistant_token" yaml:"homeassistant_token"`
+	HomeAssistantAgentID string `mapstructure:"homeassistant_agent_id" yaml:"homeassistant_agent_id"`
+
+	// MQTT integration
+	MQTTEnabled            bool   `mapstructure:"mqtt_enabled" yaml:"mqtt_enabled"`
+	MQTTBroker             string `mapstructure:"mqtt_broker" yaml:"mqtt_broker"`
+	MQTTClientID           string `mapstructure:"mqtt_client_id" yaml:"mqtt_client_id"`
+	MQTTUsername           string `mapstructure:"mqtt_username" yaml:"mqtt_username"`
+	MQTTPassword           string `mapstructure:"mqtt_password" yaml:"mqtt_password"`
+	MQTTTopicPrefix        string `mapstructure:"mqtt_topic_prefix" yaml:"mqtt_topic_prefix"`
+	MQTTCAFile             string `mapstructure:"mqtt_ca_file" yaml:"mqtt_ca_file"`
+	MQTTCertFile           string `mapstructure:"mqtt_cert_file" yaml:"mqtt_cert_file"`
+	MQTTKeyFile            string `mapstructure:"mqtt_key_file" yaml:"mqtt_key_file"`
+	MQTTInsecureSkipVerify bool   `mapstructure:"mqtt_insecure_skip_verify" yaml:"mqtt_insecure_skip_verify"`
+
+	// Matrix bridge
+	MatrixEnabled     bool     `mapstructure:"matrix_enabled" yaml:"matrix_enabled"`
+	MatrixHomeserver  string   `mapstructure:"matrix_homeserver" yaml:"matrix_homeserver"`
+	MatrixAccessToken string   `mapstructure:"matrix_access_token" yaml:"matrix_access_token"`
+	MatrixRoom        string   `mapstructure:"matrix_room" yaml:"matrix_room"`
+	MatrixUsers       []string `mapstructure:"matrix_users" yaml:"matrix_users"`
+	MatrixTranscripts bool     `mapstructure:"matrix_transcripts" yaml:"matrix_transcripts"`
+
+	// Telegram bot
+	TelegramEnabled       bool    `mapstructure:"telegram_enabled" yaml:"telegram_enabled"`
+	TelegramToken         string  `mapstructure:"telegram_token" yaml:"telegram_token"`
+	TelegramChatIDs       []int64 `mapstructure:"telegram_chat_ids" yaml:"telegram_chat_ids"`
+	TelegramForwardChatID int64   `mapstructure:"telegram_forward_chat_id" yaml:"telegram_forward_chat_id"`
+
+	// Outgoing webhooks
+	WebhookURLs    []string `mapstructure:"webhook_urls" yaml:"webhook_urls"`
+	WebhookSecret  string   `mapstructure:"webhook_secret" yaml:"webhook_secret"`
+	WebhookRetries int      `mapstructure:"webhook_retries" yaml:"webhook_retries"`
+
+	// Commands run on events
+	HookOnWake       string        `mapstructure:"hook_on_wake" yaml:"hook_on_wake"`
+	HookOnTranscript string        `mapstructure:"hook_on_transcript" yaml:"hook_on_transcript"`
+	HookOnAIResponse string        `mapstructure:"hook_on_ai_response" yaml:"hook_on_ai_response"`
+	HookOnError      string        `mapstructure:"hook_on_error" yaml:"hook_on_error"`
+	HookTimeout      time.Duration `mapstructure:"hook_timeout" yaml:"hook_timeout"`
+	HookConcurrency  int           `mapstructure:"hook_concurrency" yaml:"hook_concurrency"`
+
+	// D-Bus service
+	DBusEnabled bool `mapstructure:"dbus_enabled" yaml:"dbus_enabled"`
+
+	// Desktop notifications
+	NotifyEnabled bool     `mapstructure:"notify_enabled" yaml:"notify_enabled"`
+	NotifyEvents  []string `mapstructure:"notify_events" yaml:"notify_events"`
+
+	// Control socket
+	ControlEnabled bool   `mapstructure:"control_enabled" yaml:"control_enabled"`
+	ControlSocket  string `mapstructure:"control_socket" yaml:"control_socket"`
+
+	// HTTP API server
+	ServerAddr           string   `mapstructure:"server_addr" yaml:"server_addr"`
+	ServerAllowedOrigins []string `mapstructure:"server_allowed_origins" yaml:"server_allowed_origins"`
+	ServerToken          string   `mapstructure:"server_token" yaml:"server_token"`
+	ServerTLSCert        string   `mapstructure:"server_tls_cert" yaml:"server_tls_cert"`
+	ServerTLSKey         string   `mapstructure:"server_tls_key" yaml:"server_tls_key"`
+	ServerClientCA       string   `mapstructure:"server_client_ca" yaml:"server_client_ca"`
+	ServerAllowedIPs     []string `mapstructure:"server_allowed_ips" yaml:"server_allowed_ips"`
+
+	// Output format
+	OutputFormat      string   `mapstructure:"output_format" yaml:"output_format"`
+	Outputs           []string `mapstructure:"outputs" yaml:"outputs"`
+	TUI               bool     `mapstructure:"tui" yaml:"tui"`
+	TimestampFormat   string   `mapstructure:"timestamp_format" yaml:"timestamp_format"`
+	TimestampTimezone string   `mapstructure:"timestamp_timezone" yaml:"timestamp_timezone"`
+
+	// Live captions
+	CaptionsFile string `mapstructure:"captions_file" yaml:"captions_file"`
+
+	// Clipboard output
+	ClipboardTarget  string `mapstructure:"clipboard" yaml:"clipboard"`
+	ClipboardBackend string `mapstructure:"clipboard_backend" yaml:"clipboard_backend"`
 
 	// AI Configuration
-	AIEnabled    bool   `mapstructure:"ai_enabled" yaml:"ai_enabled"`
-	OllamaURL    string `mapstructure:"ollama_url" yaml:"ollama_url"`
-	OllamaModel  string `mapstructure:"ollama_model" yaml:"ollama_model"`
-	SystemPrompt string `mapstructure:"system_prompt" yaml:"system_prompt"`
+	AIEnabled       bool          `mapstructure:"ai_enabled" yaml:"ai_enabled"`
+	OllamaURL       string        `mapstructure:"ollama_url" yaml:"ollama_url"`
+	OllamaModel     string        `mapstructure:"ollama_model" yaml:"ollama_model"`
+	SystemPrompt    string        `mapstructure:"system_prompt" yaml:"system_prompt"`
+	AIWatchInterval time.Duration `mapstructure:"ai_watch_interval" yaml:"ai_watch_interval"`
+	AIMinConfidence float32       `mapstructure:"ai_min_confidence" yaml:"ai_min_confidence"`
+	AIMinWords      int           `mapstructure:"ai_min_words" yaml:"ai_min_words"`
+	AIQuestionsOnly bool          `mapstructure:"ai_questions_only" yaml:"ai_questions_only"`
+	AIPrefixes      []string      `mapstructure:"ai_prefixes" yaml:"ai_prefixes"`
+	AIName          string        `mapstructure:"ai_name" yaml:"ai_name"`
+
+	// Context sent with every AI request: date and time, language, location
+	AIContext         bool          `mapstructure:"ai_context" yaml:"ai_context"`
+	AILocation        string        `mapstructure:"ai_location" yaml:"ai_location"`
+	AIContextCommands []string      `mapstructure:"ai_context_commands" yaml:"ai_context_commands"`
+	AIContextRefresh  time.Duration `mapstructure:"ai_context_refresh" yaml:"ai_context_refresh"`
+
+	// TLS and proxy of the connections to Ollama and Home Assistant
+	AICAFile             string `mapstructure:"ai_ca_file" yaml:"ai_ca_file"`
+	AICertFile           string `mapstructure:"ai_cert_file" yaml:"ai_cert_file"`
+	AIKeyFile            string `mapstructure:"ai_key_file" yaml:"ai_key_file"`
+	AIInsecureSkipVerify bool   `mapstructure:"ai_insecure_skip_verify" yaml:"ai_insecure_skip_verify"`
+	AIProxy              string `mapstructure:"ai_proxy" yaml:"ai_proxy"`
+
+	// Local intents, answered before the AI
+	IntentsEnabled bool     `mapstructure:"intents_enabled" yaml:"intents_enabled"`
+	IntentsFile    string   `mapstructure:"intents_file" yaml:"intents_file"`
+	TimerSound     string   `mapstructure:"timer_sound" yaml:"timer_sound"`
+	ShellAllowlist []string `mapstructure:"shell_allowlist" yaml:"shell_allowlist"`
+
+	// Profile of the profiles section overriding the settings above
+	Profile string `mapstructure:"profile" yaml:"profile"`
 
 	// Advanced
 	LogLevel   string `mapstructure:"log_level" yaml:"log_level"`
 	MaxHistory int    `mapstructure:"max_history" yaml:"max_history"`
+	Daemon     bool   `mapstructure:"daemon" yaml:"daemon"`
+
+	// Privacy mode, nothing heard or said is written to disk
+	Privacy bool `mapstructure:"privacy" yaml:"privacy"`
+
+	// Log files
+	LogFile           bool          `mapstructure:"log_file" yaml:"log_file"`
+	TranscriptLog     bool          `mapstructure:"transcript_log" yaml:"transcript_log"`
+	LogDir            string        `mapstructure:"log_dir" yaml:"log_dir"`
+	LogMaxSizeMB      int           `mapstructure:"log_max_size_mb" yaml:"log_max_size_mb"`
+	LogRotateInterval time.Duration `mapstructure:"log_rotate_interval" yaml:"log_rotate_interval"`
+	LogMaxBackups     int           `mapstructure:"log_max_backups" yaml:"log_max_backups"`
+
+	// Session transcript archive
+	ArchiveEnabled       bool   `mapstructure:"archive_enabled" yaml:"archive_enabled"`
+	ArchiveDir           string `mapstructure:"archive_dir" yaml:"archive_dir"`
+	SessionSummaryPrompt string `mapstructure:"session_summary_prompt" yaml:"session_summary_prompt"`
+
+	// Daily Markdown notes
+	DailyNotesPath string `mapstructure:"daily_notes_path" yaml:"daily_notes_path"`
+
+	// Transcription worker
+	TranscriptionQueueSize   int           `mapstructure:"transcription_queue_size" yaml:"transcription_queue_size"`
+	TranscriptionQueuePolicy string        `mapstructure:"transcription_queue_policy" yaml:"transcription_queue_policy"`
+	TranscriptionFallback    string        `mapstructure:"transcription_fallback_model" yaml:"transcription_fallback_model"`
+	TranscriptionTimeout     time.Duration `mapstructure:"transcription_timeout" yaml:"transcription_timeout"`
+	TranscriptionPartials    bool          `mapstructure:"transcription_partials" yaml:"transcription_partials"`
+	AudioStatsInterval       time.Duration `mapstructure:"audio_stats_interval" yaml:"audio_stats_interval"`
 }
 
 // DefaultConfig returns a configuration with default values
@@ -39,20 +248,220 @@ func DefaultConfig() *Config {
 		Language:     "fr",
 		AudioSource:  "default",
 
+		// Model selection defaults: the configured one
+		AutoModel: false,
+
+		// Language switching defaults: after 3 utterances in a language
+		LanguageSwitch: 3,
+		SystemPrompts:  map[string]string{},
+
+		// Pipe sources default to the samples the pipeline reads
+		PipeFormat:     "f32le",
+		PipeSampleRate: 16000,
+		PipeChannels:   1,
+
+		// ffmpeg defaults: from PATH, with the platform input
+		FFmpegPath:        "ffmpeg",
+		FFmpegInputFormat: "",
+		FFmpegExtraArgs:   []string{},
+		FFmpegQueueSize:   0,
+
+		// Capture watchdog defaults
+		CaptureStallTimeout: 10 * time.Second,
+
+		// Whisper decoding defaults (zero keeps whisper.cpp defaults)
+		BeamSize:         0,
+		Temperature:      0,
+		EntropyThreshold: 2.4,
+		MaxSegmentLength: 0,
+
+		// Voice activity detection defaults
+		VADThreshold:   0.01,
+		VADSilenceMs:   800,
+		VADMinSpeechMs: 500,
+		VADMaxSpeechS:  30,
+
+		// Speaker diarization defaults
+		DiarizationEnabled:     false,
+		DiarizationThreshold:   0.85,
+		DiarizationMaxSpeakers: 8,
+
+		// Speaker identification defaults
+		VoicesEnabled:   false,
+		VoicesFile:      "",
+		VoicesThreshold: 0.85,
+
+		// Redaction defaults
+		RedactEnabled:  false,
+		RedactRules:    []string{"email", "phone", "card"},
+		RedactPatterns: []string{},
+
+		// Blocklist defaults
+		BlocklistEnabled:  true,
+		BlocklistPhrases:  []string{},
+		BlocklistPatterns: []string{},
+
 		// Wake Word defaults
 		WakeWordEnabled: false,
 		WakeWord:        "Jack",
-		WakeWordSound:   "./sounds/pop-cartoon-328167.mp3",
+		WakeWordSound:   "",
+		GPIOPin:         -1,
+		GPIOActiveLow:   true,
+
+		// Audio feedback defaults: generated tones, thinking is silent
+		SoundTheme:  "default",
+		SoundEvents: []string{"wake", "sleep", "error", "timer"},
+
+		// Mode defaults
+		Mode:                 "assistant",
+		MeetingDir:           "",
+		MeetingSummary:       false,
+		MeetingSummaryPrompt: "Tu es un assistant qui rédige des comptes rendus de réunion. Résume la transcription suivante en français avec les points clés, les décisions et les actions à mener.",
+		DictationBackend:     "auto",
+		TranslateTarget:      "en",
+		TranslateBackend:     "auto",
+
+		// Home Assistant defaults
+		HomeAssistantMode:    "off",
+		HomeAssistantURL:     "http://homeassistant.local:8123",
+		HomeAssistantToken:   "",
+		HomeAssistantAgentID: "",
+
+		// MQTT defaults
+		MQTTEnabled:            false,
+		MQTTBroker:             "tcp://localhost:1883",
+		MQTTClientID:           "nrz-ai",
+		MQTTUsername:           "",
+		MQTTPassword:           "",
+		MQTTTopicPrefix:        "nrz-ai",
+		MQTTCAFile:             "",
+		MQTTCertFile:           "",
+		MQTTKeyFile:            "",
+		MQTTInsecureSkipVerify: false,
+
+		// Matrix defaults
+		MatrixEnabled:     false,
+		MatrixHomeserver:  "",
+		MatrixAccessToken: "",
+		MatrixRoom:        "",
+		MatrixUsers:       []string{},
+		MatrixTranscripts: true,
+
+		// Telegram defaults
+		TelegramEnabled:       false,
+		TelegramToken:         "",
+		TelegramChatIDs:       []int64{},
+		TelegramForwardChatID: 0,
+
+		// Webhook defaults
+		WebhookURLs:    []string{},
+		WebhookSecret:  "",
+		WebhookRetries: 3,
+
+		// Hook defaults
+		HookOnWake:       "",
+		HookOnTranscript: "",
+		HookOnAIResponse: "",
+		HookOnError:      "",
+		HookTimeout:      10 * time.Second,
+		HookConcurrency:  4,
+
+		// D-Bus defaults
+		DBusEnabled: false,
+
+		// Notification defaults
+		NotifyEnabled: false,
+		NotifyEvents:  []string{"wake_word", "transcript", "ai_response", "timer"},
+
+		// Control socket defaults
+		ControlEnabled: false,
+		ControlSocket:  "",
+
+		// Server defaults
+		ServerAddr:           "127.0.0.1:8080",
+		ServerAllowedOrigins: []string{},
+		ServerToken:          "",
+		ServerTLSCert:        "",
+		ServerTLSKey:         "",
+		ServerClientCA:       "",
+		ServerAllowedIPs:     []string{},
+
+		// Output defaults
+		OutputFormat:      "text",
+		Outputs:           []string{},
+		TUI:               false,
+		TimestampFormat:   "15:04:05",
+		TimestampTimezone: "",
+
+		// Captions defaults
+		CaptionsFile: "",
+
+		// Clipboard defaults
+		ClipboardTarget:  "off",
+		ClipboardBackend: "auto",
 
 		// AI defaults
-		AIEnabled:    false,
-		OllamaURL:    "http://localhost:11434",
-		OllamaModel:  "llama3.2:3b",
-		SystemPrompt: "Tu es un assistant vocal français intelligent et concis. Réponds brièvement et naturellement.",
+		AIEnabled:       false,
+		OllamaURL:       "http://localhost:11434",
+		OllamaModel:     "llama3.2:3b",
+		SystemPrompt:    "Tu es un assistant vocal français intelligent et concis. Réponds brièvement et naturellement.",
+		AIWatchInterval: 30 * time.Second,
+		AIMinConfidence: 0.4,
+		AIMinWords:      1,
+		AIQuestionsOnly: false,
+		AIPrefixes:      []string{},
+		AIName:          "",
+
+		// AI context defaults: date, time and language only
+		AIContext:         true,
+		AILocation:        "",
+		AIContextCommands: []string{},
+		AIContextRefresh:  10 * time.Minute,
+
+		// AI transport defaults: system roots and proxy environment
+		AICAFile:             "",
+		AICertFile:           "",
+		AIKeyFile:            "",
+		AIInsecureSkipVerify: false,
+		AIProxy:              "",
+
+		// Intent defaults
+		IntentsEnabled: false,
+		IntentsFile:    "",
+		TimerSound:     "",
+		ShellAllowlist: []string{},
 
 		// Advanced defaults
 		LogLevel:   "info",
 		MaxHistory: 10,
+		Daemon:     false,
+
+		// Privacy mode defaults
+		Privacy: false,
+
+		// Log file defaults
+		LogFile:           false,
+		TranscriptLog:     false,
+		LogDir:            "",
+		LogMaxSizeMB:      10,
+		LogRotateInterval: 24 * time.Hour,
+		LogMaxBackups:     7,
+
+		// Session archive defaults
+		ArchiveEnabled:       true,
+		ArchiveDir:           "",
+		SessionSummaryPrompt: "Tu es un assistant qui résume des conversations. Résume la transcription suivante en français en quelques points clés, avec les décisions prises et les choses à faire.",
+
+		// Daily notes defaults
+		DailyNotesPath: "",
+
+		// Transcription worker defaults
+		TranscriptionQueueSize:   4,
+		TranscriptionQueuePolicy: "block",
+		TranscriptionFallback:    "",
+		TranscriptionTimeout:     60 * time.Second,
+		TranscriptionPartials:    false,
+		AudioStatsInterval:       30 * time.Second,
 	}
 }
 
@@ -79,9 +488,11 @@ func LoadConfig() (*Config, error) {
 	viper.AddConfigPath(configDir)
 	viper.AddConfigPath(".")
 
-	// Environment variable support
+	// Environment variable support, NRZ_AI_<KEY>. Every key needs a default
+	// for viper to look it up in the environment when unmarshalling.
 	viper.SetEnvPrefix("NRZ_AI")
 	viper.AutomaticEnv()
+	setDefaults(cfg)
 
 	// Read configuration file
 	if err := viper.ReadInConfig(); err != nil {
@@ -97,14 +508,326 @@ func LoadConfig() (*Config, error) {
 		logrus.WithField("file", viper.ConfigFileUsed()).Info("Using config file")
 	}
 
-	// Unmarshal configuration
-	if err := viper.Unmarshal(cfg); err != nil {
+	// Unmarshal configuration, with the selected profile applied
+	if err := Reload(cfg); err != nil {
 		return nil, err
 	}
 
 	return cfg, nil
 }
 
+// setDefaults registers the value of every field of cfg as the viper
+// default of its key
+func setDefaults(cfg *Config) {
+	value := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if key := value.Type().Field(i).Tag.Get("mapstructure"); key != "" {
+			viper.SetDefault(key, value.Field(i).Interface())
+		}
+	}
+}
+
+// FlagKeys maps the command line flags to the config keys they override
+var FlagKeys = map[string]string{
+	"model":                 "whisper_model",
+	"auto-model":            "auto_model",
+	"language":              "language",
+	"language-switch":       "language_switch",
+	"audio-source":          "audio_source",
+	"beam-size":             "whisper_beam_size",
+	"temperature":           "whisper_temperature",
+	"entropy-threshold":     "whisper_entropy_threshold",
+	"max-segment-length":    "whisper_max_segment_length",
+	"vad-threshold":         "vad_threshold",
+	"vad-silence-ms":        "vad_silence_ms",
+	"vad-min-speech-ms":     "vad_min_speech_ms",
+	"vad-max-speech-s":      "vad_max_speech_s",
+	"diarize":               "diarization_enabled",
+	"diarization-threshold": "diarization_threshold",
+	"max-speakers":          "diarization_max_speakers",
+	"voices":                "voices_enabled",
+	"redact":                "redact_enabled",
+	"blocklist":             "blocklist_enabled",
+	"wake-word":             "wake_word_enabled",
+	"wake-word-text":        "wake_word",
+	"wake-word-sound":       "wake_word_sound",
+	"sound-theme":           "sound_theme",
+	"gpio-pin":              "gpio_pin",
+	"ai":                    "ai_enabled",
+	"ollama-url":            "ollama_url",
+	"ollama-model":          "ollama_model",
+	"system-prompt":         "system_prompt",
+	"ai-watch-interval":     "ai_watch_interval",
+	"min-confidence":        "ai_min_confidence",
+	"min-words":             "ai_min_words",
+	"questions-only":        "ai_questions_only",
+	"ai-prefix":             "ai_prefixes",
+	"ai-name":               "ai_name",
+	"ai-context":            "ai_context",
+	"location":              "ai_location",
+	"max-history":           "max_history",
+	"intents":               "intents_enabled",
+	"intents-file":          "intents_file",
+	"mode":                  "mode",
+	"meeting-dir":           "meeting_dir",
+	"meeting-summary":       "meeting_summary",
+	"dictation-backend":     "dictation_backend",
+	"target":                "translate_target",
+	"translate-backend":     "translate_backend",
+	"homeassistant":         "homeassistant_mode",
+	"homeassistant-url":     "homeassistant_url",
+	"mqtt":                  "mqtt_enabled",
+	"mqtt-broker":           "mqtt_broker",
+	"matrix":                "matrix_enabled",
+	"matrix-room":           "matrix_room",
+	"telegram":              "telegram_enabled",
+	"dbus":                  "dbus_enabled",
+	"notify":                "notify_enabled",
+	"control":               "control_enabled",
+	"control-socket":        "control_socket",
+	"webhook-url":           "webhook_urls",
+	"on-wake":               "hook_on_wake",
+	"on-transcript":         "hook_on_transcript",
+	"on-ai-response":        "hook_on_ai_response",
+	"on-error":              "hook_on_error",
+	"output":                "output_format",
+	"outputs":               "outputs",
+	"timestamp-format":      "timestamp_format",
+	"timezone":              "timestamp_timezone",
+	"tui":                   "tui",
+	"captions":              "captions_file",
+	"clipboard":             "clipboard",
+	"clipboard-backend":     "clipboard_backend",
+	"profile":               "profile",
+	"log-level":             "log_level",
+	"daemon":                "daemon",
+	"privacy":               "privacy",
+	"log-file":              "log_file",
+	"transcript-log":        "transcript_log",
+	"log-dir":               "log_dir",
+	"archive":               "archive_enabled",
+	"daily-notes":           "daily_notes_path",
+	"queue-size":            "transcription_queue_size",
+	"queue-policy":          "transcription_queue_policy",
+	"fallback-model":        "transcription_fallback_model",
+	"transcription-timeout": "transcription_timeout",
+	"partials":              "transcription_partials",
+	"stats-interval":        "audio_stats_interval",
+	"stall-timeout":         "capture_stall_timeout",
+	"addr":                  "server_addr",
+}
+
+// BindFlags binds command line flags to config keys so that viper resolves
+// every key as flag > environment > config file > default. keys maps flag
+// names to config keys; flags missing from the set are skipped.
+func BindFlags(flags *pflag.FlagSet, keys map[string]string) error {
+	for name, key := range keys {
+		flag := flags.Lookup(name)
+		if flag == nil {
+			continue
+		}
+		if err := viper.BindPFlag(key, flag); err != nil {
+			return fmt.Errorf("failed to bind flag --%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Reload re-resolves cfg from flags, environment, the selected profile,
+// config file and defaults
+func Reload(cfg *Config) error {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+	return reload(cfg)
+}
+
+// reloadMutex serializes the reads of the global viper instance and of
+// mergedProfile, a SIGHUP can come while the watcher reloads the file
+var reloadMutex sync.Mutex
+
+// reload resolves cfg, reloadMutex being held
+func reload(cfg *Config) error {
+	if err := applyProfile(); err != nil {
+		return err
+	}
+
+	reloaded := DefaultConfig()
+	if err := viper.Unmarshal(reloaded); err != nil {
+		return err
+	}
+	reloaded.ResolvePaths()
+	*cfg = *reloaded
+	return nil
+}
+
+// mergedProfile is the profile merged over the config file settings
+var mergedProfile string
+
+// applyProfile merges the settings of the selected profile over the config
+// file ones, flags and environment still take precedence
+func applyProfile() error {
+	if mergedProfile != "" {
+		// Merged settings last until the file is read again, the ones of the
+		// previous profile must not leak into the next one
+		if err := viper.ReadInConfig(); err != nil {
+			return err
+		}
+		mergedProfile = ""
+	}
+
+	name := viper.GetString("profile")
+	if name == "" {
+		return nil
+	}
+
+	key := "profiles." + name
+	if !viper.IsSet(key) {
+		return fmt.Errorf("unknown profile '%s' (not in the profiles section of the config file)", name)
+	}
+	if err := viper.MergeConfigMap(viper.GetStringMap(key)); err != nil {
+		return err
+	}
+	mergedProfile = name
+	return nil
+}
+
+// ReadConfig re-reads the config file in use and resolves a new configuration
+func ReadConfig() (*Config, error) {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+
+	if viper.ConfigFileUsed() != "" {
+		if err := viper.ReadInConfig(); err != nil {
+			return nil, err
+		}
+		mergedProfile = ""
+	}
+
+	cfg := DefaultConfig()
+	if err := reload(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Diff returns the keys whose value differs between a and b
+func Diff(a, b *Config) []string {
+	var keys []string
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	for i := 0; i < va.NumField(); i++ {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			keys = append(keys, va.Type().Field(i).Tag.Get("mapstructure"))
+		}
+	}
+	return keys
+}
+
+// Restore sets the settings of keys back to their value in previous
+func (c *Config) Restore(previous *Config, keys []string) {
+	restore := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		restore[key] = true
+	}
+	vc, vp := reflect.ValueOf(c).Elem(), reflect.ValueOf(previous).Elem()
+	for i := 0; i < vc.NumField(); i++ {
+		if restore[vc.Type().Field(i).Tag.Get("mapstructure")] {
+			vc.Field(i).Set(vp.Field(i))
+		}
+	}
+}
+
+// Dir returns the XDG config directory of nrz-ai, $XDG_CONFIG_HOME/nrz-ai
+// or ~/.config/nrz-ai
+func Dir() string {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "."
+		}
+		configHome = filepath.Join(homeDir, ".config")
+	}
+	return filepath.Join(configHome, "nrz-ai")
+}
+
+// StateDir returns the XDG state directory of nrz-ai, $XDG_STATE_HOME/nrz-ai
+// or ~/.local/state/nrz-ai
+func StateDir() string {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "."
+		}
+		stateHome = filepath.Join(homeDir, ".local", "state")
+	}
+	return filepath.Join(stateHome, "nrz-ai")
+}
+
+// DataDir returns the XDG data directory of nrz-ai, $XDG_DATA_HOME/nrz-ai
+// or ~/.local/share/nrz-ai
+func DataDir() string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "."
+		}
+		dataHome = filepath.Join(homeDir, ".local", "share")
+	}
+	return filepath.Join(dataHome, "nrz-ai")
+}
+
+// WatchConfig calls onChange with the re-read configuration every time the
+// config file in use is modified. It is a no-op when no config file was loaded.
+// The file is read with ReadConfig, not by the viper watcher, to take turns
+// with the SIGHUP reloads.
+func WatchConfig(onChange func(*Config)) {
+	file := viper.ConfigFileUsed()
+	if file == "" {
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to watch config file")
+		return
+	}
+	// The directory is watched to see the editors replacing the file
+	if err := watcher.Add(filepath.Dir(file)); err != nil {
+		watcher.Close()
+		logrus.WithError(err).Warn("Failed to watch config file")
+		return
+	}
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case e, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(e.Name) != filepath.Clean(file) || !(e.Has(fsnotify.Write) || e.Has(fsnotify.Create)) {
+					continue
+				}
+				cfg, err := ReadConfig()
+				if err != nil {
+					logrus.WithError(err).Warn("Failed to reload config file")
+					continue
+				}
+				logrus.WithField("file", e.Name).Info("Config file changed")
+				onChange(cfg)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logrus.WithError(err).Warn("Config file watcher failed")
+			}
+		}
+	}()
+}
+
 // SaveConfig saves the current configuration to the XDG config directory
 func (c *Config) SaveConfig() error {
 	// Get XDG config directory
@@ -125,22 +848,162 @@ func (c *Config) SaveConfig() error {
 		return err
 	}
 
-	// Set configuration in viper
-	viper.Set("whisper_model", c.WhisperModel)
-	viper.Set("language", c.Language)
-	viper.Set("audio_source", c.AudioSource)
-	viper.Set("wake_word_enabled", c.WakeWordEnabled)
-	viper.Set("wake_word", c.WakeWord)
-	viper.Set("wake_word_sound", c.WakeWordSound)
-	viper.Set("ai_enabled", c.AIEnabled)
-	viper.Set("ollama_url", c.OllamaURL)
-	viper.Set("ollama_model", c.OllamaModel)
-	viper.Set("system_prompt", c.SystemPrompt)
-	viper.Set("log_level", c.LogLevel)
-	viper.Set("max_history", c.MaxHistory)
+	// Set configuration in a dedicated viper, viper.Set on the global one
+	// would take precedence over flags and environment
+	v := viper.New()
+	v.Set("whisper_model", c.WhisperModel)
+	v.Set("language", c.Language)
+	v.Set("auto_model", c.AutoModel)
+	v.Set("language_switch", c.LanguageSwitch)
+	v.Set("system_prompts", c.SystemPrompts)
+	v.Set("audio_source", c.AudioSource)
+	v.Set("pipe_format", c.PipeFormat)
+	v.Set("pipe_sample_rate", c.PipeSampleRate)
+	v.Set("pipe_channels", c.PipeChannels)
+	v.Set("ffmpeg_path", c.FFmpegPath)
+	v.Set("ffmpeg_input_format", c.FFmpegInputFormat)
+	v.Set("ffmpeg_extra_args", c.FFmpegExtraArgs)
+	v.Set("ffmpeg_queue_size", c.FFmpegQueueSize)
+	v.Set("capture_stall_timeout", c.CaptureStallTimeout.String())
+	v.Set("whisper_beam_size", c.BeamSize)
+	v.Set("whisper_temperature", c.Temperature)
+	v.Set("whisper_entropy_threshold", c.EntropyThreshold)
+	v.Set("whisper_max_segment_length", c.MaxSegmentLength)
+	v.Set("vad_threshold", c.VADThreshold)
+	v.Set("vad_silence_ms", c.VADSilenceMs)
+	v.Set("vad_min_speech_ms", c.VADMinSpeechMs)
+	v.Set("vad_max_speech_s", c.VADMaxSpeechS)
+	v.Set("diarization_enabled", c.DiarizationEnabled)
+	v.Set("diarization_threshold", c.DiarizationThreshold)
+	v.Set("diarization_max_speakers", c.DiarizationMaxSpeakers)
+	v.Set("voices_enabled", c.VoicesEnabled)
+	v.Set("voices_file", c.VoicesFile)
+	v.Set("voices_threshold", c.VoicesThreshold)
+	v.Set("redact_enabled", c.RedactEnabled)
+	v.Set("redact_rules", c.RedactRules)
+	v.Set("redact_patterns", c.RedactPatterns)
+	v.Set("blocklist_enabled", c.BlocklistEnabled)
+	v.Set("blocklist_phrases", c.BlocklistPhrases)
+	v.Set("blocklist_patterns", c.BlocklistPatterns)
+	v.Set("wake_word_enabled", c.WakeWordEnabled)
+	v.Set("wake_word", c.WakeWord)
+	v.Set("wake_word_sound", c.WakeWordSound)
+	v.Set("gpio_pin", c.GPIOPin)
+	v.Set("gpio_active_low", c.GPIOActiveLow)
+	v.Set("sound_theme", c.SoundTheme)
+	v.Set("sound_events", c.SoundEvents)
+	v.Set("mode", c.Mode)
+	v.Set("meeting_dir", c.MeetingDir)
+	v.Set("meeting_summary", c.MeetingSummary)
+	v.Set("meeting_summary_prompt", c.MeetingSummaryPrompt)
+	v.Set("dictation_backend", c.DictationBackend)
+	v.Set("translate_target", c.TranslateTarget)
+	v.Set("translate_backend", c.TranslateBackend)
+	v.Set("homeassistant_mode", c.HomeAssistantMode)
+	v.Set("homeassistant_url", c.HomeAssistantURL)
+	v.Set("homeassistant_token", c.HomeAssistantToken)
+	v.Set("homeassistant_agent_id", c.HomeAssistantAgentID)
+	v.Set("mqtt_enabled", c.MQTTEnabled)
+	v.Set("mqtt_broker", c.MQTTBroker)
+	v.Set("mqtt_client_id", c.MQTTClientID)
+	v.Set("mqtt_username", c.MQTTUsername)
+	v.Set("mqtt_password", c.MQTTPassword)
+	v.Set("mqtt_topic_prefix", c.MQTTTopicPrefix)
+	v.Set("mqtt_ca_file", c.MQTTCAFile)
+	v.Set("mqtt_cert_file", c.MQTTCertFile)
+	v.Set("mqtt_key_file", c.MQTTKeyFile)
+	v.Set("mqtt_insecure_skip_verify", c.MQTTInsecureSkipVerify)
+	v.Set("matrix_enabled", c.MatrixEnabled)
+	v.Set("matrix_homeserver", c.MatrixHomeserver)
+	v.Set("matrix_access_token", c.MatrixAccessToken)
+	v.Set("matrix_room", c.MatrixRoom)
+	v.Set("matrix_users", c.MatrixUsers)
+	v.Set("matrix_transcripts", c.MatrixTranscripts)
+	v.Set("telegram_enabled", c.TelegramEnabled)
+	v.Set("telegram_token", c.TelegramToken)
+	v.Set("telegram_chat_ids", c.TelegramChatIDs)
+	v.Set("telegram_forward_chat_id", c.TelegramForwardChatID)
+	v.Set("webhook_urls", c.WebhookURLs)
+	v.Set("webhook_secret", c.WebhookSecret)
+	v.Set("webhook_retries", c.WebhookRetries)
+	v.Set("hook_on_wake", c.HookOnWake)
+	v.Set("hook_on_transcript", c.HookOnTranscript)
+	v.Set("hook_on_ai_response", c.HookOnAIResponse)
+	v.Set("hook_on_error", c.HookOnError)
+	v.Set("hook_timeout", c.HookTimeout.String())
+	v.Set("hook_concurrency", c.HookConcurrency)
+	v.Set("dbus_enabled", c.DBusEnabled)
+	v.Set("notify_enabled", c.NotifyEnabled)
+	v.Set("notify_events", c.NotifyEvents)
+	v.Set("control_enabled", c.ControlEnabled)
+	v.Set("control_socket", c.ControlSocket)
+	v.Set("server_addr", c.ServerAddr)
+	v.Set("server_allowed_origins", c.ServerAllowedOrigins)
+	v.Set("server_token", c.ServerToken)
+	v.Set("server_tls_cert", c.ServerTLSCert)
+	v.Set("server_tls_key", c.ServerTLSKey)
+	v.Set("server_client_ca", c.ServerClientCA)
+	v.Set("server_allowed_ips", c.ServerAllowedIPs)
+	v.Set("output_format", c.OutputFormat)
+	v.Set("outputs", c.Outputs)
+	v.Set("timestamp_format", c.TimestampFormat)
+	v.Set("timestamp_timezone", c.TimestampTimezone)
+	v.Set("tui", c.TUI)
+	v.Set("captions_file", c.CaptionsFile)
+	v.Set("clipboard", c.ClipboardTarget)
+	v.Set("clipboard_backend", c.ClipboardBackend)
+	v.Set("ai_enabled", c.AIEnabled)
+	v.Set("ollama_url", c.OllamaURL)
+	v.Set("ollama_model", c.OllamaModel)
+	v.Set("system_prompt", c.SystemPrompt)
+	v.Set("ai_watch_interval", c.AIWatchInterval.String())
+	v.Set("ai_min_confidence", c.AIMinConfidence)
+	v.Set("ai_min_words", c.AIMinWords)
+	v.Set("ai_questions_only", c.AIQuestionsOnly)
+	v.Set("ai_prefixes", c.AIPrefixes)
+	v.Set("ai_name", c.AIName)
+	v.Set("ai_context", c.AIContext)
+	v.Set("ai_location", c.AILocation)
+	v.Set("ai_context_commands", c.AIContextCommands)
+	v.Set("ai_context_refresh", c.AIContextRefresh.String())
+	v.Set("ai_ca_file", c.AICAFile)
+	v.Set("ai_cert_file", c.AICertFile)
+	v.Set("ai_key_file", c.AIKeyFile)
+	v.Set("ai_insecure_skip_verify", c.AIInsecureSkipVerify)
+	v.Set("ai_proxy", c.AIProxy)
+	v.Set("intents_enabled", c.IntentsEnabled)
+	v.Set("intents_file", c.IntentsFile)
+	v.Set("timer_sound", c.TimerSound)
+	v.Set("shell_allowlist", c.ShellAllowlist)
+	v.Set("profile", c.Profile)
+	v.Set("log_level", c.LogLevel)
+	v.Set("max_history", c.MaxHistory)
+	v.Set("daemon", c.Daemon)
+	v.Set("privacy", c.Privacy)
+	v.Set("log_file", c.LogFile)
+	v.Set("transcript_log", c.TranscriptLog)
+	v.Set("log_dir", c.LogDir)
+	v.Set("log_max_size_mb", c.LogMaxSizeMB)
+	v.Set("log_rotate_interval", c.LogRotateInterval.String())
+	v.Set("log_max_backups", c.LogMaxBackups)
+	v.Set("archive_enabled", c.ArchiveEnabled)
+	v.Set("archive_dir", c.ArchiveDir)
+	v.Set("session_summary_prompt", c.SessionSummaryPrompt)
+	v.Set("daily_notes_path", c.DailyNotesPath)
+	v.Set("transcription_queue_size", c.TranscriptionQueueSize)
+	v.Set("transcription_queue_policy", c.TranscriptionQueuePolicy)
+	v.Set("transcription_fallback_model", c.TranscriptionFallback)
+	v.Set("transcription_timeout", c.TranscriptionTimeout.String())
+	v.Set("transcription_partials", c.TranscriptionPartials)
+	v.Set("audio_stats_interval", c.AudioStatsInterval.String())
+
+	// Keep the profiles of the file being replaced
+	if profiles := viper.Get("profiles"); profiles != nil {
+		v.Set("profiles", profiles)
+	}
 
 	// Write configuration file
-	return viper.WriteConfigAs(configFile)
+	return v.WriteConfigAs(configFile)
 }
 
 // createDefaultConfigFile creates a default configuration file
@@ -159,19 +1022,153 @@ func createDefaultConfigFile(configDir string) error {
 
 	defaultConfig := DefaultConfig()
 	
-	// Set default values in viper
-	viper.Set("whisper_model", defaultConfig.WhisperModel)
-	viper.Set("language", defaultConfig.Language)
-	viper.Set("audio_source", defaultConfig.AudioSource)
-	viper.Set("wake_word_enabled", defaultConfig.WakeWordEnabled)
-	viper.Set("wake_word", defaultConfig.WakeWord)
-	viper.Set("wake_word_sound", defaultConfig.WakeWordSound)
-	viper.Set("ai_enabled", defaultConfig.AIEnabled)
-	viper.Set("ollama_url", defaultConfig.OllamaURL)
-	viper.Set("ollama_model", defaultConfig.OllamaModel)
-	viper.Set("system_prompt", defaultConfig.SystemPrompt)
-	viper.Set("log_level", defaultConfig.LogLevel)
-	viper.Set("max_history", defaultConfig.MaxHistory)
-
-	return viper.WriteConfigAs(configFile)
+	// Set default values in a dedicated viper, see SaveConfig
+	v := viper.New()
+	v.Set("whisper_model", defaultConfig.WhisperModel)
+	v.Set("language", defaultConfig.Language)
+	v.Set("auto_model", defaultConfig.AutoModel)
+	v.Set("language_switch", defaultConfig.LanguageSwitch)
+	v.Set("system_prompts", defaultConfig.SystemPrompts)
+	v.Set("audio_source", defaultConfig.AudioSource)
+	v.Set("pipe_format", defaultConfig.PipeFormat)
+	v.Set("pipe_sample_rate", defaultConfig.PipeSampleRate)
+	v.Set("pipe_channels", defaultConfig.PipeChannels)
+	v.Set("ffmpeg_path", defaultConfig.FFmpegPath)
+	v.Set("ffmpeg_input_format", defaultConfig.FFmpegInputFormat)
+	v.Set("ffmpeg_extra_args", defaultConfig.FFmpegExtraArgs)
+	v.Set("ffmpeg_queue_size", defaultConfig.FFmpegQueueSize)
+	v.Set("capture_stall_timeout", defaultConfig.CaptureStallTimeout.String())
+	v.Set("whisper_beam_size", defaultConfig.BeamSize)
+	v.Set("whisper_temperature", defaultConfig.Temperature)
+	v.Set("whisper_entropy_threshold", defaultConfig.EntropyThreshold)
+	v.Set("whisper_max_segment_length", defaultConfig.MaxSegmentLength)
+	v.Set("vad_threshold", defaultConfig.VADThreshold)
+	v.Set("vad_silence_ms", defaultConfig.VADSilenceMs)
+	v.Set("vad_min_speech_ms", defaultConfig.VADMinSpeechMs)
+	v.Set("vad_max_speech_s", defaultConfig.VADMaxSpeechS)
+	v.Set("diarization_enabled", defaultConfig.DiarizationEnabled)
+	v.Set("diarization_threshold", defaultConfig.DiarizationThreshold)
+	v.Set("diarization_max_speakers", defaultConfig.DiarizationMaxSpeakers)
+	v.Set("voices_enabled", defaultConfig.VoicesEnabled)
+	v.Set("voices_file", defaultConfig.VoicesFile)
+	v.Set("voices_threshold", defaultConfig.VoicesThreshold)
+	v.Set("redact_enabled", defaultConfig.RedactEnabled)
+	v.Set("redact_rules", defaultConfig.RedactRules)
+	v.Set("redact_patterns", defaultConfig.RedactPatterns)
+	v.Set("blocklist_enabled", defaultConfig.BlocklistEnabled)
+	v.Set("blocklist_phrases", defaultConfig.BlocklistPhrases)
+	v.Set("blocklist_patterns", defaultConfig.BlocklistPatterns)
+	v.Set("wake_word_enabled", defaultConfig.WakeWordEnabled)
+	v.Set("wake_word", defaultConfig.WakeWord)
+	v.Set("wake_word_sound", defaultConfig.WakeWordSound)
+	v.Set("gpio_pin", defaultConfig.GPIOPin)
+	v.Set("gpio_active_low", defaultConfig.GPIOActiveLow)
+	v.Set("sound_theme", defaultConfig.SoundTheme)
+	v.Set("sound_events", defaultConfig.SoundEvents)
+	v.Set("mode", defaultConfig.Mode)
+	v.Set("meeting_dir", defaultConfig.MeetingDir)
+	v.Set("meeting_summary", defaultConfig.MeetingSummary)
+	v.Set("meeting_summary_prompt", defaultConfig.MeetingSummaryPrompt)
+	v.Set("dictation_backend", defaultConfig.DictationBackend)
+	v.Set("translate_target", defaultConfig.TranslateTarget)
+	v.Set("translate_backend", defaultConfig.TranslateBackend)
+	v.Set("homeassistant_mode", defaultConfig.HomeAssistantMode)
+	v.Set("homeassistant_url", defaultConfig.HomeAssistantURL)
+	v.Set("homeassistant_token", defaultConfig.HomeAssistantToken)
+	v.Set("homeassistant_agent_id", defaultConfig.HomeAssistantAgentID)
+	v.Set("mqtt_enabled", defaultConfig.MQTTEnabled)
+	v.Set("mqtt_broker", defaultConfig.MQTTBroker)
+	v.Set("mqtt_client_id", defaultConfig.MQTTClientID)
+	v.Set("mqtt_username", defaultConfig.MQTTUsername)
+	v.Set("mqtt_password", defaultConfig.MQTTPassword)
+	v.Set("mqtt_topic_prefix", defaultConfig.MQTTTopicPrefix)
+	v.Set("mqtt_ca_file", defaultConfig.MQTTCAFile)
+	v.Set("mqtt_cert_file", defaultConfig.MQTTCertFile)
+	v.Set("mqtt_key_file", defaultConfig.MQTTKeyFile)
+	v.Set("mqtt_insecure_skip_verify", defaultConfig.MQTTInsecureSkipVerify)
+	v.Set("matrix_enabled", defaultConfig.MatrixEnabled)
+	v.Set("matrix_homeserver", defaultConfig.MatrixHomeserver)
+	v.Set("matrix_access_token", defaultConfig.MatrixAccessToken)
+	v.Set("matrix_room", defaultConfig.MatrixRoom)
+	v.Set("matrix_users", defaultConfig.MatrixUsers)
+	v.Set("matrix_transcripts", defaultConfig.MatrixTranscripts)
+	v.Set("telegram_enabled", defaultConfig.TelegramEnabled)
+	v.Set("telegram_token", defaultConfig.TelegramToken)
+	v.Set("telegram_chat_ids", defaultConfig.TelegramChatIDs)
+	v.Set("telegram_forward_chat_id", defaultConfig.TelegramForwardChatID)
+	v.Set("webhook_urls", defaultConfig.WebhookURLs)
+	v.Set("webhook_secret", defaultConfig.WebhookSecret)
+	v.Set("webhook_retries", defaultConfig.WebhookRetries)
+	v.Set("hook_on_wake", defaultConfig.HookOnWake)
+	v.Set("hook_on_transcript", defaultConfig.HookOnTranscript)
+	v.Set("hook_on_ai_response", defaultConfig.HookOnAIResponse)
+	v.Set("hook_on_error", defaultConfig.HookOnError)
+	v.Set("hook_timeout", defaultConfig.HookTimeout.String())
+	v.Set("hook_concurrency", defaultConfig.HookConcurrency)
+	v.Set("dbus_enabled", defaultConfig.DBusEnabled)
+	v.Set("notify_enabled", defaultConfig.NotifyEnabled)
+	v.Set("notify_events", defaultConfig.NotifyEvents)
+	v.Set("control_enabled", defaultConfig.ControlEnabled)
+	v.Set("control_socket", defaultConfig.ControlSocket)
+	v.Set("server_addr", defaultConfig.ServerAddr)
+	v.Set("server_allowed_origins", defaultConfig.ServerAllowedOrigins)
+	v.Set("server_token", defaultConfig.ServerToken)
+	v.Set("server_tls_cert", defaultConfig.ServerTLSCert)
+	v.Set("server_tls_key", defaultConfig.ServerTLSKey)
+	v.Set("server_client_ca", defaultConfig.ServerClientCA)
+	v.Set("server_allowed_ips", defaultConfig.ServerAllowedIPs)
+	v.Set("output_format", defaultConfig.OutputFormat)
+	v.Set("outputs", defaultConfig.Outputs)
+	v.Set("timestamp_format", defaultConfig.TimestampFormat)
+	v.Set("timestamp_timezone", defaultConfig.TimestampTimezone)
+	v.Set("tui", defaultConfig.TUI)
+	v.Set("captions_file", defaultConfig.CaptionsFile)
+	v.Set("clipboard", defaultConfig.ClipboardTarget)
+	v.Set("clipboard_backend", defaultConfig.ClipboardBackend)
+	v.Set("ai_enabled", defaultConfig.AIEnabled)
+	v.Set("ollama_url", defaultConfig.OllamaURL)
+	v.Set("ollama_model", defaultConfig.OllamaModel)
+	v.Set("system_prompt", defaultConfig.SystemPrompt)
+	v.Set("ai_watch_interval", defaultConfig.AIWatchInterval.String())
+	v.Set("ai_min_confidence", defaultConfig.AIMinConfidence)
+	v.Set("ai_min_words", defaultConfig.AIMinWords)
+	v.Set("ai_questions_only", defaultConfig.AIQuestionsOnly)
+	v.Set("ai_prefixes", defaultConfig.AIPrefixes)
+	v.Set("ai_name", defaultConfig.AIName)
+	v.Set("ai_context", defaultConfig.AIContext)
+	v.Set("ai_location", defaultConfig.AILocation)
+	v.Set("ai_context_commands", defaultConfig.AIContextCommands)
+	v.Set("ai_context_refresh", defaultConfig.AIContextRefresh.String())
+	v.Set("ai_ca_file", defaultConfig.AICAFile)
+	v.Set("ai_cert_file", defaultConfig.AICertFile)
+	v.Set("ai_key_file", defaultConfig.AIKeyFile)
+	v.Set("ai_insecure_skip_verify", defaultConfig.AIInsecureSkipVerify)
+	v.Set("ai_proxy", defaultConfig.AIProxy)
+	v.Set("intents_enabled", defaultConfig.IntentsEnabled)
+	v.Set("intents_file", defaultConfig.IntentsFile)
+	v.Set("timer_sound", defaultConfig.TimerSound)
+	v.Set("shell_allowlist", defaultConfig.ShellAllowlist)
+	v.Set("profile", defaultConfig.Profile)
+	v.Set("log_level", defaultConfig.LogLevel)
+	v.Set("max_history", defaultConfig.MaxHistory)
+	v.Set("daemon", defaultConfig.Daemon)
+	v.Set("privacy", defaultConfig.Privacy)
+	v.Set("log_file", defaultConfig.LogFile)
+	v.Set("transcript_log", defaultConfig.TranscriptLog)
+	v.Set("log_dir", defaultConfig.LogDir)
+	v.Set("log_max_size_mb", defaultConfig.LogMaxSizeMB)
+	v.Set("log_rotate_interval", defaultConfig.LogRotateInterval.String())
+	v.Set("log_max_backups", defaultConfig.LogMaxBackups)
+	v.Set("archive_enabled", defaultConfig.ArchiveEnabled)
+	v.Set("archive_dir", defaultConfig.ArchiveDir)
+	v.Set("session_summary_prompt", defaultConfig.SessionSummaryPrompt)
+	v.Set("daily_notes_path", defaultConfig.DailyNotesPath)
+	v.Set("transcription_queue_size", defaultConfig.TranscriptionQueueSize)
+	v.Set("transcription_queue_policy", defaultConfig.TranscriptionQueuePolicy)
+	v.Set("transcription_fallback_model", defaultConfig.TranscriptionFallback)
+	v.Set("transcription_timeout", defaultConfig.TranscriptionTimeout.String())
+	v.Set("transcription_partials", defaultConfig.TranscriptionPartials)
+	v.Set("audio_stats_interval", defaultConfig.AudioStatsInterval.String())
+
+	return v.WriteConfigAs(configFile)
 }
\ No newline at end of file
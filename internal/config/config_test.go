@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// loadTestConfig resolves a configuration from the config file content,
+// the environment and the command line args, as the nrz-ai command does
+func loadTestConfig(t *testing.T, file string, env map[string]string, args ...string) (*Config, error) {
+	t.Helper()
+
+	viper.Reset()
+	mergedProfile = ""
+	t.Cleanup(viper.Reset)
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	if file != "" {
+		if err := os.MkdirAll(filepath.Join(home, "nrz-ai"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(home, "nrz-ai", "config.yaml"), []byte(file), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	flags := pflag.NewFlagSet("nrz-ai", pflag.ContinueOnError)
+	flags.StringVar(&cfg.Language, "language", cfg.Language, "")
+	flags.IntVar(&cfg.BeamSize, "beam-size", cfg.BeamSize, "")
+	flags.StringVar(&cfg.Profile, "profile", cfg.Profile, "")
+	if err := flags.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	if err := BindFlags(flags, FlagKeys); err != nil {
+		t.Fatal(err)
+	}
+	return cfg, Reload(cfg)
+}
+
+func TestReload_Precedence(t *testing.T) {
+	const file = "language: de\nwhisper_beam_size: 3\n"
+	env := map[string]string{"NRZ_AI_LANGUAGE": "en", "NRZ_AI_WHISPER_BEAM_SIZE": "4"}
+
+	for _, test := range []struct {
+		name     string
+		file     string
+		env      map[string]string
+		args     []string
+		language string
+		beamSize int
+	}{
+		{"default", "", nil, nil, "fr", 0},
+		{"config file", file, nil, nil, "de", 3},
+		{"environment", file, env, nil, "en", 4},
+		{"flag", file, env, []string{"--language", "es", "--beam-size", "5"}, "es", 5},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			cfg, err := loadTestConfig(t, test.file, test.env, test.args...)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if cfg.Language != test.language || cfg.BeamSize != test.beamSize {
+				t.Errorf("Expected language %s and beam size %d, got %s and %d",
+					test.language, test.beamSize, cfg.Language, cfg.BeamSize)
+			}
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	previous, cfg := DefaultConfig(), DefaultConfig()
+	cfg.Language, cfg.BeamSize, cfg.AIName = "de", 5, "Max"
+
+	cfg.Restore(previous, []string{"language", "whisper_beam_size"})
+	if cfg.Language != previous.Language || cfg.BeamSize != previous.BeamSize || cfg.AIName != "Max" {
+		t.Errorf("Expected only the restored settings back, got %s, %d and %s", cfg.Language, cfg.BeamSize, cfg.AIName)
+	}
+	if diff := Diff(previous, cfg); len(diff) != 1 || diff[0] != "ai_name" {
+		t.Errorf("Expected ai_name to differ, got %v", diff)
+	}
+}
+
+const profilesFile = `language: de
+ai_name: Jack
+profile: maison
+profiles:
+  maison:
+    language: it
+    ai_name: Max
+  bureau:
+    language: nl
+`
+
+func TestReload_ProfilePrecedence(t *testing.T) {
+	cfg, err := loadTestConfig(t, profilesFile, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Language != "it" || cfg.AIName != "Max" {
+		t.Errorf("Expected the profile over the config file, got %s and %s", cfg.Language, cfg.AIName)
+	}
+
+	cfg, err = loadTestConfig(t, profilesFile, map[string]string{"NRZ_AI_AI_NAME": "Léa"}, "--language", "es")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Language != "es" || cfg.AIName != "Léa" {
+		t.Errorf("Expected the flag and environment over the profile, got %s and %s", cfg.Language, cfg.AIName)
+	}
+}
+
+func TestReload_UnknownProfile(t *testing.T) {
+	if _, err := loadTestConfig(t, profilesFile, nil, "--profile", "grenier"); err == nil {
+		t.Error("Expected an error for a profile missing from the config file")
+	}
+}
+
+func TestReload_SwitchesProfile(t *testing.T) {
+	// The profile of the file is applied on load, then the flag selects another
+	cfg, err := loadTestConfig(t, profilesFile, nil, "--profile", "bureau")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Profile != "bureau" || cfg.Language != "nl" || cfg.AIName != "Jack" {
+		t.Errorf("Expected only the settings of the new profile over the file, got %s, %s and %s",
+			cfg.Profile, cfg.Language, cfg.AIName)
+	}
+
+	viper.Set("profile", "")
+	if err := Reload(cfg); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Language != "de" || cfg.AIName != "Jack" {
+		t.Errorf("Expected the config file settings without a profile, got %s and %s", cfg.Language, cfg.AIName)
+	}
+}
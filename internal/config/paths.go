@@ -0,0 +1,110 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DataDirs returns the directories searched for data files such as models
+// and sounds, by priority: the user data directory, $XDG_DATA_DIRS
+// (/usr/local/share and /usr/share by default), then the parent of the
+// directory holding the binary, for ./dist/nrz-ai in a source checkout
+func DataDirs() []string {
+	dirs := []string{DataDir()}
+
+	systemDirs := os.Getenv("XDG_DATA_DIRS")
+	if systemDirs == "" {
+		systemDirs = "/usr/local/share:/usr/share"
+	}
+	for _, dir := range strings.Split(systemDirs, ":") {
+		if dir != "" {
+			dirs = append(dirs, filepath.Join(dir, "nrz-ai"))
+		}
+	}
+
+	if executable, err := os.Executable(); err == nil {
+		dirs = append(dirs, filepath.Dir(filepath.Dir(executable)))
+	}
+	return dirs
+}
+
+// FindDataFile resolves a relative data file path like ./models/ggml-base.bin:
+// the path itself when it exists from the working directory, else the first
+// match below DataDirs. Absolute paths and files found nowhere are returned
+// unchanged.
+func FindDataFile(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+
+	for _, dir := range DataDirs() {
+		candidate := filepath.Join(dir, path)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return path
+}
+
+// MeetingsDir returns the default directory of meeting minutes,
+// $XDG_DATA_HOME/nrz-ai/meetings
+func MeetingsDir() string {
+	return filepath.Join(DataDir(), "meetings")
+}
+
+// SessionsDir returns the default directory of recorded sessions,
+// $XDG_DATA_HOME/nrz-ai/sessions
+func SessionsDir() string {
+	return filepath.Join(DataDir(), "sessions")
+}
+
+// TranscriptsDir returns the default directory of session transcript
+// archives, $XDG_DATA_HOME/nrz-ai/transcripts
+func TranscriptsDir() string {
+	return filepath.Join(DataDir(), "transcripts")
+}
+
+// VoicesFile returns the default voice prints file,
+// $XDG_DATA_HOME/nrz-ai/voices.json
+func VoicesFile() string {
+	return filepath.Join(DataDir(), "voices.json")
+}
+
+// ExpandHome replaces a leading ~/ of path by the home directory
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(homeDir, strings.TrimPrefix(path, "~"))
+}
+
+// ResolvePaths replaces relative data file paths by the file found in the
+// data directories and fills in the default directories
+func (c *Config) ResolvePaths() {
+	c.WhisperModel = FindDataFile(c.WhisperModel)
+	c.TranscriptionFallback = FindDataFile(c.TranscriptionFallback)
+	c.WakeWordSound = FindDataFile(c.WakeWordSound)
+	c.TimerSound = FindDataFile(c.TimerSound)
+	if c.SoundTheme != "" && c.SoundTheme != "default" && c.SoundTheme != "off" {
+		c.SoundTheme = FindDataFile(ExpandHome(c.SoundTheme))
+	}
+	if c.MeetingDir == "" {
+		c.MeetingDir = MeetingsDir()
+	}
+	c.DailyNotesPath = ExpandHome(c.DailyNotesPath)
+	c.FFmpegPath = ExpandHome(c.FFmpegPath)
+	if c.ArchiveDir == "" {
+		c.ArchiveDir = TranscriptsDir()
+	}
+	if c.VoicesFile == "" {
+		c.VoicesFile = VoicesFile()
+	}
+}
@@ -0,0 +1,130 @@
+package control
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func startTestServer(t *testing.T) (*Server, *MockController) {
+	t.Helper()
+
+	controller := NewMockController()
+	server := NewServer(filepath.Join(t.TempDir(), "nrz-ai.sock"), controller)
+	if err := server.Start(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+	return server, controller
+}
+
+func TestServer_Commands(t *testing.T) {
+	server, controller := startTestServer(t)
+
+	for _, command := range []string{"pause", "forget-last", "clear-history", "set-language en", "lock-language"} {
+		if _, err := Send(server.Path(), command); err != nil {
+			t.Errorf("Expected %q to succeed, got: %v", command, err)
+		}
+	}
+
+	reply, err := Send(server.Path(), "status")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var status Status
+	if err := json.Unmarshal([]byte(reply), &status); err != nil {
+		t.Fatalf("Expected JSON status, got %q (%v)", reply, err)
+	}
+	if !status.Paused || status.Language != "en" || !status.LanguageLocked || controller.Cleared() != 1 {
+		t.Errorf("Unexpected state after commands: %+v, cleared=%d", status, controller.Cleared())
+	}
+
+	Send(server.Path(), "resume")
+	if controller.Status().Paused {
+		t.Error("Expected resume to restart processing")
+	}
+
+	// Nothing is left to forget after the clear
+	if _, err := Send(server.Path(), "forget-last"); err == nil || controller.Forgot() != 1 {
+		t.Errorf("Expected forget-last to fail after a clear, got %v, forgot=%d", err, controller.Forgot())
+	}
+}
+
+func TestServer_Say(t *testing.T) {
+	server, controller := startTestServer(t)
+
+	if _, err := Send(server.Path(), `say "Quelle heure est-il ?"`); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(controller.Said()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if said := controller.Said(); len(said) != 1 || said[0] != "Quelle heure est-il ?" {
+		t.Errorf("Expected unquoted say text, got %q", said)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	server, _ := startTestServer(t)
+
+	if _, err := Send(server.Path(), "reboot"); err == nil || !strings.Contains(err.Error(), "unknown command") {
+		t.Errorf("Expected unknown command error, got: %v", err)
+	}
+	if _, err := Send(server.Path(), "set-language xx"); err == nil {
+		t.Error("Expected controller error to be reported")
+	}
+	if _, err := Send(server.Path(), "say"); err == nil {
+		t.Error("Expected usage error for say without text")
+	}
+}
+
+func TestServer_SocketPermissions(t *testing.T) {
+	server, _ := startTestServer(t)
+
+	info, err := os.Stat(server.Path())
+	if err != nil {
+		t.Fatalf("Expected socket file, got: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected 0600 socket permissions, got %o", info.Mode().Perm())
+	}
+}
+
+func TestServer_RefusesSocketInUse(t *testing.T) {
+	server, _ := startTestServer(t)
+
+	if err := NewServer(server.Path(), NewMockController()).Start(); err == nil {
+		t.Error("Expected error when another server owns the socket")
+	}
+}
+
+func TestSend_NoServer(t *testing.T) {
+	if _, err := Send(filepath.Join(t.TempDir(), "missing.sock"), "status"); err == nil {
+		t.Error("Expected error without a running server")
+	}
+}
+
+func TestServer_Conversation(t *testing.T) {
+	server, _ := startTestServer(t)
+
+	conversation := `{"version":1,"messages":[{"role":"user","content":"` + strings.Repeat("Bonjour ", 10000) + `"}]}`
+	if _, err := Send(server.Path(), "import-conversation "+conversation); err != nil {
+		t.Fatalf("Expected a long conversation to be imported, got: %v", err)
+	}
+	reply, err := Send(server.Path(), "export-conversation")
+	if err != nil || reply != conversation {
+		t.Errorf("Expected the conversation back on one line, got %.40q (%v)", reply, err)
+	}
+
+	if _, err := Send(server.Path(), "import-conversation {"); err == nil {
+		t.Error("Expected invalid JSON to be rejected")
+	}
+	if _, err := Send(server.Path(), "import-conversation"); err == nil {
+		t.Error("Expected the usage without a conversation")
+	}
+}
@@ -0,0 +1,71 @@
+package control
+
+import "io"
+
+// Controller is the assistant driven through the control socket
+type Controller interface {
+	// Pause stops processing audio until Resume
+	Pause()
+
+	// Resume restarts audio processing
+	Resume()
+
+	// ClearHistory forgets the AI conversation
+	ClearHistory()
+
+	// ForgetLast removes the last AI exchange, false when there is none
+	ForgetLast() bool
+
+	// ExportConversation writes the AI conversation as JSON
+	ExportConversation(w io.Writer) error
+
+	// ImportConversation replaces the AI conversation with an exported one
+	ImportConversation(r io.Reader) error
+
+	// SetLanguage changes the transcription language
+	SetLanguage(language string) error
+
+	// LockLanguage stops, or resumes, the automatic language switching
+	LockLanguage(locked bool)
+
+	// Say handles text as if it had been spoken
+	Say(text string)
+
+	// Status reports the assistant state
+	Status() Status
+}
+
+// Status is the reply to the status command
+type Status struct {
+	State          string   `json:"state"` // Assistant state: idle, wake_listening, active, transcribing, thinking, speaking or paused
+	Paused         bool     `json:"paused"`
+	Language       string   `json:"language"`
+	Detected       string   `json:"detected_language,omitempty"` // Language switched to while transcribing with auto
+	LanguageLocked bool     `json:"language_locked,omitempty"`
+	AIEnabled      bool     `json:"ai_enabled"`
+	AIAvailable    bool     `json:"ai_available"` // False while the AI backend is unreachable
+	LastTranscript string   `json:"last_transcript,omitempty"`
+	CaptureStalls  int      `json:"capture_stalls"` // Capture restarts after the source stopped sending audio
+	Overload       Overload `json:"overload"`
+	Audio          Audio    `json:"audio"`
+}
+
+// Overload counts what the transcription queue policy did while
+// transcription lagged behind real time
+type Overload struct {
+	DroppedSegments  int     `json:"dropped_segments"`
+	DroppedAudioS    float64 `json:"dropped_audio_s"`
+	MergedSegments   int     `json:"merged_segments"`
+	FallbackSwitches int     `json:"fallback_switches"`
+}
+
+// Audio counts the audio going through the pipeline and what is buffered
+type Audio struct {
+	BytesRead   int64   `json:"bytes_read"`
+	ProcessedS  float64 `json:"processed_s"`
+	DroppedS    float64 `json:"dropped_s"`
+	BufferedS   float64 `json:"buffered_s"` // Utterance being cut
+	BufferSizeS float64 `json:"buffer_size_s"`
+	Queued      int     `json:"queued"` // Segments waiting for transcription
+	QueueSize   int     `json:"queue_size"`
+}
@@ -0,0 +1,16 @@
+//go:build unix
+
+package control
+
+import (
+	"net"
+	"syscall"
+)
+
+// listen creates the socket at path only accessible to its owner from the
+// start: with a chmod alone, another user could connect before it.
+func listen(path string) (net.Listener, error) {
+	umask := syscall.Umask(0077)
+	defer syscall.Umask(umask)
+	return net.Listen("unix", path)
+}
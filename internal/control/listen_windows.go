@@ -0,0 +1,11 @@
+//go:build windows
+
+package control
+
+import "net"
+
+// listen creates the socket at path, its access is restricted by the ACL
+// of its directory
+func listen(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,137 @@
+package control
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+)
+
+// MockController implements Controller for testing
+type MockController struct {
+	status       Status
+	cleared      int
+	forgot       int
+	said         []string
+	conversation string
+	mutex        sync.Mutex
+}
+
+// NewMockController creates a new mock controller
+func NewMockController() *MockController {
+	return &MockController{
+		status: Status{State: "idle", Language: "fr"},
+	}
+}
+
+// Cleared returns the number of ClearHistory calls
+func (m *MockController) Cleared() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.cleared
+}
+
+// Said returns the texts passed to Say
+func (m *MockController) Said() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]string(nil), m.said...)
+}
+
+// Pause marks the mock as paused
+func (m *MockController) Pause() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.status.Paused = true
+}
+
+// Resume marks the mock as running
+func (m *MockController) Resume() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.status.Paused = false
+}
+
+// ClearHistory counts clears
+func (m *MockController) ClearHistory() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.cleared++
+}
+
+// ForgetLast counts forgotten exchanges, there is none after a clear
+func (m *MockController) ForgetLast() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.cleared > 0 {
+		return false
+	}
+	m.forgot++
+	return true
+}
+
+// Forgot returns the number of exchanges forgotten
+func (m *MockController) Forgot() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.forgot
+}
+
+// ExportConversation writes the last imported conversation, or an empty one
+func (m *MockController) ExportConversation(w io.Writer) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	conversation := m.conversation
+	if conversation == "" {
+		conversation = `{"version": 1, "messages": []}`
+	}
+	_, err := io.WriteString(w, conversation+"\n")
+	return err
+}
+
+// ImportConversation keeps the conversation, rejecting invalid JSON
+func (m *MockController) ImportConversation(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if !json.Valid(data) {
+		return errors.New("invalid conversation")
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.conversation = string(data)
+	return nil
+}
+
+// SetLanguage sets the language, rejecting "xx"
+func (m *MockController) SetLanguage(language string) error {
+	if language == "xx" {
+		return errors.New("unsupported language")
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.status.Language = language
+	return nil
+}
+
+// LockLanguage records the lock in the status
+func (m *MockController) LockLanguage(locked bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.status.LanguageLocked = locked
+}
+
+// Say records the text
+func (m *MockController) Say(text string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.said = append(m.said, text)
+}
+
+// Status returns the mock status
+func (m *MockController) Status() Status {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.status
+}
@@ -0,0 +1,211 @@
+package control
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/logger"
+)
+
+// Commands lists the commands understood by the control socket
+var Commands = []string{"pause", "resume", "clear-history", "forget-last", "set-language <lang>", "lock-language", "unlock-language", "status", "say <text>", "export-conversation", "import-conversation <json>"}
+
+// maxLineSize bounds a command line, long enough for an imported conversation
+const maxLineSize = 4 << 20
+
+// DefaultSocketPath returns $XDG_RUNTIME_DIR/nrz-ai.sock, or a per-user
+// path in the temporary directory when XDG_RUNTIME_DIR is unset
+func DefaultSocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "nrz-ai.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("nrz-ai-%d.sock", os.Getuid()))
+}
+
+// Server accepts line-based commands on a Unix domain socket. Every command
+// gets a single line reply starting with "ok" or "error".
+type Server struct {
+	path       string
+	controller Controller
+	listener   net.Listener
+}
+
+// NewServer creates a control server listening on path
+func NewServer(path string, controller Controller) *Server {
+	return &Server{
+		path:       path,
+		controller: controller,
+	}
+}
+
+// Start listens on the socket, replacing a stale socket left by a crashed run
+func (s *Server) Start() error {
+	if conn, err := net.Dial("unix", s.path); err == nil {
+		conn.Close()
+		return fmt.Errorf("control socket %s is in use, is another nrz-ai running?", s.path)
+	}
+	os.Remove(s.path)
+
+	// Only the owner may drive the assistant
+	listener, err := listen(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.path, err)
+	}
+	if err := os.Chmod(s.path, 0600); err != nil {
+		listener.Close()
+		return err
+	}
+
+	s.listener = listener
+	go s.acceptLoop()
+	return nil
+}
+
+// Path returns the socket path
+func (s *Server) Path() string {
+	return s.path
+}
+
+// Close stops listening and removes the socket
+func (s *Server) Close() error {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Close()
+}
+
+// acceptLoop serves connections until the listener is closed
+func (s *Server) acceptLoop() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				logger.WithError(err).Error("❌ Control socket accept failed")
+			}
+			return
+		}
+		go s.serve(conn)
+	}
+}
+
+// serve handles the commands of a single connection
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fmt.Fprintln(conn, s.Execute(line))
+	}
+}
+
+// Execute runs a single command line and returns its reply
+func (s *Server) Execute(line string) string {
+	command, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+	arg = unquote(strings.TrimSpace(arg))
+
+	switch command {
+	case "pause":
+		s.controller.Pause()
+	case "resume":
+		s.controller.Resume()
+	case "clear-history":
+		s.controller.ClearHistory()
+	case "forget-last":
+		if !s.controller.ForgetLast() {
+			return "error: no AI exchange to forget"
+		}
+	case "set-language":
+		if arg == "" {
+			return "error: usage: set-language <lang>"
+		}
+		if err := s.controller.SetLanguage(arg); err != nil {
+			return "error: " + err.Error()
+		}
+	case "lock-language":
+		s.controller.LockLanguage(true)
+	case "unlock-language":
+		s.controller.LockLanguage(false)
+	case "say":
+		if arg == "" {
+			return "error: usage: say <text>"
+		}
+		// The AI can take a while to answer, reply right away
+		go s.controller.Say(arg)
+	case "export-conversation":
+		var exported bytes.Buffer
+		if err := s.controller.ExportConversation(&exported); err != nil {
+			return "error: " + err.Error()
+		}
+		// Replies are single lines
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, exported.Bytes()); err != nil {
+			return "error: " + err.Error()
+		}
+		return "ok " + compact.String()
+	case "import-conversation":
+		if arg == "" {
+			return "error: usage: import-conversation <json>"
+		}
+		if err := s.controller.ImportConversation(strings.NewReader(arg)); err != nil {
+			return "error: " + err.Error()
+		}
+	case "status":
+		data, err := json.Marshal(s.controller.Status())
+		if err != nil {
+			return "error: " + err.Error()
+		}
+		return "ok " + string(data)
+	default:
+		return fmt.Sprintf("error: unknown command '%s' (expected %s)", command, strings.Join(Commands, ", "))
+	}
+	return "ok"
+}
+
+// unquote strips matching surrounding quotes
+func unquote(text string) string {
+	if len(text) >= 2 {
+		if (text[0] == '"' && text[len(text)-1] == '"') || (text[0] == '\'' && text[len(text)-1] == '\'') {
+			return text[1 : len(text)-1]
+		}
+	}
+	return text
+}
+
+// Send sends a command to the socket at path and returns the reply, an
+// "error" reply is returned as an error
+func Send(path, command string) (string, error) {
+	conn, err := net.DialTimeout("unix", path, 2*time.Second)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to %s, is nrz-ai running with --control? %w", path, err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	if _, err := fmt.Fprintln(conn, command); err != nil {
+		return "", err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read reply: %w", err)
+	}
+	reply = strings.TrimSpace(reply)
+
+	if message, ok := strings.CutPrefix(reply, "error: "); ok {
+		return "", errors.New(message)
+	}
+	return strings.TrimSpace(strings.TrimPrefix(reply, "ok")), nil
+}
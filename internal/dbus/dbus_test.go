@@ -0,0 +1,78 @@
+package dbus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+func TestObject_Methods(t *testing.T) {
+	controller := NewMockController()
+	controller.SetLastTranscript("Bonjour")
+	obj := object{controller}
+
+	obj.Activate()
+	if !controller.IsActive() {
+		t.Error("Expected Activate to start listening")
+	}
+	obj.Deactivate()
+	if controller.IsActive() {
+		t.Error("Expected Deactivate to stop listening")
+	}
+
+	obj.Pause()
+	if !controller.IsPaused() {
+		t.Error("Expected Pause to pause")
+	}
+	obj.Resume()
+	if controller.IsPaused() {
+		t.Error("Expected Resume to resume")
+	}
+
+	if text, err := obj.GetLastTranscript(); err != nil || text != "Bonjour" {
+		t.Errorf("Expected last transcript 'Bonjour', got %q (%v)", text, err)
+	}
+
+	obj.Say("Quelle heure est-il ?")
+	deadline := time.Now().Add(time.Second)
+	for len(controller.Said()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if said := controller.Said(); len(said) != 1 || said[0] != "Quelle heure est-il ?" {
+		t.Errorf("Expected Say to reach the controller, got %q", said)
+	}
+}
+
+func TestService_EmitSignals(t *testing.T) {
+	service := NewService(NewMockController())
+
+	// Not started: events are ignored
+	if err := service.Emit(output.Event{Type: output.EventTranscript, Text: "Bonjour"}); err != nil {
+		t.Errorf("Expected no error before Start, got: %v", err)
+	}
+
+	type signal struct {
+		name   string
+		values []interface{}
+	}
+	var signals []signal
+	service.emitSignal = func(name string, values ...interface{}) error {
+		signals = append(signals, signal{name, values})
+		return nil
+	}
+
+	service.Emit(output.Event{Type: output.EventTranscript, Text: "Bonjour", Speaker: "Speaker 1"})
+	service.Emit(output.Event{Type: output.EventState, State: "active"})
+	service.Emit(output.Event{Type: output.EventAIToken, Text: "Bon"})
+
+	if len(signals) != 2 {
+		t.Fatalf("Expected 2 signals, got %d", len(signals))
+	}
+	if signals[0].name != "TranscriptReady" || signals[0].values[0] != "Bonjour" || signals[0].values[1] != "Speaker 1" {
+		t.Errorf("Unexpected transcript signal: %+v", signals[0])
+	}
+	if signals[1].name != "StateChanged" || signals[1].values[0] != "active" {
+		t.Errorf("Unexpected state signal: %+v", signals[1])
+	}
+}
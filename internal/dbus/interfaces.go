@@ -0,0 +1,22 @@
+package dbus
+
+// Controller is the assistant driven through the D-Bus methods
+type Controller interface {
+	// Activate starts listening as if the wake word had been said
+	Activate()
+
+	// Deactivate stops listening until the next wake word
+	Deactivate()
+
+	// Pause stops processing audio until Resume
+	Pause()
+
+	// Resume restarts audio processing
+	Resume()
+
+	// Say handles text as if it had been spoken
+	Say(text string)
+
+	// LastTranscript returns the most recent final transcript
+	LastTranscript() string
+}
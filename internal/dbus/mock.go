@@ -0,0 +1,71 @@
+package dbus
+
+import "sync"
+
+// MockController implements Controller for testing
+type MockController struct {
+	active         bool
+	paused         bool
+	said           []string
+	lastTranscript string
+	mutex          sync.Mutex
+}
+
+// NewMockController creates a new mock controller
+func NewMockController() *MockController {
+	return &MockController{}
+}
+
+// SetLastTranscript sets the transcript returned by LastTranscript
+func (m *MockController) SetLastTranscript(text string) {
+	m.lastTranscript = text
+}
+
+// IsActive returns whether the mock is listening
+func (m *MockController) IsActive() bool {
+	return m.active
+}
+
+// Said returns the texts passed to Say
+func (m *MockController) Said() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]string(nil), m.said...)
+}
+
+// Activate marks the mock as listening
+func (m *MockController) Activate() {
+	m.active = true
+}
+
+// Deactivate marks the mock as idle
+func (m *MockController) Deactivate() {
+	m.active = false
+}
+
+// IsPaused returns whether the mock is paused
+func (m *MockController) IsPaused() bool {
+	return m.paused
+}
+
+// Pause marks the mock as paused
+func (m *MockController) Pause() {
+	m.paused = true
+}
+
+// Resume marks the mock as running
+func (m *MockController) Resume() {
+	m.paused = false
+}
+
+// Say records the text
+func (m *MockController) Say(text string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.said = append(m.said, text)
+}
+
+// LastTranscript returns the configured transcript
+func (m *MockController) LastTranscript() string {
+	return m.lastTranscript
+}
@@ -0,0 +1,153 @@
+package dbus
+
+import (
+	"fmt"
+
+	godbus "github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+const (
+	// BusName is the well-known name owned on the session bus
+	BusName = "org.nrz.AI"
+	// ObjectPath is the path of the exported object
+	ObjectPath = godbus.ObjectPath("/org/nrz/AI")
+	// Interface is the name of the exported interface
+	Interface = "org.nrz.AI"
+)
+
+// introspection describes the exported interface for D-Bus tools
+const introspection = `
+<node>
+	<interface name="` + Interface + `">
+		<method name="Activate"/>
+		<method name="Deactivate"/>
+		<method name="Pause"/>
+		<method name="Resume"/>
+		<method name="Say">
+			<arg name="text" direction="in" type="s"/>
+		</method>
+		<method name="GetLastTranscript">
+			<arg name="text" direction="out" type="s"/>
+		</method>
+		<signal name="TranscriptReady">
+			<arg name="text" type="s"/>
+			<arg name="speaker" type="s"/>
+		</signal>
+		<signal name="StateChanged">
+			<arg name="state" type="s"/>
+		</signal>
+	</interface>` + introspect.IntrospectDataString + `</node>`
+
+// Service exposes the assistant on the session bus
+type Service struct {
+	conn       *godbus.Conn
+	controller Controller
+	emitSignal func(name string, values ...interface{}) error
+}
+
+// NewService creates a D-Bus service for controller, call Start to register it
+func NewService(controller Controller) *Service {
+	return &Service{controller: controller}
+}
+
+// Start connects to the session bus, exports the object and owns BusName
+func (s *Service) Start() error {
+	conn, err := godbus.ConnectSessionBus()
+	if err != nil {
+		return fmt.Errorf("failed to connect to session bus: %w", err)
+	}
+
+	if err := conn.Export(object{s.controller}, ObjectPath, Interface); err != nil {
+		conn.Close()
+		return err
+	}
+	if err := conn.Export(introspect.Introspectable(introspection), ObjectPath,
+		"org.freedesktop.DBus.Introspectable"); err != nil {
+		conn.Close()
+		return err
+	}
+
+	reply, err := conn.RequestName(BusName, godbus.NameFlagDoNotQueue)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to request name %s: %w", BusName, err)
+	}
+	if reply != godbus.RequestNameReplyPrimaryOwner {
+		conn.Close()
+		return fmt.Errorf("name %s already taken, is another nrz-ai running?", BusName)
+	}
+
+	s.conn = conn
+	s.emitSignal = func(name string, values ...interface{}) error {
+		return conn.Emit(ObjectPath, Interface+"."+name, values...)
+	}
+	return nil
+}
+
+// Emit turns transcript and state events into D-Bus signals
+func (s *Service) Emit(event output.Event) error {
+	if s.emitSignal == nil {
+		return nil
+	}
+
+	switch event.Type {
+	case output.EventTranscript:
+		return s.emitSignal("TranscriptReady", event.Text, event.Speaker)
+	case output.EventState:
+		return s.emitSignal("StateChanged", event.State)
+	default:
+		return nil
+	}
+}
+
+// Close releases the bus name and the connection
+func (s *Service) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	s.conn.ReleaseName(BusName)
+	return s.conn.Close()
+}
+
+// object is the exported D-Bus object, godbus requires a *godbus.Error result
+type object struct {
+	controller Controller
+}
+
+// Activate starts listening
+func (o object) Activate() *godbus.Error {
+	o.controller.Activate()
+	return nil
+}
+
+// Deactivate stops listening
+func (o object) Deactivate() *godbus.Error {
+	o.controller.Deactivate()
+	return nil
+}
+
+// Pause stops processing audio
+func (o object) Pause() *godbus.Error {
+	o.controller.Pause()
+	return nil
+}
+
+// Resume restarts audio processing
+func (o object) Resume() *godbus.Error {
+	o.controller.Resume()
+	return nil
+}
+
+// Say handles text as if it had been spoken
+func (o object) Say(text string) *godbus.Error {
+	// Run outside the D-Bus handler, the AI can take a while to answer
+	go o.controller.Say(text)
+	return nil
+}
+
+// GetLastTranscript returns the most recent final transcript
+func (o object) GetLastTranscript() (string, *godbus.Error) {
+	return o.controller.LastTranscript(), nil
+}
@@ -0,0 +1,134 @@
+package diarization
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+// speaker is a known speaker with its running-mean embedding
+type speaker struct {
+	label    string
+	centroid []float64
+	count    int
+}
+
+// ClusterDiarizer implements Diarizer with online clustering of per-segment
+// spectral embeddings: each segment joins the most similar known speaker, or
+// starts a new one when nobody is similar enough.
+type ClusterDiarizer struct {
+	config      Config
+	speakers    []*speaker
+	lastSpeaker string
+	mutex       sync.Mutex
+}
+
+// NewClusterDiarizer creates a new clustering diarizer
+func NewClusterDiarizer(config Config) *ClusterDiarizer {
+	defaults := DefaultConfig()
+	if config.SampleRate <= 0 {
+		config.SampleRate = defaults.SampleRate
+	}
+	if config.SimilarityThreshold <= 0 {
+		config.SimilarityThreshold = defaults.SimilarityThreshold
+	}
+	if config.MaxSpeakers <= 0 {
+		config.MaxSpeakers = defaults.MaxSpeakers
+	}
+
+	return &ClusterDiarizer{
+		config: config,
+	}
+}
+
+// Label assigns a speaker label to each segment
+func (d *ClusterDiarizer) Label(audio []float32, segments []whisper.Segment) []whisper.Segment {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	labeled := make([]whisper.Segment, len(segments))
+	copy(labeled, segments)
+
+	minSamples := d.config.MinSegmentMs * d.config.SampleRate / 1000
+
+	for i := range labeled {
+		start := int(labeled[i].Start * float64(d.config.SampleRate))
+		end := int(labeled[i].End * float64(d.config.SampleRate))
+		if start < 0 {
+			start = 0
+		}
+		if end > len(audio) || end <= start {
+			end = len(audio)
+		}
+
+		var embedding []float64
+		if end-start >= minSamples {
+			embedding = embed(audio[start:end], d.config.SampleRate)
+		}
+
+		if embedding == nil {
+			// Too short to tell: assume the same person kept talking
+			if d.lastSpeaker != "" {
+				labeled[i].Speaker = d.lastSpeaker
+				continue
+			}
+			embedding = embed(audio, d.config.SampleRate)
+		}
+
+		labeled[i].Speaker = d.assign(embedding)
+	}
+
+	return labeled
+}
+
+// assign matches an embedding to a speaker, creating one if needed
+func (d *ClusterDiarizer) assign(embedding []float64) string {
+	if embedding == nil {
+		return d.lastSpeaker
+	}
+
+	var best *speaker
+	bestSimilarity := -1.0
+	for _, s := range d.speakers {
+		similarity := cosineSimilarity(embedding, s.centroid)
+		if similarity > bestSimilarity {
+			best, bestSimilarity = s, similarity
+		}
+	}
+
+	if best == nil || (bestSimilarity < float64(d.config.SimilarityThreshold) &&
+		len(d.speakers) < d.config.MaxSpeakers) {
+		best = &speaker{
+			label:    fmt.Sprintf("Speaker %d", len(d.speakers)+1),
+			centroid: make([]float64, len(embedding)),
+		}
+		d.speakers = append(d.speakers, best)
+	}
+
+	// Update the running mean so the centroid follows the speaker's voice
+	best.count++
+	for i, v := range embedding {
+		best.centroid[i] += (v - best.centroid[i]) / float64(best.count)
+	}
+
+	d.lastSpeaker = best.label
+	return best.label
+}
+
+// Reset forgets all known speakers
+func (d *ClusterDiarizer) Reset() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.speakers = nil
+	d.lastSpeaker = ""
+}
+
+// SpeakerCount returns the number of distinct speakers seen so far
+func (d *ClusterDiarizer) SpeakerCount() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return len(d.speakers)
+}
@@ -0,0 +1,90 @@
+package diarization
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+// voice synthesizes a harmonic signal with a formant-like spectral envelope
+func voice(fundamental, formant float64, seconds float64, phase float64) []float32 {
+	n := int(seconds * 16000)
+	samples := make([]float32, n)
+	for h := 1; fundamental*float64(h) < 7000; h++ {
+		freq := fundamental * float64(h)
+		amplitude := math.Exp(-math.Pow((freq-formant)/600, 2))
+		for i := range samples {
+			t := float64(i) / 16000
+			samples[i] += float32(0.2 * amplitude * math.Sin(2*math.Pi*freq*t+phase*float64(h)))
+		}
+	}
+	return samples
+}
+
+func TestClusterDiarizer_SeparatesSpeakers(t *testing.T) {
+	diarizer := NewClusterDiarizer(DefaultConfig())
+
+	var audio []float32
+	audio = append(audio, voice(110, 500, 1.5, 0)...)
+	audio = append(audio, voice(230, 2500, 1.5, 0)...)
+	audio = append(audio, voice(112, 520, 1.5, 0.7)...)
+
+	segments := []whisper.Segment{
+		{Text: "Bonjour", Start: 0, End: 1.5},
+		{Text: "Salut", Start: 1.5, End: 3.0},
+		{Text: "Ça va ?", Start: 3.0, End: 4.5},
+	}
+
+	labeled := diarizer.Label(audio, segments)
+
+	if labeled[0].Speaker == "" {
+		t.Fatal("Expected segments to be labeled")
+	}
+	if labeled[0].Speaker == labeled[1].Speaker {
+		t.Errorf("Expected different speakers, got '%s' twice", labeled[0].Speaker)
+	}
+	if labeled[0].Speaker != labeled[2].Speaker {
+		t.Errorf("Expected same speaker, got '%s' and '%s'", labeled[0].Speaker, labeled[2].Speaker)
+	}
+	if diarizer.SpeakerCount() != 2 {
+		t.Errorf("Expected 2 speakers, got %d", diarizer.SpeakerCount())
+	}
+}
+
+func TestClusterDiarizer_ShortSegmentKeepsSpeaker(t *testing.T) {
+	diarizer := NewClusterDiarizer(DefaultConfig())
+
+	audio := voice(110, 500, 2.0, 0)
+	segments := []whisper.Segment{
+		{Text: "Alors", Start: 0, End: 1.5},
+		{Text: "euh", Start: 1.5, End: 1.6},
+	}
+
+	labeled := diarizer.Label(audio, segments)
+	if labeled[1].Speaker != labeled[0].Speaker {
+		t.Errorf("Expected short segment to inherit '%s', got '%s'", labeled[0].Speaker, labeled[1].Speaker)
+	}
+}
+
+func TestClusterDiarizer_Reset(t *testing.T) {
+	diarizer := NewClusterDiarizer(DefaultConfig())
+	diarizer.Label(voice(110, 500, 1.0, 0), []whisper.Segment{{Start: 0, End: 1.0}})
+
+	diarizer.Reset()
+
+	if diarizer.SpeakerCount() != 0 {
+		t.Errorf("Expected 0 speakers after reset, got %d", diarizer.SpeakerCount())
+	}
+}
+
+func TestFFT_Impulse(t *testing.T) {
+	x := make([]complex128, 8)
+	x[0] = 1
+	fft(x)
+	for i, v := range x {
+		if math.Abs(real(v)-1) > 1e-9 || math.Abs(imag(v)) > 1e-9 {
+			t.Errorf("Bin %d: expected 1, got %v", i, v)
+		}
+	}
+}
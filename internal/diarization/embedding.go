@@ -0,0 +1,209 @@
+package diarization
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+const (
+	frameSize   = 400 // 25ms at 16kHz
+	frameHop    = 160 // 10ms at 16kHz
+	fftSize     = 512
+	melBands    = 24
+	melMinHz    = 100.0
+	melMaxHz    = 7600.0
+	energyFloor = 1e-10
+)
+
+// embed computes a speaker embedding for the given samples: the mean and
+// standard deviation of gain-normalized log-mel energies over voiced frames.
+// Returns nil when the audio is too short to produce a single frame.
+func embed(samples []float32, sampleRate int) []float64 {
+	if len(samples) < frameSize {
+		return nil
+	}
+
+	filters := melFilterbank(sampleRate)
+	window := hammingWindow(frameSize)
+
+	var frames [][]float64
+	var energies []float64
+	buffer := make([]complex128, fftSize)
+
+	for start := 0; start+frameSize <= len(samples); start += frameHop {
+		var energy float64
+		for i := range buffer {
+			buffer[i] = 0
+		}
+		for i := 0; i < frameSize; i++ {
+			v := float64(samples[start+i])
+			energy += v * v
+			buffer[i] = complex(v*window[i], 0)
+		}
+
+		fft(buffer)
+
+		power := make([]float64, fftSize/2+1)
+		for i := range power {
+			power[i] = real(buffer[i])*real(buffer[i]) + imag(buffer[i])*imag(buffer[i])
+		}
+
+		bands := make([]float64, melBands)
+		var bandMean float64
+		for b, filter := range filters {
+			var sum float64
+			for i, weight := range filter {
+				sum += weight * power[i]
+			}
+			bands[b] = math.Log(sum + energyFloor)
+			bandMean += bands[b]
+		}
+
+		// Remove the frame gain so loudness does not drive the clustering
+		bandMean /= melBands
+		for b := range bands {
+			bands[b] -= bandMean
+		}
+
+		frames = append(frames, bands)
+		energies = append(energies, energy/frameSize)
+	}
+
+	voiced := voicedFrames(frames, energies)
+
+	embedding := make([]float64, 2*melBands)
+	for _, frame := range voiced {
+		for b, v := range frame {
+			embedding[b] += v
+		}
+	}
+	for b := 0; b < melBands; b++ {
+		embedding[b] /= float64(len(voiced))
+	}
+	for _, frame := range voiced {
+		for b, v := range frame {
+			d := v - embedding[b]
+			embedding[melBands+b] += d * d
+		}
+	}
+	for b := 0; b < melBands; b++ {
+		embedding[melBands+b] = math.Sqrt(embedding[melBands+b] / float64(len(voiced)))
+	}
+
+	return embedding
+}
+
+// Embed computes the speaker embedding of samples for voice prints, nil
+// when the audio is too short
+func Embed(samples []float32, sampleRate int) []float64 {
+	return embed(samples, sampleRate)
+}
+
+// Similarity returns the cosine similarity of two embeddings from Embed
+func Similarity(a, b []float64) float64 {
+	return cosineSimilarity(a, b)
+}
+
+// voicedFrames keeps frames louder than half the average energy, falling back
+// to all frames when none qualifies
+func voicedFrames(frames [][]float64, energies []float64) [][]float64 {
+	var mean float64
+	for _, e := range energies {
+		mean += e
+	}
+	mean /= float64(len(energies))
+
+	var voiced [][]float64
+	for i, frame := range frames {
+		if energies[i] >= mean/2 {
+			voiced = append(voiced, frame)
+		}
+	}
+	if len(voiced) == 0 {
+		return frames
+	}
+	return voiced
+}
+
+// cosineSimilarity returns the cosine similarity of two vectors
+func cosineSimilarity(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// melFilterbank builds triangular mel filters over the FFT bins
+func melFilterbank(sampleRate int) [][]float64 {
+	toMel := func(hz float64) float64 { return 2595 * math.Log10(1+hz/700) }
+	toHz := func(mel float64) float64 { return 700 * (math.Pow(10, mel/2595) - 1) }
+
+	maxHz := math.Min(melMaxHz, float64(sampleRate)/2)
+	minMel, maxMel := toMel(melMinHz), toMel(maxHz)
+
+	bins := make([]int, melBands+2)
+	for i := range bins {
+		hz := toHz(minMel + (maxMel-minMel)*float64(i)/float64(melBands+1))
+		bins[i] = int(math.Floor((fftSize + 1) * hz / float64(sampleRate)))
+	}
+
+	filters := make([][]float64, melBands)
+	for b := 0; b < melBands; b++ {
+		filter := make([]float64, fftSize/2+1)
+		left, center, right := bins[b], bins[b+1], bins[b+2]
+		for i := left; i < center; i++ {
+			filter[i] = float64(i-left) / float64(center-left)
+		}
+		for i := center; i < right; i++ {
+			filter[i] = float64(right-i) / float64(right-center)
+		}
+		filters[b] = filter
+	}
+	return filters
+}
+
+// hammingWindow returns a Hamming window of length n
+func hammingWindow(n int) []float64 {
+	window := make([]float64, n)
+	for i := range window {
+		window[i] = 0.54 - 0.46*math.Cos(2*math.Pi*float64(i)/float64(n-1))
+	}
+	return window
+}
+
+// fft computes an in-place radix-2 FFT; len(x) must be a power of two
+func fft(x []complex128) {
+	n := len(x)
+
+	// Bit-reversal permutation
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				even := x[start+k]
+				odd := w * x[start+k+size/2]
+				x[start+k] = even + odd
+				x[start+k+size/2] = even - odd
+				w *= step
+			}
+		}
+	}
+}
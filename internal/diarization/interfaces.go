@@ -0,0 +1,34 @@
+package diarization
+
+import "github.com/nerzhul/nrz-ai/pkg/whisper"
+
+// Diarizer assigns speaker labels to transcribed segments
+type Diarizer interface {
+	// Label returns the segments with their Speaker field set, using the audio
+	// samples the segments were transcribed from
+	Label(audio []float32, segments []whisper.Segment) []whisper.Segment
+
+	// Reset forgets all known speakers
+	Reset()
+
+	// SpeakerCount returns the number of distinct speakers seen so far
+	SpeakerCount() int
+}
+
+// Config holds speaker diarization configuration
+type Config struct {
+	SampleRate          int
+	SimilarityThreshold float32 // Minimum cosine similarity to match a known speaker
+	MaxSpeakers         int     // Upper bound on distinct speakers
+	MinSegmentMs        int     // Shorter segments inherit the previous speaker
+}
+
+// DefaultConfig returns sensible diarization defaults for 16kHz audio
+func DefaultConfig() Config {
+	return Config{
+		SampleRate:          16000,
+		SimilarityThreshold: 0.85,
+		MaxSpeakers:         8,
+		MinSegmentMs:        500,
+	}
+}
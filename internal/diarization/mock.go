@@ -0,0 +1,54 @@
+package diarization
+
+import "github.com/nerzhul/nrz-ai/pkg/whisper"
+
+// MockDiarizer implements Diarizer for testing
+type MockDiarizer struct {
+	labels   []string
+	index    int
+	speakers map[string]bool
+}
+
+// NewMockDiarizer creates a mock diarizer
+func NewMockDiarizer() *MockDiarizer {
+	return &MockDiarizer{
+		speakers: make(map[string]bool),
+	}
+}
+
+// SetLabels sets the labels assigned to successive segments
+func (m *MockDiarizer) SetLabels(labels []string) {
+	m.labels = labels
+	m.index = 0
+}
+
+// Label assigns the configured labels in order, looping when exhausted
+func (m *MockDiarizer) Label(audio []float32, segments []whisper.Segment) []whisper.Segment {
+	labeled := make([]whisper.Segment, len(segments))
+	copy(labeled, segments)
+
+	if len(m.labels) == 0 {
+		return labeled
+	}
+
+	for i := range labeled {
+		if m.index >= len(m.labels) {
+			m.index = 0
+		}
+		labeled[i].Speaker = m.labels[m.index]
+		m.speakers[m.labels[m.index]] = true
+		m.index++
+	}
+	return labeled
+}
+
+// Reset forgets all known speakers
+func (m *MockDiarizer) Reset() {
+	m.index = 0
+	m.speakers = make(map[string]bool)
+}
+
+// SpeakerCount returns the number of distinct labels handed out
+func (m *MockDiarizer) SpeakerCount() int {
+	return len(m.speakers)
+}
@@ -0,0 +1,38 @@
+package dictation
+
+import (
+	"sync"
+
+	"github.com/nerzhul/nrz-ai/internal/textproc"
+)
+
+// Dictation turns transcripts into typed text, applying voice commands and
+// the punctuation and capitalization rules of the language
+type Dictation struct {
+	injector  Injector
+	formatter *textproc.Formatter
+	mutex     sync.Mutex // The language can change while dictating
+}
+
+// NewDictation creates a dictation session typing through injector
+func NewDictation(injector Injector, language string) *Dictation {
+	return &Dictation{
+		injector:  injector,
+		formatter: textproc.NewFormatter(language),
+	}
+}
+
+// SetLanguage switches to the voice commands and punctuation rules of
+// language
+func (d *Dictation) SetLanguage(language string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.formatter.SetLanguage(language)
+}
+
+// Dictate types a transcript into the focused window
+func (d *Dictation) Dictate(text string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.injector.Type(d.formatter.Format(text))
+}
@@ -0,0 +1,56 @@
+package dictation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDictation_FrenchCommands(t *testing.T) {
+	injector := NewMockInjector()
+	d := NewDictation(injector, "fr")
+
+	if err := d.Dictate("Bonjour, virgule comment ça va point d'interrogation à la ligne Très bien point"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := "Bonjour, comment ça va\u00a0?\nTrès bien."
+	if typed := injector.Typed(); len(typed) != 1 || typed[0] != expected {
+		t.Errorf("Expected %q, got %q", expected, typed)
+	}
+}
+
+func TestDictation_SpacingAcrossUtterances(t *testing.T) {
+	injector := NewMockInjector()
+	d := NewDictation(injector, "fr")
+
+	d.Dictate("Premier essai")
+	d.Dictate("virgule second")
+	d.Dictate("nouvelle ligne")
+	d.Dictate("Fin")
+
+	expected := []string{"Premier essai", ", second", "\n", "Fin"}
+	typed := injector.Typed()
+	if len(typed) != len(expected) {
+		t.Fatalf("Expected %d typed texts, got %d", len(expected), len(typed))
+	}
+	for i := range expected {
+		if typed[i] != expected[i] {
+			t.Errorf("Utterance %d: expected %q, got %q", i, expected[i], typed[i])
+		}
+	}
+}
+
+func TestDictation_TypeError(t *testing.T) {
+	injector := NewMockInjector()
+	injector.SetTypeError(errors.New("no display"))
+
+	if err := NewDictation(injector, "fr").Dictate("Bonjour"); err == nil {
+		t.Error("Expected error when injector fails")
+	}
+}
+
+func TestNewInjector_UnknownBackend(t *testing.T) {
+	if _, err := NewInjector("telepathy"); err == nil {
+		t.Error("Expected error for unknown backend")
+	}
+}
@@ -0,0 +1,77 @@
+package dictation
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CommandInjector implements Injector by running a keystroke tool
+type CommandInjector struct {
+	name string
+	args func(text string) []string
+}
+
+// backends maps supported tools to their command line
+var backends = map[string]func(text string) []string{
+	"xdotool": func(text string) []string { return []string{"type", "--clearmodifiers", "--", text} },
+	"wtype":   func(text string) []string { return []string{"--", text} },
+	"ydotool": func(text string) []string { return []string{"type", "--", text} },
+}
+
+// NewInjector creates an injector for the given backend. "auto" picks wtype
+// or ydotool on Wayland and xdotool on X11, whichever is installed.
+func NewInjector(backend string) (*CommandInjector, error) {
+	if backend == "auto" {
+		return detectInjector()
+	}
+
+	args, ok := backends[backend]
+	if !ok {
+		return nil, fmt.Errorf("unknown dictation backend '%s' (expected auto, xdotool, wtype or ydotool)", backend)
+	}
+	if _, err := exec.LookPath(backend); err != nil {
+		return nil, fmt.Errorf("dictation backend '%s' not found in PATH", backend)
+	}
+
+	return &CommandInjector{name: backend, args: args}, nil
+}
+
+// detectInjector returns the first available backend for the current session
+func detectInjector() (*CommandInjector, error) {
+	candidates := []string{"xdotool", "ydotool"}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		candidates = []string{"wtype", "ydotool"}
+	}
+
+	for _, name := range candidates {
+		if _, err := exec.LookPath(name); err == nil {
+			return &CommandInjector{name: name, args: backends[name]}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no dictation backend found, install one of: %s", strings.Join(candidates, ", "))
+}
+
+// Type runs the backend to type text
+func (c *CommandInjector) Type(text string) error {
+	if text == "" {
+		return nil
+	}
+
+	cmd := exec.Command(c.name, c.args(text)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", c.name, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// Name returns the backend name
+func (c *CommandInjector) Name() string {
+	return c.name
+}
@@ -0,0 +1,10 @@
+package dictation
+
+// Injector types text into the focused window
+type Injector interface {
+	// Type sends text as keystrokes, "\n" is typed as Return
+	Type(text string) error
+
+	// Name returns the backend name
+	Name() string
+}
@@ -0,0 +1,36 @@
+package dictation
+
+// MockInjector implements Injector for testing
+type MockInjector struct {
+	typed     []string
+	typeError error
+}
+
+// NewMockInjector creates a new mock injector
+func NewMockInjector() *MockInjector {
+	return &MockInjector{}
+}
+
+// SetTypeError sets an error to return for Type calls
+func (m *MockInjector) SetTypeError(err error) {
+	m.typeError = err
+}
+
+// Typed returns the texts passed to Type
+func (m *MockInjector) Typed() []string {
+	return m.typed
+}
+
+// Type records the text
+func (m *MockInjector) Type(text string) error {
+	if m.typeError != nil {
+		return m.typeError
+	}
+	m.typed = append(m.typed, text)
+	return nil
+}
+
+// Name returns the mock name
+func (m *MockInjector) Name() string {
+	return "mock"
+}
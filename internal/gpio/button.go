@@ -0,0 +1,103 @@
+package gpio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SysfsRoot is the kernel GPIO sysfs interface
+	SysfsRoot = "/sys/class/gpio"
+
+	pollInterval = 20 * time.Millisecond
+	// Contact bounces within this delay are not new presses
+	debounceDelay = 200 * time.Millisecond
+)
+
+// Button watches a push-button wired to a GPIO input, e.g. on a Raspberry
+// Pi header, by polling its sysfs value
+type Button struct {
+	root      string
+	pin       int
+	activeLow bool
+	onPress   func()
+	done      chan struct{}
+}
+
+// NewButton creates a button on pin calling onPress on every press. With
+// activeLow a press reads 0, for buttons pulling the pin to ground.
+func NewButton(pin int, activeLow bool, onPress func()) *Button {
+	return &Button{
+		root:      SysfsRoot,
+		pin:       pin,
+		activeLow: activeLow,
+		onPress:   onPress,
+		done:      make(chan struct{}),
+	}
+}
+
+// Start exports the pin as an input and watches it until Close
+func (b *Button) Start() error {
+	dir := filepath.Join(b.root, fmt.Sprintf("gpio%d", b.pin))
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := os.WriteFile(filepath.Join(b.root, "export"), []byte(strconv.Itoa(b.pin)), 0200); err != nil {
+			return fmt.Errorf("failed to export GPIO %d: %w", b.pin, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "direction"), []byte("in"), 0200); err != nil {
+		return fmt.Errorf("failed to set GPIO %d as input: %w", b.pin, err)
+	}
+
+	valuePath := filepath.Join(dir, "value")
+	pressed, err := b.pressed(valuePath)
+	if err != nil {
+		return err
+	}
+
+	go b.watch(valuePath, pressed)
+	return nil
+}
+
+// watch polls the pin and reports presses, i.e. released to pressed edges
+func (b *Button) watch(valuePath string, pressed bool) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var lastPress time.Time
+	for {
+		select {
+		case <-ticker.C:
+		case <-b.done:
+			return
+		}
+
+		now, err := b.pressed(valuePath)
+		if err != nil {
+			continue
+		}
+		if now && !pressed && time.Since(lastPress) >= debounceDelay {
+			lastPress = time.Now()
+			b.onPress()
+		}
+		pressed = now
+	}
+}
+
+// pressed reads whether the button is held down
+func (b *Button) pressed(valuePath string) (bool, error) {
+	data, err := os.ReadFile(valuePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read GPIO %d: %w", b.pin, err)
+	}
+	high := strings.TrimSpace(string(data)) == "1"
+	return high != b.activeLow, nil
+}
+
+// Close stops watching the pin
+func (b *Button) Close() {
+	close(b.done)
+}
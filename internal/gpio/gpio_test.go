@@ -0,0 +1,82 @@
+package gpio
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestButton creates a button on a fake sysfs tree with gpio17 exported
+func newTestButton(t *testing.T, activeLow bool) (*Button, string, *atomic.Int32) {
+	t.Helper()
+
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "gpio17"), 0755)
+	value := filepath.Join(root, "gpio17", "value")
+	initial := "0\n"
+	if activeLow {
+		initial = "1\n"
+	}
+	os.WriteFile(value, []byte(initial), 0644)
+
+	var presses atomic.Int32
+	button := NewButton(17, activeLow, func() { presses.Add(1) })
+	button.root = root
+	return button, value, &presses
+}
+
+func waitPresses(presses *atomic.Int32, expected int32) int32 {
+	deadline := time.Now().Add(time.Second)
+	for presses.Load() < expected && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	return presses.Load()
+}
+
+func TestButton_ReportsPresses(t *testing.T) {
+	button, value, presses := newTestButton(t, false)
+	if err := button.Start(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer button.Close()
+
+	direction, _ := os.ReadFile(filepath.Join(button.root, "gpio17", "direction"))
+	if string(direction) != "in" {
+		t.Errorf("Expected the pin to be set as input, got %q", direction)
+	}
+
+	os.WriteFile(value, []byte("1\n"), 0644)
+	if got := waitPresses(presses, 1); got != 1 {
+		t.Errorf("Expected 1 press, got %d", got)
+	}
+
+	// Held down: still a single press
+	time.Sleep(5 * pollInterval)
+	if got := presses.Load(); got != 1 {
+		t.Errorf("Expected a held button to count once, got %d", got)
+	}
+}
+
+func TestButton_ActiveLow(t *testing.T) {
+	button, value, presses := newTestButton(t, true)
+	if err := button.Start(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer button.Close()
+
+	os.WriteFile(value, []byte("0\n"), 0644)
+	if got := waitPresses(presses, 1); got != 1 {
+		t.Errorf("Expected a low level to be a press, got %d presses", got)
+	}
+}
+
+func TestButton_ExportFails(t *testing.T) {
+	button := NewButton(4, false, func() {})
+	button.root = filepath.Join(t.TempDir(), "missing")
+
+	if err := button.Start(); err == nil {
+		t.Error("Expected error when the pin cannot be exported")
+	}
+}
@@ -0,0 +1,87 @@
+// Package hardware detects the acceleration available to whisper and picks
+// the largest model transcribing in real time on it
+package hardware
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Accelerators
+const (
+	AcceleratorCPU   = "cpu"
+	AcceleratorCUDA  = "cuda"
+	AcceleratorROCm  = "rocm"
+	AcceleratorMetal = "metal"
+)
+
+// detectTimeout bounds the tools queried for the devices
+const detectTimeout = 3 * time.Second
+
+// Capabilities is the hardware whisper runs on
+type Capabilities struct {
+	Accelerator string // One of the Accelerator constants
+	Device      string // Name of the GPU, if known
+	Cores       int    // CPU cores
+	// RenderNode is a GPU found without any runtime whisper can use
+	RenderNode string
+}
+
+// Detect probes the GPU runtimes and counts the CPU cores
+func Detect() Capabilities {
+	caps := Capabilities{Accelerator: AcceleratorCPU, Cores: runtime.NumCPU()}
+
+	switch {
+	case runtime.GOOS == "darwin" && runtime.GOARCH == "arm64":
+		caps.Accelerator = AcceleratorMetal
+		caps.Device = "Apple Silicon"
+	case exists("/dev/kfd"):
+		caps.Accelerator = AcceleratorROCm
+		caps.Device = "AMD ROCm device (/dev/kfd)"
+	default:
+		if name, err := query("nvidia-smi", "--query-gpu=name", "--format=csv,noheader"); err == nil && name != "" {
+			caps.Accelerator = AcceleratorCUDA
+			caps.Device = "NVIDIA " + strings.Split(name, "\n")[0]
+		} else if matches, _ := filepath.Glob("/dev/dri/renderD*"); len(matches) > 0 {
+			caps.RenderNode = matches[0]
+		}
+	}
+	return caps
+}
+
+// GPU reports whether whisper can run on a GPU
+func (c Capabilities) GPU() bool {
+	return c.Accelerator != AcceleratorCPU
+}
+
+// String describes the capabilities for the logs
+func (c Capabilities) String() string {
+	if c.GPU() {
+		return fmt.Sprintf("%s (%s), %d CPU cores", c.Device, c.Accelerator, c.Cores)
+	}
+	return fmt.Sprintf("no GPU, %d CPU cores", c.Cores)
+}
+
+// exists reports whether path exists
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// query runs a tool and returns its trimmed output
+func query(name string, args ...string) (string, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), detectTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, name, args...).Output()
+	return strings.TrimSpace(string(output)), err
+}
@@ -0,0 +1,67 @@
+package hardware
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCapabilities_Recommend(t *testing.T) {
+	tests := []struct {
+		caps     Capabilities
+		expected string
+	}{
+		{Capabilities{Accelerator: AcceleratorCPU, Cores: 1}, "tiny"},
+		{Capabilities{Accelerator: AcceleratorCPU, Cores: 8}, "small"},
+		{Capabilities{Accelerator: AcceleratorCPU, Cores: 32}, "medium"},
+		{Capabilities{Accelerator: AcceleratorMetal, Cores: 8}, "large-v3-turbo"},
+		{Capabilities{Accelerator: AcceleratorCUDA, Cores: 2}, "large-v3"},
+	}
+
+	for _, test := range tests {
+		if got := test.caps.Recommend(); got != test.expected {
+			t.Errorf("Expected %s for %s, got %s", test.expected, test.caps, got)
+		}
+	}
+	if (Capabilities{Accelerator: AcceleratorCUDA}).Sustains("huge") {
+		t.Error("Expected an unknown model not to be sustained")
+	}
+}
+
+func TestSelectModel(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	for _, path := range []string{
+		filepath.Join(first, "ggml-base.bin"),
+		filepath.Join(second, "ggml-small.bin"),
+		filepath.Join(second, "ggml-large-v3.bin"),
+	} {
+		if err := os.WriteFile(path, []byte("ggml"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dirs := []string{first, second}
+
+	path, name := SelectModel(Capabilities{Accelerator: AcceleratorCPU, Cores: 8}, dirs)
+	if name != "small" || path != filepath.Join(second, "ggml-small.bin") {
+		t.Errorf("Expected small on 8 cores, got %s (%s)", name, path)
+	}
+	if _, name := SelectModel(Capabilities{Accelerator: AcceleratorCUDA}, dirs); name != "large-v3" {
+		t.Errorf("Expected large-v3 on CUDA, got %s", name)
+	}
+	if path, name := SelectModel(Capabilities{Accelerator: AcceleratorCPU, Cores: 1}, dirs); path != "" || name != "" {
+		t.Errorf("Expected no model on a single core, got %s (%s)", name, path)
+	}
+}
+
+func TestModelDirs(t *testing.T) {
+	dir := t.TempDir()
+	dirs := ModelDirs(filepath.Join(dir, "ggml-base.bin"), []string{dir, filepath.Join(dir, "missing")})
+	if len(dirs) == 0 || dirs[0] != dir {
+		t.Fatalf("Expected the directory of the configured model first, got %v", dirs)
+	}
+	for _, got := range dirs {
+		if got == filepath.Join(dir, "missing", "models") {
+			t.Errorf("Expected missing directories to be dropped, got %v", dirs)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package hardware
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// model is a whisper model with the hardware transcribing it in real time.
+// CUDA and ROCm cards run them all.
+type model struct {
+	name  string
+	cores int  // CPU cores needed without a GPU, 0 for none
+	metal bool // Real time on Apple Silicon
+}
+
+// models lists the whisper models from the smallest. The needs are
+// conservative figures for whisper.cpp keeping up with live speech.
+var models = []model{
+	{name: "tiny", cores: 1, metal: true},
+	{name: "base", cores: 2, metal: true},
+	{name: "small", cores: 6, metal: true},
+	{name: "medium", cores: 12, metal: true},
+	{name: "large-v3-turbo", metal: true},
+	{name: "large-v3"},
+}
+
+// Sustains reports whether name transcribes in real time on c, false for
+// an unknown model
+func (c Capabilities) Sustains(name string) bool {
+	for _, m := range models {
+		if m.name != name {
+			continue
+		}
+		switch c.Accelerator {
+		case AcceleratorCUDA, AcceleratorROCm:
+			return true
+		case AcceleratorMetal:
+			return m.metal
+		default:
+			return m.cores > 0 && c.Cores >= m.cores
+		}
+	}
+	return false
+}
+
+// Recommend returns the largest model transcribing in real time on c
+func (c Capabilities) Recommend() string {
+	best := models[0].name
+	for _, m := range models {
+		if c.Sustains(m.name) {
+			best = m.name
+		}
+	}
+	return best
+}
+
+// SelectModel returns the path and name of the largest model sustained by
+// c among the ggml-<name>.bin files of dirs, empty when none is installed
+func SelectModel(c Capabilities, dirs []string) (path, name string) {
+	for i := len(models) - 1; i >= 0; i-- {
+		if !c.Sustains(models[i].name) {
+			continue
+		}
+		for _, dir := range dirs {
+			candidate := filepath.Join(dir, "ggml-"+models[i].name+".bin")
+			if exists(candidate) {
+				return candidate, models[i].name
+			}
+		}
+	}
+	return "", ""
+}
+
+// ModelDirs returns the directories holding models: the one of configured,
+// models and the models directory of dataDirs, without duplicates
+func ModelDirs(configured string, dataDirs []string) []string {
+	dirs := []string{filepath.Dir(configured), "models"}
+	for _, dir := range dataDirs {
+		dirs = append(dirs, filepath.Join(dir, "models"))
+	}
+
+	seen := make(map[string]bool, len(dirs))
+	unique := dirs[:0]
+	for _, dir := range dirs {
+		clean := filepath.Clean(dir)
+		if seen[clean] {
+			continue
+		}
+		seen[clean] = true
+		if info, err := os.Stat(clean); err == nil && info.IsDir() {
+			unique = append(unique, clean)
+		}
+	}
+	return unique
+}
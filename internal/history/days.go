@@ -0,0 +1,46 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// weekdays maps the English and French day names to their weekday
+var weekdays = map[string]time.Weekday{
+	"monday": time.Monday, "lundi": time.Monday,
+	"tuesday": time.Tuesday, "mardi": time.Tuesday,
+	"wednesday": time.Wednesday, "mercredi": time.Wednesday,
+	"thursday": time.Thursday, "jeudi": time.Thursday,
+	"friday": time.Friday, "vendredi": time.Friday,
+	"saturday": time.Saturday, "samedi": time.Saturday,
+	"sunday": time.Sunday, "dimanche": time.Sunday,
+}
+
+// ParseDay returns the start of the day named by value, relative to now:
+// a 2006-01-02 date, today, yesterday or a day name for the last such day
+// before today ("tuesday" on a Tuesday is a week ago)
+func ParseDay(value string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	name := strings.ToLower(strings.TrimSpace(value))
+	switch name {
+	case "today", "aujourd'hui":
+		return today, nil
+	case "yesterday", "hier":
+		return today.AddDate(0, 0, -1), nil
+	}
+	if weekday, ok := weekdays[name]; ok {
+		days := (int(today.Weekday()) - int(weekday) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, -days), nil
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", name, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid day '%s' (expected YYYY-MM-DD, today, yesterday or a day name)", value)
+	}
+	return day, nil
+}
@@ -0,0 +1,336 @@
+// Package history indexes the archived session transcripts in a SQLite
+// full-text index, to search what was said across sessions
+package history
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/archive"
+	"github.com/nerzhul/nrz-ai/internal/privacy"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+
+	// Pure Go SQLite driver, built with FTS5
+	_ "modernc.org/sqlite"
+)
+
+// DefaultLimit is the number of matches returned when Query.Limit is not set
+const DefaultLimit = 20
+
+// schema creates the index tables. Events keep the order of their session
+// for the context lookups, events_fts holds their text with accents and case
+// folded so "chauffe" also finds "Chauffé".
+const schema = `
+CREATE TABLE IF NOT EXISTS sessions (
+	id       TEXT PRIMARY KEY,
+	start    INTEGER NOT NULL,
+	mode     TEXT NOT NULL,
+	language TEXT NOT NULL,
+	size     INTEGER NOT NULL,
+	modified INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS events (
+	id      INTEGER PRIMARY KEY,
+	session TEXT NOT NULL,
+	seq     INTEGER NOT NULL,
+	time    INTEGER NOT NULL,
+	type    TEXT NOT NULL,
+	speaker TEXT NOT NULL,
+	text    TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS events_session ON events (session, seq);
+CREATE VIRTUAL TABLE IF NOT EXISTS events_fts USING fts5(text, tokenize = 'unicode61 remove_diacritics 2');
+`
+
+// Index is the full-text index of the archived sessions, a cache rebuilt
+// from the archive files by Sync
+type Index struct {
+	db *sql.DB
+}
+
+// Open opens or creates the index at path
+func Open(path string) (*Index, error) {
+	if err := privacy.Check("history index"); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	// The index holds transcripts, only its owner may read it
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	file.Close()
+
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create the history index: %w", err)
+	}
+	return &Index{db: db}, nil
+}
+
+// Close closes the index
+func (i *Index) Close() error {
+	return i.db.Close()
+}
+
+// Sync indexes the sessions of archiveDir that changed since the last sync
+// and forgets the removed ones, returning the number of sessions indexed
+func (i *Index) Sync(archiveDir string) (int, error) {
+	sessions, err := archive.List(archiveDir)
+	if err != nil {
+		return 0, err
+	}
+
+	archived := make(map[string]bool, len(sessions))
+	indexed := 0
+	for _, session := range sessions {
+		archived[session.ID] = true
+		info, err := os.Stat(archive.File(archiveDir, session.ID))
+		if err != nil {
+			return indexed, err
+		}
+
+		var size, modified int64
+		err = i.db.QueryRow(`SELECT size, modified FROM sessions WHERE id = ?`, session.ID).Scan(&size, &modified)
+		if err == nil && size == info.Size() && modified == info.ModTime().UnixNano() {
+			continue
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return indexed, err
+		}
+
+		// The live session keeps growing, index it again from the start
+		_, events, err := archive.Open(archiveDir, session.ID)
+		if err != nil {
+			return indexed, err
+		}
+		if err := i.index(session, events, info); err != nil {
+			return indexed, fmt.Errorf("failed to index session %s: %w", session.ID, err)
+		}
+		indexed++
+	}
+
+	rows, err := i.db.Query(`SELECT id FROM sessions`)
+	if err != nil {
+		return indexed, err
+	}
+	var removed []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return indexed, err
+		}
+		if !archived[id] {
+			removed = append(removed, id)
+		}
+	}
+	rows.Close()
+	for _, id := range removed {
+		if err := i.forget(i.db, id); err != nil {
+			return indexed, err
+		}
+	}
+	return indexed, rows.Err()
+}
+
+// execer runs statements on the database or in a transaction
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// forget removes a session from the index
+func (i *Index) forget(db execer, id string) error {
+	if _, err := db.Exec(`DELETE FROM events_fts WHERE rowid IN (SELECT id FROM events WHERE session = ?)`, id); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`DELETE FROM events WHERE session = ?`, id); err != nil {
+		return err
+	}
+	_, err := db.Exec(`DELETE FROM sessions WHERE id = ?`, id)
+	return err
+}
+
+// index replaces the indexed events of session
+func (i *Index) index(session archive.Session, events []output.Event, info os.FileInfo) error {
+	tx, err := i.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := i.forget(tx, session.ID); err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO sessions (id, start, mode, language, size, modified) VALUES (?, ?, ?, ?, ?, ?)`,
+		session.ID, session.Start.UnixNano(), session.Mode, session.Language, info.Size(), info.ModTime().UnixNano())
+	if err != nil {
+		return err
+	}
+	for seq, event := range events {
+		result, err := tx.Exec(`INSERT INTO events (session, seq, time, type, speaker, text) VALUES (?, ?, ?, ?, ?, ?)`,
+			session.ID, seq, event.Time.UnixNano(), string(event.Type), event.Speaker, event.Text)
+		if err != nil {
+			return err
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`INSERT INTO events_fts (rowid, text) VALUES (?, ?)`, id, event.Text); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Query selects the utterances to search
+type Query struct {
+	Text    string    // Words that must all appear, accents and case ignored
+	Since   time.Time // Earliest utterance, zero for no limit
+	Until   time.Time // Utterances before this time, zero for no limit
+	Session string    // Session ID or archive.Latest, empty for all sessions
+	Context int       // Utterances returned around each match
+	Limit   int       // Maximum number of matches, DefaultLimit if not set
+}
+
+// Match is an utterance matching a query, with the utterances around it
+type Match struct {
+	Session string
+	Event   output.Event
+	Before  []output.Event
+	After   []output.Event
+}
+
+// Search returns the utterances matching query, most recent first
+func (i *Index) Search(query Query) ([]Match, error) {
+	match := matchExpression(query.Text)
+	if match == "" {
+		return nil, errors.New("nothing to search for")
+	}
+
+	conditions := []string{"events_fts MATCH ?"}
+	args := []any{match}
+	if !query.Since.IsZero() {
+		conditions = append(conditions, "e.time >= ?")
+		args = append(args, query.Since.UnixNano())
+	}
+	if !query.Until.IsZero() {
+		conditions = append(conditions, "e.time < ?")
+		args = append(args, query.Until.UnixNano())
+	}
+	if query.Session != "" {
+		session := query.Session
+		if session == archive.Latest {
+			err := i.db.QueryRow(`SELECT id FROM sessions ORDER BY start DESC LIMIT 1`).Scan(&session)
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, archive.ErrNotFound
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		conditions = append(conditions, "e.session = ?")
+		args = append(args, session)
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	args = append(args, limit)
+
+	rows, err := i.db.Query(`SELECT e.session, e.seq, e.time, e.type, e.speaker, e.text
+		FROM events_fts JOIN events e ON e.id = events_fts.rowid
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY e.time DESC LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	var matches []Match
+	var seqs []int
+	for rows.Next() {
+		var m Match
+		var seq int
+		event, err := scanEvent(rows, &m.Session, &seq)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		m.Event = event
+		matches = append(matches, m)
+		seqs = append(seqs, seq)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if query.Context > 0 {
+		for n := range matches {
+			if err := i.addContext(&matches[n], seqs[n], query.Context); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return matches, nil
+}
+
+// addContext fills in the utterances around a match
+func (i *Index) addContext(m *Match, seq, context int) error {
+	rows, err := i.db.Query(`SELECT session, seq, time, type, speaker, text FROM events
+		WHERE session = ? AND seq BETWEEN ? AND ? AND seq != ? ORDER BY seq`,
+		m.Session, seq-context, seq+context, seq)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var session string
+		var other int
+		event, err := scanEvent(rows, &session, &other)
+		if err != nil {
+			return err
+		}
+		if other < seq {
+			m.Before = append(m.Before, event)
+		} else {
+			m.After = append(m.After, event)
+		}
+	}
+	return rows.Err()
+}
+
+// scanEvent reads an events row
+func scanEvent(rows *sql.Rows, session *string, seq *int) (output.Event, error) {
+	var event output.Event
+	var nanos int64
+	var eventType string
+	if err := rows.Scan(session, seq, &nanos, &eventType, &event.Speaker, &event.Text); err != nil {
+		return output.Event{}, err
+	}
+	event.Time = time.Unix(0, nanos)
+	event.Type = output.EventType(eventType)
+	return event, nil
+}
+
+// matchExpression quotes each word of text for FTS5, so punctuation such
+// as "l'eau" is searched rather than parsed as query syntax
+func matchExpression(text string) string {
+	var terms []string
+	for _, word := range strings.Fields(text) {
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"`)
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,158 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/archive"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+// archiveSession archives one utterance per text, a second apart
+func archiveSession(t *testing.T, dir string, start time.Time, texts ...string) string {
+	t.Helper()
+
+	id := archive.NewID(start)
+	w, err := archive.Create(dir, archive.Session{ID: id, Start: start, Mode: "assistant", Language: "fr"})
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	for n, text := range texts {
+		event := output.Event{Type: output.EventTranscript, Time: start.Add(time.Duration(n) * time.Second), Text: text}
+		if err := w.Emit(event); err != nil {
+			t.Fatalf("Failed to archive event: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close archive: %v", err)
+	}
+	return id
+}
+
+// openIndex opens an index synced with dir
+func openIndex(t *testing.T, dir string) *Index {
+	t.Helper()
+
+	index, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("Failed to open index: %v", err)
+	}
+	t.Cleanup(func() { index.Close() })
+	if _, err := index.Sync(dir); err != nil {
+		t.Fatalf("Failed to sync index: %v", err)
+	}
+	return index
+}
+
+func TestSearch_AccentsAndContext(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 6, 11, 20, 0, 0, 0, time.Local)
+	archiveSession(t, dir, start, "Il fait froid ce soir.", "Monte le Chauffage à 21 degrés.", "C'est fait.", "Merci.")
+
+	matches, err := openIndex(t, dir).Search(Query{Text: "chauffage degres", Context: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("Expected one match, got %+v", matches)
+	}
+	m := matches[0]
+	if m.Event.Text != "Monte le Chauffage à 21 degrés." || !m.Event.Time.Equal(start.Add(time.Second)) {
+		t.Errorf("Unexpected match %+v", m.Event)
+	}
+	if len(m.Before) != 1 || m.Before[0].Text != "Il fait froid ce soir." || len(m.After) != 1 || m.After[0].Text != "C'est fait." {
+		t.Errorf("Expected one utterance around the match, got %+v / %+v", m.Before, m.After)
+	}
+}
+
+func TestSearch_Filters(t *testing.T) {
+	dir := t.TempDir()
+	tuesday := time.Date(2024, 6, 11, 20, 0, 0, 0, time.Local)
+	first := archiveSession(t, dir, tuesday, "Allume le chauffage")
+	last := archiveSession(t, dir, tuesday.AddDate(0, 0, 2), "Éteins le chauffage")
+	index := openIndex(t, dir)
+
+	tests := []struct {
+		name     string
+		query    Query
+		expected []string
+	}{
+		{"all, most recent first", Query{Text: "chauffage"}, []string{last, first}},
+		{"since", Query{Text: "chauffage", Since: tuesday.AddDate(0, 0, 1)}, []string{last}},
+		{"until", Query{Text: "chauffage", Until: tuesday.AddDate(0, 0, 1)}, []string{first}},
+		{"session", Query{Text: "chauffage", Session: first}, []string{first}},
+		{"latest session", Query{Text: "chauffage", Session: archive.Latest}, []string{last}},
+		{"limit", Query{Text: "chauffage", Limit: 1}, []string{last}},
+		{"punctuation", Query{Text: `"éteins" l'ampoule`}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := index.Search(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(matches) != len(tt.expected) {
+				t.Fatalf("Expected %d matches, got %+v", len(tt.expected), matches)
+			}
+			for n, m := range matches {
+				if m.Session != tt.expected[n] {
+					t.Errorf("Match %d: expected session %s, got %s", n, tt.expected[n], m.Session)
+				}
+			}
+		})
+	}
+}
+
+func TestSync_ReindexesChangedAndForgetsRemoved(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 6, 11, 20, 0, 0, 0, time.Local)
+	id := archiveSession(t, dir, start, "Allume le chauffage")
+	index := openIndex(t, dir)
+
+	if n, err := index.Sync(dir); err != nil || n != 0 {
+		t.Errorf("Expected nothing to index again, got %d, %v", n, err)
+	}
+
+	os.Remove(archive.File(dir, id))
+	archiveSession(t, dir, start, "Éteins la lumière")
+	if n, err := index.Sync(dir); err != nil || n != 1 {
+		t.Fatalf("Expected the changed session to be indexed again, got %d, %v", n, err)
+	}
+	if matches, _ := index.Search(Query{Text: "chauffage"}); len(matches) != 0 {
+		t.Errorf("Expected the old utterances to be gone, got %+v", matches)
+	}
+	if matches, _ := index.Search(Query{Text: "lumiere"}); len(matches) != 1 {
+		t.Errorf("Expected the new utterance, got %+v", matches)
+	}
+
+	os.Remove(archive.File(dir, id))
+	if _, err := index.Sync(dir); err != nil {
+		t.Fatal(err)
+	}
+	if matches, _ := index.Search(Query{Text: "lumiere"}); len(matches) != 0 {
+		t.Errorf("Expected the removed session to be forgotten, got %+v", matches)
+	}
+}
+
+func TestParseDay(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2024, 6, 12, 15, 4, 5, 0, time.UTC)
+	tests := map[string]time.Time{
+		"today":      time.Date(2024, 6, 12, 0, 0, 0, 0, time.UTC),
+		"Yesterday":  time.Date(2024, 6, 11, 0, 0, 0, 0, time.UTC),
+		"tuesday":    time.Date(2024, 6, 11, 0, 0, 0, 0, time.UTC),
+		"mercredi":   time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC),
+		"2024-05-01": time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for value, expected := range tests {
+		day, err := ParseDay(value, now)
+		if err != nil || !day.Equal(expected) {
+			t.Errorf("%s: expected %s, got %s (%v)", value, expected, day, err)
+		}
+	}
+	if _, err := ParseDay("last week", now); err == nil {
+		t.Error("Expected an error for an unknown day")
+	}
+}
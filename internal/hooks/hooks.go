@@ -0,0 +1,146 @@
+// Package hooks runs user commands on pipeline events, with the event as
+// JSON on their standard input, to integrate with anything scriptable
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+// Defaults of Config
+const (
+	DefaultTimeout     = 10 * time.Second
+	DefaultConcurrency = 4
+)
+
+// maxOutput bounds the output of a failed hook kept in the logs
+const maxOutput = 512
+
+// Config holds the hook commands, run with sh -c, empty for none
+type Config struct {
+	OnWake       string
+	OnTranscript string
+	OnAIResponse string
+	OnError      string
+	// Timeout kills a hook running longer, 0 for DefaultTimeout
+	Timeout time.Duration
+	// Concurrency bounds the hooks running at once, the events arriving
+	// beyond are dropped. 0 for DefaultConcurrency.
+	Concurrency int
+}
+
+// Commands returns the hook command of every event type having one
+func (c Config) Commands() map[output.EventType]string {
+	commands := make(map[output.EventType]string)
+	for eventType, command := range map[output.EventType]string{
+		output.EventWakeWord:   c.OnWake,
+		output.EventTranscript: c.OnTranscript,
+		output.EventAIResponse: c.OnAIResponse,
+		output.EventError:      c.OnError,
+	} {
+		if command = strings.TrimSpace(command); command != "" {
+			commands[eventType] = command
+		}
+	}
+	return commands
+}
+
+// Runner runs the hooks of the events it is given. Hooks run in the
+// background so that slow ones never stall the pipeline.
+type Runner struct {
+	commands map[output.EventType]string
+	timeout  time.Duration
+	slots    chan struct{}
+	running  sync.WaitGroup
+}
+
+// NewRunner creates a runner of the hooks of config
+func NewRunner(config Config) *Runner {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultConcurrency
+	}
+	return &Runner{
+		commands: config.Commands(),
+		timeout:  config.Timeout,
+		slots:    make(chan struct{}, config.Concurrency),
+	}
+}
+
+// Emit starts the hook of the event, if any
+func (r *Runner) Emit(event output.Event) error {
+	command, ok := r.commands[event.Type]
+	if !ok {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case r.slots <- struct{}{}:
+	default:
+		return fmt.Errorf("%d hooks already running, dropped %s hook", cap(r.slots), event.Type)
+	}
+	r.running.Add(1)
+	go func() {
+		defer r.running.Done()
+		defer func() { <-r.slots }()
+
+		if err := r.run(command, event, payload); err != nil {
+			logger.WithError(err).WithField("event", event.Type).Error("❌ Hook failed")
+		}
+	}()
+	return nil
+}
+
+// Close waits for the running hooks
+func (r *Runner) Close() {
+	r.running.Wait()
+}
+
+// run runs a hook command with the event on its standard input, and its
+// type and text in NRZ_EVENT and NRZ_TEXT
+func (r *Runner) run(command string, event output.Event, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(append(payload, '\n'))
+	cmd.Env = append(os.Environ(), "NRZ_EVENT="+string(event.Type), "NRZ_TEXT="+event.Text)
+	// Do not wait for the children keeping the output open once killed
+	cmd.WaitDelay = time.Second
+
+	started := time.Now()
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", r.timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, truncate(strings.TrimSpace(string(out))))
+	}
+	logger.Debugf("🪝 %s hook done in %s", event.Type, time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
+// truncate keeps the end of a long output, where errors usually are
+func truncate(text string) string {
+	if len(text) <= maxOutput {
+		return text
+	}
+	return "…" + text[len(text)-maxOutput:]
+}
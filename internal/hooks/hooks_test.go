@@ -0,0 +1,79 @@
+package hooks
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+func TestRunner_PassesTheEvent(t *testing.T) {
+	dir := t.TempDir()
+	runner := NewRunner(Config{
+		OnTranscript: `cat > "` + filepath.Join(dir, "event.json") + `"; echo "$NRZ_EVENT $NRZ_TEXT" > "` + filepath.Join(dir, "env") + `"`,
+	})
+
+	if err := runner.Emit(output.Event{Type: output.EventTranscript, Text: "Bonjour"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runner.Emit(output.Event{Type: output.EventAIResponse, Text: "Salut"}); err != nil {
+		t.Fatalf("Expected no error without a hook, got: %v", err)
+	}
+	runner.Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, "event.json"))
+	if err != nil {
+		t.Fatalf("Expected the hook to run, got: %v", err)
+	}
+	var event output.Event
+	if err := json.Unmarshal(data, &event); err != nil || event.Text != "Bonjour" {
+		t.Errorf("Expected the event as JSON on stdin, got %q (%v)", data, err)
+	}
+	if env, _ := os.ReadFile(filepath.Join(dir, "env")); strings.TrimSpace(string(env)) != "transcript Bonjour" {
+		t.Errorf("Expected the event type and text in the environment, got %q", env)
+	}
+}
+
+func TestRunner_TimesOut(t *testing.T) {
+	runner := NewRunner(Config{OnError: "sleep 10", Timeout: 50 * time.Millisecond})
+
+	started := time.Now()
+	runner.Emit(output.Event{Type: output.EventError, Error: "boom"})
+	runner.Close()
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("Expected the hook killed after its timeout, took %s", elapsed)
+	}
+}
+
+func TestRunner_LimitsConcurrency(t *testing.T) {
+	release := filepath.Join(t.TempDir(), "release")
+	runner := NewRunner(Config{
+		OnWake:      `while [ ! -e "` + release + `" ]; do sleep 0.01; done`,
+		Concurrency: 1,
+	})
+
+	if err := runner.Emit(output.Event{Type: output.EventWakeWord}); err != nil {
+		t.Fatalf("Expected the first hook to start, got: %v", err)
+	}
+	if err := runner.Emit(output.Event{Type: output.EventWakeWord}); err == nil {
+		t.Error("Expected the second hook dropped while the first runs")
+	}
+	os.WriteFile(release, nil, 0600)
+	runner.Close()
+
+	if err := runner.Emit(output.Event{Type: output.EventWakeWord}); err != nil {
+		t.Errorf("Expected a free slot once the hook is done, got: %v", err)
+	}
+	runner.Close()
+}
+
+func TestConfig_Commands(t *testing.T) {
+	commands := Config{OnWake: " ", OnAIResponse: "say-it"}.Commands()
+	if len(commands) != 1 || commands[output.EventAIResponse] != "say-it" {
+		t.Errorf("Expected only the AI response hook, got %v", commands)
+	}
+}
@@ -0,0 +1,115 @@
+// Package i18n translates the spoken replies and the messages of the live
+// session to the transcription language
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Fallback is the language of the messages missing from a catalog
+const Fallback = "en"
+
+// Messages maps message keys to fmt format strings. Formats may reorder
+// their arguments with explicit indexes, e.g. "%[2]s".
+type Messages map[string]string
+
+var (
+	catalogs = map[string]Messages{"en": english, "fr": french}
+	mutex    sync.RWMutex
+)
+
+// Register adds messages to the catalog of language, replacing the
+// messages with the same keys, e.g. to add a locale or adjust a reply
+func Register(language string, messages Messages) {
+	language = base(language)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	catalog, ok := catalogs[language]
+	if !ok {
+		catalog = Messages{}
+		catalogs[language] = catalog
+	}
+	for key, message := range messages {
+		catalog[key] = message
+	}
+}
+
+// LoadDir registers every <language>.yaml catalog of dir, e.g. es.yaml
+// with "key: message" lines. A missing directory is not an error.
+func LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var messages Messages
+		if err := yaml.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		Register(strings.TrimSuffix(filepath.Base(path), ".yaml"), messages)
+	}
+	return nil
+}
+
+// T returns the message key in language formatted with args, falling back
+// to English, then to the key itself
+func T(language, key string, args ...interface{}) string {
+	mutex.RLock()
+	message, ok := catalogs[base(language)][key]
+	if !ok {
+		message, ok = catalogs[Fallback][key]
+	}
+	mutex.RUnlock()
+
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Clock formats a time of day, e.g. "14 h 05" or "2:05 PM"
+func Clock(language string, t time.Time) string {
+	hour := t.Hour() % 12
+	if hour == 0 {
+		hour = 12
+	}
+	period := "AM"
+	if t.Hour() >= 12 {
+		period = "PM"
+	}
+	return T(language, "clock", t.Hour(), t.Minute(), hour, period)
+}
+
+// Supported reports whether language has a catalog
+func Supported(language string) bool {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	_, ok := catalogs[base(language)]
+	return ok
+}
+
+// base strips the region of a language tag, "fr-CA" becoming "fr"
+func base(language string) string {
+	language = strings.ToLower(language)
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	return language
+}
@@ -0,0 +1,81 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestT_FallsBack(t *testing.T) {
+	if got := T("fr", "intents.done"); got != "C'est fait." {
+		t.Errorf("Expected the French message, got %q", got)
+	}
+	if got := T("fr-CA", "intents.done"); got != "C'est fait." {
+		t.Errorf("Expected the region to be ignored, got %q", got)
+	}
+	if got := T("de", "intents.done"); got != "Done." {
+		t.Errorf("Expected English for a language without catalog, got %q", got)
+	}
+	if got := T("fr", "no.such.key"); got != "no.such.key" {
+		t.Errorf("Expected the key of a missing message, got %q", got)
+	}
+}
+
+func TestT_ReordersArguments(t *testing.T) {
+	fr := T("fr", "timers.reminder_set", "d'appeler Marc", "appeler Marc", "18 h 00")
+	if fr != "Je te rappellerai d'appeler Marc à 18 h 00." {
+		t.Errorf("Unexpected French reply %q", fr)
+	}
+	en := T("en", "timers.reminder_set", "d'appeler Marc", "call Marc", "6:00 PM")
+	if en != "I'll remind you to call Marc at 6:00 PM." {
+		t.Errorf("Unexpected English reply %q", en)
+	}
+}
+
+func TestCatalogs_Complete(t *testing.T) {
+	for key := range french {
+		if _, ok := english[key]; !ok {
+			t.Errorf("Expected French key %q in the English catalog", key)
+		}
+	}
+}
+
+func TestClock(t *testing.T) {
+	at := time.Date(2026, 3, 1, 0, 5, 0, 0, time.Local)
+	if got := Clock("fr", at); got != "0 h 05" {
+		t.Errorf("Expected '0 h 05', got %q", got)
+	}
+	if got := Clock("en", at); got != "12:05 AM" {
+		t.Errorf("Expected '12:05 AM', got %q", got)
+	}
+	if got := Clock("en", at.Add(14*time.Hour)); got != "2:05 PM" {
+		t.Errorf("Expected '2:05 PM', got %q", got)
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "xx.yaml"), []byte("intents.done: \"Fatto.\"\ntimers.started: \"Timer di %s.\"\n"), 0644)
+
+	if err := LoadDir(dir); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !Supported("xx") || T("xx", "intents.done") != "Fatto." {
+		t.Errorf("Expected the loaded catalog, got %q", T("xx", "intents.done"))
+	}
+	if got := T("xx", "timers.started", "5 minutes"); got != "Timer di 5 minutes." {
+		t.Errorf("Expected a formatted message, got %q", got)
+	}
+	if got := T("xx", "intents.cancelled"); got != "Cancelled." {
+		t.Errorf("Expected English for keys the catalog lacks, got %q", got)
+	}
+
+	if err := LoadDir(filepath.Join(dir, "missing")); err != nil {
+		t.Errorf("Expected no error for a missing directory, got: %v", err)
+	}
+	os.WriteFile(filepath.Join(dir, "yy.yaml"), []byte("- not a map"), 0644)
+	if err := LoadDir(dir); err == nil {
+		t.Error("Expected error for an invalid catalog")
+	}
+}
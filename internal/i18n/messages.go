@@ -0,0 +1,155 @@
+package i18n
+
+// english is the fallback catalog, every key must be defined here
+var english = Messages{
+	// Voice commands
+	"command.history_cleared":      "History cleared.",
+	"command.forgot_last":          "OK, forgotten.",
+	"command.nothing_to_forget":    "There is nothing to forget.",
+	"command.unknown_language":     "I don't know the language %s.",
+	"command.language_set":         "Transcription language set to %s.",
+	"command.language_switched":    "I'll answer in %s from now on.",
+	"command.stop_wake_word":       "OK, say '%s' when you need me.",
+	"command.paused":               "Pausing, resume with 'nrz-ai ctl resume'.",
+	"command.nothing_said":         "I haven't said anything yet.",
+	"command.nothing_to_shorten":   "There is no answer to shorten.",
+	"command.shorten_prompt":       "Rephrase your last answer much more briefly.",
+	"command.nothing_to_summarize": "We haven't talked about anything yet.",
+	"command.summarize_prompt":     "Summarize our conversation so far in a few sentences: the questions asked, the answers and what remains to do.",
+
+	// Intents
+	"intents.cancelled": "Cancelled.",
+	"intents.confirm":   "Please confirm: %s?",
+	"intents.done":      "Done.",
+	"intents.time":      "It is %s.",
+	"intents.date":      "Today is %[1]s, %[3]s %[2]d, %[4]d.",
+
+	// Timers and reminders
+	"timers.bad_duration":  "I didn't get the duration.",
+	"timers.started":       "Timer set for %s.",
+	"timers.reminder_what": "What should I remind you of?",
+	"timers.bad_when":      "I didn't get when.",
+	"timers.bad_time":      "I didn't get the time.",
+	"timers.reminder_set":  "I'll remind you to %[2]s at %[3]s.",
+	"timers.none":          "No pending timers.",
+	"timers.item":          "%s timer (%s)",
+	"timers.cancelled":     "%d timer(s) cancelled.",
+	"timers.reminder":      "Reminder: %s",
+	"timers.done":          "Your %s timer is done.",
+
+	// Durations, times of day and dates; clock gets the hour, minutes,
+	// 12-hour clock hour and AM/PM
+	"duration.hour":    "hour",
+	"duration.hours":   "hours",
+	"duration.minute":  "minute",
+	"duration.minutes": "minutes",
+	"duration.second":  "second",
+	"duration.seconds": "seconds",
+	"duration.and":     "and",
+	"clock":            "%[3]d:%02[2]d %[4]s",
+	"weekday.0":        "Sunday",
+	"weekday.1":        "Monday",
+	"weekday.2":        "Tuesday",
+	"weekday.3":        "Wednesday",
+	"weekday.4":        "Thursday",
+	"weekday.5":        "Friday",
+	"weekday.6":        "Saturday",
+	"month.1":          "January",
+	"month.2":          "February",
+	"month.3":          "March",
+	"month.4":          "April",
+	"month.5":          "May",
+	"month.6":          "June",
+	"month.7":          "July",
+	"month.8":          "August",
+	"month.9":          "September",
+	"month.10":         "October",
+	"month.11":         "November",
+	"month.12":         "December",
+
+	// Language names, by Whisper code
+	"language.fr": "French",
+	"language.en": "English",
+	"language.es": "Spanish",
+	"language.de": "German",
+	"language.it": "Italian",
+	"language.pt": "Portuguese",
+	"language.nl": "Dutch",
+
+	// Live session
+	"session.tip_ai":        "💡 Tip: Speak naturally, AI will respond to your voice!",
+	"session.tip_wake_word": "🎯 Say '%s' to activate listening, then speak normally",
+}
+
+var french = Messages{
+	"command.history_cleared":      "Historique effacé.",
+	"command.forgot_last":          "D'accord, c'est oublié.",
+	"command.nothing_to_forget":    "Il n'y a rien à oublier.",
+	"command.unknown_language":     "Je ne connais pas la langue %s.",
+	"command.language_set":         "Je parle maintenant %s.",
+	"command.language_switched":    "Je te réponds maintenant en %s.",
+	"command.stop_wake_word":       "D'accord, dis '%s' quand tu as besoin de moi.",
+	"command.paused":               "Je me mets en pause, reprends avec 'nrz-ai ctl resume'.",
+	"command.nothing_said":         "Je n'ai encore rien dit.",
+	"command.nothing_to_shorten":   "Il n'y a pas de réponse à raccourcir.",
+	"command.shorten_prompt":       "Reformule ta dernière réponse de façon beaucoup plus courte.",
+	"command.nothing_to_summarize": "Nous n'avons encore parlé de rien.",
+	"command.summarize_prompt":     "Résume notre conversation jusqu'ici en quelques phrases : les questions posées, les réponses et ce qu'il reste à faire.",
+
+	"intents.cancelled": "Annulé.",
+	"intents.confirm":   "Tu confirmes : %s ?",
+	"intents.done":      "C'est fait.",
+	"intents.time":      "Il est %s.",
+	"intents.date":      "Nous sommes le %s %d %s %d.",
+
+	"timers.bad_duration":  "Je n'ai pas compris la durée.",
+	"timers.started":       "Minuteur de %s lancé.",
+	"timers.reminder_what": "De quoi dois-je te rappeler ?",
+	"timers.bad_when":      "Je n'ai pas compris quand.",
+	"timers.bad_time":      "Je n'ai pas compris l'heure.",
+	"timers.reminder_set":  "Je te rappellerai %[1]s à %[3]s.",
+	"timers.none":          "Aucun minuteur en cours.",
+	"timers.item":          "minuteur de %s (%s)",
+	"timers.cancelled":     "%d minuteur(s) annulé(s).",
+	"timers.reminder":      "Rappel : %s",
+	"timers.done":          "Le minuteur de %s est terminé.",
+
+	"duration.hour":    "heure",
+	"duration.hours":   "heures",
+	"duration.minute":  "minute",
+	"duration.minutes": "minutes",
+	"duration.second":  "seconde",
+	"duration.seconds": "secondes",
+	"duration.and":     "et",
+	"clock":            "%[1]d h %02[2]d",
+	"weekday.0":        "dimanche",
+	"weekday.1":        "lundi",
+	"weekday.2":        "mardi",
+	"weekday.3":        "mercredi",
+	"weekday.4":        "jeudi",
+	"weekday.5":        "vendredi",
+	"weekday.6":        "samedi",
+	"month.1":          "janvier",
+	"month.2":          "février",
+	"month.3":          "mars",
+	"month.4":          "avril",
+	"month.5":          "mai",
+	"month.6":          "juin",
+	"month.7":          "juillet",
+	"month.8":          "août",
+	"month.9":          "septembre",
+	"month.10":         "octobre",
+	"month.11":         "novembre",
+	"month.12":         "décembre",
+
+	"language.fr": "français",
+	"language.en": "anglais",
+	"language.es": "espagnol",
+	"language.de": "allemand",
+	"language.it": "italien",
+	"language.pt": "portugais",
+	"language.nl": "néerlandais",
+
+	"session.tip_ai":        "💡 Astuce : parle naturellement, l'IA te répondra !",
+	"session.tip_wake_word": "🎯 Dis '%s' pour activer l'écoute, puis parle normalement",
+}
@@ -0,0 +1,65 @@
+package instance
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAcquire_RefusesSecondInstance(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nrz-ai.lock")
+
+	lock, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if _, err := Acquire(path); !errors.Is(err, ErrRunning) {
+		t.Errorf("Expected ErrRunning while locked, got: %v", err)
+	}
+	if pid, err := Owner(path); err != nil || pid != os.Getpid() {
+		t.Errorf("Expected our PID in the lock file, got %d (%v)", pid, err)
+	}
+
+	lock.Release()
+	lock, err = Acquire(path)
+	if err != nil {
+		t.Fatalf("Expected the lock to be free after Release, got: %v", err)
+	}
+	lock.Release()
+}
+
+func TestAcquire_IgnoresStaleFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nrz-ai.lock")
+	// Left behind by an instance that crashed
+	os.WriteFile(path, []byte("999999\n"), 0600)
+
+	lock, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Expected a stale lock file to be taken over, got: %v", err)
+	}
+	defer lock.Release()
+
+	if pid, _ := Owner(path); pid != os.Getpid() {
+		t.Errorf("Expected our PID to replace the stale one, got %d", pid)
+	}
+}
+
+func TestTakeover_FreeLock(t *testing.T) {
+	lock, err := Takeover(filepath.Join(t.TempDir(), "nrz-ai.lock"), time.Second)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	lock.Release()
+}
+
+func TestOwner_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nrz-ai.lock")
+	os.WriteFile(path, []byte("garbage"), 0600)
+
+	if _, err := Owner(path); err == nil {
+		t.Error("Expected error for a lock file without PID")
+	}
+}
@@ -0,0 +1,110 @@
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pollInterval is how often Takeover checks whether the lock was released
+const pollInterval = 100 * time.Millisecond
+
+// ErrRunning is returned while another instance holds the lock
+var ErrRunning = errors.New("another nrz-ai instance is running")
+
+// errLocked is returned by lockFile when another process holds the lock
+var errLocked = errors.New("lock held by another process")
+
+// DefaultLockPath returns $XDG_RUNTIME_DIR/nrz-ai.lock, or a per-user path
+// in the temporary directory when XDG_RUNTIME_DIR is unset
+func DefaultLockPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "nrz-ai.lock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("nrz-ai-%d.lock", os.Getuid()))
+}
+
+// Lock is the single-instance lock. The kernel releases it when the
+// process exits, so a crashed instance never leaves a stale lock behind.
+type Lock struct {
+	file *os.File
+}
+
+// Acquire takes the lock at path and records the PID of this process in it
+func Acquire(path string) (*Lock, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	if err := lockFile(file); err != nil {
+		file.Close()
+		if !errors.Is(err, errLocked) {
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if pid, err := Owner(path); err == nil {
+			return nil, fmt.Errorf("%w (PID %d)", ErrRunning, pid)
+		}
+		return nil, ErrRunning
+	}
+
+	if err := file.Truncate(0); err == nil {
+		_, err = file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write lock file: %w", err)
+	}
+	return &Lock{file: file}, nil
+}
+
+// Owner returns the PID recorded in the lock file at path
+func Owner(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("no PID in lock file %s", path)
+	}
+	return pid, nil
+}
+
+// Takeover takes the lock at path, asking the instance holding it to exit
+// (SIGTERM, or a kill on Windows) and waiting at most timeout for it to do so
+func Takeover(path string, timeout time.Duration) (*Lock, error) {
+	lock, err := Acquire(path)
+	if !errors.Is(err, ErrRunning) {
+		return lock, err
+	}
+
+	pid, err := Owner(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := terminate(pid); err != nil {
+		return nil, fmt.Errorf("failed to stop instance %d: %w", pid, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		time.Sleep(pollInterval)
+		lock, err := Acquire(path)
+		if !errors.Is(err, ErrRunning) {
+			return lock, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("instance %d still running after %s: %w", pid, timeout, err)
+		}
+	}
+}
+
+// Release gives the lock up
+func (l *Lock) Release() error {
+	return l.file.Close()
+}
@@ -0,0 +1,46 @@
+package intents
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/i18n"
+)
+
+// now is replaced in tests
+var now = time.Now
+
+// DefaultIntents are the built-in intents, tried after the intents file
+func DefaultIntents() []Intent {
+	return []Intent{
+		{
+			Name:     "time",
+			Patterns: []string{"quelle heure est-il", "il est quelle heure", "quelle heure il est", "what time is it"},
+			Handler:  "time",
+		},
+		{
+			Name:     "date",
+			Patterns: []string{"quel jour sommes-nous", "quel jour on est", "on est quel jour", "quelle est la date", "what day is it", "what is the date"},
+			Handler:  "date",
+		},
+	}
+}
+
+// registerBuiltins registers the handlers shipped with nrz-ai
+func registerBuiltins(r *Router) {
+	r.handlers["time"] = HandlerFunc(handleTime)
+	r.handlers["date"] = HandlerFunc(handleDate)
+}
+
+// handleTime tells the current time
+func handleTime(match Match) (string, error) {
+	return i18n.T(match.Language, "intents.time", i18n.Clock(match.Language, now())), nil
+}
+
+// handleDate tells the current date
+func handleDate(match Match) (string, error) {
+	t := now()
+	weekday := i18n.T(match.Language, fmt.Sprintf("weekday.%d", t.Weekday()))
+	month := i18n.T(match.Language, fmt.Sprintf("month.%d", t.Month()))
+	return i18n.T(match.Language, "intents.date", weekday, t.Day(), month, t.Year()), nil
+}
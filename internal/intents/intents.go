@@ -0,0 +1,193 @@
+package intents
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Intent describes phrases answered locally instead of by the AI. An intent
+// matches when one of its patterns matches, or when all its keywords are
+// present. It is answered by its handler, plugin command or shell command,
+// or else by its response template.
+type Intent struct {
+	Name string `yaml:"name"`
+
+	// Patterns are case-insensitive regular expressions where {slot}
+	// captures words, e.g. "minuteur de {duration}"
+	Patterns []string `yaml:"patterns"`
+
+	// Keywords must all appear in the transcript
+	Keywords []string `yaml:"keywords"`
+
+	// Handler names a registered handler, e.g. "time"
+	Handler string `yaml:"handler"`
+
+	// Command runs an external skill speaking the plugin protocol
+	Command []string `yaml:"command"`
+
+	// Shell runs an allowlisted command, {slot} is replaced in each argument
+	Shell []string `yaml:"shell"`
+
+	// SpeakOutput replies with the output of the shell command
+	SpeakOutput bool `yaml:"speak_output"`
+
+	// Timeout bounds the plugin or shell command, 10s by default
+	Timeout time.Duration `yaml:"timeout"`
+
+	// Confirm asks for a yes or no before answering
+	Confirm bool `yaml:"confirm"`
+
+	// Response is a reply template where {slot} is replaced by its value
+	Response string `yaml:"response"`
+}
+
+// Match is a transcript matched to an intent
+type Match struct {
+	Intent   string
+	Text     string
+	Slots    map[string]string
+	Language string
+}
+
+// intentsFile is the layout of an intents YAML file
+type intentsFile struct {
+	Intents []Intent `yaml:"intents"`
+}
+
+// LoadFile reads intents from a YAML file with a top-level "intents" list.
+// Plugin commands starting with ./ or ../ are relative to the file.
+func LoadFile(path string) ([]Intent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file intentsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	for i, intent := range file.Intents {
+		if len(intent.Command) > 0 && (strings.HasPrefix(intent.Command[0], "./") || strings.HasPrefix(intent.Command[0], "../")) {
+			file.Intents[i].Command[0] = filepath.Join(filepath.Dir(path), intent.Command[0])
+		}
+	}
+	return file.Intents, nil
+}
+
+// slotPattern matches {slot} placeholders
+var slotPattern = regexp.MustCompile(`\{([a-z_][a-z0-9_]*)\}`)
+
+// compiledIntent is an intent with its patterns compiled
+type compiledIntent struct {
+	Intent
+	patterns []*regexp.Regexp
+	keywords []string
+	plugin   *PluginHandler
+	shell    *ShellHandler
+}
+
+// compile validates an intent and compiles its patterns
+func compile(intent Intent) (*compiledIntent, error) {
+	if intent.Name == "" {
+		return nil, fmt.Errorf("intent without name")
+	}
+	if len(intent.Patterns) == 0 && len(intent.Keywords) == 0 {
+		return nil, fmt.Errorf("intent '%s' has no patterns nor keywords", intent.Name)
+	}
+	answers := 0
+	for _, set := range []bool{intent.Handler != "", len(intent.Command) > 0, len(intent.Shell) > 0} {
+		if set {
+			answers++
+		}
+	}
+	if answers == 0 && intent.Response == "" {
+		return nil, fmt.Errorf("intent '%s' has no handler, command, shell nor response", intent.Name)
+	}
+	if answers > 1 {
+		return nil, fmt.Errorf("intent '%s' must use only one of handler, command and shell", intent.Name)
+	}
+
+	compiled := &compiledIntent{Intent: intent}
+	if len(intent.Command) > 0 {
+		compiled.plugin = NewPluginHandler(intent.Command, intent.Timeout)
+	}
+	if len(intent.Shell) > 0 {
+		compiled.shell = NewShellHandler(intent.Shell, intent.SpeakOutput, intent.Timeout)
+	}
+	for _, pattern := range intent.Patterns {
+		re, err := compilePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("intent '%s': invalid pattern '%s': %w", intent.Name, pattern, err)
+		}
+		compiled.patterns = append(compiled.patterns, re)
+	}
+	for _, keyword := range intent.Keywords {
+		compiled.keywords = append(compiled.keywords, strings.ToLower(normalize(keyword)))
+	}
+	return compiled, nil
+}
+
+// compilePattern turns {slot} placeholders into named groups. A trailing
+// slot takes the rest of the sentence, others stop at the next literal.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	pattern = strings.TrimSpace(pattern)
+	expr := slotPattern.ReplaceAllString(pattern, `(?P<$1>.+?)`)
+	if slotPattern.MatchString(pattern) && strings.HasSuffix(pattern, "}") {
+		expr += `$`
+	}
+	// Normalized text is space separated, \b would not see accented letters
+	return regexp.Compile(`(?i)(?:^|\s)` + expr + `(?:\s|$)`)
+}
+
+// match returns the slots captured by the intent, false if it does not match
+func (c *compiledIntent) match(text string) (map[string]string, bool) {
+	for _, re := range c.patterns {
+		groups := re.FindStringSubmatch(text)
+		if groups == nil {
+			continue
+		}
+		slots := make(map[string]string)
+		for i, name := range re.SubexpNames() {
+			if name != "" {
+				slots[name] = strings.TrimSpace(groups[i])
+			}
+		}
+		return slots, true
+	}
+
+	if len(c.keywords) == 0 {
+		return nil, false
+	}
+	words := " " + strings.ToLower(text) + " "
+	for _, keyword := range c.keywords {
+		if !strings.Contains(words, " "+keyword+" ") {
+			return nil, false
+		}
+	}
+	return map[string]string{}, true
+}
+
+// normalize turns punctuation into single spaces, so Whisper output like
+// "Quelle heure est-il ?" matches "quelle heure est-il". Case is kept for
+// slot values, patterns are case-insensitive.
+func normalize(text string) string {
+	text = strings.NewReplacer("’", "'", "?", " ", "!", " ", ".", " ", ",", " ", ";", " ", ":", " ", "…", " ").Replace(text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// expand replaces {slot} placeholders of a response template
+func expand(template string, slots map[string]string) string {
+	return slotPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		if value, ok := slots[placeholder[1:len(placeholder)-1]]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
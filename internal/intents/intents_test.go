@@ -0,0 +1,299 @@
+package intents
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouter_DefaultIntents(t *testing.T) {
+	now = func() time.Time { return time.Date(2026, 10, 14, 9, 5, 0, 0, time.Local) }
+	defer func() { now = time.Now }()
+
+	router, err := NewRouter(DefaultIntents(), "fr")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := router.Validate(); err != nil {
+		t.Fatalf("Expected built-in handlers, got: %v", err)
+	}
+
+	tests := map[string]string{
+		"Quelle heure est-il ?":       "Il est 9 h 05.",
+		"Jack, quel jour sommes-nous": "Nous sommes le mercredi 14 octobre 2026.",
+	}
+	for text, expected := range tests {
+		reply, handled, err := router.Handle(text)
+		if err != nil || !handled {
+			t.Errorf("Expected %q to be handled, got handled=%v err=%v", text, handled, err)
+		}
+		if reply != expected {
+			t.Errorf("Expected %q for %q, got %q", expected, text, reply)
+		}
+	}
+
+	router.SetLanguage("en")
+	if reply, _, _ := router.Handle("What time is it?"); reply != "It is 9:05 AM." {
+		t.Errorf("Expected English time, got %q", reply)
+	}
+
+	if _, handled, _ := router.Handle("Raconte-moi une blague"); handled {
+		t.Error("Expected unmatched text to fall back to the AI")
+	}
+}
+
+func TestRouter_SlotsAndResponses(t *testing.T) {
+	router, err := NewRouter([]Intent{
+		{Name: "music", Patterns: []string{"(?:mets|joue) {artist}"}, Response: "Je lance {artist}."},
+		{Name: "lights", Keywords: []string{"allume", "lumière"}, Response: "J'allume la lumière."},
+		{Name: "weather", Patterns: []string{"météo à {city} demain"}, Handler: "weather"},
+	}, "fr")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var slots map[string]string
+	router.Register("weather", HandlerFunc(func(match Match) (string, error) {
+		slots = match.Slots
+		return "Il fera beau.", nil
+	}))
+
+	if reply, _, _ := router.Handle("Joue Daft Punk."); reply != "Je lance Daft Punk." {
+		t.Errorf("Expected trailing slot to capture the end of the sentence, got %q", reply)
+	}
+	if reply, _, _ := router.Handle("Tu peux allumer la lumière ? Allume la lumière du salon"); reply != "J'allume la lumière." {
+		t.Errorf("Expected keyword intent, got %q", reply)
+	}
+	if _, handled, _ := router.Handle("La lumière est belle"); handled {
+		t.Error("Expected all keywords to be required")
+	}
+	if reply, _, _ := router.Handle("Quelle est la météo à Lyon demain ?"); reply != "Il fera beau." || slots["city"] != "Lyon" {
+		t.Errorf("Expected handler with city slot, got %q and %v", reply, slots)
+	}
+}
+
+func TestRouter_HandlerErrors(t *testing.T) {
+	router, _ := NewRouter([]Intent{
+		{Name: "broken", Patterns: []string{"casse"}, Handler: "broken"},
+		{Name: "missing", Patterns: []string{"manque"}, Handler: "missing"},
+	}, "fr")
+	router.Register("broken", HandlerFunc(func(Match) (string, error) {
+		return "", errors.New("boom")
+	}))
+
+	if err := router.Validate(); err == nil {
+		t.Error("Expected unknown handler to fail validation")
+	}
+	if _, handled, err := router.Handle("casse"); !handled || err == nil {
+		t.Errorf("Expected handled error, got handled=%v err=%v", handled, err)
+	}
+	if _, handled, err := router.Handle("il manque un truc"); !handled || err == nil {
+		t.Errorf("Expected unknown handler error, got handled=%v err=%v", handled, err)
+	}
+}
+
+func TestNewRouter_InvalidIntents(t *testing.T) {
+	invalid := []Intent{
+		{Patterns: []string{"bonjour"}, Response: "Salut"},
+		{Name: "empty", Response: "Salut"},
+		{Name: "mute", Patterns: []string{"bonjour"}},
+		{Name: "regex", Patterns: []string{"(bonjour"}, Response: "Salut"},
+	}
+	for _, intent := range invalid {
+		if _, err := NewRouter([]Intent{intent}, "fr"); err == nil {
+			t.Errorf("Expected error for invalid intent %+v", intent)
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "intents.yaml")
+	os.WriteFile(path, []byte(`intents:
+  - name: greeting
+    patterns: ["bonjour {name}"]
+    response: "Bonjour {name} !"
+`), 0644)
+
+	intents, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(intents) != 1 || intents[0].Name != "greeting" || intents[0].Response != "Bonjour {name} !" {
+		t.Errorf("Unexpected intents %+v", intents)
+	}
+
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+// writePlugin writes an executable shell script to dir
+func writePlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatalf("Failed to write plugin: %v", err)
+	}
+	return path
+}
+
+func TestPluginHandler(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "weather.sh", `read request
+case "$request" in
+  *'"city":"Lyon"'*) echo '{"text": "Il fait beau à Lyon."}' ;;
+  *) echo '{"error": "ville inconnue"}' ;;
+esac
+`)
+	path := filepath.Join(dir, "intents.yaml")
+	os.WriteFile(path, []byte(`intents:
+  - name: weather
+    patterns: ["météo à {city}"]
+    command: ["./weather.sh"]
+    timeout: 2s
+`), 0644)
+
+	definitions, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if definitions[0].Command[0] != filepath.Join(dir, "weather.sh") || definitions[0].Timeout != 2*time.Second {
+		t.Errorf("Expected relative command and timeout to be resolved, got %+v", definitions[0])
+	}
+
+	router, err := NewRouter(definitions, "fr")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if reply, _, err := router.Handle("La météo à Lyon ?"); err != nil || reply != "Il fait beau à Lyon." {
+		t.Errorf("Expected plugin reply, got %q (%v)", reply, err)
+	}
+	if _, handled, err := router.Handle("météo à Brest"); !handled || err == nil || !strings.Contains(err.Error(), "ville inconnue") {
+		t.Errorf("Expected plugin error to be reported, got handled=%v err=%v", handled, err)
+	}
+}
+
+func TestPluginHandler_Failures(t *testing.T) {
+	dir := t.TempDir()
+
+	slow := NewPluginHandler([]string{writePlugin(t, dir, "slow.sh", "sleep 5\n")}, 50*time.Millisecond)
+	if _, err := slow.Handle(Match{Intent: "slow"}); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected timeout error, got: %v", err)
+	}
+
+	garbage := NewPluginHandler([]string{writePlugin(t, dir, "garbage.sh", "echo not json\n")}, 0)
+	if _, err := garbage.Handle(Match{Intent: "garbage"}); err == nil {
+		t.Error("Expected invalid JSON error")
+	}
+
+	crash := NewPluginHandler([]string{writePlugin(t, dir, "crash.sh", "echo oops >&2; exit 3\n")}, 0)
+	if _, err := crash.Handle(Match{Intent: "crash"}); err == nil || !strings.Contains(err.Error(), "oops") {
+		t.Errorf("Expected stderr in error, got: %v", err)
+	}
+
+	if _, err := NewRouter([]Intent{{Name: "both", Patterns: []string{"x"}, Handler: "time", Command: []string{"true"}}}, "fr"); err == nil {
+		t.Error("Expected error for intent with both handler and command")
+	}
+}
+
+func TestShellIntents(t *testing.T) {
+	dir := t.TempDir()
+	echo := writePlugin(t, dir, "echo.sh", `echo "volume:$1"`+"\n")
+	lock := writePlugin(t, dir, "lock.sh", "touch "+filepath.Join(dir, "locked")+"\n")
+
+	router, err := NewRouter([]Intent{
+		{Name: "volume", Patterns: []string{"volume à {level}"}, Shell: []string{echo, "{level}"}, SpeakOutput: true},
+		{Name: "lock", Patterns: []string{"verrouille l'écran"}, Shell: []string{lock}, Confirm: true},
+	}, "fr")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if err := router.Validate(); err == nil {
+		t.Fatal("Expected shell intents to be refused without allowlist")
+	}
+	router.SetShellAllowlist([]string{echo, lock})
+	if err := router.Validate(); err != nil {
+		t.Fatalf("Expected allowlisted commands, got: %v", err)
+	}
+
+	if reply, _, err := router.Handle("Mets le volume à 30 ; rm -rf"); err != nil || reply != "volume:30 rm -rf" {
+		t.Errorf("Expected slot passed as a single argument, got %q (%v)", reply, err)
+	}
+	if _, _, err := router.Handle("volume à --help"); err == nil {
+		t.Error("Expected option-like slot values to be refused")
+	}
+
+	// Confirmation: no, then yes
+	if reply, _, _ := router.Handle("Verrouille l'écran."); reply != "Tu confirmes : Verrouille l'écran. ?" {
+		t.Errorf("Expected confirmation question, got %q", reply)
+	}
+	if reply, _, _ := router.Handle("Non."); reply != "Annulé." {
+		t.Errorf("Expected cancellation, got %q", reply)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "locked")); err == nil {
+		t.Fatal("Expected command not to run before confirmation")
+	}
+
+	router.Handle("Verrouille l'écran")
+	if reply, _, err := router.Handle("Oui !"); err != nil || reply != "C'est fait." {
+		t.Errorf("Expected confirmed command, got %q (%v)", reply, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "locked")); err != nil {
+		t.Error("Expected command to run after confirmation")
+	}
+}
+
+func TestRouter_HandleRemote(t *testing.T) {
+	router, _ := NewRouter([]Intent{
+		{Name: "reboot", Patterns: []string{"redémarre"}, Response: "Redémarrage.", Confirm: true},
+		{Name: "backup", Patterns: []string{"sauvegarde"}, Shell: []string{"true"}},
+		{Name: "lights", Patterns: []string{"allume la lumière"}, Response: "Lumière allumée"},
+	}, "fr")
+	router.SetShellAllowlist([]string{"true"})
+
+	if reply, handled, _ := router.HandleRemote("Allume la lumière"); !handled || reply != "Lumière allumée" {
+		t.Errorf("Expected the response intent to answer, got %q", reply)
+	}
+	for _, text := range []string{"redémarre", "sauvegarde"} {
+		if reply, handled, _ := router.HandleRemote(text); handled {
+			t.Errorf("Expected %q to be left out, got %q", text, reply)
+		}
+	}
+
+	// The question asked at home waits for the speaker
+	router.Handle("redémarre")
+	if reply, handled, _ := router.HandleRemote("oui"); handled {
+		t.Errorf("Expected a remote yes to be ignored, got %q", reply)
+	}
+	if reply, _, _ := router.Handle("oui"); reply != "Redémarrage." {
+		t.Errorf("Expected the spoken yes to confirm, got %q", reply)
+	}
+}
+
+func TestConfirmation_Expires(t *testing.T) {
+	router, _ := NewRouter([]Intent{
+		{Name: "reboot", Patterns: []string{"redémarre"}, Response: "Redémarrage.", Confirm: true},
+	}, "en")
+
+	clock := time.Now()
+	router.now = func() time.Time { return clock }
+
+	router.Handle("redémarre")
+	clock = clock.Add(time.Minute)
+	if _, handled, _ := router.Handle("yes"); handled {
+		t.Error("Expected expired confirmation to be ignored")
+	}
+
+	router.Handle("redémarre")
+	if reply, _, _ := router.Handle("Yes."); reply != "Redémarrage." {
+		t.Errorf("Expected confirmed response, got %q", reply)
+	}
+	if _, handled, _ := router.Handle("yes"); handled {
+		t.Error("Expected confirmation to be consumed")
+	}
+}
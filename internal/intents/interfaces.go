@@ -0,0 +1,15 @@
+package intents
+
+// Handler answers a matched intent
+type Handler interface {
+	// Handle returns the reply to the matched intent
+	Handle(match Match) (string, error)
+}
+
+// HandlerFunc adapts a function to Handler
+type HandlerFunc func(match Match) (string, error)
+
+// Handle calls f
+func (f HandlerFunc) Handle(match Match) (string, error) {
+	return f(match)
+}
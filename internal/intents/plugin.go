@@ -0,0 +1,90 @@
+package intents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// PluginProtocolVersion is sent in every plugin request
+const PluginProtocolVersion = 1
+
+// defaultPluginTimeout bounds plugins that do not set a timeout
+const defaultPluginTimeout = 10 * time.Second
+
+// PluginRequest is written as a single JSON document on the plugin stdin
+type PluginRequest struct {
+	Version  int               `json:"version"`
+	Intent   string            `json:"intent"`
+	Text     string            `json:"text"`
+	Slots    map[string]string `json:"slots"`
+	Language string            `json:"language"`
+}
+
+// PluginResponse is read as a single JSON document from the plugin stdout
+type PluginResponse struct {
+	Text  string `json:"text"`            // Reply to speak, may be empty
+	Error string `json:"error,omitempty"` // Set when the skill failed
+}
+
+// PluginHandler handles intents by running an external executable. Each
+// match starts the command, writes a PluginRequest to its stdin and reads a
+// PluginResponse from its stdout; stderr is kept for error messages.
+type PluginHandler struct {
+	command []string
+	timeout time.Duration
+}
+
+// NewPluginHandler creates a handler running command, killed after timeout
+func NewPluginHandler(command []string, timeout time.Duration) *PluginHandler {
+	if timeout <= 0 {
+		timeout = defaultPluginTimeout
+	}
+	return &PluginHandler{command: command, timeout: timeout}
+}
+
+// Handle runs the plugin for a match
+func (p *PluginHandler) Handle(match Match) (string, error) {
+	request, err := json.Marshal(PluginRequest{
+		Version:  PluginProtocolVersion,
+		Intent:   match.Intent,
+		Text:     match.Text,
+		Slots:    match.Slots,
+		Language: match.Language,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.command[0], p.command[1:]...)
+	cmd.Stdin = bytes.NewReader(append(request, '\n'))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Do not wait for children of a killed plugin still holding stdout
+	cmd.WaitDelay = 500 * time.Millisecond
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("plugin %s timed out after %s", p.command[0], p.timeout)
+		}
+		return "", fmt.Errorf("plugin %s failed: %w: %s", p.command[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	var response PluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return "", fmt.Errorf("plugin %s returned invalid JSON: %w", p.command[0], err)
+	}
+	if response.Error != "" {
+		return "", fmt.Errorf("plugin %s: %s", p.command[0], response.Error)
+	}
+	return response.Text, nil
+}
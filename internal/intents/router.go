@@ -0,0 +1,215 @@
+package intents
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/i18n"
+)
+
+// confirmationTimeout is how long a confirmation question waits for its answer
+const confirmationTimeout = 30 * time.Second
+
+// Confirmation answers, after normalization
+var (
+	yesWords = []string{"oui", "ouais", "confirme", "je confirme", "vas-y", "d'accord", "ok", "yes", "yeah", "confirm", "go ahead", "sure"}
+	noWords  = []string{"non", "annule", "laisse tomber", "no", "nope", "cancel"}
+)
+
+// pendingConfirmation is an intent waiting for a yes or no
+type pendingConfirmation struct {
+	intent  *compiledIntent
+	match   Match
+	expires time.Time
+}
+
+// Router matches transcripts against intents, in order, and dispatches the
+// first match to its handler
+type Router struct {
+	intents   []*compiledIntent
+	handlers  map[string]Handler
+	language  string
+	allowlist []string
+	pending   *pendingConfirmation
+	now       func() time.Time
+	mutex     sync.RWMutex
+}
+
+// NewRouter compiles intents for transcripts in language. The built-in
+// handlers are registered.
+func NewRouter(intents []Intent, language string) (*Router, error) {
+	r := &Router{
+		handlers: make(map[string]Handler),
+		language: language,
+		now:      time.Now,
+	}
+	for _, intent := range intents {
+		compiled, err := compile(intent)
+		if err != nil {
+			return nil, err
+		}
+		r.intents = append(r.intents, compiled)
+	}
+
+	registerBuiltins(r)
+	return r, nil
+}
+
+// Register makes a handler available to intents under name
+func (r *Router) Register(name string, handler Handler) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.handlers[name] = handler
+}
+
+// SetLanguage changes the language passed to handlers
+func (r *Router) SetLanguage(language string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.language = language
+}
+
+// SetShellAllowlist sets the commands shell intents may run, as written in
+// the intents (name looked up in PATH or path). Shell intents are refused by default.
+func (r *Router) SetShellAllowlist(commands []string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.allowlist = commands
+}
+
+// Validate checks that every intent handler is registered and every shell
+// command is allowlisted
+func (r *Router) Validate() error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, intent := range r.intents {
+		if len(intent.Shell) > 0 && !allowed(intent.Shell[0], r.allowlist) {
+			return fmt.Errorf("intent '%s' runs '%s' which is not in shell_allowlist", intent.Name, intent.Shell[0])
+		}
+		if intent.Handler == "" {
+			continue
+		}
+		if _, ok := r.handlers[intent.Handler]; !ok {
+			return fmt.Errorf("intent '%s' uses unknown handler '%s'", intent.Name, intent.Handler)
+		}
+	}
+	return nil
+}
+
+// Intents returns the number of intents
+func (r *Router) Intents() int {
+	return len(r.intents)
+}
+
+// Handle answers text with the first matching intent. handled is false when
+// no intent matches and the transcript should go to the AI. Intents marked
+// confirm ask first and run on the next "oui"/"yes".
+func (r *Router) Handle(text string) (reply string, handled bool, err error) {
+	return r.handle(text, false)
+}
+
+// HandleRemote answers text typed in a remote client like Handle, leaving
+// out the shell intents, the intents to confirm and the confirmation
+// pending for the spoken ones: a remote message can neither run a command
+// nor answer a question asked at home.
+func (r *Router) HandleRemote(text string) (reply string, handled bool, err error) {
+	return r.handle(text, true)
+}
+
+// handle answers text, as typed in a remote client when remote is set
+func (r *Router) handle(text string, remote bool) (string, bool, error) {
+	normalized := normalize(text)
+
+	r.mutex.Lock()
+	language := r.language
+	var pending *pendingConfirmation
+	if !remote {
+		pending = r.pending
+		r.pending = nil
+	}
+	r.mutex.Unlock()
+
+	if pending != nil && r.now().Before(pending.expires) {
+		switch {
+		case isAnswer(normalized, yesWords):
+			return r.run(pending.intent, pending.match)
+		case isAnswer(normalized, noWords):
+			return i18n.T(language, "intents.cancelled"), true, nil
+		}
+		// Anything else drops the question and is handled normally
+	}
+
+	for _, intent := range r.intents {
+		if remote && (intent.shell != nil || intent.Confirm) {
+			continue
+		}
+		slots, ok := intent.match(normalized)
+		if !ok {
+			continue
+		}
+
+		match := Match{Intent: intent.Name, Text: text, Slots: slots, Language: language}
+		if intent.Confirm {
+			r.mutex.Lock()
+			r.pending = &pendingConfirmation{intent: intent, match: match, expires: r.now().Add(confirmationTimeout)}
+			r.mutex.Unlock()
+			return i18n.T(language, "intents.confirm", strings.TrimSpace(text)), true, nil
+		}
+		return r.run(intent, match)
+	}
+
+	return "", false, nil
+}
+
+// run answers a matched intent
+func (r *Router) run(intent *compiledIntent, match Match) (string, bool, error) {
+	var handler Handler
+	switch {
+	case intent.plugin != nil:
+		handler = intent.plugin
+	case intent.shell != nil:
+		r.mutex.RLock()
+		ok := allowed(intent.Shell[0], r.allowlist)
+		r.mutex.RUnlock()
+		if !ok {
+			return "", true, fmt.Errorf("intent '%s' runs '%s' which is not in shell_allowlist", intent.Name, intent.Shell[0])
+		}
+		handler = intent.shell
+	case intent.Handler != "":
+		r.mutex.RLock()
+		registered, ok := r.handlers[intent.Handler]
+		r.mutex.RUnlock()
+		if !ok {
+			return "", true, fmt.Errorf("intent '%s' uses unknown handler '%s'", intent.Name, intent.Handler)
+		}
+		handler = registered
+	default:
+		return expand(intent.Response, match.Slots), true, nil
+	}
+
+	reply, err := handler.Handle(match)
+	if err != nil {
+		return "", true, fmt.Errorf("intent '%s' failed: %w", intent.Name, err)
+	}
+	if reply == "" {
+		reply = expand(intent.Response, match.Slots)
+	}
+	if reply == "" && intent.shell != nil {
+		reply = i18n.T(match.Language, "intents.done")
+	}
+	return reply, true, nil
+}
+
+// isAnswer reports whether text is one of the answers
+func isAnswer(text string, answers []string) bool {
+	text = strings.ToLower(text)
+	for _, answer := range answers {
+		if text == answer {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package intents
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// maxSpokenOutput keeps long command output out of replies
+const maxSpokenOutput = 300
+
+// ShellHandler runs an allowlisted command. Arguments are passed as is,
+// without a shell, and {slot} placeholders are replaced in each argument,
+// so captured words can never add arguments nor run other commands.
+type ShellHandler struct {
+	command     []string
+	speakOutput bool
+	timeout     time.Duration
+}
+
+// NewShellHandler creates a handler running command. With speakOutput the
+// command output is the reply.
+func NewShellHandler(command []string, speakOutput bool, timeout time.Duration) *ShellHandler {
+	if timeout <= 0 {
+		timeout = defaultPluginTimeout
+	}
+	return &ShellHandler{command: command, speakOutput: speakOutput, timeout: timeout}
+}
+
+// Handle runs the command with the slots of the match
+func (s *ShellHandler) Handle(match Match) (string, error) {
+	args := make([]string, 0, len(s.command)-1)
+	for _, arg := range s.command[1:] {
+		for name, value := range match.Slots {
+			if strings.Contains(arg, "{"+name+"}") && strings.HasPrefix(value, "-") {
+				// Would be read as an option by most tools
+				return "", fmt.Errorf("slot %s value '%s' must not start with '-'", name, value)
+			}
+		}
+		args = append(args, expand(arg, match.Slots))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.command[0], args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	cmd.WaitDelay = 500 * time.Millisecond
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("%s timed out after %s", s.command[0], s.timeout)
+		}
+		return "", fmt.Errorf("%s failed: %w: %s", s.command[0], err, strings.TrimSpace(out.String()))
+	}
+
+	if !s.speakOutput {
+		return "", nil
+	}
+	output := strings.Join(strings.Fields(out.String()), " ")
+	if len([]rune(output)) > maxSpokenOutput {
+		output = string([]rune(output)[:maxSpokenOutput-1]) + "…"
+	}
+	return output, nil
+}
+
+// allowed reports whether command is in the allowlist. Entries are
+// compared as written: "loginctl" does not allow "/usr/bin/loginctl".
+func allowed(command string, allowlist []string) bool {
+	for _, entry := range allowlist {
+		if entry == command {
+			return true
+		}
+	}
+	return false
+}
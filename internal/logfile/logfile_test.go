@@ -0,0 +1,129 @@
+package logfile
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+func TestRotatingFile_RotatesBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "nrz-ai.log")
+
+	f, err := NewRotatingFile(path, RotateConfig{MaxSize: 10})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer f.Close()
+
+	clock := time.Date(2026, 10, 14, 15, 4, 5, 0, time.UTC)
+	f.now = func() time.Time { return clock }
+
+	f.Write([]byte("12345678\n"))
+	f.Write([]byte("abcdefgh\n"))
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "abcdefgh\n" {
+		t.Errorf("Expected current file to hold the last write, got %q", string(data))
+	}
+	backup, err := os.ReadFile(path + ".20261014-150405")
+	if err != nil || string(backup) != "12345678\n" {
+		t.Errorf("Expected timestamped backup with the first write, got %q (%v)", string(backup), err)
+	}
+}
+
+func TestRotatingFile_RotatesByAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nrz-ai.log")
+
+	f, err := NewRotatingFile(path, RotateConfig{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer f.Close()
+
+	clock := time.Now()
+	f.now = func() time.Time { return clock }
+	f.opened = clock
+
+	f.Write([]byte("before\n"))
+	clock = clock.Add(30 * time.Minute)
+	f.Write([]byte("still\n"))
+	clock = clock.Add(time.Hour)
+	f.Write([]byte("after\n"))
+
+	backups, _ := filepath.Glob(path + ".*")
+	if len(backups) != 1 {
+		t.Fatalf("Expected 1 backup, got %v", backups)
+	}
+	data, _ := os.ReadFile(backups[0])
+	if string(data) != "before\nstill\n" {
+		t.Errorf("Unexpected backup content %q", string(data))
+	}
+}
+
+func TestRotatingFile_PrunesBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nrz-ai.log")
+
+	f, err := NewRotatingFile(path, RotateConfig{MaxSize: 1, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer f.Close()
+
+	clock := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	f.now = func() time.Time { return clock }
+	for i := 0; i < 5; i++ {
+		f.Write([]byte("x"))
+		clock = clock.Add(time.Second)
+	}
+
+	backups, _ := filepath.Glob(path + ".*")
+	if len(backups) != 2 {
+		t.Fatalf("Expected 2 backups kept, got %v", backups)
+	}
+	if !strings.HasSuffix(backups[1], ".20261014-000004") {
+		t.Errorf("Expected the newest backups to be kept, got %v", backups)
+	}
+}
+
+func TestRotatingFile_AppendsToExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nrz-ai.log")
+	os.WriteFile(path, []byte("previous run\n"), 0644)
+
+	f, err := NewRotatingFile(path, RotateConfig{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	f.Write([]byte("this run\n"))
+	f.Close()
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "previous run\nthis run\n" {
+		t.Errorf("Expected appended content, got %q", string(data))
+	}
+	if _, err := f.Write([]byte("late")); err == nil {
+		t.Error("Expected error writing to a closed file")
+	}
+}
+
+func TestTranscriptLog_Emit(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewTranscriptLog(&buf)
+
+	at := time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC)
+	log.Emit(output.Event{Type: output.EventTranscript, Time: at, Text: " Bonjour "})
+	log.Emit(output.Event{Type: output.EventTranscript, Time: at, Text: "Salut", Speaker: "Speaker 2"})
+	log.Emit(output.Event{Type: output.EventVAD, Time: at, State: "speech"})
+	log.Emit(output.Event{Type: output.EventAIResponse, Time: at, Text: "Bonjour !"})
+
+	expected := "2026-10-14 09:30:00 [you] Bonjour\n" +
+		"2026-10-14 09:30:00 [Speaker 2] Salut\n" +
+		"2026-10-14 09:30:00 [ai] Bonjour !\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
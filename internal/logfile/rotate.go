@@ -0,0 +1,175 @@
+package logfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/privacy"
+)
+
+// RotateConfig configures when a log file is rotated and how many old files are kept
+type RotateConfig struct {
+	MaxSize    int64         // Rotate once the file reaches this many bytes (0 = no size limit)
+	MaxAge     time.Duration // Rotate once the file is this old (0 = no time limit)
+	MaxBackups int           // Rotated files kept, oldest are deleted (0 = keep all)
+}
+
+// RotatingFile is an append-only file rotated by size and age. Rotated files
+// are renamed with their rotation time, e.g. nrz-ai.log.20261014-150405.
+type RotatingFile struct {
+	path   string
+	config RotateConfig
+	file   *os.File
+	size   int64
+	opened time.Time
+	now    func() time.Time
+	mutex  sync.Mutex
+}
+
+// NewRotatingFile opens path for appending, creating its directory
+func NewRotatingFile(path string, config RotateConfig) (*RotatingFile, error) {
+	if err := privacy.Check("log files"); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	f := &RotatingFile{
+		path:   path,
+		config: config,
+		now:    time.Now,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Path returns the path of the current file
+func (f *RotatingFile) Path() string {
+	return f.path
+}
+
+// Write appends p, rotating first when the file is too big or too old
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file
+func (f *RotatingFile) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// open opens the current file, picking up the size of an existing one
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.opened = f.now()
+	if f.size > 0 {
+		// Age an existing file from its last write, not from this run
+		f.opened = info.ModTime()
+	}
+	return nil
+}
+
+// shouldRotate reports whether writing n more bytes needs a new file
+func (f *RotatingFile) shouldRotate(n int64) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.config.MaxSize > 0 && f.size+n > f.config.MaxSize {
+		return true
+	}
+	return f.config.MaxAge > 0 && f.now().Sub(f.opened) >= f.config.MaxAge
+}
+
+// rotate renames the current file aside, reopens a fresh one and prunes old backups
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	backup := f.backupName(f.now())
+	if err := os.Rename(f.path, backup); err != nil {
+		return fmt.Errorf("failed to rotate %s: %w", f.path, err)
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+	return f.prune()
+}
+
+// backupName returns an unused name for a file rotated at t
+func (f *RotatingFile) backupName(t time.Time) string {
+	base := f.path + "." + t.Format("20060102-150405")
+	name := base
+	for i := 1; ; i++ {
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			return name
+		}
+		name = fmt.Sprintf("%s.%d", base, i)
+	}
+}
+
+// prune deletes the oldest backups beyond MaxBackups
+func (f *RotatingFile) prune() error {
+	if f.config.MaxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := filepath.Glob(f.path + ".*")
+	if err != nil {
+		return err
+	}
+	// Timestamped names sort chronologically
+	sort.Strings(backups)
+
+	for len(backups) > f.config.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package logfile
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+// TranscriptLog appends final transcripts and AI responses to a
+// human-readable log, one line per event
+type TranscriptLog struct {
+	w     io.Writer
+	mutex sync.Mutex
+}
+
+// NewTranscriptLog creates a transcript log writing to w
+func NewTranscriptLog(w io.Writer) *TranscriptLog {
+	return &TranscriptLog{w: w}
+}
+
+// Emit writes transcript, translation and AI response events, other events
+// are ignored
+func (l *TranscriptLog) Emit(event output.Event) error {
+	text := strings.TrimSpace(event.Text)
+	if text == "" {
+		return nil
+	}
+
+	var who string
+	switch event.Type {
+	case output.EventTranscript:
+		who = event.Speaker
+		if who == "" {
+			who = "you"
+		}
+	case output.EventTranslation:
+		who = event.Language
+	case output.EventAIResponse:
+		who = "ai"
+	default:
+		return nil
+	}
+
+	timestamp := event.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	_, err := fmt.Fprintf(l.w, "%s [%s] %s\n", timestamp.Format("2006-01-02 15:04:05"), who, text)
+	return err
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"io"
 	"os"
 	"strings"
 
@@ -33,6 +34,59 @@ func InitLogger(level string) {
 	Logger.SetOutput(os.Stdout)
 }
 
+// SetOutput redirects log output, e.g. to stderr when stdout carries data
+func SetOutput(w io.Writer) {
+	if Logger != nil {
+		Logger.SetOutput(w)
+	}
+}
+
+// SetJournalFormat switches to plain lines without colors nor timestamps,
+// journald already records the time of each line
+func SetJournalFormat() {
+	if Logger != nil {
+		Logger.SetFormatter(&logrus.TextFormatter{
+			DisableColors:    true,
+			DisableTimestamp: true,
+		})
+	}
+}
+
+// AddFileOutput also writes logs to w, without colors and with full dates
+func AddFileOutput(w io.Writer) {
+	if Logger != nil {
+		Logger.AddHook(&fileHook{
+			writer: w,
+			formatter: &logrus.TextFormatter{
+				FullTimestamp:   true,
+				TimestampFormat: "2006-01-02 15:04:05",
+				DisableColors:   true,
+			},
+		})
+	}
+}
+
+// fileHook copies every log entry to a writer with its own formatter
+type fileHook struct {
+	writer    io.Writer
+	formatter logrus.Formatter
+}
+
+// Levels returns all levels, the logger level already filters entries
+func (h *fileHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire writes the formatted entry
+func (h *fileHook) Fire(entry *logrus.Entry) error {
+	line, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	_, err = h.writer.Write(line)
+	return err
+}
+
 // Info logs an info message
 func Info(args ...interface{}) {
 	if Logger != nil {
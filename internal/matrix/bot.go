@@ -0,0 +1,403 @@
+// Package matrix bridges the assistant to a Matrix room: transcripts and AI
+// answers are posted to it and the messages of its allowed members are
+// handled as if they had been spoken at home
+package matrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+const (
+	queueSize      = 64
+	requestTimeout = 10 * time.Second
+	// syncTimeout is how long the homeserver holds a sync without news
+	syncTimeout = 30 * time.Second
+	retryDelay  = 5 * time.Second
+	// maxSendRetries bounds the retries of a rate limited message
+	maxSendRetries = 3
+)
+
+// message is the content of a room message
+type message struct {
+	MsgType string `json:"msgtype"`
+	Body    string `json:"body"`
+}
+
+// Bot posts transcripts and AI answers to a Matrix room and hands the
+// messages of the allowed members to a CommandHandler. Messages are sent in
+// the background so that a slow homeserver never stalls the pipeline.
+type Bot struct {
+	config     Config
+	httpClient *http.Client
+	handler    CommandHandler
+	users      map[string]bool
+	session    string // Prefix of the transaction IDs, unique per run
+	txn        atomic.Int64
+
+	// Set by Start
+	userID string // Account of the bot, its own messages are ignored
+	roomID string
+	since  string // Sync token of the last handled batch
+
+	queue     chan message
+	ctx       context.Context
+	cancel    context.CancelFunc
+	started   bool
+	sent      chan struct{} // Closed when the sender stops
+	synced    chan struct{} // Closed when the sync loop stops
+	closeOnce sync.Once
+}
+
+// NewBot creates a bot, call Start to join the room
+func NewBot(config Config) *Bot {
+	config.Homeserver = strings.TrimSuffix(strings.TrimSpace(config.Homeserver), "/")
+
+	users := make(map[string]bool, len(config.Users))
+	for _, user := range config.Users {
+		if user = strings.TrimSpace(user); user != "" {
+			users[user] = true
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Bot{
+		config:     config,
+		httpClient: &http.Client{},
+		users:      users,
+		session:    strconv.FormatInt(time.Now().UnixNano(), 36),
+		queue:      make(chan message, queueSize),
+		ctx:        ctx,
+		cancel:     cancel,
+		sent:       make(chan struct{}),
+		synced:     make(chan struct{}),
+	}
+}
+
+// SetCommandHandler enables the messages of the room, without one the bot
+// only posts
+func (b *Bot) SetCommandHandler(handler CommandHandler) {
+	b.handler = handler
+}
+
+// Start joins the room and starts posting to and listening to it. The
+// messages posted before are ignored.
+func (b *Bot) Start() error {
+	if b.config.Homeserver == "" || b.config.AccessToken == "" || b.config.Room == "" {
+		return errors.New("the Matrix homeserver, access token and room are required")
+	}
+
+	ctx, cancel := context.WithTimeout(b.ctx, requestTimeout)
+	defer cancel()
+
+	var whoami struct {
+		UserID string `json:"user_id"`
+	}
+	if err := b.call(ctx, http.MethodGet, "/account/whoami", nil, nil, &whoami); err != nil {
+		return fmt.Errorf("failed to authenticate to %s: %w", b.config.Homeserver, err)
+	}
+
+	// Joining resolves aliases and does nothing for a room already joined
+	var joined struct {
+		RoomID string `json:"room_id"`
+	}
+	if err := b.call(ctx, http.MethodPost, "/join/"+url.PathEscape(b.config.Room), nil, struct{}{}, &joined); err != nil {
+		return fmt.Errorf("failed to join %s: %w", b.config.Room, err)
+	}
+	b.userID, b.roomID = whoami.UserID, joined.RoomID
+
+	// The first sync only skips the history of the room
+	batch, err := b.sync(b.ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to sync with %s: %w", b.config.Homeserver, err)
+	}
+	b.since = batch.NextBatch
+
+	b.started = true
+	go b.sender()
+	if b.handler != nil {
+		go b.listen()
+	} else {
+		close(b.synced)
+	}
+	return nil
+}
+
+// UserID returns the account of the bot, once started
+func (b *Bot) UserID() string {
+	return b.userID
+}
+
+// Emit queues the AI answers, and the transcripts when enabled, for the room
+func (b *Bot) Emit(event output.Event) error {
+	text := strings.TrimSpace(event.Text)
+	if text == "" {
+		return nil
+	}
+
+	var body string
+	switch {
+	case event.Type == output.EventAIResponse:
+		body = "🤖 " + text
+	case event.Type == output.EventTranscript && b.config.Transcripts:
+		if event.Speaker != "" {
+			text = event.Speaker + ": " + text
+		}
+		body = "🎙️ " + text
+	default:
+		return nil
+	}
+
+	// Notices are the messages of bots, clients never answer them
+	select {
+	case b.queue <- message{MsgType: "m.notice", Body: body}:
+		return nil
+	default:
+		return fmt.Errorf("matrix queue full, dropped %s event", event.Type)
+	}
+}
+
+// Close sends the queued messages and stops listening to the room
+func (b *Bot) Close() {
+	b.closeOnce.Do(func() {
+		if b.started {
+			close(b.queue)
+			<-b.sent
+		}
+		b.cancel()
+		if b.started {
+			<-b.synced
+		}
+	})
+}
+
+// sender posts the queued messages to the room
+func (b *Bot) sender() {
+	defer close(b.sent)
+
+	for msg := range b.queue {
+		if err := b.send(msg); err != nil {
+			logger.WithError(err).Error("❌ Matrix message not sent")
+		}
+	}
+}
+
+// send posts a message, waiting as told by the homeserver when rate limited
+func (b *Bot) send(msg message) error {
+	path := "/rooms/" + url.PathEscape(b.roomID) + "/send/m.room.message/" +
+		b.session + "-" + strconv.FormatInt(b.txn.Add(1), 10)
+
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		err := b.call(ctx, http.MethodPut, path, nil, msg, nil)
+		cancel()
+
+		var apiErr *apiError
+		if !errors.As(err, &apiErr) || apiErr.Status != http.StatusTooManyRequests || attempt == maxSendRetries {
+			return err
+		}
+		time.Sleep(apiErr.RetryAfter())
+	}
+}
+
+// listen hands the messages of the room to the handler until Close
+func (b *Bot) listen() {
+	defer close(b.synced)
+
+	for b.ctx.Err() == nil {
+		batch, err := b.sync(b.ctx, syncTimeout)
+		if err != nil {
+			if b.ctx.Err() != nil {
+				return
+			}
+			logger.WithError(err).Warnf("⚠️  Matrix sync failed, retrying in %s", retryDelay)
+			select {
+			case <-b.ctx.Done():
+				return
+			case <-time.After(retryDelay):
+			}
+			continue
+		}
+
+		b.since = batch.NextBatch
+		for _, event := range batch.Rooms.Join[b.roomID].Timeline.Events {
+			if text, ok := b.command(event); ok {
+				logger.WithField("sender", event.Sender).Debugf("💬 Matrix message: %s", text)
+				go b.handler.Say(text)
+			}
+		}
+	}
+}
+
+// syncResponse is the part of a sync reply read by the bot
+type syncResponse struct {
+	NextBatch string `json:"next_batch"`
+	Rooms     struct {
+		Join map[string]struct {
+			Timeline struct {
+				Events []roomEvent `json:"events"`
+			} `json:"timeline"`
+		} `json:"join"`
+	} `json:"rooms"`
+}
+
+// roomEvent is a timeline event
+type roomEvent struct {
+	Type    string `json:"type"`
+	Sender  string `json:"sender"`
+	Content struct {
+		MsgType    string          `json:"msgtype"`
+		Body       string          `json:"body"`
+		NewContent json.RawMessage `json:"m.new_content"` // Set on edits
+	} `json:"content"`
+}
+
+// sync waits up to timeout for the events following the last batch
+func (b *Bot) sync(ctx context.Context, timeout time.Duration) (syncResponse, error) {
+	query := url.Values{
+		"timeout": {strconv.FormatInt(timeout.Milliseconds(), 10)},
+		"filter":  {b.filter()},
+	}
+	if b.since != "" {
+		query.Set("since", b.since)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout+requestTimeout)
+	defer cancel()
+
+	var response syncResponse
+	err := b.call(ctx, http.MethodGet, "/sync", query, nil, &response)
+	return response, err
+}
+
+// filter limits the syncs to the messages of the room
+func (b *Bot) filter() string {
+	none := map[string][]string{"not_types": {"*"}}
+	filter, _ := json.Marshal(map[string]any{
+		"presence":     none,
+		"account_data": none,
+		"room": map[string]any{
+			"rooms":        []string{b.roomID},
+			"timeline":     map[string][]string{"types": {"m.room.message"}},
+			"state":        none,
+			"ephemeral":    none,
+			"account_data": none,
+		},
+	})
+	return string(filter)
+}
+
+// command returns the text of event to hand to the handler, ignoring the
+// messages of the bot and of the users not allowed, edits and anything but
+// text messages
+func (b *Bot) command(event roomEvent) (string, bool) {
+	if event.Type != "m.room.message" || event.Content.MsgType != "m.text" || event.Content.NewContent != nil {
+		return "", false
+	}
+	if event.Sender == b.userID {
+		return "", false
+	}
+	if !b.users[event.Sender] {
+		logger.Warnf("⚠️  Matrix message from %s ignored, add it to matrix_users to handle it", event.Sender)
+		return "", false
+	}
+
+	text := strings.TrimSpace(stripReply(event.Content.Body))
+	return text, text != ""
+}
+
+// stripReply removes the quote of the replied message clients put before
+// the reply
+func stripReply(body string) string {
+	if !strings.HasPrefix(body, "> ") {
+		return body
+	}
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, ">") {
+			return strings.Join(lines[i:], "\n")
+		}
+	}
+	return ""
+}
+
+// apiError is an error reply of the homeserver
+type apiError struct {
+	Status       int
+	Code         string `json:"errcode"`
+	Message      string `json:"error"`
+	RetryAfterMs int64  `json:"retry_after_ms"`
+}
+
+func (e *apiError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("unexpected status %d", e.Status)
+	}
+	return fmt.Sprintf("%s: %s (status %d)", e.Code, e.Message, e.Status)
+}
+
+// RetryAfter returns how long to wait before retrying, a second when the
+// homeserver does not tell
+func (e *apiError) RetryAfter() time.Duration {
+	if e.RetryAfterMs <= 0 {
+		return time.Second
+	}
+	return time.Duration(e.RetryAfterMs) * time.Millisecond
+}
+
+// call sends a request to the client-server API and decodes its JSON reply
+// into result, unless nil
+func (b *Bot) call(ctx context.Context, method, path string, query url.Values, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	endpoint := b.config.Homeserver + "/_matrix/client/v3" + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	request, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+b.config.AccessToken)
+	request.Header.Set("User-Agent", "nrz-ai")
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := b.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &apiError{Status: resp.StatusCode}
+		json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(apiErr)
+		return apiErr
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,20 @@
+package matrix
+
+// CommandHandler handles the messages posted in the room
+type CommandHandler interface {
+	// Say handles text as if it had been spoken
+	Say(text string)
+}
+
+// Config holds the Matrix settings
+type Config struct {
+	Homeserver  string // e.g. https://matrix.org
+	AccessToken string // Token of the bot account
+	Room        string // Room ID (!id:server) or alias (#alias:server), joined on start
+
+	// Users allowed to talk to the assistant, the other members are ignored
+	Users []string
+
+	// Transcripts posts every transcript, not only the AI answers
+	Transcripts bool
+}
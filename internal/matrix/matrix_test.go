@@ -0,0 +1,210 @@
+package matrix
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+// homeserver fakes the client-server API for a room
+type homeserver struct {
+	server  *httptest.Server
+	mutex   sync.Mutex
+	batches [][]string // Events of the next syncs, as JSON
+	syncs   int
+	sent    []message
+	paths   []string
+	limited int // Sends answered 429 before accepting
+}
+
+func newHomeserver(t *testing.T, batches ...[]string) *homeserver {
+	t.Helper()
+
+	h := &homeserver{batches: batches}
+	h.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"errcode":"M_UNKNOWN_TOKEN","error":"Invalid token"}`)
+			return
+		}
+
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+
+		switch {
+		case r.URL.Path == "/_matrix/client/v3/account/whoami":
+			fmt.Fprint(w, `{"user_id":"@nrz:home"}`)
+		case r.URL.Path == "/_matrix/client/v3/join/#maison:home":
+			fmt.Fprint(w, `{"room_id":"!room:home"}`)
+		case r.URL.Path == "/_matrix/client/v3/sync":
+			h.syncs++
+			if len(h.batches) == 0 {
+				h.mutex.Unlock()
+				select {
+				case <-r.Context().Done():
+				case <-time.After(20 * time.Millisecond):
+				}
+				h.mutex.Lock()
+				fmt.Fprintf(w, `{"next_batch":"s%d"}`, h.syncs)
+				return
+			}
+			events := h.batches[0]
+			h.batches = h.batches[1:]
+			fmt.Fprintf(w, `{"next_batch":"s%d","rooms":{"join":{"!room:home":{"timeline":{"events":[%s]}}}}}`,
+				h.syncs, strings.Join(events, ","))
+		case strings.HasPrefix(r.URL.Path, "/_matrix/client/v3/rooms/!room:home/send/m.room.message/"):
+			if h.limited > 0 {
+				h.limited--
+				w.WriteHeader(http.StatusTooManyRequests)
+				fmt.Fprint(w, `{"errcode":"M_LIMIT_EXCEEDED","retry_after_ms":1}`)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			var msg message
+			json.Unmarshal(body, &msg)
+			h.sent = append(h.sent, msg)
+			h.paths = append(h.paths, r.URL.Path)
+			fmt.Fprint(w, `{"event_id":"$event"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errcode":"M_UNRECOGNIZED"}`)
+		}
+	}))
+	t.Cleanup(h.server.Close)
+	return h
+}
+
+func (h *homeserver) messages() []message {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return append([]message(nil), h.sent...)
+}
+
+func textEvent(sender, body string) string {
+	return fmt.Sprintf(`{"type":"m.room.message","sender":%q,"content":{"msgtype":"m.text","body":%q}}`, sender, body)
+}
+
+func TestBot_HandlesAllowedMessages(t *testing.T) {
+	hs := newHomeserver(t,
+		[]string{textEvent("@jean:home", "Message d'hier")},
+		[]string{
+			textEvent("@jean:home", " Quelle heure est-il ? "),
+			textEvent("@nrz:home", "🤖 Il est midi"),
+			textEvent("@inconnu:home", "Ouvre la porte"),
+			`{"type":"m.room.message","sender":"@jean:home","content":{"msgtype":"m.image","body":"photo.jpg"}}`,
+			`{"type":"m.room.message","sender":"@jean:home","content":{"msgtype":"m.text","body":"* Quelle heure ?","m.new_content":{}}}`,
+			textEvent("@jean:home", "> <@nrz:home> Il est midi\n\nEt demain ?"),
+		},
+	)
+	handler := NewMockCommandHandler()
+	bot := NewBot(Config{Homeserver: hs.server.URL + "/", AccessToken: "s3cret", Room: "#maison:home", Users: []string{"@jean:home"}})
+	bot.SetCommandHandler(handler)
+
+	if err := bot.Start(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if bot.UserID() != "@nrz:home" {
+		t.Errorf("Expected the account of the token, got %q", bot.UserID())
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(handler.Said()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	bot.Close()
+
+	said := handler.Said()
+	if len(said) != 2 {
+		t.Fatalf("Expected the 2 text messages of the allowed user after the start, got %q", said)
+	}
+	for _, expected := range []string{"Quelle heure est-il ?", "Et demain ?"} {
+		if said[0] != expected && said[1] != expected {
+			t.Errorf("Expected %q to be handled, got %q", expected, said)
+		}
+	}
+}
+
+func TestBot_IgnoresEveryoneWithoutUsers(t *testing.T) {
+	bot := NewBot(Config{})
+	bot.userID = "@nrz:home"
+
+	var event roomEvent
+	json.Unmarshal([]byte(textEvent("@jean:home", "Ouvre la porte")), &event)
+	if text, ok := bot.command(event); ok {
+		t.Errorf("Expected no member to be allowed without users, got %q", text)
+	}
+}
+
+func TestBot_PostsAnswersAndTranscripts(t *testing.T) {
+	hs := newHomeserver(t)
+	hs.limited = 1
+	bot := NewBot(Config{Homeserver: hs.server.URL, AccessToken: "s3cret", Room: "#maison:home", Transcripts: true})
+	if err := bot.Start(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	bot.Emit(output.Event{Type: output.EventTranscript, Text: "Bonjour", Speaker: "jean"})
+	bot.Emit(output.Event{Type: output.EventVAD, State: "speech"})
+	bot.Emit(output.Event{Type: output.EventAIResponse, Text: "Bonjour Jean !"})
+	bot.Emit(output.Event{Type: output.EventAIResponse, Text: "  "})
+	bot.Close()
+
+	sent := hs.messages()
+	if len(sent) != 2 {
+		t.Fatalf("Expected the transcript and the answer to be posted, got %v", sent)
+	}
+	if sent[0].Body != "🎙️ jean: Bonjour" || sent[1].Body != "🤖 Bonjour Jean !" {
+		t.Errorf("Unexpected messages: %v", sent)
+	}
+	if sent[0].MsgType != "m.notice" {
+		t.Errorf("Expected notices, got %q", sent[0].MsgType)
+	}
+	if hs.paths[0] == hs.paths[1] {
+		t.Errorf("Expected a transaction ID per message, got %s twice", hs.paths[0])
+	}
+}
+
+func TestBot_SkipsTranscriptsWhenDisabled(t *testing.T) {
+	hs := newHomeserver(t)
+	bot := NewBot(Config{Homeserver: hs.server.URL, AccessToken: "s3cret", Room: "#maison:home"})
+	if err := bot.Start(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	bot.Emit(output.Event{Type: output.EventTranscript, Text: "Bonjour"})
+	bot.Emit(output.Event{Type: output.EventAIResponse, Text: "Salut"})
+	bot.Close()
+
+	if sent := hs.messages(); len(sent) != 1 || sent[0].Body != "🤖 Salut" {
+		t.Errorf("Expected only the answer to be posted, got %v", sent)
+	}
+}
+
+func TestBot_StartErrors(t *testing.T) {
+	hs := newHomeserver(t)
+
+	if err := NewBot(Config{Homeserver: hs.server.URL, Room: "#maison:home"}).Start(); err == nil {
+		t.Error("Expected an error without an access token")
+	}
+
+	bot := NewBot(Config{Homeserver: hs.server.URL, AccessToken: "wrong", Room: "#maison:home"})
+	err := bot.Start()
+	if err == nil || !strings.Contains(err.Error(), "M_UNKNOWN_TOKEN") {
+		t.Errorf("Expected the homeserver error, got: %v", err)
+	}
+	bot.Close()
+
+	bot = NewBot(Config{Homeserver: hs.server.URL, AccessToken: "s3cret", Room: "#grenier:home"})
+	if err := bot.Start(); err == nil {
+		t.Error("Expected an error for a room that cannot be joined")
+	}
+	bot.Close()
+}
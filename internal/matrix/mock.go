@@ -0,0 +1,28 @@
+package matrix
+
+import "sync"
+
+// MockCommandHandler implements CommandHandler for testing
+type MockCommandHandler struct {
+	said  []string
+	mutex sync.Mutex
+}
+
+// NewMockCommandHandler creates a new mock command handler
+func NewMockCommandHandler() *MockCommandHandler {
+	return &MockCommandHandler{}
+}
+
+// Say records the text
+func (m *MockCommandHandler) Say(text string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.said = append(m.said, text)
+}
+
+// Said returns the texts passed to Say
+func (m *MockCommandHandler) Said() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]string(nil), m.said...)
+}
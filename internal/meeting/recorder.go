@@ -0,0 +1,193 @@
+package meeting
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/privacy"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+)
+
+// Entry is a single transcribed utterance of the meeting
+type Entry struct {
+	Time    time.Time
+	Speaker string
+	Text    string
+}
+
+// Recorder collects meeting transcripts and keeps a Markdown minutes file up to date
+type Recorder struct {
+	path    string
+	started time.Time
+	ended   time.Time
+	entries []Entry
+	summary string
+	mutex   sync.Mutex
+}
+
+// NewRecorder creates a recorder writing to a timestamped file in dir
+func NewRecorder(dir string) (*Recorder, error) {
+	if err := privacy.Check("meeting notes"); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	name := fmt.Sprintf("meeting-%s.md", started.Format("2006-01-02-150405"))
+
+	r := &Recorder{
+		path:    filepath.Join(dir, name),
+		started: started,
+	}
+
+	return r, r.flush()
+}
+
+// Path returns the path of the Markdown file
+func (r *Recorder) Path() string {
+	return r.path
+}
+
+// Add records an utterance and rewrites the minutes file
+func (r *Recorder) Add(entry Entry) error {
+	text := strings.TrimSpace(entry.Text)
+	if text == "" {
+		return nil
+	}
+	entry.Text = text
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.entries = append(r.entries, entry)
+	return r.flushLocked()
+}
+
+// Transcript returns the plain-text transcript, one utterance per line
+func (r *Recorder) Transcript() string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var b strings.Builder
+	for _, entry := range r.entries {
+		if entry.Speaker != "" {
+			fmt.Fprintf(&b, "%s: %s\n", entry.Speaker, entry.Text)
+		} else {
+			fmt.Fprintf(&b, "%s\n", entry.Text)
+		}
+	}
+	return b.String()
+}
+
+// Summarize asks the AI service for a summary of the meeting and adds it to the minutes
+func (r *Recorder) Summarize(service ai.AIService, prompt string) error {
+	transcript := r.Transcript()
+	if transcript == "" {
+		return nil
+	}
+
+	response, err := service.Chat(ai.ChatRequest{
+		Messages: []ai.Message{
+			{Role: "system", Content: prompt},
+			{Role: "user", Content: transcript},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to summarize meeting: %w", err)
+	}
+	if response.Error != "" {
+		return fmt.Errorf("failed to summarize meeting: %s", response.Error)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.summary = strings.TrimSpace(response.Message.Content)
+	return r.flushLocked()
+}
+
+// Close marks the end of the meeting and writes the final minutes
+func (r *Recorder) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.ended = time.Now()
+	return r.flushLocked()
+}
+
+// flush rewrites the minutes file
+func (r *Recorder) flush() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.flushLocked()
+}
+
+// flushLocked atomically rewrites the minutes file, caller holds the mutex
+func (r *Recorder) flushLocked() error {
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(r.markdownLocked()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.path)
+}
+
+// markdownLocked renders the minutes, caller holds the mutex
+func (r *Recorder) markdownLocked() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Meeting — %s\n\n", r.started.Format("2006-01-02 15:04"))
+	fmt.Fprintf(&b, "- **Started:** %s\n", r.started.Format("2006-01-02 15:04:05"))
+	if !r.ended.IsZero() {
+		fmt.Fprintf(&b, "- **Ended:** %s\n", r.ended.Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(&b, "- **Duration:** %s\n", r.ended.Sub(r.started).Round(time.Second))
+	}
+	if speakers := r.speakersLocked(); len(speakers) > 0 {
+		fmt.Fprintf(&b, "- **Speakers:** %s\n", strings.Join(speakers, ", "))
+	}
+
+	if r.summary != "" {
+		fmt.Fprintf(&b, "\n## Summary\n\n%s\n", r.summary)
+	}
+
+	b.WriteString("\n## Transcript\n\n")
+	for _, entry := range r.entries {
+		offset := formatOffset(entry.Time.Sub(r.started))
+		if entry.Speaker != "" {
+			fmt.Fprintf(&b, "**[%s] %s:** %s\n\n", offset, entry.Speaker, entry.Text)
+		} else {
+			fmt.Fprintf(&b, "**[%s]** %s\n\n", offset, entry.Text)
+		}
+	}
+
+	return b.String()
+}
+
+// speakersLocked returns the sorted distinct speakers, caller holds the mutex
+func (r *Recorder) speakersLocked() []string {
+	seen := make(map[string]bool)
+	var speakers []string
+	for _, entry := range r.entries {
+		if entry.Speaker != "" && !seen[entry.Speaker] {
+			seen[entry.Speaker] = true
+			speakers = append(speakers, entry.Speaker)
+		}
+	}
+	sort.Strings(speakers)
+	return speakers
+}
+
+// formatOffset formats a session offset as HH:MM:SS
+func formatOffset(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	seconds := int(d.Seconds())
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, (seconds%3600)/60, seconds%60)
+}
@@ -0,0 +1,84 @@
+package meeting
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+)
+
+func TestRecorder_WritesMarkdown(t *testing.T) {
+	dir := t.TempDir()
+
+	recorder, err := NewRecorder(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	recorder.Add(Entry{Time: recorder.started.Add(5 * time.Second), Speaker: "Speaker 1", Text: " Bonjour à tous. "})
+	recorder.Add(Entry{Time: recorder.started.Add(65 * time.Second), Speaker: "Speaker 2", Text: "Commençons."})
+	recorder.Add(Entry{Time: recorder.started.Add(70 * time.Second), Text: "   "})
+
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(recorder.Path())
+	if err != nil {
+		t.Fatalf("Expected minutes file, got: %v", err)
+	}
+	content := string(data)
+
+	for _, expected := range []string{
+		"# Meeting — ",
+		"- **Ended:** ",
+		"- **Speakers:** Speaker 1, Speaker 2",
+		"**[00:00:05] Speaker 1:** Bonjour à tous.",
+		"**[00:01:05] Speaker 2:** Commençons.",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected minutes to contain %q, got:\n%s", expected, content)
+		}
+	}
+
+	if strings.Count(content, "**[") != 2 {
+		t.Errorf("Expected blank entries to be skipped, got:\n%s", content)
+	}
+}
+
+func TestRecorder_Summarize(t *testing.T) {
+	recorder, err := NewRecorder(t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	recorder.Add(Entry{Time: time.Now(), Text: "On valide le budget."})
+
+	service := ai.NewMockAIService()
+	service.SetResponses([]ai.ChatResponse{
+		{Message: ai.Message{Role: "assistant", Content: "Budget validé."}, Done: true},
+	})
+
+	if err := recorder.Summarize(service, "Résume la réunion."); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, _ := os.ReadFile(recorder.Path())
+	if !strings.Contains(string(data), "## Summary\n\nBudget validé.") {
+		t.Errorf("Expected summary section, got:\n%s", string(data))
+	}
+}
+
+func TestRecorder_SummarizeError(t *testing.T) {
+	recorder, _ := NewRecorder(t.TempDir())
+	recorder.Add(Entry{Time: time.Now(), Text: "Bonjour"})
+
+	service := ai.NewMockAIService()
+	service.SetChatError(errors.New("connection refused"))
+
+	if err := recorder.Summarize(service, "Résume."); err == nil {
+		t.Error("Expected error when AI service fails")
+	}
+}
@@ -0,0 +1,205 @@
+package mqtt
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+const operationTimeout = 10 * time.Second
+
+// Client publishes pipeline events to MQTT and dispatches command topics.
+//
+// Published topics, all JSON events:
+//
+//	<prefix>/transcript, <prefix>/wake_word, <prefix>/state,
+//	<prefix>/ai_response, <prefix>/error
+//
+// Command topics, plain text payloads:
+//
+//	<prefix>/cmd/activate, <prefix>/cmd/say, <prefix>/cmd/persona
+type Client struct {
+	client  paho.Client
+	prefix  string
+	handler CommandHandler
+	publish func(topic string, retained bool, payload []byte) error
+}
+
+// NewClient creates an MQTT client, call Connect to reach the broker
+func NewClient(cfg Config) (*Client, error) {
+	opts := paho.NewClientOptions().
+		AddBroker(cfg.Broker).
+		SetClientID(cfg.ClientID).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true)
+
+	if cfg.CAFile != "" || cfg.CertFile != "" || cfg.InsecureSkipVerify {
+		tlsConfig, err := newTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts.SetTLSConfig(tlsConfig)
+	}
+
+	c := &Client{prefix: strings.TrimSuffix(cfg.TopicPrefix, "/")}
+
+	// Birth and last will on the state topic so subscribers know when we go away
+	opts.SetWill(c.topic("state"), offlineState, 1, true)
+	opts.SetOnConnectHandler(func(client paho.Client) {
+		c.subscribeCommands(client)
+	})
+
+	c.client = paho.NewClient(opts)
+	c.publish = c.publishPaho
+	return c, nil
+}
+
+// offlineState is the retained state payload when nrz-ai disconnects
+var offlineState = `{"type":"state","state":"offline"}`
+
+// newTLSConfig builds the TLS configuration from CA and client certificate files
+func newTLSConfig(cfg Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+
+	if cfg.CAFile != "" {
+		caData, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read MQTT CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, errors.New("no certificate found in MQTT CA file")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load MQTT client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// SetCommandHandler enables the command topics
+func (c *Client) SetCommandHandler(handler CommandHandler) {
+	c.handler = handler
+}
+
+// Connect connects to the broker
+func (c *Client) Connect() error {
+	token := c.client.Connect()
+	if !token.WaitTimeout(operationTimeout) {
+		return errors.New("timed out connecting to MQTT broker")
+	}
+	return token.Error()
+}
+
+// Emit publishes an event on its topic
+func (c *Client) Emit(event output.Event) error {
+	topic, retained, ok := eventTopic(event.Type)
+	if !ok {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return c.publish(c.topic(topic), retained, payload)
+}
+
+// eventTopic maps an event type to its topic suffix, state is retained
+func eventTopic(eventType output.EventType) (string, bool, bool) {
+	switch eventType {
+	case output.EventTranscript, output.EventWakeWord, output.EventAIResponse, output.EventError:
+		return string(eventType), false, true
+	case output.EventState:
+		return "state", true, true
+	default:
+		// vad and ai_token are too chatty for a broker
+		return "", false, false
+	}
+}
+
+// topic returns a topic under the configured prefix
+func (c *Client) topic(suffix string) string {
+	if c.prefix == "" {
+		return suffix
+	}
+	return c.prefix + "/" + suffix
+}
+
+// publishPaho publishes through the paho client
+func (c *Client) publishPaho(topic string, retained bool, payload []byte) error {
+	token := c.client.Publish(topic, 1, retained, payload)
+	if !token.WaitTimeout(operationTimeout) {
+		return fmt.Errorf("timed out publishing to %s", topic)
+	}
+	return token.Error()
+}
+
+// subscribeCommands subscribes to the command topics, on every (re)connection
+func (c *Client) subscribeCommands(client paho.Client) {
+	if c.handler == nil {
+		return
+	}
+
+	token := client.Subscribe(c.topic("cmd/#"), 1, func(_ paho.Client, msg paho.Message) {
+		c.handleCommand(msg.Topic(), string(msg.Payload()))
+	})
+	go func() {
+		if token.WaitTimeout(operationTimeout) && token.Error() != nil {
+			logger.WithError(token.Error()).Error("❌ Failed to subscribe to MQTT commands")
+		}
+	}()
+}
+
+// handleCommand dispatches a command topic to the handler
+func (c *Client) handleCommand(topic, payload string) {
+	payload = strings.TrimSpace(payload)
+
+	switch strings.TrimPrefix(topic, c.topic("cmd/")) {
+	case "activate":
+		c.handler.Activate()
+	case "say":
+		if payload != "" {
+			c.handler.Say(payload)
+		}
+	case "persona":
+		if payload != "" {
+			c.handler.SetPersona(payload)
+		}
+	case "pause":
+		c.handler.Pause()
+	case "resume":
+		c.handler.Resume()
+	default:
+		logger.Warnf("⚠️  Unknown MQTT command topic %s", topic)
+	}
+}
+
+// Close disconnects from the broker after publishing the offline state
+func (c *Client) Close() {
+	if !c.client.IsConnected() {
+		return
+	}
+	if err := c.publish(c.topic("state"), true, []byte(offlineState)); err != nil {
+		logger.WithError(err).Warn("⚠️  Failed to publish MQTT offline state")
+	}
+	c.client.Disconnect(250)
+}
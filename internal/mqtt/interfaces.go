@@ -0,0 +1,34 @@
+package mqtt
+
+// CommandHandler executes commands received on the MQTT command topics
+type CommandHandler interface {
+	// Activate starts listening as if the wake word had been said
+	Activate()
+
+	// Say handles text as if it had been spoken
+	Say(text string)
+
+	// SetPersona replaces the AI system prompt
+	SetPersona(prompt string)
+
+	// Pause stops processing audio until Resume
+	Pause()
+
+	// Resume restarts audio processing
+	Resume()
+}
+
+// Config holds the MQTT connection settings
+type Config struct {
+	Broker      string // e.g. tcp://localhost:1883 or ssl://broker:8883
+	ClientID    string
+	Username    string
+	Password    string
+	TopicPrefix string
+
+	// TLS, used for ssl:// and tls:// brokers
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	InsecureSkipVerify bool
+}
@@ -0,0 +1,59 @@
+package mqtt
+
+// MockCommandHandler implements CommandHandler for testing
+type MockCommandHandler struct {
+	activations int
+	said        []string
+	persona     string
+	paused      bool
+}
+
+// NewMockCommandHandler creates a new mock command handler
+func NewMockCommandHandler() *MockCommandHandler {
+	return &MockCommandHandler{}
+}
+
+// Activate counts activations
+func (m *MockCommandHandler) Activate() {
+	m.activations++
+}
+
+// Say records the text
+func (m *MockCommandHandler) Say(text string) {
+	m.said = append(m.said, text)
+}
+
+// SetPersona records the prompt
+func (m *MockCommandHandler) SetPersona(prompt string) {
+	m.persona = prompt
+}
+
+// Pause marks the mock as paused
+func (m *MockCommandHandler) Pause() {
+	m.paused = true
+}
+
+// Resume marks the mock as running
+func (m *MockCommandHandler) Resume() {
+	m.paused = false
+}
+
+// IsPaused returns whether the mock is paused
+func (m *MockCommandHandler) IsPaused() bool {
+	return m.paused
+}
+
+// Activations returns the number of Activate calls
+func (m *MockCommandHandler) Activations() int {
+	return m.activations
+}
+
+// Said returns the texts passed to Say
+func (m *MockCommandHandler) Said() []string {
+	return m.said
+}
+
+// Persona returns the last prompt passed to SetPersona
+func (m *MockCommandHandler) Persona() string {
+	return m.persona
+}
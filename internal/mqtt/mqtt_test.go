@@ -0,0 +1,94 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+type published struct {
+	topic    string
+	retained bool
+	payload  []byte
+}
+
+func newTestClient(t *testing.T) (*Client, *[]published) {
+	t.Helper()
+
+	client, err := NewClient(Config{Broker: "tcp://localhost:1883", ClientID: "test", TopicPrefix: "home/nrz-ai/"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var messages []published
+	client.publish = func(topic string, retained bool, payload []byte) error {
+		messages = append(messages, published{topic, retained, payload})
+		return nil
+	}
+	return client, &messages
+}
+
+func TestClient_EmitTopics(t *testing.T) {
+	client, messages := newTestClient(t)
+
+	client.Emit(output.Event{Type: output.EventTranscript, Text: "Bonjour"})
+	client.Emit(output.Event{Type: output.EventState, State: "active"})
+	client.Emit(output.Event{Type: output.EventAIToken, Text: "Bon"})
+	client.Emit(output.Event{Type: output.EventVAD, State: "speech"})
+
+	if len(*messages) != 2 {
+		t.Fatalf("Expected 2 published messages, got %d", len(*messages))
+	}
+
+	first := (*messages)[0]
+	if first.topic != "home/nrz-ai/transcript" || first.retained {
+		t.Errorf("Unexpected transcript publication: %s retained=%v", first.topic, first.retained)
+	}
+	var event output.Event
+	if err := json.Unmarshal(first.payload, &event); err != nil || event.Text != "Bonjour" {
+		t.Errorf("Expected JSON event payload, got %s (%v)", first.payload, err)
+	}
+
+	second := (*messages)[1]
+	if second.topic != "home/nrz-ai/state" || !second.retained {
+		t.Errorf("Expected retained state publication, got %s retained=%v", second.topic, second.retained)
+	}
+}
+
+func TestClient_Commands(t *testing.T) {
+	client, _ := newTestClient(t)
+	handler := NewMockCommandHandler()
+	client.SetCommandHandler(handler)
+
+	client.handleCommand("home/nrz-ai/cmd/activate", "")
+	client.handleCommand("home/nrz-ai/cmd/say", " Quelle heure est-il ? ")
+	client.handleCommand("home/nrz-ai/cmd/say", "  ")
+	client.handleCommand("home/nrz-ai/cmd/persona", "Tu es un pirate.")
+	client.handleCommand("home/nrz-ai/cmd/reboot", "")
+
+	if handler.Activations() != 1 {
+		t.Errorf("Expected 1 activation, got %d", handler.Activations())
+	}
+	if said := handler.Said(); len(said) != 1 || said[0] != "Quelle heure est-il ?" {
+		t.Errorf("Expected one trimmed say command, got %q", said)
+	}
+	if handler.Persona() != "Tu es un pirate." {
+		t.Errorf("Expected persona to be set, got %q", handler.Persona())
+	}
+
+	client.handleCommand("home/nrz-ai/cmd/pause", "")
+	if !handler.IsPaused() {
+		t.Error("Expected the pause command to pause")
+	}
+	client.handleCommand("home/nrz-ai/cmd/resume", "")
+	if handler.IsPaused() {
+		t.Error("Expected the resume command to resume")
+	}
+}
+
+func TestNewClient_InvalidCAFile(t *testing.T) {
+	if _, err := NewClient(Config{Broker: "ssl://localhost:8883", CAFile: "/nonexistent/ca.pem"}); err == nil {
+		t.Error("Expected error for missing CA file")
+	}
+}
@@ -0,0 +1,105 @@
+// Package notes appends the transcripts and AI exchanges to daily Markdown
+// notes, so voice notes land in a note-taking system such as Obsidian
+package notes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/privacy"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+// DailyNotes is an output.Emitter appending transcripts and AI answers to
+// the note of their day. The note is opened for each entry, so other
+// programs may edit it in between and the day rolls over at midnight.
+type DailyNotes struct {
+	template string
+	lastPath string
+	mutex    sync.Mutex
+}
+
+// NewDailyNotes creates a sink writing to the notes named by template, where
+// {date} is replaced by 2006-01-02 and {year}, {month} and {day} by the parts
+// of the date
+func NewDailyNotes(template string) (*DailyNotes, error) {
+	if err := privacy.Check("daily notes"); err != nil {
+		return nil, err
+	}
+	if !strings.Contains(template, "{date}") && !strings.Contains(template, "{day}") {
+		return nil, fmt.Errorf("daily notes path '%s' has no {date} or {day} placeholder", template)
+	}
+	return &DailyNotes{template: template}, nil
+}
+
+// Path returns the note of the day of t
+func (n *DailyNotes) Path(t time.Time) string {
+	return strings.NewReplacer(
+		"{date}", t.Format("2006-01-02"),
+		"{year}", t.Format("2006"),
+		"{month}", t.Format("01"),
+		"{day}", t.Format("02"),
+	).Replace(n.template)
+}
+
+// Emit appends transcript and AI response events, other events are ignored
+func (n *DailyNotes) Emit(event output.Event) error {
+	text := strings.TrimSpace(event.Text)
+	if text == "" {
+		return nil
+	}
+
+	var who string
+	switch event.Type {
+	case output.EventTranscript:
+		who = event.Speaker
+	case output.EventAIResponse:
+		who = "AI"
+	default:
+		return nil
+	}
+
+	timestamp := event.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	path := n.Path(timestamp)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var b strings.Builder
+	if path != n.lastPath {
+		// Each session, and each day of a long one, gets its own section
+		if info, err := file.Stat(); err == nil && info.Size() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "## Voice notes %s\n\n", timestamp.Format("15:04"))
+	}
+	fmt.Fprintf(&b, "- **%s** ", timestamp.Format("15:04:05"))
+	if who != "" {
+		b.WriteString(who + ": ")
+	}
+	// Continuation lines stay in the list item
+	b.WriteString(strings.ReplaceAll(text, "\n", "\n  "))
+	b.WriteString("\n")
+
+	if _, err := file.WriteString(b.String()); err != nil {
+		return err
+	}
+	n.lastPath = path
+	return nil
+}
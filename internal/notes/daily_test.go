@@ -0,0 +1,58 @@
+package notes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+func TestDailyNotes_AppendsToTheNoteOfTheDay(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "2024-06-01.md")
+	if err := os.WriteFile(existing, []byte("# Saturday\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := NewDailyNotes(filepath.Join(dir, "{date}.md"))
+	if err != nil {
+		t.Fatalf("Failed to create daily notes: %v", err)
+	}
+	evening := time.Date(2024, 6, 1, 23, 59, 0, 0, time.Local)
+	for _, event := range []output.Event{
+		{Type: output.EventTranscript, Time: evening, Text: " Acheter du pain "},
+		{Type: output.EventVAD, Time: evening, State: "speech"},
+		{Type: output.EventAIResponse, Time: evening.Add(2 * time.Second), Text: "C'est noté.\nAutre chose ?"},
+		{Type: output.EventTranscript, Time: evening.Add(2 * time.Minute), Text: "Bonne nuit", Speaker: "Alice"},
+	} {
+		if err := n.Emit(event); err != nil {
+			t.Fatalf("Failed to write note: %v", err)
+		}
+	}
+
+	note, _ := os.ReadFile(existing)
+	expected := "# Saturday\n\n## Voice notes 23:59\n\n- **23:59:00** Acheter du pain\n- **23:59:02** AI: C'est noté.\n  Autre chose ?\n"
+	if string(note) != expected {
+		t.Errorf("Expected %q, got %q", expected, note)
+	}
+	next, _ := os.ReadFile(filepath.Join(dir, "2024-06-02.md"))
+	if expected := "## Voice notes 00:01\n\n- **00:01:00** Alice: Bonne nuit\n"; string(next) != expected {
+		t.Errorf("Expected %q the next day, got %q", expected, next)
+	}
+}
+
+func TestDailyNotes_Path(t *testing.T) {
+	n, err := NewDailyNotes("/notes/{year}/{month}/{day}.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path := n.Path(time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)); path != "/notes/2024/06/01.md" {
+		t.Errorf("Expected /notes/2024/06/01.md, got %s", path)
+	}
+
+	if _, err := NewDailyNotes("/notes/journal.md"); err == nil {
+		t.Error("Expected an error without a date placeholder")
+	}
+}
@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// CommandNotifier implements Notifier with notify-send (libnotify)
+type CommandNotifier struct {
+	path string
+}
+
+// NewNotifier creates a notifier using notify-send from PATH
+func NewNotifier() (*CommandNotifier, error) {
+	path, err := exec.LookPath("notify-send")
+	if err != nil {
+		return nil, fmt.Errorf("notify-send not found in PATH, install libnotify")
+	}
+	return &CommandNotifier{path: path}, nil
+}
+
+// Notify runs notify-send
+func (c *CommandNotifier) Notify(notification Notification) error {
+	args := []string{"--app-name", "nrz-ai", "--icon", "audio-input-microphone"}
+	if notification.Urgency != "" {
+		args = append(args, "--urgency", notification.Urgency)
+	}
+	args = append(args, "--", notification.Summary)
+	if notification.Body != "" {
+		args = append(args, notification.Body)
+	}
+
+	if output, err := exec.Command(c.path, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("notify-send failed: %w: %s", err, output)
+	}
+	return nil
+}
+
+// Name returns the backend name
+func (c *CommandNotifier) Name() string {
+	return "notify-send"
+}
@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+// maxBodyLength keeps long AI answers from covering the screen
+const maxBodyLength = 200
+
+// Events lists the event types that can trigger a notification
+var Events = []output.EventType{output.EventWakeWord, output.EventTranscript, output.EventAIResponse, output.EventTimer, output.EventAIStatus}
+
+// ParseEvents validates the event names selected for notifications
+func ParseEvents(names []string) ([]output.EventType, error) {
+	events := make([]output.EventType, 0, len(names))
+	for _, name := range names {
+		event := output.EventType(strings.TrimSpace(name))
+		supported := false
+		for _, candidate := range Events {
+			if event == candidate {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return nil, fmt.Errorf("unknown notification event '%s' (expected wake_word, transcript, ai_response, timer or ai_status)", name)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// Emitter turns pipeline events into desktop notifications
+type Emitter struct {
+	notifier Notifier
+	events   map[output.EventType]bool
+}
+
+// NewEmitter notifies the given event types through notifier
+func NewEmitter(notifier Notifier, events []output.EventType) *Emitter {
+	e := &Emitter{
+		notifier: notifier,
+		events:   make(map[output.EventType]bool),
+	}
+	for _, event := range events {
+		e.events[event] = true
+	}
+	return e
+}
+
+// Emit shows a notification for selected events without blocking the pipeline
+func (e *Emitter) Emit(event output.Event) error {
+	if !e.events[event.Type] {
+		return nil
+	}
+
+	notification, ok := notificationFor(event)
+	if !ok {
+		return nil
+	}
+
+	go func() {
+		if err := e.notifier.Notify(notification); err != nil {
+			logger.WithError(err).Warn("⚠️  Failed to show desktop notification")
+		}
+	}()
+	return nil
+}
+
+// notificationFor builds the notification of an event, false for empty events
+func notificationFor(event output.Event) (Notification, bool) {
+	text := truncate(strings.TrimSpace(event.Text))
+
+	switch event.Type {
+	case output.EventWakeWord:
+		body := "Wake word detected"
+		if event.State != "" && event.State != "voice" {
+			body = "Activated (" + event.State + ")"
+		}
+		return Notification{Summary: "🎯 Listening", Body: body, Urgency: "low"}, true
+	case output.EventTranscript:
+		if text == "" {
+			return Notification{}, false
+		}
+		summary := "🗣️ Transcript"
+		if event.Speaker != "" {
+			summary = "🗣️ " + event.Speaker
+		}
+		return Notification{Summary: summary, Body: text, Urgency: "low"}, true
+	case output.EventAIResponse:
+		if text == "" {
+			return Notification{}, false
+		}
+		return Notification{Summary: "🤖 nrz-ai", Body: text}, true
+	case output.EventTimer:
+		return Notification{Summary: "⏰ nrz-ai", Body: text, Urgency: "critical"}, true
+	case output.EventAIStatus:
+		if event.State == "available" {
+			return Notification{Summary: "🤖 nrz-ai", Body: "AI service back online", Urgency: "low"}, true
+		}
+		return Notification{Summary: "🤖 nrz-ai", Body: "AI service unavailable"}, true
+	default:
+		return Notification{}, false
+	}
+}
+
+// truncate shortens text to maxBodyLength runes
+func truncate(text string) string {
+	runes := []rune(text)
+	if len(runes) <= maxBodyLength {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:maxBodyLength-1])) + "…"
+}
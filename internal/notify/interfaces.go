@@ -0,0 +1,17 @@
+package notify
+
+// Notifier shows desktop notifications
+type Notifier interface {
+	// Notify shows a notification
+	Notify(notification Notification) error
+
+	// Name returns the backend name
+	Name() string
+}
+
+// Notification is a single desktop notification
+type Notification struct {
+	Summary string
+	Body    string
+	Urgency string // low, normal or critical, empty for the server default
+}
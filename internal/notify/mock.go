@@ -0,0 +1,45 @@
+package notify
+
+import "sync"
+
+// MockNotifier implements Notifier for testing
+type MockNotifier struct {
+	notifications []Notification
+	notifyError   error
+	mutex         sync.Mutex
+}
+
+// NewMockNotifier creates a new mock notifier
+func NewMockNotifier() *MockNotifier {
+	return &MockNotifier{}
+}
+
+// SetNotifyError sets an error to return for Notify calls
+func (m *MockNotifier) SetNotifyError(err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.notifyError = err
+}
+
+// Notifications returns the notifications shown so far
+func (m *MockNotifier) Notifications() []Notification {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]Notification(nil), m.notifications...)
+}
+
+// Notify records the notification
+func (m *MockNotifier) Notify(notification Notification) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.notifyError != nil {
+		return m.notifyError
+	}
+	m.notifications = append(m.notifications, notification)
+	return nil
+}
+
+// Name returns the mock name
+func (m *MockNotifier) Name() string {
+	return "mock"
+}
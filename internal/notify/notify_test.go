@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+// waitNotifications waits for the asynchronous notifications
+func waitNotifications(mock *MockNotifier, count int) []Notification {
+	deadline := time.Now().Add(time.Second)
+	for len(mock.Notifications()) < count && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	return mock.Notifications()
+}
+
+func TestParseEvents(t *testing.T) {
+	events, err := ParseEvents([]string{"wake_word", " ai_response"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(events) != 2 || events[1] != output.EventAIResponse {
+		t.Errorf("Unexpected events %v", events)
+	}
+
+	if _, err := ParseEvents([]string{"vad"}); err == nil {
+		t.Error("Expected error for unsupported event")
+	}
+}
+
+func TestEmitter_SelectedEvents(t *testing.T) {
+	mock := NewMockNotifier()
+	emitter := NewEmitter(mock, []output.EventType{output.EventTranscript, output.EventAIResponse})
+
+	emitter.Emit(output.Event{Type: output.EventWakeWord, Text: "Jack"})
+	emitter.Emit(output.Event{Type: output.EventVAD, State: "speech"})
+	emitter.Emit(output.Event{Type: output.EventTranscript, Text: "  "})
+	emitter.Emit(output.Event{Type: output.EventTranscript, Text: "Bonjour", Speaker: "Speaker 1"})
+
+	notifications := waitNotifications(mock, 1)
+	if len(notifications) != 1 {
+		t.Fatalf("Expected 1 notification, got %v", notifications)
+	}
+	if notifications[0].Summary != "🗣️ Speaker 1" || notifications[0].Body != "Bonjour" {
+		t.Errorf("Unexpected notification %+v", notifications[0])
+	}
+}
+
+func TestEmitter_TruncatesLongAnswers(t *testing.T) {
+	mock := NewMockNotifier()
+	emitter := NewEmitter(mock, []output.EventType{output.EventAIResponse})
+
+	emitter.Emit(output.Event{Type: output.EventAIResponse, Text: strings.Repeat("é", 500)})
+
+	notifications := waitNotifications(mock, 1)
+	if len(notifications) != 1 {
+		t.Fatalf("Expected 1 notification, got %v", notifications)
+	}
+	body := []rune(notifications[0].Body)
+	if len(body) != maxBodyLength || body[len(body)-1] != '…' {
+		t.Errorf("Expected body truncated to %d runes, got %d", maxBodyLength, len(body))
+	}
+}
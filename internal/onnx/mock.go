@@ -0,0 +1,65 @@
+package onnx
+
+import "errors"
+
+// MockSession implements Session for testing
+type MockSession struct {
+	inputs  []string
+	outputs []string
+	run     func(inputs map[string]*Tensor) (map[string]*Tensor, error)
+	calls   int
+	closed  bool
+}
+
+// NewMockSession creates a mock session of a model with the given input and
+// output names
+func NewMockSession(inputs, outputs []string) *MockSession {
+	return &MockSession{inputs: inputs, outputs: outputs}
+}
+
+// SetRun sets the function computing the outputs of the model
+func (m *MockSession) SetRun(run func(inputs map[string]*Tensor) (map[string]*Tensor, error)) {
+	m.run = run
+}
+
+// Run checks the inputs and returns the outputs of the run function, an
+// empty map without one
+func (m *MockSession) Run(inputs map[string]*Tensor) (map[string]*Tensor, error) {
+	if m.closed {
+		return nil, errors.New("ONNX session closed")
+	}
+	if err := checkInputs(m.inputs, inputs); err != nil {
+		return nil, err
+	}
+	m.calls++
+	if m.run == nil {
+		return map[string]*Tensor{}, nil
+	}
+	return m.run(inputs)
+}
+
+// InputNames returns the input names of the mock model
+func (m *MockSession) InputNames() []string {
+	return m.inputs
+}
+
+// OutputNames returns the output names of the mock model
+func (m *MockSession) OutputNames() []string {
+	return m.outputs
+}
+
+// Provider returns ProviderCPU
+func (m *MockSession) Provider() Provider {
+	return ProviderCPU
+}
+
+// Close marks the session closed
+func (m *MockSession) Close() error {
+	m.closed = true
+	return nil
+}
+
+// Calls returns the number of successful runs
+func (m *MockSession) Calls() int {
+	return m.calls
+}
@@ -0,0 +1,88 @@
+// Package onnx runs ONNX models for the subsystems needing neural inference,
+// such as voice activity detection, wake word spotting and diarization. The
+// onnxruntime library is only linked in builds tagged onnx.
+package onnx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nerzhul/nrz-ai/internal/hardware"
+)
+
+// ErrUnavailable is returned by NewSession in builds without onnxruntime
+var ErrUnavailable = errors.New("ONNX runtime not built in, rebuild with -tags onnx")
+
+// Provider is the onnxruntime execution provider running a model
+type Provider string
+
+// Providers
+const (
+	ProviderAuto Provider = "auto" // CUDA when an NVIDIA GPU is detected
+	ProviderCPU  Provider = "cpu"
+	ProviderCUDA Provider = "cuda"
+)
+
+// Options holds the settings of a session
+type Options struct {
+	Provider Provider // Defaults to ProviderAuto
+	Threads  int      // Intra-op threads, 0 for the runtime default
+}
+
+// Session is a loaded model
+type Session interface {
+	// Run runs the model on inputs, keyed by input name, and returns every
+	// output keyed by name
+	Run(inputs map[string]*Tensor) (map[string]*Tensor, error)
+
+	// InputNames returns the names of the model inputs, in model order
+	InputNames() []string
+
+	// OutputNames returns the names of the model outputs, in model order
+	OutputNames() []string
+
+	// Provider returns the execution provider the model runs on
+	Provider() Provider
+
+	// Close releases the model
+	Close() error
+}
+
+// ParseProvider parses a provider name, empty for ProviderAuto
+func ParseProvider(name string) (Provider, error) {
+	switch provider := Provider(strings.ToLower(strings.TrimSpace(name))); provider {
+	case "":
+		return ProviderAuto, nil
+	case ProviderAuto, ProviderCPU, ProviderCUDA:
+		return provider, nil
+	default:
+		return "", fmt.Errorf("unknown ONNX provider %q (auto, cpu, cuda)", name)
+	}
+}
+
+// ResolveProvider returns the provider actually requested on caps: CUDA for
+// auto on an NVIDIA GPU, the CPU otherwise
+func ResolveProvider(provider Provider, caps hardware.Capabilities) Provider {
+	if provider != ProviderAuto && provider != "" {
+		return provider
+	}
+	if caps.Accelerator == hardware.AcceleratorCUDA {
+		return ProviderCUDA
+	}
+	return ProviderCPU
+}
+
+// checkInputs reports the first input of names missing from inputs
+func checkInputs(names []string, inputs map[string]*Tensor) error {
+	for _, name := range names {
+		tensor, ok := inputs[name]
+		if !ok || tensor == nil {
+			return fmt.Errorf("missing ONNX input %q", name)
+		}
+		if err := tensor.Validate(); err != nil {
+			return fmt.Errorf("invalid ONNX input %q: %w", name, err)
+		}
+	}
+	return nil
+}
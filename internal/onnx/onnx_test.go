@@ -0,0 +1,99 @@
+package onnx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nerzhul/nrz-ai/internal/hardware"
+)
+
+func TestTensor_Validate(t *testing.T) {
+	if _, err := NewFloat([]float32{1, 2, 3, 4, 5, 6}, 2, 3); err != nil {
+		t.Errorf("Expected a valid 2x3 tensor, got: %v", err)
+	}
+	if _, err := NewFloat([]float32{1, 2, 3}, 2, 2); err == nil {
+		t.Error("Expected an error for 3 elements in a 2x2 shape")
+	}
+	if _, err := NewInt([]int64{16000}, -1); err == nil {
+		t.Error("Expected an error for a negative dimension")
+	}
+
+	rate, err := NewInt([]int64{16000})
+	if err != nil || len(rate.Shape) != 1 || rate.Shape[0] != 1 {
+		t.Errorf("Expected a one-dimensional tensor by default, got %v (%v)", rate.Shape, err)
+	}
+	if both := (&Tensor{Shape: []int64{1}, Float: []float32{1}, Int: []int64{1}}); both.Validate() == nil {
+		t.Error("Expected an error for a tensor holding both element types")
+	}
+}
+
+func TestTensor_Helpers(t *testing.T) {
+	state := Zeros(2, 1, 128)
+	if len(state.Float) != 256 || state.Validate() != nil {
+		t.Errorf("Expected 256 zeros, got %d", len(state.Float))
+	}
+	if scalar := Zeros(); scalar.Elements() != 1 {
+		t.Errorf("Expected a scalar to hold 1 element, got %d", scalar.Elements())
+	}
+
+	batch := Batch(make([]float32, 512))
+	if batch.Shape[0] != 1 || batch.Shape[1] != 512 || batch.Validate() != nil {
+		t.Errorf("Expected a [1 512] batch, got %v", batch.Shape)
+	}
+}
+
+func TestProviders(t *testing.T) {
+	for name, expected := range map[string]Provider{"": ProviderAuto, "CUDA": ProviderCUDA, " cpu ": ProviderCPU} {
+		if provider, err := ParseProvider(name); err != nil || provider != expected {
+			t.Errorf("Expected %s for %q, got %s (%v)", expected, name, provider, err)
+		}
+	}
+	if _, err := ParseProvider("tpu"); err == nil {
+		t.Error("Expected an error for an unknown provider")
+	}
+
+	cuda := hardware.Capabilities{Accelerator: hardware.AcceleratorCUDA}
+	rocm := hardware.Capabilities{Accelerator: hardware.AcceleratorROCm}
+	if provider := ResolveProvider(ProviderAuto, cuda); provider != ProviderCUDA {
+		t.Errorf("Expected CUDA on an NVIDIA GPU, got %s", provider)
+	}
+	if provider := ResolveProvider(ProviderAuto, rocm); provider != ProviderCPU {
+		t.Errorf("Expected the CPU without CUDA, got %s", provider)
+	}
+	if provider := ResolveProvider(ProviderCPU, cuda); provider != ProviderCPU {
+		t.Errorf("Expected an explicit provider to be kept, got %s", provider)
+	}
+}
+
+func TestNewSession_Unavailable(t *testing.T) {
+	if Available {
+		t.Skip("onnxruntime linked in")
+	}
+	if _, err := NewSession("silero_vad.onnx", Options{}); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable, got: %v", err)
+	}
+}
+
+func TestMockSession(t *testing.T) {
+	session := NewMockSession([]string{"input", "sr"}, []string{"output"})
+	session.SetRun(func(inputs map[string]*Tensor) (map[string]*Tensor, error) {
+		return map[string]*Tensor{"output": {Shape: []int64{1}, Float: []float32{inputs["input"].Float[0] * 2}}}, nil
+	})
+
+	rate, _ := NewInt([]int64{16000})
+	if _, err := session.Run(map[string]*Tensor{"input": Batch([]float32{0.5})}); err == nil {
+		t.Error("Expected an error for a missing input")
+	}
+	outputs, err := session.Run(map[string]*Tensor{"input": Batch([]float32{0.5}), "sr": rate})
+	if err != nil || outputs["output"].Float[0] != 1 {
+		t.Errorf("Expected output 1, got %v (%v)", outputs["output"], err)
+	}
+	if session.Calls() != 1 {
+		t.Errorf("Expected 1 run, got %d", session.Calls())
+	}
+
+	session.Close()
+	if _, err := session.Run(map[string]*Tensor{"input": Batch([]float32{0.5}), "sr": rate}); err == nil {
+		t.Error("Expected an error once closed")
+	}
+}
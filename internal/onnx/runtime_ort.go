@@ -0,0 +1,444 @@
+//go:build onnx
+
+package onnx
+
+/*
+#cgo LDFLAGS: -lonnxruntime
+#include <stdlib.h>
+#include <string.h>
+#include <onnxruntime_c_api.h>
+
+static const OrtApi *ort;
+
+static int ort_init(void) {
+	const OrtApiBase *base = OrtGetApiBase();
+	if (base != NULL) {
+		ort = base->GetApi(ORT_API_VERSION);
+	}
+	return ort != NULL;
+}
+
+// ort_error returns the message of status, to be freed, and releases it
+static char *ort_error(OrtStatus *status) {
+	if (status == NULL) {
+		return NULL;
+	}
+	char *message = strdup(ort->GetErrorMessage(status));
+	ort->ReleaseStatus(status);
+	return message;
+}
+
+static char *ort_create_env(OrtEnv **env) {
+	return ort_error(ort->CreateEnv(ORT_LOGGING_LEVEL_WARNING, "nrz-ai", env));
+}
+
+static char *ort_create_options(OrtSessionOptions **options, int threads) {
+	char *err = ort_error(ort->CreateSessionOptions(options));
+	if (err == NULL && threads > 0) {
+		err = ort_error(ort->SetIntraOpNumThreads(*options, threads));
+	}
+	if (err == NULL) {
+		err = ort_error(ort->SetSessionGraphOptimizationLevel(*options, ORT_ENABLE_ALL));
+	}
+	return err;
+}
+
+static char *ort_append_cuda(OrtSessionOptions *options) {
+	OrtCUDAProviderOptionsV2 *cuda = NULL;
+	char *err = ort_error(ort->CreateCUDAProviderOptions(&cuda));
+	if (err == NULL) {
+		err = ort_error(ort->SessionOptionsAppendExecutionProvider_CUDA_V2(options, cuda));
+		ort->ReleaseCUDAProviderOptions(cuda);
+	}
+	return err;
+}
+
+static char *ort_create_session(OrtEnv *env, const char *path, OrtSessionOptions *options, OrtSession **session) {
+	return ort_error(ort->CreateSession(env, path, options, session));
+}
+
+static char *ort_count(OrtSession *session, int output, size_t *count) {
+	if (output) {
+		return ort_error(ort->SessionGetOutputCount(session, count));
+	}
+	return ort_error(ort->SessionGetInputCount(session, count));
+}
+
+// ort_name returns the name of an input or output, to be freed
+static char *ort_name(OrtSession *session, int output, size_t index, char **name) {
+	OrtAllocator *allocator = NULL;
+	char *allocated = NULL;
+	char *err = ort_error(ort->GetAllocatorWithDefaultOptions(&allocator));
+	if (err == NULL && output) {
+		err = ort_error(ort->SessionGetOutputName(session, index, allocator, &allocated));
+	} else if (err == NULL) {
+		err = ort_error(ort->SessionGetInputName(session, index, allocator, &allocated));
+	}
+	if (err == NULL) {
+		*name = strdup(allocated);
+		free(ort_error(ort->AllocatorFree(allocator, allocated)));
+	}
+	return err;
+}
+
+static char *ort_create_tensor(void *data, size_t size, int64_t *shape, size_t dims, int type, OrtValue **value) {
+	OrtMemoryInfo *memory = NULL;
+	char *err = ort_error(ort->CreateCpuMemoryInfo(OrtArenaAllocator, OrtMemTypeDefault, &memory));
+	if (err == NULL) {
+		err = ort_error(ort->CreateTensorWithDataAsOrtValue(memory, data, size, shape, dims,
+			(ONNXTensorElementDataType)type, value));
+		ort->ReleaseMemoryInfo(memory);
+	}
+	return err;
+}
+
+static char *ort_run(OrtSession *session, const char **input_names, const OrtValue **inputs, size_t input_count,
+		const char **output_names, size_t output_count, OrtValue **outputs) {
+	return ort_error(ort->Run(session, NULL, input_names, inputs, input_count, output_names, output_count, outputs));
+}
+
+// ort_tensor_shape reads the element type, element count and shape of value,
+// dims is set even when the shape holds more than max dimensions
+static char *ort_tensor_shape(const OrtValue *value, int *type, size_t *count, int64_t *shape, size_t max, size_t *dims) {
+	OrtTensorTypeAndShapeInfo *info = NULL;
+	ONNXTensorElementDataType element;
+	char *err = ort_error(ort->GetTensorTypeAndShape(value, &info));
+	if (err == NULL) {
+		err = ort_error(ort->GetTensorElementType(info, &element));
+	}
+	if (err == NULL) {
+		*type = element;
+		err = ort_error(ort->GetTensorShapeElementCount(info, count));
+	}
+	if (err == NULL) {
+		err = ort_error(ort->GetDimensionsCount(info, dims));
+	}
+	if (err == NULL && *dims <= max) {
+		err = ort_error(ort->GetDimensions(info, shape, *dims));
+	}
+	if (info != NULL) {
+		ort->ReleaseTensorTypeAndShapeInfo(info);
+	}
+	return err;
+}
+
+static char *ort_tensor_data(OrtValue *value, void **data) {
+	return ort_error(ort->GetTensorMutableData(value, data));
+}
+
+static void ort_release_value(OrtValue *value) { ort->ReleaseValue(value); }
+static void ort_release_session(OrtSession *session) { ort->ReleaseSession(session); }
+static void ort_release_options(OrtSessionOptions *options) { ort->ReleaseSessionOptions(options); }
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+
+	"github.com/nerzhul/nrz-ai/internal/hardware"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+)
+
+// Available reports whether onnxruntime is linked in
+const Available = true
+
+// maxDims bounds the dimensions of the output tensors
+const maxDims = 8
+
+// The environment is shared by every session of the process, as the
+// runtime expects
+var (
+	envOnce sync.Once
+	env     *C.OrtEnv
+	envErr  error
+)
+
+// ortSession is a session of onnxruntime
+type ortSession struct {
+	mutex    sync.Mutex
+	session  *C.OrtSession
+	inputs   []string
+	outputs  []string
+	cInputs  []*C.char
+	cOutputs []*C.char
+	provider Provider
+}
+
+// NewSession loads the model at modelPath. With ProviderAuto, a model that
+// cannot run on CUDA falls back to the CPU.
+func NewSession(modelPath string, opts Options) (Session, error) {
+	envOnce.Do(initEnv)
+	if envErr != nil {
+		return nil, envErr
+	}
+
+	provider := opts.Provider
+	if provider == ProviderAuto || provider == "" {
+		provider = ResolveProvider(provider, hardware.Detect())
+	}
+
+	session, err := createSession(modelPath, provider, opts.Threads)
+	if err != nil && provider == ProviderCUDA && opts.Provider != ProviderCUDA {
+		logger.WithError(err).Warn("⚠️  ONNX model cannot run on CUDA, falling back to the CPU")
+		provider = ProviderCPU
+		session, err = createSession(modelPath, provider, opts.Threads)
+	}
+	if err != nil {
+		return nil, err
+	}
+	session.provider = provider
+
+	if session.inputs, session.cInputs, err = session.names(false); err == nil {
+		session.outputs, session.cOutputs, err = session.names(true)
+	}
+	if err == nil && (len(session.inputs) == 0 || len(session.outputs) == 0) {
+		err = fmt.Errorf("ONNX model %s without inputs or outputs", modelPath)
+	}
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+	return session, nil
+}
+
+// initEnv creates the shared environment
+func initEnv() {
+	if C.ort_init() == 0 {
+		envErr = errors.New("onnxruntime library does not provide the expected API version")
+		return
+	}
+	envErr = status(C.ort_create_env(&env))
+}
+
+// createSession loads the model at modelPath on provider
+func createSession(modelPath string, provider Provider, threads int) (*ortSession, error) {
+	var options *C.OrtSessionOptions
+	err := status(C.ort_create_options(&options, C.int(threads)))
+	if options != nil {
+		defer C.ort_release_options(options)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ONNX session options: %w", err)
+	}
+
+	if provider == ProviderCUDA {
+		if err := status(C.ort_append_cuda(options)); err != nil {
+			return nil, fmt.Errorf("failed to enable CUDA: %w", err)
+		}
+	}
+
+	path := C.CString(modelPath)
+	defer C.free(unsafe.Pointer(path))
+
+	s := &ortSession{}
+	if err := status(C.ort_create_session(env, path, options, &s.session)); err != nil {
+		return nil, fmt.Errorf("failed to load ONNX model %s: %w", modelPath, err)
+	}
+	return s, nil
+}
+
+// names returns the input or output names of the model, also as C strings
+func (s *ortSession) names(output bool) ([]string, []*C.char, error) {
+	kind := C.int(0)
+	if output {
+		kind = 1
+	}
+
+	var count C.size_t
+	if err := status(C.ort_count(s.session, kind, &count)); err != nil {
+		return nil, nil, err
+	}
+
+	names := make([]string, 0, int(count))
+	cNames := make([]*C.char, 0, int(count))
+	for i := range int(count) {
+		var name *C.char
+		if err := status(C.ort_name(s.session, kind, C.size_t(i), &name)); err != nil {
+			freeNames(cNames)
+			return nil, nil, err
+		}
+		names = append(names, C.GoString(name))
+		cNames = append(cNames, name)
+	}
+	return names, cNames, nil
+}
+
+// Run runs the model on inputs
+func (s *ortSession) Run(inputs map[string]*Tensor) (map[string]*Tensor, error) {
+	if err := checkInputs(s.inputs, inputs); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.session == nil {
+		return nil, errors.New("ONNX session closed")
+	}
+
+	values := make([]*C.OrtValue, len(s.inputs))
+	buffers := make([]unsafe.Pointer, 0, len(s.inputs))
+	defer func() {
+		for _, value := range values {
+			if value != nil {
+				C.ort_release_value(value)
+			}
+		}
+		for _, buffer := range buffers {
+			C.free(buffer)
+		}
+	}()
+
+	for i, name := range s.inputs {
+		value, buffer, err := newValue(inputs[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ONNX input %q: %w", name, err)
+		}
+		values[i] = value
+		buffers = append(buffers, buffer)
+	}
+
+	outputs := make([]*C.OrtValue, len(s.outputs))
+	err := status(C.ort_run(s.session, &s.cInputs[0], &values[0], C.size_t(len(values)),
+		&s.cOutputs[0], C.size_t(len(outputs)), &outputs[0]))
+	defer func() {
+		for _, output := range outputs {
+			if output != nil {
+				C.ort_release_value(output)
+			}
+		}
+	}()
+	if err != nil {
+		return nil, fmt.Errorf("ONNX inference failed: %w", err)
+	}
+
+	results := make(map[string]*Tensor, len(outputs))
+	for i, output := range outputs {
+		tensor, err := readValue(output)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ONNX output %q: %w", s.outputs[i], err)
+		}
+		results[s.outputs[i]] = tensor
+	}
+	return results, nil
+}
+
+// newValue copies t out of the Go heap, the runtime keeping a pointer to its
+// elements, and wraps it in a value. The buffer is freed after the value.
+func newValue(t *Tensor) (*C.OrtValue, unsafe.Pointer, error) {
+	elementType := C.int(C.ONNX_TENSOR_ELEMENT_DATA_TYPE_FLOAT)
+	size := len(t.Float) * 4
+	source := unsafe.Pointer(unsafe.SliceData(t.Float))
+	if t.Int != nil {
+		elementType = C.int(C.ONNX_TENSOR_ELEMENT_DATA_TYPE_INT64)
+		size = len(t.Int) * 8
+		source = unsafe.Pointer(unsafe.SliceData(t.Int))
+	}
+
+	buffer := C.malloc(C.size_t(max(size, 1)))
+	if size > 0 {
+		C.memcpy(buffer, source, C.size_t(size))
+	}
+
+	shape := make([]C.int64_t, len(t.Shape))
+	for i, dim := range t.Shape {
+		shape[i] = C.int64_t(dim)
+	}
+	var shapePtr *C.int64_t
+	if len(shape) > 0 {
+		shapePtr = &shape[0]
+	}
+
+	var value *C.OrtValue
+	if err := status(C.ort_create_tensor(buffer, C.size_t(size), shapePtr, C.size_t(len(shape)), elementType, &value)); err != nil {
+		C.free(buffer)
+		return nil, nil, err
+	}
+	return value, buffer, nil
+}
+
+// readValue copies a float32 or int64 output into a tensor
+func readValue(value *C.OrtValue) (*Tensor, error) {
+	var elementType C.int
+	var count, dims C.size_t
+	shape := make([]C.int64_t, maxDims)
+	if err := status(C.ort_tensor_shape(value, &elementType, &count, &shape[0], maxDims, &dims)); err != nil {
+		return nil, err
+	}
+	if dims > maxDims {
+		return nil, fmt.Errorf("%d dimensions, at most %d supported", dims, maxDims)
+	}
+
+	t := &Tensor{Shape: make([]int64, int(dims))}
+	for i := range t.Shape {
+		t.Shape[i] = int64(shape[i])
+	}
+
+	var data unsafe.Pointer
+	if err := status(C.ort_tensor_data(value, &data)); err != nil {
+		return nil, err
+	}
+	switch elementType {
+	case C.ONNX_TENSOR_ELEMENT_DATA_TYPE_FLOAT:
+		t.Float = make([]float32, int(count))
+		if count > 0 {
+			copy(t.Float, unsafe.Slice((*float32)(data), int(count)))
+		}
+	case C.ONNX_TENSOR_ELEMENT_DATA_TYPE_INT64:
+		t.Int = make([]int64, int(count))
+		if count > 0 {
+			copy(t.Int, unsafe.Slice((*int64)(data), int(count)))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported element type %d", int(elementType))
+	}
+	return t, nil
+}
+
+// InputNames returns the names of the model inputs
+func (s *ortSession) InputNames() []string {
+	return s.inputs
+}
+
+// OutputNames returns the names of the model outputs
+func (s *ortSession) OutputNames() []string {
+	return s.outputs
+}
+
+// Provider returns the execution provider the model runs on
+func (s *ortSession) Provider() Provider {
+	return s.provider
+}
+
+// Close releases the model
+func (s *ortSession) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.session != nil {
+		C.ort_release_session(s.session)
+		s.session = nil
+	}
+	freeNames(s.cInputs)
+	freeNames(s.cOutputs)
+	s.cInputs, s.cOutputs = nil, nil
+	return nil
+}
+
+// status converts a runtime error message into an error and frees it
+func status(message *C.char) error {
+	if message == nil {
+		return nil
+	}
+	defer C.free(unsafe.Pointer(message))
+	return errors.New(C.GoString(message))
+}
+
+// freeNames frees the C strings of names
+func freeNames(names []*C.char) {
+	for _, name := range names {
+		C.free(unsafe.Pointer(name))
+	}
+}
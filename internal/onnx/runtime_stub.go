@@ -0,0 +1,11 @@
+//go:build !onnx
+
+package onnx
+
+// Available reports whether onnxruntime is linked in
+const Available = false
+
+// NewSession fails with ErrUnavailable, onnxruntime is not linked in
+func NewSession(modelPath string, opts Options) (Session, error) {
+	return nil, ErrUnavailable
+}
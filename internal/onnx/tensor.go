@@ -0,0 +1,75 @@
+package onnx
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Tensor is a dense tensor in row-major order, holding either float32 or
+// int64 elements
+type Tensor struct {
+	Shape []int64
+	Float []float32
+	Int   []int64 // e.g. the sample rate input of Silero
+}
+
+// NewFloat creates a float32 tensor of shape, one dimension of len(data)
+// when no shape is given
+func NewFloat(data []float32, shape ...int64) (*Tensor, error) {
+	if len(shape) == 0 {
+		shape = []int64{int64(len(data))}
+	}
+	t := &Tensor{Shape: shape, Float: data}
+	return t, t.Validate()
+}
+
+// NewInt creates an int64 tensor of shape, one dimension of len(data) when
+// no shape is given
+func NewInt(data []int64, shape ...int64) (*Tensor, error) {
+	if len(shape) == 0 {
+		shape = []int64{int64(len(data))}
+	}
+	t := &Tensor{Shape: shape, Int: data}
+	return t, t.Validate()
+}
+
+// Zeros creates a float32 tensor of shape filled with zeros, such as the
+// initial state of a recurrent model
+func Zeros(shape ...int64) *Tensor {
+	t := &Tensor{Shape: shape}
+	t.Float = make([]float32, max(t.Elements(), 0))
+	return t
+}
+
+// Batch creates a batch of one: a [1, len(samples)] float32 tensor
+func Batch(samples []float32) *Tensor {
+	return &Tensor{Shape: []int64{1, int64(len(samples))}, Float: samples}
+}
+
+// Elements returns the number of elements of the shape, 1 for a scalar and
+// -1 for a negative dimension
+func (t *Tensor) Elements() int {
+	count := 1
+	for _, dim := range t.Shape {
+		if dim < 0 {
+			return -1
+		}
+		count *= int(dim)
+	}
+	return count
+}
+
+// Validate checks that exactly one element slice fits the shape
+func (t *Tensor) Validate() error {
+	if t.Float != nil && t.Int != nil {
+		return errors.New("tensor holds both float and int elements")
+	}
+	count := t.Elements()
+	if count < 0 {
+		return fmt.Errorf("negative dimension in shape %v", t.Shape)
+	}
+	if length := max(len(t.Float), len(t.Int)); length != count {
+		return fmt.Errorf("%d elements for shape %v, want %d", length, t.Shape, count)
+	}
+	return nil
+}
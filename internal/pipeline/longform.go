@@ -0,0 +1,168 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+// stitchTolerance is how far, in seconds, a segment may end past the merged
+// ones and still be taken for a repetition
+const stitchTolerance = 0.05
+
+// LongForm transcribes recordings of any length in overlapping windows read
+// one at a time, so that memory stays bounded. Every window but the first
+// starts with the end of the previous one, and the words heard twice at the
+// seams are merged.
+type LongForm struct {
+	service    whisper.WhisperService
+	language   string
+	window     int
+	overlap    int
+	sampleRate int
+	processor  audio.AudioProcessor
+
+	// Optional labeler of the segments of every window, e.g. a diarizer
+	labeler func(samples []float32, segments []whisper.Segment) []whisper.Segment
+}
+
+// NewLongForm creates a transcriber of windows of window length, starting
+// overlap before the end of the previous one. The overlap is at most half
+// the window.
+func NewLongForm(service whisper.WhisperService, language string, window, overlap time.Duration, sampleRate int) *LongForm {
+	l := &LongForm{
+		service:    service,
+		language:   language,
+		window:     int(window.Seconds() * float64(sampleRate)),
+		overlap:    int(overlap.Seconds() * float64(sampleRate)),
+		sampleRate: sampleRate,
+		processor:  audio.NewProcessor(),
+	}
+	l.window = max(l.window, 1)
+	l.overlap = min(max(l.overlap, 0), l.window/2)
+	return l
+}
+
+// SetLabeler sets the function labeling the segments of every window with
+// its samples, before they are merged. Segment times are relative to the
+// window.
+func (l *LongForm) SetLabeler(labeler func(samples []float32, segments []whisper.Segment) []whisper.Segment) {
+	l.labeler = labeler
+}
+
+// Transcribe transcribes the raw float32 samples of stream until it ends
+func (l *LongForm) Transcribe(ctx context.Context, stream io.Reader) (whisper.TranscriptionResult, error) {
+	merged := whisper.TranscriptionResult{Language: l.language}
+	data := make([]byte, l.window*audio.BytesPerSample)
+	buffer := make([]float32, 0, l.window)
+	var start int64 // Stream position of the window
+
+	for {
+		n, err := io.ReadFull(stream, data[:(l.window-len(buffer))*audio.BytesPerSample])
+		last := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !last {
+			return merged, err
+		}
+		buffer = l.processor.AppendSamples(buffer, data[:n])
+
+		// A last window holding the overlap alone still brings the words
+		// past the seam of the previous one
+		if err := l.transcribeWindow(ctx, &merged, buffer, start, last); err != nil {
+			return merged, err
+		}
+		if last {
+			break
+		}
+
+		kept := copy(buffer, buffer[len(buffer)-l.overlap:])
+		start += int64(len(buffer) - kept)
+		buffer = buffer[:kept]
+	}
+
+	merged.Duration = float64(start+int64(len(buffer))) / float64(l.sampleRate)
+	return merged, nil
+}
+
+// transcribeWindow transcribes the window of samples starting at stream
+// position start and merges it into merged
+func (l *LongForm) transcribeWindow(ctx context.Context, merged *whisper.TranscriptionResult, samples []float32, start int64, last bool) error {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	var previous string
+	if count := len(merged.Segments); count > 0 {
+		previous = merged.Segments[count-1].Text
+	}
+
+	var result whisper.TranscriptionResult
+	var err error
+	if prompter, ok := l.service.(whisper.PromptTranscriber); ok && previous != "" {
+		result, err = prompter.TranscribePrompt(ctx, samples, l.language, previous)
+	} else {
+		result, err = l.service.Transcribe(ctx, samples, l.language)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to transcribe at %s: %w",
+			time.Duration(start)*time.Second/time.Duration(l.sampleRate), err)
+	}
+
+	duration := float64(len(samples)) / float64(l.sampleRate)
+	segments := result.Segments
+	if len(segments) == 0 && result.Text != "" {
+		segments = []whisper.Segment{{Text: result.Text, End: duration}}
+	}
+	if l.labeler != nil {
+		segments = l.labeler(samples, segments)
+	}
+	if start == 0 && result.Language != "" {
+		merged.Language = result.Language
+	}
+
+	offset := float64(start) / float64(l.sampleRate)
+	seam := duration - float64(l.overlap)/float64(l.sampleRate)/2
+	stitched := start == 0
+	for _, segment := range segments {
+		// The next window hears the end of this one whole
+		if !last && segment.Start >= seam {
+			break
+		}
+
+		segment.Start += offset
+		segment.End += offset
+		end := mergedEnd(merged)
+		if segment.End <= end+stitchTolerance && start > 0 {
+			continue
+		}
+		if !stitched {
+			stitched = true
+			var tail string
+			if count := len(merged.Segments); count > 0 {
+				tail = merged.Segments[count-1].Text
+			}
+			text := Stitch(tail, segment.Text)
+			if text == "" {
+				continue
+			}
+			segment.Text = " " + text
+			segment.Start = max(segment.Start, end)
+		}
+
+		merged.Segments = append(merged.Segments, segment)
+		merged.Text += segment.Text
+	}
+	return nil
+}
+
+// mergedEnd returns the end of the last merged segment, in seconds
+func mergedEnd(merged *whisper.TranscriptionResult) float64 {
+	if count := len(merged.Segments); count > 0 {
+		return merged.Segments[count-1].End
+	}
+	return 0
+}
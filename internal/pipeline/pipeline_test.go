@@ -0,0 +1,470 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/nerzhul/nrz-ai/pkg/vad"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+func encodeSamples(samples []float32) []byte {
+	data := make([]byte, 0, len(samples)*4)
+	for _, sample := range samples {
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(sample))
+	}
+	return data
+}
+
+func testVADConfig() vad.VADConfig {
+	return vad.VADConfig{
+		SampleRate:          1000,
+		SilenceDurationMs:   3,
+		MinSpeechDurationMs: 2,
+	}
+}
+
+func speech(speaking bool, n int) []bool {
+	pattern := make([]bool, n)
+	for i := range pattern {
+		pattern[i] = speaking
+	}
+	return pattern
+}
+
+func feed(chunks ...Chunk) <-chan Chunk {
+	in := make(chan Chunk, len(chunks))
+	for _, chunk := range chunks {
+		in <- chunk
+	}
+	close(in)
+	return in
+}
+
+func collect[T any](in <-chan T) []T {
+	var items []T
+	for item := range in {
+		items = append(items, item)
+	}
+	return items
+}
+
+func TestCaptureDecode_NumbersSamples(t *testing.T) {
+	stream := audio.NewMockAudioStream(encodeSamples([]float32{0.1, 0.2, 0.3, 0.4, 0.5}))
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	frames := Capture(context.Background(), stream, 8, func() time.Time { return clock }, nil)
+	chunks := collect(Decode(context.Background(), frames, audio.NewProcessor()))
+
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 chunks, got %d", len(chunks))
+	}
+	for i, expected := range []int64{0, 2, 4} {
+		if chunks[i].Offset != expected {
+			t.Errorf("Expected chunk %d at offset %d, got %d", i, expected, chunks[i].Offset)
+		}
+		if !chunks[i].Time.Equal(clock) {
+			t.Errorf("Expected chunk %d to carry the read time, got %v", i, chunks[i].Time)
+		}
+	}
+	if len(chunks[2].Samples) != 1 || chunks[2].Samples[0] != 0.5 {
+		t.Errorf("Expected last chunk [0.5], got %v", chunks[2].Samples)
+	}
+}
+
+func TestDecode_FramesSplitAcrossReads(t *testing.T) {
+	stream := audio.NewMockAudioStream(encodeSamples([]float32{0.1, 0.2, 0.3, 0.4, 0.5}))
+
+	// 6 byte reads end in the middle of every other sample
+	frames := Capture(context.Background(), stream, 6, time.Now, nil)
+	var samples []float32
+	var offset int64
+	for _, chunk := range collect(Decode(context.Background(), frames, audio.NewProcessor())) {
+		if chunk.Offset != offset {
+			t.Errorf("Expected chunk at offset %d, got %d", offset, chunk.Offset)
+		}
+		offset += int64(len(chunk.Samples))
+		samples = append(samples, chunk.Samples...)
+	}
+
+	expected := []float32{0.1, 0.2, 0.3, 0.4, 0.5}
+	if len(samples) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, samples)
+	}
+	for i := range expected {
+		if samples[i] != expected[i] {
+			t.Errorf("Expected sample %d to be %.1f, got %f", i, expected[i], samples[i])
+		}
+	}
+}
+
+func BenchmarkCaptureDecode(b *testing.B) {
+	data := make([]byte, 64*4096)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for b.Loop() {
+		frames := Capture(context.Background(), audio.NewMockAudioStream(data), 4096, time.Now, nil)
+		for range Decode(context.Background(), frames, audio.NewProcessor()) {
+		}
+	}
+}
+
+func TestCapture_ReportsReadErrors(t *testing.T) {
+	stream := audio.NewMockAudioStream(nil)
+	stream.SetReadError(context.DeadlineExceeded)
+
+	var reported error
+	frames := Capture(context.Background(), stream, 8, time.Now, func(err error) { reported = err })
+	if len(collect(frames)) != 0 {
+		t.Error("Expected no frames on read error")
+	}
+	if reported != context.DeadlineExceeded {
+		t.Errorf("Expected read error to be reported, got %v", reported)
+	}
+}
+
+func TestFilter_DropsEmptyChunks(t *testing.T) {
+	in := feed(
+		Chunk{Samples: []float32{0.1}},
+		Chunk{Samples: []float32{0.2}},
+		Chunk{Discontinuity: true, Samples: []float32{0.3}},
+	)
+	chunks := collect(Filter(context.Background(), in, func(chunk Chunk) Chunk {
+		chunk.Samples = chunk.Samples[:0]
+		return chunk
+	}))
+
+	if len(chunks) != 1 || !chunks[0].Discontinuity {
+		t.Errorf("Expected only the discontinuity to pass, got %+v", chunks)
+	}
+}
+
+func TestSegmenter_CutsOnSilence(t *testing.T) {
+	detector := vad.NewMockVAD()
+	detector.SetSpeechPattern(append(speech(true, 5), speech(false, 4)...))
+
+	segmenter := NewSegmenter(detector, testVADConfig(), 100)
+	var speaking []bool
+	segmenter.SetSpeechListener(func(s bool) { speaking = append(speaking, s) })
+	if err := segmenter.Initialize(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	segments := collect(segmenter.Run(context.Background(), feed(
+		Chunk{Samples: make([]float32, 9), Offset: 10},
+	)))
+
+	if len(segments) != 1 {
+		t.Fatalf("Expected 1 segment, got %d", len(segments))
+	}
+	if len(segments[0].Samples) != 8 || segments[0].Offset != 10 {
+		t.Errorf("Expected 8 samples at offset 10, got %d at %d", len(segments[0].Samples), segments[0].Offset)
+	}
+	if len(speaking) != 2 || !speaking[0] || speaking[1] {
+		t.Errorf("Expected speech start then end, got %v", speaking)
+	}
+}
+
+func TestSegmenter_FlushesOnClose(t *testing.T) {
+	detector := vad.NewMockVAD()
+	detector.SetSpeechPattern(speech(true, 4))
+
+	segmenter := NewSegmenter(detector, testVADConfig(), 100)
+	segmenter.Initialize()
+
+	segments := collect(segmenter.Run(context.Background(), feed(Chunk{Samples: make([]float32, 4)})))
+	if len(segments) != 1 || len(segments[0].Samples) != 4 {
+		t.Errorf("Expected the utterance in progress to be flushed, got %+v", segments)
+	}
+}
+
+func TestSegmenter_MaxSamples(t *testing.T) {
+	detector := vad.NewMockVAD()
+	detector.SetSpeechPattern(speech(true, 6))
+
+	segmenter := NewSegmenter(detector, testVADConfig(), 6)
+	segmenter.Initialize()
+
+	segments := collect(segmenter.Run(context.Background(), feed(
+		Chunk{Samples: make([]float32, 6)},
+		Chunk{Samples: make([]float32, 1), Offset: 6},
+	)))
+	if len(segments) != 2 || len(segments[0].Samples) != 6 {
+		t.Fatalf("Expected a segment cut at the maximum length, got %+v", segments)
+	}
+	// Without a pause the end of the first segment starts the second one
+	if second := segments[1]; !second.Continues || second.Overlap != 3 || len(second.Samples) != 4 || second.Offset != 3 {
+		t.Errorf("Expected the second segment to repeat 3 samples at offset 3, got %+v", second)
+	}
+}
+
+func TestSegmenter_SplitsAtLastPause(t *testing.T) {
+	config := testVADConfig()
+	config.SilenceDurationMs = 300
+	detector := vad.NewMockVAD()
+	// A 200ms pause in the speech, too short to end the utterance
+	detector.SetSpeechPattern(append(append(speech(true, 600), speech(false, 200)...), speech(true, 200)...))
+
+	segmenter := NewSegmenter(detector, config, 1000)
+	segmenter.Initialize()
+
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	segments := collect(segmenter.Run(context.Background(), feed(
+		Chunk{Samples: make([]float32, 1000), Time: clock},
+	)))
+	if len(segments) != 2 {
+		t.Fatalf("Expected the utterance to be split in two, got %d segments", len(segments))
+	}
+	first, second := segments[0], segments[1]
+	if len(first.Samples) != 700 || first.Continues {
+		t.Errorf("Expected a first segment of 700 samples up to the pause, got %d", len(first.Samples))
+	}
+	if !first.Time.Equal(clock.Add(-300 * time.Millisecond)) {
+		t.Errorf("Expected the first segment to end at the pause, got %v", first.Time)
+	}
+	if !second.Continues || second.Overlap != 0 || len(second.Samples) != 300 || second.Offset != 700 {
+		t.Errorf("Expected a second segment of 300 samples at offset 700, got %+v", second)
+	}
+}
+
+func TestSegmenter_DiscontinuityAbandonsUtterance(t *testing.T) {
+	detector := vad.NewMockVAD()
+	detector.SetSpeechPattern(speech(true, 4))
+
+	segmenter := NewSegmenter(detector, testVADConfig(), 100)
+	var speaking []bool
+	segmenter.SetSpeechListener(func(s bool) { speaking = append(speaking, s) })
+	segmenter.Initialize()
+
+	segments := collect(segmenter.Run(context.Background(), feed(
+		Chunk{Samples: make([]float32, 4)},
+		Chunk{Offset: 4, Discontinuity: true},
+	)))
+	if len(segments) != 0 {
+		t.Errorf("Expected no segment after a discontinuity, got %+v", segments)
+	}
+	if len(speaking) != 2 || speaking[1] {
+		t.Errorf("Expected speech end on discontinuity, got %v", speaking)
+	}
+}
+
+func TestQueue_DropOldest(t *testing.T) {
+	in := make(chan int, 3)
+	in <- 1
+	in <- 2
+	in <- 3
+	close(in)
+
+	dropped := make(chan int, 3)
+	out := Queue(context.Background(), in, 1, DropOldest(func(item int) { dropped <- item }))
+
+	if first, second := <-dropped, <-dropped; first != 1 || second != 2 {
+		t.Errorf("Expected items 1 and 2 to be dropped, got %d and %d", first, second)
+	}
+	if items := collect(out); len(items) != 1 || items[0] != 3 {
+		t.Errorf("Expected only the newest item, got %v", items)
+	}
+}
+
+func TestQueue_OverflowReworksPending(t *testing.T) {
+	in := make(chan int, 3)
+	in <- 1
+	in <- 2
+	in <- 3
+	close(in)
+
+	// Sum the pending items with the new one
+	overflows := make(chan struct{}, 2)
+	out := Queue(context.Background(), in, 1, func(pending []int, item int) []int {
+		for _, p := range pending {
+			item += p
+		}
+		overflows <- struct{}{}
+		return []int{item}
+	})
+	<-overflows
+	<-overflows
+	if items := collect(out); len(items) != 1 || items[0] != 6 {
+		t.Errorf("Expected the items to be merged, got %v", items)
+	}
+}
+
+func TestConcat(t *testing.T) {
+	end := time.Date(2024, 1, 1, 12, 0, 10, 0, time.UTC)
+	merged := Concat(10,
+		Segment{Samples: make([]float32, 10), Offset: 100, Time: end},
+		Segment{Samples: make([]float32, 20), Offset: 300, Time: end.Add(5 * time.Second)},
+	)
+
+	if len(merged.Samples) != 30 || merged.Offset != 100 {
+		t.Errorf("Expected 30 samples at offset 100, got %d at %d", len(merged.Samples), merged.Offset)
+	}
+	// Still starts where the first segment starts
+	if start := merged.Time.Add(-merged.Duration(10)); !start.Equal(end.Add(-time.Second)) {
+		t.Errorf("Expected the merged segment to start at %v, got %v", end.Add(-time.Second), start)
+	}
+}
+
+func TestTranscriber_Route(t *testing.T) {
+	service := whisper.NewMockWhisperService()
+	service.LoadModel("test.bin")
+	service.SetTranscribeResult(whisper.TranscriptionResult{Text: "Bonjour"})
+
+	in := make(chan Segment, 1)
+	in <- Segment{Samples: make([]float32, 16000)}
+	close(in)
+
+	transcriber := NewTranscriber(service, func() string { return "fr" }, time.Second, 16000)
+	var activity []bool
+	transcriber.SetActivityListener(func(busy bool) { activity = append(activity, busy) })
+	var transcripts []Transcript
+	<-Route(context.Background(), transcriber.Run(context.Background(), in), func(transcript Transcript) {
+		transcripts = append(transcripts, transcript)
+	})
+
+	if len(transcripts) != 1 {
+		t.Fatalf("Expected 1 transcript, got %d", len(transcripts))
+	}
+	if transcripts[0].Err != nil || transcripts[0].Result.Text != "Bonjour" {
+		t.Errorf("Expected 'Bonjour', got %+v", transcripts[0])
+	}
+	if transcripts[0].Segment.Duration(16000) != time.Second {
+		t.Errorf("Expected a 1s segment, got %s", transcripts[0].Segment.Duration(16000))
+	}
+	if len(activity) != 2 || !activity[0] || activity[1] {
+		t.Errorf("Expected transcription start then end, got %v", activity)
+	}
+}
+
+func TestTranscriber_StitchesContinuedSegments(t *testing.T) {
+	service := whisper.NewMockWhisperService()
+	service.LoadModel("test.bin")
+	transcriber := NewTranscriber(service, func() string { return "en" }, 0, 16000)
+
+	service.SetTranscribeResult(whisper.TranscriptionResult{Text: " We walked to the park"})
+	transcriber.transcribe(context.Background(), Segment{Samples: make([]float32, 10)})
+
+	service.SetTranscribeResult(whisper.TranscriptionResult{Text: " the park and back home."})
+	transcript := transcriber.transcribe(context.Background(), Segment{Samples: make([]float32, 10), Continues: true, Overlap: 5})
+	if transcript.Result.Text != "and back home." {
+		t.Errorf("Expected the repeated words to be dropped, got %q", transcript.Result.Text)
+	}
+	if prompt := service.GetPrompt(); prompt != " We walked to the park" {
+		t.Errorf("Expected the previous text as prompt, got %q", prompt)
+	}
+}
+
+func TestTranscriber_StreamsSegments(t *testing.T) {
+	service := whisper.NewMockWhisperService()
+	service.LoadModel("test.bin")
+	service.SetTranscribeResult(whisper.TranscriptionResult{Text: " Un deux", Segments: []whisper.Segment{
+		{Text: " Un", End: 1},
+		{Text: " deux", Start: 1, End: 2},
+	}})
+	transcriber := NewTranscriber(service, func() string { return "fr" }, 0, 16000)
+
+	var decoded []string
+	transcriber.SetSegmentListener(func(segment Segment, s whisper.Segment) {
+		decoded = append(decoded, s.Text)
+	})
+
+	transcript := transcriber.transcribe(context.Background(), Segment{Samples: make([]float32, 10)})
+	if len(decoded) != 2 || decoded[1] != " deux" {
+		t.Errorf("Expected both segments as decoded, got %q", decoded)
+	}
+	if transcript.Result.Text != " Un deux" {
+		t.Errorf("Expected the whole transcript too, got %q", transcript.Result.Text)
+	}
+}
+
+func TestStitch(t *testing.T) {
+	for _, test := range []struct{ previous, next, expected string }{
+		{"We walked to the park", "the park and back home.", "and back home."},
+		{"We walked to the Park,", "park and back", "and back"},
+		{"We walked", "to the park", "to the park"},
+		{"", " to the park ", "to the park"},
+		{"to the park", "to the park", ""},
+	} {
+		if stitched := Stitch(test.previous, test.next); stitched != test.expected {
+			t.Errorf("Stitch(%q, %q): expected %q, got %q", test.previous, test.next, test.expected, stitched)
+		}
+	}
+}
+
+func TestTranscriber_ReportsErrors(t *testing.T) {
+	service := whisper.NewMockWhisperService()
+
+	in := make(chan Segment, 1)
+	in <- Segment{Samples: make([]float32, 10)}
+	close(in)
+
+	transcripts := collect(NewTranscriber(service, func() string { return "fr" }, 0, 16000).Run(context.Background(), in))
+	if len(transcripts) != 1 || transcripts[0].Err == nil {
+		t.Errorf("Expected a transcript carrying the error, got %+v", transcripts)
+	}
+}
+
+// scriptedWhisper returns its results in turn and records the prompts
+type scriptedWhisper struct {
+	*whisper.MockWhisperService
+	results []whisper.TranscriptionResult
+	prompts []string
+}
+
+func (s *scriptedWhisper) Transcribe(ctx context.Context, audio []float32, language string) (whisper.TranscriptionResult, error) {
+	return s.TranscribePrompt(ctx, audio, language, "")
+}
+
+func (s *scriptedWhisper) TranscribePrompt(ctx context.Context, audio []float32, language, prompt string) (whisper.TranscriptionResult, error) {
+	s.prompts = append(s.prompts, prompt)
+	result := s.results[0]
+	s.results = s.results[1:]
+	return result, nil
+}
+
+func TestLongForm_MergesOverlappingWindows(t *testing.T) {
+	service := &scriptedWhisper{MockWhisperService: whisper.NewMockWhisperService(), results: []whisper.TranscriptionResult{
+		// 0s to 10s, the segments past 9s are left to the next window
+		{Language: "en", Segments: []whisper.Segment{
+			{Text: " We walked to the park", Start: 0, End: 4},
+			{Text: " and then we", Start: 4, End: 8.5},
+			{Text: " went ho", Start: 9.2, End: 10},
+		}},
+		// 8s to 18s
+		{Segments: []whisper.Segment{
+			{Text: " we", Start: 0, End: 0.5},
+			{Text: " we went home.", Start: 0.5, End: 4},
+			{Text: " It was late", Start: 4, End: 9.5},
+			{Text: " and", Start: 9.5, End: 10},
+		}},
+		// 16s to 18s
+		{Segments: []whisper.Segment{
+			{Text: " was late", Start: 0, End: 1.5},
+			{Text: " and cold.", Start: 1.5, End: 2},
+		}},
+	}}
+
+	longForm := NewLongForm(service, "auto", 10*time.Second, 2*time.Second, 10)
+	result, err := longForm.Transcribe(context.Background(), audio.NewMockAudioStream(encodeSamples(make([]float32, 180))))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if expected := " We walked to the park and then we went home. It was late and cold."; result.Text != expected {
+		t.Errorf("Expected %q, got %q", expected, result.Text)
+	}
+	if result.Language != "en" || result.Duration != 18 {
+		t.Errorf("Expected 18s of English, got %.1fs of %s", result.Duration, result.Language)
+	}
+	if len(result.Segments) != 5 || result.Segments[2].Start != 8.5 || result.Segments[3].End != 17.5 {
+		t.Errorf("Expected the segments on the stream time, got %+v", result.Segments)
+	}
+	if len(service.prompts) != 3 || service.prompts[1] != " and then we" {
+		t.Errorf("Expected windows to follow the last merged segment, got prompts %q", service.prompts)
+	}
+}
@@ -0,0 +1,301 @@
+package pipeline
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/pkg/vad"
+)
+
+const (
+	// PauseDurationMs is the shortest dip in speech the segmenter may split
+	// a long utterance at
+	PauseDurationMs = 150
+	// OverlapDurationMs is the audio repeated at the start of the next
+	// segment when a long utterance has no pause to split at
+	OverlapDurationMs = 1000
+)
+
+// Segment is an utterance cut out of the stream
+type Segment struct {
+	Samples []float32
+	Offset  int64     // Stream position of the first sample
+	Time    time.Time // Clock time of the chunk the utterance ended in
+	Ready   time.Time // Wall-clock time the segment was cut, for the latency
+
+	// Continues is set when the segment continues the utterance of the
+	// previous one, split at the maximum length
+	Continues bool
+	// Overlap is the number of leading samples repeating the end of the
+	// previous segment
+	Overlap int
+}
+
+// Duration returns the length of the segment at sampleRate
+func (s Segment) Duration(sampleRate int) time.Duration {
+	return time.Duration(len(s.Samples)) * time.Second / time.Duration(sampleRate)
+}
+
+// Concat joins consecutive segments into one utterance starting where the
+// first one starts. The silence between them is left out.
+func Concat(sampleRate int, segments ...Segment) Segment {
+	if len(segments) == 0 {
+		return Segment{}
+	}
+	merged := segments[0]
+	merged.Samples = nil
+	for _, segment := range segments {
+		merged.Samples = append(merged.Samples, segment.Samples...)
+	}
+	// Time is where the utterance ends: move it so that the merged
+	// utterance still starts with the first segment
+	merged.Time = merged.Time.Add(merged.Duration(sampleRate) - segments[0].Duration(sampleRate))
+	return merged
+}
+
+// Segmenter cuts utterances out of the stream with a voice activity
+// detector: a segment ends after the configured silence following speech,
+// or when it reaches the maximum length. A long utterance is split at its
+// last short pause, or cut with some overlap when there is none.
+type Segmenter struct {
+	detector   vad.VoiceActivityDetector
+	config     vad.VADConfig
+	pending    atomic.Pointer[vad.VADConfig]
+	maxSamples int
+	buffer     []float32
+	buffered   atomic.Int64 // Length of buffer, read from other goroutines
+	calibrated atomic.Bool
+
+	// Buffer position in the middle of the last short pause, 0 for none
+	pause int
+	// Whether the buffer continues a split utterance, and how many of its
+	// samples repeat the previous segment
+	continues bool
+	overlap   int
+
+	// Optional listener of speech starts and ends
+	onSpeech func(speaking bool)
+}
+
+// NewSegmenter creates a segmenter cutting segments of at most maxSamples
+func NewSegmenter(detector vad.VoiceActivityDetector, config vad.VADConfig, maxSamples int) *Segmenter {
+	return &Segmenter{
+		detector:   detector,
+		config:     config,
+		maxSamples: maxSamples,
+		buffer:     make([]float32, 0, maxSamples),
+	}
+}
+
+// SetMaxSamples replaces the most samples of a segment, before Run
+func (s *Segmenter) SetMaxSamples(maxSamples int) {
+	s.maxSamples = maxSamples
+	s.buffer = make([]float32, 0, maxSamples)
+}
+
+// SetSpeechListener sets the function called on the segmenter goroutine
+// when the detector starts or stops hearing speech
+func (s *Segmenter) SetSpeechListener(listener func(speaking bool)) {
+	s.onSpeech = listener
+}
+
+// SetConfig replaces the VAD settings. Once running, they are applied
+// between utterances and the noise floor is calibrated again.
+func (s *Segmenter) SetConfig(config vad.VADConfig) {
+	s.pending.Store(&config)
+}
+
+// Initialize initializes the detector, before Run
+func (s *Segmenter) Initialize() error {
+	if pending := s.pending.Swap(nil); pending != nil {
+		s.config = *pending
+	}
+	return s.detector.Initialize(s.config)
+}
+
+// Buffered returns the samples of the utterance being cut and the most the
+// buffer holds, safe to call from any goroutine
+func (s *Segmenter) Buffered() (samples, size int) {
+	return int(s.buffered.Load()), s.maxSamples
+}
+
+// Calibrated reports whether the detector noise floor is calibrated, safe
+// to call from any goroutine
+func (s *Segmenter) Calibrated() bool {
+	return s.calibrated.Load()
+}
+
+// Run segments the chunks of in until it is closed, then flushes the
+// utterance in progress, or until ctx is done
+func (s *Segmenter) Run(ctx context.Context, in <-chan Chunk) <-chan Segment {
+	out := make(chan Segment)
+
+	go func() {
+		defer close(out)
+
+		speaking := false
+		var position int64
+		var clock time.Time
+
+		for chunk := range in {
+			// Reloaded settings never cut an utterance short
+			if !s.detector.IsSpeaking() {
+				s.applyPendingConfig()
+			}
+			silenceSamples := s.config.SilenceDurationMs * s.config.SampleRate / 1000
+			minSpeechSamples := s.config.MinSpeechDurationMs * s.config.SampleRate / 1000
+			pauseSamples := min(PauseDurationMs*s.config.SampleRate/1000, silenceSamples)
+
+			if chunk.Discontinuity {
+				s.reset()
+				if speaking {
+					speaking = false
+					s.notify(false)
+				}
+			}
+
+			clock = chunk.Time
+			for i, sample := range chunk.Samples {
+				position = chunk.Offset + int64(i) + 1
+				s.buffer = append(s.buffer, sample)
+
+				s.detector.ProcessSample(sample)
+				if s.detector.IsSpeaking() != speaking {
+					speaking = !speaking
+					s.notify(speaking)
+				}
+
+				silence := s.detector.GetSilenceDuration()
+				if !s.detector.IsSpeaking() || silence == 0 {
+					continue
+				}
+
+				// Silence after speech ends the utterance
+				if silence >= silenceSamples {
+					if len(s.buffer) >= minSpeechSamples && !s.send(ctx, out, len(s.buffer), position, clock) {
+						return
+					}
+					s.reset()
+				} else if silence >= pauseSamples {
+					s.pause = len(s.buffer) - silence/2
+				}
+			}
+			s.calibrated.Store(s.detector.IsCalibrated())
+
+			// Prevent buffer overflow
+			if len(s.buffer) >= s.maxSamples {
+				if !s.detector.IsSpeaking() {
+					logger.Warn("⚠️  Max buffer reached, processing...")
+					if !s.send(ctx, out, len(s.buffer), position, clock) {
+						return
+					}
+					s.reset()
+				} else if !s.split(ctx, out, position, clock) {
+					return
+				}
+			}
+			s.buffered.Store(int64(len(s.buffer)))
+
+			if ctx.Err() != nil {
+				return
+			}
+		}
+
+		// Input ended: flush the utterance in progress
+		minSpeechSamples := s.config.MinSpeechDurationMs * s.config.SampleRate / 1000
+		if ctx.Err() == nil && s.detector.IsSpeaking() && len(s.buffer) >= minSpeechSamples {
+			s.send(ctx, out, len(s.buffer), position, clock)
+		}
+		s.reset()
+	}()
+
+	return out
+}
+
+// split sends the utterance in progress up to its last short pause, or the
+// whole buffer when there is none in its second half, and keeps the rest
+// for the next segment. Without a pause the next segment starts with the
+// end of this one, so that the words cut in the middle are heard whole.
+func (s *Segmenter) split(ctx context.Context, out chan<- Segment, end int64, clock time.Time) bool {
+	cut, overlap := len(s.buffer), min(OverlapDurationMs*s.config.SampleRate/1000, len(s.buffer)/2)
+	if s.pause >= len(s.buffer)/2 {
+		cut, overlap = s.pause, 0
+		logger.Debugf("✂️  Max buffer reached, splitting at a pause %d samples back", len(s.buffer)-cut)
+	} else {
+		logger.Warn("⚠️  Max buffer reached without a pause, processing...")
+	}
+
+	if !s.send(ctx, out, cut, end, clock) {
+		return false
+	}
+
+	rest := copy(s.buffer, s.buffer[cut-overlap:])
+	s.buffer = s.buffer[:rest]
+	s.pause = 0
+	s.continues = true
+	s.overlap = overlap
+	return true
+}
+
+// send hands a copy of the first n samples of the buffer to out, the buffer
+// ending at stream position end in the chunk read at clock. It returns
+// false when ctx is done first.
+func (s *Segmenter) send(ctx context.Context, out chan<- Segment, n int, end int64, clock time.Time) bool {
+	samples := make([]float32, n)
+	copy(samples, s.buffer)
+
+	segment := Segment{
+		Samples:   samples,
+		Offset:    end - int64(len(s.buffer)),
+		Time:      clock,
+		Ready:     time.Now(),
+		Continues: s.continues,
+		Overlap:   s.overlap,
+	}
+	// The samples after n were heard later than their end
+	if later := len(s.buffer) - n; later > 0 && s.config.SampleRate > 0 {
+		segment.Time = clock.Add(-time.Duration(later) * time.Second / time.Duration(s.config.SampleRate))
+	}
+
+	select {
+	case out <- segment:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// reset drops the buffered audio and resets the detector for the next phrase
+func (s *Segmenter) reset() {
+	s.buffer = s.buffer[:0]
+	s.buffered.Store(0)
+	s.pause = 0
+	s.continues = false
+	s.overlap = 0
+	s.detector.Reset()
+}
+
+// notify reports a speech start or end to the listener
+func (s *Segmenter) notify(speaking bool) {
+	if s.onSpeech != nil {
+		s.onSpeech(speaking)
+	}
+}
+
+// applyPendingConfig re-initializes the detector with the settings given to
+// SetConfig, if any
+func (s *Segmenter) applyPendingConfig() {
+	pending := s.pending.Swap(nil)
+	if pending == nil {
+		return
+	}
+	if err := s.detector.Initialize(*pending); err != nil {
+		logger.WithError(err).Error("❌ Failed to apply VAD settings")
+		return
+	}
+	s.config = *pending
+	logger.Infof("🎚️  VAD settings updated (threshold %.3f, silence %dms)",
+		pending.SilenceThreshold, pending.SilenceDurationMs)
+}
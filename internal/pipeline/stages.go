@@ -0,0 +1,206 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+)
+
+// Frame is a block of raw audio read from the stream
+type Frame struct {
+	Data []byte
+	Time time.Time // Clock time the block was read
+
+	buffer *[]byte // Read buffer of Data, recycled by Decode
+}
+
+// framePool recycles the read buffers of Capture once Decode converted
+// them, sparing an allocation per read
+var framePool sync.Pool
+
+// getFrameBuffer returns a read buffer of size bytes
+func getFrameBuffer(size int) *[]byte {
+	if buffer, ok := framePool.Get().(*[]byte); ok && cap(*buffer) >= size {
+		*buffer = (*buffer)[:size]
+		return buffer
+	}
+	buffer := make([]byte, size)
+	return &buffer
+}
+
+// Chunk is a block of decoded samples and its position in the stream
+type Chunk struct {
+	Samples []float32
+	Offset  int64     // Samples read from the stream before the first one
+	Time    time.Time // Clock time the block was read
+	// Samples before this chunk were dropped: the utterance in progress,
+	// if any, is abandoned
+	Discontinuity bool
+}
+
+// Capture reads frames from stream until it ends or ctx is done. now
+// timestamps the frames, onError receives read errors other than io.EOF.
+func Capture(ctx context.Context, stream audio.AudioStream, frameSize int, now func() time.Time, onError func(error)) <-chan Frame {
+	out := make(chan Frame)
+
+	go func() {
+		defer close(out)
+
+		for ctx.Err() == nil {
+			buffer := getFrameBuffer(frameSize)
+			n, err := stream.Read(*buffer)
+			if err != nil {
+				framePool.Put(buffer)
+				if !errors.Is(err, io.EOF) && onError != nil {
+					onError(err)
+				}
+				return
+			}
+
+			select {
+			case out <- Frame{Data: (*buffer)[:n], Time: now(), buffer: buffer}:
+			case <-ctx.Done():
+				framePool.Put(buffer)
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// Decode converts frames to samples, numbering them from the stream start
+func Decode(ctx context.Context, in <-chan Frame, processor audio.AudioProcessor) <-chan Chunk {
+	out := make(chan Chunk)
+
+	go func() {
+		defer close(out)
+
+		decoder := audio.NewFrameDecoder(processor)
+		var offset int64
+		for frame := range in {
+			samples := decoder.Decode(frame.Data)
+			// The samples are a copy, the read buffer can be reused
+			if frame.buffer != nil {
+				framePool.Put(frame.buffer)
+			}
+			chunk := Chunk{Samples: samples, Offset: offset, Time: frame.Time}
+			offset += int64(len(samples))
+
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// Filter hands every chunk to fn on the stage goroutine, so fn may keep
+// state, and forwards what it returns. Chunks left without samples are
+// dropped unless they mark a discontinuity.
+func Filter(ctx context.Context, in <-chan Chunk, fn func(Chunk) Chunk) <-chan Chunk {
+	out := make(chan Chunk)
+
+	go func() {
+		defer close(out)
+
+		for chunk := range in {
+			chunk = fn(chunk)
+			if len(chunk.Samples) == 0 && !chunk.Discontinuity {
+				continue
+			}
+
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// Overflow decides what a full queue does when item arrives: it is handed
+// the pending items, oldest first, and returns the items to queue instead.
+// Items beyond the queue size wait for room.
+type Overflow[T any] func(pending []T, item T) []T
+
+// DropOldest is the overflow discarding the oldest pending item, handed to
+// onDrop, so that the stages upstream never wait
+func DropOldest[T any](onDrop func(T)) Overflow[T] {
+	return func(pending []T, item T) []T {
+		if len(pending) == 0 {
+			return []T{item}
+		}
+		if onDrop != nil {
+			onDrop(pending[0])
+		}
+		return append(pending[1:], item)
+	}
+}
+
+// Queue buffers up to size items between two stages. A full queue blocks
+// the stages upstream, unless overflow reworks its pending items.
+func Queue[T any](ctx context.Context, in <-chan T, size int, overflow Overflow[T]) <-chan T {
+	if size <= 0 {
+		size = 1
+	}
+	out := make(chan T, size)
+
+	go func() {
+		defer close(out)
+
+		for item := range in {
+			items := []T{item}
+			if overflow != nil {
+				if offer(out, item) {
+					continue
+				}
+				// Queue is full: the pending items are taken back, the
+				// consumer keeps the one it is working on
+				items = overflow(drain(out), item)
+			}
+
+			for _, queued := range items {
+				select {
+				case out <- queued:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// drain takes the pending items of out without blocking
+func drain[T any](out chan T) []T {
+	var items []T
+	for {
+		select {
+		case item := <-out:
+			items = append(items, item)
+		default:
+			return items
+		}
+	}
+}
+
+// offer sends item to out without blocking, reporting whether it was sent
+func offer[T any](out chan T, item T) bool {
+	select {
+	case out <- item:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,43 @@
+package pipeline
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maxStitchWords bounds the words an overlap may repeat, a second of speech
+// is far below it
+const maxStitchWords = 12
+
+// Stitch returns next without its leading words repeating the last words
+// of previous, as when next was transcribed from audio overlapping the end
+// of previous. Words are compared ignoring case and punctuation.
+func Stitch(previous, next string) string {
+	tail := strings.Fields(previous)
+	head := strings.Fields(next)
+
+	longest := min(len(tail), len(head), maxStitchWords)
+	for n := longest; n > 0; n-- {
+		if sameWords(tail[len(tail)-n:], head[:n]) {
+			return strings.Join(head[n:], " ")
+		}
+	}
+	return strings.TrimSpace(next)
+}
+
+// sameWords reports whether a and b hold the same normalized words
+func sameWords(a, b []string) bool {
+	for i := range a {
+		if normalizeWord(a[i]) != normalizeWord(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeWord lowercases word and trims its punctuation
+func normalizeWord(word string) string {
+	return strings.ToLower(strings.TrimFunc(word, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSymbol(r)
+	}))
+}
@@ -0,0 +1,158 @@
+package pipeline
+
+import (
+	"context"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+// Transcript is the transcription of a segment
+type Transcript struct {
+	Segment Segment
+	Result  whisper.TranscriptionResult
+	// Err is set when the transcription failed or timed out
+	Err error
+
+	// Wait is the time the segment spent queued, Elapsed the time Whisper
+	// spent on it
+	Wait    time.Duration
+	Elapsed time.Duration
+}
+
+// Transcriber transcribes segments with Whisper, one at a time
+type Transcriber struct {
+	service    whisper.WhisperService
+	language   func() string
+	timeout    time.Duration
+	sampleRate int
+
+	// Text of the last segment, the context of the segment continuing it
+	previous string
+
+	// Optional listener of transcription starts and ends
+	onActivity func(busy bool)
+	// Optional listener of the decoded whisper segments
+	onSegment func(segment Segment, decoded whisper.Segment)
+}
+
+// NewTranscriber creates a transcriber. language is asked before every
+// segment, so it may change while running; a zero timeout disables it.
+func NewTranscriber(service whisper.WhisperService, language func() string, timeout time.Duration, sampleRate int) *Transcriber {
+	return &Transcriber{
+		service:    service,
+		language:   language,
+		timeout:    timeout,
+		sampleRate: sampleRate,
+	}
+}
+
+// SetActivityListener sets the function called on the transcriber
+// goroutine when a transcription starts and ends
+func (t *Transcriber) SetActivityListener(listener func(busy bool)) {
+	t.onActivity = listener
+}
+
+// SetSegmentListener sets the function called on the transcriber goroutine
+// with every whisper segment of a segment as soon as it is decoded. whisper
+// then decodes a single segment per 30-second window.
+func (t *Transcriber) SetSegmentListener(listener func(segment Segment, decoded whisper.Segment)) {
+	t.onSegment = listener
+}
+
+// Run transcribes the segments of in until it is closed or ctx is done,
+// which also cancels the transcription in progress
+func (t *Transcriber) Run(ctx context.Context, in <-chan Segment) <-chan Transcript {
+	out := make(chan Transcript)
+
+	go func() {
+		defer close(out)
+
+		for segment := range in {
+			if ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case out <- t.transcribe(ctx, segment):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// transcribe transcribes a single segment within the timeout
+func (t *Transcriber) transcribe(ctx context.Context, segment Segment) Transcript {
+	logger.Debugf("📈 Processing %d samples (%.2f seconds)",
+		len(segment.Samples), segment.Duration(t.sampleRate).Seconds())
+	started := time.Now()
+	var wait time.Duration
+	if !segment.Ready.IsZero() {
+		wait = started.Sub(segment.Ready)
+	}
+
+	var cancel context.CancelFunc
+	if t.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	if t.onActivity != nil {
+		t.onActivity(true)
+		defer t.onActivity(false)
+	}
+
+	result, err := t.transcribeSegment(ctx, segment)
+	elapsed := time.Since(started)
+	if err != nil {
+		t.previous = ""
+		return Transcript{Segment: segment, Result: result, Err: err, Wait: wait, Elapsed: elapsed}
+	}
+
+	// The overlap was heard at the end of the previous segment already
+	if segment.Continues && segment.Overlap > 0 {
+		result.Text = Stitch(t.previous, result.Text)
+	}
+	t.previous = result.Text
+	return Transcript{Segment: segment, Result: result, Wait: wait, Elapsed: elapsed}
+}
+
+// transcribeSegment transcribes a segment, following the text of the
+// previous one when it continues it and the service takes a prompt, else
+// streaming the decoded segments to the listener if any
+func (t *Transcriber) transcribeSegment(ctx context.Context, segment Segment) (whisper.TranscriptionResult, error) {
+	if prompter, ok := t.service.(whisper.PromptTranscriber); ok && segment.Continues && t.previous != "" {
+		return prompter.TranscribePrompt(ctx, segment.Samples, t.language(), t.previous)
+	}
+	if t.onSegment != nil {
+		return t.service.TranscribeStream(ctx, segment.Samples, t.language(), func(decoded whisper.Segment) {
+			t.onSegment(segment, decoded)
+		})
+	}
+	return t.service.Transcribe(ctx, segment.Samples, t.language())
+}
+
+// Route hands every transcript of in to handle until in is closed or ctx is
+// done. The returned channel is closed once the last handle call returned.
+func Route(ctx context.Context, in <-chan Transcript, handle func(Transcript)) <-chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for transcript := range in {
+			if ctx.Err() != nil {
+				return
+			}
+			handle(transcript)
+		}
+	}()
+
+	return done
+}
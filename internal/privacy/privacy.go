@@ -0,0 +1,35 @@
+// Package privacy enforces the privacy mode: no audio, transcript or
+// conversation is written to disk. Every feature persisting them checks
+// it before creating a file.
+package privacy
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrDisabled is returned by the features writing to disk in privacy mode
+var ErrDisabled = errors.New("disabled in privacy mode")
+
+var enabled atomic.Bool
+
+// Enable turns the privacy mode on for the rest of the process, it cannot
+// be turned off, not even by a configuration reload
+func Enable() {
+	enabled.Store(true)
+}
+
+// Enabled reports whether the privacy mode is on
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Check returns ErrDisabled in privacy mode, feature names what would
+// have written to disk
+func Check(feature string) error {
+	if enabled.Load() {
+		return fmt.Errorf("%s: %w", feature, ErrDisabled)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package privacy
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	if err := Check("session recording"); err != nil {
+		t.Fatalf("Expected no error before Enable, got %v", err)
+	}
+
+	Enable()
+	if !Enabled() {
+		t.Fatal("Expected the privacy mode to be enabled")
+	}
+	err := Check("session recording")
+	if !errors.Is(err, ErrDisabled) {
+		t.Fatalf("Expected ErrDisabled, got %v", err)
+	}
+	if err.Error() != "session recording: disabled in privacy mode" {
+		t.Errorf("Expected the feature in the error, got %q", err)
+	}
+}
@@ -0,0 +1,194 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/privacy"
+)
+
+// magic starts every session file
+const magic = "NRZS"
+
+// version is the current session file format version
+const version = 1
+
+// maxChunkSize bounds a chunk read from a session file, to reject corrupt files
+const maxChunkSize = 1 << 20
+
+// Meta describes a recorded session
+type Meta struct {
+	Start      time.Time `json:"start"`
+	SampleRate int       `json:"sample_rate"`
+	Source     string    `json:"source"`
+	Language   string    `json:"language,omitempty"`
+}
+
+// Chunk is a block of raw 32-bit float mono audio as read from the capture,
+// with the time it was read at relative to the session start
+type Chunk struct {
+	Offset time.Duration
+	Data   []byte
+}
+
+// Writer writes a session file: a header then timed chunks.
+//
+// Layout, little-endian: "NRZS", uint16 version, uint32 metadata length,
+// JSON metadata, then for each chunk int64 offset in nanoseconds, uint32
+// length and the raw audio bytes.
+type Writer struct {
+	w      *bufio.Writer
+	closer io.Closer
+}
+
+// Create creates a session file at path
+func Create(path string, meta Meta) (*Writer, error) {
+	if err := privacy.Check("session recording"); err != nil {
+		return nil, err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewWriter(file, meta)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	writer.closer = file
+	return writer, nil
+}
+
+// NewWriter writes the session header to w
+func NewWriter(w io.Writer, meta Meta) (*Writer, error) {
+	header, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := &Writer{w: bufio.NewWriter(w)}
+	writer.w.WriteString(magic)
+	binary.Write(writer.w, binary.LittleEndian, uint16(version))
+	binary.Write(writer.w, binary.LittleEndian, uint32(len(header)))
+	if _, err := writer.w.Write(header); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// WriteChunk appends a chunk
+func (w *Writer) WriteChunk(chunk Chunk) error {
+	binary.Write(w.w, binary.LittleEndian, int64(chunk.Offset))
+	binary.Write(w.w, binary.LittleEndian, uint32(len(chunk.Data)))
+	_, err := w.w.Write(chunk.Data)
+	return err
+}
+
+// Close flushes the file
+func (w *Writer) Close() error {
+	err := w.w.Flush()
+	if w.closer != nil {
+		if closeErr := w.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// Reader reads the chunks of a session file in order
+type Reader struct {
+	r      *bufio.Reader
+	closer io.Closer
+	meta   Meta
+}
+
+// Open opens a session file
+func Open(path string) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	reader.closer = file
+	return reader, nil
+}
+
+// NewReader reads the session header from r
+func NewReader(r io.Reader) (*Reader, error) {
+	reader := &Reader{r: bufio.NewReader(r)}
+
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(reader.r, header); err != nil || string(header) != magic {
+		return nil, errors.New("not an nrz-ai session file")
+	}
+
+	var fileVersion uint16
+	var metaLength uint32
+	if err := binary.Read(reader.r, binary.LittleEndian, &fileVersion); err != nil {
+		return nil, err
+	}
+	if fileVersion != version {
+		return nil, fmt.Errorf("unsupported session file version %d", fileVersion)
+	}
+	if err := binary.Read(reader.r, binary.LittleEndian, &metaLength); err != nil {
+		return nil, err
+	}
+	if metaLength > maxChunkSize {
+		return nil, errors.New("corrupt session header")
+	}
+
+	metaData := make([]byte, metaLength)
+	if _, err := io.ReadFull(reader.r, metaData); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(metaData, &reader.meta); err != nil {
+		return nil, fmt.Errorf("corrupt session header: %w", err)
+	}
+	return reader, nil
+}
+
+// Meta returns the session metadata
+func (r *Reader) Meta() Meta {
+	return r.meta
+}
+
+// Next returns the next chunk, or io.EOF at the end of the session
+func (r *Reader) Next() (Chunk, error) {
+	var offset int64
+	var length uint32
+	if err := binary.Read(r.r, binary.LittleEndian, &offset); err != nil {
+		return Chunk{}, err
+	}
+	if err := binary.Read(r.r, binary.LittleEndian, &length); err != nil {
+		return Chunk{}, io.ErrUnexpectedEOF
+	}
+	if length > maxChunkSize {
+		return Chunk{}, errors.New("corrupt session chunk")
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return Chunk{}, io.ErrUnexpectedEOF
+	}
+	return Chunk{Offset: time.Duration(offset), Data: data}, nil
+}
+
+// Close closes the file
+func (r *Reader) Close() error {
+	if r.closer != nil {
+		return r.closer.Close()
+	}
+	return nil
+}
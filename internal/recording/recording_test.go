@@ -0,0 +1,102 @@
+package recording
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+)
+
+func TestWriterReader_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	writer, err := NewWriter(&buf, Meta{Start: start, SampleRate: 16000, Source: "default", Language: "fr"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	writer.WriteChunk(Chunk{Offset: 0, Data: []byte{1, 2, 3, 4}})
+	writer.WriteChunk(Chunk{Offset: 250 * time.Millisecond, Data: []byte{5, 6, 7, 8}})
+	writer.Close()
+
+	reader, err := NewReader(&buf)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if meta := reader.Meta(); !meta.Start.Equal(start) || meta.Source != "default" || meta.Language != "fr" {
+		t.Errorf("Unexpected metadata: %+v", meta)
+	}
+
+	first, _ := reader.Next()
+	second, err := reader.Next()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !bytes.Equal(first.Data, []byte{1, 2, 3, 4}) || second.Offset != 250*time.Millisecond {
+		t.Errorf("Unexpected chunks: %+v %+v", first, second)
+	}
+	if _, err := reader.Next(); err != io.EOF {
+		t.Errorf("Expected EOF, got: %v", err)
+	}
+}
+
+func TestNewReader_RejectsOtherFiles(t *testing.T) {
+	if _, err := NewReader(bytes.NewReader([]byte("RIFF....WAVE"))); err == nil {
+		t.Error("Expected error for a non-session file")
+	}
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.nrz")
+	start := time.Now()
+
+	writer, err := Create(path, Meta{Start: start, SampleRate: 16000})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	source := audio.NewMockAudioStream(bytes.Repeat([]byte{0, 0, 128, 63}, 10))
+	recorder := NewRecordingStream(source, writer)
+	elapsed := time.Duration(0)
+	recorder.now = func() time.Time {
+		elapsed += time.Second
+		return recorder.started.Add(elapsed)
+	}
+
+	buf := make([]byte, 16)
+	for {
+		if _, err := recorder.Read(buf); err != nil {
+			break
+		}
+	}
+	recorder.Close()
+	writer.Close()
+
+	reader, err := Open(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	capture := NewReplayCapture(reader)
+	stream, _ := capture.StartCapture("ignored")
+	defer stream.Close()
+
+	// Smaller reads than the recorded chunks
+	total := 0
+	small := make([]byte, 6)
+	for {
+		n, err := stream.Read(small)
+		total += n
+		if err != nil {
+			break
+		}
+	}
+	if total != 40 {
+		t.Errorf("Expected 40 replayed bytes, got %d", total)
+	}
+	if got := capture.Now().Sub(start); got != 3*time.Second {
+		t.Errorf("Expected mock clock at the last chunk offset (3s), got %s", got)
+	}
+}
@@ -0,0 +1,129 @@
+package recording
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+)
+
+// RecordingStream records everything read from an audio stream
+type RecordingStream struct {
+	stream  audio.AudioStream
+	writer  *Writer
+	started time.Time
+	now     func() time.Time
+}
+
+// NewRecordingStream tees stream into writer, timing chunks from now
+func NewRecordingStream(stream audio.AudioStream, writer *Writer) *RecordingStream {
+	return &RecordingStream{
+		stream:  stream,
+		writer:  writer,
+		started: time.Now(),
+		now:     time.Now,
+	}
+}
+
+// Read reads from the stream and records the chunk
+func (s *RecordingStream) Read(data []byte) (int, error) {
+	n, err := s.stream.Read(data)
+	if n > 0 {
+		chunk := Chunk{Offset: s.now().Sub(s.started), Data: data[:n]}
+		if writeErr := s.writer.WriteChunk(chunk); writeErr != nil && err == nil {
+			err = writeErr
+		}
+	}
+	return n, err
+}
+
+// Close closes the underlying stream, the writer is closed by its owner
+func (s *RecordingStream) Close() error {
+	return s.stream.Close()
+}
+
+// ReplayCapture implements audio.AudioCapture by playing back a session
+// file as fast as it is read, whatever the audio source
+type ReplayCapture struct {
+	reader *Reader
+	clock  *replayClock
+}
+
+// NewReplayCapture replays the session of reader
+func NewReplayCapture(reader *Reader) *ReplayCapture {
+	return &ReplayCapture{
+		reader: reader,
+		clock:  &replayClock{start: reader.Meta().Start},
+	}
+}
+
+// StartCapture returns the session stream
+func (c *ReplayCapture) StartCapture(audioSource string) (audio.AudioStream, error) {
+	return &replayStream{reader: c.reader, clock: c.clock}, nil
+}
+
+// Stop is a no-op, the stream ends with the session
+func (c *ReplayCapture) Stop() error {
+	return nil
+}
+
+// Now is a mock clock returning the recorded time of the last chunk read,
+// so timestamps are the same on every replay
+func (c *ReplayCapture) Now() time.Time {
+	return c.clock.now()
+}
+
+// replayClock follows the recorded chunk offsets
+type replayClock struct {
+	start  time.Time
+	offset time.Duration
+	mutex  sync.Mutex
+}
+
+// now returns the recorded time of the current chunk
+func (c *replayClock) now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.start.Add(c.offset)
+}
+
+// advance moves the clock to a chunk offset
+func (c *replayClock) advance(offset time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.offset = offset
+}
+
+// replayStream reads the chunks of a session, splitting them to fit the
+// read buffers
+type replayStream struct {
+	reader  *Reader
+	clock   *replayClock
+	pending []byte
+}
+
+// Read returns the next recorded audio bytes, io.EOF at the end
+func (s *replayStream) Read(data []byte) (int, error) {
+	for len(s.pending) == 0 {
+		chunk, err := s.reader.Next()
+		if err != nil {
+			if err == io.ErrUnexpectedEOF {
+				// Truncated by a crash during recording, keep what was read
+				return 0, io.EOF
+			}
+			return 0, err
+		}
+		s.clock.advance(chunk.Offset)
+		s.pending = chunk.Data
+	}
+
+	n := copy(data, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// Close closes the session file
+func (s *replayStream) Close() error {
+	return s.reader.Close()
+}
@@ -0,0 +1,118 @@
+// Package redact masks personal data in transcripts before they are shown,
+// stored, sent to integrations or to the AI
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+// Built-in rules
+const (
+	RuleEmail = "email"
+	RulePhone = "phone"
+	RuleCard  = "card"
+)
+
+// DefaultRules are the built-in rules enabled by default
+var DefaultRules = []string{RuleEmail, RulePhone, RuleCard}
+
+// rule replaces the matches of pattern accepted by check with mask
+type rule struct {
+	pattern *regexp.Regexp
+	mask    string
+	check   func(match string) bool
+}
+
+// builtins are applied in this order: card numbers before phone numbers,
+// which would match their digit groups
+var builtins = map[string]rule{
+	RuleEmail: {
+		pattern: regexp.MustCompile(`[\p{L}0-9._%+-]+@[\p{L}0-9-]+(\.[\p{L}0-9-]+)*\.\p{L}{2,}`),
+		mask:    "[email]",
+	},
+	RuleCard: {
+		pattern: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		mask:    "[card]",
+	},
+	RulePhone: {
+		pattern: regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{1,4}\)[ .-]?)?\d{1,4}(?:[ .-]?\d{2,4}){2,5}\b`),
+		mask:    "[phone]",
+		check:   func(match string) bool { return digits(match) >= 8 },
+	},
+}
+
+var builtinOrder = []string{RuleEmail, RuleCard, RulePhone}
+
+// Redactor masks the matches of its rules
+type Redactor struct {
+	rules []rule
+}
+
+// New creates a redactor applying the named built-in rules, then the
+// patterns, regular expressions whose matches become "[redacted]"
+func New(rules []string, patterns []string) (*Redactor, error) {
+	enabled := make(map[string]bool, len(rules))
+	for _, name := range rules {
+		if _, ok := builtins[name]; !ok {
+			return nil, fmt.Errorf("unknown redaction rule '%s', expected one of %s",
+				name, strings.Join(builtinOrder, ", "))
+		}
+		enabled[name] = true
+	}
+
+	r := &Redactor{}
+	for _, name := range builtinOrder {
+		if enabled[name] {
+			r.rules = append(r.rules, builtins[name])
+		}
+	}
+	for _, pattern := range patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern '%s': %w", pattern, err)
+		}
+		r.rules = append(r.rules, rule{pattern: compiled, mask: "[redacted]"})
+	}
+	return r, nil
+}
+
+// Redact returns text with the matches of every rule masked
+func (r *Redactor) Redact(text string) string {
+	for _, rule := range r.rules {
+		text = rule.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if rule.check != nil && !rule.check(match) {
+				return match
+			}
+			return rule.mask
+		})
+	}
+	return text
+}
+
+// Result redacts the text and the segments of a transcription
+func (r *Redactor) Result(result whisper.TranscriptionResult) whisper.TranscriptionResult {
+	result.Text = r.Redact(result.Text)
+	segments := make([]whisper.Segment, len(result.Segments))
+	for i, segment := range result.Segments {
+		segment.Text = r.Redact(segment.Text)
+		segments[i] = segment
+	}
+	result.Segments = segments
+	return result
+}
+
+// digits counts the digits of s
+func digits(s string) int {
+	count := 0
+	for _, c := range s {
+		if unicode.IsDigit(c) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,68 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+func TestRedact_Builtins(t *testing.T) {
+	redactor, err := New(DefaultRules, nil)
+	if err != nil {
+		t.Fatalf("Failed to create redactor: %v", err)
+	}
+
+	tests := map[string]string{
+		"écris à jean.dupont@exemple.fr demain":       "écris à [email] demain",
+		"appelle le 06 12 34 56 78 ce soir":           "appelle le [phone] ce soir",
+		"call +1 (555) 123-4567 now":                  "call [phone] now",
+		"ma carte 4970 1012 3456 7890 expire":         "ma carte [card] expire",
+		"card 4111-1111-1111-1111":                    "card [card]",
+		"rendez-vous à 14 h 30 le 12 juin 2024":       "rendez-vous à 14 h 30 le 12 juin 2024",
+		"il y a 3 pommes et 25 poires dans le panier": "il y a 3 pommes et 25 poires dans le panier",
+	}
+	for text, expected := range tests {
+		if redacted := redactor.Redact(text); redacted != expected {
+			t.Errorf("%q: expected %q, got %q", text, expected, redacted)
+		}
+	}
+}
+
+func TestRedact_Patterns(t *testing.T) {
+	redactor, err := New([]string{RuleEmail}, []string{`(?i)projet \w+`})
+	if err != nil {
+		t.Fatalf("Failed to create redactor: %v", err)
+	}
+
+	if redacted := redactor.Redact("le Projet Mercure démarre, appelle le 06 12 34 56 78"); redacted != "le [redacted] démarre, appelle le 06 12 34 56 78" {
+		t.Errorf("Expected only the pattern masked, got %q", redacted)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	if _, err := New([]string{"address"}, nil); err == nil {
+		t.Error("Expected an error for an unknown rule")
+	}
+	if _, err := New(nil, []string{"("}); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+func TestRedact_Result(t *testing.T) {
+	redactor, _ := New(DefaultRules, nil)
+	original := whisper.TranscriptionResult{
+		Text:     "mon mail est a@b.io",
+		Segments: []whisper.Segment{{Text: "mon mail est a@b.io", Speaker: "Speaker 1"}},
+	}
+
+	result := redactor.Result(original)
+	if result.Text != "mon mail est [email]" || result.Segments[0].Text != "mon mail est [email]" {
+		t.Errorf("Expected the text and segments redacted, got %+v", result)
+	}
+	if result.Segments[0].Speaker != "Speaker 1" {
+		t.Errorf("Expected the segment metadata kept, got %+v", result.Segments[0])
+	}
+	if original.Segments[0].Text != "mon mail est a@b.io" {
+		t.Error("Expected the original segments untouched")
+	}
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+)
+
+// publicPaths answer without credentials so that container health checks
+// work and browsers load the dashboard, the IP allowlist still applies
+var publicPaths = map[string]bool{
+	"/":        true,
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// SetToken requires "Authorization: Bearer <token>" on every request
+// except the public paths: the dashboard page "/", "/healthz" and
+// "/readyz". Browsers cannot set headers on /events and /ws, they may
+// pass ?access_token=<token> instead.
+func (s *Server) SetToken(token string) {
+	s.token = token
+}
+
+// SetAllowedIPs only accepts clients from the listed addresses or CIDR
+// ranges, e.g. 192.168.1.0/24. An empty list accepts any client.
+func (s *Server) SetAllowedIPs(allowed []string) error {
+	prefixes := make([]netip.Prefix, 0, len(allowed))
+	for _, entry := range allowed {
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return fmt.Errorf("invalid allowed IP '%s': %w", entry, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return fmt.Errorf("invalid allowed IP range '%s': %w", entry, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	s.allowedIPs = prefixes
+	return nil
+}
+
+// SetTLS serves HTTPS with the certificate and key files. With a client
+// CA file, clients presenting a certificate signed by it are authenticated
+// without token; without token they must present one (mutual TLS).
+func (s *Server) SetTLS(certFile, keyFile, clientCAFile string) error {
+	if certFile == "" || keyFile == "" {
+		return errors.New("TLS needs both a certificate and a key file")
+	}
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if clientCAFile != "" {
+		pem, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificate found in client CA %s", clientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	s.tlsConfig = config
+	return nil
+}
+
+// TLSEnabled reports whether the server serves HTTPS
+func (s *Server) TLSEnabled() bool {
+	return s.tlsConfig != nil
+}
+
+// Authenticated reports whether clients must authenticate, with a token
+// or a client certificate
+func (s *Server) Authenticated() bool {
+	return s.token != "" || (s.tlsConfig != nil && s.tlsConfig.ClientCAs != nil)
+}
+
+// authorize wraps next with the IP allowlist and the authentication
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.allowedIP(r.RemoteAddr) {
+			writeError(w, http.StatusForbidden, "client address not allowed")
+			return
+		}
+		if s.Authenticated() && !publicPaths[r.URL.Path] && !s.authenticated(r) {
+			if s.token != "" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="nrz-ai"`)
+			}
+			writeError(w, http.StatusUnauthorized, "missing or invalid credentials")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowedIP checks the client address against the allowlist
+func (s *Server) allowedIP(remoteAddr string) bool {
+	if len(s.allowedIPs) == 0 {
+		return true
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range s.allowedIPs {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticated checks the client certificate or the bearer token
+func (s *Server) authenticated(r *http.Request) bool {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return true
+	}
+	if s.token == "" {
+		return false
+	}
+
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found && (r.URL.Path == "/events" || r.URL.Path == "/ws") {
+		token = r.URL.Query().Get("access_token")
+	}
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// serveRequest runs a request from remoteAddr through the server routes
+func serveRequest(srv *Server, remoteAddr, target string, header http.Header) int {
+	request := httptest.NewRequest(http.MethodGet, target, nil)
+	request.RemoteAddr = remoteAddr
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	recorder := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, request)
+	return recorder.Code
+}
+
+func TestServer_Token(t *testing.T) {
+	srv, _ := newTestServer(t)
+	srv.SetToken("s3cret")
+
+	bearer := http.Header{"Authorization": {"Bearer s3cret"}}
+	wrong := http.Header{"Authorization": {"Bearer nope"}}
+	tests := []struct {
+		target string
+		header http.Header
+		status int
+	}{
+		{"/status", nil, http.StatusUnauthorized},
+		{"/status", wrong, http.StatusUnauthorized},
+		{"/status", bearer, http.StatusOK},
+		{"/status?access_token=s3cret", nil, http.StatusUnauthorized},
+		{"/events?access_token=s3cret", nil, http.StatusServiceUnavailable},
+		{"/healthz", nil, http.StatusOK},
+	}
+	for _, test := range tests {
+		if status := serveRequest(srv, "192.0.2.1:1234", test.target, test.header); status != test.status {
+			t.Errorf("%s %v: expected %d, got %d", test.target, test.header, test.status, status)
+		}
+	}
+}
+
+func TestServer_AllowedIPs(t *testing.T) {
+	srv, _ := newTestServer(t)
+	if err := srv.SetAllowedIPs([]string{"127.0.0.1", "192.168.1.0/24", "::1"}); err != nil {
+		t.Fatalf("Failed to set allowed IPs: %v", err)
+	}
+
+	for remoteAddr, status := range map[string]int{
+		"127.0.0.1:5000":    http.StatusOK,
+		"192.168.1.42:5000": http.StatusOK,
+		"[::1]:5000":        http.StatusOK,
+		"192.168.2.1:5000":  http.StatusForbidden,
+		"10.0.0.1:5000":     http.StatusForbidden,
+	} {
+		if got := serveRequest(srv, remoteAddr, "/healthz", nil); got != status {
+			t.Errorf("%s: expected %d, got %d", remoteAddr, status, got)
+		}
+	}
+
+	if err := srv.SetAllowedIPs([]string{"192.168.1.0/33"}); err == nil {
+		t.Error("Expected an error for an invalid range")
+	}
+}
+
+// testPKI is a CA with the files of a server certificate for 127.0.0.1 and
+// a client certificate it signed
+type testPKI struct {
+	caFile, certFile, keyFile string
+	client                    tls.Certificate
+	roots                     *x509.CertPool
+}
+
+func newTestPKI(t *testing.T) testPKI {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "nrz-ai test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, _ := x509.ParseCertificate(caDER)
+
+	issue := func(serial int64, usage x509.ExtKeyUsage) ([]byte, *ecdsa.PrivateKey) {
+		key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "nrz-ai test"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return der, key
+	}
+	write := func(name, kind string, der []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	serverDER, serverKey := issue(2, x509.ExtKeyUsageServerAuth)
+	serverKeyDER, _ := x509.MarshalECPrivateKey(serverKey)
+	clientDER, clientKey := issue(3, x509.ExtKeyUsageClientAuth)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	return testPKI{
+		caFile:   write("ca.pem", "CERTIFICATE", caDER),
+		certFile: write("server.pem", "CERTIFICATE", serverDER),
+		keyFile:  write("server-key.pem", "EC PRIVATE KEY", serverKeyDER),
+		client:   tls.Certificate{Certificate: [][]byte{clientDER}, PrivateKey: clientKey},
+		roots:    roots,
+	}
+}
+
+func TestServer_MutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	srv, _ := newTestServer(t)
+	if err := srv.SetTLS(pki.certFile, pki.keyFile, pki.caFile); err != nil {
+		t.Fatalf("Failed to set up TLS: %v", err)
+	}
+	if !srv.TLSEnabled() || !srv.Authenticated() {
+		t.Fatal("Expected TLS with client authentication")
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Serve(ctx, listener)
+
+	get := func(certificates []tls.Certificate) int {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      pki.roots,
+			Certificates: certificates,
+		}}}
+		response, err := client.Get("https://" + listener.Addr().String() + "/status")
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		response.Body.Close()
+		return response.StatusCode
+	}
+
+	if status := get([]tls.Certificate{pki.client}); status != http.StatusOK {
+		t.Errorf("Expected 200 with a client certificate, got %d", status)
+	}
+	if status := get(nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without client certificate, got %d", status)
+	}
+}
+
+func TestServer_SetTLSErrors(t *testing.T) {
+	srv, _ := newTestServer(t)
+	if err := srv.SetTLS("", "key.pem", ""); err == nil {
+		t.Error("Expected an error without certificate")
+	}
+	if err := srv.SetTLS("/nonexistent/cert.pem", "/nonexistent/key.pem", ""); err == nil {
+		t.Error("Expected an error for missing files")
+	}
+}
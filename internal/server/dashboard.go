@@ -0,0 +1,26 @@
+package server
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// dashboardPage is the web dashboard: live captions, voice activity and
+// assistant state from /ws, the conversation from /history and a chat box
+// posting to /chat
+//
+//go:embed dashboard.html
+var dashboardPage []byte
+
+// dashboardPolicy only lets the page talk to the server it comes from
+const dashboardPolicy = "default-src 'none'; script-src 'unsafe-inline'; style-src 'unsafe-inline'; " +
+	"connect-src 'self'; frame-ancestors 'none'"
+
+// handleDashboard serves the web dashboard. The page holds no data, it asks
+// for the token when the API needs one.
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", dashboardPolicy)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(dashboardPage)
+}
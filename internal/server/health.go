@@ -0,0 +1,91 @@
+package server
+
+import (
+	"net/http"
+	"time"
+)
+
+// modelChecker is implemented by Whisper services reporting their model state
+type modelChecker interface {
+	IsLoaded() bool
+}
+
+// healthResponse is the body returned by GET /healthz
+type healthResponse struct {
+	Status string `json:"status"`
+	Uptime string `json:"uptime"`
+}
+
+// componentStatus is the readiness of one component in GET /readyz
+type componentStatus struct {
+	Ready  bool   `json:"ready"`
+	Detail string `json:"detail"`
+}
+
+// readyResponse is the body returned by GET /readyz
+type readyResponse struct {
+	Status     string                     `json:"status"` // ready or not_ready
+	Components map[string]componentStatus `json:"components"`
+}
+
+// SetCapturing reports the live audio stream in /readyz, capturing tells
+// whether audio is still flowing
+func (s *Server) SetCapturing(capturing func() bool) {
+	s.capturing = capturing
+}
+
+// handleHealth answers as long as the process serves requests
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, healthResponse{
+		Status: "ok",
+		Uptime: time.Since(s.started).Round(time.Second).String(),
+	})
+}
+
+// handleReady reports whether every component is ready, replying 503
+// with the failing ones otherwise
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	components := map[string]componentStatus{
+		"whisper": s.whisperStatus(),
+	}
+	if s.capturing != nil {
+		components["audio"] = s.audioStatus()
+	}
+	if s.aiService != nil {
+		components["ai"] = s.aiStatus()
+	}
+
+	response := readyResponse{Status: "ready", Components: components}
+	status := http.StatusOK
+	for _, component := range components {
+		if !component.Ready {
+			response.Status = "not_ready"
+			status = http.StatusServiceUnavailable
+		}
+	}
+	writeJSON(w, status, response)
+}
+
+// whisperStatus checks the Whisper model is loaded
+func (s *Server) whisperStatus() componentStatus {
+	if checker, ok := s.whisperService.(modelChecker); ok && !checker.IsLoaded() {
+		return componentStatus{Detail: "model not loaded"}
+	}
+	return componentStatus{Ready: true, Detail: s.model}
+}
+
+// audioStatus checks the live audio stream is flowing
+func (s *Server) audioStatus() componentStatus {
+	if !s.capturing() {
+		return componentStatus{Detail: "no audio captured"}
+	}
+	return componentStatus{Ready: true, Detail: "capturing"}
+}
+
+// aiStatus checks the AI backend is reachable
+func (s *Server) aiStatus() componentStatus {
+	if !s.aiService.IsAvailable() {
+		return componentStatus{Detail: "backend unreachable"}
+	}
+	return componentStatus{Ready: true, Detail: "reachable"}
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+)
+
+// probe sends a GET request to path and decodes the readiness body
+func probe(t *testing.T, srv *Server, path string) (int, readyResponse) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var response readyResponse
+	json.Unmarshal(recorder.Body.Bytes(), &response)
+	return recorder.Code, response
+}
+
+func TestServer_Healthz(t *testing.T) {
+	srv, service := newTestServer(t)
+	service.Close()
+
+	if code, _ := probe(t, srv, "/healthz"); code != http.StatusOK {
+		t.Errorf("Expected 200 while the process runs, got %d", code)
+	}
+}
+
+func TestServer_Readyz(t *testing.T) {
+	srv, service := newTestServer(t)
+	aiService := ai.NewMockAIService()
+	srv.SetAI(aiService, ai.NewMockConversationManager())
+	capturing := true
+	srv.SetCapturing(func() bool { return capturing })
+
+	code, response := probe(t, srv, "/readyz")
+	if code != http.StatusOK || response.Status != "ready" || len(response.Components) != 3 {
+		t.Fatalf("Expected every component ready, got %d %+v", code, response)
+	}
+
+	capturing = false
+	aiService.SetAvailable(false)
+	code, response = probe(t, srv, "/readyz")
+	if code != http.StatusServiceUnavailable || response.Status != "not_ready" {
+		t.Fatalf("Expected 503, got %d %+v", code, response)
+	}
+	if response.Components["audio"].Ready || response.Components["ai"].Ready || !response.Components["whisper"].Ready {
+		t.Errorf("Expected audio and AI to be reported, got %+v", response.Components)
+	}
+
+	service.Close()
+	if _, response = probe(t, srv, "/readyz"); response.Components["whisper"].Detail != "model not loaded" {
+		t.Errorf("Expected the unloaded model to be reported, got %+v", response.Components["whisper"])
+	}
+}
+
+func TestServer_ReadyzWithoutLivePipeline(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	code, response := probe(t, srv, "/readyz")
+	if code != http.StatusOK || len(response.Components) != 1 {
+		t.Errorf("Expected only Whisper to be checked, got %d %+v", code, response)
+	}
+}
@@ -0,0 +1,502 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/blocklist"
+	"github.com/nerzhul/nrz-ai/internal/privacy"
+	"github.com/nerzhul/nrz-ai/internal/redact"
+	"github.com/nerzhul/nrz-ai/internal/transcript"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+const (
+	maxUploadBytes = 200 << 20 // 200 MiB
+	historySize    = 100
+	subscriberSize = 32
+)
+
+// Answerer answers the messages of /chat, e.g. the live assistant
+type Answerer interface {
+	// Answer handles a message described by meta and returns the reply
+	Answer(text string, meta *ai.Metadata) (string, error)
+}
+
+// Server exposes the transcription and AI services over HTTP
+type Server struct {
+	whisperService whisper.WhisperService
+	decoder        audio.FileDecoder
+	aiService      ai.AIService
+	conversation   ai.ConversationManager
+	answerer       Answerer
+	chatMutex      sync.Mutex // Serializes the exchanges without an Answerer
+	language       string
+	model          string
+	live           bool
+	activate       func()
+	capturing      func() bool
+	redactor       *redact.Redactor
+	blocklist      *blocklist.Blocklist
+	allowedOrigins []string
+	started        time.Time
+
+	// Access control, see auth.go
+	token      string
+	allowedIPs []netip.Prefix
+	tlsConfig  *tls.Config
+
+	history     []output.Event
+	subscribers map[chan output.Event]struct{}
+	mutex       sync.Mutex
+}
+
+// NewServer creates an API server transcribing uploads with service
+func NewServer(service whisper.WhisperService, decoder audio.FileDecoder, language, model string) *Server {
+	return &Server{
+		whisperService: service,
+		decoder:        decoder,
+		language:       language,
+		model:          model,
+		started:        time.Now(),
+		subscribers:    make(map[chan output.Event]struct{}),
+	}
+}
+
+// SetAI enables the /chat endpoint
+func (s *Server) SetAI(service ai.AIService, conversation ai.ConversationManager) {
+	s.aiService = service
+	s.conversation = conversation
+}
+
+// SetAnswerer sends the /chat messages to answerer instead of the AI
+// service, so that with the live pipeline they take turns with the spoken
+// questions and go through its intents and events
+func (s *Server) SetAnswerer(answerer Answerer) {
+	s.answerer = answerer
+}
+
+// SetLive marks the microphone pipeline as running, enabling /events and /ws
+func (s *Server) SetLive(live bool) {
+	s.live = live
+}
+
+// SetActivation enables POST /activate, calling activate to start listening
+// as if the wake word had been said
+func (s *Server) SetActivation(activate func()) {
+	s.activate = activate
+}
+
+// SetAllowedOrigins lists the browser origins allowed to open /ws,
+// "*" allows any origin
+func (s *Server) SetAllowedOrigins(origins []string) {
+	s.allowedOrigins = origins
+}
+
+// SetRedactor masks personal data in the transcripts of uploads
+func (s *Server) SetRedactor(redactor *redact.Redactor) {
+	s.redactor = redactor
+}
+
+// SetBlocklist drops the made up phrases of list from the transcripts of
+// uploads
+func (s *Server) SetBlocklist(list *blocklist.Blocklist) {
+	s.blocklist = list
+}
+
+// Handler returns the HTTP routes
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /transcribe", s.handleTranscribe)
+	mux.HandleFunc("POST /chat", s.handleChat)
+	mux.HandleFunc("POST /activate", s.handleActivate)
+	mux.HandleFunc("GET /status", s.handleStatus)
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /readyz", s.handleReady)
+	mux.HandleFunc("GET /history", s.handleHistory)
+	mux.HandleFunc("GET /events", s.handleEvents)
+	mux.HandleFunc("GET /ws", s.handleWebSocket)
+	mux.HandleFunc("GET /{$}", s.handleDashboard)
+	return s.authorize(mux)
+}
+
+// ListenAndServe serves the API on addr until ctx is done
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves the API on an open listener until ctx is done, over TLS
+// when SetTLS was called
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	if s.tlsConfig != nil {
+		listener = tls.NewListener(listener, s.tlsConfig)
+	}
+	httpServer := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Emit records transcript events in the history and streams every event to
+// /events subscribers. Slow subscribers miss events instead of blocking the pipeline.
+func (s *Server) Emit(event output.Event) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if event.Type == output.EventTranscript {
+		s.addHistoryLocked(event)
+	}
+
+	for subscriber := range s.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+		}
+	}
+	return nil
+}
+
+// addHistoryLocked appends to the bounded transcript history, caller holds the mutex
+func (s *Server) addHistoryLocked(event output.Event) {
+	s.history = append(s.history, event)
+	if len(s.history) > historySize {
+		s.history = s.history[len(s.history)-historySize:]
+	}
+}
+
+// handleTranscribe transcribes an uploaded audio file, sent either as the
+// "file" field of a multipart form or as the raw request body
+func (s *Server) handleTranscribe(w http.ResponseWriter, r *http.Request) {
+	// Uploads go through a temporary file for ffmpeg
+	if err := privacy.Check("audio uploads"); err != nil {
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBytes)
+
+	format := transcript.FormatJSON
+	if name := r.URL.Query().Get("format"); name != "" {
+		parsed, err := transcript.ParseFormat(name)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		format = parsed
+	}
+
+	language := s.language
+	if lang := r.URL.Query().Get("language"); lang != "" {
+		language = lang
+	}
+
+	path, err := s.saveUpload(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer os.Remove(path)
+
+	samples, err := s.decoder.DecodeFile(path)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	result, err := s.whisperService.Transcribe(r.Context(), samples, language)
+	if err != nil {
+		writeError(w, errorStatus(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+	if s.blocklist != nil {
+		result, _ = s.blocklist.Result(result)
+	}
+	if s.redactor != nil {
+		result = s.redactor.Result(result)
+	}
+
+	s.mutex.Lock()
+	s.addHistoryLocked(output.Event{
+		Type:     output.EventTranscript,
+		Time:     time.Now(),
+		Text:     strings.TrimSpace(result.Text),
+		Language: result.Language,
+	})
+	s.mutex.Unlock()
+
+	w.Header().Set("Content-Type", contentType(format))
+	if err := transcript.Write(w, result, format); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// errorStatus returns the HTTP status of a service error, fallback when
+// its class is unknown
+func errorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, whisper.ErrTimeout), errors.Is(err, ai.ErrTimeout):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, whisper.ErrModelNotLoaded), errors.Is(err, ai.ErrAIUnavailable), errors.Is(err, ai.ErrModelNotLoaded):
+		return http.StatusServiceUnavailable
+	}
+	return fallback
+}
+
+// saveUpload writes the uploaded audio to a temporary file for ffmpeg
+func (s *Server) saveUpload(r *http.Request) (string, error) {
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return "", fmt.Errorf("missing 'file' form field: %w", err)
+		}
+		defer file.Close()
+		body = file
+	}
+
+	tmp, err := os.CreateTemp("", "nrz-ai-upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer tmp.Close()
+
+	written, err := io.Copy(tmp, body)
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to read upload: %w", err)
+	}
+	if written == 0 {
+		os.Remove(tmp.Name())
+		return "", errors.New("empty upload")
+	}
+	return tmp.Name(), nil
+}
+
+// chatRequest is the body of POST /chat
+type chatRequest struct {
+	Message string `json:"message"`
+}
+
+// chatResponse is the body returned by POST /chat
+type chatResponse struct {
+	Response string `json:"response"`
+}
+
+// handleChat sends a text message to the AI, sharing the conversation with the live pipeline
+func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
+	if s.aiService == nil {
+		writeError(w, http.StatusServiceUnavailable, "AI is disabled")
+		return
+	}
+
+	var request chatRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || strings.TrimSpace(request.Message) == "" {
+		writeError(w, http.StatusBadRequest, "expected JSON body with a non-empty 'message'")
+		return
+	}
+
+	meta := ai.NewMetadata(time.Now(), ai.SourceAPI)
+	var answer string
+	var err error
+	if s.answerer != nil {
+		answer, err = s.answerer.Answer(request.Message, meta)
+	} else {
+		answer, err = s.chat(request.Message, meta)
+	}
+	if err != nil {
+		writeError(w, errorStatus(err, http.StatusBadGateway), err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, chatResponse{Response: answer})
+}
+
+// chat sends text to the AI in the shared conversation and returns the
+// answer, one exchange at a time
+func (s *Server) chat(text string, meta *ai.Metadata) (string, error) {
+	s.chatMutex.Lock()
+	defer s.chatMutex.Unlock()
+
+	s.conversation.AddMessage(ai.Message{Role: "user", Content: text, Meta: meta})
+	response, err := s.aiService.Chat(ai.ChatRequest{Messages: s.conversation.GetMessages()})
+	if err != nil {
+		return "", err
+	}
+	if response.Error != "" {
+		return "", errors.New(response.Error)
+	}
+	response.Message.Meta = &ai.Metadata{Time: time.Now(), Model: response.Model}
+	s.conversation.AddMessage(response.Message)
+	return strings.TrimSpace(response.Message.Content), nil
+}
+
+// handleActivate starts listening without the wake word, replying with the
+// status
+func (s *Server) handleActivate(w http.ResponseWriter, r *http.Request) {
+	if s.activate == nil {
+		writeError(w, http.StatusServiceUnavailable, "activation needs the live pipeline with a wake word")
+		return
+	}
+	s.activate()
+	s.handleStatus(w, r)
+}
+
+// statusResponse is the body returned by GET /status
+type statusResponse struct {
+	Status    string `json:"status"`
+	Model     string `json:"model"`
+	Language  string `json:"language"`
+	AIEnabled bool   `json:"ai_enabled"`
+	Live      bool   `json:"live"`
+	Uptime    string `json:"uptime"`
+}
+
+// handleStatus reports the server configuration
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, statusResponse{
+		Status:    "ok",
+		Model:     s.model,
+		Language:  s.language,
+		AIEnabled: s.aiService != nil,
+		Live:      s.live,
+		Uptime:    time.Since(s.started).Round(time.Second).String(),
+	})
+}
+
+// historyResponse is the body returned by GET /history
+type historyResponse struct {
+	Transcripts  []output.Event `json:"transcripts"`
+	Conversation []ai.Message   `json:"conversation"`
+}
+
+// handleHistory returns recent transcripts and the AI conversation
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	transcripts := append([]output.Event{}, s.history...)
+	s.mutex.Unlock()
+
+	conversation := []ai.Message{}
+	if s.conversation != nil {
+		conversation = s.conversation.GetMessages()
+	}
+
+	writeJSON(w, http.StatusOK, historyResponse{
+		Transcripts:  transcripts,
+		Conversation: conversation,
+	})
+}
+
+// handleEvents streams live pipeline events as Server-Sent Events
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if !s.live {
+		writeError(w, http.StatusServiceUnavailable, "live pipeline is not running, start with 'serve --live'")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+
+	events := s.subscribe()
+	defer s.unsubscribe(events)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// subscribe registers a new event stream
+func (s *Server) subscribe() chan output.Event {
+	events := make(chan output.Event, subscriberSize)
+	s.mutex.Lock()
+	s.subscribers[events] = struct{}{}
+	s.mutex.Unlock()
+	return events
+}
+
+// unsubscribe removes an event stream
+func (s *Server) unsubscribe(events chan output.Event) {
+	s.mutex.Lock()
+	delete(s.subscribers, events)
+	s.mutex.Unlock()
+}
+
+// contentType returns the MIME type of a transcript format
+func contentType(format transcript.Format) string {
+	switch format {
+	case transcript.FormatJSON:
+		return "application/json"
+	case transcript.FormatVTT:
+		return "text/vtt; charset=utf-8"
+	case transcript.FormatSRT:
+		return "application/x-subrip; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// errorResponse is the body of every error reply
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeError writes a JSON error reply
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+// writeJSON writes a JSON reply
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,399 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/assistant"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+	"github.com/nerzhul/nrz-ai/pkg/vad"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+func newTestServer(t *testing.T) (*Server, *whisper.MockWhisperService) {
+	t.Helper()
+
+	service := whisper.NewMockWhisperService()
+	service.LoadModel("test.bin")
+	service.SetTranscribeResult(whisper.TranscriptionResult{
+		Text:     " Bonjour.",
+		Language: "fr",
+		Segments: []whisper.Segment{{Text: " Bonjour.", Start: 0, End: 1.2}},
+	})
+
+	decoder := audio.NewMockFileDecoder()
+	decoder.SetDefaultSamples(make([]float32, 16000))
+
+	return NewServer(service, decoder, "fr", "test.bin"), service
+}
+
+func TestServer_TranscribeRawBody(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	request := httptest.NewRequest(http.MethodPost, "/transcribe?format=srt", strings.NewReader("RIFF...."))
+	recorder := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if !strings.Contains(recorder.Body.String(), "00:00:00,000 --> 00:00:01,200\nBonjour.") {
+		t.Errorf("Expected SRT cue, got %q", recorder.Body.String())
+	}
+}
+
+func TestServer_TranscribeMultipart(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "hello.wav")
+	part.Write([]byte("RIFF...."))
+	form.Close()
+
+	request := httptest.NewRequest(http.MethodPost, "/transcribe", &body)
+	request.Header.Set("Content-Type", form.FormDataContentType())
+	recorder := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	var doc struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &doc); err != nil || doc.Text != "Bonjour." {
+		t.Errorf("Expected JSON transcript, got %q (%v)", recorder.Body.String(), err)
+	}
+
+	// Uploads show up in the history
+	recorder = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/history", nil))
+	var history historyResponse
+	json.Unmarshal(recorder.Body.Bytes(), &history)
+	if len(history.Transcripts) != 1 || history.Transcripts[0].Text != "Bonjour." {
+		t.Errorf("Expected upload in history, got %+v", history.Transcripts)
+	}
+}
+
+func TestServer_TranscribeEmptyUpload(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	recorder := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/transcribe", nil))
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for empty upload, got %d", recorder.Code)
+	}
+}
+
+func TestServer_Chat(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	recorder := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/chat", strings.NewReader(`{"message":"Salut"}`)))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 without AI, got %d", recorder.Code)
+	}
+
+	service := ai.NewMockAIService()
+	service.SetResponses([]ai.ChatResponse{{Message: ai.Message{Role: "assistant", Content: "Bonjour !"}, Done: true}})
+	conversation := ai.NewConversation(10)
+	srv.SetAI(service, conversation)
+
+	recorder = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/chat", strings.NewReader(`{"message":"Salut"}`)))
+
+	var response chatResponse
+	json.Unmarshal(recorder.Body.Bytes(), &response)
+	if recorder.Code != http.StatusOK || response.Response != "Bonjour !" {
+		t.Errorf("Expected AI answer, got %d %q", recorder.Code, recorder.Body.String())
+	}
+	if len(conversation.GetMessages()) < 2 {
+		t.Errorf("Expected exchange in conversation, got %d messages", len(conversation.GetMessages()))
+	}
+
+	// The history tells where the messages came from
+	recorder = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/history", nil))
+	var history historyResponse
+	json.Unmarshal(recorder.Body.Bytes(), &history)
+	if len(history.Conversation) != 2 || history.Conversation[0].Meta == nil || history.Conversation[0].Meta.Source != ai.SourceAPI {
+		t.Fatalf("Expected the API source in the history, got %s", recorder.Body.String())
+	}
+	if meta := history.Conversation[1].Meta; meta == nil || meta.Time.IsZero() {
+		t.Errorf("Expected the time of the answer in the history, got %s", recorder.Body.String())
+	}
+}
+
+func TestServer_ChatTakesTurnsWithVoice(t *testing.T) {
+	const questions = 8
+
+	// The mocks are not safe for concurrent use, the race detector catches
+	// exchanges that are not serialized
+	service := whisper.NewMockWhisperService()
+	service.SetTranscribeResult(whisper.TranscriptionResult{
+		Text:     "Quelle heure est-il ?",
+		Segments: []whisper.Segment{{Text: "Quelle heure est-il ?", Start: 0, End: 1}},
+	})
+	detector := vad.NewMockVAD()
+	detector.SetSpeechPattern([]bool{true, true, true, true, true, false, false, false, false})
+	var audioData []byte
+	for range 9 * questions {
+		audioData = binary.LittleEndian.AppendUint32(audioData, math.Float32bits(0.1))
+	}
+	aiService := ai.NewMockAIService()
+	conversation := ai.NewMockConversationManager()
+	processor, err := assistant.New(assistant.Options{
+		Whisper:      service,
+		Capture:      audio.NewMockAudioCapture(audio.NewMockAudioStream(audioData)),
+		Detector:     detector,
+		AI:           aiService,
+		Conversation: conversation,
+		Quiet:        true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer processor.Close()
+	processor.SetVADConfig(vad.VADConfig{SampleRate: 1000, SilenceDurationMs: 3, MinSpeechDurationMs: 2})
+	if err := processor.Initialize("test.bin", "default", "fr"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	srv, _ := newTestServer(t)
+	srv.SetAI(aiService, conversation)
+	srv.SetAnswerer(processor)
+	handler := srv.Handler()
+
+	var wg sync.WaitGroup
+	wg.Add(1 + questions)
+	go func() {
+		defer wg.Done()
+		if err := processor.ProcessStream("default"); err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+	}()
+	for i := range questions {
+		go func() {
+			defer wg.Done()
+			body := fmt.Sprintf(`{"message":"Question %d"}`, i)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/chat", strings.NewReader(body)))
+			if recorder.Code != http.StatusOK {
+				t.Errorf("Expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+			}
+		}()
+	}
+	wg.Wait()
+
+	messages := conversation.GetMessages()
+	if len(messages) <= 2*questions || len(messages)%2 != 0 {
+		t.Fatalf("Expected the typed and spoken exchanges, got %d messages", len(messages))
+	}
+	for i := 0; i < len(messages); i += 2 {
+		question, answer := messages[i], messages[i+1]
+		if question.Role != "user" || answer.Content != "Mock response for: "+question.Content {
+			t.Errorf("Expected exchange %d to be a question and its answer, got %q then %q", i/2, question.Content, answer.Content)
+		}
+	}
+}
+
+func TestServer_ErrorClasses(t *testing.T) {
+	srv, service := newTestServer(t)
+	aiService := ai.NewMockAIService()
+	srv.SetAI(aiService, ai.NewConversation(10))
+
+	for _, test := range []struct {
+		err    error
+		status int
+	}{
+		{fmt.Errorf("%w: connection refused", ai.ErrAIUnavailable), http.StatusServiceUnavailable},
+		{fmt.Errorf("%w: no answer", ai.ErrTimeout), http.StatusGatewayTimeout},
+		{fmt.Errorf("unexpected answer"), http.StatusBadGateway},
+	} {
+		aiService.SetChatError(test.err)
+		recorder := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/chat", strings.NewReader(`{"message":"Salut"}`)))
+		if recorder.Code != test.status {
+			t.Errorf("Expected %d for '%v', got %d", test.status, test.err, recorder.Code)
+		}
+	}
+
+	service.SetTranscribeError(fmt.Errorf("%w: context deadline exceeded", whisper.ErrTimeout))
+	recorder := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/transcribe", strings.NewReader("audio")))
+	if recorder.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected 504 for a transcription timeout, got %d", recorder.Code)
+	}
+}
+
+func TestServer_Status(t *testing.T) {
+	srv, _ := newTestServer(t)
+	srv.SetLive(true)
+
+	recorder := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	var status statusResponse
+	json.Unmarshal(recorder.Body.Bytes(), &status)
+	if status.Status != "ok" || status.Model != "test.bin" || !status.Live || status.AIEnabled {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+}
+
+func TestServer_Activate(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	recorder := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/activate", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 without activation, got %d", recorder.Code)
+	}
+
+	activations := 0
+	srv.SetActivation(func() { activations++ })
+	recorder = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/activate", nil))
+	if recorder.Code != http.StatusOK || activations != 1 {
+		t.Errorf("Expected one activation, got %d (status %d)", activations, recorder.Code)
+	}
+}
+
+func TestServer_EventsRequireLivePipeline(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	recorder := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/events", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 when not live, got %d", recorder.Code)
+	}
+}
+
+func TestServer_EventsStream(t *testing.T) {
+	srv, _ := newTestServer(t)
+	srv.SetLive(true)
+
+	httpServer := httptest.NewServer(srv.Handler())
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, httpServer.URL+"/events", nil)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer response.Body.Close()
+
+	// Headers are flushed once the subscription is registered
+	srv.Emit(output.Event{Type: output.EventTranscript, Text: "En direct"})
+
+	reader := bufio.NewReader(response.Body)
+	line, _ := reader.ReadString('\n')
+	if line != "event: transcript\n" {
+		t.Fatalf("Expected transcript event, got %q", line)
+	}
+	data, _ := reader.ReadString('\n')
+	if !strings.Contains(data, `"text":"En direct"`) {
+		t.Errorf("Expected event data, got %q", data)
+	}
+}
+
+func TestServer_WebSocketStream(t *testing.T) {
+	srv, _ := newTestServer(t)
+	srv.SetLive(true)
+
+	httpServer := httptest.NewServer(srv.Handler())
+	defer httpServer.Close()
+
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/ws"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer conn.Close()
+
+	// The subscription is registered right after the upgrade
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		srv.mutex.Lock()
+		subscribed := len(srv.subscribers) > 0
+		srv.mutex.Unlock()
+		if subscribed || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	srv.Emit(output.Event{Type: output.EventAIToken, Text: "Bon"})
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var event output.Event
+	if err := conn.ReadJSON(&event); err != nil {
+		t.Fatalf("Expected event, got: %v", err)
+	}
+	if event.Type != output.EventAIToken || event.Text != "Bon" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+}
+
+func TestServer_WebSocketRejectsForeignOrigin(t *testing.T) {
+	srv, _ := newTestServer(t)
+	srv.SetLive(true)
+
+	httpServer := httptest.NewServer(srv.Handler())
+	defer httpServer.Close()
+
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/ws"
+	header := http.Header{"Origin": []string{"https://evil.example"}}
+	if _, _, err := websocket.DefaultDialer.Dial(url, header); err == nil {
+		t.Error("Expected foreign origin to be rejected")
+	}
+
+	srv.SetAllowedOrigins([]string{"https://evil.example"})
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	if err != nil {
+		t.Fatalf("Expected allowed origin to connect, got: %v", err)
+	}
+	conn.Close()
+}
+
+func TestServer_Dashboard(t *testing.T) {
+	srv, _ := newTestServer(t)
+	srv.SetToken("s3cret")
+
+	recorder := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected the dashboard without the token, got %d", recorder.Code)
+	}
+	if recorder.Header().Get("Content-Type") != "text/html; charset=utf-8" || !strings.Contains(recorder.Body.String(), `new WebSocket(`) {
+		t.Errorf("Expected the dashboard page, got %q", recorder.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(recorder.Header().Get("Content-Security-Policy"), "frame-ancestors 'none'") {
+		t.Errorf("Expected a content security policy, got %q", recorder.Header().Get("Content-Security-Policy"))
+	}
+
+	recorder = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("Expected only the dashboard page to be public, got %d", recorder.Code)
+	}
+}
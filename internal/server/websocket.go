@@ -0,0 +1,73 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const wsWriteTimeout = 5 * time.Second
+
+// upgrader returns the WebSocket upgrader. Browsers are only accepted from the
+// server's own origin or from the configured allowed origins.
+func (s *Server) upgrader() websocket.Upgrader {
+	return websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				return true
+			}
+			for _, allowed := range s.allowedOrigins {
+				if allowed == "*" || allowed == origin {
+					return true
+				}
+			}
+			return origin == "http://"+r.Host || origin == "https://"+r.Host
+		},
+	}
+}
+
+// handleWebSocket streams live pipeline events as JSON text messages
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	if !s.live {
+		writeError(w, http.StatusServiceUnavailable, "live pipeline is not running, start with 'serve --live'")
+		return
+	}
+
+	upgrader := s.upgrader()
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade already replied to the client
+		return
+	}
+	defer conn.Close()
+
+	events := s.subscribe()
+	defer s.unsubscribe(events)
+
+	// Clients only listen, reading detects when they go away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package sounds
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/pkg/assistant"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+// ParseNames validates the sound names selected for playback
+func ParseNames(names []string) ([]string, error) {
+	selected := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		supported := false
+		for _, candidate := range Names {
+			if name == candidate {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return nil, fmt.Errorf("unknown sound '%s' (expected %s)", name, strings.Join(Names, ", "))
+		}
+		selected = append(selected, name)
+	}
+	return selected, nil
+}
+
+// Play plays sound with ffplay and waits for the end, generated sounds are
+// piped to its standard input
+func Play(sound Sound) error {
+	input := sound.Path
+	if sound.Data != nil {
+		input = "-"
+	}
+	cmd := exec.Command("ffplay", "-nodisp", "-autoexit", "-v", "quiet", input)
+	if sound.Data != nil {
+		cmd.Stdin = bytes.NewReader(sound.Data)
+	}
+	return cmd.Run()
+}
+
+// Emitter plays the sounds of a theme on pipeline events
+type Emitter struct {
+	theme    *Theme
+	selected map[string]bool
+	play     func(sound Sound) error
+	state    string // Last assistant state, sleep follows active states only
+	mutex    sync.Mutex
+}
+
+// NewEmitter plays the selected sounds of theme
+func NewEmitter(theme *Theme, names []string) *Emitter {
+	e := &Emitter{
+		theme:    theme,
+		selected: make(map[string]bool),
+		play:     Play,
+	}
+	for _, name := range names {
+		e.selected[name] = true
+	}
+	return e
+}
+
+// Emit plays the sound of an event without blocking the pipeline
+func (e *Emitter) Emit(event output.Event) error {
+	name := e.soundFor(event)
+	if name == "" || !e.selected[name] {
+		return nil
+	}
+	sound, ok := e.theme.Sound(name)
+	if !ok {
+		return nil
+	}
+
+	go func() {
+		if err := e.play(sound); err != nil {
+			logger.WithError(err).WithField("sound", name).Error("🔊 Failed to play sound")
+		}
+	}()
+	return nil
+}
+
+// soundFor returns the sound name of an event, empty for silent events
+func (e *Emitter) soundFor(event output.Event) string {
+	switch event.Type {
+	case output.EventWakeWord:
+		return SoundWake
+	case output.EventTimer:
+		return SoundTimer
+	case output.EventError:
+		return SoundError
+	case output.EventState:
+		e.mutex.Lock()
+		previous := e.state
+		e.state = event.State
+		e.mutex.Unlock()
+		switch assistant.State(event.State) {
+		case assistant.StateThinking:
+			return SoundThinking
+		case assistant.StateWakeListening:
+			// Not at startup nor on resume
+			if previous != "" && previous != string(assistant.StateIdle) && previous != string(assistant.StatePaused) {
+				return SoundSleep
+			}
+		}
+	}
+	return ""
+}
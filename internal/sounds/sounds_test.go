@@ -0,0 +1,113 @@
+package sounds
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+func TestDefaultTheme_GeneratesEverySound(t *testing.T) {
+	theme := DefaultTheme()
+	for _, name := range Names {
+		sound, ok := theme.Sound(name)
+		if !ok || len(sound.Data) <= 44 || string(sound.Data[:4]) != "RIFF" || string(sound.Data[8:12]) != "WAVE" {
+			t.Errorf("Expected a generated WAV for %s, got %d bytes", name, len(sound.Data))
+		}
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "wake.ogg"), []byte("ogg"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	theme, err := LoadTheme(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if sound, _ := theme.Sound(SoundWake); sound.Path != filepath.Join(dir, "wake.ogg") {
+		t.Errorf("Expected the wake file of the theme, got %+v", sound.Path)
+	}
+	if sound, ok := theme.Sound(SoundError); !ok || sound.Data == nil {
+		t.Error("Expected the missing error sound to keep its tone")
+	}
+
+	off, err := LoadTheme(ThemeOff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := off.Sound(SoundWake); ok {
+		t.Error("Expected no sound with the off theme")
+	}
+	if _, err := LoadTheme(t.TempDir()); err == nil {
+		t.Error("Expected an error for a directory without sounds")
+	}
+	if _, err := LoadTheme(filepath.Join(dir, "missing")); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
+
+func TestParseNames(t *testing.T) {
+	if names, err := ParseNames([]string{" Wake", "error"}); err != nil || len(names) != 2 || names[0] != SoundWake {
+		t.Errorf("Expected wake and error, got %v (%v)", names, err)
+	}
+	if _, err := ParseNames([]string{"beep"}); err == nil {
+		t.Error("Expected an error for an unknown sound")
+	}
+}
+
+func TestEmitter_SoundsOfEvents(t *testing.T) {
+	e := NewEmitter(DefaultTheme(), Names)
+
+	for i, step := range []struct {
+		event output.Event
+		sound string
+	}{
+		{output.Event{Type: output.EventState, State: "wake_listening"}, ""}, // Startup
+		{output.Event{Type: output.EventWakeWord, Text: "Jack"}, SoundWake},
+		{output.Event{Type: output.EventState, State: "active"}, ""},
+		{output.Event{Type: output.EventState, State: "thinking"}, SoundThinking},
+		{output.Event{Type: output.EventError, Error: "AI unreachable"}, SoundError},
+		{output.Event{Type: output.EventState, State: "wake_listening"}, SoundSleep},
+		{output.Event{Type: output.EventState, State: "paused"}, ""},
+		{output.Event{Type: output.EventState, State: "wake_listening"}, ""}, // Resumed
+		{output.Event{Type: output.EventTimer, Text: "Pâtes"}, SoundTimer},
+		{output.Event{Type: output.EventTranscript, Text: "Bonjour"}, ""},
+	} {
+		if sound := e.soundFor(step.event); sound != step.sound {
+			t.Errorf("Step %d: expected sound %q for %+v, got %q", i, step.sound, step.event, sound)
+		}
+	}
+}
+
+func TestEmitter_PlaysSelectedSounds(t *testing.T) {
+	played := make(chan Sound, 2)
+	theme := DefaultTheme()
+	theme.SetFile(SoundWake, "/sounds/wake.wav")
+	e := NewEmitter(theme, []string{SoundWake})
+	e.play = func(sound Sound) error {
+		played <- sound
+		return nil
+	}
+
+	e.Emit(output.Event{Type: output.EventError, Error: "AI unreachable"}) // Not selected
+	e.Emit(output.Event{Type: output.EventWakeWord, Text: "Jack"})
+
+	select {
+	case sound := <-played:
+		if sound.Path != "/sounds/wake.wav" {
+			t.Errorf("Expected the wake sound, got %+v", sound)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the wake sound to be played")
+	}
+	select {
+	case sound := <-played:
+		t.Errorf("Expected no other sound, got %+v", sound)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
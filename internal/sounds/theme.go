@@ -0,0 +1,110 @@
+// Package sounds plays the audio feedback of the assistant events from a
+// sound theme: a directory of audio files named after the events, or tones
+// generated at startup so that no asset file is required
+package sounds
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Sound names, the events of a theme
+const (
+	SoundWake     = "wake"     // The wake word was heard, listening starts
+	SoundSleep    = "sleep"    // Listening stopped, waiting for the wake word again
+	SoundThinking = "thinking" // A request was sent to the AI
+	SoundError    = "error"    // Something failed, e.g. the AI is unreachable
+	SoundTimer    = "timer"    // A timer or reminder fired
+)
+
+// Names lists the sounds of a theme
+var Names = []string{SoundWake, SoundSleep, SoundThinking, SoundError, SoundTimer}
+
+// Theme names with a special meaning
+const (
+	ThemeDefault = "default" // Generated tones
+	ThemeOff     = "off"     // No sound at all
+)
+
+// extensions are the audio files looked up in a theme directory, by priority
+var extensions = []string{".wav", ".ogg", ".opus", ".flac", ".mp3"}
+
+// Sound is an audio file or generated WAV data
+type Sound struct {
+	Path string
+	Data []byte
+}
+
+// Theme maps the sound names to sounds, missing names are silent
+type Theme struct {
+	name   string
+	sounds map[string]Sound
+}
+
+// DefaultTheme returns the generated tones
+func DefaultTheme() *Theme {
+	t := &Theme{name: ThemeDefault, sounds: make(map[string]Sound)}
+	for name, notes := range defaultTones {
+		t.sounds[name] = Sound{Data: tone(notes)}
+	}
+	return t
+}
+
+// LoadTheme loads a theme: ThemeDefault (or empty), ThemeOff, or a
+// directory of files named after the sounds, e.g. wake.wav or error.mp3.
+// The sounds missing from the directory keep their generated tone.
+func LoadTheme(theme string) (*Theme, error) {
+	switch strings.ToLower(theme) {
+	case "", ThemeDefault:
+		return DefaultTheme(), nil
+	case ThemeOff:
+		return &Theme{name: ThemeOff, sounds: make(map[string]Sound)}, nil
+	}
+
+	info, err := os.Stat(theme)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sound theme: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("sound theme %s is not a directory", theme)
+	}
+
+	t := DefaultTheme()
+	t.name = theme
+	found := 0
+	for _, name := range Names {
+		for _, extension := range extensions {
+			path := filepath.Join(theme, name+extension)
+			if _, err := os.Stat(path); err == nil {
+				t.sounds[name] = Sound{Path: path}
+				found++
+				break
+			}
+		}
+	}
+	if found == 0 {
+		return nil, fmt.Errorf("sound theme %s has none of the %s sounds", theme, strings.Join(Names, ", "))
+	}
+	return t, nil
+}
+
+// Name returns the theme name or directory
+func (t *Theme) Name() string {
+	return t.name
+}
+
+// SetFile plays the file at path for the sound name, empty keeps the theme
+// sound
+func (t *Theme) SetFile(name, path string) {
+	if path != "" {
+		t.sounds[name] = Sound{Path: path}
+	}
+}
+
+// Sound returns the sound of name, false when it is silent
+func (t *Theme) Sound(name string) (Sound, bool) {
+	sound, ok := t.sounds[name]
+	return sound, ok
+}
@@ -0,0 +1,67 @@
+package sounds
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// toneSampleRate is the sample rate of the generated tones
+const toneSampleRate = 22050
+
+// note is a sine wave, a zero frequency is a silence
+type note struct {
+	frequency float64
+	ms        int
+}
+
+// defaultTones are the sounds of the default theme: rising for the wake
+// word, falling when listening stops, low for errors
+var defaultTones = map[string][]note{
+	SoundWake:     {{660, 80}, {880, 120}},
+	SoundSleep:    {{880, 80}, {587, 140}},
+	SoundThinking: {{523, 60}},
+	SoundError:    {{220, 150}, {0, 60}, {220, 150}},
+	SoundTimer:    {{988, 120}, {0, 80}, {988, 120}, {0, 80}, {988, 200}},
+}
+
+// tone renders notes as a 16-bit mono WAV file
+func tone(notes []note) []byte {
+	var samples []int16
+	for _, n := range notes {
+		count := toneSampleRate * n.ms / 1000
+		// Short fades keep the notes from clicking
+		fade := toneSampleRate * 5 / 1000
+		for i := 0; i < count; i++ {
+			if n.frequency == 0 {
+				samples = append(samples, 0)
+				continue
+			}
+			gain := 0.3
+			if i < fade {
+				gain *= float64(i) / float64(fade)
+			} else if count-i < fade {
+				gain *= float64(count-i) / float64(fade)
+			}
+			value := gain * math.Sin(2*math.Pi*n.frequency*float64(i)/toneSampleRate)
+			samples = append(samples, int16(value*math.MaxInt16))
+		}
+	}
+
+	var buf bytes.Buffer
+	dataSize := uint32(len(samples) * 2)
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, 36+dataSize)
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(1)) // PCM
+	binary.Write(&buf, binary.LittleEndian, uint16(1)) // Mono
+	binary.Write(&buf, binary.LittleEndian, uint32(toneSampleRate))
+	binary.Write(&buf, binary.LittleEndian, uint32(toneSampleRate*2))
+	binary.Write(&buf, binary.LittleEndian, uint16(2))
+	binary.Write(&buf, binary.LittleEndian, uint16(16))
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, dataSize)
+	binary.Write(&buf, binary.LittleEndian, samples)
+	return buf.Bytes()
+}
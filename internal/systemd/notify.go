@@ -0,0 +1,85 @@
+package systemd
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Notification states, see sd_notify(3)
+const (
+	Ready    = "READY=1"
+	Stopping = "STOPPING=1"
+	Watchdog = "WATCHDOG=1"
+)
+
+// Notify sends a state to the service manager. It returns false without
+// error when not running under systemd with Type=notify.
+func Notify(state string) (bool, error) {
+	socketPath := os.Getenv("NOTIFY_SOCKET")
+	if socketPath == "" {
+		return false, nil
+	}
+
+	// Abstract namespace sockets are announced with a leading @
+	addr := &net.UnixAddr{Name: socketPath, Net: "unixgram"}
+	if socketPath[0] == '@' {
+		addr.Name = "\x00" + socketPath[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, addr)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Status returns the STATUS= notification shown by systemctl status
+func Status(text string) string {
+	return "STATUS=" + text
+}
+
+// WatchdogInterval returns the watchdog timeout requested by systemd
+// (WatchdogSec=), or 0 when the watchdog is disabled for this process
+func WatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// StartWatchdog pings the watchdog at half its interval while healthy
+// returns true, until stop is closed. A stalled process is then restarted
+// by systemd. It is a no-op when the watchdog is disabled.
+func StartWatchdog(healthy func() bool, stop <-chan struct{}) {
+	interval := WatchdogInterval()
+	if interval == 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if healthy() {
+					Notify(Watchdog)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,91 @@
+package systemd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotify(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	t.Setenv("NOTIFY_SOCKET", socketPath)
+	sent, err := Notify(Ready)
+	if err != nil || !sent {
+		t.Fatalf("Expected notification to be sent, got sent=%v err=%v", sent, err)
+	}
+
+	buf := make([]byte, 64)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _ := conn.Read(buf)
+	if string(buf[:n]) != "READY=1" {
+		t.Errorf("Expected READY=1, got %q", string(buf[:n]))
+	}
+}
+
+func TestNotify_NotUnderSystemd(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if sent, err := Notify(Ready); sent || err != nil {
+		t.Errorf("Expected no-op without NOTIFY_SOCKET, got sent=%v err=%v", sent, err)
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "30000000")
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+	if interval := WatchdogInterval(); interval != 30*time.Second {
+		t.Errorf("Expected 30s, got %s", interval)
+	}
+
+	t.Setenv("WATCHDOG_PID", "1")
+	if interval := WatchdogInterval(); interval != 0 {
+		t.Errorf("Expected watchdog of another process to be ignored, got %s", interval)
+	}
+
+	t.Setenv("WATCHDOG_USEC", "")
+	if interval := WatchdogInterval(); interval != 0 {
+		t.Errorf("Expected disabled watchdog, got %s", interval)
+	}
+}
+
+func TestUnitFile(t *testing.T) {
+	unit := UnitFile(UnitConfig{
+		ExecStart: []string{"/home/me/bin/nrz-ai", "--daemon", "--system-prompt", `Tu es "Jack", 100% français`},
+		User:      true,
+	})
+
+	for _, expected := range []string{
+		"Type=notify\n",
+		"WatchdogSec=30\n",
+		"ExecReload=/bin/kill -HUP $MAINPID\n",
+		`ExecStart=/home/me/bin/nrz-ai --daemon --system-prompt "Tu es \"Jack\", 100%% français"` + "\n",
+		"WantedBy=default.target\n",
+	} {
+		if !strings.Contains(unit, expected) {
+			t.Errorf("Expected unit to contain %q, got:\n%s", expected, unit)
+		}
+	}
+
+	if system := UnitFile(UnitConfig{ExecStart: []string{"/usr/bin/nrz-ai"}}); !strings.Contains(system, "WantedBy=multi-user.target") {
+		t.Errorf("Expected system unit target, got:\n%s", system)
+	}
+}
+
+func TestUnitPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/config")
+	if path, _ := UnitPath(true); path != "/tmp/config/systemd/user/nrz-ai.service" {
+		t.Errorf("Unexpected user unit path %s", path)
+	}
+	if path, _ := UnitPath(false); path != "/etc/systemd/system/nrz-ai.service" {
+		t.Errorf("Unexpected system unit path %s", path)
+	}
+}
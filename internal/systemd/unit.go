@@ -0,0 +1,86 @@
+package systemd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// UnitName is the name of the installed service
+const UnitName = "nrz-ai.service"
+
+// UnitConfig describes the generated service
+type UnitConfig struct {
+	ExecStart []string // Command line, the first element is the absolute binary path
+	User      bool     // User service (systemctl --user) rather than a system one
+}
+
+// UnitFile renders a Type=notify service unit running ExecStart
+func UnitFile(cfg UnitConfig) string {
+	args := make([]string, 0, len(cfg.ExecStart))
+	for _, arg := range cfg.ExecStart {
+		args = append(args, quote(arg))
+	}
+
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=NRZ-AI real-time speech-to-text assistant\n")
+	b.WriteString("Documentation=https://github.com/nerzhul/nrz-ai\n")
+	if cfg.User {
+		// Needs the user audio server
+		b.WriteString("After=pipewire-pulse.service pulseaudio.service\n")
+	} else {
+		b.WriteString("After=network-online.target sound.target\n")
+		b.WriteString("Wants=network-online.target\n")
+	}
+
+	b.WriteString("\n[Service]\n")
+	b.WriteString("Type=notify\n")
+	b.WriteString("NotifyAccess=main\n")
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(args, " "))
+	// systemctl reload re-reads the config file
+	b.WriteString("ExecReload=/bin/kill -HUP $MAINPID\n")
+	b.WriteString("WatchdogSec=30\n")
+	b.WriteString("Restart=on-failure\n")
+	b.WriteString("RestartSec=5\n")
+	// Leaves time to transcribe pending utterances on stop
+	b.WriteString("TimeoutStopSec=45\n")
+
+	b.WriteString("\n[Install]\n")
+	if cfg.User {
+		b.WriteString("WantedBy=default.target\n")
+	} else {
+		b.WriteString("WantedBy=multi-user.target\n")
+	}
+	return b.String()
+}
+
+// UnitPath returns where the unit is installed: the systemd user directory
+// for user services, /etc/systemd/system otherwise
+func UnitPath(user bool) (string, error) {
+	if !user {
+		return filepath.Join("/etc/systemd/system", UnitName), nil
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configHome = filepath.Join(homeDir, ".config")
+	}
+	return filepath.Join(configHome, "systemd", "user", UnitName), nil
+}
+
+// quote quotes an ExecStart argument when needed, systemd understands
+// C-style double quotes and expands % specifiers
+func quote(arg string) string {
+	arg = strings.ReplaceAll(arg, "%", "%%")
+	if arg != "" && !strings.ContainsAny(arg, " \t\"'\\$;") {
+		return arg
+	}
+	arg = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `$$`).Replace(arg)
+	return `"` + arg + `"`
+}
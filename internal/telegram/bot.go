@@ -0,0 +1,476 @@
+// Package telegram is a Telegram bot interface: the text and voice messages
+// of the allowed chats are answered by the assistant, voice messages
+// transcribed by Whisper first, and the live transcripts can be forwarded to
+// a chat
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/blocklist"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/internal/privacy"
+	"github.com/nerzhul/nrz-ai/internal/redact"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+const (
+	apiURL         = "https://api.telegram.org"
+	queueSize      = 64
+	requestTimeout = 10 * time.Second
+	// pollTimeout is how long Telegram holds a poll without news
+	pollTimeout = 30 * time.Second
+	retryDelay  = 5 * time.Second
+	// maxVoiceBytes is the largest file bots can download
+	maxVoiceBytes = 20 << 20
+	// maxMessageRunes is the longest text of a message, longer ones are split
+	maxMessageRunes = 4096
+	// transcribeTimeout bounds the transcription of a voice message
+	transcribeTimeout = 2 * time.Minute
+)
+
+// outgoing is a message to send
+type outgoing struct {
+	ChatID int64  `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+// Bot answers the messages of the allowed chats with an Answerer and forwards
+// the live transcripts. Messages are handled one at a time, in order, and
+// forwarded transcripts are sent in the background so that Telegram never
+// stalls the pipeline.
+type Bot struct {
+	config         Config
+	api            string
+	httpClient     *http.Client
+	chats          map[int64]bool
+	whisperService whisper.WhisperService
+	decoder        audio.FileDecoder
+	language       string
+	answerer       Answerer
+	redactor       *redact.Redactor
+	blocklist      *blocklist.Blocklist
+
+	// Set by Start
+	username string
+	offset   int64 // ID of the next update to handle
+
+	queue     chan outgoing
+	ctx       context.Context
+	cancel    context.CancelFunc
+	started   bool
+	sent      chan struct{} // Closed when the sender stops
+	polled    chan struct{} // Closed when the poll loop stops
+	closeOnce sync.Once
+}
+
+// NewBot creates a bot transcribing voice messages with service, call Start
+// to receive the messages
+func NewBot(config Config, service whisper.WhisperService, decoder audio.FileDecoder, language string) *Bot {
+	chats := make(map[int64]bool, len(config.ChatIDs))
+	for _, id := range config.ChatIDs {
+		chats[id] = true
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Bot{
+		config:         config,
+		api:            apiURL,
+		httpClient:     &http.Client{},
+		chats:          chats,
+		whisperService: service,
+		decoder:        decoder,
+		language:       language,
+		queue:          make(chan outgoing, queueSize),
+		ctx:            ctx,
+		cancel:         cancel,
+		sent:           make(chan struct{}),
+		polled:         make(chan struct{}),
+	}
+}
+
+// SetAnswerer enables the answers, the live assistant answers the messages
+// as if they had been spoken. Without it voice messages are only transcribed.
+func (b *Bot) SetAnswerer(answerer Answerer) {
+	b.answerer = answerer
+}
+
+// SetRedactor masks personal data in the transcripts of voice messages
+func (b *Bot) SetRedactor(redactor *redact.Redactor) {
+	b.redactor = redactor
+}
+
+// SetBlocklist drops the made up phrases of list from the transcripts of
+// voice messages
+func (b *Bot) SetBlocklist(list *blocklist.Blocklist) {
+	b.blocklist = list
+}
+
+// Start checks the token and starts receiving the messages. The messages
+// sent before are ignored.
+func (b *Bot) Start() error {
+	if strings.TrimSpace(b.config.Token) == "" {
+		return errors.New("the Telegram bot token is required")
+	}
+
+	ctx, cancel := context.WithTimeout(b.ctx, requestTimeout)
+	defer cancel()
+
+	var me struct {
+		Username string `json:"username"`
+	}
+	if err := b.call(ctx, "getMe", nil, &me); err != nil {
+		return fmt.Errorf("failed to authenticate to Telegram: %w", err)
+	}
+	b.username = me.Username
+
+	// Telegram keeps the updates of the last day, only the last one is
+	// fetched to start after it
+	var updates []update
+	if err := b.call(ctx, "getUpdates", map[string]any{"offset": -1}, &updates); err != nil {
+		return fmt.Errorf("failed to reach Telegram: %w", err)
+	}
+	if len(updates) > 0 {
+		b.offset = updates[len(updates)-1].ID + 1
+	}
+
+	b.started = true
+	go b.sender()
+	go b.poll()
+	return nil
+}
+
+// Username returns the name of the bot, once started
+func (b *Bot) Username() string {
+	return b.username
+}
+
+// Emit queues the transcripts of the live microphone for the forward chat
+func (b *Bot) Emit(event output.Event) error {
+	text := strings.TrimSpace(event.Text)
+	if event.Type != output.EventTranscript || b.config.ForwardChatID == 0 || text == "" {
+		return nil
+	}
+	if event.Speaker != "" {
+		text = event.Speaker + ": " + text
+	}
+
+	select {
+	case b.queue <- outgoing{ChatID: b.config.ForwardChatID, Text: "🎙️ " + text}:
+		return nil
+	default:
+		return fmt.Errorf("telegram queue full, dropped %s event", event.Type)
+	}
+}
+
+// Close sends the queued transcripts and stops receiving the messages
+func (b *Bot) Close() {
+	b.closeOnce.Do(func() {
+		b.cancel()
+		if b.started {
+			<-b.polled
+			close(b.queue)
+			<-b.sent
+		}
+	})
+}
+
+// sender sends the forwarded transcripts
+func (b *Bot) sender() {
+	defer close(b.sent)
+
+	for msg := range b.queue {
+		if err := b.send(msg.ChatID, msg.Text); err != nil {
+			logger.WithError(err).Error("❌ Telegram message not sent")
+		}
+	}
+}
+
+// update is the part of a Telegram update read by the bot
+type update struct {
+	ID      int64    `json:"update_id"`
+	Message *message `json:"message"`
+}
+
+// message is a message sent to the bot
+type message struct {
+	ID   int64 `json:"message_id"`
+	Chat struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+	Text  string `json:"text"`
+	Voice *voice `json:"voice"`
+}
+
+// voice is a voice message
+type voice struct {
+	FileID   string `json:"file_id"`
+	Duration int    `json:"duration"`
+	FileSize int64  `json:"file_size"`
+}
+
+// poll handles the messages until Close
+func (b *Bot) poll() {
+	defer close(b.polled)
+
+	for b.ctx.Err() == nil {
+		ctx, cancel := context.WithTimeout(b.ctx, pollTimeout+requestTimeout)
+		var updates []update
+		err := b.call(ctx, "getUpdates", map[string]any{
+			"offset":          b.offset,
+			"timeout":         int(pollTimeout.Seconds()),
+			"allowed_updates": []string{"message"},
+		}, &updates)
+		cancel()
+		if err != nil {
+			if b.ctx.Err() != nil {
+				return
+			}
+			logger.WithError(err).Warnf("⚠️  Telegram poll failed, retrying in %s", retryDelay)
+			select {
+			case <-b.ctx.Done():
+				return
+			case <-time.After(retryDelay):
+			}
+			continue
+		}
+
+		for _, u := range updates {
+			b.offset = u.ID + 1
+			b.handle(u)
+		}
+	}
+}
+
+// handle answers the message of an update, if from an allowed chat
+func (b *Bot) handle(u update) {
+	msg := u.Message
+	if msg == nil {
+		return
+	}
+	chat := msg.Chat.ID
+	if !b.chats[chat] {
+		logger.Warnf("⚠️  Telegram message from chat %d ignored, add it to telegram_chat_ids to answer it", chat)
+		return
+	}
+
+	text := strings.TrimSpace(msg.Text)
+	meta := ai.NewMetadata(time.Now(), ai.SourceTelegram)
+	switch {
+	case msg.Voice != nil:
+		transcript, err := b.transcribe(msg.Voice)
+		if err != nil {
+			logger.WithError(err).Error("❌ Telegram voice message not transcribed")
+			b.reply(chat, "❌ "+err.Error())
+			return
+		}
+		if transcript == "" {
+			b.reply(chat, "🔇 Nothing heard")
+			return
+		}
+		b.reply(chat, "🎙️ "+transcript)
+		text = transcript
+		meta.Duration = float64(msg.Voice.Duration)
+	case text == "":
+		return
+	case text == "/start" || strings.HasPrefix(text, "/start "):
+		b.reply(chat, "👋 Send me a question, typed or as a voice message")
+		return
+	}
+
+	if b.answerer == nil {
+		if msg.Voice == nil {
+			b.reply(chat, "🤖 The AI is disabled")
+		}
+		return
+	}
+	answer, err := b.answerer.Answer(text, meta)
+	if err != nil {
+		logger.WithError(err).Error("❌ Telegram question not answered")
+		b.reply(chat, "❌ "+err.Error())
+		return
+	}
+	b.reply(chat, answer)
+}
+
+// transcribe downloads and transcribes a voice message
+func (b *Bot) transcribe(v *voice) (string, error) {
+	// Voice messages go through a temporary file for ffmpeg
+	if err := privacy.Check("Telegram voice messages"); err != nil {
+		return "", err
+	}
+	if v.FileSize > maxVoiceBytes {
+		return "", fmt.Errorf("voice message of %d bytes, the limit is %d", v.FileSize, maxVoiceBytes)
+	}
+
+	path, err := b.download(v.FileID)
+	if err != nil {
+		return "", fmt.Errorf("failed to download the voice message: %w", err)
+	}
+	defer os.Remove(path)
+
+	samples, err := b.decoder.DecodeFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(b.ctx, transcribeTimeout)
+	defer cancel()
+	result, err := b.whisperService.Transcribe(ctx, samples, b.language)
+	if err != nil {
+		return "", err
+	}
+	if b.blocklist != nil {
+		result, _ = b.blocklist.Result(result)
+	}
+	if b.redactor != nil {
+		result = b.redactor.Result(result)
+	}
+	return strings.TrimSpace(result.Text), nil
+}
+
+// download saves a file sent to the bot to a temporary file
+func (b *Bot) download(fileID string) (string, error) {
+	ctx, cancel := context.WithTimeout(b.ctx, requestTimeout)
+	defer cancel()
+
+	var file struct {
+		Path string `json:"file_path"`
+	}
+	if err := b.call(ctx, "getFile", map[string]any{"file_id": fileID}, &file); err != nil {
+		return "", err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, b.api+"/file/bot"+b.config.Token+"/"+file.Path, nil)
+	if err != nil {
+		return "", errors.New("invalid file path")
+	}
+	resp, err := b.httpClient.Do(request)
+	if err != nil {
+		return "", withoutURL(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	tmp, err := os.CreateTemp("", "nrz-ai-telegram-*")
+	if err != nil {
+		return "", err
+	}
+	defer tmp.Close()
+	if _, err := io.Copy(tmp, io.LimitReader(resp.Body, maxVoiceBytes)); err != nil {
+		os.Remove(tmp.Name())
+		return "", withoutURL(err)
+	}
+	return tmp.Name(), nil
+}
+
+// reply sends text to chat, logging the failures
+func (b *Bot) reply(chat int64, text string) {
+	if err := b.send(chat, text); err != nil {
+		logger.WithError(err).Error("❌ Telegram message not sent")
+	}
+}
+
+// send sends text to chat, split in as many messages as needed
+func (b *Bot) send(chat int64, text string) error {
+	for _, part := range split(strings.TrimSpace(text), maxMessageRunes) {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		err := b.call(ctx, "sendMessage", outgoing{ChatID: chat, Text: part}, nil)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// split cuts text in parts of at most size runes, on line breaks or spaces
+// when there are
+func split(text string, size int) []string {
+	var parts []string
+	for text != "" {
+		runes := []rune(text)
+		if len(runes) <= size {
+			parts = append(parts, text)
+			break
+		}
+		part := string(runes[:size])
+		if cut := strings.LastIndexAny(part, "\n "); cut > 0 {
+			part = part[:cut]
+		}
+		parts = append(parts, part)
+		text = strings.TrimSpace(text[len(part):])
+	}
+	return parts
+}
+
+// apiResponse is the envelope of every Bot API reply
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+}
+
+// call calls a Bot API method with params sent as JSON, and decodes its
+// result into result, unless nil
+func (b *Bot) call(ctx context.Context, method string, params, result any) error {
+	payload, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	if params == nil {
+		payload = []byte("{}")
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, b.api+"/bot"+b.config.Token+"/"+method, bytes.NewReader(payload))
+	if err != nil {
+		// The URL holds the token, keep it out of the logs
+		return errors.New("invalid Telegram token")
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "nrz-ai")
+
+	resp, err := b.httpClient.Do(request)
+	if err != nil {
+		return withoutURL(err)
+	}
+	defer resp.Body.Close()
+
+	var reply apiResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxVoiceBytes)).Decode(&reply); err != nil {
+		return fmt.Errorf("unexpected %s reply: %s", method, resp.Status)
+	}
+	if !reply.OK {
+		return fmt.Errorf("%s failed: %s (%d)", method, reply.Description, reply.ErrorCode)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(reply.Result, result)
+}
+
+// withoutURL strips the URL, holding the token, from an HTTP client error
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
@@ -0,0 +1,21 @@
+package telegram
+
+import "github.com/nerzhul/nrz-ai/pkg/ai"
+
+// Answerer answers the messages of the chats
+type Answerer interface {
+	// Answer handles a message described by meta and returns the reply
+	Answer(text string, meta *ai.Metadata) (string, error)
+}
+
+// Config holds the Telegram bot settings
+type Config struct {
+	Token string // Bot token given by @BotFather
+
+	// ChatIDs lists the chats answered, the messages of the others are
+	// ignored and logged with their chat ID
+	ChatIDs []int64
+
+	// ForwardChatID receives the transcripts of the live microphone, 0 for none
+	ForwardChatID int64
+}
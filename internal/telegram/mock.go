@@ -0,0 +1,42 @@
+package telegram
+
+import (
+	"sync"
+
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+)
+
+// MockAnswerer implements Answerer for testing
+type MockAnswerer struct {
+	questions []string
+	metas     []*ai.Metadata
+	mutex     sync.Mutex
+}
+
+// NewMockAnswerer creates a new mock answerer
+func NewMockAnswerer() *MockAnswerer {
+	return &MockAnswerer{}
+}
+
+// Answer records the message and echoes it
+func (m *MockAnswerer) Answer(text string, meta *ai.Metadata) (string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.questions = append(m.questions, text)
+	m.metas = append(m.metas, meta)
+	return "Answer to: " + text, nil
+}
+
+// Questions returns the messages passed to Answer
+func (m *MockAnswerer) Questions() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]string(nil), m.questions...)
+}
+
+// Metas returns the metadata passed to Answer
+func (m *MockAnswerer) Metas() []*ai.Metadata {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]*ai.Metadata(nil), m.metas...)
+}
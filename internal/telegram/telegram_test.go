@@ -0,0 +1,219 @@
+package telegram
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+// botAPI fakes the Bot API of the token "42:s3cret"
+type botAPI struct {
+	server  *httptest.Server
+	mutex   sync.Mutex
+	updates [][]string // Updates of the next getUpdates, as JSON
+	offsets []int64
+	sent    []outgoing
+}
+
+func newBotAPI(t *testing.T, updates ...[]string) *botAPI {
+	t.Helper()
+
+	api := &botAPI{updates: updates}
+	api.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/file/bot42:s3cret/voice/file_1.oga" {
+			fmt.Fprint(w, "OggS....")
+			return
+		}
+		method, ok := strings.CutPrefix(r.URL.Path, "/bot42:s3cret/")
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"ok":false,"error_code":401,"description":"Unauthorized"}`)
+			return
+		}
+
+		var params map[string]any
+		json.NewDecoder(r.Body).Decode(&params)
+
+		api.mutex.Lock()
+		defer api.mutex.Unlock()
+
+		switch method {
+		case "getMe":
+			fmt.Fprint(w, `{"ok":true,"result":{"id":42,"username":"maison_bot"}}`)
+		case "getFile":
+			fmt.Fprint(w, `{"ok":true,"result":{"file_path":"voice/file_1.oga"}}`)
+		case "getUpdates":
+			offset, _ := params["offset"].(float64)
+			api.offsets = append(api.offsets, int64(offset))
+			if len(api.updates) == 0 {
+				api.mutex.Unlock()
+				select {
+				case <-r.Context().Done():
+				case <-time.After(20 * time.Millisecond):
+				}
+				api.mutex.Lock()
+				fmt.Fprint(w, `{"ok":true,"result":[]}`)
+				return
+			}
+			batch := api.updates[0]
+			api.updates = api.updates[1:]
+			fmt.Fprintf(w, `{"ok":true,"result":[%s]}`, strings.Join(batch, ","))
+		case "sendMessage":
+			chat, _ := params["chat_id"].(float64)
+			text, _ := params["text"].(string)
+			api.sent = append(api.sent, outgoing{ChatID: int64(chat), Text: text})
+			fmt.Fprint(w, `{"ok":true,"result":{}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"ok":false,"error_code":404,"description":"Not Found"}`)
+		}
+	}))
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+func (a *botAPI) messages() []outgoing {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return append([]outgoing(nil), a.sent...)
+}
+
+func textUpdate(id, chat int64, text string) string {
+	return fmt.Sprintf(`{"update_id":%d,"message":{"message_id":%d,"chat":{"id":%d},"text":%q}}`, id, id, chat, text)
+}
+
+func newTestBot(t *testing.T, api *botAPI, config Config) *Bot {
+	t.Helper()
+
+	service := whisper.NewMockWhisperService()
+	service.LoadModel("test.bin")
+	service.SetTranscribeResult(whisper.TranscriptionResult{Text: " Quel temps fait-il ?", Language: "fr"})
+	decoder := audio.NewMockFileDecoder()
+	decoder.SetDefaultSamples(make([]float32, 16000))
+
+	config.Token = "42:s3cret"
+	bot := NewBot(config, service, decoder, "fr")
+	bot.api = api.server.URL
+	return bot
+}
+
+func TestBot_AnswersAllowedChats(t *testing.T) {
+	api := newBotAPI(t,
+		[]string{textUpdate(41, 7, "Message d'hier")},
+		[]string{
+			textUpdate(42, 7, "/start"),
+			textUpdate(43, 99, "Ouvre la porte"),
+			textUpdate(44, 7, "Quelle heure est-il ?"),
+			`{"update_id":45,"message":{"message_id":45,"chat":{"id":7},"voice":{"file_id":"f1","duration":2,"file_size":9000}}}`,
+		},
+	)
+	answerer := NewMockAnswerer()
+	bot := newTestBot(t, api, Config{ChatIDs: []int64{7}})
+	bot.SetAnswerer(answerer)
+
+	if err := bot.Start(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if bot.Username() != "maison_bot" {
+		t.Errorf("Expected the name of the bot, got %q", bot.Username())
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(api.messages()) < 4 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	bot.Close()
+
+	expected := []outgoing{
+		{ChatID: 7, Text: "👋 Send me a question, typed or as a voice message"},
+		{ChatID: 7, Text: "Answer to: Quelle heure est-il ?"},
+		{ChatID: 7, Text: "🎙️ Quel temps fait-il ?"},
+		{ChatID: 7, Text: "Answer to: Quel temps fait-il ?"},
+	}
+	sent := api.messages()
+	if len(sent) != len(expected) {
+		t.Fatalf("Expected %d messages, got %v", len(expected), sent)
+	}
+	for i := range expected {
+		if sent[i] != expected[i] {
+			t.Errorf("Expected message %d to be %v, got %v", i, expected[i], sent[i])
+		}
+	}
+
+	if api.offsets[0] != -1 || api.offsets[1] != 42 {
+		t.Errorf("Expected the history to be skipped, got offsets %v", api.offsets)
+	}
+	metas := answerer.Metas()
+	if len(metas) != 2 || metas[0].Source != ai.SourceTelegram || metas[1].Duration != 2 {
+		t.Errorf("Expected the questions to be answered with their source, got %+v", metas)
+	}
+}
+
+func TestBot_TranscribesWithoutAI(t *testing.T) {
+	api := newBotAPI(t)
+	bot := newTestBot(t, api, Config{ChatIDs: []int64{7}})
+
+	voiceMessage := &message{ID: 1, Voice: &voice{FileID: "f1", Duration: 2}}
+	voiceMessage.Chat.ID = 7
+	bot.handle(update{ID: 1, Message: voiceMessage})
+	bot.handle(update{ID: 2})
+
+	if sent := api.messages(); len(sent) != 1 || sent[0].Text != "🎙️ Quel temps fait-il ?" {
+		t.Errorf("Expected only the transcript, got %v", sent)
+	}
+}
+
+func TestBot_ForwardsTranscripts(t *testing.T) {
+	api := newBotAPI(t)
+	bot := newTestBot(t, api, Config{ForwardChatID: -100})
+	if err := bot.Start(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	bot.Emit(output.Event{Type: output.EventTranscript, Text: "Bonjour", Speaker: "jean"})
+	bot.Emit(output.Event{Type: output.EventAIResponse, Text: "Salut"})
+	bot.Close()
+
+	if sent := api.messages(); len(sent) != 1 || sent[0] != (outgoing{ChatID: -100, Text: "🎙️ jean: Bonjour"}) {
+		t.Errorf("Expected the transcript to be forwarded, got %v", sent)
+	}
+}
+
+func TestBot_StartErrors(t *testing.T) {
+	api := newBotAPI(t)
+
+	if err := NewBot(Config{}, nil, nil, "fr").Start(); err == nil {
+		t.Error("Expected an error without a token")
+	}
+
+	bot := NewBot(Config{Token: "42:wrong"}, nil, nil, "fr")
+	bot.api = api.server.URL
+	err := bot.Start()
+	if err == nil || !strings.Contains(err.Error(), "Unauthorized") {
+		t.Errorf("Expected the Bot API error, got: %v", err)
+	}
+	if strings.Contains(err.Error(), "wrong") {
+		t.Errorf("Expected the token to stay out of the error, got: %v", err)
+	}
+	bot.Close()
+}
+
+func TestSplit(t *testing.T) {
+	parts := split("un deux trois\nquatre", 9)
+	if len(parts) != 3 || parts[0] != "un deux" || parts[1] != "trois" || parts[2] != "quatre" {
+		t.Errorf("Expected the text to be split on spaces and line breaks, got %q", parts)
+	}
+	if parts := split(strings.Repeat("é", 5), 2); len(parts) != 3 || parts[2] != "é" {
+		t.Errorf("Expected runes to be kept whole, got %q", parts)
+	}
+}
@@ -0,0 +1,240 @@
+// Package textproc formats dictated transcripts: spoken punctuation
+// commands, punctuation spacing and sentence capitalization, with rules per
+// language
+package textproc
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// noBreakSpace separates French double punctuation from the previous word
+const noBreakSpace = "\u00a0"
+
+// punctuationMarks are attached to the previous word
+const punctuationMarks = ".,;:!?…"
+
+// command is a spoken phrase replaced by a symbol
+type command struct {
+	words      []string
+	symbol     string
+	attach     bool // No space before the symbol
+	spaceAfter bool // Space before the next word
+}
+
+// newCommand builds a command from a spoken phrase
+func newCommand(phrase, symbol string, attach, spaceAfter bool) command {
+	return command{words: strings.Fields(phrase), symbol: symbol, attach: attach, spaceAfter: spaceAfter}
+}
+
+// punctuation is attached to the previous word and followed by a space
+func punctuation(phrase, symbol string) command {
+	return newCommand(phrase, symbol, true, true)
+}
+
+// lineBreak is attached to the previous word and starts the next one
+func lineBreak(phrase, symbol string) command {
+	return newCommand(phrase, symbol, true, false)
+}
+
+// languageRules are the formatting rules of a language
+type languageRules struct {
+	commands []command
+	// spaceBefore lists the marks preceded by a no-break space
+	spaceBefore string
+}
+
+// languages lists the rules per language, others only get the generic
+// spacing and capitalization
+var languages = map[string]languageRules{
+	"fr": {
+		commands: []command{
+			lineBreak("point à la ligne", ".\n"),
+			lineBreak("nouveau paragraphe", "\n\n"),
+			lineBreak("à la ligne", "\n"),
+			lineBreak("nouvelle ligne", "\n"),
+			punctuation("point d'interrogation", "?"),
+			punctuation("point d'exclamation", "!"),
+			punctuation("points de suspension", "..."),
+			punctuation("point virgule", ";"),
+			punctuation("point-virgule", ";"),
+			punctuation("deux points", ":"),
+			punctuation("virgule", ","),
+			punctuation("point", "."),
+			newCommand("ouvrez la parenthèse", "(", false, false),
+			punctuation("fermez la parenthèse", ")"),
+		},
+		spaceBefore: ";:!?",
+	},
+	"en": {
+		commands: []command{
+			lineBreak("new paragraph", "\n\n"),
+			lineBreak("new line", "\n"),
+			punctuation("question mark", "?"),
+			punctuation("exclamation mark", "!"),
+			punctuation("exclamation point", "!"),
+			punctuation("full stop", "."),
+			punctuation("period", "."),
+			punctuation("comma", ","),
+			punctuation("semicolon", ";"),
+			punctuation("colon", ":"),
+			newCommand("open parenthesis", "(", false, false),
+			punctuation("close parenthesis", ")"),
+		},
+	},
+}
+
+// Formatter formats the successive transcripts of a dictation, spacing and
+// capitalizing each one after what was already typed
+type Formatter struct {
+	rules         languageRules
+	space         bool // Whether the next word needs a leading space
+	sentenceStart bool // Whether the next word starts a sentence
+}
+
+// NewFormatter creates a formatter with the rules of language
+func NewFormatter(language string) *Formatter {
+	return &Formatter{rules: rulesFor(language), sentenceStart: true}
+}
+
+// SetLanguage switches to the rules of language, what follows is still
+// spaced and capitalized after what was already typed
+func (f *Formatter) SetLanguage(language string) {
+	f.rules = rulesFor(language)
+}
+
+// rulesFor returns the rules of language, none for an unknown one
+func rulesFor(language string) languageRules {
+	rules := languages[language]
+	rules.commands = append([]command(nil), rules.commands...)
+	// Longest phrases first so "point virgule" wins over "point"
+	sort.SliceStable(rules.commands, func(i, j int) bool {
+		return len(rules.commands[i].words) > len(rules.commands[j].words)
+	})
+	return rules
+}
+
+// Format applies the spoken commands and normalizes the punctuation and
+// capitalization of text
+func (f *Formatter) Format(text string) string {
+	words := strings.Fields(text)
+	var out strings.Builder
+
+	for i := 0; i < len(words); {
+		if cmd, ok := f.match(words[i:]); ok {
+			typed := out.String()
+			if cmd.attach && cmd.symbol != "\n" && cmd.symbol != "\n\n" {
+				// Drop the punctuation Whisper guessed before the spoken one
+				typed = strings.TrimRight(typed, punctuationMarks+noBreakSpace)
+			}
+			out.Reset()
+			out.WriteString(typed)
+
+			switch {
+			case !cmd.attach && f.space:
+				out.WriteString(" ")
+			case cmd.attach && strings.ContainsAny(cmd.symbol[:1], f.rules.spaceBefore) && (typed != "" || f.space):
+				out.WriteString(noBreakSpace)
+			}
+			out.WriteString(cmd.symbol)
+			f.space = cmd.spaceAfter
+			f.endSentence(cmd.symbol)
+			i += len(cmd.words)
+			continue
+		}
+
+		word := words[i]
+		switch {
+		case strings.Trim(word, punctuationMarks) == "":
+			// Whisper sometimes spaces the punctuation out: "Bonjour , toi"
+			if strings.ContainsAny(word[:1], f.rules.spaceBefore) && (out.Len() > 0 || f.space) {
+				out.WriteString(noBreakSpace)
+			}
+		case f.space:
+			out.WriteString(" ")
+		}
+		if f.sentenceStart {
+			word = capitalize(word)
+		}
+		out.WriteString(f.spacePunctuation(word))
+		f.space = true
+		f.endSentence(word)
+		i++
+	}
+
+	return out.String()
+}
+
+// match returns the command starting at the first word
+func (f *Formatter) match(words []string) (command, bool) {
+	for _, cmd := range f.rules.commands {
+		if len(cmd.words) > len(words) {
+			continue
+		}
+		matched := true
+		for i, word := range cmd.words {
+			if normalizeWord(words[i]) != word {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// endSentence records whether text written last ends a sentence
+func (f *Formatter) endSentence(text string) {
+	trimmed := strings.TrimRight(text, ")\"»")
+	if trimmed == "" {
+		return
+	}
+	last, _ := utf8.DecodeLastRuneInString(trimmed)
+	switch {
+	case strings.ContainsRune(".!?…\n", last):
+		f.sentenceStart = true
+	case unicode.IsLetter(last) || unicode.IsDigit(last) || strings.ContainsRune(",;:", last):
+		f.sentenceStart = false
+	}
+}
+
+// spacePunctuation puts a no-break space before the trailing marks of word
+// that need one, "va?" becomes "va\u00a0?" in French
+func (f *Formatter) spacePunctuation(word string) string {
+	if f.rules.spaceBefore == "" {
+		return word
+	}
+	body := strings.TrimRight(word, punctuationMarks)
+	if body == "" || body == word || !strings.ContainsAny(word[len(body):], f.rules.spaceBefore) {
+		return word
+	}
+	return body + noBreakSpace + word[len(body):]
+}
+
+// capitalize uppercases the first letter of word, after opening quotes or
+// parentheses
+func capitalize(word string) string {
+	for i, r := range word {
+		if unicode.IsLetter(r) {
+			if unicode.IsUpper(r) {
+				return word
+			}
+			return word[:i] + string(unicode.ToUpper(r)) + word[i+utf8.RuneLen(r):]
+		}
+		if unicode.IsDigit(r) {
+			return word
+		}
+	}
+	return word
+}
+
+// normalizeWord lowercases a word and strips the punctuation Whisper adds
+func normalizeWord(word string) string {
+	word = strings.ToLower(word)
+	word = strings.ReplaceAll(word, "’", "'")
+	return strings.Trim(word, ".,;:!?…\"«»")
+}
@@ -0,0 +1,68 @@
+package textproc
+
+import "testing"
+
+func TestFormatter_FrenchCommands(t *testing.T) {
+	f := NewFormatter("fr")
+
+	got := f.Format("bonjour virgule comment ça va point d'interrogation à la ligne très bien point")
+	expected := "Bonjour, comment ça va\u00a0?\nTrès bien."
+	if got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestFormatter_LongestCommandWins(t *testing.T) {
+	if got := NewFormatter("fr").Format("un point virgule deux"); got != "Un\u00a0; deux" {
+		t.Errorf("Expected 'Un ; deux', got %q", got)
+	}
+}
+
+func TestFormatter_FrenchSpacing(t *testing.T) {
+	tests := map[string]string{
+		"Attention: il pleut!":  "Attention\u00a0: il pleut\u00a0!",
+		"Bonjour , ça va ?":     "Bonjour, ça va\u00a0?",
+		"Il est 14:30.":         "Il est 14:30.",
+		"Vraiment?! c'est fou.": "Vraiment\u00a0?! C'est fou.",
+	}
+	for text, expected := range tests {
+		if got := NewFormatter("fr").Format(text); got != expected {
+			t.Errorf("%q: expected %q, got %q", text, expected, got)
+		}
+	}
+}
+
+func TestFormatter_EnglishCommands(t *testing.T) {
+	got := NewFormatter("en").Format("Hello comma world. Period. New line open parenthesis see below close parenthesis")
+	expected := "Hello, world.\n(See below)"
+	if got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestFormatter_CapitalizationAcrossUtterances(t *testing.T) {
+	f := NewFormatter("en")
+
+	expected := []string{"Hello there", " and welcome.", " Next one", "?", " «Quoted» text"}
+	for i, text := range []string{"hello there", "and welcome.", "next one", "question mark", "«quoted» text"} {
+		if got := f.Format(text); got != expected[i] {
+			t.Errorf("Utterance %d: expected %q, got %q", i, expected[i], got)
+		}
+	}
+}
+
+func TestFormatter_UnknownLanguage(t *testing.T) {
+	if got := NewFormatter("de").Format("hallo Komma Welt , gut"); got != "Hallo Komma Welt, gut" {
+		t.Errorf("Expected only the generic rules, got %q", got)
+	}
+}
+
+func TestFormatter_SetLanguage(t *testing.T) {
+	f := NewFormatter("en")
+	f.Format("Hello comma")
+
+	f.SetLanguage("fr")
+	if got := f.Format("ça va point d'interrogation"); got != " ça va\u00a0?" {
+		t.Errorf("Expected the French rules after what was typed, got %q", got)
+	}
+}
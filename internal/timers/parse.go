@@ -0,0 +1,253 @@
+package timers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/nerzhul/nrz-ai/internal/i18n"
+)
+
+// numberWords maps spelled-out numbers, French and English, to their value.
+// Consecutive number words are added, so "vingt cinq" is 25.
+var numberWords = map[string]int{
+	"un": 1, "une": 1, "deux": 2, "trois": 3, "quatre": 4, "cinq": 5, "six": 6,
+	"sept": 7, "huit": 8, "neuf": 9, "dix": 10, "onze": 11, "douze": 12,
+	"treize": 13, "quatorze": 14, "quinze": 15, "seize": 16, "vingt": 20,
+	"trente": 30, "quarante": 40, "cinquante": 50, "soixante": 60,
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11,
+	"twelve": 12, "fifteen": 15, "twenty": 20, "thirty": 30, "forty": 40,
+	"fifty": 50, "sixty": 60,
+}
+
+// unitWords maps duration units to their length
+var unitWords = map[string]time.Duration{
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "seconde": time.Second,
+	"secondes": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "mn": time.Minute, "min": time.Minute, "mins": time.Minute,
+	"minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "heure": time.Hour, "heures": time.Hour, "hour": time.Hour,
+	"hours": time.Hour, "hr": time.Hour, "hrs": time.Hour,
+}
+
+// tokenize lowercases text and splits words, separating digits from letters
+// so that "1h30" gives "1", "h", "30"
+func tokenize(text string) []string {
+	var tokens []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			tokens = append(tokens, string(current))
+			current = current[:0]
+		}
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsDigit(r):
+			if len(current) > 0 && !unicode.IsDigit(current[len(current)-1]) {
+				flush()
+			}
+			current = append(current, r)
+		case unicode.IsLetter(r):
+			if len(current) > 0 && unicode.IsDigit(current[len(current)-1]) {
+				flush()
+			}
+			current = append(current, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return tokens
+}
+
+// number returns the value of a digit or number word token
+func number(token string) (int, bool) {
+	if n, err := strconv.Atoi(token); err == nil {
+		return n, true
+	}
+	n, ok := numberWords[token]
+	return n, ok
+}
+
+// ParseDuration reads a spoken duration such as "5 minutes", "une heure et
+// demie", "1h30", "un quart d'heure" or "two hours and ten minutes"
+func ParseDuration(text string) (time.Duration, error) {
+	tokens := tokenize(text)
+
+	var total time.Duration
+	var lastUnit time.Duration
+	pending, hasPending := 0, false
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+
+		if n, ok := number(token); ok {
+			// "vingt cinq" adds up, "a"/"an" only count before a unit
+			pending += n
+			hasPending = true
+			continue
+		}
+
+		if unit, ok := unitWords[token]; ok {
+			if !hasPending {
+				pending = 1
+			}
+			total += time.Duration(pending) * unit
+			lastUnit = unit
+			pending, hasPending = 0, false
+			continue
+		}
+
+		switch token {
+		case "demie", "demi", "half":
+			if i+1 < len(tokens) {
+				if unit, ok := unitWords[tokens[i+1]]; ok {
+					// "une demi-heure", "half an hour"
+					total += unit / 2
+					lastUnit = unit
+					i++
+					pending, hasPending = 0, false
+					continue
+				}
+			}
+			if lastUnit > 0 {
+				// "une heure et demie", "an hour and a half"
+				total += lastUnit / 2
+			} else if i+2 < len(tokens) && tokens[i+1] == "an" {
+				if unit, ok := unitWords[tokens[i+2]]; ok {
+					total += unit / 2
+					lastUnit = unit
+					i += 2
+				}
+			}
+			pending, hasPending = 0, false
+		case "quart", "quarter":
+			// "un quart d'heure", "a quarter of an hour"
+			total += 15 * time.Minute
+			lastUnit = time.Hour
+			pending, hasPending = 0, false
+			for i+1 < len(tokens) && (tokens[i+1] == "d" || tokens[i+1] == "of" || tokens[i+1] == "an" || unitWords[tokens[i+1]] == time.Hour) {
+				i++
+			}
+		}
+	}
+
+	// "1 h 30": a trailing number is in the unit below the last one
+	if hasPending && pending > 0 {
+		switch lastUnit {
+		case time.Hour:
+			total += time.Duration(pending) * time.Minute
+		case time.Minute:
+			total += time.Duration(pending) * time.Second
+		}
+	}
+
+	if total <= 0 {
+		return 0, fmt.Errorf("no duration found in '%s'", text)
+	}
+	return total, nil
+}
+
+// ParseTime reads a spoken time of day such as "15 h 30", "8 heures",
+// "midi", "3 pm" or "15:30", returning its next occurrence after now
+func ParseTime(text string, now time.Time) (time.Time, error) {
+	tokens := tokenize(text)
+
+	hour, minute := -1, 0
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch token {
+		case "midi", "noon":
+			hour = 12
+			continue
+		case "minuit", "midnight":
+			hour = 0
+			continue
+		case "pm":
+			if hour >= 0 && hour < 12 {
+				hour += 12
+			}
+			continue
+		case "am":
+			if hour == 12 {
+				hour = 0
+			}
+			continue
+		case "et":
+			// "midi et demi", "huit heures et quart"
+			if i+1 < len(tokens) && hour >= 0 {
+				switch tokens[i+1] {
+				case "demi", "demie":
+					minute = 30
+					i++
+				case "quart":
+					minute = 15
+					i++
+				}
+			}
+			continue
+		}
+
+		n, ok := number(token)
+		if !ok {
+			continue
+		}
+		if hour < 0 {
+			hour = n
+		} else {
+			minute = n
+		}
+	}
+
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return time.Time{}, fmt.Errorf("no time of day found in '%s'", text)
+	}
+
+	due := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	if !due.After(now) {
+		due = due.AddDate(0, 0, 1)
+	}
+	return due, nil
+}
+
+// FormatDuration spells a duration for replies, e.g. "1 heure et 30 minutes"
+func FormatDuration(d time.Duration, language string) string {
+	d = d.Round(time.Second)
+	hours := int(d / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	seconds := int(d % time.Minute / time.Second)
+
+	var parts []string
+	for _, part := range []struct {
+		value int
+		unit  string
+	}{{hours, "hour"}, {minutes, "minute"}, {seconds, "second"}} {
+		if part.value == 0 {
+			continue
+		}
+		name := i18n.T(language, "duration."+part.unit+"s")
+		if part.value == 1 {
+			name = i18n.T(language, "duration."+part.unit)
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", part.value, name))
+	}
+
+	switch len(parts) {
+	case 0:
+		return "0 " + i18n.T(language, "duration.seconds")
+	case 1:
+		return parts[0]
+	default:
+		return strings.Join(parts[:len(parts)-1], ", ") + " " + i18n.T(language, "duration.and") + " " + parts[len(parts)-1]
+	}
+}
+
+// FormatClock formats a time of day for replies
+func FormatClock(t time.Time, language string) string {
+	return i18n.Clock(language, t)
+}
@@ -0,0 +1,134 @@
+package timers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nerzhul/nrz-ai/internal/i18n"
+	"github.com/nerzhul/nrz-ai/internal/intents"
+)
+
+// Intents returns the intents of the timers skill, to add to the router
+func Intents() []intents.Intent {
+	return []intents.Intent{
+		{
+			Name: "timer",
+			Patterns: []string{
+				"(?:mets|lance|démarre|programme) un minuteur (?:de|pour|dans) {duration}",
+				"minuteur (?:de|pour) {duration}",
+				"set a timer for {duration}",
+				"start a timer for {duration}",
+			},
+			Handler: "timer",
+		},
+		{
+			Name: "reminder",
+			Patterns: []string{
+				"rappelle-moi (?:de |d')?{task} dans {duration}",
+				"rappelle-moi dans {duration} (?:de |d')?{task}",
+				"rappelle-moi (?:de |d')?{task} à {time}",
+				"remind me to {task} in {duration}",
+				"remind me in {duration} to {task}",
+				"remind me to {task} at {time}",
+			},
+			Handler: "reminder",
+		},
+		{
+			Name:     "timers_list",
+			Patterns: []string{"quels sont mes (?:minuteurs|rappels)", "liste (?:les|mes) (?:minuteurs|rappels)", "(?:list|what are) my (?:timers|reminders)"},
+			Handler:  "timers_list",
+		},
+		{
+			Name:     "timers_cancel",
+			Patterns: []string{"annule (?:le|les|mes) (?:minuteurs?|rappels?)", "cancel (?:the|my|all) (?:timers?|reminders?)"},
+			Handler:  "timers_cancel",
+		},
+	}
+}
+
+// RegisterHandlers registers the timers skill handlers on router
+func RegisterHandlers(router *intents.Router, m *Manager) {
+	router.Register("timer", intents.HandlerFunc(m.handleTimer))
+	router.Register("reminder", intents.HandlerFunc(m.handleReminder))
+	router.Register("timers_list", intents.HandlerFunc(m.handleList))
+	router.Register("timers_cancel", intents.HandlerFunc(m.handleCancel))
+}
+
+// handleTimer starts a countdown
+func (m *Manager) handleTimer(match intents.Match) (string, error) {
+	duration, err := ParseDuration(match.Slots["duration"])
+	if err != nil {
+		return i18n.T(match.Language, "timers.bad_duration"), nil
+	}
+
+	if _, err := m.Add(KindTimer, "", duration, m.now().Add(duration)); err != nil {
+		return "", err
+	}
+	return i18n.T(match.Language, "timers.started", FormatDuration(duration, match.Language)), nil
+}
+
+// handleReminder schedules a reminder after a duration or at a time of day
+func (m *Manager) handleReminder(match intents.Match) (string, error) {
+	task := strings.TrimSpace(match.Slots["task"])
+	if task == "" {
+		return i18n.T(match.Language, "timers.reminder_what"), nil
+	}
+
+	now := m.now()
+	due := now
+	if text, ok := match.Slots["duration"]; ok {
+		duration, err := ParseDuration(text)
+		if err != nil {
+			return i18n.T(match.Language, "timers.bad_when"), nil
+		}
+		due = now.Add(duration)
+	} else {
+		at, err := ParseTime(match.Slots["time"], now)
+		if err != nil {
+			return i18n.T(match.Language, "timers.bad_time"), nil
+		}
+		due = at
+	}
+
+	if _, err := m.Add(KindReminder, task, 0, due); err != nil {
+		return "", err
+	}
+	// The French reply elides "de" before the task, "d'appeler"
+	return i18n.T(match.Language, "timers.reminder_set", elide("de", task), task, FormatClock(due, match.Language)), nil
+}
+
+// handleList lists the pending timers
+func (m *Manager) handleList(match intents.Match) (string, error) {
+	pending := m.Pending()
+	if len(pending) == 0 {
+		return i18n.T(match.Language, "timers.none"), nil
+	}
+
+	var items []string
+	for _, t := range pending {
+		clock := FormatClock(t.Due, match.Language)
+		if t.Kind == KindReminder {
+			items = append(items, fmt.Sprintf("%s (%s)", t.Label, clock))
+		} else {
+			items = append(items, i18n.T(match.Language, "timers.item", FormatDuration(t.Duration, match.Language), clock))
+		}
+	}
+	return strings.Join(items, ", ") + ".", nil
+}
+
+// handleCancel cancels every pending timer
+func (m *Manager) handleCancel(match intents.Match) (string, error) {
+	count, err := m.CancelAll()
+	if err != nil {
+		return "", err
+	}
+	return i18n.T(match.Language, "timers.cancelled", count), nil
+}
+
+// elide joins a French preposition to a word, "de appeler" becoming "d'appeler"
+func elide(preposition, word string) string {
+	if word != "" && strings.ContainsRune("aeiouyhàâéèêîôû", []rune(strings.ToLower(word))[0]) {
+		return preposition[:len(preposition)-1] + "'" + word
+	}
+	return preposition + " " + word
+}
@@ -0,0 +1,205 @@
+package timers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/i18n"
+	"github.com/nerzhul/nrz-ai/internal/privacy"
+)
+
+// Kind distinguishes countdown timers from reminders
+type Kind string
+
+const (
+	KindTimer    Kind = "timer"
+	KindReminder Kind = "reminder"
+)
+
+// Timer is a pending timer or reminder
+type Timer struct {
+	ID       int           `json:"id"`
+	Kind     Kind          `json:"kind"`
+	Label    string        `json:"label,omitempty"`    // What to remind, empty for timers
+	Duration time.Duration `json:"duration,omitempty"` // Countdown length of timers
+	Due      time.Time     `json:"due"`
+}
+
+// Message returns the announcement of a fired timer
+func (t Timer) Message(language string) string {
+	if t.Kind == KindReminder {
+		return i18n.T(language, "timers.reminder", t.Label)
+	}
+	return i18n.T(language, "timers.done", FormatDuration(t.Duration, language))
+}
+
+// Manager schedules timers and persists the pending ones to a JSON file, so
+// they survive restarts. Timers due while nrz-ai was stopped fire on Start.
+type Manager struct {
+	path    string
+	pending map[int]Timer
+	running map[int]*time.Timer
+	nextID  int
+	onFire  func(Timer)
+	now     func() time.Time
+	mutex   sync.Mutex
+}
+
+// NewManager loads the pending timers saved at path
+func NewManager(path string) (*Manager, error) {
+	m := &Manager{
+		path:    path,
+		pending: make(map[int]Timer),
+		running: make(map[int]*time.Timer),
+		nextID:  1,
+		now:     time.Now,
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var saved []Timer
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, t := range saved {
+		m.pending[t.ID] = t
+		if t.ID >= m.nextID {
+			m.nextID = t.ID + 1
+		}
+	}
+	return m, nil
+}
+
+// Start schedules the pending timers, onFire is called when each one is due
+func (m *Manager) Start(onFire func(Timer)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.onFire = onFire
+	for _, t := range m.pending {
+		m.scheduleLocked(t)
+	}
+}
+
+// Add schedules a new timer and saves it
+func (m *Manager) Add(kind Kind, label string, duration time.Duration, due time.Time) (Timer, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	t := Timer{ID: m.nextID, Kind: kind, Label: label, Duration: duration, Due: due}
+	m.nextID++
+	m.pending[t.ID] = t
+
+	if err := m.saveLocked(); err != nil {
+		delete(m.pending, t.ID)
+		return Timer{}, err
+	}
+	if m.onFire != nil {
+		m.scheduleLocked(t)
+	}
+	return t, nil
+}
+
+// Pending returns the pending timers, soonest first
+func (m *Manager) Pending() []Timer {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	timers := make([]Timer, 0, len(m.pending))
+	for _, t := range m.pending {
+		timers = append(timers, t)
+	}
+	sort.Slice(timers, func(i, j int) bool { return timers[i].Due.Before(timers[j].Due) })
+	return timers
+}
+
+// CancelAll cancels every pending timer and returns how many there were
+func (m *Manager) CancelAll() (int, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	count := len(m.pending)
+	for id, timer := range m.running {
+		timer.Stop()
+		delete(m.running, id)
+	}
+	m.pending = make(map[int]Timer)
+	return count, m.saveLocked()
+}
+
+// Close stops the scheduled timers, pending ones stay saved for the next run
+func (m *Manager) Close() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for id, timer := range m.running {
+		timer.Stop()
+		delete(m.running, id)
+	}
+	m.onFire = nil
+}
+
+// scheduleLocked arms a timer, immediately if it is overdue. Caller holds the mutex.
+func (m *Manager) scheduleLocked(t Timer) {
+	delay := t.Due.Sub(m.now())
+	if delay < 0 {
+		delay = 0
+	}
+	m.running[t.ID] = time.AfterFunc(delay, func() { m.fire(t.ID) })
+}
+
+// fire removes a due timer and announces it
+func (m *Manager) fire(id int) {
+	m.mutex.Lock()
+	t, ok := m.pending[id]
+	onFire := m.onFire
+	if ok {
+		delete(m.pending, id)
+		delete(m.running, id)
+		m.saveLocked()
+	}
+	m.mutex.Unlock()
+
+	if ok && onFire != nil {
+		onFire(t)
+	}
+}
+
+// saveLocked atomically rewrites the state file. Caller holds the mutex.
+func (m *Manager) saveLocked() error {
+	// Reminders hold what was said, they only live in memory in privacy mode
+	if privacy.Enabled() {
+		return nil
+	}
+	timers := make([]Timer, 0, len(m.pending))
+	for _, t := range m.pending {
+		timers = append(timers, t)
+	}
+	sort.Slice(timers, func(i, j int) bool { return timers[i].ID < timers[j].ID })
+
+	data, err := json.MarshalIndent(timers, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return err
+	}
+
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, m.path)
+}
@@ -0,0 +1,179 @@
+package timers
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/intents"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"5 minutes":                 5 * time.Minute,
+		"cinq minutes":              5 * time.Minute,
+		"vingt-cinq minutes":        25 * time.Minute,
+		"une heure et demie":        90 * time.Minute,
+		"1h30":                      90 * time.Minute,
+		"1 h 30":                    90 * time.Minute,
+		"une demi-heure":            30 * time.Minute,
+		"un quart d'heure":          15 * time.Minute,
+		"2 minutes et 30 secondes":  150 * time.Second,
+		"two hours and ten minutes": 130 * time.Minute,
+		"an hour and a half":        90 * time.Minute,
+		"half an hour":              30 * time.Minute,
+		"a quarter of an hour":      15 * time.Minute,
+		"quarante-cinq secondes":    45 * time.Second,
+		"3 min":                     3 * time.Minute,
+	}
+	for text, expected := range tests {
+		duration, err := ParseDuration(text)
+		if err != nil {
+			t.Errorf("Expected no error for %q, got: %v", text, err)
+			continue
+		}
+		if duration != expected {
+			t.Errorf("Expected %s for %q, got %s", expected, text, duration)
+		}
+	}
+
+	if _, err := ParseDuration("bientôt"); err == nil {
+		t.Error("Expected error without duration")
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2026, 10, 14, 14, 0, 0, 0, time.Local)
+
+	tests := map[string]time.Time{
+		"15 h 30":              time.Date(2026, 10, 14, 15, 30, 0, 0, time.Local),
+		"8 heures":             time.Date(2026, 10, 15, 8, 0, 0, 0, time.Local),
+		"midi et demi":         time.Date(2026, 10, 15, 12, 30, 0, 0, time.Local),
+		"huit heures et quart": time.Date(2026, 10, 15, 8, 15, 0, 0, time.Local),
+		"3 pm":                 time.Date(2026, 10, 14, 15, 0, 0, 0, time.Local),
+		"17 45":                time.Date(2026, 10, 14, 17, 45, 0, 0, time.Local),
+	}
+	for text, expected := range tests {
+		due, err := ParseTime(text, now)
+		if err != nil {
+			t.Errorf("Expected no error for %q, got: %v", text, err)
+			continue
+		}
+		if !due.Equal(expected) {
+			t.Errorf("Expected %s for %q, got %s", expected, text, due)
+		}
+	}
+
+	if _, err := ParseTime("25 h", now); err == nil {
+		t.Error("Expected error for invalid hour")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	if got := FormatDuration(90*time.Minute, "fr"); got != "1 heure et 30 minutes" {
+		t.Errorf("Unexpected French duration %q", got)
+	}
+	if got := FormatDuration(2*time.Hour+time.Minute+5*time.Second, "en"); got != "2 hours, 1 minute and 5 seconds" {
+		t.Errorf("Unexpected English duration %q", got)
+	}
+}
+
+func TestManager_FiresAndPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timers.json")
+
+	m, err := NewManager(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	fired := make(chan Timer, 2)
+	m.Start(func(timer Timer) { fired <- timer })
+
+	m.Add(KindTimer, "", time.Millisecond, time.Now().Add(10*time.Millisecond))
+	m.Add(KindReminder, "sortir le chat", 0, time.Now().Add(time.Hour))
+
+	select {
+	case timer := <-fired:
+		if timer.Kind != KindTimer {
+			t.Errorf("Expected the timer to fire first, got %+v", timer)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected timer to fire")
+	}
+	m.Close()
+
+	// The reminder is still pending after a restart
+	restarted, err := NewManager(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	pending := restarted.Pending()
+	if len(pending) != 1 || pending[0].Label != "sortir le chat" {
+		t.Fatalf("Expected the reminder to be saved, got %+v", pending)
+	}
+	if timer, _ := restarted.Add(KindTimer, "", time.Minute, time.Now().Add(time.Minute)); timer.ID != 3 {
+		t.Errorf("Expected IDs to continue after restart, got %d", timer.ID)
+	}
+}
+
+func TestManager_FiresOverdueOnStart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timers.json")
+
+	m, _ := NewManager(path)
+	m.Add(KindReminder, "appeler Marie", 0, time.Now().Add(-time.Minute))
+
+	restarted, _ := NewManager(path)
+	fired := make(chan Timer, 1)
+	restarted.Start(func(timer Timer) { fired <- timer })
+	defer restarted.Close()
+
+	select {
+	case timer := <-fired:
+		if timer.Message("fr") != "Rappel : appeler Marie" {
+			t.Errorf("Unexpected message %q", timer.Message("fr"))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected overdue reminder to fire on start")
+	}
+	if len(restarted.Pending()) != 0 {
+		t.Error("Expected fired reminder to be removed")
+	}
+}
+
+func TestSkill_Intents(t *testing.T) {
+	m, _ := NewManager(filepath.Join(t.TempDir(), "timers.json"))
+	m.now = func() time.Time { return time.Date(2026, 10, 14, 14, 0, 0, 0, time.Local) }
+	m.Start(func(Timer) {})
+	defer m.Close()
+
+	router, err := intents.NewRouter(Intents(), "fr")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	RegisterHandlers(router, m)
+	if err := router.Validate(); err != nil {
+		t.Fatalf("Expected all handlers registered, got: %v", err)
+	}
+
+	tests := map[string]string{
+		"Mets un minuteur de 5 minutes.":                "Minuteur de 5 minutes lancé.",
+		"Rappelle-moi de sortir le chat dans une heure": "Je te rappellerai de sortir le chat à 15 h 00.",
+		"Rappelle-moi d'appeler Marie à 18 h 30":        "Je te rappellerai d'appeler Marie à 18 h 30.",
+		"Quels sont mes minuteurs ?":                    "minuteur de 5 minutes (14 h 05), sortir le chat (15 h 00), appeler Marie (18 h 30).",
+		"Annule les minuteurs":                          "3 minuteur(s) annulé(s).",
+	}
+	for _, text := range []string{
+		"Mets un minuteur de 5 minutes.",
+		"Rappelle-moi de sortir le chat dans une heure",
+		"Rappelle-moi d'appeler Marie à 18 h 30",
+		"Quels sont mes minuteurs ?",
+		"Annule les minuteurs",
+	} {
+		reply, handled, err := router.Handle(text)
+		if err != nil || !handled {
+			t.Errorf("Expected %q to be handled, got handled=%v err=%v", text, handled, err)
+		}
+		if reply != tests[text] {
+			t.Errorf("Expected %q for %q, got %q", tests[text], text, reply)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package transcript
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/nerzhul/nrz-ai/internal/privacy"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+// CaptionWriter appends live SRT or WebVTT cues to a file as segments finalize.
+// Segment times are offsets from the start of the stream.
+type CaptionWriter struct {
+	file   *os.File
+	format Format
+	index  int
+	mutex  sync.Mutex
+}
+
+// NewCaptionWriter creates the caption file, the format is taken from the
+// .srt or .vtt extension
+func NewCaptionWriter(path string) (*CaptionWriter, error) {
+	format, err := ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+	if err != nil || (format != FormatSRT && format != FormatVTT) {
+		return nil, fmt.Errorf("captions file '%s' must end with .srt or .vtt", path)
+	}
+
+	if err := privacy.Check("live captions"); err != nil {
+		return nil, err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == FormatVTT {
+		if _, err := fmt.Fprint(file, "WEBVTT\n\n"); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+
+	return &CaptionWriter{
+		file:   file,
+		format: format,
+		index:  1,
+	}, nil
+}
+
+// Path returns the caption file path
+func (c *CaptionWriter) Path() string {
+	return c.file.Name()
+}
+
+// Note adds a comment block to WebVTT captions, such as the wall clock time
+// the cue offsets count from. SRT has no comments, nothing is written.
+func (c *CaptionWriter) Note(text string) error {
+	if c.format != FormatVTT {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	_, err := fmt.Fprintf(c.file, "NOTE %s\n\n", strings.ReplaceAll(text, "\n\n", "\n"))
+	return err
+}
+
+// WriteSegments appends one cue per non-empty segment
+func (c *CaptionWriter) WriteSegments(segments []whisper.Segment) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, segment := range segments {
+		if strings.TrimSpace(segment.Text) == "" {
+			continue
+		}
+
+		var err error
+		if c.format == FormatSRT {
+			err = writeSRTCue(c.file, c.index, segment)
+		} else {
+			err = writeVTTCue(c.file, segment)
+		}
+		if err != nil {
+			return err
+		}
+		c.index++
+	}
+	return nil
+}
+
+// Close closes the caption file
+func (c *CaptionWriter) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.file.Close()
+}
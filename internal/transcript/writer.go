@@ -0,0 +1,170 @@
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+// Format identifies a transcript output format
+type Format string
+
+const (
+	FormatText Format = "txt"
+	FormatJSON Format = "json"
+	FormatSRT  Format = "srt"
+	FormatVTT  Format = "vtt"
+)
+
+// ParseFormat validates a transcript format name
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(name)) {
+	case FormatText, FormatJSON, FormatSRT, FormatVTT:
+		return Format(strings.ToLower(name)), nil
+	default:
+		return "", fmt.Errorf("unknown transcript format '%s' (expected txt, json, srt or vtt)", name)
+	}
+}
+
+// jsonSegment is the JSON representation of a transcript segment
+type jsonSegment struct {
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Text    string  `json:"text"`
+	Speaker string  `json:"speaker,omitempty"`
+}
+
+// jsonTranscript is the JSON representation of a transcription result
+type jsonTranscript struct {
+	Text     string        `json:"text"`
+	Language string        `json:"language"`
+	Duration float64       `json:"duration"`
+	Segments []jsonSegment `json:"segments"`
+}
+
+// Write writes a transcription result to w in the given format
+func Write(w io.Writer, result whisper.TranscriptionResult, format Format) error {
+	switch format {
+	case FormatText:
+		return writeText(w, result)
+	case FormatJSON:
+		return writeJSON(w, result)
+	case FormatSRT:
+		return writeSRT(w, result)
+	case FormatVTT:
+		return writeVTT(w, result)
+	default:
+		return fmt.Errorf("unsupported transcript format '%s'", format)
+	}
+}
+
+// writeText writes one trimmed segment per line, prefixed by its speaker
+func writeText(w io.Writer, result whisper.TranscriptionResult) error {
+	for _, segment := range result.Segments {
+		text := strings.TrimSpace(segment.Text)
+		if text == "" {
+			continue
+		}
+		if segment.Speaker != "" {
+			text = segment.Speaker + ": " + text
+		}
+		if _, err := fmt.Fprintln(w, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeJSON writes the full result as an indented JSON document
+func writeJSON(w io.Writer, result whisper.TranscriptionResult) error {
+	doc := jsonTranscript{
+		Text:     strings.TrimSpace(result.Text),
+		Language: result.Language,
+		Duration: result.Duration,
+		Segments: make([]jsonSegment, 0, len(result.Segments)),
+	}
+
+	for _, segment := range result.Segments {
+		doc.Segments = append(doc.Segments, jsonSegment{
+			Start:   segment.Start,
+			End:     segment.End,
+			Text:    strings.TrimSpace(segment.Text),
+			Speaker: segment.Speaker,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// writeSRT writes SubRip cues
+func writeSRT(w io.Writer, result whisper.TranscriptionResult) error {
+	index := 1
+	for _, segment := range result.Segments {
+		if strings.TrimSpace(segment.Text) == "" {
+			continue
+		}
+		if err := writeSRTCue(w, index, segment); err != nil {
+			return err
+		}
+		index++
+	}
+	return nil
+}
+
+// writeVTT writes WebVTT cues
+func writeVTT(w io.Writer, result whisper.TranscriptionResult) error {
+	if _, err := fmt.Fprint(w, "WEBVTT\n\n"); err != nil {
+		return err
+	}
+
+	for _, segment := range result.Segments {
+		if strings.TrimSpace(segment.Text) == "" {
+			continue
+		}
+		if err := writeVTTCue(w, segment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSRTCue writes a single SubRip cue
+func writeSRTCue(w io.Writer, index int, segment whisper.Segment) error {
+	text := strings.TrimSpace(segment.Text)
+	if segment.Speaker != "" {
+		text = "[" + segment.Speaker + "] " + text
+	}
+	_, err := fmt.Fprintf(w, "%d\n%s --> %s\n%s\n\n", index,
+		formatTimestamp(segment.Start, ","), formatTimestamp(segment.End, ","), text)
+	return err
+}
+
+// writeVTTCue writes a single WebVTT cue
+func writeVTTCue(w io.Writer, segment whisper.Segment) error {
+	text := strings.TrimSpace(segment.Text)
+	if segment.Speaker != "" {
+		// WebVTT voice span
+		text = "<v " + segment.Speaker + ">" + text
+	}
+	_, err := fmt.Fprintf(w, "%s --> %s\n%s\n\n",
+		formatTimestamp(segment.Start, "."), formatTimestamp(segment.End, "."), text)
+	return err
+}
+
+// formatTimestamp formats seconds as HH:MM:SS<sep>mmm
+func formatTimestamp(seconds float64, separator string) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	totalMs := int64(seconds*1000 + 0.5)
+	hours := totalMs / 3600000
+	minutes := (totalMs % 3600000) / 60000
+	secs := (totalMs % 60000) / 1000
+	ms := totalMs % 1000
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", hours, minutes, secs, separator, ms)
+}
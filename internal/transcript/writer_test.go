@@ -0,0 +1,167 @@
+package transcript
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+func testResult() whisper.TranscriptionResult {
+	return whisper.TranscriptionResult{
+		Text:     " Bonjour le monde. Comment ça va ?",
+		Language: "fr",
+		Duration: 4.2,
+		Segments: []whisper.Segment{
+			{Text: " Bonjour le monde.", Start: 0.0, End: 1.5},
+			{Text: " ", Start: 1.5, End: 2.0, NoSpeech: true},
+			{Text: " Comment ça va ?", Start: 2.0, End: 3725.25},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("SRT")
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if format != FormatSRT {
+		t.Errorf("Expected format 'srt', got '%s'", format)
+	}
+
+	if _, err := ParseFormat("docx"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestWrite_Text(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testResult(), FormatText); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := "Bonjour le monde.\nComment ça va ?\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestWrite_SRT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testResult(), FormatSRT); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := "1\n00:00:00,000 --> 00:00:01,500\nBonjour le monde.\n\n" +
+		"2\n00:00:02,000 --> 01:02:05,250\nComment ça va ?\n\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestWrite_VTT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testResult(), FormatVTT); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "WEBVTT\n\n") {
+		t.Errorf("Expected WEBVTT header, got %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "00:00:02.000 --> 01:02:05.250\nComment ça va ?") {
+		t.Errorf("Expected dot-separated cue timestamps, got %q", buf.String())
+	}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testResult(), FormatJSON); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var doc jsonTranscript
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+
+	if doc.Language != "fr" {
+		t.Errorf("Expected language 'fr', got '%s'", doc.Language)
+	}
+	if len(doc.Segments) != 3 {
+		t.Errorf("Expected 3 segments, got %d", len(doc.Segments))
+	}
+	if doc.Segments[0].Text != "Bonjour le monde." {
+		t.Errorf("Expected trimmed segment text, got '%s'", doc.Segments[0].Text)
+	}
+}
+
+func TestWrite_SpeakerLabels(t *testing.T) {
+	result := testResult()
+	result.Segments[0].Speaker = "Speaker 1"
+	result.Segments[2].Speaker = "Speaker 2"
+
+	var text, srt, vtt bytes.Buffer
+	Write(&text, result, FormatText)
+	Write(&srt, result, FormatSRT)
+	Write(&vtt, result, FormatVTT)
+
+	if !strings.HasPrefix(text.String(), "Speaker 1: Bonjour le monde.\n") {
+		t.Errorf("Expected speaker prefix in text, got %q", text.String())
+	}
+	if !strings.Contains(srt.String(), "[Speaker 2] Comment ça va ?") {
+		t.Errorf("Expected speaker tag in SRT, got %q", srt.String())
+	}
+	if !strings.Contains(vtt.String(), "<v Speaker 1>Bonjour le monde.") {
+		t.Errorf("Expected voice span in VTT, got %q", vtt.String())
+	}
+}
+
+func TestCaptionWriter_AppendsCues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "live.srt")
+
+	captions, err := NewCaptionWriter(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	captions.Note("SRT has no comments")
+	captions.WriteSegments([]whisper.Segment{{Text: " Bonjour.", Start: 1.0, End: 2.0}})
+	captions.WriteSegments([]whisper.Segment{
+		{Text: " ", Start: 5.0, End: 5.5},
+		{Text: " Au revoir.", Start: 61.5, End: 63.0},
+	})
+	captions.Close()
+
+	data, _ := os.ReadFile(path)
+	expected := "1\n00:00:01,000 --> 00:00:02,000\nBonjour.\n\n" +
+		"2\n00:01:01,500 --> 00:01:03,000\nAu revoir.\n\n"
+	if string(data) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(data))
+	}
+}
+
+func TestCaptionWriter_VTTHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "live.vtt")
+
+	captions, err := NewCaptionWriter(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	captions.Note("Started 2026-03-01T15:04:12+01:00")
+	captions.WriteSegments([]whisper.Segment{{Text: "Salut", Start: 0.5, End: 1.0}})
+	captions.Close()
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "WEBVTT\n\nNOTE Started 2026-03-01T15:04:12+01:00\n\n00:00:00.500 --> 00:00:01.000\nSalut\n\n" {
+		t.Errorf("Unexpected VTT captions: %q", string(data))
+	}
+}
+
+func TestCaptionWriter_RejectsOtherFormats(t *testing.T) {
+	if _, err := NewCaptionWriter(filepath.Join(t.TempDir(), "live.json")); err == nil {
+		t.Error("Expected error for non-caption extension")
+	}
+}
@@ -0,0 +1,123 @@
+// Package translate translates the utterances of the translate mode, with
+// the AI backend or with Whisper for English
+package translate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nerzhul/nrz-ai/internal/i18n"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+// Translator backends
+const (
+	BackendAuto    = "auto"    // Whisper for English, else the AI
+	BackendAI      = "ai"      // The AI backend, any target language
+	BackendWhisper = "whisper" // Whisper translation, English only
+)
+
+// Translator translates an utterance into its target language
+type Translator interface {
+	// Translate returns text, spoken in language and recorded in samples,
+	// in the target language
+	Translate(ctx context.Context, samples []float32, text, language string) (string, error)
+
+	// Target returns the target language code
+	Target() string
+}
+
+// ParseBackend validates a translator backend name for target
+func ParseBackend(name, target string) (string, error) {
+	switch strings.ToLower(name) {
+	case "", BackendAuto:
+		if target == "en" {
+			return BackendWhisper, nil
+		}
+		return BackendAI, nil
+	case BackendAI:
+		return BackendAI, nil
+	case BackendWhisper:
+		if target != "en" {
+			return "", fmt.Errorf("whisper only translates into English, not '%s' (use the ai backend)", target)
+		}
+		return BackendWhisper, nil
+	default:
+		return "", fmt.Errorf("unknown translation backend '%s' (expected auto, ai or whisper)", name)
+	}
+}
+
+// AITranslator asks the AI backend for translations
+type AITranslator struct {
+	service ai.AIService
+	target  string
+}
+
+// NewAITranslator creates a translator into target using service
+func NewAITranslator(service ai.AIService, target string) *AITranslator {
+	return &AITranslator{service: service, target: target}
+}
+
+// Target returns the target language code
+func (t *AITranslator) Target() string {
+	return t.target
+}
+
+// Translate sends text to the AI with a translation prompt, the samples
+// are not used
+func (t *AITranslator) Translate(ctx context.Context, samples []float32, text, language string) (string, error) {
+	prompt := fmt.Sprintf("You are an interpreter. Translate the user message into %s. Reply with the translation only, without quotes, notes or explanations.", languageName(t.target))
+	if language != "" && language != "auto" {
+		prompt = fmt.Sprintf("You are an interpreter. Translate the user message from %s into %s. Reply with the translation only, without quotes, notes or explanations.", languageName(language), languageName(t.target))
+	}
+
+	response, err := t.service.Chat(ai.ChatRequest{
+		Messages: []ai.Message{
+			{Role: "system", Content: prompt},
+			{Role: "user", Content: text},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to translate: %w", err)
+	}
+	if response.Error != "" {
+		return "", fmt.Errorf("failed to translate: %s", response.Error)
+	}
+	return strings.TrimSpace(response.Message.Content), nil
+}
+
+// WhisperTranslator transcribes the utterances again, straight into English
+type WhisperTranslator struct {
+	service whisper.AudioTranslator
+}
+
+// NewWhisperTranslator creates a translator into English using service
+func NewWhisperTranslator(service whisper.AudioTranslator) *WhisperTranslator {
+	return &WhisperTranslator{service: service}
+}
+
+// Target returns en, the only language Whisper translates into
+func (t *WhisperTranslator) Target() string {
+	return "en"
+}
+
+// Translate transcribes samples into English, the text is not used
+func (t *WhisperTranslator) Translate(ctx context.Context, samples []float32, text, language string) (string, error) {
+	result, err := t.service.TranslateAudio(ctx, samples, language)
+	if err != nil {
+		return "", fmt.Errorf("failed to translate: %w", err)
+	}
+	return strings.TrimSpace(result.Text), nil
+}
+
+// languageName returns the English name of a language code, the code
+// itself for the languages without a name
+func languageName(code string) string {
+	key := "language." + code
+	if name := i18n.T("en", key); name != key {
+		return name
+	}
+	return code
+}
@@ -0,0 +1,73 @@
+package translate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+// promptRecorder answers every request and records the system prompts
+type promptRecorder struct {
+	*ai.MockAIService
+	prompts []string
+}
+
+func (p *promptRecorder) Chat(request ai.ChatRequest) (ai.ChatResponse, error) {
+	p.prompts = append(p.prompts, request.Messages[0].Content)
+	return p.MockAIService.Chat(request)
+}
+
+func TestParseBackend(t *testing.T) {
+	for _, test := range []struct {
+		name, target, expected string
+	}{
+		{"auto", "en", BackendWhisper},
+		{"", "de", BackendAI},
+		{"AI", "en", BackendAI},
+		{"whisper", "en", BackendWhisper},
+	} {
+		if backend, err := ParseBackend(test.name, test.target); err != nil || backend != test.expected {
+			t.Errorf("Expected %s for %q into %s, got %q (%v)", test.expected, test.name, test.target, backend, err)
+		}
+	}
+	if _, err := ParseBackend("whisper", "fr"); err == nil {
+		t.Error("Expected an error for Whisper into French")
+	}
+	if _, err := ParseBackend("deepl", "en"); err == nil {
+		t.Error("Expected an error for an unknown backend")
+	}
+}
+
+func TestAITranslator(t *testing.T) {
+	service := &promptRecorder{MockAIService: ai.NewMockAIService()}
+	service.SetResponses([]ai.ChatResponse{{Message: ai.Message{Role: "assistant", Content: " Guten Tag \n"}, Done: true}})
+	translator := NewAITranslator(service, "de")
+
+	translation, err := translator.Translate(context.Background(), nil, "Bonjour", "fr")
+	if err != nil || translation != "Guten Tag" {
+		t.Fatalf("Expected the trimmed translation, got %q (%v)", translation, err)
+	}
+	if !strings.Contains(service.prompts[0], "from French into German") {
+		t.Errorf("Expected the language names in the prompt, got %q", service.prompts[0])
+	}
+
+	translator.Translate(context.Background(), nil, "Bonjour", "auto")
+	if !strings.Contains(service.prompts[1], "into German") || strings.Contains(service.prompts[1], "from") {
+		t.Errorf("Expected no source language when detected, got %q", service.prompts[1])
+	}
+}
+
+func TestWhisperTranslator(t *testing.T) {
+	service := whisper.NewMockWhisperService()
+	service.LoadModel("test.bin")
+	service.SetTranslateResult(whisper.TranscriptionResult{Text: " Good morning"})
+	translator := NewWhisperTranslator(service)
+
+	translation, err := translator.Translate(context.Background(), make([]float32, 16000), "Bonjour", "fr")
+	if err != nil || translation != "Good morning" || translator.Target() != "en" {
+		t.Errorf("Expected the English transcription, got %q (%v)", translation, err)
+	}
+}
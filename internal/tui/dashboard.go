@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+// now timestamps events emitted without a time
+var now = time.Now
+
+// Dashboard runs the terminal UI and receives the pipeline events
+type Dashboard struct {
+	program *tea.Program
+}
+
+// NewDashboard creates a dashboard drawing on out
+func NewDashboard(info Info, controller Controller, out io.Writer) *Dashboard {
+	program := tea.NewProgram(NewModel(info, controller),
+		tea.WithOutput(out),
+		tea.WithAltScreen(),
+		// Ctrl-C is a key, so the session stops gracefully
+		tea.WithoutSignalHandler())
+	return &Dashboard{program: program}
+}
+
+// Run draws the dashboard until Quit
+func (d *Dashboard) Run() error {
+	_, err := d.program.Run()
+	return err
+}
+
+// Quit restores the terminal
+func (d *Dashboard) Quit() {
+	d.program.Quit()
+	d.program.Wait()
+}
+
+// Emit implements output.Emitter
+func (d *Dashboard) Emit(event output.Event) error {
+	if event.Time.IsZero() {
+		event.Time = now()
+	}
+	d.program.Send(eventMsg(event))
+	return nil
+}
+
+// Level reports the input level of the last audio chunk
+func (d *Dashboard) Level(level float32, calibrated bool) {
+	d.program.Send(levelMsg{level: level, calibrated: calibrated})
+}
+
+// LogWriter returns a writer showing each written line in the log pane
+func (d *Dashboard) LogWriter() io.Writer {
+	return &logWriter{send: func(line string) { d.program.Send(logMsg(line)) }}
+}
+
+// logWriter splits writes into lines
+type logWriter struct {
+	send   func(string)
+	buffer bytes.Buffer
+	mutex  sync.Mutex
+}
+
+// Write buffers data and sends complete lines
+func (w *logWriter) Write(data []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.buffer.Write(data)
+	for {
+		line, err := w.buffer.ReadString('\n')
+		if err != nil {
+			// Incomplete line, keep it for the next write
+			w.buffer.Reset()
+			w.buffer.WriteString(line)
+			return len(data), nil
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			w.send(line)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package tui
+
+// Controller is the part of the assistant driven from the keyboard
+type Controller interface {
+	// Pause stops processing audio until Resume
+	Pause()
+
+	// Resume restarts audio processing
+	Resume()
+
+	// ClearHistory forgets the AI conversation
+	ClearHistory()
+
+	// Stop ends the session once pending transcriptions are done
+	Stop()
+}
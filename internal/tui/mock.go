@@ -0,0 +1,35 @@
+package tui
+
+// MockController records the calls made by the dashboard
+type MockController struct {
+	Paused       bool
+	Cleared      int
+	Stopped      bool
+	ResumeCalled bool
+}
+
+// NewMockController creates a mock controller
+func NewMockController() *MockController {
+	return &MockController{}
+}
+
+// Pause records a pause
+func (m *MockController) Pause() {
+	m.Paused = true
+}
+
+// Resume records a resume
+func (m *MockController) Resume() {
+	m.Paused = false
+	m.ResumeCalled = true
+}
+
+// ClearHistory counts history clears
+func (m *MockController) ClearHistory() {
+	m.Cleared++
+}
+
+// Stop records the stop request
+func (m *MockController) Stop() {
+	m.Stopped = true
+}
@@ -0,0 +1,275 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nerzhul/nrz-ai/pkg/assistant"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+const (
+	// maxLines bounds the transcript and log history kept in memory
+	maxLines = 200
+	// meterWidth is the number of cells of the level meter
+	meterWidth = 30
+	// meterScale maps an RMS level to a full meter
+	meterScale = 0.2
+)
+
+var (
+	titleStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	labelStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	youStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+	aiStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	errorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	speechStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Bold(true)
+	sectionStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	stateStyles = map[assistant.State]lipgloss.Style{
+		assistant.StateActive:        lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Bold(true),
+		assistant.StateTranscribing:  lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Bold(true),
+		assistant.StateThinking:      lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true),
+		assistant.StateSpeaking:      lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true),
+		assistant.StatePaused:        lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true),
+		assistant.StateWakeListening: lipgloss.NewStyle().Foreground(lipgloss.Color("250")),
+		assistant.StateIdle:          lipgloss.NewStyle().Foreground(lipgloss.Color("245")),
+	}
+)
+
+// eventMsg carries a pipeline event into the program
+type eventMsg output.Event
+
+// levelMsg carries the input level of the last audio chunk
+type levelMsg struct {
+	level      float32
+	calibrated bool
+}
+
+// logMsg carries a log line
+type logMsg string
+
+// Info is the static session information shown in the header
+type Info struct {
+	Model       string
+	Language    string
+	AudioSource string
+	WakeWord    string             // Empty when listening permanently
+	Timestamps  *output.Timestamps // Format of the line timestamps, 15:04:05 when nil
+}
+
+// Model is the bubbletea model of the dashboard
+type Model struct {
+	info       Info
+	controller Controller
+
+	state      assistant.State
+	speech     bool
+	paused     bool
+	level      float32
+	peak       float32
+	calibrated bool
+
+	lines   []string // Rolling transcript and answers
+	pending string   // AI answer being streamed
+	logs    []string
+
+	width  int
+	height int
+}
+
+// NewModel creates the dashboard model
+func NewModel(info Info, controller Controller) Model {
+	state := assistant.StateActive
+	if info.WakeWord != "" {
+		state = assistant.StateWakeListening
+	}
+	return Model{info: info, controller: controller, state: state}
+}
+
+// Init implements tea.Model
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			m.controller.Stop()
+			m.logs = appendLine(m.logs, "Stopping, finishing pending transcriptions...")
+		case "p", " ":
+			if m.paused {
+				m.controller.Resume()
+			} else {
+				m.controller.Pause()
+			}
+		case "c":
+			m.controller.ClearHistory()
+			m.logs = appendLine(m.logs, "AI conversation history cleared")
+		}
+
+	case levelMsg:
+		m.level = msg.level
+		m.calibrated = msg.calibrated
+		// Slowly decaying peak marker
+		m.peak *= 0.95
+		if m.level > m.peak {
+			m.peak = m.level
+		}
+
+	case logMsg:
+		m.logs = appendLine(m.logs, string(msg))
+
+	case eventMsg:
+		m.handleEvent(output.Event(msg))
+	}
+	return m, nil
+}
+
+// handleEvent updates the model from a pipeline event
+func (m *Model) handleEvent(event output.Event) {
+	clock := m.info.Timestamps.Event(event)
+
+	switch event.Type {
+	case output.EventState:
+		m.state = assistant.State(event.State)
+		m.paused = m.state == assistant.StatePaused
+	case output.EventVAD:
+		m.speech = event.State == "speech"
+	case output.EventWakeWord:
+		m.lines = appendLine(m.lines, labelStyle.Render(clock+" 🎯 "+event.Text))
+	case output.EventTranscript:
+		speaker := "you"
+		if event.Speaker != "" {
+			speaker = event.Speaker
+		}
+		m.lines = appendLine(m.lines, labelStyle.Render(clock)+" "+youStyle.Render(speaker+": ")+event.Text)
+	case output.EventTranslation:
+		m.lines = appendLine(m.lines, labelStyle.Render(clock)+" "+aiStyle.Render(event.Language+": ")+event.Text)
+	case output.EventAIToken:
+		m.pending += event.Text
+	case output.EventAIResponse:
+		m.pending = ""
+		m.lines = appendLine(m.lines, labelStyle.Render(clock)+" "+aiStyle.Render("ai: ")+event.Text)
+	case output.EventTimer:
+		m.lines = appendLine(m.lines, labelStyle.Render(clock+" ⏰ ")+event.Text)
+	case output.EventError:
+		m.pending = ""
+		m.logs = appendLine(m.logs, errorStyle.Render(event.Error))
+	}
+}
+
+// View implements tea.Model
+func (m Model) View() string {
+	var b strings.Builder
+
+	// Header
+	state := m.state
+	if state == "" {
+		state = assistant.StateIdle
+	}
+	style, ok := stateStyles[state]
+	if !ok {
+		style = stateStyles[assistant.StateIdle]
+	}
+	b.WriteString(titleStyle.Render("NRZ-AI") + "  " + style.Render("● "+string(state)))
+	b.WriteString(labelStyle.Render(fmt.Sprintf("   model %s · %s · %s", m.info.Model, m.info.Language, m.info.AudioSource)))
+	if m.info.WakeWord != "" {
+		b.WriteString(labelStyle.Render(" · wake word " + m.info.WakeWord))
+	}
+	b.WriteString("\n\n")
+
+	// Level and VAD meter
+	b.WriteString(labelStyle.Render("Level ") + meter(m.level, m.peak))
+	b.WriteString(fmt.Sprintf(" %.3f  ", m.level))
+	if m.speech {
+		b.WriteString(speechStyle.Render("SPEECH"))
+	} else {
+		b.WriteString(labelStyle.Render("silence"))
+	}
+	if m.calibrated {
+		b.WriteString(labelStyle.Render("  calibrated ✓"))
+	} else {
+		b.WriteString(labelStyle.Render("  calibrating noise floor..."))
+	}
+	b.WriteString("\n\n")
+
+	// Transcript fills the space left by the other sections
+	logLines := tail(m.logs, 3)
+	height := m.height - 9 - len(logLines)
+	if height < 3 {
+		height = 3
+	}
+	lines := m.lines
+	if m.pending != "" {
+		lines = append(append([]string(nil), lines...), aiStyle.Render("ai: ")+m.pending+"▌")
+	}
+	b.WriteString(sectionStyle.Render("── Transcript ──") + "\n")
+	for _, line := range tail(lines, height) {
+		b.WriteString(truncate(line, m.width) + "\n")
+	}
+
+	if len(logLines) > 0 {
+		b.WriteString(sectionStyle.Render("── Log ──") + "\n")
+		for _, line := range logLines {
+			b.WriteString(truncate(line, m.width) + "\n")
+		}
+	}
+
+	b.WriteString("\n" + labelStyle.Render("q quit · p pause/resume · c clear AI history"))
+	return b.String()
+}
+
+// meter renders a level bar with a peak marker
+func meter(level, peak float32) string {
+	cells := func(value float32) int {
+		n := int(value / meterScale * meterWidth)
+		if n > meterWidth {
+			n = meterWidth
+		}
+		if n < 0 {
+			n = 0
+		}
+		return n
+	}
+
+	filled, peakCell := cells(level), cells(peak)
+	bar := []rune(strings.Repeat("█", filled) + strings.Repeat("░", meterWidth-filled))
+	if peakCell > filled && peakCell < meterWidth {
+		bar[peakCell] = '|'
+	}
+	return speechStyle.Render(string(bar[:filled])) + labelStyle.Render(string(bar[filled:]))
+}
+
+// appendLine adds a line, dropping the oldest beyond maxLines
+func appendLine(lines []string, line string) []string {
+	lines = append(lines, line)
+	if len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+	}
+	return lines
+}
+
+// tail returns the last n lines
+func tail(lines []string, n int) []string {
+	if len(lines) > n {
+		return lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// truncate cuts a line to the terminal width, 0 means unknown
+func truncate(line string, width int) string {
+	if width <= 0 || lipgloss.Width(line) <= width {
+		return line
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(line)
+}
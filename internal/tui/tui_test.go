@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nerzhul/nrz-ai/pkg/assistant"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+func update(m Model, msg tea.Msg) Model {
+	next, _ := m.Update(msg)
+	return next.(Model)
+}
+
+func TestModel_Events(t *testing.T) {
+	m := NewModel(Info{Model: "ggml-base.bin", Language: "fr", WakeWord: "Jack"}, NewMockController())
+	if m.state != assistant.StateWakeListening {
+		t.Errorf("Expected wake_listening state with a wake word, got %s", m.state)
+	}
+
+	at := time.Date(2026, 3, 1, 15, 4, 12, 0, time.Local)
+	m = update(m, eventMsg{Type: output.EventState, State: "active", Time: at})
+	m = update(m, eventMsg{Type: output.EventVAD, State: "speech", Time: at})
+	m = update(m, eventMsg{Type: output.EventTranscript, Text: "Bonjour", Time: at})
+	m = update(m, eventMsg{Type: output.EventAIToken, Text: "Salut", Time: at})
+
+	view := m.View()
+	for _, expected := range []string{"active", "SPEECH", "you: ", "Bonjour", "Salut▌"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected view to contain %q, got:\n%s", expected, view)
+		}
+	}
+
+	m = update(m, eventMsg{Type: output.EventAIResponse, Text: "Salut !", Time: at})
+	if m.pending != "" || len(m.lines) != 2 {
+		t.Errorf("Expected streamed answer to be replaced by the final one, got %q and %d lines", m.pending, len(m.lines))
+	}
+}
+
+func TestModel_Keys(t *testing.T) {
+	controller := NewMockController()
+	m := NewModel(Info{}, controller)
+
+	m = update(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if !controller.Paused {
+		t.Error("Expected p to pause")
+	}
+	m = update(m, eventMsg{Type: output.EventState, State: "paused"})
+	m = update(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if !controller.ResumeCalled {
+		t.Error("Expected p to resume when paused")
+	}
+
+	m = update(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	update(m, tea.KeyMsg{Type: tea.KeyCtrlC})
+	if controller.Cleared != 1 || !controller.Stopped {
+		t.Errorf("Expected clear and stop, got %+v", controller)
+	}
+}
+
+func TestModel_LevelPeak(t *testing.T) {
+	m := NewModel(Info{}, NewMockController())
+	m = update(m, levelMsg{level: 0.1, calibrated: true})
+	m = update(m, levelMsg{level: 0.01, calibrated: true})
+
+	if m.peak <= m.level || m.peak > 0.1 {
+		t.Errorf("Expected decaying peak above the level, got peak %.3f level %.3f", m.peak, m.level)
+	}
+	if !strings.Contains(m.View(), "calibrated ✓") {
+		t.Error("Expected calibration status in view")
+	}
+}
+
+func TestLogWriter_SplitsLines(t *testing.T) {
+	var lines []string
+	w := &logWriter{send: func(line string) { lines = append(lines, line) }}
+
+	w.Write([]byte("first\nsec"))
+	w.Write([]byte("ond\n\n"))
+
+	if len(lines) != 2 || lines[0] != "first" || lines[1] != "second" {
+		t.Errorf("Unexpected lines: %q", lines)
+	}
+}
+
+func TestAppendLine_Bounded(t *testing.T) {
+	var lines []string
+	for i := 0; i < maxLines+10; i++ {
+		lines = appendLine(lines, "x")
+	}
+	if len(lines) != maxLines {
+		t.Errorf("Expected %d lines, got %d", maxLines, len(lines))
+	}
+}
@@ -0,0 +1,199 @@
+// Package voices keeps the voice prints of enrolled household members and
+// identifies who spoke an utterance, so that each one gets their own AI
+// conversation
+package voices
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nerzhul/nrz-ai/internal/diarization"
+	"github.com/nerzhul/nrz-ai/internal/privacy"
+)
+
+// Guest is the profile of speakers matching no voice print
+const Guest = "guest"
+
+// DefaultThreshold is the voice similarity needed to recognize a speaker
+const DefaultThreshold = 0.85
+
+// ErrUnknownVoice is returned for a name that is not enrolled
+var ErrUnknownVoice = errors.New("unknown voice")
+
+// Voice is an enrolled speaker
+type Voice struct {
+	Name string `json:"name"`
+	// Prompt replaces the system prompt in this speaker's conversation
+	Prompt string `json:"prompt,omitempty"`
+	// Print is the mean embedding of the enrollment recordings
+	Print []float64 `json:"print"`
+	// Recordings is the number of enrollment recordings averaged in Print
+	Recordings int `json:"recordings"`
+}
+
+// Store keeps the voice prints in a JSON file
+type Store struct {
+	path       string
+	sampleRate int
+	threshold  float64
+	voices     map[string]*Voice
+	mutex      sync.RWMutex
+}
+
+// Open loads the voice prints saved at path, a missing file is an empty store
+func Open(path string, sampleRate int) (*Store, error) {
+	s := &Store{
+		path:       path,
+		sampleRate: sampleRate,
+		threshold:  DefaultThreshold,
+		voices:     make(map[string]*Voice),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var saved []Voice
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for i := range saved {
+		s.voices[key(saved[i].Name)] = &saved[i]
+	}
+	return s, nil
+}
+
+// SetThreshold sets the similarity needed to recognize a speaker (0-1)
+func (s *Store) SetThreshold(threshold float64) {
+	if threshold > 0 {
+		s.threshold = threshold
+	}
+}
+
+// Enroll adds a recording of name to their voice print, creating the voice
+// on the first one. A non-empty prompt replaces the voice system prompt.
+func (s *Store) Enroll(name string, samples []float32, prompt string) (Voice, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || key(name) == Guest {
+		return Voice{}, fmt.Errorf("invalid voice name '%s'", name)
+	}
+	if err := privacy.Check("voice enrollment"); err != nil {
+		return Voice{}, err
+	}
+	embedding := diarization.Embed(samples, s.sampleRate)
+	if embedding == nil {
+		return Voice{}, errors.New("recording too short for a voice print")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	voice, ok := s.voices[key(name)]
+	if !ok {
+		voice = &Voice{Name: name, Print: make([]float64, len(embedding))}
+		s.voices[key(name)] = voice
+	}
+	voice.Recordings++
+	for i, v := range embedding {
+		voice.Print[i] += (v - voice.Print[i]) / float64(voice.Recordings)
+	}
+	if prompt != "" {
+		voice.Prompt = prompt
+	}
+	return *voice, s.saveLocked()
+}
+
+// Remove forgets the voice print of name
+func (s *Store) Remove(name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.voices[key(name)]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownVoice, name)
+	}
+	delete(s.voices, key(name))
+	return s.saveLocked()
+}
+
+// Voices returns the enrolled voices sorted by name
+func (s *Store) Voices() []Voice {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	voices := make([]Voice, 0, len(s.voices))
+	for _, voice := range s.voices {
+		voices = append(voices, *voice)
+	}
+	sort.Slice(voices, func(i, j int) bool { return key(voices[i].Name) < key(voices[j].Name) })
+	return voices
+}
+
+// Identify returns the enrolled speaker whose voice print is the most
+// similar to samples, ok is false when none is similar enough
+func (s *Store) Identify(samples []float32) (name string, ok bool) {
+	embedding := diarization.Embed(samples, s.sampleRate)
+	if embedding == nil {
+		return "", false
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	best := s.threshold
+	for _, voice := range s.voices {
+		if similarity := diarization.Similarity(embedding, voice.Print); similarity >= best {
+			name, best, ok = voice.Name, similarity, true
+		}
+	}
+	return name, ok
+}
+
+// Prompt returns the system prompt of name, empty for the default one
+func (s *Store) Prompt(name string) string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if voice, ok := s.voices[key(name)]; ok {
+		return voice.Prompt
+	}
+	return ""
+}
+
+// saveLocked atomically rewrites the voices file. Caller holds the mutex.
+func (s *Store) saveLocked() error {
+	voices := make([]*Voice, 0, len(s.voices))
+	for _, voice := range s.voices {
+		voices = append(voices, voice)
+	}
+	sort.Slice(voices, func(i, j int) bool { return key(voices[i].Name) < key(voices[j].Name) })
+
+	data, err := json.MarshalIndent(voices, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	// Voice prints identify people, keep them private
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// key is the case-insensitive lookup key of a name
+func key(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,95 @@
+package voices
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// voice synthesizes a harmonic signal with a formant-like spectral envelope
+func voice(fundamental, formant float64, seconds float64, phase float64) []float32 {
+	n := int(seconds * 16000)
+	samples := make([]float32, n)
+	for h := 1; fundamental*float64(h) < 7000; h++ {
+		freq := fundamental * float64(h)
+		amplitude := math.Exp(-math.Pow((freq-formant)/600, 2))
+		for i := range samples {
+			t := float64(i) / 16000
+			samples[i] += float32(0.2 * amplitude * math.Sin(2*math.Pi*freq*t+phase*float64(h)))
+		}
+	}
+	return samples
+}
+
+func TestStore_EnrollAndIdentify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "voices.json")
+	store, err := Open(path, 16000)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+
+	if _, err := store.Enroll("Alice", voice(230, 2500, 2, 0), "Tu parles à Alice."); err != nil {
+		t.Fatalf("Failed to enroll: %v", err)
+	}
+	if _, err := store.Enroll("Bob", voice(110, 500, 2, 0), ""); err != nil {
+		t.Fatalf("Failed to enroll: %v", err)
+	}
+	enrolled, err := store.Enroll("bob", voice(112, 520, 2, 0.3), "")
+	if err != nil || enrolled.Recordings != 2 {
+		t.Fatalf("Expected a second recording for Bob, got %+v, %v", enrolled, err)
+	}
+
+	// Reopen to check the voice prints were saved
+	store, err = Open(path, 16000)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	if voices := store.Voices(); len(voices) != 2 || voices[0].Name != "Alice" || voices[1].Name != "Bob" {
+		t.Fatalf("Expected Alice and Bob, got %+v", voices)
+	}
+
+	if name, ok := store.Identify(voice(228, 2480, 1.5, 0.5)); !ok || name != "Alice" {
+		t.Errorf("Expected Alice, got %q (%v)", name, ok)
+	}
+	if name, ok := store.Identify(voice(111, 510, 1.5, 0.9)); !ok || name != "Bob" {
+		t.Errorf("Expected Bob, got %q (%v)", name, ok)
+	}
+	if store.Prompt("alice") != "Tu parles à Alice." || store.Prompt("Bob") != "" {
+		t.Errorf("Expected the prompts kept, got %q and %q", store.Prompt("alice"), store.Prompt("Bob"))
+	}
+}
+
+func TestStore_UnknownSpeaker(t *testing.T) {
+	store, _ := Open(filepath.Join(t.TempDir(), "voices.json"), 16000)
+	if _, ok := store.Identify(voice(110, 500, 1.5, 0)); ok {
+		t.Error("Expected no speaker without voice prints")
+	}
+
+	store.Enroll("Bob", voice(110, 500, 2, 0), "")
+	if name, ok := store.Identify(voice(230, 2500, 1.5, 0)); ok {
+		t.Errorf("Expected a guest, got %q", name)
+	}
+	if _, ok := store.Identify(make([]float32, 100)); ok {
+		t.Error("Expected no speaker for a too short utterance")
+	}
+}
+
+func TestStore_Errors(t *testing.T) {
+	store, _ := Open(filepath.Join(t.TempDir(), "voices.json"), 16000)
+
+	if _, err := store.Enroll("Guest", voice(110, 500, 2, 0), ""); err == nil {
+		t.Error("Expected an error for the guest profile name")
+	}
+	if _, err := store.Enroll("Bob", make([]float32, 100), ""); err == nil {
+		t.Error("Expected an error for a too short recording")
+	}
+	if err := store.Remove("Carol"); !errors.Is(err, ErrUnknownVoice) {
+		t.Errorf("Expected ErrUnknownVoice, got %v", err)
+	}
+
+	store.Enroll("Bob", voice(110, 500, 2, 0), "")
+	if err := store.Remove("BOB"); err != nil || len(store.Voices()) != 0 {
+		t.Errorf("Expected Bob removed, got %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+const (
+	queueSize      = 64
+	requestTimeout = 10 * time.Second
+)
+
+// Config holds the webhook settings
+type Config struct {
+	URLs       []string
+	Secret     string        // HMAC-SHA256 key, empty disables signing
+	MaxRetries int           // Retries after the first attempt
+	RetryDelay time.Duration // Initial backoff, doubled on every retry
+}
+
+// delivery is an event waiting to be posted
+type delivery struct {
+	eventType output.EventType
+	payload   []byte
+}
+
+// Dispatcher posts transcript, wake word and AI events to webhook URLs.
+// Deliveries run in the background so slow endpoints never stall the pipeline.
+type Dispatcher struct {
+	config     Config
+	httpClient *http.Client
+	queue      chan delivery
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// NewDispatcher creates a dispatcher and starts its delivery worker
+func NewDispatcher(config Config) *Dispatcher {
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = time.Second
+	}
+
+	d := &Dispatcher{
+		config:     config,
+		httpClient: &http.Client{Timeout: requestTimeout},
+		queue:      make(chan delivery, queueSize),
+		done:       make(chan struct{}),
+	}
+	go d.worker()
+	return d
+}
+
+// Emit queues final transcripts, wake word activations and AI responses
+func (d *Dispatcher) Emit(event output.Event) error {
+	switch event.Type {
+	case output.EventTranscript, output.EventWakeWord, output.EventAIResponse:
+	default:
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case d.queue <- delivery{eventType: event.Type, payload: payload}:
+		return nil
+	default:
+		return fmt.Errorf("webhook queue full, dropped %s event", event.Type)
+	}
+}
+
+// Close delivers the queued events and stops the worker
+func (d *Dispatcher) Close() {
+	d.closeOnce.Do(func() {
+		close(d.queue)
+		<-d.done
+	})
+}
+
+// worker delivers queued events to every URL
+func (d *Dispatcher) worker() {
+	defer close(d.done)
+
+	for item := range d.queue {
+		for _, url := range d.config.URLs {
+			if err := d.deliver(url, item); err != nil {
+				logger.WithError(err).WithField("url", url).Error("❌ Webhook delivery failed")
+			}
+		}
+	}
+}
+
+// deliver posts an event, retrying with exponential backoff on network
+// errors, 429 and 5xx replies
+func (d *Dispatcher) deliver(url string, item delivery) error {
+	delay := d.config.RetryDelay
+	var lastErr error
+
+	for attempt := 0; attempt <= d.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		retry, err := d.post(url, item)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return lastErr
+}
+
+// post sends a single request, reporting whether a failure is worth retrying
+func (d *Dispatcher) post(url string, item delivery) (bool, error) {
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(item.payload))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "nrz-ai")
+	request.Header.Set("X-NRZ-Event", string(item.eventType))
+	request.Header.Set("X-NRZ-Timestamp", timestamp)
+	if d.config.Secret != "" {
+		request.Header.Set("X-NRZ-Signature", "sha256="+Sign(d.config.Secret, timestamp, item.payload))
+	}
+
+	resp, err := d.httpClient.Do(request)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("webhook returned %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<payload>", as sent in
+// the X-NRZ-Signature header. Receivers recompute it to authenticate
+// requests and reject old timestamps to prevent replays.
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
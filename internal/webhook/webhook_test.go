@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+type receiver struct {
+	server   *httptest.Server
+	mutex    sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+	statuses []int // Replies in order, 200 once exhausted
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	t.Helper()
+
+	r := &receiver{statuses: statuses}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		r.mutex.Lock()
+		r.requests = append(r.requests, req)
+		r.bodies = append(r.bodies, body)
+		status := http.StatusOK
+		if len(r.statuses) > 0 {
+			status, r.statuses = r.statuses[0], r.statuses[1:]
+		}
+		r.mutex.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *receiver) count() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.requests)
+}
+
+func TestDispatcher_SignsAndFilters(t *testing.T) {
+	rcv := newReceiver(t)
+	dispatcher := NewDispatcher(Config{URLs: []string{rcv.server.URL}, Secret: "s3cret"})
+
+	dispatcher.Emit(output.Event{Type: output.EventVAD, State: "speech"})
+	dispatcher.Emit(output.Event{Type: output.EventTranscript, Text: "Bonjour"})
+	dispatcher.Close()
+
+	if rcv.count() != 1 {
+		t.Fatalf("Expected only the transcript to be delivered, got %d requests", rcv.count())
+	}
+
+	request, body := rcv.requests[0], rcv.bodies[0]
+	if request.Header.Get("X-NRZ-Event") != "transcript" {
+		t.Errorf("Expected event header, got %q", request.Header.Get("X-NRZ-Event"))
+	}
+	expected := "sha256=" + Sign("s3cret", request.Header.Get("X-NRZ-Timestamp"), body)
+	if request.Header.Get("X-NRZ-Signature") != expected {
+		t.Errorf("Expected signature %q, got %q", expected, request.Header.Get("X-NRZ-Signature"))
+	}
+
+	var event output.Event
+	if err := json.Unmarshal(body, &event); err != nil || event.Text != "Bonjour" {
+		t.Errorf("Expected JSON event body, got %s (%v)", body, err)
+	}
+}
+
+func TestDispatcher_RetriesServerErrors(t *testing.T) {
+	rcv := newReceiver(t, http.StatusBadGateway, http.StatusServiceUnavailable)
+	dispatcher := NewDispatcher(Config{URLs: []string{rcv.server.URL}, MaxRetries: 3, RetryDelay: time.Millisecond})
+
+	dispatcher.Emit(output.Event{Type: output.EventAIResponse, Text: "Salut"})
+	dispatcher.Close()
+
+	if rcv.count() != 3 {
+		t.Errorf("Expected 2 failures then a success, got %d requests", rcv.count())
+	}
+}
+
+func TestDispatcher_DoesNotRetryClientErrors(t *testing.T) {
+	rcv := newReceiver(t, http.StatusUnauthorized)
+	dispatcher := NewDispatcher(Config{URLs: []string{rcv.server.URL}, MaxRetries: 3, RetryDelay: time.Millisecond})
+
+	dispatcher.Emit(output.Event{Type: output.EventWakeWord, Text: "ok nrz"})
+	dispatcher.Close()
+
+	if rcv.count() != 1 {
+		t.Errorf("Expected a single attempt on 401, got %d requests", rcv.count())
+	}
+}
+
+func TestDispatcher_UnsignedWithoutSecret(t *testing.T) {
+	rcv := newReceiver(t)
+	dispatcher := NewDispatcher(Config{URLs: []string{rcv.server.URL}})
+
+	dispatcher.Emit(output.Event{Type: output.EventTranscript, Text: "Bonjour"})
+	dispatcher.Close()
+
+	if rcv.count() != 1 || rcv.requests[0].Header.Get("X-NRZ-Signature") != "" {
+		t.Error("Expected an unsigned delivery without secret")
+	}
+}
@@ -84,4 +84,37 @@ func TestMockAIService_ListModels(t *testing.T) {
 	if models[0] != "mock-model-1" {
 		t.Errorf("Expected 'mock-model-1', got '%s'", models[0])
 	}
-}
\ No newline at end of file
+}
+
+func TestAggregateStream_StopsOnError(t *testing.T) {
+	mock := NewMockAIService()
+	mock.SetResponses([]ChatResponse{
+		{Message: Message{Content: "Un"}},
+		{Error: "model crashed"},
+		{Message: Message{Content: "jamais lu"}, Done: true},
+	})
+	stream, _ := mock.ChatStream(ChatRequest{})
+
+	response, err := AggregateStream(stream, nil)
+	if err == nil || err.Error() != "model crashed" {
+		t.Errorf("Expected the chunk error, got: %v", err)
+	}
+	if response.Error != "model crashed" || response.Message.Content != "Un" {
+		t.Errorf("Expected the partial answer with the error, got %+v", response)
+	}
+}
+
+func TestAggregateStream_ForcesAssistantRole(t *testing.T) {
+	stream := make(chan ChatResponse, 2)
+	stream <- ChatResponse{Message: Message{Content: "Bon"}}
+	stream <- ChatResponse{Message: Message{Content: "soir"}, Done: true}
+	close(stream)
+
+	response, err := AggregateStream(stream, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if response.Message.Role != "assistant" || response.Message.Content != "Bonsoir" || !response.Done {
+		t.Errorf("Expected the assembled assistant message, got %+v", response)
+	}
+}
@@ -0,0 +1,203 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultContextTimeout bounds the context commands
+const defaultContextTimeout = 5 * time.Second
+
+// ContextSource gives a line of context to the AI, e.g. the location or
+// the weather
+type ContextSource interface {
+	// Context returns the line, empty when there is nothing to tell
+	Context() (string, error)
+}
+
+// StaticContext is a context line that never changes
+type StaticContext string
+
+// Context returns the line
+func (s StaticContext) Context() (string, error) {
+	return string(s), nil
+}
+
+// CommandContext is a context line printed by an external command, such
+// as a weather script. The output is kept for a refresh period, so that
+// requests do not wait for the command every time.
+type CommandContext struct {
+	command []string
+	timeout time.Duration
+	refresh time.Duration
+	now     func() time.Time
+	line    string
+	updated time.Time
+	mutex   sync.Mutex
+}
+
+// NewCommandContext creates a source running command, split on spaces
+// without a shell, at most once per refresh
+func NewCommandContext(command string, refresh time.Duration) (*CommandContext, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty context command")
+	}
+	return &CommandContext{
+		command: fields,
+		timeout: defaultContextTimeout,
+		refresh: refresh,
+		now:     time.Now,
+	}, nil
+}
+
+// Context returns the trimmed output of the command. When the command
+// fails the previous output is kept along with the error.
+func (c *CommandContext) Context() (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	if !c.updated.IsZero() && now.Sub(c.updated) < c.refresh {
+		return c.line, nil
+	}
+	// Failures are retried after a refresh period too
+	c.updated = now
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.command[0], c.command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = 500 * time.Millisecond
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return c.line, fmt.Errorf("context command %s timed out after %s", c.command[0], c.timeout)
+		}
+		return c.line, fmt.Errorf("context command %s failed: %w: %s", c.command[0], err, strings.TrimSpace(stderr.String()))
+	}
+	c.line = strings.TrimSpace(stdout.String())
+	return c.line, nil
+}
+
+// ContextService wraps a service to send it the current date and time, the
+// user language and the lines of its sources in a system message. The
+// message is built for every request and never kept in the conversation.
+type ContextService struct {
+	service  AIService
+	sources  []ContextSource
+	language string
+	now      func() time.Time
+	onError  func(err error)
+	mutex    sync.RWMutex
+}
+
+// NewContextService creates a service giving the context of sources to service
+func NewContextService(service AIService, sources ...ContextSource) *ContextService {
+	return &ContextService{
+		service: service,
+		sources: sources,
+		now:     time.Now,
+	}
+}
+
+// SetClock replaces the clock giving the current time, for tests
+func (c *ContextService) SetClock(now func() time.Time) {
+	c.now = now
+}
+
+// SetErrorHandler sets the function called when a source fails, the
+// request is sent without its line
+func (c *ContextService) SetErrorHandler(onError func(err error)) {
+	c.onError = onError
+}
+
+// SetLanguage sets the user language, auto or empty leaves it out
+func (c *ContextService) SetLanguage(language string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.language = language
+}
+
+// Message returns the context system message
+func (c *ContextService) Message() Message {
+	now := c.now()
+	lines := []string{
+		"Current date and time: " + now.Format("Monday 2 January 2006, 15:04 MST (UTC-07:00)"),
+	}
+
+	c.mutex.RLock()
+	language := c.language
+	c.mutex.RUnlock()
+	if language != "" && language != "auto" {
+		lines = append(lines, "User language: "+language)
+	}
+
+	for _, source := range c.sources {
+		line, err := source.Context()
+		if err != nil && c.onError != nil {
+			c.onError(err)
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return Message{Role: "system", Content: "Context of this request:\n- " + strings.Join(lines, "\n- ")}
+}
+
+// withContext returns request with the context message after the leading
+// system messages, the messages of the caller are left untouched
+func (c *ContextService) withContext(request ChatRequest) ChatRequest {
+	position := 0
+	for position < len(request.Messages) && request.Messages[position].Role == "system" {
+		position++
+	}
+
+	messages := make([]Message, 0, len(request.Messages)+1)
+	messages = append(messages, request.Messages[:position]...)
+	messages = append(messages, c.Message())
+	messages = append(messages, request.Messages[position:]...)
+	request.Messages = messages
+	return request
+}
+
+// Chat sends request with the context
+func (c *ContextService) Chat(request ChatRequest) (ChatResponse, error) {
+	return c.service.Chat(c.withContext(request))
+}
+
+// ChatStream streams the answer to request with the context
+func (c *ContextService) ChatStream(request ChatRequest) (<-chan ChatResponse, error) {
+	return c.service.ChatStream(c.withContext(request))
+}
+
+// ListModels returns the models of the service
+func (c *ContextService) ListModels() ([]string, error) {
+	return c.service.ListModels()
+}
+
+// IsAvailable reports whether the service is available
+func (c *ContextService) IsAvailable() bool {
+	return c.service.IsAvailable()
+}
+
+// SetModel changes the model of the service if it supports it
+func (c *ContextService) SetModel(model string) {
+	if setter, ok := c.service.(ModelSetter); ok {
+		setter.SetModel(model)
+	}
+}
+
+// Close closes the service
+func (c *ContextService) Close() error {
+	return c.service.Close()
+}
@@ -0,0 +1,79 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordingService records the requests sent to a mock service
+type recordingService struct {
+	*MockAIService
+	requests []ChatRequest
+}
+
+func (r *recordingService) Chat(request ChatRequest) (ChatResponse, error) {
+	r.requests = append(r.requests, request)
+	return r.MockAIService.Chat(request)
+}
+
+func TestContextService_AddsContextAfterSystemPrompt(t *testing.T) {
+	backend := &recordingService{MockAIService: NewMockAIService()}
+	service := NewContextService(backend, StaticContext("Location: Lyon, France"), StaticContext(" "))
+	paris, _ := time.LoadLocation("Europe/Paris")
+	service.SetClock(func() time.Time { return time.Date(2024, 6, 11, 14, 5, 0, 0, paris) })
+	service.SetLanguage("fr")
+
+	messages := []Message{
+		{Role: "system", Content: "Tu es un assistant."},
+		{Role: "user", Content: "Quelle heure est-il ?"},
+	}
+	if _, err := service.Chat(ChatRequest{Messages: messages}); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := backend.requests[0].Messages
+	if len(sent) != 3 || sent[0].Content != "Tu es un assistant." || sent[2].Role != "user" {
+		t.Fatalf("Expected the context between the system prompt and the question, got %+v", sent)
+	}
+	expected := "Context of this request:\n- Current date and time: Tuesday 11 June 2024, 14:05 CEST (UTC+02:00)\n- User language: fr\n- Location: Lyon, France"
+	if sent[1].Role != "system" || sent[1].Content != expected {
+		t.Errorf("Expected context %q, got %q", expected, sent[1].Content)
+	}
+	if len(messages) != 2 {
+		t.Error("Expected the messages of the caller to be left untouched")
+	}
+
+	service.SetLanguage("auto")
+	if content := service.Message().Content; strings.Contains(content, "User language") {
+		t.Errorf("Expected no language with auto, got %q", content)
+	}
+}
+
+func TestCommandContext_KeepsOutputUntilRefresh(t *testing.T) {
+	source, err := NewCommandContext("echo Weather: sunny, 18°C", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 6, 11, 14, 0, 0, 0, time.UTC)
+	source.now = func() time.Time { return now }
+
+	if line, err := source.Context(); err != nil || line != "Weather: sunny, 18°C" {
+		t.Fatalf("Expected the command output, got %q (%v)", line, err)
+	}
+
+	// Within the refresh period the command is not run again
+	source.command = []string{"false"}
+	if line, err := source.Context(); err != nil || line != "Weather: sunny, 18°C" {
+		t.Errorf("Expected the cached output, got %q (%v)", line, err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if line, err := source.Context(); err == nil || line != "Weather: sunny, 18°C" {
+		t.Errorf("Expected an error and the previous output, got %q (%v)", line, err)
+	}
+
+	if _, err := NewCommandContext("  ", time.Minute); err == nil {
+		t.Error("Expected an error for an empty command")
+	}
+}
@@ -30,8 +30,12 @@ func (c *Conversation) AddMessage(message Message) {
 	defer c.mutex.Unlock()
 
 	c.messages = append(c.messages, message)
+	c.trim()
+}
 
-	// Keep only the last maxHistory messages (excluding system prompt)
+// trim keeps only the last maxHistory messages, and the system prompt.
+// Called with the mutex locked.
+func (c *Conversation) trim() {
 	if len(c.messages) > c.maxHistory {
 		// Find system message if it exists
 		systemIndex := -1
@@ -119,4 +123,40 @@ func (c *Conversation) GetMessageCount() int {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 	return len(c.messages)
-}
\ No newline at end of file
+}
+
+// ReplaceSystemPrompt sets the system prompt like SetSystemPrompt and
+// returns the previous one, to restore it later
+func (c *Conversation) ReplaceSystemPrompt(prompt string) string {
+	previous := c.GetSystemPrompt()
+	c.SetSystemPrompt(prompt)
+	return previous
+}
+
+// RemoveLast removes the last n messages, never the system prompt, and
+// returns them oldest first
+func (c *Conversation) RemoveLast(n int) []Message {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var removed []Message
+	for i := len(c.messages) - 1; i >= 0 && len(removed) < n; i-- {
+		if c.messages[i].Role == "system" {
+			break
+		}
+		removed = append([]Message{c.messages[i]}, removed...)
+		c.messages = c.messages[:i]
+	}
+	return removed
+}
+
+// LastExchange returns the number of messages of the last exchange: the
+// last user message and the answers after it, 0 without user message
+func LastExchange(messages []Message) int {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return len(messages) - i
+		}
+	}
+	return 0
+}
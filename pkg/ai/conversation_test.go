@@ -91,3 +91,51 @@ func TestConversation_ClearHistory(t *testing.T) {
 		t.Errorf("Expected system message to remain after clear, got role '%s'", messages[0].Role)
 	}
 }
+
+func TestConversation_RemoveLast(t *testing.T) {
+	conv := NewConversation(10)
+	conv.SetSystemPrompt("Be brief")
+	conv.AddMessage(Message{Role: "user", Content: "Allume la lumière"})
+	conv.AddMessage(Message{Role: "assistant", Content: "C'est fait."})
+	conv.AddMessage(Message{Role: "user", Content: "Allume le chauffe-eau du voisin"})
+	conv.AddMessage(Message{Role: "assistant", Content: "Je ne peux pas."})
+
+	removed := conv.RemoveLast(LastExchange(conv.GetMessages()))
+	if len(removed) != 2 || removed[0].Content != "Allume le chauffe-eau du voisin" {
+		t.Errorf("Expected the last exchange to be removed, got %+v", removed)
+	}
+	if messages := conv.GetMessages(); len(messages) != 3 || messages[2].Content != "C'est fait." {
+		t.Errorf("Expected the previous exchange to stay, got %+v", messages)
+	}
+
+	// The system prompt is never removed
+	if removed := conv.RemoveLast(10); len(removed) != 2 {
+		t.Errorf("Expected 2 messages removed, got %+v", removed)
+	}
+	if messages := conv.GetMessages(); len(messages) != 1 || messages[0].Role != "system" {
+		t.Errorf("Expected only the system prompt left, got %+v", messages)
+	}
+}
+
+func TestConversation_ReplaceSystemPrompt(t *testing.T) {
+	conv := NewConversation(10)
+	conv.SetSystemPrompt("Be brief")
+	conv.AddMessage(Message{Role: "user", Content: "Hello"})
+
+	if previous := conv.ReplaceSystemPrompt("Be funny"); previous != "Be brief" {
+		t.Errorf("Expected the previous prompt, got %q", previous)
+	}
+	messages := conv.GetMessages()
+	if len(messages) != 2 || messages[0].Content != "Be funny" || messages[1].Content != "Hello" {
+		t.Errorf("Expected the new prompt and the history, got %+v", messages)
+	}
+}
+
+func TestLastExchange(t *testing.T) {
+	if n := LastExchange([]Message{{Role: "system"}, {Role: "assistant"}}); n != 0 {
+		t.Errorf("Expected no exchange without user message, got %d", n)
+	}
+	if n := LastExchange([]Message{{Role: "user"}, {Role: "assistant"}, {Role: "user"}}); n != 1 {
+		t.Errorf("Expected an unanswered message to be an exchange, got %d", n)
+	}
+}
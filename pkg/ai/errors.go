@@ -0,0 +1,45 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Error classes of the AI services, matched with errors.Is
+var (
+	// ErrAIUnavailable means the backend cannot be reached or failed,
+	// retrying later may work
+	ErrAIUnavailable = errors.New("AI service unavailable")
+	// ErrModelNotLoaded means the backend does not have the requested model
+	ErrModelNotLoaded = errors.New("AI model not available")
+	// ErrTimeout means the backend did not answer in time
+	ErrTimeout = errors.New("AI request timed out")
+)
+
+// requestError classifies the failure of a request to a backend
+func requestError(err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return fmt.Errorf("%w: %w", ErrAIUnavailable, err)
+}
+
+// statusError reads the body of a failed request into an error classified
+// by the status code
+func statusError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	err := fmt.Errorf("API error %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+
+	switch {
+	case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusGatewayTimeout:
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return fmt.Errorf("%w: %w", ErrAIUnavailable, err)
+	}
+	return err
+}
@@ -0,0 +1,107 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ConversationVersion is the version of the conversations written by
+// ExportJSON
+const ConversationVersion = 1
+
+// ExportedConversation is the JSON form of a conversation: its system
+// prompt and the messages after it, oldest first
+type ExportedConversation struct {
+	Version      int       `json:"version"`
+	SystemPrompt string    `json:"system_prompt,omitempty"`
+	Messages     []Message `json:"messages"`
+}
+
+// newExportedConversation exports the system prompt and the messages
+// other than the system one
+func newExportedConversation(systemPrompt string, messages []Message) ExportedConversation {
+	exported := ExportedConversation{
+		Version:      ConversationVersion,
+		SystemPrompt: systemPrompt,
+		Messages:     make([]Message, 0, len(messages)),
+	}
+	for _, message := range messages {
+		if message.Role != "system" {
+			exported.Messages = append(exported.Messages, message)
+		}
+	}
+	return exported
+}
+
+// encode writes the conversation as indented JSON
+func (e ExportedConversation) encode(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(e)
+}
+
+// DecodeConversation reads a conversation written by ExportJSON or by hand,
+// rejecting unknown fields and messages that are not user or assistant ones
+func DecodeConversation(r io.Reader) (ExportedConversation, error) {
+	var exported ExportedConversation
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&exported); err != nil {
+		return ExportedConversation{}, fmt.Errorf("invalid conversation: %w", err)
+	}
+	if exported.Version > ConversationVersion {
+		return ExportedConversation{}, fmt.Errorf("conversation version %d is not supported, expected %d at most",
+			exported.Version, ConversationVersion)
+	}
+
+	for i, message := range exported.Messages {
+		switch message.Role {
+		case "user", "assistant":
+		case "system":
+			return ExportedConversation{}, fmt.Errorf("message %d: the system prompt goes in system_prompt", i+1)
+		default:
+			return ExportedConversation{}, fmt.Errorf("message %d: unknown role '%s', expected user or assistant", i+1, message.Role)
+		}
+		if strings.TrimSpace(message.Content) == "" {
+			return ExportedConversation{}, fmt.Errorf("message %d: empty content", i+1)
+		}
+	}
+	return exported, nil
+}
+
+// ExportJSON writes the system prompt and the history as indented JSON, to
+// move the conversation to another machine or edit it by hand
+func (c *Conversation) ExportJSON(w io.Writer) error {
+	c.mutex.RLock()
+	exported := newExportedConversation(c.systemPrompt, c.messages)
+	c.mutex.RUnlock()
+
+	return exported.encode(w)
+}
+
+// ImportJSON replaces the history with a conversation read from r, keeping
+// the last messages that fit. Its system prompt replaces the current one,
+// which is kept when it has none.
+func (c *Conversation) ImportJSON(r io.Reader) error {
+	imported, err := DecodeConversation(r)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if imported.SystemPrompt != "" {
+		c.systemPrompt = imported.SystemPrompt
+	}
+	c.messages = make([]Message, 0, len(imported.Messages)+1)
+	if c.systemPrompt != "" {
+		c.messages = append(c.messages, Message{Role: "system", Content: c.systemPrompt})
+	}
+	c.messages = append(c.messages, imported.Messages...)
+	c.trim()
+	return nil
+}
@@ -0,0 +1,86 @@
+package ai
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConversation_ExportImportJSON(t *testing.T) {
+	source := NewConversation(10)
+	source.SetSystemPrompt("Tu es Jack")
+	source.AddMessage(Message{Role: "user", Content: "Bonjour", Meta: NewMetadata(time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC), SourceVoice)})
+	source.AddMessage(Message{Role: "assistant", Content: "Salut <toi> !"})
+
+	var buffer bytes.Buffer
+	if err := source.ExportJSON(&buffer); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(buffer.String(), `"system_prompt": "Tu es Jack"`) || !strings.Contains(buffer.String(), "<toi>") {
+		t.Errorf("Expected readable indented JSON, got %s", buffer.String())
+	}
+
+	target := NewConversation(10)
+	target.SetSystemPrompt("Tu es un assistant")
+	target.AddMessage(Message{Role: "user", Content: "Oublie-moi"})
+	if err := target.ImportJSON(&buffer); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	messages := target.GetMessages()
+	if len(messages) != 3 || messages[0].Role != "system" || messages[0].Content != "Tu es Jack" {
+		t.Fatalf("Expected the system prompt and 2 messages, got %+v", messages)
+	}
+	if messages[1].Meta == nil || messages[1].Meta.Source != SourceVoice || messages[2].Content != "Salut <toi> !" {
+		t.Errorf("Expected the messages with their metadata, got %+v", messages[1:])
+	}
+	if target.GetSystemPrompt() != "Tu es Jack" {
+		t.Errorf("Expected the imported system prompt, got %q", target.GetSystemPrompt())
+	}
+}
+
+func TestConversation_ImportJSONKeepsPromptAndTrims(t *testing.T) {
+	conv := NewConversation(3)
+	conv.SetSystemPrompt("Tu es Jack")
+
+	err := conv.ImportJSON(strings.NewReader(`{"messages": [
+		{"role": "user", "content": "Un"},
+		{"role": "assistant", "content": "Deux"},
+		{"role": "user", "content": "Trois"},
+		{"role": "assistant", "content": "Quatre"}
+	]}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	messages := conv.GetMessages()
+	if len(messages) != 3 || messages[0].Content != "Tu es Jack" || messages[1].Content != "Trois" {
+		t.Errorf("Expected the current prompt and the last messages, got %+v", messages)
+	}
+}
+
+func TestDecodeConversation_Rejects(t *testing.T) {
+	invalid := map[string]string{
+		"syntax":  `{"messages": [`,
+		"unknown": `{"mesages": []}`,
+		"version": `{"version": 2, "messages": []}`,
+		"system":  `{"messages": [{"role": "system", "content": "Tu es Jack"}]}`,
+		"role":    `{"messages": [{"role": "robot", "content": "Bip"}]}`,
+		"empty":   `{"messages": [{"role": "user", "content": " "}]}`,
+	}
+	for name, data := range invalid {
+		if _, err := DecodeConversation(strings.NewReader(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	conv := NewConversation(5)
+	conv.AddMessage(Message{Role: "user", Content: "Bonjour"})
+	if err := conv.ImportJSON(strings.NewReader(`{"messages": [{"role": "robot", "content": "Bip"}]}`)); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if conv.GetMessageCount() != 1 {
+		t.Error("Expected the history untouched by a failed import")
+	}
+}
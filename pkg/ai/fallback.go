@@ -0,0 +1,74 @@
+package ai
+
+import "strings"
+
+// FallbackService asks a primary service first and falls back to another
+// one when it fails or does not understand, e.g. Home Assistant then Ollama
+type FallbackService struct {
+	primary  AIService
+	fallback AIService
+}
+
+// NewFallbackService creates a service chaining primary and fallback
+func NewFallbackService(primary, fallback AIService) *FallbackService {
+	return &FallbackService{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+// Chat returns the primary answer, or the fallback one if the primary failed
+func (f *FallbackService) Chat(request ChatRequest) (ChatResponse, error) {
+	response, err := f.primary.Chat(request)
+	if err == nil && response.Error == "" && strings.TrimSpace(response.Message.Content) != "" {
+		return response, nil
+	}
+	return f.fallback.Chat(request)
+}
+
+// ChatStream streams the primary answer as a single chunk, or the fallback stream
+func (f *FallbackService) ChatStream(request ChatRequest) (<-chan ChatResponse, error) {
+	response, err := f.primary.Chat(request)
+	if err == nil && response.Error == "" && strings.TrimSpace(response.Message.Content) != "" {
+		responseChan := make(chan ChatResponse, 1)
+		responseChan <- response
+		close(responseChan)
+		return responseChan, nil
+	}
+	return f.fallback.ChatStream(request)
+}
+
+// ListModels returns the models of both services
+func (f *FallbackService) ListModels() ([]string, error) {
+	primary, err := f.primary.ListModels()
+	if err != nil {
+		return nil, err
+	}
+	fallback, err := f.fallback.ListModels()
+	if err != nil {
+		return nil, err
+	}
+	return append(primary, fallback...), nil
+}
+
+// IsAvailable reports whether either service is available
+func (f *FallbackService) IsAvailable() bool {
+	return f.primary.IsAvailable() || f.fallback.IsAvailable()
+}
+
+// SetModel changes the model of the services that support it
+func (f *FallbackService) SetModel(model string) {
+	for _, service := range []AIService{f.primary, f.fallback} {
+		if setter, ok := service.(ModelSetter); ok {
+			setter.SetModel(model)
+		}
+	}
+}
+
+// Close closes both services
+func (f *FallbackService) Close() error {
+	if err := f.primary.Close(); err != nil {
+		return err
+	}
+	return f.fallback.Close()
+}
@@ -0,0 +1,185 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HomeAssistantService implements AIService on top of the Home Assistant
+// conversation (Assist) API, so utterances can control devices
+type HomeAssistantService struct {
+	baseURL        string
+	token          string
+	agentID        string
+	language       string
+	httpClient     *http.Client
+	conversationID string
+	mutex          sync.Mutex
+}
+
+// NewHomeAssistantService creates a new Home Assistant conversation service
+// authenticated with a long-lived access token
+func NewHomeAssistantService(baseURL, token, agentID, language string) *HomeAssistantService {
+	if baseURL == "" {
+		baseURL = "http://homeassistant.local:8123"
+	}
+
+	return &HomeAssistantService{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		token:    token,
+		agentID:  agentID,
+		language: language,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// haConversationRequest is the body of POST /api/conversation/process
+type haConversationRequest struct {
+	Text           string `json:"text"`
+	Language       string `json:"language,omitempty"`
+	AgentID        string `json:"agent_id,omitempty"`
+	ConversationID string `json:"conversation_id,omitempty"`
+}
+
+// haConversationResponse is the subset of the Assist response we use
+type haConversationResponse struct {
+	ConversationID string `json:"conversation_id"`
+	Response       struct {
+		ResponseType string `json:"response_type"` // action_done, query_answer or error
+		Speech       struct {
+			Plain struct {
+				Speech string `json:"speech"`
+			} `json:"plain"`
+		} `json:"speech"`
+		Data struct {
+			Code string `json:"code"`
+		} `json:"data"`
+	} `json:"response"`
+}
+
+// Chat sends the last user message to Home Assistant. Utterances Home
+// Assistant does not understand are reported in ChatResponse.Error.
+func (h *HomeAssistantService) Chat(request ChatRequest) (ChatResponse, error) {
+	text := lastUserMessage(request.Messages)
+	if text == "" {
+		return ChatResponse{}, fmt.Errorf("no user message to send")
+	}
+
+	h.mutex.Lock()
+	conversationID := h.conversationID
+	h.mutex.Unlock()
+
+	reqBody, err := json.Marshal(haConversationRequest{
+		Text:           text,
+		Language:       h.language,
+		AgentID:        h.agentID,
+		ConversationID: conversationID,
+	})
+	if err != nil {
+		return ChatResponse{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpRequest, err := http.NewRequest(http.MethodPost, h.baseURL+"/api/conversation/process", bytes.NewBuffer(reqBody))
+	if err != nil {
+		return ChatResponse{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Authorization", "Bearer "+h.token)
+
+	resp, err := h.httpClient.Do(httpRequest)
+	if err != nil {
+		return ChatResponse{}, requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ChatResponse{}, statusError(resp)
+	}
+
+	var result haConversationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return ChatResponse{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	h.mutex.Lock()
+	h.conversationID = result.ConversationID
+	h.mutex.Unlock()
+
+	response := ChatResponse{
+		Model:   "homeassistant",
+		Message: Message{Role: "assistant", Content: result.Response.Speech.Plain.Speech},
+		Done:    true,
+	}
+	if result.Response.ResponseType == "error" {
+		response.Error = fmt.Sprintf("home assistant: %s", result.Response.Data.Code)
+	}
+	return response, nil
+}
+
+// ChatStream returns the whole Home Assistant answer as a single chunk
+func (h *HomeAssistantService) ChatStream(request ChatRequest) (<-chan ChatResponse, error) {
+	return singleResponseStream(h, request)
+}
+
+// ListModels returns the conversation agent in use
+func (h *HomeAssistantService) ListModels() ([]string, error) {
+	if h.agentID == "" {
+		return []string{"homeassistant"}, nil
+	}
+	return []string{h.agentID}, nil
+}
+
+// IsAvailable checks the Home Assistant API is reachable with the token
+func (h *HomeAssistantService) IsAvailable() bool {
+	httpRequest, err := http.NewRequest(http.MethodGet, h.baseURL+"/api/", nil)
+	if err != nil {
+		return false
+	}
+	httpRequest.Header.Set("Authorization", "Bearer "+h.token)
+
+	resp, err := h.httpClient.Do(httpRequest)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// Close closes the HTTP client (no-op for this implementation)
+func (h *HomeAssistantService) Close() error {
+	return nil
+}
+
+// SetTransport sends the requests through transport, e.g. from NewTransport
+func (h *HomeAssistantService) SetTransport(transport http.RoundTripper) {
+	h.httpClient.Transport = transport
+}
+
+// lastUserMessage returns the content of the most recent user message
+func lastUserMessage(messages []Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+	return ""
+}
+
+// singleResponseStream wraps a non-streaming Chat call into a stream
+func singleResponseStream(service AIService, request ChatRequest) (<-chan ChatResponse, error) {
+	response, err := service.Chat(request)
+	if err != nil {
+		return nil, err
+	}
+	responseChan := make(chan ChatResponse, 1)
+	responseChan <- response
+	close(responseChan)
+	return responseChan, nil
+}
@@ -0,0 +1,132 @@
+package ai
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newHomeAssistantServer(t *testing.T, responseType string) (*httptest.Server, *[]haConversationRequest) {
+	t.Helper()
+
+	var requests []haConversationRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/api/" {
+			w.Write([]byte(`{"message": "API running."}`))
+			return
+		}
+
+		var request haConversationRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		requests = append(requests, request)
+
+		speech := "J'ai allumé la lumière du salon."
+		code := ""
+		if responseType == "error" {
+			speech = "Désolé, je n'ai pas compris."
+			code = "no_intent_match"
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"conversation_id": "conv-1",
+			"response": map[string]interface{}{
+				"response_type": responseType,
+				"speech":        map[string]interface{}{"plain": map[string]string{"speech": speech}},
+				"data":          map[string]string{"code": code},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestHomeAssistantService_Chat(t *testing.T) {
+	server, requests := newHomeAssistantServer(t, "action_done")
+	service := NewHomeAssistantService(server.URL, "secret", "", "fr")
+
+	if !service.IsAvailable() {
+		t.Fatal("Expected service to be available")
+	}
+
+	messages := []Message{
+		{Role: "system", Content: "prompt"},
+		{Role: "user", Content: "allume la lumière du salon"},
+	}
+	response, err := service.Chat(ChatRequest{Messages: messages})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if response.Message.Content != "J'ai allumé la lumière du salon." || response.Error != "" {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+
+	// The conversation ID is kept for follow-up utterances
+	service.Chat(ChatRequest{Messages: messages})
+	if len(*requests) != 2 || (*requests)[0].Text != "allume la lumière du salon" || (*requests)[0].Language != "fr" {
+		t.Fatalf("Unexpected requests: %+v", *requests)
+	}
+	if (*requests)[1].ConversationID != "conv-1" {
+		t.Errorf("Expected conversation ID to be reused, got %q", (*requests)[1].ConversationID)
+	}
+}
+
+func TestHomeAssistantService_Unauthorized(t *testing.T) {
+	server, _ := newHomeAssistantServer(t, "action_done")
+	service := NewHomeAssistantService(server.URL, "wrong", "", "fr")
+
+	if service.IsAvailable() {
+		t.Error("Expected service to be unavailable with a wrong token")
+	}
+	if _, err := service.Chat(ChatRequest{Messages: []Message{{Role: "user", Content: "Bonjour"}}}); err == nil {
+		t.Error("Expected error with a wrong token")
+	}
+}
+
+func TestFallbackService_FallsBackWhenNotUnderstood(t *testing.T) {
+	server, _ := newHomeAssistantServer(t, "error")
+	homeAssistant := NewHomeAssistantService(server.URL, "secret", "", "fr")
+
+	ollama := NewMockAIService()
+	ollama.SetResponses([]ChatResponse{{Message: Message{Role: "assistant", Content: "Réponse du LLM"}, Done: true}})
+
+	service := NewFallbackService(homeAssistant, ollama)
+	response, err := service.Chat(ChatRequest{Messages: []Message{{Role: "user", Content: "raconte une blague"}}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if response.Message.Content != "Réponse du LLM" {
+		t.Errorf("Expected fallback answer, got %q", response.Message.Content)
+	}
+}
+
+func TestFallbackService_UsesPrimaryAnswer(t *testing.T) {
+	server, _ := newHomeAssistantServer(t, "action_done")
+	service := NewFallbackService(NewHomeAssistantService(server.URL, "secret", "", "fr"), NewMockAIService())
+
+	stream, err := service.ChatStream(ChatRequest{Messages: []Message{{Role: "user", Content: "allume le salon"}}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var chunks []ChatResponse
+	for chunk := range stream {
+		chunks = append(chunks, chunk)
+	}
+	if len(chunks) != 1 || chunks[0].Message.Content != "J'ai allumé la lumière du salon." {
+		t.Errorf("Expected single Home Assistant chunk, got %+v", chunks)
+	}
+}
+
+func TestFallbackService_SetModel(t *testing.T) {
+	server, _ := newHomeAssistantServer(t, "action_done")
+	ollama := NewOllamaService("http://localhost:11434", "llama3.2:3b")
+	service := NewFallbackService(NewHomeAssistantService(server.URL, "secret", "", "fr"), ollama)
+
+	service.SetModel("mistral")
+	if ollama.GetModel() != "mistral" {
+		t.Errorf("Expected fallback model 'mistral', got '%s'", ollama.GetModel())
+	}
+}
@@ -1,9 +1,14 @@
+// Package ai talks to chat backends (Ollama, Home Assistant) and keeps the
+// conversation history sent to them.
 package ai
 
+import "io"
+
 // Message represents a single message in a conversation
 type Message struct {
-	Role    string `json:"role"`    // "user", "assistant", "system"
-	Content string `json:"content"` // Message content
+	Role    string    `json:"role"`           // "user", "assistant", "system"
+	Content string    `json:"content"`        // Message content
+	Meta    *Metadata `json:"meta,omitempty"` // Provenance, never sent to the backends
 }
 
 // ChatRequest represents a chat completion request
@@ -43,6 +48,18 @@ type AIService interface {
 	Close() error
 }
 
+// ModelSetter is implemented by services whose model can change at runtime
+type ModelSetter interface {
+	// SetModel changes the model of the next requests
+	SetModel(model string)
+}
+
+// LanguageSetter is implemented by services told the user language
+type LanguageSetter interface {
+	// SetLanguage changes the language of the next requests
+	SetLanguage(language string)
+}
+
 // ConversationManager handles conversation context
 type ConversationManager interface {
 	// AddMessage adds a message to the conversation
@@ -56,4 +73,19 @@ type ConversationManager interface {
 	
 	// SetSystemPrompt sets the system prompt
 	SetSystemPrompt(prompt string)
+
+	// ReplaceSystemPrompt sets the system prompt, keeping the history, and
+	// returns the previous one
+	ReplaceSystemPrompt(prompt string) string
+
+	// RemoveLast removes the last n messages, never the system prompt, and
+	// returns them oldest first
+	RemoveLast(n int) []Message
+
+	// ExportJSON writes the system prompt and the history as JSON
+	ExportJSON(w io.Writer) error
+
+	// ImportJSON replaces the history with a conversation written by
+	// ExportJSON
+	ImportJSON(r io.Reader) error
 }
\ No newline at end of file
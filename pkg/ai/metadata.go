@@ -0,0 +1,38 @@
+package ai
+
+import "time"
+
+// Sources of the user messages
+const (
+	SourceVoice = "voice" // Transcribed speech
+	SourceText  = "text"  // Typed in nrz-ai chat or sent to the control socket
+	SourceAPI   = "api"   // Sent to the HTTP API
+
+	SourceTelegram = "telegram" // Sent to the Telegram bot, typed or as a voice message
+)
+
+// Metadata tells when and how a message was produced. It is kept in the
+// conversation history and exports, never sent to the backends.
+type Metadata struct {
+	Time       time.Time `json:"time"`
+	Source     string    `json:"source,omitempty"`     // Source of a user message
+	Duration   float64   `json:"duration,omitempty"`   // Seconds of audio of a voice message
+	Confidence float64   `json:"confidence,omitempty"` // Mean Whisper confidence of a voice message
+	Model      string    `json:"model,omitempty"`      // Model of an answer
+}
+
+// NewMetadata returns the metadata of a message from source produced at t
+func NewMetadata(t time.Time, source string) *Metadata {
+	return &Metadata{Time: t, Source: source}
+}
+
+// withoutMetadata returns a copy of messages without their metadata, as
+// sent to the backends
+func withoutMetadata(messages []Message) []Message {
+	stripped := make([]Message, len(messages))
+	for i, message := range messages {
+		message.Meta = nil
+		stripped[i] = message
+	}
+	return stripped
+}
@@ -1,5 +1,7 @@
 package ai
 
+import "io"
+
 // MockAIService implements AIService for testing
 type MockAIService struct {
 	responses     []ChatResponse
@@ -159,4 +161,39 @@ func (m *MockConversationManager) ClearHistory() {
 // SetSystemPrompt sets the mock system prompt
 func (m *MockConversationManager) SetSystemPrompt(prompt string) {
 	m.systemPrompt = prompt
+}
+
+// ReplaceSystemPrompt sets the mock system prompt, returning the previous one
+func (m *MockConversationManager) ReplaceSystemPrompt(prompt string) string {
+	previous := m.systemPrompt
+	m.systemPrompt = prompt
+	return previous
+}
+
+// ExportJSON writes the mock system prompt and messages
+func (m *MockConversationManager) ExportJSON(w io.Writer) error {
+	return newExportedConversation(m.systemPrompt, m.messages).encode(w)
+}
+
+// ImportJSON replaces the mock messages, and the system prompt when set
+func (m *MockConversationManager) ImportJSON(r io.Reader) error {
+	imported, err := DecodeConversation(r)
+	if err != nil {
+		return err
+	}
+	if imported.SystemPrompt != "" {
+		m.systemPrompt = imported.SystemPrompt
+	}
+	m.messages = imported.Messages
+	return nil
+}
+
+// RemoveLast removes the last n mock messages
+func (m *MockConversationManager) RemoveLast(n int) []Message {
+	if n > len(m.messages) {
+		n = len(m.messages)
+	}
+	removed := append([]Message(nil), m.messages[len(m.messages)-n:]...)
+	m.messages = m.messages[:len(m.messages)-n]
+	return removed
 }
\ No newline at end of file
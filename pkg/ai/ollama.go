@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -15,6 +16,7 @@ type OllamaService struct {
 	baseURL    string
 	httpClient *http.Client
 	model      string
+	modelMutex sync.RWMutex
 	timeout    time.Duration
 }
 
@@ -39,8 +41,9 @@ func NewOllamaService(baseURL, model string) *OllamaService {
 
 // Chat sends a message to Ollama and returns the response
 func (o *OllamaService) Chat(request ChatRequest) (ChatResponse, error) {
-	request.Model = o.model
+	request.Model = o.GetModel()
 	request.Stream = false
+	request.Messages = withoutMetadata(request.Messages)
 
 	reqBody, err := json.Marshal(request)
 	if err != nil {
@@ -53,13 +56,12 @@ func (o *OllamaService) Chat(request ChatRequest) (ChatResponse, error) {
 		bytes.NewBuffer(reqBody),
 	)
 	if err != nil {
-		return ChatResponse{}, fmt.Errorf("failed to send request: %w", err)
+		return ChatResponse{}, requestError(err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return ChatResponse{}, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return ChatResponse{}, o.statusError(resp)
 	}
 
 	// Read the full response body
@@ -106,8 +108,9 @@ func (o *OllamaService) Chat(request ChatRequest) (ChatResponse, error) {
 
 // ChatStream sends a message and returns a streaming response
 func (o *OllamaService) ChatStream(request ChatRequest) (<-chan ChatResponse, error) {
-	request.Model = o.model
+	request.Model = o.GetModel()
 	request.Stream = true
+	request.Messages = withoutMetadata(request.Messages)
 
 	reqBody, err := json.Marshal(request)
 	if err != nil {
@@ -120,13 +123,12 @@ func (o *OllamaService) ChatStream(request ChatRequest) (<-chan ChatResponse, er
 		bytes.NewBuffer(reqBody),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, requestError(err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		resp.Body.Close()
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		defer resp.Body.Close()
+		return nil, o.statusError(resp)
 	}
 
 	responseChan := make(chan ChatResponse)
@@ -157,16 +159,26 @@ func (o *OllamaService) ChatStream(request ChatRequest) (<-chan ChatResponse, er
 	return responseChan, nil
 }
 
+// statusError classifies a failed request, Ollama answers 404 for the
+// models it does not have
+func (o *OllamaService) statusError(resp *http.Response) error {
+	err := statusError(resp)
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %w", ErrModelNotLoaded, err)
+	}
+	return err
+}
+
 // ListModels returns available models from Ollama
 func (o *OllamaService) ListModels() ([]string, error) {
 	resp, err := o.httpClient.Get(fmt.Sprintf("%s/api/tags", o.baseURL))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get models: %w", err)
+		return nil, requestError(err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error %d", resp.StatusCode)
+		return nil, o.statusError(resp)
 	}
 
 	var result struct {
@@ -202,16 +214,25 @@ func (o *OllamaService) Close() error {
 	return nil
 }
 
-// SetModel changes the model used for requests
+// SetModel changes the model used for requests, safe to call while chatting
 func (o *OllamaService) SetModel(model string) {
+	o.modelMutex.Lock()
+	defer o.modelMutex.Unlock()
 	o.model = model
 }
 
 // GetModel returns the current model
 func (o *OllamaService) GetModel() string {
+	o.modelMutex.RLock()
+	defer o.modelMutex.RUnlock()
 	return o.model
 }
 
+// SetTransport sends the requests through transport, e.g. from NewTransport
+func (o *OllamaService) SetTransport(transport http.RoundTripper) {
+	o.httpClient.Transport = transport
+}
+
 // SetTimeout sets the request timeout
 func (o *OllamaService) SetTimeout(timeout time.Duration) {
 	o.timeout = timeout
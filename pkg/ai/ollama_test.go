@@ -0,0 +1,129 @@
+package ai
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newOllamaServer answers /api/chat with status and the NDJSON lines
+func newOllamaServer(t *testing.T, status int, lines ...string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		for _, line := range lines {
+			w.Write([]byte(line + "\n"))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOllamaService_ChatStream(t *testing.T) {
+	server := newOllamaServer(t, http.StatusOK,
+		`{"model":"llama3.2:3b","message":{"role":"assistant","content":"Bon"},"done":false}`,
+		`{"model":"llama3.2:3b","message":{"role":"assistant","content":"jour"},"done":false}`,
+		`{"model":"llama3.2:3b","message":{"role":"assistant","content":""},"done":true}`)
+
+	stream, err := NewOllamaService(server.URL, "").ChatStream(ChatRequest{Messages: []Message{{Role: "user", Content: "Salut"}}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var tokens []string
+	response, err := AggregateStream(stream, func(token string) { tokens = append(tokens, token) })
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if response.Message.Content != "Bonjour" || !response.Done || response.Model != "llama3.2:3b" {
+		t.Errorf("Expected the aggregated final response, got %+v", response)
+	}
+	if len(tokens) != 2 || tokens[0] != "Bon" || tokens[1] != "jour" {
+		t.Errorf("Expected each token reported, got %v", tokens)
+	}
+}
+
+func TestOllamaService_ChatLeavesMetadataOut(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		w.Write([]byte(`{"message":{"role":"assistant","content":"Oui"},"done":true}` + "\n"))
+	}))
+	defer server.Close()
+
+	messages := []Message{{Role: "user", Content: "Salut", Meta: NewMetadata(time.Now(), SourceVoice)}}
+	if _, err := NewOllamaService(server.URL, "").Chat(ChatRequest{Messages: messages}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if strings.Contains(string(body), "meta") || !strings.Contains(string(body), `"content":"Salut"`) {
+		t.Errorf("Expected the message without its metadata, got %s", body)
+	}
+	if messages[0].Meta == nil {
+		t.Error("Expected the metadata of the caller to be kept")
+	}
+}
+
+func TestOllamaService_ChatStreamAPIError(t *testing.T) {
+	server := newOllamaServer(t, http.StatusNotFound, `{"error":"model 'mistral' not found"}`)
+
+	_, err := NewOllamaService(server.URL, "mistral").ChatStream(ChatRequest{})
+	if err == nil {
+		t.Fatal("Expected error for a 404 answer")
+	}
+	if !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "model 'mistral' not found") {
+		t.Errorf("Expected the status and the body in the error, got: %v", err)
+	}
+}
+
+func TestOllamaService_ErrorClasses(t *testing.T) {
+	missing := newOllamaServer(t, http.StatusNotFound, `{"error":"model 'mistral' not found"}`)
+	if _, err := NewOllamaService(missing.URL, "mistral").Chat(ChatRequest{}); !errors.Is(err, ErrModelNotLoaded) {
+		t.Errorf("Expected ErrModelNotLoaded for a 404, got: %v", err)
+	}
+
+	failing := newOllamaServer(t, http.StatusInternalServerError, `{"error":"out of memory"}`)
+	if _, err := NewOllamaService(failing.URL, "").ChatStream(ChatRequest{}); !errors.Is(err, ErrAIUnavailable) {
+		t.Errorf("Expected ErrAIUnavailable for a 500, got: %v", err)
+	}
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	if _, err := NewOllamaService(down.URL, "").Chat(ChatRequest{}); !errors.Is(err, ErrAIUnavailable) {
+		t.Errorf("Expected ErrAIUnavailable without server, got: %v", err)
+	}
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slow.Close()
+	service := NewOllamaService(slow.URL, "")
+	service.httpClient.Timeout = 20 * time.Millisecond
+	if _, err := service.Chat(ChatRequest{}); !errors.Is(err, ErrTimeout) {
+		t.Errorf("Expected ErrTimeout, got: %v", err)
+	}
+}
+
+func TestOllamaService_ChatStreamTruncated(t *testing.T) {
+	server := newOllamaServer(t, http.StatusOK,
+		`{"message":{"role":"assistant","content":"Bon"},"done":false}`,
+		`{"message":{"role":"assist`)
+
+	stream, err := NewOllamaService(server.URL, "").ChatStream(ChatRequest{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	response, err := AggregateStream(stream, nil)
+	if err == nil || !strings.Contains(err.Error(), "decode error") {
+		t.Errorf("Expected a decode error, got: %v", err)
+	}
+	if response.Message.Content != "Bon" {
+		t.Errorf("Expected the content received before the error, got '%s'", response.Message.Content)
+	}
+}
@@ -0,0 +1,36 @@
+package ai
+
+import (
+	"errors"
+	"strings"
+)
+
+// AggregateStream reads a ChatStream to its end and returns the answer as
+// Chat would: the last chunk with the content of all of them. onToken, if
+// set, receives every piece of content as it arrives. A chunk carrying an
+// error ends the stream, it is returned with the content received so far.
+func AggregateStream(stream <-chan ChatResponse, onToken func(token string)) (ChatResponse, error) {
+	var content strings.Builder
+	var response ChatResponse
+	for chunk := range stream {
+		if chunk.Error != "" {
+			// Never leave the sender blocked on what follows the error
+			go func() {
+				for range stream {
+				}
+			}()
+			chunk.Message = Message{Role: "assistant", Content: content.String()}
+			return chunk, errors.New(chunk.Error)
+		}
+		if chunk.Message.Content != "" {
+			content.WriteString(chunk.Message.Content)
+			if onToken != nil {
+				onToken(chunk.Message.Content)
+			}
+		}
+		response = chunk
+	}
+
+	response.Message = Message{Role: "assistant", Content: content.String()}
+	return response, nil
+}
@@ -0,0 +1,90 @@
+package ai
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTLSOllamaServer answers /api/tags over HTTPS and writes its certificate
+// to a CA file
+func newTLSOllamaServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"models":[]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certificate, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return server, caFile
+}
+
+func TestNewTransport_TLS(t *testing.T) {
+	server, caFile := newTLSOllamaServer(t)
+
+	tests := map[string]struct {
+		opts    TransportOptions
+		success bool
+	}{
+		"system roots": {TransportOptions{}, false},
+		"CA file":      {TransportOptions{CAFile: caFile}, true},
+		"insecure":     {TransportOptions{InsecureSkipVerify: true}, true},
+	}
+	for name, test := range tests {
+		transport, err := NewTransport(test.opts)
+		if err != nil {
+			t.Fatalf("%s: failed to create transport: %v", name, err)
+		}
+		service := NewOllamaService(server.URL, "")
+		service.SetTransport(transport)
+
+		if _, err := service.ListModels(); (err == nil) != test.success {
+			t.Errorf("%s: expected success %v, got %v", name, test.success, err)
+		}
+	}
+}
+
+func TestNewTransport_Proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.Write([]byte(`{"models":[]}`))
+	}))
+	defer proxy.Close()
+
+	transport, err := NewTransport(TransportOptions{Proxy: proxy.URL})
+	if err != nil {
+		t.Fatalf("Failed to create transport: %v", err)
+	}
+	service := NewOllamaService("http://ollama.invalid:11434", "")
+	service.SetTransport(transport)
+	service.ListModels()
+
+	if proxied != "http://ollama.invalid:11434/api/tags" {
+		t.Errorf("Expected the request through the proxy, got %q", proxied)
+	}
+}
+
+func TestNewTransport_Errors(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	os.WriteFile(empty, nil, 0600)
+
+	for name, opts := range map[string]TransportOptions{
+		"missing CA":    {CAFile: "/nonexistent/ca.pem"},
+		"empty CA":      {CAFile: empty},
+		"missing key":   {CertFile: "/nonexistent/cert.pem"},
+		"invalid proxy": {Proxy: "://proxy"},
+	} {
+		if _, err := NewTransport(opts); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package ai
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Watcher polls the availability of an AI service in the background, so
+// that a backend started late or restarted is picked up again
+type Watcher struct {
+	service   AIService
+	interval  time.Duration
+	onChange  func(available bool)
+	available atomic.Bool
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewWatcher creates a watcher checking service every interval and calling
+// onChange whenever its availability changes
+func NewWatcher(service AIService, interval time.Duration, onChange func(available bool)) *Watcher {
+	return &Watcher{
+		service:  service,
+		interval: interval,
+		onChange: onChange,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start watches the service until Close, available is its current state
+func (w *Watcher) Start(available bool) {
+	w.available.Store(available)
+	go w.watch()
+}
+
+// watch checks the service every interval
+func (w *Watcher) watch() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.done:
+			return
+		}
+
+		available := w.service.IsAvailable()
+		if w.available.Swap(available) != available {
+			w.onChange(available)
+		}
+	}
+}
+
+// Available reports the availability seen by the last check
+func (w *Watcher) Available() bool {
+	return w.available.Load()
+}
+
+// Close stops watching the service
+func (w *Watcher) Close() {
+	w.closeOnce.Do(func() { close(w.done) })
+}
@@ -0,0 +1,54 @@
+package ai
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatcher_ReportsChanges(t *testing.T) {
+	var up atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	changes := make(chan bool, 4)
+	watcher := NewWatcher(NewOllamaService(server.URL, ""), 5*time.Millisecond, func(available bool) {
+		changes <- available
+	})
+	watcher.Start(false)
+	defer watcher.Close()
+
+	for _, expected := range []bool{true, false} {
+		up.Store(expected)
+		select {
+		case available := <-changes:
+			if available != expected {
+				t.Fatalf("Expected available=%v, got %v", expected, available)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected a change to available=%v", expected)
+		}
+		if watcher.Available() != expected {
+			t.Errorf("Expected Available() to be %v", expected)
+		}
+	}
+
+	// No change, no callback
+	time.Sleep(20 * time.Millisecond)
+	if len(changes) != 0 {
+		t.Errorf("Expected no more changes, got %d", len(changes))
+	}
+}
+
+func TestWatcher_CloseTwice(t *testing.T) {
+	watcher := NewWatcher(NewMockAIService(), time.Hour, func(bool) {})
+	watcher.Start(true)
+	watcher.Close()
+	watcher.Close()
+}
@@ -0,0 +1,1752 @@
+// Package assistant runs the nrz-ai voice assistant: audio is captured,
+// cut into utterances by a voice activity detector, transcribed with
+// Whisper and answered with voice commands, local intents or an AI backend.
+// Everything that happens is published as events on a bus.
+package assistant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"github.com/nerzhul/nrz-ai/internal/blocklist"
+	"github.com/nerzhul/nrz-ai/internal/clipboard"
+	"github.com/nerzhul/nrz-ai/internal/control"
+	"github.com/nerzhul/nrz-ai/internal/diarization"
+	"github.com/nerzhul/nrz-ai/internal/dictation"
+	"github.com/nerzhul/nrz-ai/internal/intents"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/internal/meeting"
+	"github.com/nerzhul/nrz-ai/internal/pipeline"
+	"github.com/nerzhul/nrz-ai/internal/redact"
+	"github.com/nerzhul/nrz-ai/internal/transcript"
+	"github.com/nerzhul/nrz-ai/internal/translate"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+	"github.com/nerzhul/nrz-ai/pkg/vad"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+const (
+	// SampleRate is the rate of the mono 32-bit float audio of the pipeline
+	SampleRate = 16000
+	// ReadChunkSize is the size in bytes of a single audio capture read
+	ReadChunkSize = 4096
+	// MaxBufferDurationS bounds the length of an utterance, in seconds
+	MaxBufferDurationS = 30
+
+	silenceThreshold    = 0.01
+	silenceDurationMs   = 800
+	minSpeechDurationMs = 500
+	listeningTimeoutS   = 30
+	rmsWindowSize       = 160
+	noiseFloorSamples   = 32000
+	defaultMaxHistory   = 10
+
+	// No audio read for this long means the capture is stuck
+	captureStallTimeout = 10 * time.Second
+)
+
+var (
+	errAIDisabled  = errors.New("the AI is disabled")
+	errEmptyAnswer = errors.New("the AI returned an empty response")
+)
+
+// Activation sources, reported as the State of wake_word events
+const (
+	// ActivatedByVoice is the wake word being heard
+	ActivatedByVoice = "voice"
+	// ActivatedRemotely is the control socket, D-Bus or MQTT
+	ActivatedRemotely = "remote"
+	// ActivatedByHTTP is a POST /activate on the API server
+	ActivatedByHTTP = "http"
+	// ActivatedByGPIO is a hardware push-button
+	ActivatedByGPIO = "gpio"
+)
+
+// QueuePolicy defines what happens when the transcription queue is full
+type QueuePolicy string
+
+const (
+	// QueuePolicyBlock makes audio capture wait until the transcriber frees a slot
+	QueuePolicyBlock QueuePolicy = "block"
+	// QueuePolicyDropOldest discards the oldest pending segment to make room
+	QueuePolicyDropOldest QueuePolicy = "drop-oldest"
+	// QueuePolicyMerge joins the pending segments into a single longer one,
+	// transcribed in one pass
+	QueuePolicyMerge QueuePolicy = "merge"
+	// QueuePolicyFallbackModel blocks like QueuePolicyBlock and transcribes
+	// with a smaller model until the queue is drained, see SetFallbackModel
+	QueuePolicyFallbackModel QueuePolicy = "fallback-model"
+)
+
+// ParseQueuePolicy validates a queue policy name
+func ParseQueuePolicy(name string) (QueuePolicy, error) {
+	switch QueuePolicy(name) {
+	case QueuePolicyBlock, QueuePolicyDropOldest, QueuePolicyMerge, QueuePolicyFallbackModel:
+		return QueuePolicy(name), nil
+	default:
+		return "", fmt.Errorf("unknown queue policy '%s' (expected %s, %s, %s or %s)",
+			name, QueuePolicyBlock, QueuePolicyDropOldest, QueuePolicyMerge, QueuePolicyFallbackModel)
+	}
+}
+
+// OverloadStats counts what the queue policy did while transcription could
+// not keep up with real time
+type OverloadStats struct {
+	DroppedSegments  int           // Segments discarded unheard
+	DroppedAudio     time.Duration // Audio of the discarded segments
+	MergedSegments   int           // Segments joined to a pending one
+	FallbackSwitches int           // Times the fallback model was loaded
+}
+
+// speechSegment is a transcribed utterance
+type speechSegment struct {
+	samples []float32
+	start   time.Time     // Wall-clock time the utterance started
+	offset  time.Duration // Stream position the utterance started at
+}
+
+// newSpeechSegment converts a segment cut by the pipeline
+func newSpeechSegment(segment pipeline.Segment) speechSegment {
+	return speechSegment{
+		samples: segment.Samples,
+		start:   segment.Time.Add(-segment.Duration(SampleRate)),
+		offset:  time.Duration(segment.Offset) * time.Second / SampleRate,
+	}
+}
+
+// Assistant is the live voice assistant: it transcribes what is said and
+// answers it with voice commands, local intents or the AI.
+//
+// The Set* options must be called before ProcessStream. The remote controls
+// (Activate, Deactivate, Pause, Resume, SetLanguage, SetPersona, Say, Status,
+// Stop...) are safe to call from any goroutine while it runs. The audio
+// state, wake word buffer and stream position, is owned by the wake word
+// stage.
+type Assistant struct {
+	audioCapture   audio.AudioCapture
+	audioProcessor audio.AudioProcessor
+	segmenter      *pipeline.Segmenter
+	whisperService whisper.WhisperService
+	aiService      ai.AIService
+	conversation   ai.ConversationManager
+	// One AI exchange at a time so that typed and spoken requests don't
+	// interleave in the conversation history
+	chatMutex sync.Mutex
+
+	streamSamples int64 // Stream position of the wake word gate
+	language      string
+	aiEnabled     bool
+	// Cleared while the AI backend is unreachable, see SetAIAvailable
+	aiAvailable atomic.Bool
+
+	// Wake word detection
+	wakeWordEnabled bool
+	wakeWord        string
+	wakeWordBuffer  []float32
+	// Stream position (in samples) listening stops at, -1 to start the
+	// countdown on the next sample
+	listeningDeadline atomic.Int64
+
+	// Transcription queue
+	queueSize            int
+	queuePolicy          QueuePolicy
+	transcriptionTimeout time.Duration
+	partials             bool // Segments emitted as they are decoded
+
+	// Overload handling, the counters are guarded by stateMutex. The
+	// fallback model is loaded while wantFallback is set.
+	overload      OverloadStats
+	counters      audioCounters
+	statsInterval time.Duration
+	modelPath     string
+	fallbackModel string
+	wantFallback  atomic.Bool
+	onFallback    bool
+	modelMutex    sync.Mutex
+
+	// Optional speaker diarization
+	diarizer diarization.Diarizer
+
+	// Optional speaker identification, see SetSpeakers. The conversations
+	// of the enrolled speakers are guarded by speakersMutex.
+	speakers       SpeakerProfiles
+	speakerPrompt  string
+	speakerHistory int
+	conversations  map[string]ai.ConversationManager
+	speakersMutex  sync.Mutex
+
+	// Optional masking of personal data in transcripts
+	redactor *redact.Redactor
+
+	// Optional phrases Whisper makes up, dropped from the transcripts
+	blocklist *blocklist.Blocklist
+
+	// Transcripts with fewer words or a lower mean confidence are not
+	// answered, see SetAnswerGate. Guarded by stateMutex.
+	minWords      int
+	aiTrigger     AITrigger
+	minConfidence float64
+
+	// Meeting minutes recorder (meeting mode)
+	meetingRecorder *meeting.Recorder
+
+	// Keystroke injection (dictation mode)
+	dictation *dictation.Dictation
+
+	// Live interpreting (translate mode)
+	translator translate.Translator
+
+	// Pipeline events; the console writer is subscribed until JSON output
+	// replaces it
+	bus           *output.Bus
+	console       func()
+	consoleWriter *output.ConsoleWriter
+	timestamps    *output.Timestamps
+
+	// Optional input level listener, called for every audio chunk
+	levelMeter func(level float32, calibrated bool)
+
+	// Optional live captions file
+	captions *transcript.CaptionWriter
+
+	// Voice commands and local intents answered before the AI
+	commands *intents.Router
+	intents  *intents.Router
+
+	// Optional clipboard output
+	clipboard       clipboard.Clipboard
+	clipboardTarget clipboard.Target
+
+	// Most recent final transcript and language, changed by remote controls
+	lastTranscript string
+	lastReply      string
+	askedReply     string // Answer of Ask, output already, for the command returning it
+	lastSpeaker    string // Speaker of the last AI exchange, empty for a guest
+	lastLatency    Latency
+	stateMutex     sync.Mutex
+
+	// Automatic language switching, see SetLanguageSwitch, guarded by
+	// stateMutex
+	languageSwitch  *languageSwitcher
+	languagePrompts map[string]string
+	languageLocked  bool
+
+	// Audio is read but discarded while paused
+	paused atomic.Bool
+
+	// Assistant state, every transition is emitted as a state event
+	state *Machine
+
+	// Graceful shutdown: Stop ends the capture stage, the others drain
+	// their input; Abort also cancels them and closes the stream when it
+	// blocks. lastRead (unix nanoseconds) feeds the watchdog, captureErr
+	// is the read error that ended the capture, if any.
+	stopping    atomic.Bool
+	stopCtx     context.Context
+	stopCapture context.CancelFunc
+	lastRead    atomic.Int64
+	watchdog    *audio.WatchdogCapture
+	stream      audio.AudioStream
+	captureErr  error
+	streamMutex sync.Mutex
+
+	// Clock of the timestamps, replaced by the recorded one on replay
+	now func() time.Time
+
+	// Cancelled on Close to abort in-flight transcriptions
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Options configures an assistant. Only Whisper is required.
+type Options struct {
+	// Whisper transcribes the utterances
+	Whisper whisper.WhisperService
+	// Capture opens the audio source, ffmpeg by default
+	Capture audio.AudioCapture
+	// Processor converts the captured bytes to samples, 32-bit float PCM by default
+	Processor audio.AudioProcessor
+	// Detector finds speech in the audio, the RMS detector by default
+	Detector vad.VoiceActivityDetector
+	// AI answers the transcripts, nil to only transcribe
+	AI ai.AIService
+	// Conversation keeps the AI history, a new one by default when AI is set
+	Conversation ai.ConversationManager
+	// WakeWord only listens after the wake word was heard, empty listens
+	// permanently
+	WakeWord string
+	// Outputs receive every pipeline event, next to the console lines
+	Outputs []output.Emitter
+	// Quiet drops the console lines, e.g. when an output prints JSON
+	Quiet bool
+}
+
+// New creates an assistant from opts, then with applied in order. Initialize
+// it, then run it with ProcessStream.
+func New(opts Options, with ...Option) (*Assistant, error) {
+	for _, option := range with {
+		option(&opts)
+	}
+	if opts.Whisper == nil {
+		return nil, errors.New("a Whisper service is required")
+	}
+	if opts.Capture == nil {
+		opts.Capture = audio.NewFFmpegCapture(audio.FFmpegOptions{})
+	}
+	if opts.Processor == nil {
+		opts.Processor = audio.NewProcessor()
+	}
+	if opts.Detector == nil {
+		opts.Detector = vad.NewRMSDetector()
+	}
+	if opts.AI != nil && opts.Conversation == nil {
+		opts.Conversation = ai.NewConversation(defaultMaxHistory)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopCtx, stopCapture := context.WithCancel(ctx)
+
+	a := &Assistant{
+		audioCapture:    opts.Capture,
+		audioProcessor:  opts.Processor,
+		segmenter:       pipeline.NewSegmenter(opts.Detector, DefaultVADConfig(), SampleRate*MaxBufferDurationS),
+		whisperService:  opts.Whisper,
+		aiService:       opts.AI,
+		conversation:    opts.Conversation,
+		language:        "fr",
+		aiEnabled:       opts.AI != nil,
+		wakeWordEnabled: opts.WakeWord != "",
+		wakeWord:        opts.WakeWord,
+		wakeWordBuffer:  make([]float32, 0, SampleRate*2), // 2 seconds for wake word detection
+		state:           NewMachine(),
+		bus:             output.NewBus(),
+		queueSize:       4,
+		queuePolicy:     QueuePolicyBlock,
+		minWords:        1,
+		statsInterval:   30 * time.Second,
+		stopCtx:         stopCtx,
+		stopCapture:     stopCapture,
+		now:             time.Now,
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+	a.aiAvailable.Store(true)
+	a.consoleWriter = output.NewConsoleWriter(os.Stdout)
+	a.console = a.bus.Subscribe(a.consoleWriter, output.ConsoleEvents...)
+	if opts.Quiet {
+		a.console()
+	}
+	for _, emitter := range opts.Outputs {
+		a.AddEmitter(emitter)
+	}
+	a.segmenter.SetSpeechListener(a.emitVADState)
+	a.state.Subscribe(a.emitState)
+	return a, nil
+}
+
+// SetTranscriptionQueue configures the size and back-pressure policy of the
+// queue feeding the transcription stage. Must be called before ProcessStream.
+func (a *Assistant) SetTranscriptionQueue(size int, policy QueuePolicy) {
+	if size <= 0 {
+		size = 1
+	}
+	a.queueSize = size
+	a.queuePolicy = policy
+}
+
+// SetFallbackModel sets the smaller model loaded by the fallback-model queue
+// policy while transcription lags behind
+func (a *Assistant) SetFallbackModel(modelPath string) {
+	a.fallbackModel = modelPath
+}
+
+// SetClock replaces the clock used to timestamp transcripts and events
+func (a *Assistant) SetClock(now func() time.Time) {
+	a.now = now
+	a.state.SetClock(now)
+}
+
+// SetTimestamps sets the format of the console timestamps. Elapsed
+// timestamps count from the start of ProcessStream. Must be called before
+// ProcessStream.
+func (a *Assistant) SetTimestamps(timestamps *output.Timestamps) {
+	a.timestamps = timestamps
+	a.consoleWriter.SetTimestamps(timestamps)
+}
+
+// SetTranscriptionTimeout bounds the time spent transcribing a single
+// utterance. Zero disables the timeout.
+func (a *Assistant) SetTranscriptionTimeout(timeout time.Duration) {
+	a.transcriptionTimeout = timeout
+}
+
+// SetPartialTranscripts emits the segments of every utterance as partial
+// events as soon as whisper decodes them, before its transcript
+func (a *Assistant) SetPartialTranscripts(enabled bool) {
+	a.partials = enabled
+}
+
+// SetCaptureWatchdog restarts the capture once the source sent no audio for
+// timeout, e.g. a Bluetooth microphone that dropped. Zero disables it.
+func (a *Assistant) SetCaptureWatchdog(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+	a.watchdog = audio.NewWatchdogCapture(a.audioCapture, timeout, func(err error) {
+		a.captureStalled(timeout, err)
+	})
+	a.audioCapture = a.watchdog
+}
+
+// captureStalled reports a capture restarted by the watchdog
+func (a *Assistant) captureStalled(timeout time.Duration, err error) {
+	if err != nil {
+		logger.WithError(err).Warnf("⚠️  No audio for %s, failed to restart the capture", timeout)
+	} else {
+		logger.Warnf("⚠️  No audio for %s, capture restarted", timeout)
+	}
+	a.emit(output.Event{Type: output.EventError, Error: fmt.Sprintf("audio capture stalled for %s", timeout)})
+}
+
+// CaptureStalls returns the number of capture restarts of the watchdog
+func (a *Assistant) CaptureStalls() int {
+	if a.watchdog == nil {
+		return 0
+	}
+	return a.watchdog.Stalls()
+}
+
+// SetDiarizer enables speaker labels on transcribed segments
+func (a *Assistant) SetDiarizer(diarizer diarization.Diarizer) {
+	a.diarizer = diarizer
+}
+
+// SetRedactor masks personal data in the transcripts before they are
+// shown, written, sent to the integrations or to the AI
+func (a *Assistant) SetRedactor(redactor *redact.Redactor) {
+	a.redactor = redactor
+}
+
+// SetAnswerGate only answers transcripts of at least minWords words whose
+// mean segment confidence reaches minConfidence (0-1), so that Whisper
+// noise such as "..." or hallucinated fragments does not reach the intents
+// and the AI. Zero disables a check.
+func (a *Assistant) SetAnswerGate(minConfidence float64, minWords int) {
+	a.stateMutex.Lock()
+	defer a.stateMutex.Unlock()
+	a.minConfidence = minConfidence
+	a.minWords = minWords
+}
+
+// SetBlocklist drops the phrases of list from the transcripts before
+// they are shown, written or answered
+func (a *Assistant) SetBlocklist(list *blocklist.Blocklist) {
+	a.blocklist = list
+}
+
+// SetMeetingRecorder records every transcript into meeting minutes
+func (a *Assistant) SetMeetingRecorder(recorder *meeting.Recorder) {
+	a.meetingRecorder = recorder
+}
+
+// SetDictation types every transcript into the focused window
+func (a *Assistant) SetDictation(d *dictation.Dictation) {
+	a.dictation = d
+}
+
+// SetTranslator translates every transcript, the translations follow the
+// transcripts as output.EventTranslation events
+func (a *Assistant) SetTranslator(translator translate.Translator) {
+	a.translator = translator
+}
+
+// SetEventWriter reports the pipeline events to events, e.g. an
+// output.JSONWriter, instead of console lines
+func (a *Assistant) SetEventWriter(events output.Emitter) {
+	a.console()
+	a.AddEmitter(events)
+}
+
+// SetLevelMeter reports the RMS level of every audio chunk and whether
+// the VAD noise floor is calibrated
+func (a *Assistant) SetLevelMeter(meter func(level float32, calibrated bool)) {
+	a.levelMeter = meter
+}
+
+// AddEmitter sends every pipeline event to an additional listener
+func (a *Assistant) AddEmitter(emitter output.Emitter) {
+	a.bus.Subscribe(emitter)
+}
+
+// Events returns the bus carrying the pipeline events, to subscribe to
+// some event types only
+func (a *Assistant) Events() *output.Bus {
+	return a.bus
+}
+
+// emitVADState reports voice activity changes
+func (a *Assistant) emitVADState(speaking bool) {
+	if !a.bus.Wants(output.EventVAD) {
+		return
+	}
+	state := "silence"
+	if speaking {
+		state = "speech"
+	}
+	a.emit(output.Event{Type: output.EventVAD, State: state})
+}
+
+// emitState reports assistant state transitions
+func (a *Assistant) emitState(transition Transition) {
+	a.emit(output.Event{Type: output.EventState, Time: transition.Time, State: string(transition.To)})
+}
+
+// emit sends an event to every listener
+func (a *Assistant) emit(event output.Event) {
+	if event.Time.IsZero() {
+		event.Time = a.now()
+	}
+	if err := a.bus.Emit(event); err != nil {
+		logger.WithError(err).Error("❌ Failed to write event")
+	}
+}
+
+// SetCaptionWriter appends stream-aligned caption cues for every transcript
+func (a *Assistant) SetCaptionWriter(captions *transcript.CaptionWriter) {
+	a.captions = captions
+}
+
+// SetVoiceCommands handles built-in commands controlling the assistant
+func (a *Assistant) SetVoiceCommands(router *intents.Router) {
+	a.commands = router
+}
+
+// SetIntents answers matching transcripts locally before the AI
+func (a *Assistant) SetIntents(router *intents.Router) {
+	a.intents = router
+}
+
+// SetClipboard copies each transcript or AI answer to the clipboard
+func (a *Assistant) SetClipboard(cb clipboard.Clipboard, target clipboard.Target) {
+	a.clipboard = cb
+	a.clipboardTarget = target
+}
+
+// copyToClipboard copies text when the clipboard target matches
+func (a *Assistant) copyToClipboard(target clipboard.Target, text string) {
+	if a.clipboard == nil || a.clipboardTarget != target {
+		return
+	}
+	if err := a.clipboard.Copy(text); err != nil {
+		logger.WithError(err).Error("❌ Failed to copy to clipboard")
+	}
+}
+
+// transcriptionContext returns the context for a single transcription,
+// cancelled on Close or when the per-utterance timeout expires
+func (a *Assistant) transcriptionContext() (context.Context, context.CancelFunc) {
+	if a.transcriptionTimeout > 0 {
+		return context.WithTimeout(a.ctx, a.transcriptionTimeout)
+	}
+	return context.WithCancel(a.ctx)
+}
+
+// Initialize initializes all components
+func (a *Assistant) Initialize(modelPath, audioSource, language string) error {
+	// Load Whisper model
+	if err := a.whisperService.LoadModel(modelPath); err != nil {
+		return fmt.Errorf("failed to load Whisper model: %w", err)
+	}
+
+	a.whisperService.SetLanguage(language)
+	a.stateMutex.Lock()
+	a.language = language
+	a.modelPath = modelPath
+	a.stateMutex.Unlock()
+
+	// Initialize VAD
+	return a.segmenter.Initialize()
+}
+
+// SetVADConfig replaces the VAD settings. Once running, the segmenter
+// applies them between utterances and recalibrates the noise floor.
+func (a *Assistant) SetVADConfig(config vad.VADConfig) {
+	a.segmenter.SetConfig(config)
+}
+
+// SetMaxUtterance bounds the length of an utterance, split at its last
+// short pause beyond. Must be called before ProcessStream.
+func (a *Assistant) SetMaxUtterance(duration time.Duration) {
+	a.segmenter.SetMaxSamples(int(duration.Seconds() * SampleRate))
+}
+
+// DefaultVADConfig returns the built-in VAD settings of the live pipeline
+func DefaultVADConfig() vad.VADConfig {
+	return vad.VADConfig{
+		SampleRate:          SampleRate,
+		SilenceThreshold:    silenceThreshold,
+		SilenceDurationMs:   silenceDurationMs,
+		MinSpeechDurationMs: minSpeechDurationMs,
+		RMSWindowSize:       rmsWindowSize,
+		NoiseFloorSamples:   noiseFloorSamples,
+	}
+}
+
+// detectWakeWord checks if the wake word is present in the audio buffer
+func (a *Assistant) detectWakeWord() bool {
+	if !a.wakeWordEnabled || len(a.wakeWordBuffer) < SampleRate/2 {
+		return false
+	}
+
+	// Use Whisper to transcribe the wake word buffer
+	ctx, cancel := a.transcriptionContext()
+	defer cancel()
+
+	result, err := a.whisperService.Transcribe(ctx, a.wakeWordBuffer, a.Language())
+	if err != nil {
+		return false
+	}
+
+	// Check if wake word is present (case-insensitive)
+	text := strings.ToLower(strings.TrimSpace(result.Text))
+	wakeWord := strings.ToLower(a.wakeWord)
+
+	return strings.Contains(text, wakeWord)
+}
+
+// resetWakeWordBuffer clears the wake word buffer
+func (a *Assistant) resetWakeWordBuffer() {
+	a.wakeWordBuffer = a.wakeWordBuffer[:0]
+}
+
+// startListeningTimeout deactivates listening after 30 seconds of audio,
+// counted from the next processed sample
+func (a *Assistant) startListeningTimeout() {
+	a.listeningDeadline.Store(-1)
+}
+
+// checkListeningTimeout stops listening once the stream has gone past the
+// deadline. Counting samples rather than wall time keeps replays deterministic.
+func (a *Assistant) checkListeningTimeout() {
+	deadline := a.listeningDeadline.Load()
+	if deadline < 0 {
+		a.listeningDeadline.CompareAndSwap(deadline, a.streamSamples+listeningTimeoutS*SampleRate)
+		return
+	}
+	if deadline == 0 || a.streamSamples < deadline {
+		return
+	}
+
+	a.listeningDeadline.Store(0)
+	if a.wakeWordEnabled && a.state.Mode() == StateActive {
+		fmt.Printf("🔍 Listening timeout. Waiting for wake word '%s' again...\n", a.wakeWord)
+		a.state.SetMode(StateWakeListening)
+	}
+}
+
+// Activate starts listening as if the wake word had been said
+func (a *Assistant) Activate() {
+	a.ActivateBy(ActivatedRemotely)
+}
+
+// ActivateBy starts listening as if the wake word had been said, source
+// tells what triggered it
+func (a *Assistant) ActivateBy(source string) {
+	if !a.wakeWordEnabled {
+		return
+	}
+	a.emit(output.Event{Type: output.EventWakeWord, Text: a.wakeWord, State: source})
+	a.state.SetMode(StateActive)
+	// Deactivate listening after 30 seconds of audio
+	a.startListeningTimeout()
+}
+
+// Deactivate stops listening until the next wake word
+func (a *Assistant) Deactivate() {
+	if !a.wakeWordEnabled {
+		return
+	}
+	fmt.Printf("🔍 Listening deactivated remotely. Waiting for wake word '%s' again...\n", a.wakeWord)
+	a.state.SetMode(StateWakeListening)
+}
+
+// WakeWord returns the wake word, or "" when the assistant always listens
+func (a *Assistant) WakeWord() string {
+	if !a.wakeWordEnabled {
+		return ""
+	}
+	return a.wakeWord
+}
+
+// AIEnabled reports whether transcripts are answered by an AI service
+func (a *Assistant) AIEnabled() bool {
+	return a.aiEnabled
+}
+
+// AIAvailable reports whether the AI service is enabled and reachable
+func (a *Assistant) AIAvailable() bool {
+	return a.aiEnabled && a.aiAvailable.Load()
+}
+
+// SetAIAvailable pauses the answers while the AI backend is unreachable
+// and resumes them once it is back, e.g. from an ai.Watcher. Transcription
+// goes on either way. Safe to call while ProcessStream runs.
+func (a *Assistant) SetAIAvailable(available bool) {
+	if !a.aiEnabled || a.aiAvailable.Swap(available) == available {
+		return
+	}
+
+	state := "available"
+	if !available {
+		state = "unavailable"
+	}
+	a.emit(output.Event{Type: output.EventAIStatus, State: state})
+}
+
+// LastTranscript returns the most recent final transcript
+func (a *Assistant) LastTranscript() string {
+	a.stateMutex.Lock()
+	defer a.stateMutex.Unlock()
+	return a.lastTranscript
+}
+
+// LastReply returns the most recent assistant answer
+func (a *Assistant) LastReply() string {
+	a.stateMutex.Lock()
+	defer a.stateMutex.Unlock()
+	return a.lastReply
+}
+
+// Language returns the transcription language
+func (a *Assistant) Language() string {
+	a.stateMutex.Lock()
+	defer a.stateMutex.Unlock()
+	return a.language
+}
+
+// SetLanguage changes the transcription language of the next utterances
+func (a *Assistant) SetLanguage(language string) error {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if !isLanguageCode(language) {
+		return fmt.Errorf("invalid language '%s' (expected a code like fr, en or auto)", language)
+	}
+
+	a.stateMutex.Lock()
+	a.language = language
+	if a.languageSwitch != nil {
+		// A chosen language is the reference of the next switches
+		*a.languageSwitch = languageSwitcher{after: a.languageSwitch.after}
+		if language != "auto" {
+			a.languageSwitch.current = language
+		}
+	}
+	a.stateMutex.Unlock()
+
+	a.whisperService.SetLanguage(language)
+	for _, router := range []*intents.Router{a.commands, a.intents} {
+		if router != nil {
+			router.SetLanguage(language)
+		}
+	}
+	if setter, ok := a.aiService.(ai.LanguageSetter); ok {
+		setter.SetLanguage(language)
+	}
+	if a.dictation != nil {
+		a.dictation.SetLanguage(language)
+	}
+	fmt.Printf("🌐 Transcription language set to %s\n", language)
+	return nil
+}
+
+// isLanguageCode reports whether language looks like a Whisper language code
+func isLanguageCode(language string) bool {
+	if language == "auto" {
+		return true
+	}
+	if len(language) < 2 || len(language) > 3 {
+		return false
+	}
+	for _, c := range language {
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Pause stops processing audio until Resume, the current utterance is dropped
+func (a *Assistant) Pause() {
+	if a.paused.Swap(true) {
+		return
+	}
+	fmt.Println("⏸️  Audio processing paused")
+	a.state.SetPaused(true)
+}
+
+// Resume restarts audio processing after Pause
+func (a *Assistant) Resume() {
+	if !a.paused.Swap(false) {
+		return
+	}
+	fmt.Println("▶️  Audio processing resumed")
+	a.state.SetPaused(false)
+}
+
+// ClearHistory forgets the AI conversations, keeping the system prompts
+func (a *Assistant) ClearHistory() {
+	if a.conversation == nil {
+		return
+	}
+	a.conversation.ClearHistory()
+	a.clearSpeakerHistories()
+	fmt.Println("🧹 AI conversation history cleared")
+}
+
+// ForgetLast removes the last exchange, the user message and the answer to
+// it, from the conversation it took place in, so a misheard request does
+// not steer the next answers. It returns false when there is nothing to
+// forget.
+func (a *Assistant) ForgetLast() bool {
+	if a.conversation == nil {
+		return false
+	}
+	// Wait for an answer in progress, it is part of the exchange
+	a.chatMutex.Lock()
+	defer a.chatMutex.Unlock()
+
+	a.stateMutex.Lock()
+	speaker := a.lastSpeaker
+	a.stateMutex.Unlock()
+
+	conversation := a.conversationFor(speaker)
+	removed := conversation.RemoveLast(ai.LastExchange(conversation.GetMessages()))
+	if len(removed) == 0 {
+		return false
+	}
+	logger.Debugf("Forgot %d message(s) starting with %q", len(removed), removed[0].Content)
+	fmt.Println("↩️  Last AI exchange forgotten")
+	return true
+}
+
+// ExportConversation writes the AI conversation as JSON. The conversations
+// of the identified speakers are not exported.
+func (a *Assistant) ExportConversation(w io.Writer) error {
+	if a.conversation == nil {
+		return errAIDisabled
+	}
+	return a.conversation.ExportJSON(w)
+}
+
+// ImportConversation replaces the AI conversation with one written by
+// ExportConversation, once the answer in progress is over
+func (a *Assistant) ImportConversation(r io.Reader) error {
+	if a.conversation == nil {
+		return errAIDisabled
+	}
+	a.chatMutex.Lock()
+	defer a.chatMutex.Unlock()
+
+	if err := a.conversation.ImportJSON(r); err != nil {
+		return err
+	}
+	fmt.Printf("📥 AI conversation imported (%d messages)\n", len(a.conversation.GetMessages()))
+	return nil
+}
+
+// Status reports the assistant state to the control socket
+func (a *Assistant) Status() control.Status {
+	return control.Status{
+		State:          string(a.State()),
+		Paused:         a.paused.Load(),
+		Language:       a.Language(),
+		Detected:       a.DetectedLanguage(),
+		LanguageLocked: a.LanguageLocked(),
+		AIEnabled:      a.aiEnabled,
+		AIAvailable:    a.AIAvailable(),
+		LastTranscript: a.LastTranscript(),
+		CaptureStalls:  a.CaptureStalls(),
+		Overload:       a.overloadStatus(),
+		Audio:          a.audioStatus(),
+	}
+}
+
+// overloadStatus reports the overload counters to the control socket
+func (a *Assistant) overloadStatus() control.Overload {
+	overload := a.Overload()
+	return control.Overload{
+		DroppedSegments:  overload.DroppedSegments,
+		DroppedAudioS:    overload.DroppedAudio.Seconds(),
+		MergedSegments:   overload.MergedSegments,
+		FallbackSwitches: overload.FallbackSwitches,
+	}
+}
+
+// Say handles text as if it had been spoken
+func (a *Assistant) Say(text string) {
+	fmt.Printf("[%s] 💬 %s\n", a.now().Format("15:04:05"), text)
+	a.respondTo("", text, ai.NewMetadata(a.now(), ai.SourceText))
+}
+
+// Answer handles a message typed in a remote client, described by meta, and
+// returns the reply. It goes through the voice commands and intents but the
+// shell intents and the confirmations, which stay with the speaker at home,
+// then the AI without the AI trigger since the message is addressed to it,
+// and takes turns with the spoken questions. The reply is emitted as for them.
+func (a *Assistant) Answer(text string, meta *ai.Metadata) (string, error) {
+	if a.redactor != nil {
+		text = a.redactor.Redact(text)
+	}
+	fmt.Printf("[%s] 💬 %s\n", a.now().Format("15:04:05"), text)
+
+	if reply, handled, err := a.command(text, true); handled {
+		return reply, err
+	}
+	return a.ask("", text, meta)
+}
+
+// SetPersona replaces the AI system prompt, enrolled speakers with their
+// own prompt keep it
+func (a *Assistant) SetPersona(prompt string) {
+	if a.conversation == nil {
+		return
+	}
+	a.conversation.SetSystemPrompt(prompt)
+	a.setSpeakerPersona(prompt)
+	fmt.Println("🎭 AI persona updated")
+}
+
+// State returns the assistant state reported to event listeners
+func (a *Assistant) State() State {
+	return a.state.State()
+}
+
+// SubscribeState calls listener on every assistant state transition until
+// the returned function is called
+func (a *Assistant) SubscribeState(listener func(Transition)) func() {
+	return a.state.Subscribe(listener)
+}
+
+// Announce outputs a fired timer or reminder
+func (a *Assistant) Announce(text string) {
+	defer a.state.Begin(StateSpeaking)()
+	a.emit(output.Event{Type: output.EventTimer, Text: text})
+}
+
+// ProcessStream runs the audio pipeline until the stream ends, Stop or
+// Abort: capture → decode → level, pause and wake word filters → VAD
+// segmenter → transcription queue → transcriber → router (outputs, intents
+// and AI). Every stage runs in its own goroutine.
+func (a *Assistant) ProcessStream(audioSource string) error {
+	stream, err := a.audioCapture.StartCapture(audioSource)
+	if err != nil {
+		return fmt.Errorf("failed to start audio capture: %w", err)
+	}
+	defer stream.Close()
+
+	a.streamMutex.Lock()
+	a.stream = stream
+	a.captureErr = nil
+	a.streamMutex.Unlock()
+	if a.timestamps != nil {
+		a.timestamps.SetOrigin(a.now())
+	}
+
+	if a.wakeWordEnabled {
+		fmt.Printf("🔍 Listening for wake word '%s'...\n", a.wakeWord)
+		a.state.SetMode(StateWakeListening)
+	} else {
+		fmt.Println("🔴 Processing audio stream...")
+		a.state.SetMode(StateActive)
+	}
+	defer a.state.SetMode(StateIdle)
+
+	// Stop only ends the capture, the stages after it drain their input
+	frames := pipeline.Capture(a.stopCtx, countingStream{stream, &a.counters.bytesRead}, ReadChunkSize, a.now, a.captureError)
+	chunks := pipeline.Decode(a.ctx, frames, a.audioProcessor)
+	chunks = pipeline.Filter(a.ctx, chunks, a.meterChunk)
+	chunks = pipeline.Filter(a.ctx, chunks, a.pauseFilter())
+	if a.wakeWordEnabled {
+		chunks = pipeline.Filter(a.ctx, chunks, a.wakeWordGate)
+	}
+	segments := a.segmenter.Run(a.ctx, chunks)
+
+	// Transcription runs in its own stage so capture never waits on whisper
+	queued := pipeline.Queue(a.ctx, segments, a.queueSize, a.overflow())
+	a.counters.queued.Store(&queued)
+	reporting, stopReport := context.WithCancel(a.ctx)
+	defer stopReport()
+	go a.reportStats(reporting)
+	transcriber := pipeline.NewTranscriber(a.whisperService, a.Language, a.transcriptionTimeout, SampleRate)
+	transcriber.SetActivityListener(a.transcribing(queued))
+	if a.partials {
+		transcriber.SetSegmentListener(a.emitPartial)
+	}
+	<-pipeline.Route(a.ctx, transcriber.Run(a.ctx, queued), a.handleTranscript)
+
+	if overload := a.Overload(); overload.DroppedSegments > 0 {
+		logger.Warnf("⚠️  Transcription could not keep up: %d segments dropped (%.1f seconds of audio)",
+			overload.DroppedSegments, overload.DroppedAudio.Seconds())
+	}
+
+	// A source that went away is fatal, other read errors end the session
+	a.streamMutex.Lock()
+	captureErr := a.captureErr
+	a.streamMutex.Unlock()
+	if errors.Is(captureErr, audio.ErrAudioSource) {
+		return fmt.Errorf("audio capture failed: %w", captureErr)
+	}
+	return nil
+}
+
+// overflow returns what the transcription queue does once full, nil to block
+func (a *Assistant) overflow() pipeline.Overflow[pipeline.Segment] {
+	switch a.queuePolicy {
+	case QueuePolicyDropOldest:
+		return pipeline.DropOldest(a.segmentDropped)
+	case QueuePolicyMerge:
+		return a.mergeSegments
+	case QueuePolicyFallbackModel:
+		if a.fallbackModel != "" {
+			return a.fallBack
+		}
+	}
+	return nil
+}
+
+// mergeSegments joins the pending segments and the new one, dropping the
+// oldest audio beyond the longest utterance whisper transcribes
+func (a *Assistant) mergeSegments(pending []pipeline.Segment, segment pipeline.Segment) []pipeline.Segment {
+	segments := append(pending, segment)
+	total := 0
+	for _, s := range segments {
+		total += len(s.Samples)
+	}
+	for len(segments) > 1 && total > SampleRate*MaxBufferDurationS {
+		a.segmentDropped(segments[0])
+		total -= len(segments[0].Samples)
+		segments = segments[1:]
+	}
+	if len(segments) == 1 {
+		return segments
+	}
+
+	merged := pipeline.Concat(SampleRate, segments...)
+	a.stateMutex.Lock()
+	a.overload.MergedSegments += len(segments) - 1
+	a.stateMutex.Unlock()
+	logger.Warnf("⚠️  Transcription queue full, merged %d segments", len(segments))
+	a.emitOverload("merged", merged)
+	return []pipeline.Segment{merged}
+}
+
+// fallBack loads the fallback model when the queue is full, the segments
+// wait for room as with the block policy
+func (a *Assistant) fallBack(pending []pipeline.Segment, segment pipeline.Segment) []pipeline.Segment {
+	if a.wantFallback.CompareAndSwap(false, true) {
+		go a.syncModel()
+	}
+	return append(pending, segment)
+}
+
+// syncModel loads the fallback or the configured model, whichever is wanted.
+// Switches are serialized and the last one wins.
+func (a *Assistant) syncModel() {
+	a.modelMutex.Lock()
+	defer a.modelMutex.Unlock()
+
+	fallback := a.wantFallback.Load()
+	if fallback == a.onFallback {
+		return
+	}
+
+	a.stateMutex.Lock()
+	modelPath := a.modelPath
+	a.stateMutex.Unlock()
+	state := "recovered"
+	if fallback {
+		modelPath = a.fallbackModel
+		state = "fallback"
+	}
+
+	if err := a.whisperService.ReloadModel(modelPath); err != nil {
+		logger.WithError(err).WithField("model", modelPath).Error("❌ Failed to switch Whisper model")
+		return
+	}
+	a.onFallback = fallback
+
+	if fallback {
+		a.stateMutex.Lock()
+		a.overload.FallbackSwitches++
+		a.stateMutex.Unlock()
+		logger.Warnf("⚠️  Transcription lagging behind, using fallback model %s", modelPath)
+	} else {
+		logger.Infof("✅ Transcription caught up, back to model %s", modelPath)
+	}
+	a.emit(output.Event{Type: output.EventOverload, State: state, Text: modelPath})
+}
+
+// Overload returns what the queue policy did so far while transcription
+// lagged behind
+func (a *Assistant) Overload() OverloadStats {
+	a.stateMutex.Lock()
+	defer a.stateMutex.Unlock()
+	return a.overload
+}
+
+// emitOverload reports the audio affected by the queue policy
+func (a *Assistant) emitOverload(state string, segment pipeline.Segment) {
+	start := time.Duration(segment.Offset) * time.Second / SampleRate
+	a.emit(output.Event{
+		Type:  output.EventOverload,
+		State: state,
+		Start: start.Seconds(),
+		End:   (start + segment.Duration(SampleRate)).Seconds(),
+	})
+}
+
+// captureError reports a failed read, unless the stream was closed on purpose
+func (a *Assistant) captureError(err error) {
+	if a.stopping.Load() {
+		return
+	}
+	logger.WithError(err).Error("Error reading audio stream")
+
+	a.streamMutex.Lock()
+	a.captureErr = err
+	a.streamMutex.Unlock()
+}
+
+// meterChunk feeds the watchdog and the level meter with every chunk read
+func (a *Assistant) meterChunk(chunk pipeline.Chunk) pipeline.Chunk {
+	a.lastRead.Store(time.Now().UnixNano())
+	a.counters.samplesProcessed.Add(int64(len(chunk.Samples)))
+	if a.levelMeter != nil {
+		a.levelMeter(a.audioProcessor.CalculateRMS(chunk.Samples, len(chunk.Samples)), a.segmenter.Calibrated())
+	}
+	return chunk
+}
+
+// pauseFilter drops the audio read while paused, so no stale audio is
+// processed on resume, and abandons the utterance in progress on pause
+func (a *Assistant) pauseFilter() func(pipeline.Chunk) pipeline.Chunk {
+	paused := false
+	return func(chunk pipeline.Chunk) pipeline.Chunk {
+		if !a.paused.Load() {
+			paused = false
+			return chunk
+		}
+		if paused {
+			return pipeline.Chunk{}
+		}
+		paused = true
+		return pipeline.Chunk{Offset: chunk.Offset, Time: chunk.Time, Discontinuity: true}
+	}
+}
+
+// wakeWordGate only passes the audio heard between the wake word and the
+// listening timeout
+func (a *Assistant) wakeWordGate(chunk pipeline.Chunk) pipeline.Chunk {
+	if chunk.Discontinuity {
+		a.resetWakeWordBuffer()
+	}
+
+	listened := chunk
+	listened.Samples = nil
+	for i, sample := range chunk.Samples {
+		a.streamSamples = chunk.Offset + int64(i) + 1
+		a.wakeWordBuffer = append(a.wakeWordBuffer, sample)
+
+		// Keep wake word buffer to reasonable size (2 seconds)
+		if len(a.wakeWordBuffer) > SampleRate*2 {
+			// Remove oldest samples
+			copy(a.wakeWordBuffer, a.wakeWordBuffer[SampleRate/4:])
+			a.wakeWordBuffer = a.wakeWordBuffer[:len(a.wakeWordBuffer)-SampleRate/4]
+		}
+
+		// Check for wake word every 500ms
+		if len(a.wakeWordBuffer)%(SampleRate/2) == 0 {
+			if a.detectWakeWord() {
+				a.ActivateBy(ActivatedByVoice)
+				a.resetWakeWordBuffer()
+			}
+		}
+
+		a.checkListeningTimeout()
+
+		// If not actively listening, skip regular processing
+		if a.state.Mode() != StateActive {
+			continue
+		}
+		if len(listened.Samples) == 0 {
+			listened.Offset = chunk.Offset + int64(i)
+		}
+		listened.Samples = append(listened.Samples, sample)
+	}
+
+	return listened
+}
+
+// transcribing returns the transcriber activity listener, keeping the
+// assistant in the transcribing state while whisper runs and restoring the
+// configured model once the queue is drained
+func (a *Assistant) transcribing(queued <-chan pipeline.Segment) func(busy bool) {
+	end := func() {}
+	return func(busy bool) {
+		if busy {
+			end = a.state.Begin(StateTranscribing)
+			return
+		}
+		end()
+		if len(queued) == 0 && a.wantFallback.CompareAndSwap(true, false) {
+			go a.syncModel()
+		}
+	}
+}
+
+// segmentDropped reports a segment discarded by a full transcription queue
+func (a *Assistant) segmentDropped(segment pipeline.Segment) {
+	a.stateMutex.Lock()
+	a.overload.DroppedSegments++
+	a.overload.DroppedAudio += segment.Duration(SampleRate)
+	a.stateMutex.Unlock()
+	a.counters.samplesDropped.Add(int64(len(segment.Samples)))
+
+	logger.Warnf("⚠️  Transcription queue full, dropped oldest segment (%.2f seconds)",
+		segment.Duration(SampleRate).Seconds())
+	a.emitOverload("dropped", segment)
+}
+
+// handleTranscript outputs a transcript and answers it
+func (a *Assistant) handleTranscript(transcript pipeline.Transcript) {
+	if err := transcript.Err; err != nil {
+		if errors.Is(err, whisper.ErrTimeout) {
+			logger.Warnf("⏱️  Transcription timed out after %s, skipping utterance", a.transcriptionTimeout)
+			return
+		}
+		logger.WithError(err).Error("Failed to transcribe")
+		a.emit(output.Event{Type: output.EventError, Error: err.Error()})
+		return
+	}
+
+	latency := newLatency(transcript)
+	defer a.reportLatency(&latency, transcript.Segment.Ready)
+
+	segment := newSpeechSegment(transcript.Segment)
+	result := transcript.Result
+	if a.blocklist != nil {
+		var dropped []string
+		result, dropped = a.blocklist.Result(result)
+		for _, text := range dropped {
+			logger.Debugf("🚫 Dropped %q, in the blocklist", text)
+		}
+	}
+	if a.redactor != nil {
+		result = a.redactor.Result(result)
+	}
+	if result.Text != "" {
+		// Clean up the text
+		cleanText := strings.TrimSpace(result.Text)
+
+		a.stateMutex.Lock()
+		a.lastTranscript = cleanText
+		a.stateMutex.Unlock()
+
+		if a.diarizer != nil {
+			result.Segments = a.diarizer.Label(segment.samples, result.Segments)
+		}
+		speaker := a.identifySpeaker(segment.samples, result.Segments)
+
+		a.emitTranscript(segment, result)
+		a.observeLanguage(result.Language)
+
+		if a.translator != nil {
+			a.translateTranscript(segment, result.Language, speaker, cleanText)
+		}
+
+		if a.meetingRecorder != nil {
+			a.recordMeeting(segment, result.Segments)
+		}
+
+		if a.captions != nil {
+			a.writeCaptions(segment, result.Segments)
+		}
+
+		if a.dictation != nil {
+			if err := a.dictation.Dictate(cleanText); err != nil {
+				logger.WithError(err).Error("❌ Failed to type dictated text")
+			}
+		}
+
+		a.copyToClipboard(clipboard.TargetTranscript, cleanText)
+
+		// Answer locally or with the AI if the text is meaningful
+		if a.worthAnswering(cleanText, result.Segments) {
+			meta := ai.NewMetadata(segment.start, ai.SourceVoice)
+			meta.Duration = float64(len(segment.samples)) / SampleRate
+			meta.Confidence, _ = transcriptConfidence(result.Segments)
+			answered := time.Now()
+			a.respondTo(speaker, cleanText, meta)
+			latency.Answer = time.Since(answered)
+		}
+	}
+}
+
+// translateTranscript emits the translation of an utterance, spoken in
+// language, unless it is already in the target language
+func (a *Assistant) translateTranscript(segment speechSegment, language, speaker, text string) {
+	if language == a.translator.Target() {
+		return
+	}
+
+	thinking := a.state.Begin(StateThinking)
+	defer thinking()
+	ctx, cancel := a.transcriptionContext()
+	defer cancel()
+	translation, err := a.translator.Translate(ctx, segment.samples, text, language)
+	if err != nil {
+		if a.ctx.Err() == nil {
+			logger.WithError(err).Error("❌ Translation failed")
+			a.emit(output.Event{Type: output.EventError, Error: err.Error()})
+		}
+		return
+	}
+	if a.redactor != nil {
+		// Whisper translates the audio, not the redacted transcript
+		translation = a.redactor.Redact(translation)
+	}
+	if translation == "" {
+		return
+	}
+	a.emit(output.Event{
+		Type:     output.EventTranslation,
+		Text:     translation,
+		Language: a.translator.Target(),
+		Speaker:  speaker,
+		Start:    segment.offset.Seconds(),
+		End:      segment.offset.Seconds() + float64(len(segment.samples))/SampleRate,
+	})
+}
+
+// recordMeeting adds each speaker turn of an utterance to the meeting minutes
+func (a *Assistant) recordMeeting(segment speechSegment, segments []whisper.Segment) {
+	for _, turn := range speakerTurns(segments) {
+		entry := meeting.Entry{
+			Time:    segment.start.Add(time.Duration(turn.Start * float64(time.Second))),
+			Speaker: turn.Speaker,
+			Text:    turn.Text,
+		}
+		if err := a.meetingRecorder.Add(entry); err != nil {
+			logger.WithError(err).Error("❌ Failed to write meeting minutes")
+		}
+	}
+}
+
+// emitTranscript writes one transcript event per speaker turn
+func (a *Assistant) emitTranscript(segment speechSegment, result whisper.TranscriptionResult) {
+	for _, turn := range speakerTurns(result.Segments) {
+		a.emit(output.Event{
+			Type:       output.EventTranscript,
+			Text:       strings.TrimSpace(turn.Text),
+			Language:   result.Language,
+			Speaker:    turn.Speaker,
+			Start:      segment.offset.Seconds() + turn.Start,
+			End:        segment.offset.Seconds() + turn.End,
+			Confidence: turn.Confidence,
+		})
+	}
+}
+
+// emitPartial emits a whisper segment of an utterance still being
+// transcribed, shifted to its stream position. It goes through the
+// blocklist and the redactor like the final transcript.
+func (a *Assistant) emitPartial(segment pipeline.Segment, decoded whisper.Segment) {
+	text := decoded.Text
+	// The language is only detected once the utterance is transcribed
+	if a.blocklist != nil && a.blocklist.Blocked(text, "") {
+		return
+	}
+	if a.redactor != nil {
+		text = a.redactor.Redact(text)
+	}
+
+	offset := newSpeechSegment(segment).offset.Seconds()
+	a.emit(output.Event{
+		Type:       output.EventPartial,
+		Text:       strings.TrimSpace(text),
+		Start:      offset + decoded.Start,
+		End:        offset + decoded.End,
+		Confidence: decoded.Confidence,
+	})
+}
+
+// writeCaptions appends the utterance segments, shifted to their stream position
+func (a *Assistant) writeCaptions(segment speechSegment, segments []whisper.Segment) {
+	shifted := make([]whisper.Segment, len(segments))
+	for i, s := range segments {
+		s.Start += segment.offset.Seconds()
+		s.End += segment.offset.Seconds()
+		shifted[i] = s
+	}
+	if err := a.captions.WriteSegments(shifted); err != nil {
+		logger.WithError(err).Error("❌ Failed to write captions")
+	}
+}
+
+// worthAnswering applies the answer gate to a transcript, logging the
+// skipped ones at debug level
+func (a *Assistant) worthAnswering(text string, segments []whisper.Segment) bool {
+	if len(text) <= 3 {
+		return false
+	}
+	a.stateMutex.Lock()
+	minWords, minConfidence := a.minWords, a.minConfidence
+	a.stateMutex.Unlock()
+
+	if words := countWords(text); words < minWords {
+		logger.Debugf("🔇 Not answering %q: %d word(s), %d needed", text, words, minWords)
+		return false
+	}
+	if minConfidence <= 0 {
+		return true
+	}
+	confidence, speech := transcriptConfidence(segments)
+	if !speech {
+		logger.Debugf("🔇 Not answering %q: no speech segment", text)
+		return false
+	}
+	if confidence < minConfidence {
+		logger.Debugf("🔇 Not answering %q: confidence %.2f below %.2f", text, confidence, minConfidence)
+		return false
+	}
+	return true
+}
+
+// countWords counts the words of text holding a letter or a digit
+func countWords(text string) int {
+	count := 0
+	for _, word := range strings.Fields(text) {
+		if strings.IndexFunc(word, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) >= 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// transcriptConfidence averages the confidence of the speech segments,
+// weighted by their text length. speech is false when every segment is
+// marked as no speech; without segments there is nothing to judge and the
+// confidence is 1.
+func transcriptConfidence(segments []whisper.Segment) (confidence float64, speech bool) {
+	if len(segments) == 0 {
+		return 1, true
+	}
+	var sum, weight float64
+	for _, segment := range segments {
+		length := float64(len(strings.TrimSpace(segment.Text)))
+		if segment.NoSpeech || length == 0 {
+			continue
+		}
+		sum += segment.Confidence * length
+		weight += length
+	}
+	if weight == 0 {
+		return 0, false
+	}
+	return sum / weight, true
+}
+
+// speakerTurns merges consecutive segments of the same speaker, averaging
+// their confidence
+func speakerTurns(segments []whisper.Segment) []whisper.Segment {
+	var turns []whisper.Segment
+	var merged []int
+	for _, segment := range segments {
+		if strings.TrimSpace(segment.Text) == "" {
+			continue
+		}
+		if last := len(turns) - 1; last >= 0 && turns[last].Speaker == segment.Speaker {
+			turns[last].Text += segment.Text
+			turns[last].End = segment.End
+			turns[last].Confidence = (turns[last].Confidence*float64(merged[last]) + segment.Confidence) /
+				float64(merged[last]+1)
+			merged[last]++
+			continue
+		}
+		turns = append(turns, segment)
+		merged = append(merged, 1)
+	}
+	return turns
+}
+
+// chat sends a request to the AI, streaming tokens to event listeners when
+// there are any and returning the aggregated response
+func (a *Assistant) chat(request ai.ChatRequest) (ai.ChatResponse, error) {
+	if !a.bus.Wants(output.EventAIToken) {
+		return a.aiService.Chat(request)
+	}
+
+	stream, err := a.aiService.ChatStream(request)
+	if err != nil {
+		return ai.ChatResponse{}, err
+	}
+
+	type result struct {
+		response ai.ChatResponse
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		response, err := ai.AggregateStream(stream, func(token string) {
+			if a.ctx.Err() == nil {
+				a.emit(output.Event{Type: output.EventAIToken, Text: token})
+			}
+		})
+		done <- result{response, err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.response, result.err
+	case <-a.ctx.Done():
+		// Aborted: give up on the answer rather than waiting for it
+		return ai.ChatResponse{}, a.ctx.Err()
+	}
+}
+
+// respondTo answers text with a voice command, the first matching intent,
+// or else the AI in the conversation of speaker, empty for a guest. meta
+// describes the message kept in the conversation.
+func (a *Assistant) respondTo(speaker, text string, meta *ai.Metadata) {
+	if _, handled, _ := a.command(text, false); handled {
+		return
+	}
+
+	question, ok := a.triggerAI(text)
+	if !ok {
+		return
+	}
+	a.ask(speaker, question, meta)
+}
+
+// command answers text with a voice command or the first matching intent,
+// returning the reply and whether one handled it. remote is set for the
+// messages of remote clients.
+func (a *Assistant) command(text string, remote bool) (string, bool, error) {
+	a.stateMutex.Lock()
+	a.askedReply = ""
+	a.stateMutex.Unlock()
+
+	// Voice commands controlling the assistant come first
+	for _, router := range []*intents.Router{a.commands, a.intents} {
+		if router == nil {
+			continue
+		}
+		handle := router.Handle
+		if remote {
+			handle = router.HandleRemote
+		}
+		reply, handled, err := handle(text)
+		if err != nil {
+			logger.WithError(err).Error("❌ Intent failed")
+			a.emit(output.Event{Type: output.EventError, Error: err.Error()})
+			return "", true, err
+		}
+		if handled {
+			a.stateMutex.Lock()
+			asked := reply != "" && reply == a.askedReply
+			a.askedReply = ""
+			a.stateMutex.Unlock()
+			if asked {
+				return reply, true, nil
+			}
+
+			speaking := a.state.Begin(StateSpeaking)
+			reply = a.reply(reply)
+			speaking()
+			return reply, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// reply outputs an assistant answer, returning it trimmed
+func (a *Assistant) reply(content string) string {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return ""
+	}
+
+	a.stateMutex.Lock()
+	a.lastReply = content
+	a.stateMutex.Unlock()
+
+	a.emit(output.Event{Type: output.EventAIResponse, Text: content})
+
+	a.copyToClipboard(clipboard.TargetAI, content)
+	return content
+}
+
+// Ask sends text to the AI as if it had been spoken, skipping voice
+// commands and intents, and returns the answer. It fails when the AI is
+// disabled or unreachable. The answer is output as for the spoken
+// questions, a command handler returns it without it being output again.
+func (a *Assistant) Ask(text string) (string, error) {
+	reply, err := a.ask("", text, nil)
+	if err == nil {
+		a.stateMutex.Lock()
+		a.askedReply = reply
+		a.stateMutex.Unlock()
+	}
+	return reply, err
+}
+
+// ask sends text to the AI in the conversation of speaker, returning the
+// answer
+func (a *Assistant) ask(speaker, text string, meta *ai.Metadata) (string, error) {
+	if !a.aiEnabled {
+		return "", errAIDisabled
+	}
+	if !a.aiAvailable.Load() {
+		logger.Warn("⚠️  AI service unavailable, not answering")
+		return "", ai.ErrAIUnavailable
+	}
+	return a.processWithAI(speaker, text, meta)
+}
+
+// processWithAI sends the transcribed text to the AI service
+func (a *Assistant) processWithAI(speaker, text string, meta *ai.Metadata) (string, error) {
+	a.chatMutex.Lock()
+	defer a.chatMutex.Unlock()
+
+	conversation := a.conversationFor(speaker)
+	a.stateMutex.Lock()
+	a.lastSpeaker = speaker
+	a.stateMutex.Unlock()
+
+	// Add user message to conversation
+	if meta == nil {
+		meta = ai.NewMetadata(a.now(), "")
+	}
+	userMsg := ai.Message{
+		Role:    "user",
+		Content: text,
+		Meta:    meta,
+	}
+	conversation.AddMessage(userMsg)
+
+	// Prepare chat request
+	request := ai.ChatRequest{
+		Messages: conversation.GetMessages(),
+		Model:    "", // Will be set by the service
+	}
+
+	// Send to AI
+	thinking := a.state.Begin(StateThinking)
+	defer thinking()
+	response, err := a.chat(request)
+	if err != nil && a.ctx.Err() != nil {
+		return "", err
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, ai.ErrAIUnavailable):
+			logger.WithError(err).Error("❌ AI unreachable, is the backend running?")
+		case errors.Is(err, ai.ErrModelNotLoaded):
+			logger.WithError(err).Error("❌ AI model missing, pull it or pick another with --ollama-model")
+		default:
+			logger.WithError(err).Error("❌ AI Error")
+		}
+		a.emit(output.Event{Type: output.EventError, Error: err.Error()})
+		return "", err
+	}
+
+	if response.Error != "" {
+		logger.WithField("error", response.Error).Error("❌ AI Response Error")
+		a.emit(output.Event{Type: output.EventError, Error: response.Error})
+		return "", errors.New(response.Error)
+	}
+
+	// Validate response content
+	if response.Message.Content == "" {
+		logger.Warn("⚠️  Warning: AI returned empty response")
+		return "", errEmptyAnswer
+	}
+
+	// Add AI response to conversation
+	response.Message.Meta = &ai.Metadata{Time: a.now(), Model: response.Model}
+	conversation.AddMessage(response.Message)
+
+	// Display AI response, speaking takes over from thinking
+	speaking := a.state.Begin(StateSpeaking)
+	thinking()
+	reply := a.reply(response.Message.Content)
+	speaking()
+	return reply, nil
+}
+
+// SwitchModel swaps the Whisper model while the pipeline keeps running.
+// Queued segments wait for the swap and are transcribed with the new model.
+func (a *Assistant) SwitchModel(modelPath string) error {
+	fmt.Printf("📦 Switching Whisper model to %s...\n", modelPath)
+
+	a.modelMutex.Lock()
+	defer a.modelMutex.Unlock()
+	if err := a.whisperService.ReloadModel(modelPath); err != nil {
+		return fmt.Errorf("failed to reload Whisper model: %w", err)
+	}
+	// Replaces the fallback model too, until the next overload
+	a.onFallback = false
+	a.stateMutex.Lock()
+	a.modelPath = modelPath
+	a.stateMutex.Unlock()
+
+	fmt.Printf("✅ Whisper model switched to %s\n", modelPath)
+	return nil
+}
+
+// Stop makes ProcessStream return once the utterance in progress and the
+// pending transcriptions are done
+func (a *Assistant) Stop() {
+	a.stopping.Store(true)
+	a.stopCapture()
+}
+
+// Abort cancels in-flight transcriptions and closes the audio stream, used
+// when a graceful Stop takes too long
+func (a *Assistant) Abort() {
+	a.stopping.Store(true)
+	a.cancel()
+
+	a.streamMutex.Lock()
+	defer a.streamMutex.Unlock()
+	if a.stream != nil {
+		a.stream.Close()
+	}
+}
+
+// Capturing reports whether audio was read recently, i.e. the capture
+// loop is alive
+func (a *Assistant) Capturing() bool {
+	last := a.lastRead.Load()
+	return last != 0 && time.Since(time.Unix(0, last)) < captureStallTimeout
+}
+
+// Close aborts in-flight transcriptions and closes all resources
+func (a *Assistant) Close() error {
+	a.cancel()
+
+	if err := a.audioCapture.Stop(); err != nil {
+		logger.WithError(err).Error("Error stopping audio capture")
+	}
+	return a.whisperService.Close()
+}
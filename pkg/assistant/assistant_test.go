@@ -0,0 +1,636 @@
+package assistant
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/blocklist"
+	"github.com/nerzhul/nrz-ai/internal/intents"
+	"github.com/nerzhul/nrz-ai/internal/pipeline"
+	"github.com/nerzhul/nrz-ai/internal/redact"
+	"github.com/nerzhul/nrz-ai/internal/translate"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+	"github.com/nerzhul/nrz-ai/pkg/vad"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+// eventRecorder keeps the events emitted by an assistant
+type eventRecorder struct {
+	events []output.Event
+	mutex  sync.Mutex
+}
+
+func (r *eventRecorder) Emit(event output.Event) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *eventRecorder) texts(eventType output.EventType) []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var texts []string
+	for _, event := range r.events {
+		if event.Type == eventType {
+			texts = append(texts, event.Text)
+		}
+	}
+	return texts
+}
+
+// endlessStream returns silence until closed
+type endlessStream struct{}
+
+func (endlessStream) Read(p []byte) (int, error) {
+	time.Sleep(time.Millisecond)
+	clear(p)
+	return len(p), nil
+}
+
+func (endlessStream) Close() error { return nil }
+
+func encodeSamples(n int) []byte {
+	data := make([]byte, 0, n*4)
+	for range n {
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(0.1))
+	}
+	return data
+}
+
+// speechPattern is 5 speech samples followed by the 4 silent samples
+// ending the utterance with testVADConfig
+func speechPattern() []bool {
+	return []bool{true, true, true, true, true, false, false, false, false}
+}
+
+func testVADConfig() vad.VADConfig {
+	return vad.VADConfig{
+		SampleRate:          1000,
+		SilenceDurationMs:   3,
+		MinSpeechDurationMs: 2,
+	}
+}
+
+// newTestAssistant creates an assistant on mocks reading samples of audio
+func newTestAssistant(t *testing.T, opts Options, samples int, text string) (*Assistant, *eventRecorder) {
+	t.Helper()
+
+	service := whisper.NewMockWhisperService()
+	service.SetTranscribeResult(whisper.TranscriptionResult{
+		Text:     text,
+		Segments: []whisper.Segment{{Text: text, Start: 0, End: 1}},
+	})
+	detector := vad.NewMockVAD()
+	detector.SetSpeechPattern(speechPattern())
+
+	opts.Whisper = service
+	opts.Capture = audio.NewMockAudioCapture(audio.NewMockAudioStream(encodeSamples(samples)))
+	opts.Detector = detector
+
+	a, err := New(opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	recorder := &eventRecorder{}
+	a.console()
+	a.AddEmitter(recorder)
+	a.SetVADConfig(testVADConfig())
+	if err := a.Initialize("test.bin", "default", "fr"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	return a, recorder
+}
+
+func TestNew_RequiresWhisper(t *testing.T) {
+	if _, err := New(Options{}); err == nil {
+		t.Error("Expected error without a Whisper service")
+	}
+}
+
+func TestProcessStream_Transcribes(t *testing.T) {
+	a, recorder := newTestAssistant(t, Options{}, 9, " Bonjour le monde ")
+	defer a.Close()
+
+	if err := a.ProcessStream("default"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if texts := recorder.texts(output.EventTranscript); len(texts) != 1 || texts[0] != "Bonjour le monde" {
+		t.Errorf("Expected one trimmed transcript, got %v", texts)
+	}
+	if a.LastTranscript() != "Bonjour le monde" {
+		t.Errorf("Expected last transcript 'Bonjour le monde', got '%s'", a.LastTranscript())
+	}
+	if a.State() != StateIdle {
+		t.Errorf("Expected idle once the stream ended, got %s", a.State())
+	}
+}
+
+func TestProcessStream_AnswersWithAI(t *testing.T) {
+	service := ai.NewMockAIService()
+	service.SetResponses([]ai.ChatResponse{
+		{Message: ai.Message{Role: "assistant", Content: "Salut !"}, Done: true},
+	})
+	conversation := ai.NewMockConversationManager()
+
+	a, recorder := newTestAssistant(t, Options{AI: service, Conversation: conversation}, 9, "Bonjour")
+	defer a.Close()
+
+	if err := a.ProcessStream("default"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if texts := recorder.texts(output.EventAIResponse); len(texts) != 1 || texts[0] != "Salut !" {
+		t.Errorf("Expected the AI answer, got %v", texts)
+	}
+	messages := conversation.GetMessages()
+	if len(messages) != 2 || messages[0].Content != "Bonjour" {
+		t.Fatalf("Expected the exchange in the history, got %+v", messages)
+	}
+	if meta := messages[0].Meta; meta == nil || meta.Source != ai.SourceVoice || meta.Duration <= 0 || meta.Time.IsZero() {
+		t.Errorf("Expected the voice metadata of the question, got %+v", meta)
+	}
+	if messages[1].Meta == nil || messages[1].Meta.Time.IsZero() {
+		t.Errorf("Expected the time of the answer, got %+v", messages[1].Meta)
+	}
+	if a.LastReply() != "Salut !" {
+		t.Errorf("Expected last reply 'Salut !', got '%s'", a.LastReply())
+	}
+}
+
+func TestProcessStream_TranslatesTranscripts(t *testing.T) {
+	service := ai.NewMockAIService()
+	service.SetResponses([]ai.ChatResponse{
+		{Message: ai.Message{Role: "assistant", Content: " Hello world "}, Done: true},
+	})
+
+	a, recorder := newTestAssistant(t, Options{}, 9, "Bonjour le monde")
+	defer a.Close()
+	a.SetTranslator(translate.NewAITranslator(service, "en"))
+
+	if err := a.ProcessStream("default"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if texts := recorder.texts(output.EventTranslation); len(texts) != 1 || texts[0] != "Hello world" {
+		t.Errorf("Expected the translation after the transcript, got %v", texts)
+	}
+	if texts := recorder.texts(output.EventAIResponse); len(texts) != 0 {
+		t.Errorf("Expected no AI answer in translate mode, got %v", texts)
+	}
+}
+
+func TestProcessStream_RedactsTranscripts(t *testing.T) {
+	service := ai.NewMockAIService()
+	service.SetResponses([]ai.ChatResponse{
+		{Message: ai.Message{Role: "assistant", Content: "C'est noté."}, Done: true},
+	})
+	conversation := ai.NewMockConversationManager()
+
+	a, recorder := newTestAssistant(t, Options{AI: service, Conversation: conversation}, 9, "Écris à jean@exemple.fr")
+	defer a.Close()
+	redactor, err := redact.New(redact.DefaultRules, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	a.SetRedactor(redactor)
+
+	if err := a.ProcessStream("default"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if texts := recorder.texts(output.EventTranscript); len(texts) != 1 || texts[0] != "Écris à [email]" {
+		t.Errorf("Expected the redacted transcript, got %v", texts)
+	}
+	if messages := conversation.GetMessages(); len(messages) == 0 || messages[0].Content != "Écris à [email]" {
+		t.Errorf("Expected the AI to get the redacted transcript, got %+v", messages)
+	}
+}
+
+func TestProcessStream_AnswerGate(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments []whisper.Segment
+		answered bool
+	}{
+		{"confident", []whisper.Segment{{Text: "Quelle heure est-il", Confidence: 0.9}}, true},
+		{"low confidence", []whisper.Segment{{Text: "Quelle heure est-il", Confidence: 0.2}}, false},
+		{"no speech", []whisper.Segment{{Text: "", NoSpeech: true}}, false},
+		{"weighted", []whisper.Segment{
+			{Text: "Quelle heure est-il", Confidence: 0.8},
+			{Text: " euh", Confidence: 0.1},
+		}, true},
+		{"one word", []whisper.Segment{{Text: "Bonjour", Confidence: 0.9}}, false},
+		{"noise", []whisper.Segment{{Text: "... ...", Confidence: 0.9}}, false},
+	}
+	for _, test := range tests {
+		service := ai.NewMockAIService()
+		service.SetResponses([]ai.ChatResponse{
+			{Message: ai.Message{Role: "assistant", Content: "Midi."}, Done: true},
+		})
+		a, recorder := newTestAssistant(t, Options{AI: service}, 9, "")
+		var text string
+		for _, segment := range test.segments {
+			text += segment.Text
+		}
+		a.whisperService.(*whisper.MockWhisperService).SetTranscribeResult(whisper.TranscriptionResult{
+			Text:     text,
+			Segments: test.segments,
+		})
+		a.SetAnswerGate(0.5, 2)
+
+		if err := a.ProcessStream("default"); err != nil {
+			t.Fatalf("%s: expected no error, got: %v", test.name, err)
+		}
+		if answered := len(recorder.texts(output.EventAIResponse)) == 1; answered != test.answered {
+			t.Errorf("%s: expected answered %v, got %v", test.name, test.answered, answered)
+		}
+		a.Close()
+	}
+}
+
+func TestProcessStream_WaitsForWakeWord(t *testing.T) {
+	a, recorder := newTestAssistant(t, Options{WakeWord: "jack"}, SampleRate, "Bonjour")
+	defer a.Close()
+
+	if err := a.ProcessStream("default"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if texts := recorder.texts(output.EventTranscript); len(texts) != 0 {
+		t.Errorf("Expected no transcript without the wake word, got %v", texts)
+	}
+	if texts := recorder.texts(output.EventWakeWord); len(texts) != 0 {
+		t.Errorf("Expected no wake word event, got %v", texts)
+	}
+}
+
+func TestProcessStream_ListensAfterWakeWord(t *testing.T) {
+	a, recorder := newTestAssistant(t, Options{WakeWord: "Jack"}, SampleRate*3/4, "Jack, bonjour")
+	defer a.Close()
+
+	if err := a.ProcessStream("default"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if texts := recorder.texts(output.EventWakeWord); len(texts) != 1 || texts[0] != "Jack" {
+		t.Errorf("Expected one wake word event, got %v", texts)
+	}
+	if texts := recorder.texts(output.EventTranscript); len(texts) == 0 {
+		t.Error("Expected the audio after the wake word to be transcribed")
+	}
+}
+
+func TestWakeWordAccessors(t *testing.T) {
+	a, err := New(Options{Whisper: whisper.NewMockWhisperService()})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if a.WakeWord() != "" || a.AIEnabled() {
+		t.Errorf("Expected no wake word and no AI, got '%s' and %v", a.WakeWord(), a.AIEnabled())
+	}
+
+	a, _ = New(Options{Whisper: whisper.NewMockWhisperService(), AI: ai.NewMockAIService(), WakeWord: "jack"})
+	if a.WakeWord() != "jack" || !a.AIEnabled() {
+		t.Errorf("Expected wake word 'jack' and AI, got '%s' and %v", a.WakeWord(), a.AIEnabled())
+	}
+}
+
+func TestSetLanguage(t *testing.T) {
+	service := whisper.NewMockWhisperService()
+	a, _ := New(Options{Whisper: service})
+
+	if err := a.SetLanguage("en"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if a.Language() != "en" || service.GetLanguage() != "en" {
+		t.Errorf("Expected language 'en', got '%s' (whisper '%s')", a.Language(), service.GetLanguage())
+	}
+	if err := a.SetLanguage("Klingon!"); err == nil {
+		t.Error("Expected error for an invalid language code")
+	}
+}
+
+func TestSetLanguage_TellsTheAI(t *testing.T) {
+	service := ai.NewContextService(ai.NewMockAIService())
+	a, _ := New(Options{Whisper: whisper.NewMockWhisperService(), AI: service})
+
+	if err := a.SetLanguage("en"); err != nil {
+		t.Fatal(err)
+	}
+	if content := service.Message().Content; !strings.Contains(content, "User language: en") {
+		t.Errorf("Expected the AI context to tell the new language, got %q", content)
+	}
+}
+
+func TestStop_EndsProcessStream(t *testing.T) {
+	a, err := New(Options{
+		Whisper:  whisper.NewMockWhisperService(),
+		Capture:  audio.NewMockAudioCapture(endlessStream{}),
+		Detector: vad.NewMockVAD(),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	a.console()
+	a.Initialize("test.bin", "default", "fr")
+
+	done := make(chan error, 1)
+	go func() { done <- a.ProcessStream("default") }()
+
+	// Remote controls race with the pipeline goroutines
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.Pause()
+			a.Status()
+			a.SetLanguage("en")
+			a.Resume()
+		}()
+	}
+	wg.Wait()
+	a.Stop()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected ProcessStream to return after Stop")
+	}
+}
+
+func TestDropOldest_CountsOverload(t *testing.T) {
+	a, _ := New(Options{Whisper: whisper.NewMockWhisperService()})
+	a.console()
+	a.SetTranscriptionQueue(2, QueuePolicyDropOldest)
+
+	// Nothing is consumed while 5 segments of a second go through a queue of 2
+	segments := make(chan pipeline.Segment, 5)
+	for i := range 5 {
+		segments <- pipeline.Segment{Samples: make([]float32, SampleRate), Offset: int64(i * SampleRate)}
+	}
+	close(segments)
+	queued := pipeline.Queue(context.Background(), segments, a.queueSize, a.overflow())
+
+	deadline := time.Now().Add(2 * time.Second)
+	for a.Overload().DroppedSegments < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	var offsets []int64
+	for segment := range queued {
+		offsets = append(offsets, segment.Offset/SampleRate)
+	}
+	if len(offsets) != 2 || offsets[0] != 3 || offsets[1] != 4 {
+		t.Errorf("Expected the 2 newest segments to be kept, got %v", offsets)
+	}
+
+	overload := a.Overload()
+	if overload.DroppedSegments != 3 || overload.DroppedAudio != 3*time.Second {
+		t.Errorf("Expected 3 dropped segments of a second, got %+v", overload)
+	}
+	if dropped := a.AudioStats().SamplesDropped; dropped != 3*SampleRate {
+		t.Errorf("Expected %d dropped samples, got %d", 3*SampleRate, dropped)
+	}
+}
+
+func TestMergeSegments_CountsOverload(t *testing.T) {
+	a, _ := New(Options{Whisper: whisper.NewMockWhisperService()})
+	a.console()
+	recorder := &eventRecorder{}
+	a.AddEmitter(recorder)
+
+	pending := []pipeline.Segment{{Samples: make([]float32, SampleRate*MaxBufferDurationS)}}
+	merged := a.mergeSegments(pending, pipeline.Segment{Samples: make([]float32, SampleRate)})
+	if len(merged) != 1 || len(merged[0].Samples) != SampleRate {
+		t.Fatalf("Expected the oldest audio beyond the limit to be dropped, got %d segments", len(merged))
+	}
+
+	pending = []pipeline.Segment{{Samples: make([]float32, SampleRate)}, {Samples: make([]float32, SampleRate)}}
+	merged = a.mergeSegments(pending, pipeline.Segment{Samples: make([]float32, SampleRate)})
+	if len(merged) != 1 || len(merged[0].Samples) != 3*SampleRate {
+		t.Fatalf("Expected one 3 seconds segment, got %+v", merged)
+	}
+
+	overload := a.Overload()
+	if overload.DroppedSegments != 1 || overload.DroppedAudio != MaxBufferDurationS*time.Second {
+		t.Errorf("Expected one dropped segment of %ds, got %+v", MaxBufferDurationS, overload)
+	}
+	if overload.MergedSegments != 2 {
+		t.Errorf("Expected 2 merged segments, got %d", overload.MergedSegments)
+	}
+	if status := a.Status(); status.Overload.DroppedSegments != 1 || status.Overload.MergedSegments != 2 {
+		t.Errorf("Expected the counters in the status, got %+v", status.Overload)
+	}
+	if len(recorder.texts(output.EventOverload)) != 2 {
+		t.Errorf("Expected 2 overload events, got %d", len(recorder.texts(output.EventOverload)))
+	}
+}
+
+func TestSyncModel_FallsBackAndRecovers(t *testing.T) {
+	service := whisper.NewMockWhisperService()
+	a, _ := New(Options{Whisper: service})
+	a.console()
+	a.SetFallbackModel("small.bin")
+	a.Initialize("large.bin", "default", "fr")
+
+	a.wantFallback.Store(true)
+	a.syncModel()
+	if service.GetModelPath() != "small.bin" {
+		t.Errorf("Expected the fallback model, got '%s'", service.GetModelPath())
+	}
+
+	a.wantFallback.Store(false)
+	a.syncModel()
+	if service.GetModelPath() != "large.bin" {
+		t.Errorf("Expected the configured model back, got '%s'", service.GetModelPath())
+	}
+	if a.Overload().FallbackSwitches != 1 {
+		t.Errorf("Expected one fallback switch, got %d", a.Overload().FallbackSwitches)
+	}
+}
+
+func TestSetAIAvailable_PausesAnswers(t *testing.T) {
+	service := ai.NewMockAIService()
+	service.SetResponses([]ai.ChatResponse{
+		{Message: ai.Message{Role: "assistant", Content: "Salut !"}, Done: true},
+	})
+	a, _ := New(Options{Whisper: whisper.NewMockWhisperService(), AI: service})
+	a.console()
+	recorder := &eventRecorder{}
+	a.AddEmitter(recorder)
+
+	a.SetAIAvailable(false)
+	a.SetAIAvailable(false)
+	a.Ask("Bonjour")
+	if texts := recorder.texts(output.EventAIResponse); len(texts) != 0 {
+		t.Errorf("Expected no answer while the AI is unavailable, got %v", texts)
+	}
+	if a.AIAvailable() || a.Status().AIAvailable || !a.AIEnabled() {
+		t.Error("Expected the AI to stay enabled but be reported unavailable")
+	}
+
+	a.SetAIAvailable(true)
+	a.Ask("Bonjour")
+	if texts := recorder.texts(output.EventAIResponse); len(texts) != 1 {
+		t.Errorf("Expected an answer once the AI is back, got %v", texts)
+	}
+
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	var states []string
+	for _, event := range recorder.events {
+		if event.Type == output.EventAIStatus {
+			states = append(states, event.State)
+		}
+	}
+	if len(states) != 2 || states[0] != "unavailable" || states[1] != "available" {
+		t.Errorf("Expected one event per change, got %v", states)
+	}
+}
+
+func TestAnswer(t *testing.T) {
+	conversation := ai.NewMockConversationManager()
+	a, recorder := newTestAssistant(t, Options{AI: ai.NewMockAIService(), Conversation: conversation}, 9, "Bonjour")
+	defer a.Close()
+	router, err := intents.NewRouter([]intents.Intent{
+		{Name: "lights", Patterns: []string{"allume la lumière"}, Response: "Lumière allumée"},
+		{Name: "reboot", Patterns: []string{"redémarre"}, Response: "Redémarrage.", Confirm: true},
+	}, "fr")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	a.SetIntents(router)
+	redactor, _ := redact.New(redact.DefaultRules, nil)
+	a.SetRedactor(redactor)
+	// Typed messages are addressed to the assistant, the trigger is for transcripts
+	a.SetAITrigger(AITrigger{Name: "Jack"})
+
+	reply, err := a.Answer("Allume la lumière", ai.NewMetadata(time.Now(), ai.SourceTelegram))
+	if err != nil || reply != "Lumière allumée" {
+		t.Errorf("Expected the intent to answer, got %q (%v)", reply, err)
+	}
+
+	reply, err = a.Answer("Écris à jean@exemple.fr", ai.NewMetadata(time.Now(), ai.SourceTelegram))
+	if err != nil || reply != "Mock streaming response" {
+		t.Errorf("Expected the AI to answer, got %q (%v)", reply, err)
+	}
+	messages := conversation.GetMessages()
+	if len(messages) != 2 || messages[0].Content != "Écris à [email]" || messages[0].Meta.Source != ai.SourceTelegram {
+		t.Errorf("Expected the redacted exchange in the conversation with its source, got %+v", messages)
+	}
+	if texts := recorder.texts(output.EventAIResponse); len(texts) != 2 || texts[1] != reply {
+		t.Errorf("Expected the replies to be emitted, got %v", texts)
+	}
+
+	// A confirmation asked at home is not answered remotely
+	router.Handle("redémarre")
+	if reply, _ := a.Answer("oui", nil); reply == "Redémarrage." {
+		t.Error("Expected the remote yes not to confirm the spoken question")
+	}
+	if reply, _ := a.Answer("redémarre", nil); reply == "Redémarrage." || strings.Contains(reply, "redémarre") {
+		t.Errorf("Expected the intents to confirm to be left out, got %q", reply)
+	}
+
+	a.SetAIAvailable(false)
+	if _, err := a.Answer("Bonjour", nil); !errors.Is(err, ai.ErrAIUnavailable) {
+		t.Errorf("Expected the AI to be unavailable, got: %v", err)
+	}
+
+	withoutAI, _ := newTestAssistant(t, Options{}, 9, "Bonjour")
+	defer withoutAI.Close()
+	if _, err := withoutAI.Answer("Bonjour", nil); err == nil {
+		t.Error("Expected an error without AI")
+	}
+}
+
+func TestAnswer_ReturnsTheAnswerOfACommand(t *testing.T) {
+	a, recorder := newTestAssistant(t, Options{AI: ai.NewMockAIService(), Conversation: ai.NewMockConversationManager()}, 9, "Bonjour")
+	defer a.Close()
+	commands, _ := intents.NewRouter([]intents.Intent{{Name: "shorter", Patterns: []string{"plus court"}, Handler: "shorter"}}, "fr")
+	commands.Register("shorter", intents.HandlerFunc(func(match intents.Match) (string, error) {
+		return a.Ask("Reformule plus court")
+	}))
+	a.SetVoiceCommands(commands)
+
+	reply, err := a.Answer("Plus court", nil)
+	if err != nil || reply != "Mock streaming response" {
+		t.Errorf("Expected the AI answer of the command, got %q (%v)", reply, err)
+	}
+	if texts := recorder.texts(output.EventAIResponse); len(texts) != 1 {
+		t.Errorf("Expected the answer to be output once, got %v", texts)
+	}
+}
+
+func TestProcessStream_DropsBlockedPhrases(t *testing.T) {
+	a, recorder := newTestAssistant(t, Options{}, 9, " Sous-titres réalisés par la communauté d'Amara.org")
+	defer a.Close()
+	list, _ := blocklist.New(true, nil, nil)
+	a.SetBlocklist(list)
+
+	if err := a.ProcessStream("default"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if texts := recorder.texts(output.EventTranscript); len(texts) != 0 {
+		t.Errorf("Expected the credits dropped, got %v", texts)
+	}
+	if a.LastTranscript() != "" {
+		t.Errorf("Expected no last transcript, got %q", a.LastTranscript())
+	}
+}
+
+func TestEmitPartial_RedactsAndBlocks(t *testing.T) {
+	a, _ := New(Options{Whisper: whisper.NewMockWhisperService()})
+	a.console()
+	recorder := &eventRecorder{}
+	a.AddEmitter(recorder)
+	redactor, _ := redact.New(redact.DefaultRules, nil)
+	a.SetRedactor(redactor)
+	list, _ := blocklist.New(true, nil, nil)
+	a.SetBlocklist(list)
+
+	a.emitPartial(pipeline.Segment{}, whisper.Segment{Text: " Écris à jean@exemple.fr"})
+	a.emitPartial(pipeline.Segment{}, whisper.Segment{Text: " Sous-titres réalisés par la communauté d'Amara.org"})
+
+	if texts := recorder.texts(output.EventPartial); len(texts) != 1 || texts[0] != "Écris à [email]" {
+		t.Errorf("Expected only the redacted partial, got %v", texts)
+	}
+}
+
+func TestAssistant_ExportImportConversation(t *testing.T) {
+	conversation := ai.NewMockConversationManager()
+	a, _ := newTestAssistant(t, Options{AI: ai.NewMockAIService(), Conversation: conversation}, 9, "Bonjour")
+	defer a.Close()
+
+	err := a.ImportConversation(strings.NewReader(`{"system_prompt": "Tu es Jack", "messages": [{"role": "user", "content": "Bonjour"}]}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if messages := conversation.GetMessages(); len(messages) != 1 || messages[0].Content != "Bonjour" {
+		t.Errorf("Expected the imported message, got %+v", messages)
+	}
+
+	var exported strings.Builder
+	if err := a.ExportConversation(&exported); err != nil || !strings.Contains(exported.String(), "Tu es Jack") {
+		t.Errorf("Expected the system prompt in the export, got %q (%v)", exported.String(), err)
+	}
+
+	withoutAI, _ := newTestAssistant(t, Options{}, 9, "Bonjour")
+	defer withoutAI.Close()
+	if err := withoutAI.ExportConversation(&exported); err == nil {
+		t.Error("Expected an error without AI")
+	}
+}
@@ -0,0 +1,121 @@
+package assistant
+
+import (
+	"fmt"
+
+	"github.com/nerzhul/nrz-ai/internal/i18n"
+	"github.com/nerzhul/nrz-ai/internal/intents"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+)
+
+// languageSwitcher follows the languages detected by Whisper and switches
+// once a new one was detected in enough consecutive utterances, so that a
+// single misdetected utterance changes nothing
+type languageSwitcher struct {
+	after     int
+	current   string
+	candidate string
+	count     int
+}
+
+// observe records the language of an utterance and reports whether it
+// became the current language
+func (s *languageSwitcher) observe(language string) bool {
+	if language == "" || language == "auto" || language == s.current {
+		s.candidate, s.count = "", 0
+		return false
+	}
+	if language != s.candidate {
+		s.candidate, s.count = language, 0
+	}
+	s.count++
+	if s.count < s.after {
+		return false
+	}
+	s.current, s.candidate, s.count = language, "", 0
+	return true
+}
+
+// SetLanguageSwitch follows the spoken language when transcribing with
+// language auto: once after consecutive utterances are detected in another
+// language, voice commands, intents, dictation and the AI switch to it, with
+// the system prompt of prompts for that language if any. Whisper stays on
+// auto so that it keeps detecting the language. 0 turns it off.
+func (a *Assistant) SetLanguageSwitch(after int, prompts map[string]string) {
+	a.stateMutex.Lock()
+	defer a.stateMutex.Unlock()
+	if after <= 0 {
+		a.languageSwitch = nil
+		return
+	}
+	a.languageSwitch = &languageSwitcher{after: after}
+	a.languagePrompts = prompts
+}
+
+// LockLanguage stops, or resumes, the automatic language switching
+func (a *Assistant) LockLanguage(locked bool) {
+	a.stateMutex.Lock()
+	a.languageLocked = locked
+	language := a.language
+	if a.languageSwitch != nil && a.languageSwitch.current != "" {
+		language = a.languageSwitch.current
+	}
+	a.stateMutex.Unlock()
+
+	if locked {
+		fmt.Printf("🔒 Language locked to %s\n", language)
+		return
+	}
+	fmt.Println("🔓 Automatic language switching resumed")
+}
+
+// LanguageLocked reports whether the automatic language switching is locked
+func (a *Assistant) LanguageLocked() bool {
+	a.stateMutex.Lock()
+	defer a.stateMutex.Unlock()
+	return a.languageLocked
+}
+
+// DetectedLanguage returns the language switched to automatically, empty
+// until the first switch
+func (a *Assistant) DetectedLanguage() string {
+	a.stateMutex.Lock()
+	defer a.stateMutex.Unlock()
+	if a.languageSwitch == nil {
+		return ""
+	}
+	return a.languageSwitch.current
+}
+
+// observeLanguage switches to the language detected in an utterance if it
+// persisted long enough
+func (a *Assistant) observeLanguage(detected string) {
+	a.stateMutex.Lock()
+	switcher := a.languageSwitch
+	switched := switcher != nil && !a.languageLocked && a.language == "auto" && switcher.observe(detected)
+	prompt, hasPrompt := a.languagePrompts[detected]
+	a.stateMutex.Unlock()
+	if !switched {
+		return
+	}
+
+	fmt.Printf("🌐 Language switched to %s, spoken in %d utterances in a row\n", detected, switcher.after)
+	for _, router := range []*intents.Router{a.commands, a.intents} {
+		if router != nil {
+			router.SetLanguage(detected)
+		}
+	}
+	if setter, ok := a.aiService.(ai.LanguageSetter); ok {
+		setter.SetLanguage(detected)
+	}
+	if a.dictation != nil {
+		a.dictation.SetLanguage(detected)
+	}
+	if hasPrompt {
+		a.SetPersona(prompt)
+	}
+
+	speaking := a.state.Begin(StateSpeaking)
+	a.reply(i18n.T(detected, "command.language_switched", i18n.T(detected, "language."+detected)))
+	speaking()
+}
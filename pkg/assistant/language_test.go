@@ -0,0 +1,102 @@
+package assistant
+
+import (
+	"testing"
+
+	"github.com/nerzhul/nrz-ai/internal/dictation"
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+func TestLanguageSwitcher_NeedsConsecutiveUtterances(t *testing.T) {
+	s := &languageSwitcher{after: 3, current: "fr"}
+
+	for i, step := range []struct {
+		language string
+		switched bool
+	}{
+		{"en", false},
+		{"en", false},
+		{"fr", false}, // Back to French, the count starts again
+		{"en", false},
+		{"auto", false},
+		{"en", false},
+		{"de", false},
+		{"en", false},
+		{"en", false},
+		{"en", true},
+		{"en", false},
+	} {
+		if switched := s.observe(step.language); switched != step.switched {
+			t.Errorf("Step %d: expected switched %v for %s, got %v", i, step.switched, step.language, switched)
+		}
+	}
+	if s.current != "en" {
+		t.Errorf("Expected en as the current language, got %s", s.current)
+	}
+}
+
+func TestObserveLanguage_SwitchesTheReplyLanguage(t *testing.T) {
+	conversation := ai.NewMockConversationManager()
+	a, err := New(Options{Whisper: whisper.NewMockWhisperService(), Conversation: conversation})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	recorder := &eventRecorder{}
+	a.console()
+	a.AddEmitter(recorder)
+	a.SetLanguageSwitch(2, map[string]string{"en": "You are a concise voice assistant."})
+	if err := a.SetLanguage("auto"); err != nil {
+		t.Fatal(err)
+	}
+
+	a.observeLanguage("en")
+	if detected := a.Status().Detected; detected != "" {
+		t.Errorf("Expected no switch after one utterance, got %s", detected)
+	}
+	a.observeLanguage("en")
+	if detected := a.Status().Detected; detected != "en" {
+		t.Errorf("Expected a switch to en, got %q", detected)
+	}
+	if prompt := conversation.ReplaceSystemPrompt(""); prompt != "You are a concise voice assistant." {
+		t.Errorf("Expected the English system prompt, got %q", prompt)
+	}
+	if texts := recorder.texts(output.EventAIResponse); len(texts) != 1 || texts[0] != "I'll answer in English from now on." {
+		t.Errorf("Expected the switch to be announced, got %v", texts)
+	}
+
+	a.LockLanguage(true)
+	a.observeLanguage("fr")
+	a.observeLanguage("fr")
+	if status := a.Status(); status.Detected != "en" || !status.LanguageLocked {
+		t.Errorf("Expected the locked language to stay en, got %+v", status)
+	}
+}
+
+func TestObserveLanguage_SwitchesTheDictation(t *testing.T) {
+	service := whisper.NewMockWhisperService()
+	a, err := New(Options{Whisper: service, Quiet: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	injector := dictation.NewMockInjector()
+	a.SetDictation(dictation.NewDictation(injector, "auto"))
+	a.SetLanguageSwitch(2, nil)
+	if err := a.SetLanguage("auto"); err != nil {
+		t.Fatal(err)
+	}
+
+	a.observeLanguage("fr")
+	a.observeLanguage("fr")
+	if err := a.dictation.Dictate("Bonjour virgule toi"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if typed := injector.Typed(); len(typed) != 1 || typed[0] != "Bonjour, toi" {
+		t.Errorf("Expected the French dictation commands, got %q", typed)
+	}
+	// Whisper keeps detecting the language to notice the next switch
+	if language := service.GetLanguage(); language != "auto" {
+		t.Errorf("Expected the transcription language to stay auto, got %q", language)
+	}
+}
@@ -0,0 +1,73 @@
+package assistant
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/internal/pipeline"
+)
+
+// Latency is the time an utterance spent in every stage
+type Latency struct {
+	Audio      time.Duration // Length of the utterance
+	Wait       time.Duration // Queued before transcription
+	Transcribe time.Duration // Whisper
+	Answer     time.Duration // Intents or AI, zero when not answered
+	Total      time.Duration // From the end of the utterance to the answer, or the transcript
+}
+
+// newLatency returns the latency of a transcript, Answer and Total unset
+func newLatency(transcript pipeline.Transcript) Latency {
+	return Latency{
+		Audio:      transcript.Segment.Duration(SampleRate),
+		Wait:       transcript.Wait,
+		Transcribe: transcript.Elapsed,
+	}
+}
+
+// RTF returns the real-time factor of the transcription, below 1 when
+// Whisper is faster than speech
+func (l Latency) RTF() float64 {
+	if l.Audio <= 0 {
+		return 0
+	}
+	return l.Transcribe.Seconds() / l.Audio.Seconds()
+}
+
+// String formats the latency for the logs
+func (l Latency) String() string {
+	parts := []string{
+		fmt.Sprintf("audio %.2fs", l.Audio.Seconds()),
+		fmt.Sprintf("queue %.2fs", l.Wait.Seconds()),
+		fmt.Sprintf("whisper %.2fs (RTF %.2f)", l.Transcribe.Seconds(), l.RTF()),
+	}
+	if l.Answer > 0 {
+		parts = append(parts, fmt.Sprintf("answer %.2fs", l.Answer.Seconds()))
+	}
+	parts = append(parts, fmt.Sprintf("total %.2fs", l.Total.Seconds()))
+	return strings.Join(parts, ", ")
+}
+
+// reportLatency completes the latency of the utterance cut at ready and
+// logs it at debug level
+func (a *Assistant) reportLatency(latency *Latency, ready time.Time) {
+	if ready.IsZero() {
+		latency.Total = latency.Wait + latency.Transcribe + latency.Answer
+	} else {
+		latency.Total = time.Since(ready)
+	}
+	logger.Debugf("⏱️  Latency: %s", latency)
+
+	a.stateMutex.Lock()
+	a.lastLatency = *latency
+	a.stateMutex.Unlock()
+}
+
+// LastLatency returns the latency of the last transcribed utterance
+func (a *Assistant) LastLatency() Latency {
+	a.stateMutex.Lock()
+	defer a.stateMutex.Unlock()
+	return a.lastLatency
+}
@@ -0,0 +1,54 @@
+package assistant
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+)
+
+func TestLatency_String(t *testing.T) {
+	latency := Latency{
+		Audio:      2 * time.Second,
+		Wait:       100 * time.Millisecond,
+		Transcribe: 500 * time.Millisecond,
+		Total:      600 * time.Millisecond,
+	}
+	if latency.RTF() != 0.25 {
+		t.Errorf("Expected RTF 0.25, got %.2f", latency.RTF())
+	}
+	expected := "audio 2.00s, queue 0.10s, whisper 0.50s (RTF 0.25), total 0.60s"
+	if latency.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, latency.String())
+	}
+
+	latency.Answer = 1200 * time.Millisecond
+	if !strings.Contains(latency.String(), "answer 1.20s") {
+		t.Errorf("Expected the answer time, got %q", latency.String())
+	}
+	if (Latency{}).RTF() != 0 {
+		t.Error("Expected no RTF without audio")
+	}
+}
+
+func TestProcessStream_ReportsLatency(t *testing.T) {
+	service := ai.NewMockAIService()
+	service.SetResponses([]ai.ChatResponse{
+		{Message: ai.Message{Role: "assistant", Content: "Salut !"}, Done: true},
+	})
+	a, _ := newTestAssistant(t, Options{AI: service}, 9, "Bonjour")
+	defer a.Close()
+
+	if err := a.ProcessStream("default"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	latency := a.LastLatency()
+	if latency.Audio <= 0 {
+		t.Errorf("Expected the length of the utterance, got %s", latency.Audio)
+	}
+	if latency.Total < latency.Transcribe+latency.Answer {
+		t.Errorf("Expected the total to cover every stage, got %s", latency)
+	}
+}
@@ -0,0 +1,74 @@
+package assistant
+
+import (
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+	"github.com/nerzhul/nrz-ai/pkg/vad"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+// Option sets up the Options of New, so that new capabilities are added
+// without changing the calls of the existing ones:
+//
+//	assistant.New(assistant.Options{}, assistant.WithWhisper(w), assistant.WithWakeWord("jack"))
+type Option func(*Options)
+
+// WithWhisper transcribes the utterances with service
+func WithWhisper(service whisper.WhisperService) Option {
+	return func(o *Options) {
+		o.Whisper = service
+	}
+}
+
+// WithCapture opens the audio source with capture instead of ffmpeg
+func WithCapture(capture audio.AudioCapture) Option {
+	return func(o *Options) {
+		o.Capture = capture
+	}
+}
+
+// WithProcessor converts the captured bytes to samples with processor
+func WithProcessor(processor audio.AudioProcessor) Option {
+	return func(o *Options) {
+		o.Processor = processor
+	}
+}
+
+// WithDetector finds speech with detector instead of the RMS detector
+func WithDetector(detector vad.VoiceActivityDetector) Option {
+	return func(o *Options) {
+		o.Detector = detector
+	}
+}
+
+// WithAI answers the transcripts with service, keeping the history in
+// conversation, a new one when nil
+func WithAI(service ai.AIService, conversation ai.ConversationManager) Option {
+	return func(o *Options) {
+		o.AI = service
+		o.Conversation = conversation
+	}
+}
+
+// WithWakeWord only listens after word was heard
+func WithWakeWord(word string) Option {
+	return func(o *Options) {
+		o.WakeWord = word
+	}
+}
+
+// WithOutput sends every pipeline event to emitters, next to the console
+// lines
+func WithOutput(emitters ...output.Emitter) Option {
+	return func(o *Options) {
+		o.Outputs = append(o.Outputs, emitters...)
+	}
+}
+
+// WithoutConsole drops the console lines
+func WithoutConsole() Option {
+	return func(o *Options) {
+		o.Quiet = true
+	}
+}
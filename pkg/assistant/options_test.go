@@ -0,0 +1,52 @@
+package assistant
+
+import (
+	"testing"
+
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+	"github.com/nerzhul/nrz-ai/pkg/vad"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+func TestNew_FunctionalOptions(t *testing.T) {
+	service := whisper.NewMockWhisperService()
+	service.SetTranscribeResult(whisper.TranscriptionResult{Text: "Bonjour"})
+	detector := vad.NewMockVAD()
+	detector.SetSpeechPattern(speechPattern())
+	chat := ai.NewMockAIService()
+	chat.SetResponses([]ai.ChatResponse{
+		{Message: ai.Message{Role: "assistant", Content: "Salut !"}, Done: true},
+	})
+	recorder := &eventRecorder{}
+
+	a, err := New(Options{WakeWord: "jack"},
+		WithWhisper(service),
+		WithCapture(audio.NewMockAudioCapture(audio.NewMockAudioStream(encodeSamples(9)))),
+		WithDetector(detector),
+		WithAI(chat, nil),
+		WithWakeWord(""),
+		WithoutConsole(),
+		WithOutput(recorder),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer a.Close()
+	if a.WakeWord() != "" {
+		t.Error("Expected the options to override the struct fields")
+	}
+
+	a.SetVADConfig(testVADConfig())
+	if err := a.Initialize("test.bin", "default", "fr"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := a.ProcessStream("default"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if texts := recorder.texts(output.EventAIResponse); len(texts) != 1 || texts[0] != "Salut !" {
+		t.Errorf("Expected the AI answer on the output, got %v", texts)
+	}
+}
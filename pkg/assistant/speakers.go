@@ -0,0 +1,102 @@
+package assistant
+
+import (
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
+)
+
+// SpeakerProfiles identifies the enrolled speaker of an utterance, such as
+// a voices.Store
+type SpeakerProfiles interface {
+	// Identify returns the speaker of the utterance samples, ok is false
+	// for a guest
+	Identify(samples []float32) (name string, ok bool)
+
+	// Prompt returns the system prompt of name, empty for the default one
+	Prompt(name string) string
+}
+
+// SetSpeakers identifies the speaker of every utterance: each enrolled
+// speaker gets a conversation of maxHistory messages with their own system
+// prompt, or systemPrompt, while guests share the default conversation
+func (a *Assistant) SetSpeakers(profiles SpeakerProfiles, systemPrompt string, maxHistory int) {
+	a.speakersMutex.Lock()
+	defer a.speakersMutex.Unlock()
+
+	a.speakers = profiles
+	a.speakerPrompt = systemPrompt
+	a.speakerHistory = maxHistory
+	a.conversations = make(map[string]ai.ConversationManager)
+}
+
+// identifySpeaker returns the enrolled speaker of an utterance, empty for a
+// guest. Without diarization, the segments get the speaker name as label.
+func (a *Assistant) identifySpeaker(samples []float32, segments []whisper.Segment) string {
+	if a.speakers == nil {
+		return ""
+	}
+	name, ok := a.speakers.Identify(samples)
+	if !ok {
+		return ""
+	}
+	if a.diarizer == nil {
+		for i := range segments {
+			segments[i].Speaker = name
+		}
+	}
+	return name
+}
+
+// conversationFor returns the conversation of speaker, created on its
+// first utterance, or the default one for a guest
+func (a *Assistant) conversationFor(speaker string) ai.ConversationManager {
+	if speaker == "" || a.speakers == nil {
+		return a.conversation
+	}
+
+	a.speakersMutex.Lock()
+	defer a.speakersMutex.Unlock()
+
+	conversation, ok := a.conversations[speaker]
+	if !ok {
+		conversation = ai.NewConversation(a.speakerHistory)
+		conversation.SetSystemPrompt(a.promptOf(speaker))
+		a.conversations[speaker] = conversation
+	}
+	return conversation
+}
+
+// promptOf returns the system prompt of speaker. Caller holds speakersMutex.
+func (a *Assistant) promptOf(speaker string) string {
+	if prompt := a.speakers.Prompt(speaker); prompt != "" {
+		return prompt
+	}
+	return a.speakerPrompt
+}
+
+// clearSpeakerHistories forgets the conversations of the enrolled speakers
+func (a *Assistant) clearSpeakerHistories() {
+	a.speakersMutex.Lock()
+	defer a.speakersMutex.Unlock()
+
+	for _, conversation := range a.conversations {
+		conversation.ClearHistory()
+	}
+}
+
+// setSpeakerPersona replaces the default system prompt of the enrolled
+// speakers, those with their own prompt keep it
+func (a *Assistant) setSpeakerPersona(prompt string) {
+	a.speakersMutex.Lock()
+	defer a.speakersMutex.Unlock()
+
+	if a.speakers == nil {
+		return
+	}
+	a.speakerPrompt = prompt
+	for speaker, conversation := range a.conversations {
+		if a.speakers.Prompt(speaker) == "" {
+			conversation.SetSystemPrompt(prompt)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package assistant
+
+import (
+	"testing"
+
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+// fixedSpeaker identifies every utterance as name, a guest when empty
+type fixedSpeaker struct {
+	name    string
+	prompts map[string]string
+}
+
+func (f fixedSpeaker) Identify(samples []float32) (string, bool) {
+	return f.name, f.name != ""
+}
+
+func (f fixedSpeaker) Prompt(name string) string {
+	return f.prompts[name]
+}
+
+func TestProcessStream_SpeakerConversation(t *testing.T) {
+	service := ai.NewMockAIService()
+	service.SetResponses([]ai.ChatResponse{
+		{Message: ai.Message{Role: "assistant", Content: "Salut Alice !"}, Done: true},
+	})
+	guest := ai.NewMockConversationManager()
+
+	a, recorder := newTestAssistant(t, Options{AI: service, Conversation: guest}, 9, "Bonjour")
+	defer a.Close()
+	a.SetSpeakers(fixedSpeaker{"Alice", map[string]string{"Alice": "Tu parles à Alice."}}, "Prompt par défaut", 10)
+
+	if err := a.ProcessStream("default"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if messages := guest.GetMessages(); len(messages) != 0 {
+		t.Errorf("Expected the guest conversation untouched, got %+v", messages)
+	}
+	messages := a.conversationFor("Alice").GetMessages()
+	if len(messages) != 3 || messages[0].Content != "Tu parles à Alice." || messages[1].Content != "Bonjour" {
+		t.Errorf("Expected Alice's prompt and exchange, got %+v", messages)
+	}
+
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	for _, event := range recorder.events {
+		if event.Type == output.EventTranscript && event.Speaker != "Alice" {
+			t.Errorf("Expected the transcript labeled Alice, got '%s'", event.Speaker)
+		}
+	}
+}
+
+func TestProcessStream_GuestConversation(t *testing.T) {
+	service := ai.NewMockAIService()
+	service.SetResponses([]ai.ChatResponse{
+		{Message: ai.Message{Role: "assistant", Content: "Salut !"}, Done: true},
+	})
+	guest := ai.NewMockConversationManager()
+
+	a, _ := newTestAssistant(t, Options{AI: service, Conversation: guest}, 9, "Bonjour")
+	defer a.Close()
+	a.SetSpeakers(fixedSpeaker{}, "Prompt par défaut", 10)
+
+	if err := a.ProcessStream("default"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if messages := guest.GetMessages(); len(messages) != 2 || messages[0].Content != "Bonjour" {
+		t.Errorf("Expected the exchange in the guest conversation, got %+v", messages)
+	}
+}
+
+func TestSetPersona_KeepsSpeakerPrompts(t *testing.T) {
+	a, _ := newTestAssistant(t, Options{AI: ai.NewMockAIService()}, 9, "")
+	defer a.Close()
+	a.SetSpeakers(fixedSpeaker{prompts: map[string]string{"Alice": "Tu parles à Alice."}}, "Prompt par défaut", 10)
+	alice, bob := a.conversationFor("Alice"), a.conversationFor("Bob")
+
+	a.SetPersona("Tu es un pirate.")
+
+	if messages := alice.GetMessages(); len(messages) != 1 || messages[0].Content != "Tu parles à Alice." {
+		t.Errorf("Expected Alice's prompt kept, got %+v", messages)
+	}
+	if messages := bob.GetMessages(); len(messages) != 1 || messages[0].Content != "Tu es un pirate." {
+		t.Errorf("Expected Bob to get the persona, got %+v", messages)
+	}
+}
+
+func TestForgetLast_SpeakerConversation(t *testing.T) {
+	service := ai.NewMockAIService()
+	service.SetResponses([]ai.ChatResponse{
+		{Message: ai.Message{Role: "assistant", Content: "Salut Alice !"}, Done: true},
+	})
+	guest := ai.NewMockConversationManager()
+	guest.AddMessage(ai.Message{Role: "user", Content: "Quelle heure est-il ?"})
+
+	a, _ := newTestAssistant(t, Options{AI: service, Conversation: guest}, 9, "Bonjour")
+	defer a.Close()
+	a.SetSpeakers(fixedSpeaker{"Alice", nil}, "Prompt par défaut", 10)
+
+	if err := a.ProcessStream("default"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !a.ForgetLast() {
+		t.Fatal("Expected Alice's exchange to be forgotten")
+	}
+	if messages := a.conversationFor("Alice").GetMessages(); len(messages) != 1 || messages[0].Role != "system" {
+		t.Errorf("Expected only Alice's prompt left, got %+v", messages)
+	}
+	if messages := guest.GetMessages(); len(messages) != 1 {
+		t.Errorf("Expected the guest conversation untouched, got %+v", messages)
+	}
+	if a.ForgetLast() {
+		t.Error("Expected nothing left to forget")
+	}
+}
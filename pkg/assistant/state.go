@@ -0,0 +1,170 @@
+package assistant
+
+import (
+	"sync"
+	"time"
+)
+
+// State is a phase of the assistant, as reported to event listeners
+type State string
+
+const (
+	// StateIdle is the state before the pipeline starts and after it stops
+	StateIdle State = "idle"
+	// StateWakeListening waits for the wake word
+	StateWakeListening State = "wake_listening"
+	// StateActive listens for requests
+	StateActive State = "active"
+	// StateTranscribing transcribes an utterance
+	StateTranscribing State = "transcribing"
+	// StateThinking waits for the AI answer
+	StateThinking State = "thinking"
+	// StateSpeaking delivers an answer
+	StateSpeaking State = "speaking"
+	// StatePaused discards the audio until resumed
+	StatePaused State = "paused"
+)
+
+// activities lists the busy states, highest priority first: an answer
+// delivered while the next utterance is transcribed shows as speaking
+var activities = []State{StateSpeaking, StateThinking, StateTranscribing}
+
+// Transition is a change of state
+type Transition struct {
+	From State
+	To   State
+	Time time.Time
+}
+
+// Machine tracks the assistant state. The listening mode (idle, waiting for
+// the wake word or active) is set by the pipeline, activities (transcribing,
+// thinking, speaking) are begun and ended around the work they describe,
+// possibly from several goroutines; pause overrides both.
+type Machine struct {
+	mode   State
+	paused bool
+	busy   map[State]int
+	state  State
+	mutex  sync.Mutex
+
+	// Serializes transitions so listeners see them in order
+	notifyMutex sync.Mutex
+	listeners   map[int]func(Transition)
+	nextID      int
+
+	now func() time.Time
+}
+
+// NewMachine creates an idle state machine
+func NewMachine() *Machine {
+	return &Machine{
+		mode:      StateIdle,
+		busy:      make(map[State]int),
+		state:     StateIdle,
+		listeners: make(map[int]func(Transition)),
+		now:       time.Now,
+	}
+}
+
+// SetClock replaces the clock used to timestamp transitions
+func (m *Machine) SetClock(now func() time.Time) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.now = now
+}
+
+// State returns the current state
+func (m *Machine) State() State {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.state
+}
+
+// Mode returns the listening mode: idle, wake listening or active
+func (m *Machine) Mode() State {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.mode
+}
+
+// SetMode changes the listening mode
+func (m *Machine) SetMode(mode State) {
+	m.update(func() {
+		m.mode = mode
+	})
+}
+
+// SetPaused pauses or resumes the assistant
+func (m *Machine) SetPaused(paused bool) {
+	m.update(func() {
+		m.paused = paused
+	})
+}
+
+// Begin starts an activity and returns the function ending it, safe to
+// call more than once
+func (m *Machine) Begin(activity State) func() {
+	m.update(func() {
+		m.busy[activity]++
+	})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.update(func() {
+				m.busy[activity]--
+			})
+		})
+	}
+}
+
+// Subscribe calls listener on every transition, in order, until the
+// returned function is called. Listeners run on the goroutine changing the
+// state and may query it, but must not change it.
+func (m *Machine) Subscribe(listener func(Transition)) func() {
+	m.notifyMutex.Lock()
+	defer m.notifyMutex.Unlock()
+
+	id := m.nextID
+	m.nextID++
+	m.listeners[id] = listener
+
+	return func() {
+		m.notifyMutex.Lock()
+		defer m.notifyMutex.Unlock()
+		delete(m.listeners, id)
+	}
+}
+
+// update applies change and notifies the listeners if the state changed
+func (m *Machine) update(change func()) {
+	m.notifyMutex.Lock()
+	defer m.notifyMutex.Unlock()
+
+	m.mutex.Lock()
+	change()
+	transition := Transition{From: m.state, To: m.resolveLocked(), Time: m.now()}
+	m.state = transition.To
+	m.mutex.Unlock()
+
+	if transition.From == transition.To {
+		return
+	}
+	for _, listener := range m.listeners {
+		listener(transition)
+	}
+}
+
+// resolveLocked derives the state from the mode, pause and activities,
+// caller holds the mutex
+func (m *Machine) resolveLocked() State {
+	if m.paused {
+		return StatePaused
+	}
+	for _, activity := range activities {
+		if m.busy[activity] > 0 {
+			return activity
+		}
+	}
+	return m.mode
+}
@@ -0,0 +1,89 @@
+package assistant
+
+import (
+	"testing"
+)
+
+func record(m *Machine) *[]Transition {
+	var transitions []Transition
+	m.Subscribe(func(t Transition) { transitions = append(transitions, t) })
+	return &transitions
+}
+
+func TestMachine_Conversation(t *testing.T) {
+	m := NewMachine()
+	transitions := record(m)
+
+	m.SetMode(StateWakeListening)
+	m.SetMode(StateActive)
+	transcribing := m.Begin(StateTranscribing)
+	transcribing()
+	thinking := m.Begin(StateThinking)
+	speaking := m.Begin(StateSpeaking)
+	thinking()
+	speaking()
+	m.SetMode(StateWakeListening)
+
+	expected := []State{StateWakeListening, StateActive, StateTranscribing, StateActive,
+		StateThinking, StateSpeaking, StateActive, StateWakeListening}
+	if len(*transitions) != len(expected) {
+		t.Fatalf("Expected %d transitions, got %+v", len(expected), *transitions)
+	}
+	for i, state := range expected {
+		if (*transitions)[i].To != state {
+			t.Errorf("Expected transition %d to %s, got %s", i, state, (*transitions)[i].To)
+		}
+	}
+	if (*transitions)[0].From != StateIdle {
+		t.Errorf("Expected to start idle, got %s", (*transitions)[0].From)
+	}
+}
+
+func TestMachine_PauseOverrides(t *testing.T) {
+	m := NewMachine()
+	m.SetMode(StateActive)
+	thinking := m.Begin(StateThinking)
+
+	m.SetPaused(true)
+	if m.State() != StatePaused {
+		t.Errorf("Expected paused, got %s", m.State())
+	}
+
+	thinking()
+	m.SetPaused(false)
+	if m.State() != StateActive {
+		t.Errorf("Expected active after resume, got %s", m.State())
+	}
+}
+
+func TestMachine_ConcurrentActivities(t *testing.T) {
+	m := NewMachine()
+	m.SetMode(StateActive)
+
+	first := m.Begin(StateTranscribing)
+	second := m.Begin(StateTranscribing)
+	first()
+	first()
+	if m.State() != StateTranscribing {
+		t.Errorf("Expected transcribing while an activity remains, got %s", m.State())
+	}
+	second()
+	if m.State() != StateActive {
+		t.Errorf("Expected active, got %s", m.State())
+	}
+}
+
+func TestMachine_Unsubscribe(t *testing.T) {
+	m := NewMachine()
+	count := 0
+	unsubscribe := m.Subscribe(func(Transition) { count++ })
+
+	m.SetMode(StateActive)
+	m.SetMode(StateActive)
+	unsubscribe()
+	m.SetMode(StateIdle)
+
+	if count != 1 {
+		t.Errorf("Expected 1 notification, got %d", count)
+	}
+}
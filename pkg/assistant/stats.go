@@ -0,0 +1,110 @@
+package assistant
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/nerzhul/nrz-ai/internal/control"
+	"github.com/nerzhul/nrz-ai/internal/logger"
+	"github.com/nerzhul/nrz-ai/internal/pipeline"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+)
+
+// AudioStats counts the audio going through the pipeline, to tell whether
+// the machine keeps up with real time
+type AudioStats struct {
+	BytesRead        int64 // Read from the audio source
+	SamplesProcessed int64 // Decoded from what was read
+	SamplesDropped   int64 // Discarded unheard by the queue policy
+	Buffered         int   // Samples of the utterance being cut
+	BufferSize       int   // Most samples of an utterance
+	Queued           int   // Segments waiting for Whisper
+	QueueSize        int
+}
+
+// String formats the counters for the logs
+func (s AudioStats) String() string {
+	occupancy := 0.0
+	if s.BufferSize > 0 {
+		occupancy = float64(s.Buffered) / float64(s.BufferSize) * 100
+	}
+	return fmt.Sprintf("%.1f MB read, %.1fs processed, %.1fs dropped, buffer %.0f%% (%.1fs), queue %d/%d",
+		float64(s.BytesRead)/1e6, float64(s.SamplesProcessed)/SampleRate, float64(s.SamplesDropped)/SampleRate,
+		occupancy, float64(s.Buffered)/SampleRate, s.Queued, s.QueueSize)
+}
+
+// audioCounters are the counters of AudioStats updated by the stages
+type audioCounters struct {
+	bytesRead        atomic.Int64
+	samplesProcessed atomic.Int64
+	samplesDropped   atomic.Int64
+	queued           atomic.Pointer[<-chan pipeline.Segment]
+}
+
+// countingStream counts the bytes read from an audio stream
+type countingStream struct {
+	audio.AudioStream
+	count *atomic.Int64
+}
+
+// Read reads from the stream and counts the bytes read
+func (c countingStream) Read(p []byte) (int, error) {
+	n, err := c.AudioStream.Read(p)
+	c.count.Add(int64(n))
+	return n, err
+}
+
+// SetStatsInterval logs the audio counters every interval at debug level.
+// Zero disables the report. Must be called before ProcessStream.
+func (a *Assistant) SetStatsInterval(interval time.Duration) {
+	a.statsInterval = interval
+}
+
+// AudioStats returns the audio counters of the running pipeline
+func (a *Assistant) AudioStats() AudioStats {
+	stats := AudioStats{
+		BytesRead:        a.counters.bytesRead.Load(),
+		SamplesProcessed: a.counters.samplesProcessed.Load(),
+		SamplesDropped:   a.counters.samplesDropped.Load(),
+		QueueSize:        a.queueSize,
+	}
+	stats.Buffered, stats.BufferSize = a.segmenter.Buffered()
+	if queued := a.counters.queued.Load(); queued != nil {
+		stats.Queued = len(*queued)
+	}
+	return stats
+}
+
+// audioStatus reports the audio counters to the control socket
+func (a *Assistant) audioStatus() control.Audio {
+	stats := a.AudioStats()
+	return control.Audio{
+		BytesRead:   stats.BytesRead,
+		ProcessedS:  float64(stats.SamplesProcessed) / SampleRate,
+		DroppedS:    float64(stats.SamplesDropped) / SampleRate,
+		BufferedS:   float64(stats.Buffered) / SampleRate,
+		BufferSizeS: float64(stats.BufferSize) / SampleRate,
+		Queued:      stats.Queued,
+		QueueSize:   stats.QueueSize,
+	}
+}
+
+// reportStats logs the audio counters every statsInterval until ctx is done
+func (a *Assistant) reportStats(ctx context.Context) {
+	if a.statsInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(a.statsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			logger.Debugf("📊 Audio: %s", a.AudioStats())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
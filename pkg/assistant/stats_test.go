@@ -0,0 +1,49 @@
+package assistant
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAudioStats_String(t *testing.T) {
+	stats := AudioStats{
+		BytesRead:        1_920_000,
+		SamplesProcessed: 30 * SampleRate,
+		SamplesDropped:   2 * SampleRate,
+		Buffered:         SampleRate * MaxBufferDurationS / 4,
+		BufferSize:       SampleRate * MaxBufferDurationS,
+		Queued:           1,
+		QueueSize:        4,
+	}
+
+	want := "1.9 MB read, 30.0s processed, 2.0s dropped, buffer 25% (7.5s), queue 1/4"
+	if got := stats.String(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if !strings.Contains((AudioStats{}).String(), "buffer 0%") {
+		t.Error("Expected an empty buffer without a size")
+	}
+}
+
+func TestProcessStream_CountsAudio(t *testing.T) {
+	a, _ := newTestAssistant(t, Options{}, 9, "Bonjour")
+	defer a.Close()
+
+	if err := a.ProcessStream("default"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	stats := a.AudioStats()
+	if stats.BytesRead != 9*4 {
+		t.Errorf("Expected 36 bytes read, got %d", stats.BytesRead)
+	}
+	if stats.SamplesProcessed != 9 {
+		t.Errorf("Expected 9 samples processed, got %d", stats.SamplesProcessed)
+	}
+	if stats.SamplesDropped != 0 || stats.Queued != 0 || stats.Buffered != 0 {
+		t.Errorf("Expected nothing dropped or waiting once the stream ended, got %s", stats)
+	}
+	if status := a.Status().Audio; status.QueueSize != 4 || status.BufferSizeS != MaxBufferDurationS {
+		t.Errorf("Expected the queue and buffer sizes in the status, got %+v", status)
+	}
+}
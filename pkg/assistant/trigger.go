@@ -0,0 +1,124 @@
+package assistant
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/nerzhul/nrz-ai/internal/logger"
+)
+
+// AITrigger picks the transcripts sent to the AI, so that everything is
+// transcribed while the AI only answers when addressed. Voice commands and
+// intents are not affected.
+type AITrigger struct {
+	Questions bool     // Only questions, ending with "?"
+	Prefixes  []string // Words one of which must start the transcript, e.g. "Jack"
+	// Name must start the transcript and is removed from what the AI is
+	// sent: "Jack, quelle est la météo" asks "Quelle est la météo". The
+	// prefixes apply to what follows it.
+	Name string
+}
+
+// SetAITrigger sets the rules a transcript must follow to be sent to the AI
+func (a *Assistant) SetAITrigger(trigger AITrigger) {
+	a.stateMutex.Lock()
+	defer a.stateMutex.Unlock()
+	a.aiTrigger = trigger
+}
+
+// Matches reports whether text follows the rules, with the reason when not
+func (t AITrigger) Matches(text string) (bool, string) {
+	_, ok, reason := t.Apply(text)
+	return ok, reason
+}
+
+// Apply returns the text to send the AI when text follows the rules, without
+// the name, or the reason when not
+func (t AITrigger) Apply(text string) (string, bool, string) {
+	if t.Questions && !isQuestion(text) {
+		return "", false, "not a question"
+	}
+	if name := strings.TrimSpace(t.Name); name != "" {
+		rest, ok := cutWord(text, name)
+		if !ok {
+			return "", false, "not addressed to " + name
+		}
+		if strings.IndexFunc(rest, isWordRune) < 0 {
+			return "", false, "nothing said after " + name
+		}
+		text = capitalize(rest)
+	}
+	if len(t.Prefixes) > 0 && !startsWithAny(text, t.Prefixes) {
+		return "", false, "not addressed with " + strings.Join(t.Prefixes, ", ")
+	}
+	return text, true, ""
+}
+
+// triggerAI applies the AI trigger to a transcript, returning what to send
+// and logging the skipped ones at debug level
+func (a *Assistant) triggerAI(text string) (string, bool) {
+	a.stateMutex.Lock()
+	trigger := a.aiTrigger
+	a.stateMutex.Unlock()
+
+	question, ok, reason := trigger.Apply(text)
+	if !ok {
+		logger.Debugf("🔇 Not sending %q to the AI: %s", text, reason)
+	}
+	return question, ok
+}
+
+// isQuestion reports whether text ends with a question mark, before any
+// closing quote or bracket
+func isQuestion(text string) bool {
+	return strings.HasSuffix(strings.TrimRight(text, " \t\"'»)]"), "?")
+}
+
+// startsWithAny reports whether the first word of text is one of words,
+// ignoring case and the punctuation around it
+func startsWithAny(text string, words []string) bool {
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word == "" {
+			continue
+		}
+		if _, ok := cutWord(text, word); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// cutWord returns what follows word at the start of text, without the
+// punctuation around it, ignoring case
+func cutWord(text, word string) (string, bool) {
+	text = strings.TrimLeftFunc(text, isSeparator)
+	if len(text) < len(word) || !strings.EqualFold(text[:len(word)], word) {
+		return "", false
+	}
+	rest := text[len(word):]
+	if rest != "" && strings.IndexFunc(rest, isSeparator) != 0 {
+		// A longer word, such as "Jacky" for "Jack"
+		return "", false
+	}
+	return strings.TrimLeftFunc(rest, isSeparator), true
+}
+
+// capitalize upper cases the first letter of text
+func capitalize(text string) string {
+	r, size := utf8.DecodeRuneInString(text)
+	if size == 0 {
+		return text
+	}
+	return string(unicode.ToUpper(r)) + text[size:]
+}
+
+// isWordRune reports whether r belongs to a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isSeparator reports whether r separates words
+func isSeparator(r rune) bool {
+	return !isWordRune(r)
+}
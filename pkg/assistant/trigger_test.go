@@ -0,0 +1,110 @@
+package assistant
+
+import (
+	"testing"
+
+	"github.com/nerzhul/nrz-ai/pkg/ai"
+	"github.com/nerzhul/nrz-ai/pkg/output"
+)
+
+func TestAITrigger_Matches(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger AITrigger
+		text    string
+		want    bool
+	}{
+		{"no rule", AITrigger{}, "Il fait beau", true},
+		{"question", AITrigger{Questions: true}, "Quelle heure est-il ?", true},
+		{"quoted question", AITrigger{Questions: true}, "« Tu viens ? »", true},
+		{"statement", AITrigger{Questions: true}, "Il fait beau.", false},
+		{"prefix", AITrigger{Prefixes: []string{"Jack"}}, "Jack, quelle est la météo", true},
+		{"prefix case", AITrigger{Prefixes: []string{"jack"}}, "  JACK quelle est la météo", true},
+		{"prefix alone", AITrigger{Prefixes: []string{"Jack"}}, "Jack", true},
+		{"longer word", AITrigger{Prefixes: []string{"Jack"}}, "Jacky est parti", false},
+		{"not first", AITrigger{Prefixes: []string{"Jack"}}, "Dis Jack, la météo", false},
+		{"any prefix", AITrigger{Prefixes: []string{"Jack", "Ordinateur"}}, "Ordinateur, la météo", true},
+		{"both", AITrigger{Questions: true, Prefixes: []string{"Jack"}}, "Jack, il fait beau", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, reason := tt.trigger.Matches(tt.text); got != tt.want {
+				t.Errorf("Expected %v for %q, got %v (%s)", tt.want, tt.text, got, reason)
+			}
+		})
+	}
+}
+
+func TestAITrigger_RemovesTheName(t *testing.T) {
+	trigger := AITrigger{Name: "Jack"}
+	tests := []struct {
+		text string
+		want string
+		ok   bool
+	}{
+		{"Jack, quelle est la météo ?", "Quelle est la météo ?", true},
+		{"jack... éteins la lumière", "Éteins la lumière", true},
+		{"Jack.", "", false},
+		{"Quelle est la météo, Jack ?", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok, _ := trigger.Apply(tt.text)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("Expected %q (%v) for %q, got %q (%v)", tt.want, tt.ok, tt.text, got, ok)
+		}
+	}
+
+	trigger.Prefixes = []string{"Ordinateur"}
+	if ok, _ := trigger.Matches("Ordinateur, la météo"); ok {
+		t.Error("Expected the name to be needed before the prefixes")
+	}
+	if ok, _ := trigger.Matches("Jack, ordinateur, la météo"); !ok {
+		t.Error("Expected the prefixes to apply after the name")
+	}
+}
+
+func TestProcessStream_SkipsUntriggeredAI(t *testing.T) {
+	service := ai.NewMockAIService()
+	service.SetResponses([]ai.ChatResponse{
+		{Message: ai.Message{Role: "assistant", Content: "Salut !"}, Done: true},
+	})
+	a, recorder := newTestAssistant(t, Options{AI: service}, 9, "Bonjour tout le monde")
+	defer a.Close()
+	a.SetAITrigger(AITrigger{Prefixes: []string{"Jack"}})
+
+	if err := a.ProcessStream("default"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if a.LastTranscript() != "Bonjour tout le monde" {
+		t.Errorf("Expected the transcript anyway, got %q", a.LastTranscript())
+	}
+	if texts := recorder.texts(output.EventAIResponse); len(texts) != 0 {
+		t.Errorf("Expected no AI answer without the prefix, got %v", texts)
+	}
+}
+
+func TestProcessStream_AsksWithoutTheName(t *testing.T) {
+	service := ai.NewMockAIService()
+	service.SetResponses([]ai.ChatResponse{
+		{Message: ai.Message{Role: "assistant", Content: "Il fait beau."}, Done: true},
+	})
+	conversation := ai.NewMockConversationManager()
+	a, _ := newTestAssistant(t, Options{AI: service, Conversation: conversation}, 9, "Jack, quelle est la météo ?")
+	defer a.Close()
+	a.SetAITrigger(AITrigger{Name: "Jack"})
+
+	if err := a.ProcessStream("default"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if a.LastTranscript() != "Jack, quelle est la météo ?" {
+		t.Errorf("Expected the whole transcript, got %q", a.LastTranscript())
+	}
+	messages := conversation.GetMessages()
+	if len(messages) != 2 || messages[0].Content != "Quelle est la météo ?" {
+		t.Errorf("Expected the question without the name, got %+v", messages)
+	}
+}
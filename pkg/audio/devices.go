@@ -0,0 +1,147 @@
+package audio
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultSource captures from the default input device of the platform
+const DefaultSource = "default"
+
+// Device is an audio input device, Name is the --audio-source value
+type Device struct {
+	Name        string
+	Description string
+	// Monitor devices capture what the speakers play rather than a microphone
+	Monitor bool
+	// index is the AVFoundation device index, macOS only
+	index int
+}
+
+// ListDevices lists the audio input devices ffmpeg can capture from on
+// this platform
+func ListDevices() ([]Device, error) {
+	return listDevices()
+}
+
+// InputFormat returns the ffmpeg input device of this platform: pulse (PulseAudio
+// or PipeWire) on Linux, dshow (DirectShow) on Windows, avfoundation on macOS
+func InputFormat() string {
+	return inputFormat
+}
+
+// parsePactlSources parses `pactl list short sources`, monitors last
+func parsePactlSources(output string) []Device {
+	var devices, monitors []Device
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		device := Device{Name: fields[1], Monitor: strings.HasSuffix(fields[1], ".monitor")}
+		if len(fields) >= 4 {
+			device.Description = strings.Join(fields[2:4], " ")
+		}
+		if device.Monitor {
+			monitors = append(monitors, device)
+		} else {
+			devices = append(devices, device)
+		}
+	}
+	return append(devices, monitors...)
+}
+
+// parseDShowDevices parses the stderr of `ffmpeg -list_devices true -f dshow
+// -i dummy`. Recent ffmpeg builds tag every device with its type, older ones
+// list the audio devices under a "DirectShow audio devices" header.
+func parseDShowDevices(output string) []Device {
+	var devices []Device
+	audioSection := false
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		// Drop the "[dshow @ 0000012345678900]" prefix
+		if i := strings.Index(line, "]"); i >= 0 && strings.HasPrefix(line, "[") {
+			line = line[i+1:]
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.Contains(line, "DirectShow audio devices"):
+			audioSection = true
+			continue
+		case strings.Contains(line, "DirectShow video devices"):
+			audioSection = false
+			continue
+		case strings.HasPrefix(line, "Alternative name"):
+			continue
+		}
+
+		start := strings.Index(line, `"`)
+		end := strings.LastIndex(line, `"`)
+		if start != 0 || end <= start {
+			continue
+		}
+		kind := strings.TrimSpace(line[end+1:])
+		if kind == "(audio)" || (kind == "" && audioSection) {
+			devices = append(devices, Device{Name: line[start+1 : end], Description: "DirectShow"})
+		}
+	}
+	return devices
+}
+
+// parseAVFoundationDevices parses the stderr of `ffmpeg -f avfoundation
+// -list_devices true -i ""`, the audio devices follow the video ones
+func parseAVFoundationDevices(output string) []Device {
+	var devices []Device
+	audioSection := false
+	for _, line := range strings.Split(output, "\n") {
+		// Drop the "[AVFoundation indev @ 0x7fb7d8c04a40]" prefix
+		if i := strings.Index(line, "]"); i >= 0 && strings.HasPrefix(line, "[") {
+			line = line[i+1:]
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, "AVFoundation audio devices"):
+			audioSection = true
+			continue
+		case strings.HasPrefix(line, "AVFoundation video devices"):
+			audioSection = false
+			continue
+		}
+
+		// "[1] BlackHole 2ch"
+		end := strings.Index(line, "]")
+		if !audioSection || !strings.HasPrefix(line, "[") || end < 0 {
+			continue
+		}
+		index, err := strconv.Atoi(line[1:end])
+		if err != nil {
+			continue
+		}
+		devices = append(devices, Device{
+			Name:        strings.TrimSpace(line[end+1:]),
+			Description: "AVFoundation #" + strconv.Itoa(index),
+			index:       index,
+		})
+	}
+	return devices
+}
+
+// resolveAVFoundation returns the ffmpeg input of source among devices,
+// ":<index>" for a device name or index and ":default" for the default one
+func resolveAVFoundation(source string, devices []Device) (string, error) {
+	source = strings.TrimPrefix(source, ":")
+	if source == "" || source == DefaultSource {
+		return ":default", nil
+	}
+	for _, device := range devices {
+		if strings.EqualFold(device.Name, source) || strconv.Itoa(device.index) == source {
+			return ":" + strconv.Itoa(device.index), nil
+		}
+	}
+	return "", fmt.Errorf("%w: no AVFoundation audio device '%s'", ErrAudioSource, source)
+}
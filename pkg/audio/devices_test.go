@@ -0,0 +1,90 @@
+package audio
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParsePactlSources(t *testing.T) {
+	output := "52\talsa_output.pci-0000_00_1f.3.analog-stereo.monitor\tPipeWire\ts32le 2ch 48000Hz\tSUSPENDED\n" +
+		"53\talsa_input.pci-0000_00_1f.3.analog-stereo\tPipeWire\ts32le 2ch 48000Hz\tRUNNING\n"
+
+	devices := parsePactlSources(output)
+	if len(devices) != 2 {
+		t.Fatalf("Expected 2 sources, got %+v", devices)
+	}
+	if devices[0].Name != "alsa_input.pci-0000_00_1f.3.analog-stereo" || devices[0].Monitor {
+		t.Errorf("Expected the microphone first, got %+v", devices[0])
+	}
+	if !devices[1].Monitor {
+		t.Errorf("Expected the monitor last, got %+v", devices[1])
+	}
+}
+
+func TestParseDShowDevices(t *testing.T) {
+	recent := `[dshow @ 000001d9c3a5e2c0] "Integrated Camera" (video)
+[dshow @ 000001d9c3a5e2c0]   Alternative name "@device_pnp_\\?\usb#vid_5986"
+[dshow @ 000001d9c3a5e2c0] "Microphone Array (Realtek(R) Audio)" (audio)
+[dshow @ 000001d9c3a5e2c0]   Alternative name "@device_cm_{33D9A762-90C8-11D0-BD43-00A0C911CE86}\wave_{1A2B}"
+[dshow @ 000001d9c3a5e2c0] "Casque (Jabra Evolve 65)" (audio)
+dummy: Immediate exit requested`
+
+	older := `[dshow @ 0000000000a1b2c3] DirectShow video devices (some may be both video and audio devices)
+[dshow @ 0000000000a1b2c3]  "Integrated Camera"
+[dshow @ 0000000000a1b2c3] DirectShow audio devices
+[dshow @ 0000000000a1b2c3]  "Microphone Array (Realtek(R) Audio)"
+[dshow @ 0000000000a1b2c3]     Alternative name "@device_cm_{33D9A762}"
+[dshow @ 0000000000a1b2c3]  "Casque (Jabra Evolve 65)"`
+
+	expected := []string{"Microphone Array (Realtek(R) Audio)", "Casque (Jabra Evolve 65)"}
+	for name, output := range map[string]string{"recent": recent, "older": older} {
+		var names []string
+		for _, device := range parseDShowDevices(output) {
+			names = append(names, device.Name)
+		}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("%s ffmpeg: expected %v, got %v", name, expected, names)
+		}
+	}
+}
+
+func TestParseAVFoundationDevices(t *testing.T) {
+	output := `[AVFoundation indev @ 0x7fb7d8c04a40] AVFoundation video devices:
+[AVFoundation indev @ 0x7fb7d8c04a40] [0] FaceTime HD Camera
+[AVFoundation indev @ 0x7fb7d8c04a40] [1] Capture screen 0
+[AVFoundation indev @ 0x7fb7d8c04a40] AVFoundation audio devices:
+[AVFoundation indev @ 0x7fb7d8c04a40] [0] MacBook Pro Microphone
+[AVFoundation indev @ 0x7fb7d8c04a40] [1] BlackHole 2ch
+: Input/output error`
+
+	devices := parseAVFoundationDevices(output)
+	if len(devices) != 2 {
+		t.Fatalf("Expected 2 audio devices, got %+v", devices)
+	}
+	if devices[1].Name != "BlackHole 2ch" || devices[1].index != 1 {
+		t.Errorf("Expected BlackHole 2ch at index 1, got %+v", devices[1])
+	}
+}
+
+func TestResolveAVFoundation(t *testing.T) {
+	devices := []Device{{Name: "MacBook Pro Microphone", index: 0}, {Name: "BlackHole 2ch", index: 1}}
+
+	tests := map[string]string{
+		"default":       ":default",
+		"":              ":default",
+		"1":             ":1",
+		":0":            ":0",
+		"blackhole 2ch": ":1",
+	}
+	for source, expected := range tests {
+		input, err := resolveAVFoundation(source, devices)
+		if err != nil || input != expected {
+			t.Errorf("%q: expected %s, got %q (%v)", source, expected, input, err)
+		}
+	}
+
+	if _, err := resolveAVFoundation("AirPods", devices); !errors.Is(err, ErrAudioSource) {
+		t.Errorf("Expected ErrAudioSource for an unknown device, got %v", err)
+	}
+}
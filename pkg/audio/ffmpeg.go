@@ -0,0 +1,308 @@
+package audio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Common errors
+var (
+	// ErrAudioSource means the audio source cannot be captured: ffmpeg is
+	// missing, or the source does not exist or went away
+	ErrAudioSource = errors.New("audio source unavailable")
+)
+
+// terminateTimeout is how long ffmpeg may take to exit on SIGTERM before
+// it is killed
+const terminateTimeout = 2 * time.Second
+
+// FormatProbe is the input format letting ffmpeg detect the input from the
+// source, e.g. an rtsp:// URL
+const FormatProbe = "probe"
+
+// ffmpegPath is the ffmpeg binary, see SetFFmpegPath
+var ffmpegPath = "ffmpeg"
+
+// SetFFmpegPath sets the ffmpeg binary capturing, decoding and listing the
+// devices, a path or a name looked up in PATH. Empty restores ffmpeg.
+func SetFFmpegPath(path string) {
+	if path == "" {
+		path = "ffmpeg"
+	}
+	ffmpegPath = path
+}
+
+// FFmpegPath returns the ffmpeg binary
+func FFmpegPath() string {
+	return ffmpegPath
+}
+
+// FFmpegOptions adapts the capture to the devices the platform input does
+// not handle, such as JACK, an ALSA loopback or an RTSP camera. The zero
+// value captures with the platform input.
+type FFmpegOptions struct {
+	// InputFormat replaces the platform input (see InputFormat), e.g. alsa
+	// or jack, FormatProbe lets ffmpeg detect it
+	InputFormat string
+	// InputArgs are ffmpeg input options, e.g. -rtsp_transport tcp
+	InputArgs []string
+	// QueueSize is the number of packets queued from the input
+	// (-thread_queue_size), raise it when a device drops audio. 0 keeps
+	// the ffmpeg default.
+	QueueSize int
+}
+
+// FFmpegStream implements AudioStream using FFmpeg
+type FFmpegStream struct {
+	cmd     *exec.Cmd
+	stdout  io.ReadCloser
+	capture *FFmpegCapture // Tracking the stream until it is closed
+	stderr  *bytes.Buffer  // Error output of a decoding ffmpeg
+
+	waitOnce sync.Once
+	waitErr  error
+	exited   chan struct{} // Closed once ffmpeg was reaped
+	closed   sync.Once
+}
+
+// Read reads audio data from FFmpeg stdout. The end of the stream is an
+// ErrAudioSource when ffmpeg failed rather than ran out of input, or a
+// decoding error with the ffmpeg output.
+func (f *FFmpegStream) Read(data []byte) (int, error) {
+	n, err := f.stdout.Read(data)
+	if errors.Is(err, io.EOF) && f.cmd != nil {
+		if waitErr := f.wait(); waitErr != nil {
+			if f.stderr != nil {
+				return n, fmt.Errorf("ffmpeg failed to decode: %w: %s", waitErr, strings.TrimSpace(f.stderr.String()))
+			}
+			return n, fmt.Errorf("%w: ffmpeg %w", ErrAudioSource, waitErr)
+		}
+	}
+	return n, err
+}
+
+// wait reaps ffmpeg once, later calls return the same result
+func (f *FFmpegStream) wait() error {
+	f.waitOnce.Do(func() {
+		f.waitErr = f.cmd.Wait()
+		close(f.exited)
+	})
+	return f.waitErr
+}
+
+// Close terminates ffmpeg, killing it if it did not exit within
+// terminateTimeout, and reaps it
+func (f *FFmpegStream) Close() error {
+	var err error
+	f.closed.Do(func() {
+		if f.capture != nil {
+			f.capture.untrack(f)
+		}
+		if f.cmd != nil && f.cmd.Process != nil {
+			f.terminate()
+		}
+		if f.stdout != nil {
+			err = f.stdout.Close()
+		}
+	})
+	if errors.Is(err, os.ErrClosed) {
+		// Already closed by Wait
+		return nil
+	}
+	return err
+}
+
+// terminate asks ffmpeg to exit, then kills it, and waits for it
+func (f *FFmpegStream) terminate() {
+	select {
+	case <-f.exited:
+		return
+	default:
+	}
+
+	// Windows has no SIGTERM, the process is killed right away
+	if err := f.cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		f.cmd.Process.Kill()
+	}
+	go f.wait()
+
+	timer := time.NewTimer(terminateTimeout)
+	defer timer.Stop()
+	select {
+	case <-f.exited:
+	case <-timer.C:
+		f.cmd.Process.Kill()
+		<-f.exited
+	}
+}
+
+// FFmpegCapture implements AudioCapture using FFmpeg
+type FFmpegCapture struct {
+	options FFmpegOptions
+	streams map[*FFmpegStream]struct{} // Streams not closed yet
+	mutex   sync.Mutex
+}
+
+// NewFFmpegCapture creates a new FFmpeg audio capture
+func NewFFmpegCapture(options FFmpegOptions) *FFmpegCapture {
+	return &FFmpegCapture{
+		options: options,
+		streams: make(map[*FFmpegStream]struct{}),
+	}
+}
+
+// StartCapture starts capturing audio from the specified source, a device
+// of the platform input (see InputFormat) or "default"
+func (f *FFmpegCapture) StartCapture(audioSource string) (AudioStream, error) {
+	args, err := f.inputArgs(audioSource)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args,
+		"-ar", "16000",
+		"-ac", "1",
+		"-f", "f32le",
+		"-loglevel", "quiet",
+		"-")
+	cmd := exec.Command(ffmpegPath, args...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAudioSource, err)
+	}
+
+	stream := &FFmpegStream{
+		cmd:     cmd,
+		stdout:  stdout,
+		capture: f,
+		exited:  make(chan struct{}),
+	}
+	f.mutex.Lock()
+	f.streams[stream] = struct{}{}
+	f.mutex.Unlock()
+	return stream, nil
+}
+
+// inputArgs returns the ffmpeg arguments opening source with the options
+func (f *FFmpegCapture) inputArgs(source string) ([]string, error) {
+	var args []string
+	if f.options.QueueSize > 0 {
+		args = append(args, "-thread_queue_size", strconv.Itoa(f.options.QueueSize))
+	}
+	args = append(args, f.options.InputArgs...)
+
+	switch f.options.InputFormat {
+	case "", inputFormat:
+		input, err := inputArgs(source)
+		if err != nil {
+			return nil, err
+		}
+		return append(args, input...), nil
+	case FormatProbe:
+		return append(args, "-i", source), nil
+	default:
+		return append(args, "-f", f.options.InputFormat, "-i", source), nil
+	}
+}
+
+// Stop closes the streams still open, terminating their ffmpeg
+func (f *FFmpegCapture) Stop() error {
+	f.mutex.Lock()
+	streams := make([]*FFmpegStream, 0, len(f.streams))
+	for stream := range f.streams {
+		streams = append(streams, stream)
+	}
+	f.mutex.Unlock()
+
+	var errs []error
+	for _, stream := range streams {
+		if err := stream.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// untrack forgets a closed stream
+func (f *FFmpegCapture) untrack(stream *FFmpegStream) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	delete(f.streams, stream)
+}
+
+// FFmpegDecoder implements FileDecoder using FFmpeg
+type FFmpegDecoder struct {
+	processor AudioProcessor
+}
+
+// NewFFmpegDecoder creates a new FFmpeg file decoder
+func NewFFmpegDecoder() *FFmpegDecoder {
+	return &FFmpegDecoder{
+		processor: NewProcessor(),
+	}
+}
+
+// DecodeFile converts any FFmpeg-supported file to 16kHz mono float32 samples
+func (d *FFmpegDecoder) DecodeFile(path string) ([]float32, error) {
+	cmd := exec.Command(ffmpegPath,
+		"-i", path,
+		"-ar", "16000",
+		"-ac", "1",
+		"-f", "f32le",
+		"-loglevel", "error",
+		"-")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	data, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg failed to decode %s: %w: %s",
+			path, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return d.processor.ProcessBytes(data), nil
+}
+
+// DecodeStream starts ffmpeg converting a file to 16kHz mono float32
+// samples, read from the returned stream as they are decoded
+func (d *FFmpegDecoder) DecodeStream(path string) (AudioStream, error) {
+	cmd := exec.Command(ffmpegPath,
+		"-i", path,
+		"-ar", "16000",
+		"-ac", "1",
+		"-f", "f32le",
+		"-loglevel", "error",
+		"-")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("ffmpeg failed to decode %s: %w", path, err)
+	}
+
+	return &FFmpegStream{
+		cmd:    cmd,
+		stdout: stdout,
+		stderr: &stderr,
+		exited: make(chan struct{}),
+	}, nil
+}
@@ -0,0 +1,157 @@
+package audio
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startStream runs a shell command in place of ffmpeg
+func startStream(t *testing.T, script string) *FFmpegStream {
+	t.Helper()
+
+	cmd := exec.Command("sh", "-c", script)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("Cannot run sh: %v", err)
+	}
+	return &FFmpegStream{cmd: cmd, stdout: stdout, exited: make(chan struct{})}
+}
+
+func TestFFmpegStream_SourceFailure(t *testing.T) {
+	stream := startStream(t, "exit 1")
+	defer stream.Close()
+
+	_, err := io.ReadAll(stream)
+	if !errors.Is(err, ErrAudioSource) {
+		t.Errorf("Expected ErrAudioSource when ffmpeg fails, got: %v", err)
+	}
+}
+
+func TestFFmpegStream_EndOfInput(t *testing.T) {
+	stream := startStream(t, "printf abcd")
+	defer stream.Close()
+
+	data, err := io.ReadAll(stream)
+	if err != nil || string(data) != "abcd" {
+		t.Errorf("Expected the output and a clean end, got %q (%v)", data, err)
+	}
+}
+
+func TestFFmpegStream_CloseReapsFFmpeg(t *testing.T) {
+	stream := startStream(t, "exec sleep 30")
+
+	start := time.Now()
+	if err := stream.Close(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if stream.cmd.ProcessState == nil {
+		t.Error("Expected the process to be reaped")
+	}
+	if elapsed := time.Since(start); elapsed >= terminateTimeout {
+		t.Errorf("Expected SIGTERM to end the process, took %s", elapsed)
+	}
+	if err := stream.Close(); err != nil {
+		t.Errorf("Expected a second Close to succeed, got: %v", err)
+	}
+}
+
+func TestFFmpegCapture_StopClosesStreams(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "ffmpeg")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nexec sleep 30\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	SetFFmpegPath(script)
+	defer SetFFmpegPath("")
+
+	capture := NewFFmpegCapture(FFmpegOptions{InputFormat: "lavfi"})
+	stream, err := capture.StartCapture("anullsrc")
+	if err != nil {
+		t.Skipf("Cannot run the script: %v", err)
+	}
+
+	if err := capture.Stop(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if process := stream.(*FFmpegStream).cmd.ProcessState; process == nil {
+		t.Error("Expected Stop to terminate and reap ffmpeg")
+	}
+	if len(capture.streams) != 0 {
+		t.Errorf("Expected no stream left, got %d", len(capture.streams))
+	}
+}
+
+func TestFFmpegDecoder_DecodeStream(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "ffmpeg")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nprintf abcd\necho 'Invalid data found' >&2\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	SetFFmpegPath(script)
+	defer SetFFmpegPath("")
+
+	stream, err := NewFFmpegDecoder().DecodeStream("broken.mp3")
+	if err != nil {
+		t.Skipf("Cannot run the script: %v", err)
+	}
+	defer stream.Close()
+
+	data, err := io.ReadAll(stream)
+	if string(data) != "abcd" {
+		t.Errorf("Expected the decoded bytes, got %q", data)
+	}
+	if err == nil || !strings.Contains(err.Error(), "Invalid data found") {
+		t.Errorf("Expected the ffmpeg error output, got: %v", err)
+	}
+}
+
+func TestFFmpegCapture_InputArgs(t *testing.T) {
+	platform, err := inputArgs("default")
+	if err != nil {
+		t.Skipf("No platform input: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		options  FFmpegOptions
+		source   string
+		expected []string
+	}{
+		{"platform", FFmpegOptions{}, "default", platform},
+		{"alsa", FFmpegOptions{InputFormat: "alsa"}, "hw:Loopback,1", []string{"-f", "alsa", "-i", "hw:Loopback,1"}},
+		{"rtsp", FFmpegOptions{InputFormat: FormatProbe, InputArgs: []string{"-rtsp_transport", "tcp"}},
+			"rtsp://camera/stream", []string{"-rtsp_transport", "tcp", "-i", "rtsp://camera/stream"}},
+		{"queue", FFmpegOptions{InputFormat: "jack", QueueSize: 1024}, "nrz-ai",
+			[]string{"-thread_queue_size", "1024", "-f", "jack", "-i", "nrz-ai"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := NewFFmpegCapture(tt.options).inputArgs(tt.source)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if strings.Join(args, " ") != strings.Join(tt.expected, " ") {
+				t.Errorf("Expected %q, got %q", tt.expected, args)
+			}
+		})
+	}
+}
+
+func TestFFmpegCapture_Binary(t *testing.T) {
+	SetFFmpegPath("/nonexistent/ffmpeg")
+	defer SetFFmpegPath("")
+
+	if _, err := NewFFmpegCapture(FFmpegOptions{InputFormat: "lavfi"}).StartCapture("anullsrc"); !errors.Is(err, ErrAudioSource) {
+		t.Errorf("Expected ErrAudioSource with a missing ffmpeg binary, got: %v", err)
+	}
+	if _, err := NewFFmpegDecoder().DecodeFile("missing.wav"); err == nil || !strings.Contains(err.Error(), "ffmpeg failed") {
+		t.Errorf("Expected the decoder to run the configured binary, got: %v", err)
+	}
+}
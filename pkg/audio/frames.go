@@ -0,0 +1,42 @@
+package audio
+
+// BytesPerSample is the size of a 32-bit float sample frame
+const BytesPerSample = 4
+
+// FrameDecoder converts a byte stream to samples across Read calls. A read
+// may end in the middle of a frame: its first bytes are kept and completed
+// by the next call instead of shifting every following sample.
+type FrameDecoder struct {
+	processor AudioProcessor
+	partial   [BytesPerSample]byte
+	pending   int // Bytes of partial in use
+}
+
+// NewFrameDecoder creates a decoder converting frames with processor
+func NewFrameDecoder(processor AudioProcessor) *FrameDecoder {
+	return &FrameDecoder{processor: processor}
+}
+
+// Decode returns the samples completed by data, in a single allocation
+func (d *FrameDecoder) Decode(data []byte) []float32 {
+	samples := make([]float32, 0, (d.pending+len(data))/BytesPerSample)
+	if d.pending > 0 {
+		n := copy(d.partial[d.pending:], data)
+		d.pending += n
+		data = data[n:]
+		if d.pending < BytesPerSample {
+			return samples
+		}
+		samples = d.processor.AppendSamples(samples, d.partial[:])
+	}
+
+	whole := len(data) - len(data)%BytesPerSample
+	samples = d.processor.AppendSamples(samples, data[:whole])
+	d.pending = copy(d.partial[:], data[whole:])
+	return samples
+}
+
+// Pending returns the bytes of an incomplete frame waiting for the next call
+func (d *FrameDecoder) Pending() int {
+	return d.pending
+}
@@ -0,0 +1,49 @@
+//go:build darwin
+
+package audio
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+const inputFormat = "avfoundation"
+
+// inputArgs returns the ffmpeg arguments capturing source, an AVFoundation
+// audio device name or index. Names are resolved to their index, ffmpeg
+// only matches them exactly.
+func inputArgs(source string) ([]string, error) {
+	var devices []Device
+	if source != "" && source != DefaultSource {
+		var err error
+		if devices, err = listAVFoundation(); err != nil {
+			return nil, err
+		}
+	}
+	input, err := resolveAVFoundation(source, devices)
+	if err != nil {
+		return nil, err
+	}
+	return []string{"-f", "avfoundation", "-i", input}, nil
+}
+
+// listDevices lists the AVFoundation audio devices, the system default first
+func listDevices() ([]Device, error) {
+	found, err := listAVFoundation()
+	if err != nil {
+		return nil, err
+	}
+	devices := []Device{{Name: DefaultSource, Description: "Input device set in the sound settings"}}
+	return append(devices, found...), nil
+}
+
+// listAVFoundation runs ffmpeg to list the AVFoundation audio devices
+func listAVFoundation() ([]Device, error) {
+	path, err := exec.LookPath(ffmpegPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAudioSource, err)
+	}
+	// ffmpeg lists the devices on stderr, then fails to open the empty input
+	output, _ := exec.Command(path, "-hide_banner", "-f", "avfoundation", "-list_devices", "true", "-i", "").CombinedOutput()
+	return parseAVFoundationDevices(string(output)), nil
+}
@@ -0,0 +1,26 @@
+//go:build !windows && !darwin
+
+package audio
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+const inputFormat = "pulse"
+
+// inputArgs returns the ffmpeg arguments capturing source, a PulseAudio or
+// PipeWire source name
+func inputArgs(source string) ([]string, error) {
+	return []string{"-f", "pulse", "-i", source}, nil
+}
+
+// listDevices lists the PulseAudio sources, the default one first
+func listDevices() ([]Device, error) {
+	output, err := exec.Command("pactl", "list", "short", "sources").Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to list sources with pactl: %w", ErrAudioSource, err)
+	}
+	devices := []Device{{Name: DefaultSource, Description: "Default source of the audio server"}}
+	return append(devices, parsePactlSources(string(output))...), nil
+}
@@ -0,0 +1,41 @@
+//go:build windows
+
+package audio
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const inputFormat = "dshow"
+
+// inputArgs returns the ffmpeg arguments capturing source, a DirectShow
+// device name. The default source is the first audio device, Windows has
+// no default DirectShow device.
+func inputArgs(source string) ([]string, error) {
+	source = strings.TrimPrefix(source, "audio=")
+	if source == "" || source == DefaultSource {
+		devices, err := listDevices()
+		if err != nil {
+			return nil, err
+		}
+		if len(devices) == 0 {
+			return nil, fmt.Errorf("%w: no DirectShow audio device", ErrAudioSource)
+		}
+		source = devices[0].Name
+	}
+	// DirectShow buffers 500ms by default, too much for the wake word
+	return []string{"-f", "dshow", "-audio_buffer_size", "50", "-i", "audio=" + source}, nil
+}
+
+// listDevices lists the DirectShow audio devices
+func listDevices() ([]Device, error) {
+	path, err := exec.LookPath(ffmpegPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAudioSource, err)
+	}
+	// ffmpeg lists the devices on stderr, then fails to open the dummy input
+	output, _ := exec.Command(path, "-hide_banner", "-list_devices", "true", "-f", "dshow", "-i", "dummy").CombinedOutput()
+	return parseDShowDevices(string(output)), nil
+}
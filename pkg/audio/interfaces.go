@@ -1,3 +1,5 @@
+// Package audio captures audio through ffmpeg and converts it to the mono
+// 32-bit float samples used by the rest of the pipeline.
 package audio
 
 // AudioStream represents an audio input stream
@@ -25,6 +27,23 @@ type AudioProcessor interface {
 	// ProcessBytes converts raw audio bytes to float32 samples
 	ProcessBytes(data []byte) []float32
 
+	// AppendSamples converts raw audio bytes, appending the samples
+	AppendSamples(samples []float32, data []byte) []float32
+
 	// CalculateRMS calculates RMS level from audio samples
 	CalculateRMS(samples []float32, windowSize int) float32
 }
+
+// FileDecoder decodes audio files into float32 samples
+type FileDecoder interface {
+	// DecodeFile decodes the whole file to 16kHz mono float32 samples
+	DecodeFile(path string) ([]float32, error)
+}
+
+// StreamDecoder is implemented by decoders reading files progressively, so
+// that long recordings are never held in memory as a whole
+type StreamDecoder interface {
+	// DecodeStream returns a stream of the 16kHz mono float32 samples of
+	// the file, as raw bytes
+	DecodeStream(path string) (AudioStream, error)
+}
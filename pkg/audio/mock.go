@@ -1,8 +1,10 @@
 package audio
 
 import (
+	"encoding/binary"
 	"errors"
 	"io"
+	"math"
 )
 
 // MockAudioStream implements AudioStream for testing
@@ -87,3 +89,61 @@ func (m *MockAudioCapture) StartCapture(audioSource string) (AudioStream, error)
 func (m *MockAudioCapture) Stop() error {
 	return m.stopError
 }
+
+// MockFileDecoder implements FileDecoder for testing
+type MockFileDecoder struct {
+	files          map[string][]float32
+	defaultSamples []float32
+	decodeError    error
+}
+
+// NewMockFileDecoder creates a mock file decoder
+func NewMockFileDecoder() *MockFileDecoder {
+	return &MockFileDecoder{
+		files: make(map[string][]float32),
+	}
+}
+
+// SetFile sets the samples returned for a given path
+func (m *MockFileDecoder) SetFile(path string, samples []float32) {
+	m.files[path] = samples
+}
+
+// SetDefaultSamples sets the samples returned for paths without SetFile,
+// e.g. temporary upload files
+func (m *MockFileDecoder) SetDefaultSamples(samples []float32) {
+	m.defaultSamples = samples
+}
+
+// SetDecodeError sets an error to return on DecodeFile calls
+func (m *MockFileDecoder) SetDecodeError(err error) {
+	m.decodeError = err
+}
+
+// DecodeFile returns the configured samples for the path
+func (m *MockFileDecoder) DecodeFile(path string) ([]float32, error) {
+	if m.decodeError != nil {
+		return nil, m.decodeError
+	}
+	samples, ok := m.files[path]
+	if !ok {
+		if m.defaultSamples != nil {
+			return m.defaultSamples, nil
+		}
+		return nil, errors.New("file not found")
+	}
+	return samples, nil
+}
+
+// DecodeStream returns a stream of the configured samples for the path
+func (m *MockFileDecoder) DecodeStream(path string) (AudioStream, error) {
+	samples, err := m.DecodeFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, 0, len(samples)*BytesPerSample)
+	for _, sample := range samples {
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(sample))
+	}
+	return NewMockAudioStream(data), nil
+}
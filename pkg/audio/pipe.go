@@ -0,0 +1,182 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"os"
+	"strings"
+)
+
+// Pipe source prefixes: a named pipe (FIFO) or file, or a Unix socket
+const (
+	PipePrefix   = "pipe:"
+	SocketPrefix = "unix:"
+)
+
+// PipeFormat describes the raw PCM written to a pipe source
+type PipeFormat struct {
+	// Encoding is s16le or f32le
+	Encoding   string
+	SampleRate int
+	Channels   int
+}
+
+// DefaultPipeFormat is the format the pipeline reads, copied without conversion
+var DefaultPipeFormat = PipeFormat{Encoding: "f32le", SampleRate: 16000, Channels: 1}
+
+// Validate checks the format can be converted
+func (f PipeFormat) Validate() error {
+	if f.Encoding != "s16le" && f.Encoding != "f32le" {
+		return fmt.Errorf("unknown pipe encoding '%s', expected s16le or f32le", f.Encoding)
+	}
+	if f.SampleRate <= 0 {
+		return fmt.Errorf("invalid pipe sample rate %d", f.SampleRate)
+	}
+	if f.Channels <= 0 {
+		return fmt.Errorf("invalid pipe channel count %d", f.Channels)
+	}
+	return nil
+}
+
+// bytesPerSample returns the size of one sample of one channel
+func (f PipeFormat) bytesPerSample() int {
+	if f.Encoding == "s16le" {
+		return 2
+	}
+	return BytesPerSample
+}
+
+// IsPipeSource reports whether source is read by a PipeCapture
+func IsPipeSource(source string) bool {
+	return strings.HasPrefix(source, PipePrefix) || strings.HasPrefix(source, SocketPrefix)
+}
+
+// PipeCapture implements AudioCapture by reading PCM from a named pipe or
+// a Unix socket, e.g. fed by ffmpeg on the host of a container. The PCM is
+// converted from its format to 16kHz mono float32.
+type PipeCapture struct {
+	format PipeFormat
+}
+
+// NewPipeCapture creates a capture reading PCM in format
+func NewPipeCapture(format PipeFormat) *PipeCapture {
+	return &PipeCapture{format: format}
+}
+
+// StartCapture opens a "pipe:<path>" or "unix:<path>" source. Opening a
+// named pipe blocks until the writer opens it.
+func (c *PipeCapture) StartCapture(audioSource string) (AudioStream, error) {
+	if err := c.format.Validate(); err != nil {
+		return nil, err
+	}
+
+	var source io.ReadCloser
+	var err error
+	switch {
+	case strings.HasPrefix(audioSource, PipePrefix):
+		source, err = os.Open(strings.TrimPrefix(audioSource, PipePrefix))
+	case strings.HasPrefix(audioSource, SocketPrefix):
+		source, err = net.Dial("unix", strings.TrimPrefix(audioSource, SocketPrefix))
+	default:
+		return nil, fmt.Errorf("%w: '%s' is not a pipe: or unix: source", ErrAudioSource, audioSource)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAudioSource, err)
+	}
+
+	if c.format == DefaultPipeFormat {
+		return source, nil
+	}
+	return &pipeStream{
+		source: source,
+		format: c.format,
+		step:   float64(c.format.SampleRate) / 16000,
+	}, nil
+}
+
+// Stop is a no-op, closing the stream closes the pipe
+func (c *PipeCapture) Stop() error {
+	return nil
+}
+
+// pipeStream converts the PCM of source to 16kHz mono float32: channels
+// are averaged, then resampled by linear interpolation
+type pipeStream struct {
+	source io.ReadCloser
+	format PipeFormat
+	// partial holds the bytes of an incomplete input frame
+	partial []byte
+	// pending holds converted bytes not returned yet
+	pending []byte
+	// step is the input samples per output sample, position the next
+	// output sample in samples, samples[0] being the last sample of the
+	// previous read
+	step     float64
+	position float64
+	samples  []float32
+}
+
+// Read returns converted audio, reading the pipe until there is some
+func (s *pipeStream) Read(data []byte) (int, error) {
+	buffer := make([]byte, len(data))
+	for len(s.pending) < BytesPerSample {
+		n, err := s.source.Read(buffer)
+		if n > 0 {
+			s.convert(buffer[:n])
+		}
+		if err != nil && len(s.pending) < BytesPerSample {
+			return 0, err
+		}
+	}
+
+	n := copy(data, s.pending[:len(s.pending)/BytesPerSample*BytesPerSample])
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// Close closes the pipe
+func (s *pipeStream) Close() error {
+	return s.source.Close()
+}
+
+// convert appends the complete frames of data to pending
+func (s *pipeStream) convert(data []byte) {
+	data = append(s.partial, data...)
+	sampleSize := s.format.bytesPerSample()
+	frameSize := sampleSize * s.format.Channels
+
+	for ; len(data) >= frameSize; data = data[frameSize:] {
+		var sum float32
+		for channel := 0; channel < s.format.Channels; channel++ {
+			sum += s.decode(data[channel*sampleSize:])
+		}
+		s.samples = append(s.samples, sum/float32(s.format.Channels))
+	}
+	s.partial = append([]byte(nil), data...)
+
+	// Interpolate between samples[i] and samples[i+1]
+	for ; s.position < float64(len(s.samples)-1); s.position += s.step {
+		i := int(s.position)
+		fraction := float32(s.position - float64(i))
+		sample := s.samples[i]*(1-fraction) + s.samples[i+1]*fraction
+		s.pending = binary.LittleEndian.AppendUint32(s.pending, math.Float32bits(sample))
+	}
+
+	// Keep the last sample to interpolate with the next read
+	if len(s.samples) > 1 {
+		consumed := len(s.samples) - 1
+		s.position -= float64(consumed)
+		s.samples = append(s.samples[:0], s.samples[consumed])
+	}
+}
+
+// decode reads one sample of one channel
+func (s *pipeStream) decode(b []byte) float32 {
+	if s.format.Encoding == "s16le" {
+		return float32(int16(binary.LittleEndian.Uint16(b))) / 32768
+	}
+	return math.Float32frombits(binary.LittleEndian.Uint32(b))
+}
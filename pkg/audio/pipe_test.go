@@ -0,0 +1,111 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// readAll reads stream to the end with small reads, splitting frames
+func readAll(t *testing.T, stream AudioStream) []float32 {
+	t.Helper()
+	defer stream.Close()
+
+	var samples []float32
+	buffer := make([]byte, 12)
+	for {
+		n, err := stream.Read(buffer)
+		if n%BytesPerSample != 0 {
+			t.Fatalf("Expected whole samples, got %d bytes", n)
+		}
+		for i := 0; i < n; i += BytesPerSample {
+			samples = append(samples, math.Float32frombits(binary.LittleEndian.Uint32(buffer[i:])))
+		}
+		if errors.Is(err, io.EOF) {
+			return samples
+		}
+		if err != nil {
+			t.Fatalf("Failed to read: %v", err)
+		}
+	}
+}
+
+func TestPipeCapture_DefaultFormat(t *testing.T) {
+	var data []byte
+	for _, sample := range []float32{0.25, -0.5, 1} {
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(sample))
+	}
+	path := filepath.Join(t.TempDir(), "audio.pcm")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := NewPipeCapture(DefaultPipeFormat).StartCapture(PipePrefix + path)
+	if err != nil {
+		t.Fatalf("Failed to start capture: %v", err)
+	}
+	samples := readAll(t, stream)
+	if len(samples) != 3 || samples[1] != -0.5 {
+		t.Errorf("Expected the samples unchanged, got %v", samples)
+	}
+}
+
+func TestPipeCapture_ConvertsFromSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audio.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("Unix sockets not available: %v", err)
+	}
+	defer listener.Close()
+
+	// One second of 48kHz stereo s16le at half scale
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var data []byte
+		for i := 0; i < 48000; i++ {
+			data = binary.LittleEndian.AppendUint16(data, uint16(16384))
+			data = binary.LittleEndian.AppendUint16(data, uint16(16384))
+		}
+		conn.Write(data)
+	}()
+
+	format := PipeFormat{Encoding: "s16le", SampleRate: 48000, Channels: 2}
+	stream, err := NewPipeCapture(format).StartCapture(SocketPrefix + path)
+	if err != nil {
+		t.Fatalf("Failed to start capture: %v", err)
+	}
+	samples := readAll(t, stream)
+
+	if len(samples) < 15990 || len(samples) > 16000 {
+		t.Errorf("Expected about 16000 samples, got %d", len(samples))
+	}
+	for i, sample := range samples {
+		if sample != 0.5 {
+			t.Fatalf("Expected sample %d to be 0.5, got %v", i, sample)
+		}
+	}
+}
+
+func TestPipeCapture_Errors(t *testing.T) {
+	capture := NewPipeCapture(DefaultPipeFormat)
+	if _, err := capture.StartCapture(PipePrefix + "/nonexistent/audio.fifo"); !errors.Is(err, ErrAudioSource) {
+		t.Errorf("Expected ErrAudioSource for a missing pipe, got %v", err)
+	}
+	if _, err := capture.StartCapture("default"); !errors.Is(err, ErrAudioSource) {
+		t.Errorf("Expected ErrAudioSource for a device source, got %v", err)
+	}
+
+	bad := NewPipeCapture(PipeFormat{Encoding: "u8", SampleRate: 16000, Channels: 1})
+	if _, err := bad.StartCapture(PipePrefix + "/dev/null"); err == nil {
+		t.Error("Expected an error for an unknown encoding")
+	}
+}
@@ -13,17 +13,18 @@ func NewProcessor() *Processor {
 	return &Processor{}
 }
 
-// ProcessBytes converts raw audio bytes to float32 samples
+// ProcessBytes converts raw audio bytes to float32 samples. Trailing bytes
+// of an incomplete frame are ignored, streams go through a FrameDecoder.
 func (p *Processor) ProcessBytes(data []byte) []float32 {
-	samples := make([]float32, 0, len(data)/4)
+	return p.AppendSamples(make([]float32, 0, len(data)/BytesPerSample), data)
+}
 
-	for i := 0; i < len(data); i += 4 {
-		if i+4 <= len(data) {
-			sample := p.float32FromBytes(data[i : i+4])
-			samples = append(samples, sample)
-		}
+// AppendSamples converts raw audio bytes and appends the samples to
+// samples, so callers reusing a buffer convert without allocating
+func (p *Processor) AppendSamples(samples []float32, data []byte) []float32 {
+	for i := 0; i+BytesPerSample <= len(data); i += BytesPerSample {
+		samples = append(samples, p.float32FromBytes(data[i:i+BytesPerSample]))
 	}
-
 	return samples
 }
 
@@ -0,0 +1,188 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"unsafe"
+)
+
+func TestNewProcessor(t *testing.T) {
+	processor := NewProcessor()
+	if processor == nil {
+		t.Fatal("Expected processor to be created")
+	}
+}
+
+func TestProcessor_ProcessBytes(t *testing.T) {
+	processor := NewProcessor()
+
+	// Create test data: 2 float32 samples (8 bytes)
+	sample1 := float32(0.5)
+	sample2 := float32(-0.3)
+
+	data := make([]byte, 8)
+	// Convert float32 to bytes (little-endian)
+	bits1 := *(*uint32)(unsafe.Pointer(&sample1))
+	bits2 := *(*uint32)(unsafe.Pointer(&sample2))
+
+	data[0] = byte(bits1)
+	data[1] = byte(bits1 >> 8)
+	data[2] = byte(bits1 >> 16)
+	data[3] = byte(bits1 >> 24)
+
+	data[4] = byte(bits2)
+	data[5] = byte(bits2 >> 8)
+	data[6] = byte(bits2 >> 16)
+	data[7] = byte(bits2 >> 24)
+
+	samples := processor.ProcessBytes(data)
+
+	if len(samples) != 2 {
+		t.Errorf("Expected 2 samples, got %d", len(samples))
+	}
+
+	if abs(samples[0]-sample1) > 0.0001 {
+		t.Errorf("Expected sample1 %.6f, got %.6f", sample1, samples[0])
+	}
+
+	if abs(samples[1]-sample2) > 0.0001 {
+		t.Errorf("Expected sample2 %.6f, got %.6f", sample2, samples[1])
+	}
+}
+
+func TestProcessor_CalculateRMS_EmptySlice(t *testing.T) {
+	processor := NewProcessor()
+
+	samples := []float32{}
+	rms := processor.CalculateRMS(samples, 10)
+
+	if rms != 0.0 {
+		t.Errorf("Expected RMS 0.0 for empty slice, got %.6f", rms)
+	}
+}
+
+func TestFrameDecoder_SplitFrames(t *testing.T) {
+	expected := make([]float32, 64)
+	data := make([]byte, 0, len(expected)*BytesPerSample)
+	for i := range expected {
+		expected[i] = float32(i) / 100
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(expected[i]))
+	}
+
+	// Read boundaries falling anywhere inside a frame
+	for _, sizes := range [][]int{{1}, {3}, {5}, {7}, {1, 2, 3, 4, 5, 6, 7}, {6, 1, 9}, {255}} {
+		decoder := NewFrameDecoder(NewProcessor())
+		var samples []float32
+		for i, chunk := 0, 0; i < len(data); chunk++ {
+			end := min(i+sizes[chunk%len(sizes)], len(data))
+			samples = append(samples, decoder.Decode(data[i:end])...)
+			i = end
+		}
+
+		if len(samples) != len(expected) {
+			t.Fatalf("Chunk sizes %v: expected %d samples, got %d", sizes, len(expected), len(samples))
+		}
+		for i := range expected {
+			if samples[i] != expected[i] {
+				t.Fatalf("Chunk sizes %v: expected sample %d to be %.2f, got %f", sizes, i, expected[i], samples[i])
+			}
+		}
+		if decoder.Pending() != 0 {
+			t.Errorf("Chunk sizes %v: expected no pending bytes, got %d", sizes, decoder.Pending())
+		}
+	}
+}
+
+func TestFrameDecoder_KeepsIncompleteFrame(t *testing.T) {
+	decoder := NewFrameDecoder(NewProcessor())
+	data := binary.LittleEndian.AppendUint32(nil, math.Float32bits(0.5))
+
+	if samples := decoder.Decode(data[:2]); len(samples) != 0 {
+		t.Errorf("Expected no sample from half a frame, got %v", samples)
+	}
+	if decoder.Pending() != 2 {
+		t.Errorf("Expected 2 pending bytes, got %d", decoder.Pending())
+	}
+	if samples := decoder.Decode(nil); len(samples) != 0 || decoder.Pending() != 2 {
+		t.Errorf("Expected an empty read to change nothing, got %v", samples)
+	}
+	if samples := decoder.Decode(data[2:]); len(samples) != 1 || samples[0] != 0.5 {
+		t.Errorf("Expected the completed frame [0.5], got %v", samples)
+	}
+}
+
+func BenchmarkProcessBytes(b *testing.B) {
+	data := make([]byte, 4096)
+	processor := NewProcessor()
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for b.Loop() {
+		processor.ProcessBytes(data)
+	}
+}
+
+func FuzzProcessBytes(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0, 0, 0, 0x3f})
+	f.Add([]byte{0xff, 0xff, 0xc0, 0x7f, 1, 2}) // NaN and a partial frame
+	f.Fuzz(func(t *testing.T, data []byte) {
+		samples := NewProcessor().ProcessBytes(data)
+		if len(samples) != len(data)/BytesPerSample {
+			t.Fatalf("Expected %d samples from %d bytes, got %d", len(data)/BytesPerSample, len(data), len(samples))
+		}
+		for i, sample := range samples {
+			if bits := binary.LittleEndian.Uint32(data[i*BytesPerSample:]); math.Float32bits(sample) != bits {
+				t.Fatalf("Sample %d: expected bits %08x, got %08x", i, bits, math.Float32bits(sample))
+			}
+		}
+	})
+}
+
+func FuzzFrameDecoder(f *testing.F) {
+	f.Add([]byte{0, 0, 0, 0x3f, 0, 0, 0x80, 0xbf}, uint8(1), uint8(3))
+	f.Add([]byte{1, 2, 3, 4, 5, 6, 7}, uint8(0), uint8(7))
+	f.Fuzz(func(t *testing.T, data []byte, first, second uint8) {
+		// Three reads split at arbitrary points
+		a := min(int(first), len(data))
+		b := min(a+int(second), len(data))
+		decoder := NewFrameDecoder(NewProcessor())
+		var samples []float32
+		for _, chunk := range [][]byte{data[:a], data[a:b], data[b:]} {
+			samples = append(samples, decoder.Decode(chunk)...)
+		}
+
+		expected := NewProcessor().ProcessBytes(data)
+		if len(samples) != len(expected) {
+			t.Fatalf("Expected %d samples, got %d", len(expected), len(samples))
+		}
+		for i := range expected {
+			if math.Float32bits(samples[i]) != math.Float32bits(expected[i]) {
+				t.Fatalf("Sample %d: expected %v, got %v", i, expected[i], samples[i])
+			}
+		}
+		if decoder.Pending() != len(data)%BytesPerSample {
+			t.Errorf("Expected %d pending bytes, got %d", len(data)%BytesPerSample, decoder.Pending())
+		}
+	})
+}
+
+func BenchmarkFrameDecoder(b *testing.B) {
+	// One read of the live pipeline, ending in the middle of a frame
+	data := make([]byte, 4096+2)
+	decoder := NewFrameDecoder(NewProcessor())
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for b.Loop() {
+		decoder.Decode(data)
+	}
+}
+
+func abs(x float32) float32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,198 @@
+package audio
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// watchdogReadSize is the size of the reads of the watched stream
+const watchdogReadSize = 4096
+
+// WatchdogCapture restarts the capture of a source that stops producing
+// audio while ffmpeg keeps running, e.g. when a Bluetooth microphone drops:
+// the watched stream is closed after a timeout without data and captured
+// again, until it delivers audio or the stream is closed.
+type WatchdogCapture struct {
+	capture AudioCapture
+	timeout time.Duration
+	onStall func(err error)
+	stalls  atomic.Int64
+}
+
+// NewWatchdogCapture watches the streams of capture. onStall is called on
+// every stall, with the error of the restart if it failed.
+func NewWatchdogCapture(capture AudioCapture, timeout time.Duration, onStall func(err error)) *WatchdogCapture {
+	return &WatchdogCapture{capture: capture, timeout: timeout, onStall: onStall}
+}
+
+// StartCapture starts capturing audioSource under the watchdog
+func (w *WatchdogCapture) StartCapture(audioSource string) (AudioStream, error) {
+	stream, err := w.capture.StartCapture(audioSource)
+	if err != nil {
+		return nil, err
+	}
+	s := &watchdogStream{watchdog: w, source: audioSource, closed: make(chan struct{})}
+	s.watch(stream)
+	return s, nil
+}
+
+// Stop stops the watched capture
+func (w *WatchdogCapture) Stop() error {
+	return w.capture.Stop()
+}
+
+// Stalls returns the number of stalls detected
+func (w *WatchdogCapture) Stalls() int {
+	return int(w.stalls.Load())
+}
+
+// readResult is a read of the watched stream
+type readResult struct {
+	data []byte
+	err  error
+}
+
+// watchdogStream reads the watched stream in a goroutine, so that a read
+// blocked forever can be given up
+type watchdogStream struct {
+	watchdog *WatchdogCapture
+	source   string
+	closed   chan struct{}
+
+	// Current stream and the reads of its goroutine, replaced on restart
+	stream AudioStream
+	reads  chan readResult
+	done   chan struct{}
+	mutex  sync.Mutex
+
+	pending []byte
+	err     error // Error after the pending data
+}
+
+// watch starts reading stream, false when the watchdog stream was closed
+func (s *watchdogStream) watch(stream AudioStream) bool {
+	reads := make(chan readResult)
+	done := make(chan struct{})
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	select {
+	case <-s.closed:
+		stream.Close()
+		return false
+	default:
+	}
+	s.stream, s.reads, s.done = stream, reads, done
+
+	go func() {
+		for {
+			buffer := make([]byte, watchdogReadSize)
+			n, err := stream.Read(buffer)
+			select {
+			case reads <- readResult{data: buffer[:n], err: err}:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return true
+}
+
+// Read returns the data of the watched stream, restarting the capture
+// whenever nothing was read for the timeout
+func (s *watchdogStream) Read(data []byte) (int, error) {
+	if len(s.pending) > 0 {
+		n := copy(data, s.pending)
+		s.pending = s.pending[n:]
+		return n, nil
+	}
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	timer := time.NewTimer(s.watchdog.timeout)
+	defer timer.Stop()
+	for {
+		s.mutex.Lock()
+		reads := s.reads
+		s.mutex.Unlock()
+
+		select {
+		case result := <-reads:
+			n := copy(data, result.data)
+			s.pending = result.data[n:]
+			if n == 0 {
+				return 0, result.err
+			}
+			s.err = result.err
+			return n, nil
+		case <-timer.C:
+			s.restart()
+			timer.Reset(s.watchdog.timeout)
+		case <-s.closed:
+			return 0, io.EOF
+		}
+	}
+}
+
+// restart replaces the stalled stream by a new capture of the source
+func (s *watchdogStream) restart() {
+	s.watchdog.stalls.Add(1)
+	s.mutex.Lock()
+	s.stop()
+	s.mutex.Unlock()
+
+	stream, err := s.watchdog.capture.StartCapture(s.source)
+	if err != nil {
+		// Nothing to read until the next attempt
+		stream = &stalledStream{closed: make(chan struct{})}
+	}
+	if s.watchdog.onStall != nil {
+		s.watchdog.onStall(err)
+	}
+	s.watch(stream)
+}
+
+// stop closes the current stream and ends its goroutine, with the mutex held
+func (s *watchdogStream) stop() error {
+	if s.done == nil {
+		return nil
+	}
+	close(s.done)
+	s.done = nil
+	return s.stream.Close()
+}
+
+// Close closes the stream and the watched one
+func (s *watchdogStream) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	select {
+	case <-s.closed:
+		return nil
+	default:
+		close(s.closed)
+	}
+	return s.stop()
+}
+
+// stalledStream stands for a capture that failed to restart, its reads
+// block until it is closed
+type stalledStream struct {
+	closed chan struct{}
+}
+
+func (s *stalledStream) Read(data []byte) (int, error) {
+	<-s.closed
+	return 0, io.EOF
+}
+
+func (s *stalledStream) Close() error {
+	close(s.closed)
+	return nil
+}
@@ -0,0 +1,114 @@
+package audio
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// hangingStream blocks its reads until closed, like ffmpeg reading a
+// microphone that dropped
+type hangingStream struct {
+	closed chan struct{}
+}
+
+func (s *hangingStream) Read(data []byte) (int, error) {
+	<-s.closed
+	return 0, errors.New("killed")
+}
+
+func (s *hangingStream) Close() error {
+	close(s.closed)
+	return nil
+}
+
+// sequenceCapture returns its streams in turn, then fails
+type sequenceCapture struct {
+	streams []AudioStream
+	mutex   sync.Mutex
+}
+
+func (c *sequenceCapture) StartCapture(audioSource string) (AudioStream, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.streams) == 0 {
+		return nil, ErrAudioSource
+	}
+	stream := c.streams[0]
+	c.streams = c.streams[1:]
+	return stream, nil
+}
+
+func (c *sequenceCapture) Stop() error {
+	return nil
+}
+
+func TestWatchdogCapture_RestartsStalledCapture(t *testing.T) {
+	hanging := &hangingStream{closed: make(chan struct{})}
+	capture := &sequenceCapture{streams: []AudioStream{hanging, NewMockAudioStream([]byte("audio"))}}
+	var stalls []error
+	watchdog := NewWatchdogCapture(capture, 20*time.Millisecond, func(err error) {
+		stalls = append(stalls, err)
+	})
+
+	stream, err := watchdog.StartCapture("default")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer stream.Close()
+
+	data, err := io.ReadAll(stream)
+	if err != nil || string(data) != "audio" {
+		t.Errorf("Expected the audio of the restarted capture, got %q (%v)", data, err)
+	}
+	if watchdog.Stalls() != 1 || len(stalls) != 1 || stalls[0] != nil {
+		t.Errorf("Expected one successful restart, got %d stalls (%v)", watchdog.Stalls(), stalls)
+	}
+	select {
+	case <-hanging.closed:
+	default:
+		t.Error("Expected the stalled stream to be closed")
+	}
+}
+
+func TestWatchdogCapture_KeepsRetrying(t *testing.T) {
+	capture := &sequenceCapture{streams: []AudioStream{&hangingStream{closed: make(chan struct{})}}}
+	errs := make(chan error, 10)
+	watchdog := NewWatchdogCapture(capture, 10*time.Millisecond, func(err error) {
+		errs <- err
+	})
+
+	stream, err := watchdog.StartCapture("default")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := stream.Read(make([]byte, 16))
+		done <- err
+	}()
+
+	for range 2 {
+		select {
+		case err := <-errs:
+			if !errors.Is(err, ErrAudioSource) {
+				t.Errorf("Expected the failed restart to be reported, got: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected the watchdog to retry")
+		}
+	}
+
+	// Closing ends the blocked read
+	stream.Close()
+	select {
+	case err := <-done:
+		if !errors.Is(err, io.EOF) {
+			t.Errorf("Expected io.EOF after close, got: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Close to end the read")
+	}
+}
@@ -0,0 +1,89 @@
+package output
+
+import (
+	"errors"
+	"sync"
+)
+
+// subscription is an emitter and the event types it receives, all when empty
+type subscription struct {
+	id      int
+	emitter Emitter
+	types   map[EventType]bool
+}
+
+// wants reports whether the subscriber receives events of type t
+func (s subscription) wants(t EventType) bool {
+	return len(s.types) == 0 || s.types[t]
+}
+
+// Bus delivers pipeline events to its subscribers, synchronously and in
+// subscription order. It is an Emitter itself.
+type Bus struct {
+	subscriptions []subscription
+	nextID        int
+	mutex         sync.RWMutex
+}
+
+// NewBus creates an event bus without subscribers
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe sends the events of the given types, or all events if none is
+// given, to emitter until the returned function is called
+func (b *Bus) Subscribe(emitter Emitter, types ...EventType) func() {
+	sub := subscription{emitter: emitter, types: make(map[EventType]bool, len(types))}
+	for _, t := range types {
+		sub.types[t] = true
+	}
+
+	b.mutex.Lock()
+	sub.id = b.nextID
+	b.nextID++
+	b.subscriptions = append(b.subscriptions, sub)
+	b.mutex.Unlock()
+
+	return func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		for i, s := range b.subscriptions {
+			if s.id == sub.id {
+				b.subscriptions = append(b.subscriptions[:i:i], b.subscriptions[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Wants reports whether any subscriber receives events of type t, so
+// costly events can be skipped when nobody listens
+func (b *Bus) Wants(t EventType) bool {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	for _, s := range b.subscriptions {
+		if s.wants(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Emit delivers an event to every interested subscriber, returning their
+// errors joined
+func (b *Bus) Emit(event Event) error {
+	b.mutex.RLock()
+	subscriptions := b.subscriptions
+	b.mutex.RUnlock()
+
+	var errs []error
+	for _, s := range subscriptions {
+		if !s.wants(event.Type) {
+			continue
+		}
+		if err := s.emitter.Emit(event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
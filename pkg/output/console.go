@@ -0,0 +1,98 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConsoleEvents are the event types shown by the console writer
+var ConsoleEvents = []EventType{EventPartial, EventTranscript, EventTranslation, EventWakeWord, EventAIResponse, EventTimer, EventAIStatus}
+
+// ConsoleWriter prints events as emoji-decorated lines for humans
+type ConsoleWriter struct {
+	w          io.Writer
+	timestamps *Timestamps
+}
+
+// NewConsoleWriter creates a console writer printing to w
+func NewConsoleWriter(w io.Writer) *ConsoleWriter {
+	return &ConsoleWriter{w: w}
+}
+
+// SetTimestamps sets the format of the line timestamps, 15:04:05 local
+// time by default. Must be called before the first event.
+func (c *ConsoleWriter) SetTimestamps(timestamps *Timestamps) {
+	c.timestamps = timestamps
+}
+
+// Emit prints an event, other types than ConsoleEvents are ignored
+func (c *ConsoleWriter) Emit(event Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	clock := c.timestamps.Event(event)
+
+	var err error
+	switch event.Type {
+	case EventTranscript:
+		text := strings.TrimSpace(event.Text)
+		if event.Speaker != "" {
+			text = event.Speaker + ": " + text
+		}
+		_, err = fmt.Fprintf(c.w, "[%s] 🎤 %s\n", clock, text)
+	case EventPartial:
+		_, err = fmt.Fprintf(c.w, "[%s] ⏳ %s…\n", clock, strings.TrimSpace(event.Text))
+	case EventTranslation:
+		_, err = fmt.Fprintf(c.w, "[%s] 🌍 %s: %s\n", clock, event.Language, strings.TrimSpace(event.Text))
+	case EventWakeWord:
+		if event.State != "" && event.State != "voice" {
+			_, err = fmt.Fprintf(c.w, "🎯 Listening activated (%s)\n", event.State)
+			break
+		}
+		_, err = fmt.Fprintf(c.w, "🎯 Wake word '%s' detected! Activating listening...\n", event.Text)
+	case EventAIResponse:
+		_, err = fmt.Fprintf(c.w, "[%s] 🤖 %s\n", clock, event.Text)
+	case EventTimer:
+		_, err = fmt.Fprintf(c.w, "[%s] ⏰ %s\n", clock, event.Text)
+	case EventAIStatus:
+		if event.State == "available" {
+			_, err = fmt.Fprintf(c.w, "[%s] ✅ AI service back online\n", clock)
+			break
+		}
+		_, err = fmt.Fprintf(c.w, "[%s] ⚠️  AI service unavailable, answers paused until it comes back\n", clock)
+	}
+	return err
+}
+
+// PlainWriter prints transcripts and AI answers one per line without
+// decoration, for shell pipelines
+type PlainWriter struct {
+	w     io.Writer
+	mutex sync.Mutex
+}
+
+// NewPlainWriter creates a plain writer printing to w
+func NewPlainWriter(w io.Writer) *PlainWriter {
+	return &PlainWriter{w: w}
+}
+
+// Emit prints transcripts, translations and AI answers, other events are
+// ignored
+func (p *PlainWriter) Emit(event Event) error {
+	if event.Type != EventTranscript && event.Type != EventTranslation && event.Type != EventAIResponse {
+		return nil
+	}
+	// One line per event even for multi-line answers
+	text := strings.Join(strings.Fields(event.Text), " ")
+	if text == "" {
+		return nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	_, err := fmt.Fprintln(p.w, text)
+	return err
+}
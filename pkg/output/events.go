@@ -0,0 +1,114 @@
+package output
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Format selects how the live session reports its results
+type Format string
+
+const (
+	// FormatText prints emoji-decorated lines for humans
+	FormatText Format = "text"
+	// FormatJSON prints one JSON object per event (JSON Lines)
+	FormatJSON Format = "json"
+	// FormatPlain prints only transcripts and AI answers, one per line
+	FormatPlain Format = "plain"
+)
+
+// ParseFormat validates an output format name
+func ParseFormat(name string) (Format, error) {
+	switch Format(name) {
+	case FormatText, FormatJSON, FormatPlain:
+		return Format(name), nil
+	default:
+		return "", fmt.Errorf("unknown output format '%s' (expected text, json or plain)", name)
+	}
+}
+
+// EventType identifies a pipeline event
+type EventType string
+
+const (
+	EventTranscript  EventType = "transcript"
+	EventPartial     EventType = "partial"     // Segment of an utterance still being transcribed, before its transcript
+	EventTranslation EventType = "translation" // Translation of the previous transcript into Language
+	EventAIResponse  EventType = "ai_response"
+	EventAIToken     EventType = "ai_token" // Streamed piece of an AI response
+	EventVAD         EventType = "vad"      // Voice activity changed, see State
+	EventState       EventType = "state"    // Assistant state changed, see assistant.State
+	EventWakeWord    EventType = "wake_word"
+	EventTimer       EventType = "timer"     // A timer or reminder fired
+	EventOverload    EventType = "overload"  // Transcription lagging behind, see State
+	EventAIStatus    EventType = "ai_status" // AI backend went away or came back, see State
+	EventError       EventType = "error"
+)
+
+// Event is a single machine-readable pipeline event
+type Event struct {
+	Type       EventType `json:"type"`
+	Time       time.Time `json:"time"`
+	Timestamp  string    `json:"timestamp,omitempty"` // Time as printed on the console, set by JSONWriter.SetTimestamps
+	SessionID  string    `json:"session_id"`
+	Text       string    `json:"text,omitempty"`
+	Language   string    `json:"language,omitempty"`
+	Speaker    string    `json:"speaker,omitempty"`
+	Start      float64   `json:"start,omitempty"` // Seconds since the stream started
+	End        float64   `json:"end,omitempty"`
+	Confidence float64   `json:"confidence,omitempty"`
+	State      string    `json:"state,omitempty"` // speech/silence for vad, the assistant state for state, dropped/merged/fallback/recovered for overload, the activation source for wake_word, available/unavailable for ai_status
+	Error      string    `json:"error,omitempty"`
+}
+
+// JSONWriter writes events as JSON Lines
+type JSONWriter struct {
+	encoder    *json.Encoder
+	sessionID  string
+	timestamps *Timestamps
+	mutex      sync.Mutex
+}
+
+// NewJSONWriter creates a writer tagging every event with sessionID
+func NewJSONWriter(w io.Writer, sessionID string) *JSONWriter {
+	return &JSONWriter{
+		encoder:   json.NewEncoder(w),
+		sessionID: sessionID,
+	}
+}
+
+// SetTimestamps converts the event times to the time zone of timestamps
+// and adds their formatted timestamp. Must be called before the first event.
+func (j *JSONWriter) SetTimestamps(timestamps *Timestamps) {
+	j.timestamps = timestamps
+}
+
+// Emit writes an event, filling in the time and session ID
+func (j *JSONWriter) Emit(event Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	event.SessionID = j.sessionID
+	if j.timestamps != nil {
+		event.Time = j.timestamps.In(event.Time)
+		event.Timestamp = j.timestamps.Event(event)
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.encoder.Encode(event)
+}
+
+// NewSessionID returns a random identifier for a live session
+func NewSessionID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
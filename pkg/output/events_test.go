@@ -0,0 +1,213 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat("json"); err != nil || format != FormatJSON {
+		t.Errorf("Expected json format, got %q (%v)", format, err)
+	}
+	if format, err := ParseFormat("plain"); err != nil || format != FormatPlain {
+		t.Errorf("Expected plain format, got %q (%v)", format, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestJSONWriter_EmitsOneObjectPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewJSONWriter(&buf, "abc123")
+
+	writer.Emit(Event{Type: EventWakeWord, Text: "ok nrz"})
+	writer.Emit(Event{Type: EventTranscript, Text: "Bonjour", Language: "fr", Start: 1.5, End: 2.5, Confidence: 0.9})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %q", len(lines), buf.String())
+	}
+
+	var event Event
+	if err := json.Unmarshal([]byte(lines[1]), &event); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	if event.Type != EventTranscript || event.SessionID != "abc123" || event.Text != "Bonjour" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+	if event.Time.IsZero() {
+		t.Error("Expected event time to be set")
+	}
+	if strings.Contains(lines[0], "confidence") {
+		t.Errorf("Expected empty fields to be omitted, got %s", lines[0])
+	}
+}
+
+func TestNewSessionID(t *testing.T) {
+	first, second := NewSessionID(), NewSessionID()
+	if len(first) != 16 || first == second {
+		t.Errorf("Expected distinct 16-char session IDs, got %q and %q", first, second)
+	}
+}
+
+// recorder is an emitter keeping the events it receives
+type recorder struct {
+	events []Event
+	err    error
+}
+
+func (r *recorder) Emit(event Event) error {
+	r.events = append(r.events, event)
+	return r.err
+}
+
+func TestBus_FiltersByType(t *testing.T) {
+	bus := NewBus()
+	all, transcripts := &recorder{}, &recorder{}
+	bus.Subscribe(all)
+	unsubscribe := bus.Subscribe(transcripts, EventTranscript)
+
+	bus.Emit(Event{Type: EventVAD, State: "speech"})
+	bus.Emit(Event{Type: EventTranscript, Text: "Bonjour"})
+	unsubscribe()
+	bus.Emit(Event{Type: EventTranscript, Text: "Salut"})
+
+	if len(all.events) != 3 {
+		t.Errorf("Expected 3 events for the catch-all subscriber, got %d", len(all.events))
+	}
+	if len(transcripts.events) != 1 || transcripts.events[0].Text != "Bonjour" {
+		t.Errorf("Expected only the first transcript, got %+v", transcripts.events)
+	}
+}
+
+func TestBus_Wants(t *testing.T) {
+	bus := NewBus()
+	if bus.Wants(EventAIToken) {
+		t.Error("Expected an empty bus to want nothing")
+	}
+
+	bus.Subscribe(&recorder{}, ConsoleEvents...)
+	if bus.Wants(EventAIToken) || !bus.Wants(EventAIResponse) {
+		t.Error("Expected only the subscribed types to be wanted")
+	}
+}
+
+func TestBus_JoinsErrors(t *testing.T) {
+	bus := NewBus()
+	failing, next := &recorder{err: errors.New("broken pipe")}, &recorder{}
+	bus.Subscribe(failing)
+	bus.Subscribe(next)
+
+	if err := bus.Emit(Event{Type: EventError}); err == nil {
+		t.Error("Expected the subscriber error")
+	}
+	if len(next.events) != 1 {
+		t.Error("Expected delivery to continue after a failing subscriber")
+	}
+}
+
+func TestConsoleWriter(t *testing.T) {
+	var buf bytes.Buffer
+	console := NewConsoleWriter(&buf)
+	at := time.Date(2026, 3, 1, 15, 4, 12, 0, time.Local)
+
+	console.Emit(Event{Type: EventPartial, Time: at, Text: " Bonjour"})
+	console.Emit(Event{Type: EventTranscript, Time: at, Text: " Bonjour", Speaker: "Speaker 1"})
+	console.Emit(Event{Type: EventTranslation, Time: at, Text: "Hello ", Language: "en"})
+	console.Emit(Event{Type: EventAIResponse, Time: at, Text: "Salut"})
+	console.Emit(Event{Type: EventVAD, Time: at, State: "speech"})
+
+	expected := "[15:04:12] ⏳ Bonjour…\n[15:04:12] 🎤 Speaker 1: Bonjour\n[15:04:12] 🌍 en: Hello\n[15:04:12] 🤖 Salut\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestPlainWriter(t *testing.T) {
+	var buf bytes.Buffer
+	plain := NewPlainWriter(&buf)
+
+	plain.Emit(Event{Type: EventTranscript, Text: " Bonjour le monde ", Speaker: "Speaker 1"})
+	plain.Emit(Event{Type: EventWakeWord, Text: "Jack"})
+	plain.Emit(Event{Type: EventAIResponse, Text: "Salut !\nÇa va ?"})
+	plain.Emit(Event{Type: EventTranscript, Text: "  "})
+
+	expected := "Bonjour le monde\nSalut ! Ça va ?\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestTimestamps(t *testing.T) {
+	at := time.Date(2026, 3, 1, 14, 4, 12, 0, time.UTC)
+	tests := []struct {
+		format, timezone, expected string
+	}{
+		{"", "UTC", "14:04:12"},
+		{"iso8601", "Europe/Paris", "2026-03-01T15:04:12+01:00"},
+		{"2006-01-02 15:04", "America/New_York", "2026-03-01 09:04"},
+	}
+	for _, tt := range tests {
+		timestamps, err := NewTimestamps(tt.format, tt.timezone)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		if got := timestamps.Format(at); got != tt.expected {
+			t.Errorf("%s in %s: expected %s, got %s", tt.format, tt.timezone, tt.expected, got)
+		}
+	}
+
+	if _, err := NewTimestamps("hh:mm", ""); err == nil {
+		t.Error("Expected an error for a layout without time elements")
+	}
+	if _, err := NewTimestamps("", "Mars/Olympus"); err == nil {
+		t.Error("Expected an error for an unknown timezone")
+	}
+}
+
+func TestTimestamps_Elapsed(t *testing.T) {
+	timestamps, err := NewTimestamps("elapsed", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	origin := time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC)
+	timestamps.SetOrigin(origin)
+
+	if got := timestamps.Format(origin.Add(time.Hour + 2*time.Minute + 3*time.Second)); got != "01:02:03" {
+		t.Errorf("Expected 01:02:03, got %s", got)
+	}
+	// Transcripts are stamped with their stream position, not when they were transcribed
+	transcript := Event{Type: EventTranscript, Time: origin.Add(time.Minute), Start: 42.5, End: 44}
+	if got := timestamps.Event(transcript); got != "00:00:42" {
+		t.Errorf("Expected the stream position 00:00:42, got %s", got)
+	}
+}
+
+func TestWriters_Timestamps(t *testing.T) {
+	timestamps, err := NewTimestamps("iso8601", "Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 3, 1, 14, 4, 12, 0, time.UTC)
+
+	var console bytes.Buffer
+	writer := NewConsoleWriter(&console)
+	writer.SetTimestamps(timestamps)
+	writer.Emit(Event{Type: EventAIResponse, Time: at, Text: "Salut"})
+	if expected := "[2026-03-01T15:04:12+01:00] 🤖 Salut\n"; console.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, console.String())
+	}
+
+	var buf bytes.Buffer
+	events := NewJSONWriter(&buf, "abc123")
+	events.SetTimestamps(timestamps)
+	events.Emit(Event{Type: EventAIResponse, Time: at, Text: "Salut"})
+	if line := buf.String(); !strings.Contains(line, `"time":"2026-03-01T15:04:12+01:00"`) || !strings.Contains(line, `"timestamp":"2026-03-01T15:04:12+01:00"`) {
+		t.Errorf("Expected the time in the timezone and the timestamp, got %s", line)
+	}
+}
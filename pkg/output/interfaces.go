@@ -0,0 +1,9 @@
+// Package output publishes pipeline events to the console, JSON streams and
+// any other Emitter subscribed to a Bus.
+package output
+
+// Emitter receives pipeline events
+type Emitter interface {
+	// Emit handles a single event
+	Emit(event Event) error
+}
@@ -0,0 +1,148 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nerzhul/nrz-ai/internal/privacy"
+)
+
+// SinkType names an output of the live session
+type SinkType string
+
+const (
+	// SinkConsole prints emoji-decorated lines on stdout, on stderr next to SinkJSON
+	SinkConsole SinkType = "console"
+	// SinkJSON prints JSON Lines events on stdout
+	SinkJSON SinkType = "json"
+	// SinkPlain prints transcripts and AI answers on stdout
+	SinkPlain SinkType = "plain"
+	// SinkJSONLFile appends JSON Lines events to a file
+	SinkJSONLFile SinkType = "jsonl_file"
+	// SinkTextFile appends the console lines to a file
+	SinkTextFile SinkType = "text_file"
+)
+
+// Sink is an output receiving the pipeline events
+type Sink struct {
+	Type SinkType
+	Path string // File of the file sinks
+}
+
+// defaultSinkFiles are the file names of the file sinks given without a path
+var defaultSinkFiles = map[SinkType]string{
+	SinkJSONLFile: "events.jsonl",
+	SinkTextFile:  "events.log",
+}
+
+// ParseSinks parses sink specs, a type optionally followed by ":path" for
+// the file sinks, which are written in dir otherwise. Only one sink writes
+// events on stdout, and the console cannot share it with plain lines.
+func ParseSinks(specs []string, dir string) ([]Sink, error) {
+	var sinks []Sink
+	seen := make(map[Sink]bool, len(specs))
+	for _, spec := range specs {
+		name, path, _ := strings.Cut(strings.TrimSpace(spec), ":")
+		sink := Sink{Type: SinkType(strings.ToLower(name)), Path: path}
+		switch sink.Type {
+		case SinkConsole, SinkJSON, SinkPlain:
+			if path != "" {
+				return nil, fmt.Errorf("output '%s' takes no path", sink.Type)
+			}
+		case SinkJSONLFile, SinkTextFile:
+			if sink.Path == "" {
+				sink.Path = filepath.Join(dir, defaultSinkFiles[sink.Type])
+			}
+		default:
+			return nil, fmt.Errorf("unknown output '%s' (expected console, json, plain, jsonl_file or text_file)", name)
+		}
+		if !seen[sink] {
+			seen[sink] = true
+			sinks = append(sinks, sink)
+		}
+	}
+
+	if HasSink(sinks, SinkJSON) && HasSink(sinks, SinkPlain) {
+		return nil, errors.New("outputs 'json' and 'plain' both write on stdout")
+	}
+	if HasSink(sinks, SinkConsole) && HasSink(sinks, SinkPlain) {
+		return nil, errors.New("outputs 'console' and 'plain' both write on stdout")
+	}
+	return sinks, nil
+}
+
+// FormatSinks returns the sinks of an output format
+func FormatSinks(format Format) []Sink {
+	switch format {
+	case FormatJSON:
+		return []Sink{{Type: SinkJSON}}
+	case FormatPlain:
+		return []Sink{{Type: SinkPlain}}
+	default:
+		return []Sink{{Type: SinkConsole}}
+	}
+}
+
+// HasSink reports whether sinks holds a sink of type t
+func HasSink(sinks []Sink, t SinkType) bool {
+	for _, sink := range sinks {
+		if sink.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// FileSink appends the events to a file
+type FileSink struct {
+	Emitter
+	file *os.File
+}
+
+// IsFileSink reports whether sinks of type t write to disk
+func IsFileSink(t SinkType) bool {
+	return t == SinkJSONLFile || t == SinkTextFile
+}
+
+// OpenFileSink opens the file of a file sink for appending, creating its
+// directory. JSON events are tagged with sessionID. It fails in privacy mode.
+func OpenFileSink(sink Sink, sessionID string, timestamps *Timestamps) (*FileSink, error) {
+	if !IsFileSink(sink.Type) {
+		return nil, fmt.Errorf("output '%s' is not a file", sink.Type)
+	}
+	if err := privacy.Check("output files"); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(sink.Path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create the directory of %s: %w", sink.Path, err)
+	}
+	file, err := os.OpenFile(sink.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open output %s: %w", sink.Path, err)
+	}
+
+	f := &FileSink{file: file}
+	if sink.Type == SinkJSONLFile {
+		events := NewJSONWriter(file, sessionID)
+		events.SetTimestamps(timestamps)
+		f.Emitter = events
+	} else {
+		lines := NewConsoleWriter(file)
+		lines.SetTimestamps(timestamps)
+		f.Emitter = lines
+	}
+	return f, nil
+}
+
+// Path returns the path of the file
+func (f *FileSink) Path() string {
+	return f.file.Name()
+}
+
+// Close closes the file
+func (f *FileSink) Close() error {
+	return f.file.Close()
+}
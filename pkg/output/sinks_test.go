@@ -0,0 +1,89 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSinks(t *testing.T) {
+	sinks, err := ParseSinks([]string{"console", "JSONL_FILE", "text_file:/tmp/nrz.log", "console"}, "/state")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	expected := []Sink{
+		{Type: SinkConsole},
+		{Type: SinkJSONLFile, Path: filepath.Join("/state", "events.jsonl")},
+		{Type: SinkTextFile, Path: "/tmp/nrz.log"},
+	}
+	if len(sinks) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, sinks)
+	}
+	for i := range expected {
+		if sinks[i] != expected[i] {
+			t.Errorf("Expected sink %d to be %v, got %v", i, expected[i], sinks[i])
+		}
+	}
+
+	for _, specs := range [][]string{
+		{"printer"},
+		{"console:/tmp/x"},
+		{"json", "plain"},
+		{"console", "plain"},
+	} {
+		if _, err := ParseSinks(specs, "/state"); err == nil {
+			t.Errorf("Expected an error for %v", specs)
+		}
+	}
+	if _, err := ParseSinks([]string{"console", "json"}, "/state"); err != nil {
+		t.Errorf("Expected the console next to json, got: %v", err)
+	}
+}
+
+func TestFormatSinks(t *testing.T) {
+	for format, expected := range map[Format]SinkType{FormatText: SinkConsole, FormatJSON: SinkJSON, FormatPlain: SinkPlain} {
+		if sinks := FormatSinks(format); len(sinks) != 1 || sinks[0].Type != expected {
+			t.Errorf("Expected %s for %s, got %v", expected, format, sinks)
+		}
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	dir := t.TempDir()
+	jsonl, err := OpenFileSink(Sink{Type: SinkJSONLFile, Path: filepath.Join(dir, "logs", "events.jsonl")}, "s1", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	lines, err := OpenFileSink(Sink{Type: SinkTextFile, Path: filepath.Join(dir, "events.log")}, "s1", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	bus := NewBus()
+	bus.Subscribe(jsonl)
+	bus.Subscribe(lines)
+	bus.Emit(Event{Type: EventTranscript, Text: "Bonjour"})
+	jsonl.Close()
+	lines.Close()
+
+	if info, err := os.Stat(jsonl.Path()); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected a file only readable by its owner, got %v", info.Mode())
+	}
+	if info, err := os.Stat(filepath.Dir(jsonl.Path())); err != nil || info.Mode().Perm() != 0700 {
+		t.Errorf("Expected a directory only readable by its owner, got %v", info.Mode())
+	}
+
+	data, _ := os.ReadFile(jsonl.Path())
+	if !strings.Contains(string(data), `"session_id":"s1","text":"Bonjour"`) {
+		t.Errorf("Expected the JSON event, got %q", data)
+	}
+	data, _ = os.ReadFile(lines.Path())
+	if !strings.Contains(string(data), "🎤 Bonjour") {
+		t.Errorf("Expected the console line, got %q", data)
+	}
+
+	if _, err := OpenFileSink(Sink{Type: SinkConsole}, "s1", nil); err == nil {
+		t.Error("Expected an error for a sink without file")
+	}
+}
@@ -0,0 +1,110 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TimestampClock is the default timestamp layout, the local time of day
+	TimestampClock = "15:04:05"
+	// TimestampISO8601 names the ISO 8601 layout with date and offset
+	TimestampISO8601 = "iso8601"
+	// TimestampElapsed names the time elapsed since the stream started
+	TimestampElapsed = "elapsed"
+)
+
+// Timestamps formats the event times of the text and JSON outputs: a Go time
+// layout in a time zone, or the time elapsed since the stream started
+type Timestamps struct {
+	layout   string
+	location *time.Location // nil keeps the location of the times
+	elapsed  bool
+	origin   time.Time
+	mutex    sync.RWMutex
+}
+
+// defaultTimestamps is used by writers without timestamps set
+var defaultTimestamps = &Timestamps{layout: TimestampClock}
+
+// NewTimestamps parses a timestamp format, TimestampISO8601,
+// TimestampElapsed or a Go time layout (TimestampClock when empty), and a
+// time zone name, the local time zone when empty
+func NewTimestamps(format, timezone string) (*Timestamps, error) {
+	t := &Timestamps{layout: format, origin: time.Now()}
+	switch strings.ToLower(format) {
+	case "":
+		t.layout = TimestampClock
+	case TimestampISO8601:
+		t.layout = time.RFC3339
+	case TimestampElapsed:
+		t.elapsed = true
+	default:
+		// A layout without any element prints itself for every time
+		probe := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+		if probe.Format(format) == format {
+			return nil, fmt.Errorf("timestamp format '%s' is not a time layout (e.g. 15:04:05, iso8601 or elapsed)", format)
+		}
+	}
+
+	switch strings.ToLower(timezone) {
+	case "", "local":
+		t.location = time.Local
+	default:
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("unknown timezone '%s': %w", timezone, err)
+		}
+		t.location = location
+	}
+	return t, nil
+}
+
+// SetOrigin sets the time the stream started, zero for elapsed timestamps
+func (t *Timestamps) SetOrigin(origin time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.origin = origin
+}
+
+// In returns tm in the time zone of the timestamps
+func (t *Timestamps) In(tm time.Time) time.Time {
+	if t == nil || t.location == nil {
+		return tm
+	}
+	return tm.In(t.location)
+}
+
+// Format formats tm
+func (t *Timestamps) Format(tm time.Time) string {
+	if t == nil {
+		t = defaultTimestamps
+	}
+	if t.elapsed {
+		t.mutex.RLock()
+		origin := t.origin
+		t.mutex.RUnlock()
+		return formatElapsed(tm.Sub(origin))
+	}
+	return t.In(tm).Format(t.layout)
+}
+
+// Event formats the time of event. Elapsed timestamps of transcripts use
+// their stream position, exact even when transcription lags behind.
+func (t *Timestamps) Event(event Event) string {
+	if t != nil && t.elapsed && event.Type == EventTranscript && (event.Start > 0 || event.End > 0) {
+		return formatElapsed(time.Duration(event.Start * float64(time.Second)))
+	}
+	return t.Format(event.Time)
+}
+
+// formatElapsed formats a duration as hours, minutes and seconds
+func formatElapsed(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	seconds := int64(d / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
@@ -0,0 +1,189 @@
+package vad
+
+import (
+	"encoding/binary"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "Rewrite the golden segment files in testdata")
+
+// goldenTolerance is how far a boundary may move from the golden file, in
+// milliseconds
+const goldenTolerance = 50
+
+// goldenDetectors are the detectors checked against the WAV fixtures
+var goldenDetectors = map[string]func() VoiceActivityDetector{
+	"rms": func() VoiceActivityDetector { return NewRMSDetector() },
+}
+
+// liveVADConfig is the configuration of the live pipeline
+func liveVADConfig() VADConfig {
+	return VADConfig{
+		SampleRate:          16000,
+		SilenceThreshold:    0.01,
+		SilenceDurationMs:   800,
+		MinSpeechDurationMs: 500,
+		RMSWindowSize:       160,
+		NoiseFloorSamples:   32000,
+	}
+}
+
+// speechSegment is an utterance found in a fixture, in milliseconds
+type speechSegment struct {
+	Start int
+	End   int
+}
+
+// readWAV reads a 16 kHz mono 16-bit WAV file
+func readWAV(path string) ([]float32, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, errors.New("not a WAV file")
+	}
+
+	var pcm []byte
+	for chunks := data[12:]; len(chunks) >= 8; {
+		id, size := string(chunks[:4]), int(binary.LittleEndian.Uint32(chunks[4:]))
+		body := chunks[8:min(8+size, len(chunks))]
+		switch id {
+		case "fmt ":
+			if len(body) < 16 || binary.LittleEndian.Uint16(body[2:]) != 1 ||
+				binary.LittleEndian.Uint32(body[4:]) != 16000 || binary.LittleEndian.Uint16(body[14:]) != 16 {
+				return nil, errors.New("expected 16 kHz mono 16-bit audio")
+			}
+		case "data":
+			pcm = body
+		}
+		chunks = chunks[min(8+size+size%2, len(chunks)):]
+	}
+
+	samples := make([]float32, len(pcm)/2)
+	for i := range samples {
+		samples[i] = float32(int16(binary.LittleEndian.Uint16(pcm[i*2:]))) / 32768
+	}
+	return samples, nil
+}
+
+// detectSegments runs detector over samples with the rules of the live
+// segmenter: an utterance ends after the configured silence and is dropped
+// when shorter than the minimum speech duration
+func detectSegments(detector VoiceActivityDetector, config VADConfig, samples []float32) []speechSegment {
+	detector.Initialize(config)
+	silenceSamples := config.SilenceDurationMs * config.SampleRate / 1000
+	minSpeechSamples := config.MinSpeechDurationMs * config.SampleRate / 1000
+	toMs := func(samples int) int { return samples * 1000 / config.SampleRate }
+
+	var segments []speechSegment
+	start := -1
+	add := func(end int) {
+		if end-start >= minSpeechSamples {
+			segments = append(segments, speechSegment{Start: toMs(start), End: toMs(end)})
+		}
+		start = -1
+	}
+
+	for i, sample := range samples {
+		if detector.ProcessSample(sample) && start < 0 {
+			start = i
+		}
+		if start >= 0 && detector.GetSilenceDuration() >= silenceSamples {
+			add(i + 1 - silenceSamples)
+			detector.Reset()
+		}
+	}
+	if start >= 0 {
+		add(len(samples))
+	}
+	return segments
+}
+
+func readGolden(path string) ([]speechSegment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var segments []speechSegment
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var segment speechSegment
+		if _, err := fmt.Sscanf(line, "%d %d", &segment.Start, &segment.End); err != nil {
+			return nil, fmt.Errorf("invalid line %q: %w", line, err)
+		}
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+func writeGolden(path, detector, clip string, segments []speechSegment) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Speech found by the %s detector in %s: start and end in ms\n", detector, clip)
+	for _, segment := range segments {
+		fmt.Fprintf(&b, "%d %d\n", segment.Start, segment.End)
+	}
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
+
+func TestDetectors_GoldenSegments(t *testing.T) {
+	out := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(out)
+
+	clips, err := filepath.Glob(filepath.Join("testdata", "*.wav"))
+	if err != nil || len(clips) == 0 {
+		t.Fatalf("Expected WAV fixtures in testdata, got %v (%v)", clips, err)
+	}
+
+	for name, newDetector := range goldenDetectors {
+		for _, clip := range clips {
+			base := strings.TrimSuffix(filepath.Base(clip), ".wav")
+			t.Run(name+"/"+base, func(t *testing.T) {
+				samples, err := readWAV(clip)
+				if err != nil {
+					t.Fatalf("Failed to read %s: %v", clip, err)
+				}
+				segments := detectSegments(newDetector(), liveVADConfig(), samples)
+
+				golden := filepath.Join("testdata", base+"."+name+".golden")
+				if *update {
+					if err := writeGolden(golden, name, filepath.Base(clip), segments); err != nil {
+						t.Fatalf("Failed to write %s: %v", golden, err)
+					}
+					return
+				}
+
+				expected, err := readGolden(golden)
+				if err != nil {
+					t.Fatalf("Failed to read %s (run with -update to create it): %v", golden, err)
+				}
+				if len(segments) != len(expected) {
+					t.Fatalf("Expected %d segments %v, got %v", len(expected), expected, segments)
+				}
+				for i, segment := range segments {
+					if abs(segment.Start-expected[i].Start) > goldenTolerance || abs(segment.End-expected[i].End) > goldenTolerance {
+						t.Errorf("Segment %d: expected %v within %dms, got %v", i, expected[i], goldenTolerance, segment)
+					}
+				}
+			})
+		}
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -1,3 +1,4 @@
+// Package vad detects speech in audio samples.
 package vad
 
 // VoiceActivityDetector handles voice activity detection
@@ -20,6 +21,9 @@ type VoiceActivityDetector interface {
 
 	// IsCalibrated returns true if noise floor calibration is complete
 	IsCalibrated() bool
+
+	// GetState returns a snapshot of the detector state, for monitoring
+	GetState() VADState
 }
 
 // VADConfig holds Voice Activity Detection configuration
@@ -39,4 +43,6 @@ type VADState struct {
 	SpeechSamples     int
 	AdaptiveThreshold float32
 	IsCalibrated      bool
+	NoiseFloor        float32 // Measured during calibration, 0 before
+	Level             float32 // RMS level of the current window
 }
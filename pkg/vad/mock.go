@@ -79,6 +79,15 @@ func (m *MockVAD) IsCalibrated() bool {
 	return m.isCalibrated
 }
 
+// GetState returns the mock state
+func (m *MockVAD) GetState() VADState {
+	return VADState{
+		IsSpeaking:     m.isSpeaking,
+		SilenceSamples: m.silenceDuration,
+		IsCalibrated:   m.isCalibrated,
+	}
+}
+
 // IsInitialized returns initialization state (for testing)
 func (m *MockVAD) IsInitialized() bool {
 	return m.initialized
@@ -2,12 +2,12 @@ package vad
 
 import (
 	"log"
+	"math"
 )
 
 // RMSDetector implements VoiceActivityDetector using RMS-based detection
 type RMSDetector struct {
 	config         VADConfig
-	rmsBuffer      []float32
 	silenceSamples int
 	speechSamples  int
 	isSpeaking     bool
@@ -16,13 +16,20 @@ type RMSDetector struct {
 	noiseFloorSamplesCount int
 	noiseFloorSum          float64
 	adaptiveThreshold      float32
+	noiseFloor             float32
 	calibrating            bool
+	level                  float32
+
+	// RMS window: ring buffer of squared samples and their running sum
+	window       []float32
+	windowPos    int
+	windowFilled int
+	windowSum    float64
 }
 
 // NewRMSDetector creates a new RMS-based voice activity detector
 func NewRMSDetector() *RMSDetector {
 	return &RMSDetector{
-		rmsBuffer:   make([]float32, 0),
 		calibrating: true,
 	}
 }
@@ -30,7 +37,8 @@ func NewRMSDetector() *RMSDetector {
 // Initialize initializes the VAD with configuration
 func (r *RMSDetector) Initialize(config VADConfig) error {
 	r.config = config
-	r.rmsBuffer = make([]float32, 0, config.RMSWindowSize)
+	r.window = make([]float32, max(config.RMSWindowSize, 0))
+	r.windowPos, r.windowFilled, r.windowSum = 0, 0, 0
 	r.adaptiveThreshold = config.SilenceThreshold
 	r.calibrating = true
 
@@ -47,14 +55,12 @@ func (r *RMSDetector) Initialize(config VADConfig) error {
 
 // ProcessSample processes a single audio sample
 func (r *RMSDetector) ProcessSample(sample float32) bool {
-	// Add to RMS calculation buffer
-	r.rmsBuffer = append(r.rmsBuffer, sample*sample)
-	if len(r.rmsBuffer) > r.config.RMSWindowSize {
-		r.rmsBuffer = r.rmsBuffer[1:] // Keep sliding window
-	}
+	// Add to RMS calculation window
+	r.addToWindow(sample * sample)
 
 	// Calculate RMS level
 	rmsLevel := r.calculateRMS()
+	r.level = rmsLevel
 
 	// Adaptive noise floor calibration
 	if r.calibrating && r.noiseFloorSamplesCount < r.config.NoiseFloorSamples {
@@ -62,6 +68,7 @@ func (r *RMSDetector) ProcessSample(sample float32) bool {
 		r.noiseFloorSamplesCount++
 		if r.noiseFloorSamplesCount >= r.config.NoiseFloorSamples {
 			noiseFloor := r.noiseFloorSum / float64(r.config.NoiseFloorSamples)
+			r.noiseFloor = float32(noiseFloor)
 			r.adaptiveThreshold = float32(noiseFloor * 3.0) // 3x noise floor
 			if r.adaptiveThreshold < r.config.SilenceThreshold {
 				r.adaptiveThreshold = r.config.SilenceThreshold
@@ -113,28 +120,55 @@ func (r *RMSDetector) IsCalibrated() bool {
 	return !r.calibrating
 }
 
-// calculateRMS calculates RMS level from current buffer
-func (r *RMSDetector) calculateRMS() float32 {
-	if len(r.rmsBuffer) == 0 {
-		return 0.0
+// GetState returns a snapshot of the detector state
+func (r *RMSDetector) GetState() VADState {
+	return VADState{
+		IsSpeaking:        r.isSpeaking,
+		SilenceSamples:    r.silenceSamples,
+		SpeechSamples:     r.speechSamples,
+		AdaptiveThreshold: r.adaptiveThreshold,
+		IsCalibrated:      !r.calibrating,
+		NoiseFloor:        r.noiseFloor,
+		Level:             r.level,
 	}
+}
 
-	var sum float32
-	for _, val := range r.rmsBuffer {
-		sum += val
+// addToWindow adds a squared sample to the RMS window, replacing the
+// oldest one once the window is full
+func (r *RMSDetector) addToWindow(squared float32) {
+	if len(r.window) == 0 {
+		return
 	}
 
-	meanSquare := sum / float32(len(r.rmsBuffer))
-	// Simple approximation of square root
-	if meanSquare <= 0 {
+	if r.windowFilled == len(r.window) {
+		r.windowSum -= float64(r.window[r.windowPos])
+	} else {
+		r.windowFilled++
+	}
+	r.window[r.windowPos] = squared
+	r.windowSum += float64(squared)
+	r.windowPos = (r.windowPos + 1) % len(r.window)
+
+	// Resum once per turn of the window so rounding errors of the running
+	// sum never build up over a long session
+	if r.windowPos == 0 {
+		r.windowSum = 0
+		for _, value := range r.window {
+			r.windowSum += float64(value)
+		}
+	}
+}
+
+// calculateRMS calculates RMS level from current window
+func (r *RMSDetector) calculateRMS() float32 {
+	if r.windowFilled == 0 {
 		return 0.0
 	}
 
-	// Newton's method for square root
-	x := meanSquare
-	for i := 0; i < 5; i++ {
-		x = (x + meanSquare/x) / 2
+	meanSquare := r.windowSum / float64(r.windowFilled)
+	if meanSquare <= 0 {
+		return 0.0
 	}
 
-	return x
+	return float32(math.Sqrt(meanSquare))
 }
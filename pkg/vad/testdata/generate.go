@@ -0,0 +1,168 @@
+//go:build ignore
+
+// generate writes the WAV fixtures of the golden VAD tests. speech.wav and
+// silence.wav are cut from the JFK recording shipped with whisper.cpp,
+// music.wav and keyboard.wav are synthesized with a fixed seed. See
+// README.md for where to get the recording.
+//
+//	go run testdata/generate.go ../../deps/whisper.cpp/samples/jfk.wav
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+)
+
+const sampleRate = 16000
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: go run testdata/generate.go path/to/jfk.wav")
+		os.Exit(2)
+	}
+	jfk, err := readPCM16(os.Args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// Room noise between the sentences, long enough for the noise floor
+	// calibration of the live pipeline (2 seconds)
+	room := concat(cut(jfk, 2.15, 3.22), cut(jfk, 4.35, 5.36))
+
+	random := rand.New(rand.NewPCG(893, 1))
+	noise := func(seconds float64) []float32 {
+		samples := make([]float32, int(seconds*sampleRate))
+		for i := range samples {
+			samples[i] = float32(random.NormFloat64() * 0.003)
+		}
+		return samples
+	}
+
+	fixtures := map[string][]float32{
+		// "And so my fellow Americans ... ask not"
+		"speech.wav":   concat(room, cut(jfk, 0.25, 4.35), cut(jfk, 4.35, 5.36)),
+		"silence.wav":  concat(room, cut(jfk, 2.15, 3.22)),
+		"music.wav":    mix(concat(noise(2), melody(random), noise(1)), noise(5.4)),
+		"keyboard.wav": mix(concat(noise(2), typing(random, 1.5), noise(1.2), typing(random, 0.5), noise(1)), noise(6.2)),
+	}
+	for name, samples := range fixtures {
+		if err := writePCM16(filepath.Join("testdata", name), samples); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// melody plucks eight notes of a scale with Karplus-Strong string synthesis
+func melody(random *rand.Rand) []float32 {
+	var samples []float32
+	for _, frequency := range []float64{262, 294, 330, 349, 392, 440, 494, 523} {
+		period := int(sampleRate / frequency)
+		delay := make([]float64, period)
+		for i := range delay {
+			delay[i] = random.Float64()*0.6 - 0.3
+		}
+		for i := range sampleRate * 3 / 10 {
+			current := delay[i%period]
+			next := delay[(i+1)%period]
+			delay[i%period] = 0.996 * (current + next) / 2
+			samples = append(samples, float32(current))
+		}
+	}
+	return samples
+}
+
+// typing is seconds of key clicks, short bursts of decaying noise
+func typing(random *rand.Rand, seconds float64) []float32 {
+	samples := make([]float32, int(seconds*sampleRate))
+	for at := 0; at < len(samples); at += sampleRate/8 + random.IntN(sampleRate/8) {
+		for i := 0; i < sampleRate/200 && at+i < len(samples); i++ {
+			decay := math.Exp(-float64(i) / 15)
+			samples[at+i] = float32((random.Float64()*2 - 1) * 0.4 * decay)
+		}
+	}
+	return samples
+}
+
+func cut(samples []float32, from, to float64) []float32 {
+	return samples[int(from*sampleRate):int(to*sampleRate)]
+}
+
+func concat(parts ...[]float32) []float32 {
+	var samples []float32
+	for _, part := range parts {
+		samples = append(samples, part...)
+	}
+	return samples
+}
+
+func mix(a, b []float32) []float32 {
+	samples := make([]float32, len(a))
+	for i := range samples {
+		samples[i] = a[i]
+		if i < len(b) {
+			samples[i] += b[i]
+		}
+	}
+	return samples
+}
+
+// readPCM16 reads a 16 kHz mono 16-bit WAV file
+func readPCM16(path string) ([]float32, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, errors.New("not a WAV file")
+	}
+
+	var pcm []byte
+	for chunks := data[12:]; len(chunks) >= 8; {
+		id, size := string(chunks[:4]), int(binary.LittleEndian.Uint32(chunks[4:]))
+		body := chunks[8:min(8+size, len(chunks))]
+		switch id {
+		case "fmt ":
+			if len(body) < 16 || binary.LittleEndian.Uint16(body[2:]) != 1 ||
+				binary.LittleEndian.Uint32(body[4:]) != sampleRate || binary.LittleEndian.Uint16(body[14:]) != 16 {
+				return nil, errors.New("expected 16 kHz mono 16-bit audio")
+			}
+		case "data":
+			pcm = body
+		}
+		chunks = chunks[min(8+size+size%2, len(chunks)):]
+	}
+
+	samples := make([]float32, len(pcm)/2)
+	for i := range samples {
+		samples[i] = float32(int16(binary.LittleEndian.Uint16(pcm[i*2:]))) / 32768
+	}
+	return samples, nil
+}
+
+func writePCM16(path string, samples []float32) error {
+	data := make([]byte, 0, 44+len(samples)*2)
+	data = append(data, "RIFF"...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(36+len(samples)*2))
+	data = append(data, "WAVEfmt "...)
+	data = binary.LittleEndian.AppendUint32(data, 16)
+	data = binary.LittleEndian.AppendUint16(data, 1) // PCM
+	data = binary.LittleEndian.AppendUint16(data, 1) // Mono
+	data = binary.LittleEndian.AppendUint32(data, sampleRate)
+	data = binary.LittleEndian.AppendUint32(data, sampleRate*2)
+	data = binary.LittleEndian.AppendUint16(data, 2)
+	data = binary.LittleEndian.AppendUint16(data, 16)
+	data = append(data, "data"...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(samples)*2))
+	for _, sample := range samples {
+		value := max(-1, min(1, sample)) * 32767
+		data = binary.LittleEndian.AppendUint16(data, uint16(int16(value)))
+	}
+	return os.WriteFile(path, data, 0644)
+}
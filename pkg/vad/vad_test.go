@@ -0,0 +1,256 @@
+package vad
+
+import (
+	"encoding/binary"
+	"io"
+	"log"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestNewMockVAD(t *testing.T) {
+	mock := NewMockVAD()
+	if mock == nil {
+		t.Fatal("Expected mock VAD to be created")
+	}
+
+	if !mock.IsCalibrated() {
+		t.Error("Expected mock VAD to start calibrated")
+	}
+}
+
+func TestMockVAD_Initialize(t *testing.T) {
+	mock := NewMockVAD()
+
+	config := VADConfig{
+		SampleRate:          16000,
+		SilenceThreshold:    0.01,
+		SilenceDurationMs:   800,
+		MinSpeechDurationMs: 500,
+		RMSWindowSize:       160,
+		NoiseFloorSamples:   32000,
+	}
+
+	err := mock.Initialize(config)
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	if !mock.IsInitialized() {
+		t.Error("Expected VAD to be initialized")
+	}
+}
+
+func TestMockVAD_ProcessSample(t *testing.T) {
+	mock := NewMockVAD()
+
+	// Set speech pattern: speech for 3 samples, then silence for 2 samples
+	pattern := []bool{true, true, true, false, false}
+	mock.SetSpeechPattern(pattern)
+
+	// Test processing samples according to pattern
+	results := []bool{}
+	for i := 0; i < 7; i++ { // Process more than pattern length to test looping
+		result := mock.ProcessSample(0.1)
+		results = append(results, result)
+	}
+
+	// First 3 should be speech, next 2 silence, then pattern repeats
+	expected := []bool{true, true, true, false, false, true, true}
+
+	for i, result := range results {
+		if result != expected[i] {
+			t.Errorf("Sample %d: expected %v, got %v", i, expected[i], result)
+		}
+	}
+}
+
+func TestMockVAD_Reset(t *testing.T) {
+	mock := NewMockVAD()
+
+	pattern := []bool{true, false, false}
+	mock.SetSpeechPattern(pattern)
+
+	// Process some samples
+	mock.ProcessSample(0.1) // Speech
+	mock.ProcessSample(0.1) // Silence
+	mock.ProcessSample(0.1) // Silence
+
+	// Reset should clear state
+	mock.Reset()
+
+	if mock.IsSpeaking() {
+		t.Error("Expected not speaking after reset")
+	}
+
+	if mock.GetSilenceDuration() != 0 {
+		t.Errorf("Expected silence duration 0 after reset, got %d", mock.GetSilenceDuration())
+	}
+}
+
+func TestRMSDetector_GetState(t *testing.T) {
+	detector := NewRMSDetector()
+	detector.Initialize(VADConfig{
+		SampleRate:        16000,
+		SilenceThreshold:  0.01,
+		RMSWindowSize:     160,
+		NoiseFloorSamples: 1600,
+	})
+
+	for i := 0; i < 1600; i++ {
+		detector.ProcessSample(0.02)
+	}
+
+	state := detector.GetState()
+	if !state.IsCalibrated {
+		t.Fatal("Expected detector to be calibrated")
+	}
+	if state.NoiseFloor <= 0 {
+		t.Errorf("Expected measured noise floor, got %f", state.NoiseFloor)
+	}
+	if diff := state.AdaptiveThreshold - 3*state.NoiseFloor; diff > 0.0001 || diff < -0.0001 {
+		t.Errorf("Expected threshold at 3x the noise floor, got %f for %f", state.AdaptiveThreshold, state.NoiseFloor)
+	}
+
+	for i := 0; i < 160; i++ {
+		detector.ProcessSample(0.5)
+	}
+	if state = detector.GetState(); !state.IsSpeaking || state.Level <= state.AdaptiveThreshold {
+		t.Errorf("Expected loud samples to start speech, got %+v", state)
+	}
+}
+
+func TestRMSDetector_SlidingWindow(t *testing.T) {
+	detector := NewRMSDetector()
+	detector.Initialize(VADConfig{SampleRate: 16000, SilenceThreshold: 0.01, RMSWindowSize: 4})
+
+	for range 4 {
+		detector.ProcessSample(0.5)
+	}
+	if level := detector.GetState().Level; level < 0.499 || level > 0.501 {
+		t.Errorf("Expected level 0.5 over a full window, got %f", level)
+	}
+
+	// The loud samples leave the window one by one
+	detector.ProcessSample(0)
+	detector.ProcessSample(0)
+	if level := detector.GetState().Level; level < 0.353 || level > 0.355 {
+		t.Errorf("Expected level 0.354 with half the window silent, got %f", level)
+	}
+	detector.ProcessSample(0)
+	detector.ProcessSample(0)
+	if level := detector.GetState().Level; level != 0 {
+		t.Errorf("Expected level 0 once the window is silent, got %f", level)
+	}
+}
+
+// windowRMS is the RMS computed from scratch over the squared samples, as
+// the detector did for every sample before keeping a running sum
+func windowRMS(squares []float32) float32 {
+	var sum float32
+	for _, value := range squares {
+		sum += value
+	}
+	return float32(math.Sqrt(float64(sum / float32(len(squares)))))
+}
+
+func TestRMSDetector_RunningSumMatchesWindow(t *testing.T) {
+	const windowSize = 160
+	detector := NewRMSDetector()
+	detector.Initialize(VADConfig{SampleRate: 16000, SilenceThreshold: 0.01, RMSWindowSize: windowSize})
+
+	random := rand.New(rand.NewPCG(1, 2))
+	var squares []float32
+	for i := range 20 * windowSize {
+		// Quiet and loud passages, so values of the sum span magnitudes
+		sample := float32(random.NormFloat64()) * 0.001
+		if i/windowSize%2 == 1 {
+			sample *= 300
+		}
+		detector.ProcessSample(sample)
+
+		squares = append(squares, sample*sample)
+		if len(squares) > windowSize {
+			squares = squares[1:]
+		}
+		expected := float64(windowRMS(squares))
+		if level := float64(detector.GetState().Level); math.Abs(level-expected) > 1e-3*expected+1e-9 {
+			t.Fatalf("Sample %d: expected level %g, got %g", i, expected, level)
+		}
+	}
+}
+
+func BenchmarkRMSDetector(b *testing.B) {
+	const windowSize = 1600
+	samples := make([]float32, 16000)
+	for i := range samples {
+		samples[i] = float32(i%100) / 10000
+	}
+
+	b.Run("running", func(b *testing.B) {
+		detector := NewRMSDetector()
+		detector.Initialize(VADConfig{
+			SampleRate:        16000,
+			SilenceThreshold:  0.01,
+			RMSWindowSize:     windowSize,
+			NoiseFloorSamples: 1600,
+		})
+
+		b.ReportAllocs()
+		for i := 0; b.Loop(); i++ {
+			detector.ProcessSample(samples[i%len(samples)])
+		}
+	})
+
+	// The previous computation, summing the whole window per sample
+	b.Run("full-window", func(b *testing.B) {
+		squares := make([]float32, 0, windowSize+1)
+
+		b.ReportAllocs()
+		for i := 0; b.Loop(); i++ {
+			sample := samples[i%len(samples)]
+			squares = append(squares, sample*sample)
+			if len(squares) > windowSize {
+				squares = squares[1:]
+			}
+			windowRMS(squares)
+		}
+	})
+}
+
+func FuzzRMSDetector(f *testing.F) {
+	f.Add([]byte{0, 0, 0, 0x3f}, uint8(4))
+	f.Add([]byte{0, 0, 0x80, 0x7f, 0, 0, 0xc0, 0x7f}, uint8(2)) // +Inf and NaN
+	f.Fuzz(func(t *testing.T, data []byte, windowSize uint8) {
+		// The detector logs every speech start
+		out := log.Writer()
+		log.SetOutput(io.Discard)
+		defer log.SetOutput(out)
+
+		size := int(windowSize%64) + 1
+		detector := NewRMSDetector()
+		detector.Initialize(VADConfig{
+			SampleRate:        16000,
+			SilenceThreshold:  0.01,
+			RMSWindowSize:     size,
+			NoiseFloorSamples: 4,
+		})
+
+		for i := 0; i+4 <= len(data); i += 4 {
+			detector.ProcessSample(math.Float32frombits(binary.LittleEndian.Uint32(data[i:])))
+			if state := detector.GetState(); state.SilenceSamples < 0 || state.SpeechSamples < 0 {
+				t.Fatalf("Expected non-negative counters, got %+v", state)
+			}
+		}
+
+		// Whatever came before, including NaN and infinities, two windows
+		// of silence bring the level back to zero
+		for range 2 * size {
+			detector.ProcessSample(0)
+		}
+		if level := detector.GetState().Level; level != 0 {
+			t.Errorf("Expected level 0 after silence, got %v", level)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+// Package whisper transcribes audio samples with whisper.cpp models.
+package whisper
+
+import "context"
+
+// TranscriptionResult represents the result of a transcription
+type TranscriptionResult struct {
+	Text     string
+	Segments []Segment
+	Language string
+	Duration float64
+}
+
+// Segment represents a segment of transcribed text
+type Segment struct {
+	Text     string
+	Start    float64
+	End      float64
+	NoSpeech bool
+	Speaker  string // Speaker label when diarization is enabled
+
+	// Confidence is the mean probability of the segment text tokens (0-1)
+	Confidence float64
+}
+
+// WhisperService handles speech-to-text transcription
+type WhisperService interface {
+	// LoadModel loads a Whisper model from the specified path
+	LoadModel(modelPath string) error
+
+	// ReloadModel swaps the loaded model for another one without
+	// interrupting in-flight transcriptions
+	ReloadModel(modelPath string) error
+
+	// Transcribe transcribes audio samples to text, giving up when ctx is done
+	Transcribe(ctx context.Context, audio []float32, language string) (TranscriptionResult, error)
+
+	// TranscribeStream transcribes audio samples like Transcribe, handing
+	// every segment to onSegment as soon as it is decoded
+	TranscribeStream(ctx context.Context, audio []float32, language string, onSegment func(Segment)) (TranscriptionResult, error)
+
+	// SetLanguage sets the transcription language
+	SetLanguage(language string)
+
+	// Close closes the Whisper service and releases resources
+	Close() error
+}
+
+// AudioTranslator is implemented by services translating speech into
+// English, the only target language of Whisper
+type AudioTranslator interface {
+	// TranslateAudio transcribes audio spoken in language straight into English
+	TranslateAudio(ctx context.Context, audio []float32, language string) (TranscriptionResult, error)
+}
+
+// PromptTranscriber is implemented by services taking the text preceding
+// the audio as context, so that an utterance cut in two reads on
+type PromptTranscriber interface {
+	// TranscribePrompt transcribes audio following the text of prompt
+	TranscribePrompt(ctx context.Context, audio []float32, language, prompt string) (TranscriptionResult, error)
+}
+
+// ModelConfig holds configuration for Whisper model
+type ModelConfig struct {
+	ModelPath string
+	Language  string
+	Threads   int
+	Translate bool
+
+	// Decoding parameters, zero values keep whisper defaults
+	BeamSize         int     // Beam size for beam search sampling
+	Temperature      float32 // Sampling temperature
+	EntropyThreshold float32 // Entropy threshold for decoder fallback
+	MaxSegmentLength uint    // Maximum segment length in characters
+}
@@ -0,0 +1,146 @@
+package whisper
+
+import "context"
+
+// MockWhisperService implements WhisperService for testing
+type MockWhisperService struct {
+	isLoaded         bool
+	loadError        error
+	transcribeError  error
+	transcribeResult TranscriptionResult
+	translateResult  TranscriptionResult
+	language         string
+	closeError       error
+	modelPath        string
+	prompt           string
+}
+
+// NewMockWhisperService creates a mock Whisper service
+func NewMockWhisperService() *MockWhisperService {
+	return &MockWhisperService{
+		isLoaded: false,
+		language: "fr",
+	}
+}
+
+// SetLoadError sets an error to return on LoadModel calls
+func (m *MockWhisperService) SetLoadError(err error) {
+	m.loadError = err
+}
+
+// SetTranscribeError sets an error to return on Transcribe calls
+func (m *MockWhisperService) SetTranscribeError(err error) {
+	m.transcribeError = err
+}
+
+// SetTranscribeResult sets the result to return on Transcribe calls
+func (m *MockWhisperService) SetTranscribeResult(result TranscriptionResult) {
+	m.transcribeResult = result
+}
+
+// SetTranslateResult sets the result to return on TranslateAudio calls
+func (m *MockWhisperService) SetTranslateResult(result TranscriptionResult) {
+	m.translateResult = result
+}
+
+// SetCloseError sets an error to return on Close calls
+func (m *MockWhisperService) SetCloseError(err error) {
+	m.closeError = err
+}
+
+// LoadModel simulates loading a model
+func (m *MockWhisperService) LoadModel(modelPath string) error {
+	if m.loadError != nil {
+		return m.loadError
+	}
+	m.isLoaded = true
+	m.modelPath = modelPath
+	return nil
+}
+
+// ReloadModel simulates swapping the loaded model
+func (m *MockWhisperService) ReloadModel(modelPath string) error {
+	if m.loadError != nil {
+		return m.loadError
+	}
+	m.isLoaded = true
+	m.modelPath = modelPath
+	return nil
+}
+
+// GetModelPath returns the path of the loaded model (for testing)
+func (m *MockWhisperService) GetModelPath() string {
+	return m.modelPath
+}
+
+// Transcribe simulates transcribing audio
+func (m *MockWhisperService) Transcribe(ctx context.Context, audio []float32, language string) (TranscriptionResult, error) {
+	if err := ctx.Err(); err != nil {
+		return TranscriptionResult{}, aborted(err)
+	}
+	if !m.isLoaded {
+		return TranscriptionResult{}, ErrModelNotLoaded
+	}
+
+	if m.transcribeError != nil {
+		return TranscriptionResult{}, m.transcribeError
+	}
+
+	return m.transcribeResult, nil
+}
+
+// TranscribeStream simulates transcribing audio, handing the segments of
+// the result to onSegment before returning
+func (m *MockWhisperService) TranscribeStream(ctx context.Context, audio []float32, language string, onSegment func(Segment)) (TranscriptionResult, error) {
+	result, err := m.Transcribe(ctx, audio, language)
+	if err != nil {
+		return result, err
+	}
+	for _, segment := range result.Segments {
+		onSegment(segment)
+	}
+	return result, nil
+}
+
+// TranscribePrompt simulates transcribing audio following prompt
+func (m *MockWhisperService) TranscribePrompt(ctx context.Context, audio []float32, language, prompt string) (TranscriptionResult, error) {
+	m.prompt = prompt
+	return m.Transcribe(ctx, audio, language)
+}
+
+// GetPrompt returns the last prompt given to TranscribePrompt (for testing)
+func (m *MockWhisperService) GetPrompt() string {
+	return m.prompt
+}
+
+// TranslateAudio simulates translating audio into English
+func (m *MockWhisperService) TranslateAudio(ctx context.Context, audio []float32, language string) (TranscriptionResult, error) {
+	if _, err := m.Transcribe(ctx, audio, language); err != nil {
+		return TranscriptionResult{}, err
+	}
+	return m.translateResult, nil
+}
+
+// SetLanguage sets the transcription language
+func (m *MockWhisperService) SetLanguage(language string) {
+	m.language = language
+}
+
+// GetLanguage returns the current language (for testing)
+func (m *MockWhisperService) GetLanguage() string {
+	return m.language
+}
+
+// Close simulates closing the service
+func (m *MockWhisperService) Close() error {
+	if m.closeError != nil {
+		return m.closeError
+	}
+	m.isLoaded = false
+	return nil
+}
+
+// IsLoaded returns whether the model is loaded (for testing)
+func (m *MockWhisperService) IsLoaded() bool {
+	return m.isLoaded
+}
@@ -0,0 +1,120 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNewMockWhisperService(t *testing.T) {
+	mock := NewMockWhisperService()
+	if mock == nil {
+		t.Fatal("Expected mock service to be created")
+	}
+
+	if mock.IsLoaded() {
+		t.Error("Expected service to start unloaded")
+	}
+}
+
+func TestMockWhisperService_LoadModel(t *testing.T) {
+	mock := NewMockWhisperService()
+
+	err := mock.LoadModel("test-model.bin")
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	if !mock.IsLoaded() {
+		t.Error("Expected model to be loaded")
+	}
+}
+
+func TestMockWhisperService_Transcribe(t *testing.T) {
+	mock := NewMockWhisperService()
+
+	// Test transcribe without loaded model
+	_, err := mock.Transcribe(context.Background(), []float32{0.1, 0.2}, "fr")
+	if !errors.Is(err, ErrModelNotLoaded) {
+		t.Errorf("Expected ErrModelNotLoaded, got: %v", err)
+	}
+
+	// Load model and test successful transcribe
+	mock.LoadModel("test-model.bin")
+
+	expectedResult := TranscriptionResult{
+		Text:     "Bonjour le monde",
+		Language: "fr",
+		Duration: 2.0,
+	}
+	mock.SetTranscribeResult(expectedResult)
+
+	result, err := mock.Transcribe(context.Background(), []float32{0.1, 0.2}, "fr")
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	if result.Text != expectedResult.Text {
+		t.Errorf("Expected text '%s', got '%s'", expectedResult.Text, result.Text)
+	}
+}
+
+func TestMockWhisperService_ReloadModel(t *testing.T) {
+	mock := NewMockWhisperService()
+	mock.LoadModel("large.bin")
+
+	if err := mock.ReloadModel("tiny.bin"); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	if mock.GetModelPath() != "tiny.bin" {
+		t.Errorf("Expected model path 'tiny.bin', got '%s'", mock.GetModelPath())
+	}
+
+	if !mock.IsLoaded() {
+		t.Error("Expected model to stay loaded after reload")
+	}
+}
+
+func TestMockWhisperService_TranscribeCancelled(t *testing.T) {
+	mock := NewMockWhisperService()
+	mock.LoadModel("test-model.bin")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := mock.Transcribe(ctx, []float32{0.1, 0.2}, "fr")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got: %v", err)
+	}
+}
+
+func TestMockWhisperService_TranscribeTimeout(t *testing.T) {
+	mock := NewMockWhisperService()
+	mock.LoadModel("test-model.bin")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	_, err := mock.Transcribe(ctx, []float32{0.1, 0.2}, "fr")
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected ErrTimeout wrapping the deadline, got: %v", err)
+	}
+}
+
+func TestMockWhisperService_TranscribeStream(t *testing.T) {
+	mock := NewMockWhisperService()
+	mock.LoadModel("test-model.bin")
+	mock.SetTranscribeResult(TranscriptionResult{Text: " Bonjour", Segments: []Segment{{Text: " Bonjour", End: 1}}})
+
+	var streamed []Segment
+	result, err := mock.TranscribeStream(context.Background(), []float32{0.1}, "fr", func(segment Segment) {
+		streamed = append(streamed, segment)
+	})
+	if err != nil || result.Text != " Bonjour" {
+		t.Errorf("Expected the result, got %q (%v)", result.Text, err)
+	}
+	if len(streamed) != 1 || streamed[0].Text != " Bonjour" {
+		t.Errorf("Expected the segment to be streamed, got %+v", streamed)
+	}
+}
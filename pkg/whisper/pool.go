@@ -0,0 +1,134 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Pool spreads transcriptions over several services, each with its own copy
+// of the model, so that they run in parallel. The contexts of a single model
+// share its state and cannot.
+type Pool struct {
+	services []WhisperService
+	free     chan WhisperService
+}
+
+// NewPool creates a pool of services, at least one
+func NewPool(services ...WhisperService) *Pool {
+	p := &Pool{
+		services: services,
+		free:     make(chan WhisperService, len(services)),
+	}
+	for _, service := range services {
+		p.free <- service
+	}
+	return p
+}
+
+// Size returns the number of transcriptions running at once
+func (p *Pool) Size() int {
+	return len(p.services)
+}
+
+// take waits for a free service or gives up when ctx is done
+func (p *Pool) take(ctx context.Context) (WhisperService, error) {
+	select {
+	case service := <-p.free:
+		return service, nil
+	case <-ctx.Done():
+		return nil, aborted(ctx.Err())
+	}
+}
+
+// LoadModel loads the model in every service
+func (p *Pool) LoadModel(modelPath string) error {
+	for i, service := range p.services {
+		if err := service.LoadModel(modelPath); err != nil {
+			return fmt.Errorf("failed to load model %d of %d: %w", i+1, len(p.services), err)
+		}
+	}
+	return nil
+}
+
+// ReloadModel swaps the model of every service
+func (p *Pool) ReloadModel(modelPath string) error {
+	for _, service := range p.services {
+		if err := service.ReloadModel(modelPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Transcribe transcribes audio samples on the first free service
+func (p *Pool) Transcribe(ctx context.Context, audio []float32, language string) (TranscriptionResult, error) {
+	service, err := p.take(ctx)
+	if err != nil {
+		return TranscriptionResult{}, err
+	}
+	defer func() { p.free <- service }()
+
+	return service.Transcribe(ctx, audio, language)
+}
+
+// TranscribeStream transcribes audio samples on the first free service,
+// handing every segment to onSegment as soon as it is decoded
+func (p *Pool) TranscribeStream(ctx context.Context, audio []float32, language string, onSegment func(Segment)) (TranscriptionResult, error) {
+	service, err := p.take(ctx)
+	if err != nil {
+		return TranscriptionResult{}, err
+	}
+	defer func() { p.free <- service }()
+
+	return service.TranscribeStream(ctx, audio, language, onSegment)
+}
+
+// TranscribePrompt transcribes audio samples following prompt on the first
+// free service, without the prompt when it takes none
+func (p *Pool) TranscribePrompt(ctx context.Context, audio []float32, language, prompt string) (TranscriptionResult, error) {
+	service, err := p.take(ctx)
+	if err != nil {
+		return TranscriptionResult{}, err
+	}
+	defer func() { p.free <- service }()
+
+	if prompter, ok := service.(PromptTranscriber); ok {
+		return prompter.TranscribePrompt(ctx, audio, language, prompt)
+	}
+	return service.Transcribe(ctx, audio, language)
+}
+
+// TranslateAudio transcribes audio samples into English on the first free
+// service
+func (p *Pool) TranslateAudio(ctx context.Context, audio []float32, language string) (TranscriptionResult, error) {
+	service, err := p.take(ctx)
+	if err != nil {
+		return TranscriptionResult{}, err
+	}
+	defer func() { p.free <- service }()
+
+	translator, ok := service.(AudioTranslator)
+	if !ok {
+		return TranscriptionResult{}, errors.New("whisper service does not translate")
+	}
+	return translator.TranslateAudio(ctx, audio, language)
+}
+
+// SetLanguage sets the transcription language of every service
+func (p *Pool) SetLanguage(language string) {
+	for _, service := range p.services {
+		service.SetLanguage(language)
+	}
+}
+
+// Close closes every service
+func (p *Pool) Close() error {
+	var errs []error
+	for _, service := range p.services {
+		if err := service.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
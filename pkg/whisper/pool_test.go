@@ -0,0 +1,51 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPool_LoadsEveryService(t *testing.T) {
+	first, second := NewMockWhisperService(), NewMockWhisperService()
+	pool := NewPool(first, second)
+
+	if err := pool.LoadModel("test-model.bin"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !first.IsLoaded() || !second.IsLoaded() {
+		t.Error("Expected the model to be loaded in every service")
+	}
+	if pool.Size() != 2 {
+		t.Errorf("Expected a pool of 2, got %d", pool.Size())
+	}
+
+	second.SetLoadError(errors.New("out of memory"))
+	if err := pool.LoadModel("test-model.bin"); err == nil {
+		t.Error("Expected the load error of the second service")
+	}
+}
+
+func TestPool_WaitsForAFreeService(t *testing.T) {
+	service := NewMockWhisperService()
+	service.LoadModel("test-model.bin")
+	service.SetTranscribeResult(TranscriptionResult{Text: "Bonjour"})
+	pool := NewPool(service)
+
+	busy, _ := pool.take(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := pool.Transcribe(ctx, []float32{0.1}, "fr"); !errors.Is(err, ErrTimeout) {
+		t.Errorf("Expected a timeout while every service is busy, got: %v", err)
+	}
+
+	pool.free <- busy
+	result, err := pool.TranscribePrompt(context.Background(), []float32{0.1}, "fr", "Salut")
+	if err != nil || result.Text != "Bonjour" {
+		t.Errorf("Expected 'Bonjour' once the service is free, got %q (%v)", result.Text, err)
+	}
+	if service.GetPrompt() != "Salut" {
+		t.Errorf("Expected the prompt to be forwarded, got %q", service.GetPrompt())
+	}
+}
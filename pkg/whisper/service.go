@@ -0,0 +1,295 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ggerganov/whisper.cpp/bindings/go/pkg/whisper"
+)
+
+// Common errors
+var (
+	ErrModelNotLoaded = errors.New("whisper model not loaded")
+	ErrTimeout        = errors.New("transcription timed out")
+)
+
+// Service implements WhisperService interface
+type Service struct {
+	model    whisper.Model
+	config   ModelConfig
+	isLoaded atomic.Bool // Read by health checks without the lock
+
+	// whisper.cpp contexts share the model state, so calls must be serialized.
+	// A channel is used instead of a mutex so that waiting can be cancelled.
+	lock chan struct{}
+}
+
+// NewService creates a new Whisper service
+func NewService() *Service {
+	return &Service{
+		lock: make(chan struct{}, 1),
+	}
+}
+
+// NewServiceWithConfig creates a new Whisper service using the given decoding parameters
+func NewServiceWithConfig(config ModelConfig) *Service {
+	return &Service{
+		config: config,
+		lock:   make(chan struct{}, 1),
+	}
+}
+
+// acquire takes the model lock or gives up when ctx is done
+func (s *Service) acquire(ctx context.Context) error {
+	select {
+	case s.lock <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees the model lock
+func (s *Service) release() {
+	<-s.lock
+}
+
+// LoadModel loads a Whisper model from the specified path
+func (s *Service) LoadModel(modelPath string) error {
+	model, err := whisper.New(modelPath)
+	if err != nil {
+		return err
+	}
+
+	s.model = model
+	s.config.ModelPath = modelPath
+	if s.config.Threads <= 0 {
+		s.config.Threads = runtime.NumCPU()
+	}
+	s.isLoaded.Store(true)
+
+	log.Printf("📦 Whisper model loaded: %s", modelPath)
+	return nil
+}
+
+// ReloadModel loads a new model and swaps it in once the current transcription
+// (if any) completes. The previous model stays in use if loading fails.
+func (s *Service) ReloadModel(modelPath string) error {
+	// Load outside the lock so transcription keeps running meanwhile
+	model, err := whisper.New(modelPath)
+	if err != nil {
+		return err
+	}
+
+	s.acquire(context.Background())
+	previous := s.model
+	s.model = model
+	s.config.ModelPath = modelPath
+	s.isLoaded.Store(true)
+	s.release()
+
+	if previous != nil {
+		previous.Close()
+	}
+
+	log.Printf("📦 Whisper model reloaded: %s", modelPath)
+	return nil
+}
+
+// aborted wraps the error of a cancelled transcription, as ErrTimeout once
+// its deadline passed
+func aborted(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return fmt.Errorf("transcription aborted: %w", err)
+}
+
+// Transcribe transcribes audio samples to text. Cancelling ctx aborts the
+// transcription before the next 30-second window is encoded.
+func (s *Service) Transcribe(ctx context.Context, audio []float32, language string) (TranscriptionResult, error) {
+	return s.transcribe(ctx, audio, language, transcribeOptions{translate: s.config.Translate})
+}
+
+// TranscribeStream transcribes audio samples, handing every segment to
+// onSegment from the whisper callback as soon as it is decoded. whisper
+// then decodes a single segment per 30-second window.
+func (s *Service) TranscribeStream(ctx context.Context, audio []float32, language string, onSegment func(Segment)) (TranscriptionResult, error) {
+	return s.transcribe(ctx, audio, language, transcribeOptions{translate: s.config.Translate, onSegment: onSegment})
+}
+
+// TranscribePrompt transcribes audio samples with prompt as the text
+// preceding them
+func (s *Service) TranscribePrompt(ctx context.Context, audio []float32, language, prompt string) (TranscriptionResult, error) {
+	return s.transcribe(ctx, audio, language, transcribeOptions{translate: s.config.Translate, prompt: prompt})
+}
+
+// TranslateAudio transcribes audio samples into English, whatever the
+// Translate setting
+func (s *Service) TranslateAudio(ctx context.Context, audio []float32, language string) (TranscriptionResult, error) {
+	return s.transcribe(ctx, audio, language, transcribeOptions{translate: true})
+}
+
+// transcribeOptions adjust a single transcription
+type transcribeOptions struct {
+	translate bool          // Into English
+	prompt    string        // Text preceding the audio, may be empty
+	onSegment func(Segment) // Called with every segment as it is decoded
+}
+
+// transcribe transcribes audio samples with options
+func (s *Service) transcribe(ctx context.Context, audio []float32, language string, options transcribeOptions) (TranscriptionResult, error) {
+	if err := s.acquire(ctx); err != nil {
+		return TranscriptionResult{}, aborted(err)
+	}
+	defer s.release()
+
+	if !s.isLoaded.Load() {
+		return TranscriptionResult{}, ErrModelNotLoaded
+	}
+
+	if len(audio) == 0 {
+		return TranscriptionResult{Language: language}, nil
+	}
+
+	// Create a fresh context for each transcription
+	whisperCtx, err := s.model.NewContext()
+	if err != nil {
+		return TranscriptionResult{}, err
+	}
+
+	whisperCtx.SetLanguage(language)
+	whisperCtx.SetTranslate(options.translate)
+	whisperCtx.SetThreads(uint(s.config.Threads))
+	s.applyDecodingParams(whisperCtx)
+	if options.prompt != "" {
+		whisperCtx.SetInitialPrompt(options.prompt)
+	}
+
+	// Process the audio, the encoder callback stops whisper once ctx is done
+	encoderBegin := func() bool {
+		return ctx.Err() == nil
+	}
+	var segmentCallback whisper.SegmentCallback
+	if options.onSegment != nil {
+		segmentCallback = func(segment whisper.Segment) {
+			options.onSegment(newSegment(segment))
+		}
+	}
+	if err := whisperCtx.Process(audio, encoderBegin, segmentCallback, nil); err != nil {
+		if ctx.Err() != nil {
+			return TranscriptionResult{}, aborted(ctx.Err())
+		}
+		return TranscriptionResult{}, err
+	}
+	if ctx.Err() != nil {
+		return TranscriptionResult{}, aborted(ctx.Err())
+	}
+
+	// Extract all segments
+	var text string
+	var segments []Segment
+
+	for {
+		segment, err := whisperCtx.NextSegment()
+		if err != nil {
+			break
+		}
+
+		text += segment.Text
+		segments = append(segments, newSegment(segment))
+	}
+
+	// With auto, report the language whisper detected
+	if detected := whisperCtx.DetectedLanguage(); language == "auto" && detected != "" {
+		language = detected
+	}
+
+	return TranscriptionResult{
+		Text:     text,
+		Segments: segments,
+		Language: language,
+		Duration: float64(len(audio)) / 16000.0, // Assuming 16kHz sample rate
+	}, nil
+}
+
+// newSegment converts a whisper.cpp segment
+func newSegment(segment whisper.Segment) Segment {
+	return Segment{
+		Text:     segment.Text,
+		Start:    segment.Start.Seconds(),
+		End:      segment.End.Seconds(),
+		NoSpeech: segment.Text == "",
+
+		Confidence: segmentConfidence(segment.Tokens),
+	}
+}
+
+// segmentConfidence averages the probability of text tokens, skipping
+// special and timestamp tokens such as [_BEG_] or [_TT_150]
+func segmentConfidence(tokens []whisper.Token) float64 {
+	var sum float64
+	var count int
+	for _, token := range tokens {
+		if strings.HasPrefix(token.Text, "[_") || strings.HasPrefix(token.Text, "<|") {
+			continue
+		}
+		sum += float64(token.P)
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+// applyDecodingParams applies the configured decoding parameters to a context
+func (s *Service) applyDecodingParams(whisperCtx whisper.Context) {
+	if s.config.BeamSize > 0 {
+		whisperCtx.SetBeamSize(s.config.BeamSize)
+	}
+	if s.config.Temperature > 0 {
+		whisperCtx.SetTemperature(s.config.Temperature)
+	}
+	if s.config.EntropyThreshold > 0 {
+		whisperCtx.SetEntropyThold(s.config.EntropyThreshold)
+	}
+	if s.config.MaxSegmentLength > 0 {
+		// whisper.cpp only honours max_len with token timestamps enabled
+		whisperCtx.SetTokenTimestamps(true)
+		whisperCtx.SetMaxSegmentLength(s.config.MaxSegmentLength)
+	}
+}
+
+// IsLoaded reports whether a model is loaded
+func (s *Service) IsLoaded() bool {
+	return s.isLoaded.Load()
+}
+
+// SetLanguage sets the transcription language
+func (s *Service) SetLanguage(language string) {
+	s.config.Language = language
+}
+
+// SetThreads sets the number of threads of the transcriptions, before
+// LoadModel. 0 uses every CPU core.
+func (s *Service) SetThreads(threads int) {
+	s.config.Threads = threads
+}
+
+// Close closes the Whisper service and releases resources
+func (s *Service) Close() error {
+	s.acquire(context.Background())
+	defer s.release()
+
+	if s.isLoaded.Load() && s.model != nil {
+		s.model.Close()
+		s.isLoaded.Store(false)
+	}
+	return nil
+}
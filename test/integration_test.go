@@ -1,11 +1,12 @@
 package tests
 
 import (
+	"context"
 	"testing"
 
-	"github.com/nerzhul/nrz-ai/internal/audio"
-	"github.com/nerzhul/nrz-ai/internal/vad"
-	"github.com/nerzhul/nrz-ai/internal/whisper"
+	"github.com/nerzhul/nrz-ai/pkg/audio"
+	"github.com/nerzhul/nrz-ai/pkg/vad"
+	"github.com/nerzhul/nrz-ai/pkg/whisper"
 )
 
 func TestAudioProcessorIntegration(t *testing.T) {
@@ -80,7 +81,7 @@ func TestMockIntegrationWorkflow(t *testing.T) {
 	}
 
 	// Test transcription
-	result, err := whisperService.Transcribe(samples, "fr")
+	result, err := whisperService.Transcribe(context.Background(), samples, "fr")
 	if err != nil {
 		t.Fatalf("Failed to transcribe: %v", err)
 	}